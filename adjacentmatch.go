@@ -0,0 +1,68 @@
+package main
+
+// Directions of GetAdjacentMatch.
+const (
+	directionNext     = "next"
+	directionPrevious = "previous"
+)
+
+// matchOrder returns the results of a search in the order the grouped view
+// shows them: by file, in the order files first appear, and within a file
+// in the order the matches were found.
+func matchOrder(results []SearchResult) []SearchResult {
+	ordered := make([]SearchResult, 0, len(results))
+	for _, g := range groupResults(results) {
+		ordered = append(ordered, g.Results...)
+	}
+	return ordered
+}
+
+// adjacentIndex returns the index after (or before) current among total
+// matches, wrapping around at either end, and whether it wrapped. A
+// current index outside the matches starts at the first match going
+// forward and the last going back.
+func adjacentIndex(current, total int, direction string) (int, bool) {
+	if direction == directionNext {
+		switch {
+		case current < 0 || current >= total:
+			return 0, false
+		case current == total-1:
+			return 0, true
+		}
+		return current + 1, false
+	}
+	switch {
+	case current < 0 || current >= total:
+		return total - 1, false
+	case current == 0:
+		return total - 1, true
+	}
+	return current - 1, false
+}
+
+// GetAdjacentMatch returns the match after or before currentIndex in a
+// completed search, so n/p keyboard navigation can walk every match even
+// when the webview only holds a page of the results. Indexes count the
+// matches in the order of the grouped view (FilterResults without
+// exclusions). direction is "next" or "previous"; navigation wraps around
+// at either end, reported as Wrapped, and -1 starts at the first match
+// going forward or the last going back. A search without results returns
+// Index -1. Only the most recent searches are kept; an older ID fails with
+// SEARCH_NOT_FOUND.
+func (a *App) GetAdjacentMatch(searchID string, currentIndex int, direction string) (AdjacentMatch, error) {
+	if direction != directionNext && direction != directionPrevious {
+		return AdjacentMatch{}, newAppError(ErrCodeDirectionInvalid, direction)
+	}
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return AdjacentMatch{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	ordered := matchOrder(rec.results)
+	match := AdjacentMatch{SearchID: searchID, Index: -1, Total: len(ordered)}
+	if len(ordered) == 0 {
+		return match, nil
+	}
+	match.Index, match.Wrapped = adjacentIndex(currentIndex, len(ordered), direction)
+	match.Result = ordered[match.Index]
+	return match, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+// TestGetAdjacentMatch verifies walking the matches of a search in grouped
+// order in both directions, wrapping at the ends, starting from -1, an
+// empty search, and invalid directions and IDs.
+func TestGetAdjacentMatch(t *testing.T) {
+	app := NewApp()
+	id := app.newSearchID()
+	// Workers finish files out of order; the walk follows the grouped view.
+	app.storeSearch(searchRecord{id: id, results: []SearchResult{
+		{FilePath: "/a.go", LineNum: 3},
+		{FilePath: "/b.go", LineNum: 1},
+		{FilePath: "/a.go", LineNum: 9},
+	}})
+
+	steps := []struct {
+		current   int
+		direction string
+		wantIndex int
+		wantFile  string
+		wantLine  int
+		wrapped   bool
+	}{
+		{-1, "next", 0, "/a.go", 3, false},
+		{0, "next", 1, "/a.go", 9, false},
+		{1, "next", 2, "/b.go", 1, false},
+		{2, "next", 0, "/a.go", 3, true},
+		{0, "previous", 2, "/b.go", 1, true},
+		{2, "previous", 1, "/a.go", 9, false},
+		{-1, "previous", 2, "/b.go", 1, false},
+		{7, "next", 0, "/a.go", 3, false},
+	}
+	for _, s := range steps {
+		got, err := app.GetAdjacentMatch(id, s.current, s.direction)
+		if err != nil {
+			t.Fatalf("GetAdjacentMatch(%d, %s) failed: %v", s.current, s.direction, err)
+		}
+		if got.Index != s.wantIndex || got.Total != 3 || got.Wrapped != s.wrapped ||
+			got.Result.FilePath != s.wantFile || got.Result.LineNum != s.wantLine {
+			t.Errorf("GetAdjacentMatch(%d, %s) = %+v", s.current, s.direction, got)
+		}
+	}
+
+	empty := app.newSearchID()
+	app.storeSearch(searchRecord{id: empty})
+	if got, err := app.GetAdjacentMatch(empty, -1, "next"); err != nil || got.Index != -1 || got.Total != 0 {
+		t.Errorf("empty search: got %+v, %v", got, err)
+	}
+	if _, err := app.GetAdjacentMatch(id, 0, "up"); err == nil || err.(*AppError).Code != ErrCodeDirectionInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeDirectionInvalid, err)
+	}
+	if _, err := app.GetAdjacentMatch("search-999", 0, "next"); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeSearchNotFound, err)
+	}
+}
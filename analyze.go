@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// analysisTopN is how many entries AnalyzeDirectory keeps in its largest
+// files and longest lines lists.
+const analysisTopN = 10
+
+// maxAnalyzedFileSize is the largest file AnalyzeDirectory reads to count
+// lines. Bigger files are still counted by size, without their lines.
+const maxAnalyzedFileSize = 64 * 1024 * 1024
+
+// analysisPreviewLength is how much of a long line LineStat.Preview holds,
+// in bytes.
+const analysisPreviewLength = 120
+
+// resolveDirectory returns the absolute form of root after checking that
+// it is an existing directory.
+func resolveDirectory(root string) (string, error) {
+	if root == "" {
+		return "", newAppError(ErrCodeDirectoryRequired)
+	}
+	absRoot, err := filepath.Abs(filepath.Clean(root))
+	if err != nil {
+		return "", newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	if info, err := os.Stat(absRoot); err != nil {
+		return "", newAppError(ErrCodeDirectoryNotFound, absRoot)
+	} else if !info.IsDir() {
+		return "", newAppError(ErrCodeNotADirectory, absRoot)
+	}
+	return absRoot, nil
+}
+
+// fileLineStats is what analyzeFile learns from reading one file.
+type fileLineStats struct {
+	binary  bool
+	lines   int
+	longest LineStat
+}
+
+// analyzeFile counts the lines of a text file and finds its longest line,
+// reading in chunks so minified files with multi-megabyte lines cost no
+// more than their size. Files whose first chunk looks binary are reported
+// as such and not read further.
+func (a *App) analyzeFile(path string) (fileLineStats, error) {
+	f, err := os.Open(toLongPath(path))
+	if err != nil {
+		return fileLineStats{}, err
+	}
+	defer f.Close()
+
+	var stats fileLineStats
+	r := bufio.NewReaderSize(f, 64*1024)
+	first := true
+	lineNum, lineLen := 1, 0
+	var preview []byte
+	endLine := func() {
+		if lineLen > stats.longest.Length {
+			stats.longest = LineStat{FilePath: path, LineNum: lineNum, Length: lineLen, Preview: strings.TrimSpace(string(preview))}
+		}
+		lineNum++
+		lineLen = 0
+		preview = preview[:0]
+	}
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if first && len(chunk) > 0 {
+			first = false
+			head := chunk
+			if len(head) > 512 {
+				head = head[:512]
+			}
+			if a.isBinary(head) {
+				stats.binary = true
+				return stats, nil
+			}
+		}
+		text := string(chunk)
+		if strings.HasSuffix(text, "\n") {
+			text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+		}
+		lineLen += len(text)
+		if room := analysisPreviewLength - len(preview); room > 0 {
+			if len(text) < room {
+				room = len(text)
+			}
+			preview = append(preview, text[:room]...)
+		}
+		switch {
+		case err == nil:
+			stats.lines++
+			endLine()
+		case errors.Is(err, bufio.ErrBufferFull):
+			// The line goes on past the buffer; keep reading it.
+		case errors.Is(err, io.EOF):
+			if lineLen > 0 {
+				stats.lines++
+				endLine()
+			}
+			return stats, nil
+		default:
+			return stats, err
+		}
+	}
+}
+
+// AnalyzeDirectory describes the files a search of root would walk: file
+// counts, sizes, and lines per extension, the largest files, and the
+// longest lines. It uses the search walker with every subdirectory and no
+// size, type, or binary filters, so hidden directories and
+// .codesearchignore rules apply as they do to a search. Files larger than
+// 64MB are counted without their lines.
+func (a *App) AnalyzeDirectory(root string) (DirectoryAnalysis, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return DirectoryAnalysis{}, err
+	}
+
+	start := time.Now()
+	req := SearchRequest{
+		Directory:      absRoot,
+		SearchSubdirs:  true,
+		IncludeBinary:  true,
+		MaxFileSize:    math.MaxInt64,
+		MaxFilesPerDir: defaultMaxFilesPerDir,
+	}
+	files, _, _, err := a.walkDirectoryTree(req, false)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return DirectoryAnalysis{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+
+	analysis := DirectoryAnalysis{Root: absRoot}
+	byExt := make(map[string]*ExtensionStats)
+	var largest []FileStat
+	var longest []LineStat
+	var mu sync.Mutex
+	jobs := make(chan fileMeta)
+	var wg sync.WaitGroup
+	for i := 0; i < numCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for meta := range jobs {
+				var stats fileLineStats
+				read := meta.size <= maxAnalyzedFileSize
+				if read {
+					var err error
+					if stats, err = a.analyzeFile(meta.absPath); err != nil {
+						a.logDebug("Skipping unreadable file while analyzing", logrus.Fields{"filePath": meta.absPath, "error": err.Error()})
+						read = false
+					}
+				}
+
+				mu.Lock()
+				ext := strings.ToLower(filepath.Ext(meta.absPath))
+				es := byExt[ext]
+				if es == nil {
+					es = &ExtensionStats{Extension: ext}
+					byExt[ext] = es
+				}
+				es.Files++
+				es.Bytes += meta.size
+				analysis.Files++
+				analysis.Bytes += meta.size
+				switch {
+				case !read:
+					analysis.UnreadFiles++
+				case stats.binary:
+					es.BinaryFiles++
+					analysis.BinaryFiles++
+				default:
+					es.Lines += stats.lines
+					analysis.Lines += stats.lines
+					if stats.longest.Length > 0 {
+						longest = append(longest, stats.longest)
+					}
+				}
+				largest = append(largest, FileStat{FilePath: meta.absPath, Size: meta.size, Lines: stats.lines})
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, meta := range files {
+		jobs <- meta
+	}
+	close(jobs)
+	wg.Wait()
+
+	analysis.Extensions = make([]ExtensionStats, 0, len(byExt))
+	for _, es := range byExt {
+		analysis.Extensions = append(analysis.Extensions, *es)
+	}
+	sort.Slice(analysis.Extensions, func(i, j int) bool {
+		if analysis.Extensions[i].Files != analysis.Extensions[j].Files {
+			return analysis.Extensions[i].Files > analysis.Extensions[j].Files
+		}
+		return analysis.Extensions[i].Extension < analysis.Extensions[j].Extension
+	})
+	sort.Slice(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	sort.Slice(longest, func(i, j int) bool { return longest[i].Length > longest[j].Length })
+	analysis.LargestFiles = append([]FileStat{}, largest[:min(len(largest), analysisTopN)]...)
+	analysis.LongestLines = append([]LineStat{}, longest[:min(len(longest), analysisTopN)]...)
+
+	a.logInfo("Directory analyzed", logrus.Fields{
+		"root":            absRoot,
+		"files":           analysis.Files,
+		"lines":           analysis.Lines,
+		"durationSeconds": time.Since(start).Seconds(),
+	})
+	return analysis, nil
+}
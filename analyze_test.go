@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAnalyzeDirectory verifies the per-extension totals, the largest files
+// and longest lines, and that binary files are counted without lines.
+func TestAnalyzeDirectory(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	long := strings.Repeat("x", 200000)
+	files := map[string]string{
+		"main.go":           "package main\n\nfunc main() {}\n",
+		"util.go":           "package main\r\n" + "var s = \"" + long + "\"\r\n",
+		"sub/README.md":     "# Title\nno trailing newline",
+		"sub/data.bin":      "\x00\x01\x02binary",
+		"sub/empty.txt":     "",
+		".git/config":       "hidden\n",
+		"sub/deep/Notes.MD": "one\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	analysis, err := app.AnalyzeDirectory(tempDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory failed: %v", err)
+	}
+	if analysis.Files != 6 || analysis.BinaryFiles != 1 || analysis.Lines != 8 {
+		t.Errorf("expected 6 files, 1 binary, 8 lines, got %+v", analysis)
+	}
+
+	byExt := make(map[string]ExtensionStats)
+	for _, es := range analysis.Extensions {
+		byExt[es.Extension] = es
+	}
+	if es := byExt[".go"]; es.Files != 2 || es.Lines != 5 {
+		t.Errorf("expected 2 .go files with 5 lines, got %+v", es)
+	}
+	if es := byExt[".md"]; es.Files != 2 || es.Lines != 3 {
+		t.Errorf("expected .md and .MD counted together, got %+v", es)
+	}
+	if analysis.Extensions[0].Extension != ".go" && analysis.Extensions[0].Extension != ".md" {
+		t.Errorf("expected the most common extension first, got %+v", analysis.Extensions)
+	}
+
+	if got := analysis.LargestFiles[0]; filepath.Base(got.FilePath) != "util.go" {
+		t.Errorf("expected util.go to be the largest file, got %+v", got)
+	}
+	longest := analysis.LongestLines[0]
+	if filepath.Base(longest.FilePath) != "util.go" || longest.LineNum != 2 || longest.Length != len(long)+10 {
+		t.Errorf("expected line 2 of util.go with %d bytes, got %+v", len(long)+10, longest)
+	}
+	if len(longest.Preview) != analysisPreviewLength || !strings.HasPrefix(longest.Preview, "var s = \"xx") {
+		t.Errorf("expected a %d-byte preview, got %q", analysisPreviewLength, longest.Preview)
+	}
+
+	if _, err := app.AnalyzeDirectory(filepath.Join(tempDir, "main.go")); err == nil || err.(*AppError).Code != ErrCodeNotADirectory {
+		t.Errorf("expected %s, got %v", ErrCodeNotADirectory, err)
+	}
+}
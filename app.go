@@ -6,12 +6,105 @@
 package main
 
 import (
-	"fmt"
+	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// defaultEditorCommand is the program OpenInDefaultEditor launches.
+// GetCapabilities checks for it on PATH.
+const defaultEditorCommand = "xdg-open"
+
+// fileManager is a program ShowInFolder can open a directory with.
+// Launchers hand the directory to another program and exit, so their exit
+// status says whether that worked.
+type fileManager struct {
+	command  string
+	args     []string
+	launcher bool
+}
+
+// fileManagers are tried in order by ShowInFolder. xdg-open is missing on
+// some minimal desktops, which usually still have gio or a file manager.
+var fileManagers = []fileManager{
+	{"xdg-open", nil, true},
+	{"gio", []string{"open"}, true},
+	{"nautilus", nil, false},
+	{"dolphin", nil, false},
+	{"thunar", nil, false},
+	{"pcmanfm", nil, false},
+}
+
+// launcherGrace is how long ShowInFolder waits for a launcher to exit. One
+// still running by then has opened the directory and is left alone.
+const launcherGrace = 3 * time.Second
+
+// fileManagerAvailable reports whether ShowInFolder has a program to run.
+func fileManagerAvailable() bool {
+	for _, fm := range fileManagers {
+		if commandAvailable(fm.command) {
+			return true
+		}
+	}
+	return false
+}
+
+// openInFileManager opens dir with the first of fileManagers that works.
+// When none does, the error's Details lists each attempt.
+func (a *App) openInFileManager(dir string) error {
+	var attempts []FileManagerAttempt
+	var tried []string
+	for _, fm := range fileManagers {
+		attempt := FileManagerAttempt{Command: fm.command}
+		tried = append(tried, fm.command)
+		if !commandAvailable(fm.command) {
+			attempts = append(attempts, attempt)
+			continue
+		}
+		attempt.Found = true
+
+		err := startFileManager(fm, dir)
+		if err == nil {
+			a.logDebug("Opened folder", logrus.Fields{"directory": dir, "fileManager": fm.command})
+			return nil
+		}
+		attempt.Error = err.Error()
+		attempts = append(attempts, attempt)
+		a.logWarn("File manager failed, trying the next one", logrus.Fields{
+			"fileManager": fm.command,
+			"error":       err.Error(),
+		})
+	}
+
+	appErr := newAppError(ErrCodeNoFileManager, dir, strings.Join(tried, ", "))
+	appErr.Details = attempts
+	return appErr
+}
+
+// startFileManager runs a file manager on dir. A launcher is given
+// launcherGrace to fail; it is then left running.
+func startFileManager(fm fileManager, dir string) error {
+	cmd := exec.Command(fm.command, append(fm.args, dir)...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	if !fm.launcher {
+		return nil
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(launcherGrace):
+		return nil
+	}
+}
+
 // ShowInFolder opens the containing folder of the given file path in the system's file manager.
 func (a *App) ShowInFolder(filePath string) error {
 	a.logDebug("Opening file location in folder", logrus.Fields{
@@ -25,15 +118,15 @@ func (a *App) ShowInFolder(filePath string) error {
 
 	switch runtime.GOOS {
 	case "linux":
-		err = runCommand("xdg-open", []string{absDir})
+		err = a.openInFileManager(absDir)
 	case "darwin":
 		a.logError("macOS folder opening not implemented", nil, logrus.Fields{})
-		return fmt.Errorf("macOS folder opening not implemented")
+		return newAppError(ErrCodeNotImplemented, "macOS")
 	default:
 		a.logError("Unsupported platform for ShowInFolder", nil, logrus.Fields{
 			"platform": runtime.GOOS,
 		})
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return newAppError(ErrCodeUnsupportedPlatform, runtime.GOOS)
 	}
 
 	if err != nil {
@@ -51,6 +144,7 @@ func (a *App) ShowInFolder(filePath string) error {
 
 // openInEditor is a helper function to open a file in a specific editor.
 func (a *App) openInEditor(filePath string, editor string, args []string) error {
+	editor = a.resolveEditorCommand(editor)
 	a.logDebug("Opening file in editor", logrus.Fields{
 		"filePath": filePath,
 		"editor":   editor,
@@ -61,17 +155,20 @@ func (a *App) openInEditor(filePath string, editor string, args []string) error
 	if err != nil {
 		return err
 	}
+	if err := a.checkLargeFiles(cleanPath); err != nil {
+		return err
+	}
 	if err := a.lookUpEditor(editor); err != nil {
 		return err
 	}
 
-	err = runCommand(editor, append(args, cleanPath))
+	err = startEditor(editor, append(args, cleanPath))
 	if err != nil {
 		a.logError("Failed to open file in editor", err, logrus.Fields{
 			"editor": editor,
 			"args":   args,
 		})
-		return fmt.Errorf("failed to open file in %s: %v", editor, err)
+		return newAppError(ErrCodeEditorLaunchFailed, editor, err)
 	}
 
 	a.logDebug("Successfully opened file in editor", logrus.Fields{
@@ -81,11 +178,27 @@ func (a *App) openInEditor(filePath string, editor string, args []string) error
 	return nil
 }
 
+// hideConsoleWindow is a no-op; only Windows opens a console window for
+// console programs started from the GUI.
+func hideConsoleWindow(cmd *exec.Cmd) {}
+
+// startEditor launches an editor with the given arguments, terminal
+// editors in a new terminal window.
+func startEditor(editor string, args []string) error {
+	if isTerminalEditor(editor) {
+		return startTerminalEditor(editor, args)
+	}
+	return runCommand(editor, args)
+}
+
 // OpenInDefaultEditor opens a file in the system's default editor
 func (a *App) OpenInDefaultEditor(filePath string) error {
 	a.logDebug("Opening file in default editor", logrus.Fields{
 		"filePath": filePath,
 	})
+	if err := a.checkLargeFiles(filepath.Clean(filePath)); err != nil {
+		return err
+	}
 
 	switch runtime.GOOS {
 	case "linux":
@@ -93,13 +206,13 @@ func (a *App) OpenInDefaultEditor(filePath string) error {
 			a.logError("Failed to open file in default editor", err, logrus.Fields{
 				"filePath": filePath,
 			})
-			return fmt.Errorf("failed to open file in default editor: %v", err)
+			return newAppError(ErrCodeDefaultEditorFailed, err)
 		}
 	default:
 		a.logError("Unsupported platform for OpenInDefaultEditor", nil, logrus.Fields{
 			"platform": runtime.GOOS,
 		})
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return newAppError(ErrCodeUnsupportedPlatform, runtime.GOOS)
 	}
 
 	a.logDebug("Successfully opened file in default editor", logrus.Fields{
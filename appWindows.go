@@ -6,7 +6,6 @@
 package main
 
 import (
-	"fmt"
 	"os/exec"
 	"runtime"
 	"syscall"
@@ -14,6 +13,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// fileManagerCommand and defaultEditorCommand are the programs ShowInFolder
+// and OpenInDefaultEditor launch. GetCapabilities checks for them on PATH.
+const (
+	fileManagerCommand   = "explorer"
+	defaultEditorCommand = "cmd"
+)
+
+// fileManagerAvailable reports whether ShowInFolder has a program to run.
+func fileManagerAvailable() bool {
+	return commandAvailable(fileManagerCommand)
+}
+
 // ShowInFolder opens the containing folder of the given file path in the system's file manager.
 func (a *App) ShowInFolder(filePath string) error {
 	a.logDebug("Opening file location in folder", logrus.Fields{
@@ -31,7 +42,7 @@ func (a *App) ShowInFolder(filePath string) error {
 		// argument is space-safe (unlike `cmd /c start <dir>`, where a path with
 		// spaces can be misread as the window-title argument). exec.Command
 		// quotes each arg, so no manual escaping is needed.
-		cmd := exec.Command("explorer", absDir)
+		cmd := exec.Command("explorer", shellPath(absDir))
 		cmd.SysProcAttr = &syscall.SysProcAttr{
 			HideWindow:    true,
 			CreationFlags: 0x08000000,
@@ -39,12 +50,12 @@ func (a *App) ShowInFolder(filePath string) error {
 		err = cmd.Start()
 	case "darwin":
 		a.logError("macOS folder opening not implemented", nil, logrus.Fields{})
-		return fmt.Errorf("macOS folder opening not implemented")
+		return newAppError(ErrCodeNotImplemented, "macOS")
 	default:
 		a.logError("Unsupported platform for ShowInFolder", nil, logrus.Fields{
 			"platform": runtime.GOOS,
 		})
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return newAppError(ErrCodeUnsupportedPlatform, runtime.GOOS)
 	}
 
 	if err != nil {
@@ -62,6 +73,7 @@ func (a *App) ShowInFolder(filePath string) error {
 
 // openInEditor is a helper function to open a file in a specific editor.
 func (a *App) openInEditor(filePath string, editor string, args []string) error {
+	editor = a.resolveEditorCommand(editor)
 	a.logDebug("Opening file in editor", logrus.Fields{
 		"filePath": filePath,
 		"editor":   editor,
@@ -72,21 +84,21 @@ func (a *App) openInEditor(filePath string, editor string, args []string) error
 	if err != nil {
 		return err
 	}
+	if err := a.checkLargeFiles(cleanPath); err != nil {
+		return err
+	}
 	if err := a.lookUpEditor(editor); err != nil {
 		return err
 	}
 
-	cmd := exec.Command(editor, append(args, cleanPath)...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000,
-	}
-	if err := cmd.Start(); err != nil {
+	// shellPath hands editors an 8.3 short name for paths beyond MAX_PATH,
+	// which they can open even when they are not long-path aware.
+	if err := startEditor(editor, append(args, shellPath(cleanPath))); err != nil {
 		a.logError("Failed to open file in editor", err, logrus.Fields{
 			"editor": editor,
 			"args":   args,
 		})
-		return fmt.Errorf("failed to open file in %s: %v", editor, err)
+		return newAppError(ErrCodeEditorLaunchFailed, editor, err)
 	}
 
 	a.logDebug("Successfully opened file in editor", logrus.Fields{
@@ -96,6 +108,26 @@ func (a *App) openInEditor(filePath string, editor string, args []string) error
 	return nil
 }
 
+// hideConsoleWindow keeps a console program started from the GUI from
+// flashing a console window (CREATE_NO_WINDOW).
+func hideConsoleWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000,
+	}
+}
+
+// startEditor launches an editor without flashing a console window.
+// Terminal editors get a console window of their own instead.
+func startEditor(editor string, args []string) error {
+	if isTerminalEditor(editor) {
+		return startTerminalEditor(editor, args)
+	}
+	cmd := exec.Command(editor, args...)
+	hideConsoleWindow(cmd)
+	return cmd.Start()
+}
+
 // OpenInDefaultEditor opens a file in the system's default editor
 func (a *App) OpenInDefaultEditor(filePath string) error {
 	a.logDebug("Opening file in default editor", logrus.Fields{
@@ -106,10 +138,13 @@ func (a *App) OpenInDefaultEditor(filePath string) error {
 	if err != nil {
 		return err
 	}
+	if err := a.checkLargeFiles(cleanPath); err != nil {
+		return err
+	}
 
 	switch runtime.GOOS {
 	case "windows":
-		cmd := exec.Command("cmd", "/c", "start", "", cleanPath)
+		cmd := exec.Command("cmd", "/c", "start", "", shellPath(cleanPath))
 		cmd.SysProcAttr = &syscall.SysProcAttr{
 			HideWindow:    true,
 			CreationFlags: 0x08000000,
@@ -118,13 +153,13 @@ func (a *App) OpenInDefaultEditor(filePath string) error {
 			a.logError("Failed to open file in default editor", err, logrus.Fields{
 				"filePath": cleanPath,
 			})
-			return fmt.Errorf("failed to open file in default editor: %v", err)
+			return newAppError(ErrCodeDefaultEditorFailed, err)
 		}
 	default:
 		a.logError("Unsupported platform for OpenInDefaultEditor", nil, logrus.Fields{
 			"platform": runtime.GOOS,
 		})
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return newAppError(ErrCodeUnsupportedPlatform, runtime.GOOS)
 	}
 
 	a.logDebug("Successfully opened file in default editor", logrus.Fields{
@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -13,13 +13,61 @@ import (
 
 // App struct holds the application context and provides methods for the frontend to call.
 type App struct {
-	ctx              context.Context
-	logger           *logrus.Logger
-	searchMu         sync.Mutex         // Guards access to searchCancel
-	searchCancel     context.CancelFunc // Cancel function for active searches
-	editorsMu        sync.RWMutex       // Guards access to availableEditors
-	availableEditors EditorAvailability // Cache of available editors detected at startup
-	ready            int32              // Set to 1 once startup() has run; read via IsAppReady
+	ctx                context.Context
+	logger             *logrus.Logger
+	logDir             string             // Directory of app.log, resolved once at startup (see resolveLogDir)
+	searchMu           sync.Mutex         // Guards access to searchSessions
+	searchSessions     []*searchSession   // Running searches, oldest first (see CancelSearch)
+	editorsMu          sync.RWMutex       // Guards access to availableEditors
+	availableEditors   EditorAvailability // Cache of available editors detected at startup
+	ready              int32              // Set to 1 once startup() has run; read via IsAppReady
+	dataDir            string             // Directory for persisted state (session, workspaces); empty disables persistence
+	storeMu            sync.Mutex         // Serializes reads and writes of files in dataDir
+	workspacesMu       sync.Mutex         // Serializes load-modify-save cycles of the workspace store
+	templatesMu        sync.Mutex         // Serializes load-modify-save cycles of the template store
+	localeMu           sync.RWMutex       // Guards access to locale
+	locale             string             // Locale of error messages sent to the frontend (see SetLocale)
+	settingsMu         sync.Mutex         // Guards access to settings
+	settings           *Settings          // User settings, loaded lazily from dataDir by currentSettings
+	notificationsReady int32              // Set to 1 once the desktop notification service is initialized
+	hotkey             hotkeyState        // Registered global shortcut (see applyHotkey)
+	launch             LaunchRequest      // Directory and query from the command line (see GetLaunchRequest)
+	searchSeq          uint64             // Last search ID issued by newSearchID; updated atomically
+	searchesMu         sync.Mutex         // Guards access to searches
+	searches           []searchRecord     // Recent completed searches, oldest first (see FilterResults)
+
+	resultStoreMu    sync.Mutex                // Serializes access to the result store and resultStoreCache
+	resultStoreCache map[string]*storedResults // Indexed results of stored searches, by stored search ID
+	indexesMu        sync.Mutex                // Guards access to indexes
+	indexes          map[string]*fullTextIndex // Full-text indexes by root, loaded lazily (see SearchIndexed)
+	quickfixMu       sync.Mutex                // Guards access to quickfixPath
+	quickfixPath     string                    // Last file written by ExportResultsAsQuickfix
+	editorsCheckedAt int64                     // When availableEditors was last probed, in Unix milliseconds; guarded by editorsMu
+	editorBinaries   map[string]string         // Editor binaries found outside PATH, by command (see discoverEditor); guarded by editorsMu
+	largeFileMu      sync.Mutex                // Guards access to largeFileAcks
+	largeFileAcks    map[string]bool           // Files over Settings.LargeFileWarnMB the user confirmed opening (see ConfirmLargeFileOpen)
+
+	pluginsMu  sync.RWMutex                  // Guards access to plugins and pluginExts
+	plugins    []*plugin                     // Built-in and installed plugins, loaded lazily (see ListPlugins)
+	pluginExts map[string]contentTransformer // Transformers of the enabled plugins, by extension
+
+	hooksRunning sync.WaitGroup // Hook commands that haven't exited yet (see runHooks and waitForHooks)
+
+	favoritesMu sync.Mutex // Serializes load-modify-save cycles of the favorites file
+
+	resultsWatchMu   sync.Mutex         // Guards access to resultsWatchStop
+	resultsWatchStop context.CancelFunc // Stops watching the files of the latest search (see watchResults)
+
+	undoMu sync.Mutex // Serializes access to the undo journal and its backups
+
+	progressMu        sync.Mutex                // Guards access to progressCurrent, progressSnapshots, and progressOrder
+	progressCurrent   string                    // ID of the search that started last
+	progressSnapshots map[string]SearchProgress // Latest progress event of each recent search (see GetSearchProgress)
+	progressOrder     []string                  // Keys of progressSnapshots, oldest first
+
+	metrics metricsServer // Local /metrics endpoint (see applyMetricsAddr)
+
+	updates updateState // Result of the last update check (see CheckForUpdates)
 }
 
 // IsAppReady reports whether backend startup has completed. The frontend calls
@@ -37,42 +85,24 @@ func (a *App) markReady() {
 	atomic.StoreInt32(&a.ready, 1)
 }
 
-// setSearchCancel stores the cancel function for the active search under lock.
-func (a *App) setSearchCancel(cancel context.CancelFunc) {
-	a.searchMu.Lock()
-	defer a.searchMu.Unlock()
-	a.searchCancel = cancel
-}
-
-// clearSearchCancel clears the stored cancel function under lock.
-func (a *App) clearSearchCancel() {
-	a.searchMu.Lock()
-	defer a.searchMu.Unlock()
-	a.searchCancel = nil
-}
-
-// cancelActiveSearch cancels the active search (if any) under lock and reports
-// whether a search was actually cancelled.
-func (a *App) cancelActiveSearch() bool {
-	a.searchMu.Lock()
-	defer a.searchMu.Unlock()
-	if a.searchCancel != nil {
-		a.searchCancel()
-		return true
-	}
-	return false
-}
-
 // NewApp creates a new App application struct.
 // This function is called during application initialization.
 func NewApp() *App {
-	app := &App{}
+	app := &App{dataDir: defaultDataDir()}
+	app.logDir = app.resolveLogDir()
 	app.setupLogger()
 	return app
 }
 
 // shutdown is called when the app is shutting down. This is a Wails lifecycle method.
 func (a *App) shutdown(ctx context.Context) {
+	a.cleanupNotifications()
+	a.stopResultsWatch()
+	a.releaseHotkey()
+	a.stopMetrics()
+	a.closePlugins()
+	a.waitForHooks(hookShutdownWait)
+
 	// Shut down the polling manager so its log-tail goroutine and file
 	// handles are released. The in-memory buffer is discarded — the
 	// frontend will fetch fresh entries on next launch.
@@ -87,16 +117,41 @@ func (a *App) shutdown(ctx context.Context) {
 	}
 }
 
-// ReadFileLog resolves a log file name to its absolute path under the logs/ directory.
-// Despite its name, it does not read the file — it returns the full path so the frontend
-// can fetch the content via the polling server. The name is kept for Wails binding compatibility.
-func (a *App) ReadFileLog(filePath string) (string, error) {
-	dir, err := os.Getwd()
+// Bounds of the lines GetLogTail returns.
+const (
+	defaultLogTailLines = 200
+	maxLogTailLines     = 5000
+)
+
+// GetLogFilePath returns the absolute path of app.log, so the frontend can
+// open the log in an editor.
+func (a *App) GetLogFilePath() string {
+	return filepath.Join(a.logDir, logFileName)
+}
+
+// GetLogTail returns the last lines of app.log: 200 when lines is 0 or
+// less, and at most 5000. Only the last MB of the file is read (see
+// readLogTail), so fewer lines come back when they are very long. The log
+// is always app.log in the logs directory; no caller-supplied path is
+// involved. Before anything was logged it returns "".
+func (a *App) GetLogTail(lines int) (string, error) {
+	if lines <= 0 {
+		lines = defaultLogTailLines
+	}
+	lines = min(lines, maxLogTailLines)
+
+	data, err := readLogTail(a.GetLogFilePath())
+	if os.IsNotExist(err) {
+		return "", nil
+	}
 	if err != nil {
-		a.logError("Error Current Directory Not Found", err, nil)
-		return "", fmt.Errorf("failed to get current working directory: %w", err)
+		return "", newAppError(ErrCodeFileReadFailed, err)
+	}
+	tail := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(tail) > lines {
+		tail = tail[len(tail)-lines:]
 	}
-	return filepath.Join(dir, "logs", filePath), nil
+	return strings.Join(tail, "\n"), nil
 }
 
 // GetInitialLogs returns the last 20 log entries from the polling manager's
@@ -2,7 +2,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -23,14 +22,14 @@ func (a *App) validatePathForEditor(filePath string) (string, error) {
 		a.logError("Invalid file path contains directory traversal", nil, logrus.Fields{
 			"filePath": filePath,
 		})
-		return "", fmt.Errorf("invalid file path: contains directory traversal")
+		return "", newAppError(ErrCodePathTraversal)
 	}
 
-	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
+	if _, err := os.Stat(toLongPath(cleanPath)); os.IsNotExist(err) {
 		a.logError("File does not exist", err, logrus.Fields{
 			"filePath": cleanPath,
 		})
-		return "", fmt.Errorf("file does not exist: %s", cleanPath)
+		return "", newAppError(ErrCodeFileNotFound, cleanPath)
 	}
 
 	return cleanPath, nil
@@ -48,7 +47,7 @@ func (a *App) validatePathForShowInFolder(filePath string) (string, error) {
 		a.logError("Invalid file path contains directory traversal", nil, logrus.Fields{
 			"filePath": filePath,
 		})
-		return "", fmt.Errorf("invalid file path: contains directory traversal")
+		return "", newAppError(ErrCodePathTraversal)
 	}
 
 	dir := filepath.Dir(cleanPath)
@@ -58,14 +57,14 @@ func (a *App) validatePathForShowInFolder(filePath string) (string, error) {
 			"filePath": filePath,
 			"dir":      dir,
 		})
-		return "", fmt.Errorf("invalid directory path: %v", err)
+		return "", newAppError(ErrCodeDirectoryInvalid, err)
 	}
 
-	if _, err := os.Stat(absDir); os.IsNotExist(err) {
+	if _, err := os.Stat(toLongPath(absDir)); os.IsNotExist(err) {
 		a.logError("Directory does not exist", err, logrus.Fields{
 			"absDir": absDir,
 		})
-		return "", fmt.Errorf("directory does not exist: %s", absDir)
+		return "", newAppError(ErrCodeDirectoryNotFound, absDir)
 	}
 
 	return absDir, nil
@@ -78,7 +77,7 @@ func (a *App) lookUpEditor(editor string) error {
 		a.logError("Editor not found in system PATH", err, logrus.Fields{
 			"editor": editor,
 		})
-		return fmt.Errorf("editor '%s' not found in system PATH: %v", editor, err)
+		return newAppError(ErrCodeEditorNotFound, editor, err)
 	}
 	return nil
 }
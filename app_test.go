@@ -1,13 +1,33 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 )
 
+// testProfileEnv are the variables os.UserConfigDir, os.UserCacheDir, and
+// defaultLogDir resolve the per-user directories from.
+var testProfileEnv = []string{"HOME", "USERPROFILE", "XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME", "APPDATA", "LOCALAPPDATA"}
 
+// TestMain runs the tests under a throwaway user profile, so an App whose
+// dataDir a test doesn't override persists settings, sessions, and the undo
+// journal to a temporary directory rather than the developer's own.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "code-search-test-home-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "creating the test profile: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range testProfileEnv {
+		os.Setenv(name, home)
+	}
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
 
 func TestIsAppReady(t *testing.T) {
 	app := NewApp()
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBatchQueries bounds the queries of one BatchSearch. Every line is
+// tested against every query, so a batch costs more the larger it is.
+const maxBatchQueries = 100
+
+// batchQuery is one query of a batch search and the results it has so far.
+type batchQuery struct {
+	req     SearchRequest
+	matcher lineMatcher // nil for an empty query, which matches nothing
+	results []SearchResult
+	found   int32 // len(results), readable by workers without the batch lock
+}
+
+// open reports whether the query still takes matches, given the n it
+// found in the file being scanned.
+func (q *batchQuery) open(n int) bool {
+	return q.matcher != nil && int(atomic.LoadInt32(&q.found))+n < q.req.MaxResults
+}
+
+// BatchSearch runs several queries over the same files in one pass: the
+// files are collected once, each is read once, and every line is tested
+// against every query. It returns one BatchResult per request, in order,
+// which is much faster than a search per query when auditing a list of
+// deprecated functions.
+//
+// The first request selects the files: its directory, filters, and file
+// options apply to the whole batch. The other requests may differ only in
+// their query and matching options (caseSensitive, useRegex,
+// expandIdentifiers, fuzziness, extractGroups, maxResults, and
+// confirmExpensive); an empty directory means the first request's. A
+// request that selects other files fails with BATCH_FILTERS_DIFFER and an
+// invalid one with BATCH_QUERY_INVALID, both with its number. Sampling,
+// result logs, and InvertFileMatch are not supported and are ignored.
+func (a *App) BatchSearch(reqs []SearchRequest) ([]BatchResult, error) {
+	if len(reqs) == 0 {
+		return []BatchResult{}, nil
+	}
+	if len(reqs) > maxBatchQueries {
+		return nil, newAppError(ErrCodeBatchTooLarge, len(reqs), maxBatchQueries)
+	}
+	queries, err := a.prepareBatch(reqs)
+	if err != nil {
+		a.logError("Batch search validation failed", err, logrus.Fields{"queries": len(reqs)})
+		return nil, err
+	}
+	req := queries[0].req
+
+	searchStart := time.Now()
+	searchID := a.newSearchID()
+	session := a.startSearchSession(searchID)
+	defer a.endSearchSession(session)
+	ctx, cancel := session.ctx, session.cancel
+
+	a.logInfo("Starting batch search", logrus.Fields{
+		"directory": req.Directory,
+		"queries":   len(queries),
+	})
+	files, err := a.collectBatchFiles(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := eventSink{a}
+	sink.Progress(SearchProgress{SearchID: searchID, TotalFiles: len(files), Status: "started"})
+	state := a.runBatch(ctx, cancel, searchID, files, req, queries, func(_ string, fileResults [][]SearchResult) (int, bool) {
+		return addBatchResults(queries, fileResults)
+	})
+
+	batch := make([]BatchResult, len(queries))
+	total := 0
+	satisfied := true
+	for i, q := range queries {
+		batch[i] = BatchResult{
+			Query:     reqs[i].Query,
+			Results:   q.results,
+			Truncated: len(q.results) >= q.req.MaxResults,
+		}
+		if batch[i].Results == nil {
+			batch[i].Results = []SearchResult{}
+		}
+		if q.matcher != nil && !batch[i].Truncated {
+			satisfied = false
+		}
+		total += len(q.results)
+	}
+	processed := int(atomic.LoadInt32(&state.processedFiles))
+	sink.Done(SearchProgress{
+		SearchID:       searchID,
+		ProcessedFiles: processed,
+		TotalFiles:     len(files),
+		ResultsCount:   total,
+		Status:         "completed",
+		Skipped:        state.skipStats(),
+	})
+
+	duration := time.Since(searchStart)
+	rootRemoved := atomic.LoadInt32(&state.rootRemoved) != 0
+	cancelled := ctx.Err() != nil && !satisfied && !rootRemoved
+	a.notifySearchFinished(cancelled, total, duration)
+	a.logInfo("Batch search completed", logrus.Fields{
+		"queries":         len(queries),
+		"resultsCount":    total,
+		"processedFiles":  processed,
+		"totalFiles":      len(files),
+		"durationSeconds": duration.Seconds(),
+	})
+	if rootRemoved {
+		return batch, newAppError(ErrCodeSearchRootRemoved, req.Directory)
+	}
+	return batch, nil
+}
+
+// prepareBatch validates the requests of a batch and compiles their
+// queries.
+func (a *App) prepareBatch(reqs []SearchRequest) ([]*batchQuery, error) {
+	queries := make([]*batchQuery, len(reqs))
+	for i, req := range reqs {
+		if req.Directory == "" {
+			req.Directory = reqs[0].Directory
+		}
+		req.Sampling = false
+		req.ResultLogPath = ""
+		req.InvertFileMatch = false
+		validated, err := a.validateAndSetDefaults(req)
+		if err != nil {
+			return nil, newAppError(ErrCodeBatchQueryInvalid, i+1, err)
+		}
+		if i > 0 && !sameFileSelection(queries[0].req, validated) {
+			return nil, newAppError(ErrCodeBatchFiltersDiffer, i+1)
+		}
+		q := &batchQuery{req: validated}
+		if validated.Query != "" {
+			pattern, err := a.compileSearchPattern(validated)
+			if err != nil {
+				return nil, newAppError(ErrCodeBatchQueryInvalid, i+1, err)
+			}
+			q.matcher = searchLineMatcher(validated, pattern)
+		}
+		queries[i] = q
+	}
+	return queries, nil
+}
+
+// collectBatchFiles collects the files of a prepared batch and checks the
+// cost of each query over them. A collection stopped by cancelling ctx
+// yields no files, so the batch finishes at once as cancelled.
+func (a *App) collectBatchFiles(ctx context.Context, queries []*batchQuery) ([]fileMeta, error) {
+	req := queries[0].req
+	absDir, err := filepath.Abs(req.Directory)
+	if err != nil {
+		return nil, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	files, err := a.collectFilesToProcessContext(ctx, req, nil, filepath.Clean(absDir)+string(filepath.Separator))
+	if err != nil && ctx.Err() != nil {
+		return nil, nil
+	}
+	if err != nil {
+		a.logError("Failed to collect files to process", err, logrus.Fields{"directory": req.Directory})
+		return nil, err
+	}
+	for i, q := range queries {
+		if q.matcher == nil {
+			continue
+		}
+		if err := checkTreeCost(q.req, len(files)); err != nil {
+			return nil, newAppError(ErrCodeBatchQueryInvalid, i+1, err)
+		}
+	}
+	return files, nil
+}
+
+// sameFileSelection reports whether two validated requests search the same
+// files the same way, ignoring their query and matching options.
+func sameFileSelection(a, b SearchRequest) bool {
+	files := func(req SearchRequest) SearchRequest {
+		req.Directory = filepath.Clean(req.Directory)
+		req.Query = ""
+		req.CaseSensitive = false
+		req.UseRegex = nil
+		req.ExpandIdentifiers = false
+		req.Fuzziness = 0
+		req.ExtractGroups = false
+		req.MaxResults = 0
+		req.ConfirmExpensive = false
+		return req
+	}
+	return reflect.DeepEqual(files(a), files(b))
+}
+
+// addBatchResults adds the results of one file to the queries of a batch,
+// each up to its MaxResults. It returns how many results it kept and
+// whether every query has all the results it takes.
+func addBatchResults(queries []*batchQuery, fileResults [][]SearchResult) (kept int, done bool) {
+	done = true
+	for i, q := range queries {
+		results := fileResults[i]
+		if room := q.req.MaxResults - len(q.results); len(results) > room {
+			results = results[:room]
+		}
+		q.results = append(q.results, results...)
+		atomic.StoreInt32(&q.found, int32(len(q.results)))
+		kept += len(results)
+		if q.matcher != nil && len(q.results) < q.req.MaxResults {
+			done = false
+		}
+	}
+	return kept, done
+}
+
+// runBatch runs the files through a worker pool that scans each file for
+// every query, and passes the results of each scanned file to add, one
+// call at a time. add returns how many results it kept and whether the
+// search is done, which stops it. Progress events are tagged with searchID.
+func (a *App) runBatch(ctx context.Context, cancel context.CancelFunc, searchID string, files []fileMeta, req SearchRequest, queries []*batchQuery, add func(path string, fileResults [][]SearchResult) (int, bool)) *SearchState {
+	state := &SearchState{}
+	filesChan := make(chan fileMeta, len(files))
+	for _, meta := range files {
+		filesChan <- meta
+	}
+	close(filesChan)
+
+	var mu sync.Mutex
+	addResults := func(path string, fileResults [][]SearchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		kept, done := add(path, fileResults)
+		atomic.AddInt32(&state.resultsCount, int32(kept))
+		if done {
+			cancel()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(searchWorkers(req), len(files)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for meta := range filesChan {
+				if ctx.Err() != nil {
+					return
+				}
+				path, fileResults := a.batchMatchFile(ctx, a.withPlugin(meta), req, queries, state, cancel)
+				if path == "" {
+					a.emitFileProgress(searchID, state, len(files), meta.absPath, req.SlowFS)
+					continue
+				}
+				countFileScanned(meta)
+				addResults(path, fileResults)
+				a.emitFileProgress(searchID, state, len(files), path, req.SlowFS)
+			}
+		}()
+	}
+	wg.Wait()
+	return state
+}
+
+// batchMatchFile scans one file for every query of a batch, returning the
+// path that was scanned, or "" when the file was skipped, and the results
+// of each query. Like processFile it skips binary and generated files the
+// collection didn't rule out.
+func (a *App) batchMatchFile(ctx context.Context, meta fileMeta, req SearchRequest, queries []*batchQuery, state *SearchState, cancel context.CancelFunc) (string, [][]SearchResult) {
+	path := meta.absPath
+	provider := meta.contentProvider()
+	if req.SkipGenerated || meta.checkBinary {
+		var head []byte
+		err := retryIfLocked(ctx, req, func() (err error) {
+			head, err = readFileHead(provider, path)
+			return err
+		})
+		if err != nil {
+			a.skipUnreadableFile(path, err, req, state, cancel)
+			return "", nil
+		}
+		if meta.checkBinary && a.isBinary(head) {
+			return "", nil
+		}
+		if req.SkipGenerated && looksGenerated(head) {
+			atomic.AddInt32(&state.generatedSkipped, 1)
+			return "", nil
+		}
+	}
+
+	var results [][]SearchResult
+	err := retryIfLocked(ctx, req, func() (err error) {
+		results, err = scanBatchFile(ctx, provider, path, queries, newLineScope(req), searchScannerBufferSize(req))
+		return err
+	})
+	if err != nil {
+		a.skipUnreadableFile(path, err, req, state, cancel)
+		return "", nil
+	}
+	return path, results
+}
+
+// scanBatchFile reads a file line by line and tests each line in scope
+// against every query that still takes results, capturing context the way
+// processContentLineByLine does. It stops early once no query takes more
+// or no later line is in scope.
+func scanBatchFile(ctx context.Context, provider ContentProvider, path string, queries []*batchQuery, scope *lineScope, bufferSize int) ([][]SearchResult, error) {
+	file, err := provider.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	text, _ := newTextReader(file)
+	scanner := newLineScanner(text, bufferSize)
+
+	results := make([][]SearchResult, len(queries))
+	prev := make([]string, 0, streamContextLines)
+	type pendingMatch struct {
+		query, idx, remaining int
+	}
+	var pending []pendingMatch
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		inScope, scopeDone := scope.next(lineNum, scanner.Bytes())
+		line := scanner.Text()
+
+		if len(pending) > 0 {
+			stillPending := pending[:0]
+			for _, p := range pending {
+				r := &results[p.query][p.idx]
+				r.ContextAfter = append(r.ContextAfter, line)
+				p.remaining--
+				if p.remaining > 0 {
+					stillPending = append(stillPending, p)
+				}
+			}
+			pending = stillPending
+		}
+
+		open := false
+		for i, q := range queries {
+			if !q.open(len(results[i])) {
+				continue
+			}
+			open = true
+			if !inScope || !q.matcher.MatchString(line) {
+				continue
+			}
+			results[i] = append(results[i], SearchResult{
+				FilePath:      path,
+				LineNum:       lineNum,
+				Content:       strings.TrimSpace(line),
+				MatchedText:   q.matcher.FindString(line),
+				ContextBefore: append([]string{}, prev...),
+				ContextAfter:  []string{},
+				Captures:      lineCaptures(q.matcher, line),
+				Spans:         matchSpans(q.matcher, line),
+			})
+			pending = append(pending, pendingMatch{query: i, idx: len(results[i]) - 1, remaining: streamContextLines})
+		}
+
+		prev = append(prev, line)
+		if len(prev) > streamContextLines {
+			prev = prev[1:]
+		}
+
+		if (!open || scopeDone) && len(pending) == 0 {
+			break
+		}
+		if lineNum%100 == 0 && ctx.Err() != nil {
+			return results, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
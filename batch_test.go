@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBatchSearch verifies that each query of a batch gets its own results
+// with context, matching options, and limit, from one shared file list.
+func TestBatchSearch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":     "package a\n\nfunc old() { ioutil.ReadFile(x) }\nfunc f() { ioutil.WriteFile(y) }\n",
+		"b.go":     "package b\n\nvar _ = ioutil.ReadAll(r)\nvar _ = Ioutil.Discard\n",
+		"notes.md": "ioutil.ReadFile in the docs\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	literal, regex := false, true
+	batch, err := app.BatchSearch([]SearchRequest{
+		{Directory: dir, Query: "ioutil.ReadFile", UseRegex: &literal, AllowedFileTypes: []string{"go"}, CaseSensitive: true},
+		{Query: `ioutil\.(Read|Write)\w*`, UseRegex: &regex, AllowedFileTypes: []string{"go"}, MaxResults: 2},
+		{Query: "ioutil.discard", UseRegex: &literal, AllowedFileTypes: []string{"go"}},
+		{Query: "", AllowedFileTypes: []string{"go"}},
+	})
+	if err != nil {
+		t.Fatalf("BatchSearch failed: %v", err)
+	}
+	if len(batch) != 4 {
+		t.Fatalf("expected 4 result sets, got %d", len(batch))
+	}
+
+	first := batch[0]
+	if first.Query != "ioutil.ReadFile" || len(first.Results) != 1 || first.Truncated {
+		t.Fatalf("unexpected first result set %+v", first)
+	}
+	r := first.Results[0]
+	if filepath.Base(r.FilePath) != "a.go" || r.LineNum != 3 || r.MatchedText != "ioutil.ReadFile" ||
+		strings.Join(r.ContextBefore, "|") != "package a|" || len(r.ContextAfter) != 1 {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if len(batch[1].Results) != 2 || !batch[1].Truncated {
+		t.Errorf("expected 2 of 3 regex matches, got %+v", batch[1])
+	}
+	if len(batch[2].Results) != 1 || batch[2].Results[0].MatchedText != "Ioutil.Discard" {
+		t.Errorf("expected a case-insensitive match, got %+v", batch[2])
+	}
+	if batch[3].Results == nil || len(batch[3].Results) != 0 {
+		t.Errorf("expected an empty result set for the empty query, got %+v", batch[3])
+	}
+}
+
+// TestBatchSearchErrors verifies that a query selecting other files, an
+// invalid query, and an oversized batch are rejected.
+func TestBatchSearchErrors(t *testing.T) {
+	dir := t.TempDir()
+	app := NewApp()
+
+	_, err := app.BatchSearch([]SearchRequest{
+		{Directory: dir, Query: "a"},
+		{Query: "b ext:go"},
+	})
+	if err == nil || err.(*AppError).Code != ErrCodeBatchFiltersDiffer || err.(*AppError).Args[0] != 2 {
+		t.Errorf("expected %s for query 2, got %v", ErrCodeBatchFiltersDiffer, err)
+	}
+
+	_, err = app.BatchSearch([]SearchRequest{
+		{Directory: dir, Query: "a"},
+		{Query: "(unclosed"},
+	})
+	if err == nil || err.(*AppError).Code != ErrCodeBatchQueryInvalid || err.(*AppError).Args[0] != 2 {
+		t.Errorf("expected %s for query 2, got %v", ErrCodeBatchQueryInvalid, err)
+	}
+
+	if _, err := app.BatchSearch(make([]SearchRequest, maxBatchQueries+1)); err == nil || err.(*AppError).Code != ErrCodeBatchTooLarge {
+		t.Errorf("expected %s, got %v", ErrCodeBatchTooLarge, err)
+	}
+	if batch, err := app.BatchSearch(nil); err != nil || len(batch) != 0 {
+		t.Errorf("expected no result sets, got %v, %v", batch, err)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Limits for OpenResultsInEditor. The default keeps an accidental click on a
+// huge result set from opening hundreds of tabs; the maximum also keeps the
+// command line well under the Windows 32K-character limit.
+const (
+	defaultOpenResultsLimit = 20
+	maxOpenResultsLimit     = 100
+)
+
+// editorTarget is a file to open and the line to place the cursor on.
+type editorTarget struct {
+	path string
+	line int
+}
+
+// editorLocationStyles says how each editor accepts a line number for more
+// than one file in a single invocation. Editors not listed only take plain
+// paths in batch mode (JetBrains' --line and Vim's +N apply to one file), so
+// they open each file at the top.
+var editorLocationStyles = map[string]string{
+	"VSCode":   "goto",  // code -g a.go:12 -g b.go:40
+	"VSCodium": "goto",  // codium -g a.go:12 -g b.go:40
+	"Sublime":  "colon", // subl a.go:12 b.go:40
+	"Atom":     "colon", // atom a.go:12 b.go:40
+	"Emacs":    "plus",  // emacs +12 a.go +40 b.go
+	"Nano":     "plus",  // nano +12 a.go +40 b.go
+	"Helix":    "colon", // hx a.go:12 b.go:40
+}
+
+// singleFileLocationStyles adds the editors that take a line number for
+// the first or only file, used when a single file is opened.
+var singleFileLocationStyles = map[string]string{
+	"Vim":             "plus", // vim +12 a.go
+	"Neovim":          "plus", // nvim +12 a.go
+	"Geany":           "line", // geany --line 12 a.go
+	"JetBrains":       "line", // goland --line 12 a.go
+	"GoLand":          "line",
+	"PyCharm":         "line",
+	"IntelliJ":        "line",
+	"WebStorm":        "line",
+	"PhpStorm":        "line",
+	"CLion":           "line",
+	"Rider":           "line",
+	"AndroidStudio":   "line",
+	"NotepadPlusPlus": "notepad", // notepad++ -n12 a.go
+}
+
+// resultTargets returns the distinct files of results in result order, up
+// to limit, each at the line of its first match.
+func resultTargets(results []SearchResult, limit int) []editorTarget {
+	seen := make(map[string]bool)
+	var targets []editorTarget
+	for _, r := range results {
+		if len(targets) == limit {
+			break
+		}
+		if r.FilePath == "" || seen[r.FilePath] {
+			continue
+		}
+		seen[r.FilePath] = true
+		targets = append(targets, editorTarget{path: r.FilePath, line: r.LineNum})
+	}
+	return targets
+}
+
+// batchEditorArgs builds the arguments that open all targets in one
+// invocation of the editor identified by its binding name.
+func batchEditorArgs(name string, targets []editorTarget) []string {
+	style := editorLocationStyles[name]
+	var args []string
+	if style == "" {
+		args = append(args, editorBindings[name].args...)
+		if len(targets) == 1 {
+			style = singleFileLocationStyles[name]
+		}
+	}
+
+	for _, t := range targets {
+		path := shellPath(t.path)
+		if t.line < 1 {
+			args = append(args, path)
+			continue
+		}
+		location := path + ":" + strconv.Itoa(t.line)
+		switch style {
+		case "goto":
+			args = append(args, "-g", location)
+		case "colon":
+			args = append(args, location)
+		case "plus":
+			args = append(args, "+"+strconv.Itoa(t.line), path)
+		case "line":
+			args = append(args, "--line", strconv.Itoa(t.line), path)
+		case "notepad":
+			args = append(args, "-n"+strconv.Itoa(t.line), path)
+		default:
+			args = append(args, path)
+		}
+	}
+	return args
+}
+
+// OpenResultsInEditor opens the files of up to limit search results in a
+// single invocation of the editor identified by editorID (a binding name
+// as used by OpenInEditorByName, or "JetBrains"), at their match lines where
+// the editor supports it. Each file is opened once, at its first match.
+// limit <= 0 uses the default of 20. Files that no longer exist are skipped.
+// It returns the number of files opened.
+func (a *App) OpenResultsInEditor(editorID string, results []SearchResult, limit int) (int, error) {
+	if limit <= 0 {
+		limit = defaultOpenResultsLimit
+	}
+	if limit > maxOpenResultsLimit {
+		limit = maxOpenResultsLimit
+	}
+
+	var targets []editorTarget
+	for _, t := range resultTargets(results, len(results)) {
+		cleanPath, err := a.validatePathForEditor(t.path)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, editorTarget{path: cleanPath, line: t.line})
+		if len(targets) == limit {
+			break
+		}
+	}
+
+	var command string
+	switch binding, ok := editorBindings[editorID]; {
+	case ok:
+		command = binding.command
+	case editorID == "JetBrains":
+		if len(targets) > 0 {
+			command, _ = a.getJetBrainsEditor(targets[0].path)
+		}
+	default:
+		return 0, newAppError(ErrCodeUnknownEditor, editorID)
+	}
+	if len(targets) == 0 {
+		return 0, newAppError(ErrCodeNoResultsToOpen)
+	}
+	paths := make([]string, len(targets))
+	for i, t := range targets {
+		paths[i] = t.path
+	}
+	if err := a.checkLargeFiles(paths...); err != nil {
+		return 0, err
+	}
+	command = a.resolveEditorCommand(command)
+	if err := a.lookUpEditor(command); err != nil {
+		return 0, err
+	}
+
+	args := batchEditorArgs(editorID, targets)
+	if err := startEditor(command, args); err != nil {
+		a.logError("Failed to open results in editor", err, logrus.Fields{
+			"editor": command,
+			"files":  len(targets),
+		})
+		return 0, newAppError(ErrCodeEditorLaunchFailed, command, err)
+	}
+
+	a.logInfo("Opened search results in editor", logrus.Fields{
+		"editor": command,
+		"files":  len(targets),
+	})
+	return len(targets), nil
+}
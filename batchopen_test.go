@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestResultTargets verifies that files are de-duplicated at their first
+// match line, kept in result order, and capped at the limit.
+func TestResultTargets(t *testing.T) {
+	results := []SearchResult{
+		{FilePath: "/a.go", LineNum: 12},
+		{FilePath: "/a.go", LineNum: 30},
+		{FilePath: "/b.go", LineNum: 40},
+		{FilePath: "", LineNum: 1},
+		{FilePath: "/c.go", LineNum: 5},
+	}
+
+	got := resultTargets(results, 2)
+	want := []editorTarget{{"/a.go", 12}, {"/b.go", 40}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resultTargets = %+v, want %+v", got, want)
+	}
+	if got := resultTargets(results, 10); len(got) != 3 {
+		t.Errorf("expected 3 distinct files, got %+v", got)
+	}
+}
+
+// TestBatchEditorArgs verifies the per-editor argument styles for opening
+// several files at their lines in one invocation.
+func TestBatchEditorArgs(t *testing.T) {
+	targets := []editorTarget{{"/a.go", 12}, {"/b.go", 40}}
+	cases := map[string][]string{
+		"VSCode":       {"-g", "/a.go:12", "-g", "/b.go:40"},
+		"Sublime":      {"/a.go:12", "/b.go:40"},
+		"Emacs":        {"+12", "/a.go", "+40", "/b.go"},
+		"Vim":          {"/a.go", "/b.go"},
+		"VisualStudio": {"/edit", "/a.go", "/b.go"},
+	}
+	for name, want := range cases {
+		if got := batchEditorArgs(name, targets); !reflect.DeepEqual(got, want) {
+			t.Errorf("batchEditorArgs(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	if got := batchEditorArgs("VSCode", []editorTarget{{"/a.go", 0}}); !reflect.DeepEqual(got, []string{"/a.go"}) {
+		t.Errorf("expected a file without a line to be passed plainly, got %q", got)
+	}
+
+	// Editors that only take a line for one file get it when opening one.
+	single := map[string][]string{
+		"Vim":             {"+12", "/a.go"},
+		"GoLand":          {"--line", "12", "/a.go"},
+		"JetBrains":       {"--line", "12", "/a.go"},
+		"NotepadPlusPlus": {"-n12", "/a.go"},
+		"VisualStudio":    {"/edit", "/a.go"},
+	}
+	for name, want := range single {
+		if got := batchEditorArgs(name, targets[:1]); !reflect.DeepEqual(got, want) {
+			t.Errorf("batchEditorArgs(%s) for one file = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestOpenResultsInEditorErrors verifies the error codes for an unknown
+// editor and for results whose files no longer exist.
+func TestOpenResultsInEditorErrors(t *testing.T) {
+	app := NewApp()
+
+	var appErr *AppError
+	_, err := app.OpenResultsInEditor("NoSuchEditor", []SearchResult{{FilePath: "/a.go", LineNum: 1}}, 5)
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeUnknownEditor {
+		t.Errorf("expected %s, got %v", ErrCodeUnknownEditor, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "gone.go")
+	_, err = app.OpenResultsInEditor("VSCode", []SearchResult{{FilePath: missing, LineNum: 1}}, 5)
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeNoResultsToOpen {
+		t.Errorf("expected %s, got %v", ErrCodeNoResultsToOpen, err)
+	}
+}
+
+// TestOpenResultsInEditorMissingCommand verifies that an editor that isn't
+// installed is reported before anything is launched.
+func TestOpenResultsInEditorMissingCommand(t *testing.T) {
+	app := NewApp()
+	t.Setenv("PATH", t.TempDir())
+
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	var appErr *AppError
+	_, err := app.OpenResultsInEditor("Sublime", []SearchResult{{FilePath: file, LineNum: 1}}, 0)
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeEditorNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeEditorNotFound, err)
+	}
+}
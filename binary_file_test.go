@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -116,4 +118,81 @@ func TestBinaryFileFiltering(t *testing.T) {
 			t.Error("isBinary should return true for content with high percentage of non-printable characters")
 		}
 	})
-}
\ No newline at end of file
+}
+// TestBinaryHeuristics verifies the UTF-8, legacy 8-bit, and UTF-16 rules
+// of isBinary, and that fileIsBinary, reading only the sampled windows of
+// a file, agrees with it.
+func TestBinaryHeuristics(t *testing.T) {
+	app := NewApp()
+	rng := rand.New(rand.NewSource(1))
+	random := make([]byte, 4096)
+	rng.Read(random)
+	for i, b := range random {
+		if b == 0 {
+			random[i] = 0xFF
+		}
+	}
+	shorts := make([]byte, 0, 512)
+	for i := 0; i < 256; i++ {
+		shorts = append(shorts, byte(i%20+1), 0)
+	}
+	text := strings.Repeat("func main() { fmt.Println(\"世界\") }\n", 4000)
+	withBlob := []byte(text)
+	copy(withBlob[len(withBlob)/2-1000:], random[:2000])
+
+	cases := []struct {
+		name    string
+		content []byte
+		binary  bool
+	}{
+		{"utf-8", []byte(text[:1000]), false},
+		{"latin-1", bytes.Repeat([]byte("Caf\xe9 cr\xe8me br\xfbl\xe9e, na\xefve fa\xe7ade.\n"), 20), false},
+		{"random bytes without nulls", random, true},
+		{"utf-16le without bom", encodeUTF16(text[:400], false)[2:], false},
+		{"utf-16be without bom", encodeUTF16(text[:400], true)[2:], false},
+		{"16-bit integers", shorts, true},
+		{"large text cut mid-rune", []byte(text), false},
+		{"large text with binary middle", withBlob, true},
+	}
+	dir := t.TempDir()
+	for _, c := range cases {
+		if got := app.isBinary(c.content); got != c.binary {
+			t.Errorf("%s: isBinary = %v, want %v", c.name, got, c.binary)
+		}
+
+		path := filepath.Join(dir, "sample")
+		if err := os.WriteFile(path, c.content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := app.fileIsBinary(file, int64(len(c.content)), make([]byte, binarySampleSize))
+		file.Close()
+		if err != nil || got != c.binary {
+			t.Errorf("%s: fileIsBinary = %v, %v, want %v", c.name, got, err, c.binary)
+		}
+	}
+}
+
+// TestSearchUTF16WithoutBOM verifies that a UTF-16 file without a byte
+// order mark passes the binary probe and is searched as decoded text.
+func TestSearchUTF16WithoutBOM(t *testing.T) {
+	dir := t.TempDir()
+	content := encodeUTF16("first line\nneedle here\n", false)[2:]
+	if err := os.WriteFile(filepath.Join(dir, "notes.dat"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	useRegex := false
+	results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle", UseRegex: &useRegex})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].LineNum != 2 || results[0].Content != "needle here" {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
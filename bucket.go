@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Schemes of the bucket URLs SearchBucket accepts.
+const (
+	bucketSchemeS3  = "s3"
+	bucketSchemeGCS = "gs"
+)
+
+// Bounds of BucketSearchRequest.Concurrency, the objects downloaded at once.
+const (
+	defaultBucketConcurrency = 4
+	maxBucketConcurrency     = 16
+)
+
+// bucketHTTPTimeout bounds a single list or download request to a bucket.
+const bucketHTTPTimeout = 5 * time.Minute
+
+// bucketObject is an object listed under a bucket prefix.
+type bucketObject struct {
+	key      string
+	size     int64
+	modified time.Time
+}
+
+// objectStore lists and reads the objects of one bucket. The S3 and GCS
+// implementations are in bucket_s3.go and bucket_gcs.go.
+type objectStore interface {
+	list(ctx context.Context, prefix string) ([]bucketObject, error)
+	open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// parseBucketURL splits s3://bucket/prefix or gs://bucket/prefix. The
+// prefix may be empty or end in a slash; it is matched as a key prefix.
+func parseBucketURL(raw string) (scheme, bucket, prefix string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", newAppError(ErrCodeBucketURLInvalid, raw)
+	}
+	scheme = strings.ToLower(u.Scheme)
+	if (scheme != bucketSchemeS3 && scheme != bucketSchemeGCS) || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", "", newAppError(ErrCodeBucketURLInvalid, raw)
+	}
+	return scheme, u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// newObjectStore returns the store of a bucket, with credentials from the
+// provider's usual environment variables and files.
+func newObjectStore(scheme, bucket string) (objectStore, error) {
+	client := &http.Client{Timeout: bucketHTTPTimeout}
+	if scheme == bucketSchemeGCS {
+		return newGCSStore(client, bucket)
+	}
+	return newS3Store(client, bucket)
+}
+
+// bucketProvider reads objects for the search workers, at most cap(slots)
+// at a time. Paths are bucket URLs: root followed by the object key.
+type bucketProvider struct {
+	ctx   context.Context
+	store objectStore
+	root  string // "s3://bucket/"
+	slots chan struct{}
+}
+
+// Open downloads an object. A missing object is reported as unreadable, not
+// as fs.ErrNotExist: that would make the search check whether its
+// directory, here a bucket URL, still exists on disk.
+func (p *bucketProvider) Open(objectPath string) (io.ReadCloser, error) {
+	key, ok := strings.CutPrefix(objectPath, p.root)
+	if !ok {
+		return nil, fmt.Errorf("%s is not in %s", objectPath, p.root)
+	}
+	select {
+	case p.slots <- struct{}{}:
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+	body, err := p.store.open(p.ctx, key)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return &slotReader{ReadCloser: body, slots: p.slots}, nil
+}
+
+// slotReader gives its download slot back when closed.
+type slotReader struct {
+	io.ReadCloser
+	slots chan struct{}
+	once  sync.Once
+}
+
+func (r *slotReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(func() { <-r.slots })
+	return err
+}
+
+// bucketCollector lists the objects under a prefix and applies the
+// request's filters to their keys, as the directory walk does to paths.
+type bucketCollector struct {
+	a        *App
+	provider *bucketProvider
+	prefix   string
+}
+
+func (c bucketCollector) Collect(ctx context.Context, _ string, req SearchRequest, _ *regexp.Regexp, _ string) ([]fileMeta, error) {
+	objects, err := c.provider.store.list(ctx, c.prefix)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, newAppError(ErrCodeBucketListFailed, c.provider.root+c.prefix, err)
+	}
+
+	var files []fileMeta
+	for _, obj := range objects {
+		rel := strings.TrimPrefix(strings.TrimPrefix(obj.key, c.prefix), "/")
+		if rel == "" || strings.HasSuffix(obj.key, "/") {
+			continue // The prefix itself, or a folder placeholder
+		}
+		if !bucketKeyWanted(c.a, req, rel, obj.size) || !modifiedWanted(req, obj.modified.UnixMilli()) {
+			continue
+		}
+		files = append(files, fileMeta{
+			absPath:     c.provider.root + obj.key,
+			size:        obj.size,
+			checkBinary: !req.IncludeBinary && !isKnownTextExtension(obj.key),
+			content:     c.provider,
+		})
+	}
+	return files, nil
+}
+
+// bucketKeyWanted applies the directory walk's filters to an object key,
+// given relative to the searched prefix: hidden and excluded "directories",
+// SearchSubdirs, extensions (which well-known build and CI files pass with
+// IncludeWellKnownFiles), sizes, path filters, and generated-file names.
+func bucketKeyWanted(a *App, req SearchRequest, rel string, size int64) bool {
+	dir, name := path.Split(rel)
+	if dir != "" && !req.SearchSubdirs {
+		return false
+	}
+	wellKnown := req.IncludeWellKnownFiles && isWellKnownFile(rel)
+	if inHiddenDir(rel) && !wellKnown {
+		return false
+	}
+	if req.Extension != "" && !wellKnown && !matchExtension(name, req.Extension) {
+		return false
+	}
+	if len(req.AllowedFileTypes) > 0 && !wellKnown {
+		allowed := false
+		for _, ext := range req.AllowedFileTypes {
+			if matchExtension(name, ext) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if size > req.MaxFileSize || size < req.MinFileSize {
+		return false
+	}
+	for _, pattern := range req.ExcludePatterns {
+		if pattern != "" && a.matchesPattern(filepath.FromSlash(rel), pattern) {
+			return false
+		}
+	}
+	if !pathFiltersMatch(req, rel) {
+		return false
+	}
+	return !req.SkipGenerated || !isGeneratedFileName(name)
+}
+
+// SearchBucket searches the objects under an S3 or GCS bucket prefix with
+// the same matcher, filters, progress events, and result handling as
+// SearchWithProgress. Objects are listed once, filtered by key and size,
+// and downloaded by the search workers, at most req.Concurrency at a time.
+// Results carry the object URL ("s3://bucket/key") as their file path.
+// Credentials come from the standard environment variables and files of
+// each provider; see bucket_s3.go and bucket_gcs.go.
+func (a *App) SearchBucket(req BucketSearchRequest) ([]SearchResult, error) {
+	scheme, bucket, prefix, err := parseBucketURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	search, err := a.setSearchDefaults(req.Search)
+	if err != nil {
+		return nil, err
+	}
+	if search.OwnedByMe || search.PermissionMask != 0 {
+		// Objects have no owner uid or mode bits to filter on.
+		return nil, newAppError(ErrCodeOwnerFiltersUnsupported)
+	}
+	search.Directory = req.URL
+	search.ResultLogPath = ""
+	if search.Query == "" {
+		return []SearchResult{}, nil
+	}
+	if err := checkPatternCost(search); err != nil {
+		return nil, err
+	}
+	pattern, err := a.compileSearchPattern(search)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newObjectStore(scheme, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBucketConcurrency
+	}
+	concurrency = min(concurrency, maxBucketConcurrency)
+
+	searchStart := time.Now()
+	searchID := a.newSearchID()
+	session := a.startSearchSession(searchID)
+	defer a.endSearchSession(session)
+	ctx, cancel := session.ctx, session.cancel
+
+	a.logInfo("Starting bucket search", logrus.Fields{
+		"url":         req.URL,
+		"query":       search.Query,
+		"concurrency": concurrency,
+	})
+	hooks := a.startSearchHooks(searchID, search)
+	var sink ResultSink = eventSink{a}
+	if threshold := hooks.thresholdSink(); threshold != nil {
+		sink = multiSink{sink, threshold}
+	}
+	root := scheme + "://" + bucket + "/"
+	provider := &bucketProvider{ctx: ctx, store: store, root: root, slots: make(chan struct{}, concurrency)}
+	s := &searcher{
+		collector: bucketCollector{a: a, provider: provider, prefix: prefix},
+		matcher:   appMatcher{a},
+		sink:      sink,
+		log:       a,
+	}
+	out, err := s.run(ctx, cancel, searchID, search, pattern, root)
+	hooks.finish(out, err)
+	if err != nil {
+		return nil, err
+	}
+
+	a.storeSearch(searchRecord{
+		id:      searchID,
+		request: search,
+		results: out.results,
+		counts:  out.fileCounts,
+	})
+	duration := time.Since(searchStart)
+	a.notifySearchFinished(out.cancelled, len(out.results), duration)
+	a.logInfo("Bucket search completed", logrus.Fields{
+		"url":             req.URL,
+		"resultsCount":    len(out.results),
+		"totalFiles":      out.totalFiles,
+		"durationSeconds": duration.Seconds(),
+	})
+	return out.results, nil
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcsReadScope is the OAuth scope the GCS store asks tokens for.
+const gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+// googleTokenURL exchanges refresh tokens and service-account assertions
+// when the credentials file doesn't name its own token_uri.
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// gcsCredentialsFile is the part of a Google credentials JSON file the GCS
+// store reads: a service account key or a user's refresh token, as written
+// by the Cloud console and by "gcloud auth application-default login".
+type gcsCredentialsFile struct {
+	Type         string `json:"type"` // service_account or authorized_user
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// defaultGCSCredentialsPath is where gcloud writes application default
+// credentials.
+func defaultGCSCredentialsPath() string {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gcloud", "application_default_credentials.json")
+		}
+		return ""
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", "application_default_credentials.json")
+}
+
+// gcsTokenSource hands out OAuth access tokens for the GCS store, fetching
+// a new one shortly before the last expires.
+type gcsTokenSource struct {
+	client *http.Client
+	creds  gcsCredentialsFile
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// loadGCSTokenSource follows Google's application default credentials as
+// far as they apply to a desktop app: the file named by
+// GOOGLE_APPLICATION_CREDENTIALS, then gcloud's application default
+// credentials file. It returns nil when there is neither, and requests are
+// then sent without a token, which public buckets allow.
+func loadGCSTokenSource(client *http.Client) (*gcsTokenSource, error) {
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		path = defaultGCSCredentialsPath()
+		if _, err := os.Stat(path); path == "" || errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds gcsCredentialsFile
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch creds.Type {
+	case "service_account":
+		if creds.ClientEmail == "" || creds.PrivateKey == "" {
+			return nil, fmt.Errorf("%s: service account key without client_email or private_key", path)
+		}
+	case "authorized_user":
+		if creds.RefreshToken == "" {
+			return nil, fmt.Errorf("%s: authorized user without refresh_token", path)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported credentials type %q", path, creds.Type)
+	}
+	if creds.TokenURI == "" {
+		creds.TokenURI = googleTokenURL
+	}
+	return &gcsTokenSource{client: client, creds: creds, now: time.Now}, nil
+}
+
+// serviceAccountAssertion returns the signed JWT a service account trades
+// for an access token (RFC 7523).
+func (ts *gcsTokenSource) serviceAccountAssertion() (string, error) {
+	block, _ := pem.Decode([]byte(ts.creds.PrivateKey))
+	if block == nil {
+		return "", errors.New("private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", err
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("private_key is not an RSA key")
+	}
+
+	now := ts.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   ts.creds.ClientEmail,
+		"scope": gcsReadScope,
+		"aud":   ts.creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// accessToken returns a token valid for at least another minute.
+func (ts *gcsTokenSource) accessToken(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token != "" && ts.now().Add(time.Minute).Before(ts.expires) {
+		return ts.token, nil
+	}
+
+	form := url.Values{}
+	if ts.creds.Type == "service_account" {
+		assertion, err := ts.serviceAccountAssertion()
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	} else {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", ts.creds.RefreshToken)
+		form.Set("client_id", ts.creds.ClientID)
+		form.Set("client_secret", ts.creds.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.creds.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("token request failed: %w", gcsResponseError(resp))
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.AccessToken == "" {
+		return "", errors.New("token response without access_token")
+	}
+	ts.token = body.AccessToken
+	ts.expires = ts.now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	return ts.token, nil
+}
+
+// gcsStore reads a bucket through the GCS JSON API, or the emulator named
+// by STORAGE_EMULATOR_HOST, which needs no credentials.
+type gcsStore struct {
+	client   *http.Client
+	bucket   string
+	endpoint string // Scheme and host, without a trailing slash
+	tokens   *gcsTokenSource
+}
+
+func newGCSStore(client *http.Client, bucket string) (*gcsStore, error) {
+	s := &gcsStore{client: client, bucket: bucket, endpoint: "https://storage.googleapis.com"}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+		s.endpoint = strings.TrimSuffix(host, "/")
+		return s, nil
+	}
+	tokens, err := loadGCSTokenSource(client)
+	if err != nil {
+		return nil, newAppError(ErrCodeBucketCredentials, "Google Cloud", err)
+	}
+	s.tokens = tokens
+	return s, nil
+}
+
+// get sends an authorized GET and returns the response of a 2xx status.
+func (s *gcsStore) get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.tokens != nil {
+		token, err := s.tokens.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, gcsResponseError(resp)
+	}
+	return resp, nil
+}
+
+// gcsResponseError describes a non-2xx response, with the API's message
+// when the body has one.
+func gcsResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(body, &e) == nil {
+		if e.Error.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Error.Message)
+		}
+		if e.Description != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Description)
+		}
+	}
+	return errors.New(resp.Status)
+}
+
+func (s *gcsStore) list(ctx context.Context, prefix string) ([]bucketObject, error) {
+	var objects []bucketObject
+	pageToken := ""
+	for {
+		query := url.Values{"fields": {"items(name,size,updated),nextPageToken"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		resp, err := s.get(ctx, s.endpoint+"/storage/v1/b/"+url.PathEscape(s.bucket)+"/o?"+query.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Items []struct {
+				Name    string    `json:"name"`
+				Size    string    `json:"size"` // int64 as a string, as the API encodes it
+				Updated time.Time `json:"updated"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			size, err := strconv.ParseInt(item.Size, 10, 64)
+			if err != nil {
+				continue
+			}
+			objects = append(objects, bucketObject{key: item.Name, size: size, modified: item.Updated})
+		}
+		if page.NextPageToken == "" {
+			return objects, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (s *gcsStore) open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.get(ctx, s.endpoint+"/storage/v1/b/"+url.PathEscape(s.bucket)+"/o/"+url.PathEscape(key)+"?alt=media")
+	if err != nil {
+		return nil, fmt.Errorf("gs://%s/%s: %w", s.bucket, key, err)
+	}
+	return resp.Body, nil
+}
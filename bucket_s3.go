@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, the payload hash of
+// every request the S3 store sends.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// defaultS3Region is used when neither the environment nor the config file
+// names a region.
+const defaultS3Region = "us-east-1"
+
+// s3Credentials are the AWS keys requests are signed with.
+type s3Credentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// parseINI reads the sections of an AWS config or credentials file:
+// "[name]" headers followed by "key = value" lines. Comments start with #
+// or ;.
+func parseINI(data []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[' && line[len(line)-1] == ']':
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if sections[name] == nil {
+				sections[name] = make(map[string]string)
+			}
+			current = sections[name]
+		case current != nil:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return sections
+}
+
+// awsFile returns the path of an AWS file: the one named by envVar, or
+// name in ~/.aws.
+func awsFile(envVar, name string) string {
+	if path := os.Getenv(envVar); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// awsProfile returns the profile to read from the AWS files.
+func awsProfile() string {
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
+// loadS3Credentials follows the AWS SDK chain as far as it applies to a
+// desktop app: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (with
+// AWS_SESSION_TOKEN), then the profile (AWS_PROFILE, default "default") of
+// the shared credentials file (AWS_SHARED_CREDENTIALS_FILE, default
+// ~/.aws/credentials). ok is false when neither has keys, and requests are
+// then sent unsigned, which public buckets allow.
+func loadS3Credentials() (creds s3Credentials, ok bool, err error) {
+	if key := os.Getenv("AWS_ACCESS_KEY_ID"); key != "" {
+		secret := os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if secret == "" {
+			return s3Credentials{}, false, errors.New("AWS_ACCESS_KEY_ID is set without AWS_SECRET_ACCESS_KEY")
+		}
+		return s3Credentials{accessKey: key, secretKey: secret, sessionToken: os.Getenv("AWS_SESSION_TOKEN")}, true, nil
+	}
+
+	path := awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	if path == "" {
+		return s3Credentials{}, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s3Credentials{}, false, nil
+	}
+	if err != nil {
+		return s3Credentials{}, false, err
+	}
+	profile := parseINI(data)[awsProfile()]
+	if profile == nil || profile["aws_access_key_id"] == "" {
+		return s3Credentials{}, false, nil
+	}
+	if profile["aws_secret_access_key"] == "" {
+		return s3Credentials{}, false, fmt.Errorf("profile %s in %s has no aws_secret_access_key", awsProfile(), path)
+	}
+	return s3Credentials{
+		accessKey:    profile["aws_access_key_id"],
+		secretKey:    profile["aws_secret_access_key"],
+		sessionToken: profile["aws_session_token"],
+	}, true, nil
+}
+
+// s3Region returns AWS_REGION, AWS_DEFAULT_REGION, the region of the
+// profile in the config file (AWS_CONFIG_FILE, default ~/.aws/config), or
+// us-east-1.
+func s3Region() string {
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(env); region != "" {
+			return region
+		}
+	}
+	if data, err := os.ReadFile(awsFile("AWS_CONFIG_FILE", "config")); err == nil {
+		sections := parseINI(data)
+		// The config file names profiles "[profile x]", except the default.
+		name := "profile " + awsProfile()
+		if awsProfile() == "default" {
+			name = "default"
+		}
+		if region := sections[name]["region"]; region != "" {
+			return region
+		}
+	}
+	return defaultS3Region
+}
+
+// awsEscape percent-encodes s as SigV4 canonical requests require: every
+// byte except the unreserved A-Z a-z 0-9 - _ . ~, and "/" too unless
+// keepSlash.
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && keepSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// awsQuery encodes query parameters sorted by name, as both the request
+// URL and the canonical request use them.
+func awsQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, awsEscape(name, false)+"="+awsEscape(value, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// signV4 signs req for service in region with AWS Signature Version 4,
+// setting X-Amz-Date, X-Amz-Content-Sha256, X-Amz-Security-Token when
+// there is a session token, and Authorization. The host and every header
+// already on req are signed. req.URL must already be escaped with awsEscape
+// (RawPath and RawQuery).
+func signV4(req *http.Request, creds s3Credentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	}
+	if creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalPath := req.URL.EscapedPath()
+	if canonicalPath == "" {
+		canonicalPath = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// s3Store reads a bucket through the S3 REST API, or an S3-compatible
+// service (MinIO, Cloudflare R2, a GCS interoperability endpoint) named by
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL.
+type s3Store struct {
+	client    *http.Client
+	bucket    string
+	region    string
+	creds     s3Credentials
+	signed    bool
+	endpoint  *url.URL // Scheme and host requests go to
+	pathStyle bool     // The bucket is the first path segment, not part of the host
+	now       func() time.Time
+}
+
+func newS3Store(client *http.Client, bucket string) (*s3Store, error) {
+	creds, signed, err := loadS3Credentials()
+	if err != nil {
+		return nil, newAppError(ErrCodeBucketCredentials, "AWS", err)
+	}
+	s := &s3Store{client: client, bucket: bucket, region: s3Region(), creds: creds, signed: signed, now: time.Now}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_S3")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	switch {
+	case endpoint != "":
+		// Custom endpoints are addressed path-style, which every
+		// S3-compatible service supports.
+		s.endpoint, err = url.Parse(endpoint)
+		if err != nil || s.endpoint.Host == "" {
+			return nil, newAppError(ErrCodeBucketURLInvalid, endpoint)
+		}
+		s.pathStyle = true
+	case strings.Contains(bucket, "."):
+		// A dotted bucket name breaks the TLS wildcard of the virtual host.
+		s.endpoint = &url.URL{Scheme: "https", Host: "s3." + s.region + ".amazonaws.com"}
+		s.pathStyle = true
+	default:
+		s.endpoint = &url.URL{Scheme: "https", Host: bucket + ".s3." + s.region + ".amazonaws.com"}
+	}
+	return s, nil
+}
+
+// request builds and signs a GET of key (empty for the bucket itself).
+func (s *s3Store) request(ctx context.Context, key string, query url.Values) (*http.Request, error) {
+	objectPath := "/" + key
+	if s.pathStyle {
+		objectPath = "/" + s.bucket + "/" + key
+	}
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+	u.RawPath = awsEscape(u.Path, true)
+	u.RawQuery = awsQuery(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.signed {
+		signV4(req, s.creds, s.region, "s3", s.now())
+	}
+	return req, nil
+}
+
+// s3Error is the XML body of a failed S3 request.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// s3ResponseError describes a non-2xx response, with S3's error code and
+// message when the body has them.
+func s3ResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("%s: %s (%s)", resp.Status, e.Message, e.Code)
+	}
+	return errors.New(resp.Status)
+}
+
+// s3ListResult is the part of a ListObjectsV2 response the store reads.
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+func (s *s3Store) list(ctx context.Context, prefix string) ([]bucketObject, error) {
+	var objects []bucketObject
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.request(ctx, "", query)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var page s3ListResult
+		if resp.StatusCode/100 != 2 {
+			err = s3ResponseError(resp)
+		} else {
+			err = xml.NewDecoder(resp.Body).Decode(&page)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range page.Contents {
+			objects = append(objects, bucketObject{key: c.Key, size: c.Size, modified: c.LastModified})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *s3Store) open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("s3://%s/%s: %w", s.bucket, key, s3ResponseError(resp))
+	}
+	return resp.Body, nil
+}
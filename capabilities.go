@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// commandAvailable reports whether an executable is on PATH.
+func commandAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// GetCapabilities reports what this machine and build of the backend can do,
+// so the frontend can tailor onboarding and hide actions that would only
+// fail at runtime (e.g. "Show in folder" on a Linux box without xdg-open).
+// Editor availability comes from the startup detection cache; the tool
+// lookups are PATH scans done on each call, which is cheap enough for the
+// once-per-launch onboarding check.
+func (a *App) GetCapabilities() Capabilities {
+	editors := a.GetAvailableEditors()
+
+	return Capabilities{
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Editors:       editors,
+		EditorCount:   countEditorsFromSnapshot(editors),
+		Git:           commandAvailable("git"),
+		Ripgrep:       commandAvailable("rg"),
+		FileManager:   fileManagerAvailable(),
+		DefaultEditor: commandAvailable(defaultEditorCommand),
+		LongPaths:     longPathsSupported,
+		OwnerFilters:  ownerFiltersSupported,
+	}
+}
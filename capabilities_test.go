@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+// TestGetCapabilities verifies that the capability report matches the
+// platform and the tools actually on PATH.
+func TestGetCapabilities(t *testing.T) {
+	app := NewApp()
+	caps := app.GetCapabilities()
+
+	if caps.OS != runtime.GOOS || caps.Arch != runtime.GOARCH {
+		t.Errorf("expected %s/%s, got %s/%s", runtime.GOOS, runtime.GOARCH, caps.OS, caps.Arch)
+	}
+	if !caps.LongPaths {
+		t.Error("expected long-path support to be reported")
+	}
+
+	_, gitErr := exec.LookPath("git")
+	if caps.Git != (gitErr == nil) {
+		t.Errorf("Git = %v, but LookPath error = %v", caps.Git, gitErr)
+	}
+	_, rgErr := exec.LookPath("rg")
+	if caps.Ripgrep != (rgErr == nil) {
+		t.Errorf("Ripgrep = %v, but LookPath error = %v", caps.Ripgrep, rgErr)
+	}
+	if caps.FileManager != fileManagerAvailable() {
+		t.Errorf("FileManager = %v, but fileManagerAvailable() = %v", caps.FileManager, fileManagerAvailable())
+	}
+}
+
+// TestGetCapabilitiesEditorsFromCache verifies that editor availability is
+// taken from the detection cache rather than re-probed.
+func TestGetCapabilitiesEditorsFromCache(t *testing.T) {
+	app := NewApp()
+	app.editorsMu.Lock()
+	app.availableEditors = EditorAvailability{VSCode: true, Vim: true}
+	app.editorsMu.Unlock()
+
+	caps := app.GetCapabilities()
+	if !caps.Editors.VSCode || !caps.Editors.Vim || caps.Editors.Emacs {
+		t.Errorf("editors not taken from cache: %+v", caps.Editors)
+	}
+	if caps.EditorCount != 2 {
+		t.Errorf("expected EditorCount=2, got %d", caps.EditorCount)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// captureMatcher is the line matcher for ExtractGroups searches: the
+// compiled pattern, which also reports each match's capture groups.
+type captureMatcher struct {
+	*regexp.Regexp
+}
+
+// captures returns the capture groups of the first match in line, keyed by
+// group name, or by number for unnamed groups. Groups that took no part in
+// the match are left out; nil means no group matched.
+func (m captureMatcher) captures(line string) map[string]string {
+	loc := m.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return nil
+	}
+	var groups map[string]string
+	for i, name := range m.SubexpNames() {
+		if i == 0 || loc[2*i] < 0 {
+			continue
+		}
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		if groups == nil {
+			groups = make(map[string]string)
+		}
+		groups[name] = line[loc[2*i]:loc[2*i+1]]
+	}
+	return groups
+}
+
+// lineCaptures returns the capture groups for a matched line when the
+// search extracts them, nil otherwise.
+func lineCaptures(matcher lineMatcher, line string) map[string]string {
+	if m, ok := matcher.(captureMatcher); ok {
+		return m.captures(line)
+	}
+	return nil
+}
+
+// maxCaptureExamples is how many example locations AggregateCaptures keeps
+// per value.
+const maxCaptureExamples = 3
+
+// captureGroupKey resolves group against the groups of pattern to the key
+// captureMatcher stores it under: a name stays a name, and a number names
+// the group when it has one. An empty group means the first group.
+func captureGroupKey(pattern *regexp.Regexp, group string) (string, error) {
+	names := pattern.SubexpNames()
+	if group == "" {
+		group = "1"
+	}
+	if n, err := strconv.Atoi(group); err == nil {
+		if n < 1 || n >= len(names) {
+			return "", newAppError(ErrCodeUnknownCaptureGroup, group)
+		}
+		if names[n] != "" {
+			return names[n], nil
+		}
+		return group, nil
+	}
+	if pattern.SubexpIndex(group) < 0 {
+		return "", newAppError(ErrCodeUnknownCaptureGroup, group)
+	}
+	return group, nil
+}
+
+// AggregateCaptures reports the distinct values a capture group took in a
+// completed ExtractGroups search, with how often each occurred and where.
+// group is a group name or number; empty means the first group. Only the
+// results the search kept are counted, so raise MaxResults for a complete
+// report. Searches that did not extract groups fail with NO_CAPTURES.
+func (a *App) AggregateCaptures(searchID string, group string) (CaptureReport, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return CaptureReport{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	if !rec.request.ExtractGroups {
+		return CaptureReport{}, newAppError(ErrCodeNoCaptures, searchID)
+	}
+	pattern, err := a.compileSearchPattern(rec.request)
+	if err != nil {
+		return CaptureReport{}, err
+	}
+	key, err := captureGroupKey(pattern, group)
+	if err != nil {
+		return CaptureReport{}, err
+	}
+
+	report := CaptureReport{SearchID: searchID, Group: key, Values: []CaptureValue{}, Results: len(rec.results)}
+	index := make(map[string]int)
+	files := make(map[string]map[string]bool)
+	for _, result := range rec.results {
+		value, ok := result.Captures[key]
+		if !ok {
+			continue
+		}
+		report.Captured++
+		i, seen := index[value]
+		if !seen {
+			i = len(report.Values)
+			index[value] = i
+			report.Values = append(report.Values, CaptureValue{Value: value, Examples: []CaptureLocation{}})
+			files[value] = make(map[string]bool)
+		}
+		v := &report.Values[i]
+		v.Count++
+		if !files[value][result.FilePath] {
+			files[value][result.FilePath] = true
+			v.Files++
+		}
+		if len(v.Examples) < maxCaptureExamples {
+			v.Examples = append(v.Examples, CaptureLocation{FilePath: result.FilePath, LineNum: result.LineNum})
+		}
+	}
+
+	sort.SliceStable(report.Values, func(i, j int) bool {
+		if report.Values[i].Count != report.Values[j].Count {
+			return report.Values[i].Count > report.Values[j].Count
+		}
+		return report.Values[i].Value < report.Values[j].Value
+	})
+	a.logDebug("Captures aggregated", logrus.Fields{
+		"searchId": searchID,
+		"group":    key,
+		"values":   len(report.Values),
+	})
+	return report, nil
+}
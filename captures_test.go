@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// TestCaptureMatcher verifies the group keys and that groups outside the
+// match are left out.
+func TestCaptureMatcher(t *testing.T) {
+	m := captureMatcher{regexp.MustCompile(`(?P<key>\w+)=(\d+)(px)?`)}
+	tests := map[string]map[string]string{
+		"width=40px": {"key": "width", "2": "40", "3": "px"},
+		"count=7":    {"key": "count", "2": "7"},
+		"a=1 b=2":    {"key": "a", "2": "1"},
+		"no match":   nil,
+	}
+	for line, want := range tests {
+		if got := m.captures(line); !reflect.DeepEqual(got, want) {
+			t.Errorf("captures(%q) = %v, want %v", line, got, want)
+		}
+	}
+
+	if got := lineCaptures(regexp.MustCompile(`(\d+)`), "x=1"); got != nil {
+		t.Errorf("expected no captures without ExtractGroups, got %v", got)
+	}
+}
+
+// TestSearchExtractGroups verifies extraction end to end, in the streaming
+// path, and the rejected combinations.
+func TestSearchExtractGroups(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "package.json")
+	body := "{\n  \"name\": \"demo\",\n  \"version\": \"1.4.2\"\n}\n"
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("creating package.json: %v", err)
+	}
+
+	useRegex := true
+	req := SearchRequest{Directory: tempDir, Query: `"version": "(?P<version>[^"]+)"`, UseRegex: &useRegex, ExtractGroups: true, SearchSubdirs: true}
+	results, err := app.SearchWithProgress(req)
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Captures["version"] != "1.4.2" {
+		t.Fatalf("expected version 1.4.2 captured, got %+v", results)
+	}
+
+	streamed, err := app.processFileLineByLine(t.Context(), path, searchLineMatcher(req, regexp.MustCompile(req.Query)), 10, 0)
+	if err != nil {
+		t.Fatalf("processFileLineByLine failed: %v", err)
+	}
+	if len(streamed) != 1 || streamed[0].Captures["version"] != "1.4.2" {
+		t.Errorf("expected version 1.4.2 captured when streaming, got %+v", streamed)
+	}
+
+	literal := false
+	_, err = app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "version", UseRegex: &literal, ExtractGroups: true})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeExtractNeedsRegex {
+		t.Errorf("expected %s, got %v", ErrCodeExtractNeedsRegex, err)
+	}
+	_, err = app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "version", UseRegex: &useRegex, ExtractGroups: true})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeNoCaptureGroups {
+		t.Errorf("expected %s, got %v", ErrCodeNoCaptureGroups, err)
+	}
+}
+
+// TestAggregateCaptures verifies the distinct values, their counts and
+// examples, group lookup by name and number, and the error cases.
+func TestAggregateCaptures(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"a.js": "fetch(\"/api/users\")\nfetch(\"/api/orders\")\n",
+		"b.js": "fetch(\"/api/users\")\n",
+		"c.js": "fetch(\"/api/users\")\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	useRegex := true
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: `fetch\("(?P<url>[^"]+)"\)`, UseRegex: &useRegex, ExtractGroups: true, SearchSubdirs: true}); err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	report, err := app.AggregateCaptures("search-1", "url")
+	if err != nil {
+		t.Fatalf("AggregateCaptures failed: %v", err)
+	}
+	if report.Captured != 4 || len(report.Values) != 2 {
+		t.Fatalf("expected 4 captures of 2 values, got %+v", report)
+	}
+	top := report.Values[0]
+	if top.Value != "/api/users" || top.Count != 3 || top.Files != 3 || len(top.Examples) != 3 {
+		t.Errorf("expected /api/users 3 times in 3 files, got %+v", top)
+	}
+
+	byNumber, err := app.AggregateCaptures("search-1", "1")
+	if err != nil || byNumber.Group != "url" {
+		t.Errorf("expected group 1 to resolve to url, got %q, %v", byNumber.Group, err)
+	}
+	if _, err := app.AggregateCaptures("search-1", "host"); err == nil || err.(*AppError).Code != ErrCodeUnknownCaptureGroup {
+		t.Errorf("expected %s, got %v", ErrCodeUnknownCaptureGroup, err)
+	}
+
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "fetch", UseRegex: &useRegex, SearchSubdirs: true}); err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if _, err := app.AggregateCaptures("search-2", ""); err == nil || err.(*AppError).Code != ErrCodeNoCaptures {
+		t.Errorf("expected %s, got %v", ErrCodeNoCaptures, err)
+	}
+}
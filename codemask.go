@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// codeSyntax describes how a language writes comments and string
+// literals, enough for maskNonCode to tell code from text.
+type codeSyntax struct {
+	lineComments  []string    // e.g. "//", "#"
+	blockComments [][2]string // Opener and closer, e.g. {"/*", "*/"}
+	quotes        string      // Characters that open a one-line string with backslash escapes
+	rawQuotes     string      // Characters that open a string without escapes that may span lines (Go and JS backticks)
+	tripleQuotes  bool        // """ and ''' open strings that may span lines (Python)
+}
+
+var (
+	cSyntax      = codeSyntax{lineComments: []string{"//"}, blockComments: [][2]string{{"/*", "*/"}}, quotes: `"'`}
+	goSyntax     = codeSyntax{lineComments: []string{"//"}, blockComments: [][2]string{{"/*", "*/"}}, quotes: `"'`, rawQuotes: "`"}
+	rustSyntax   = codeSyntax{lineComments: []string{"//"}, blockComments: [][2]string{{"/*", "*/"}}, quotes: `"`}
+	hashSyntax   = codeSyntax{lineComments: []string{"#"}, quotes: `"'`}
+	pythonSyntax = codeSyntax{lineComments: []string{"#"}, quotes: `"'`, tripleQuotes: true}
+	sqlSyntax    = codeSyntax{lineComments: []string{"--"}, blockComments: [][2]string{{"/*", "*/"}}, quotes: `"'`}
+	luaSyntax    = codeSyntax{lineComments: []string{"--"}, blockComments: [][2]string{{"--[[", "]]"}}, quotes: `"'`}
+	phpSyntax    = codeSyntax{lineComments: []string{"//", "#"}, blockComments: [][2]string{{"/*", "*/"}}, quotes: `"'`}
+	cssSyntax    = codeSyntax{blockComments: [][2]string{{"/*", "*/"}}, quotes: `"'`}
+	markupSyntax = codeSyntax{blockComments: [][2]string{{"<!--", "-->"}}}
+)
+
+// codeSyntaxes maps lower-case file extensions, without the dot, to their
+// syntax. Files of other types have no comments or strings to skip.
+var codeSyntaxes = map[string]codeSyntax{
+	"go": goSyntax, "js": goSyntax, "mjs": goSyntax, "cjs": goSyntax, "jsx": goSyntax,
+	"ts": goSyntax, "tsx": goSyntax, "vue": goSyntax, "svelte": goSyntax,
+	"c": cSyntax, "h": cSyntax, "cc": cSyntax, "cpp": cSyntax, "hpp": cSyntax, "cs": cSyntax,
+	"java": cSyntax, "kt": cSyntax, "kts": cSyntax, "scala": cSyntax, "swift": cSyntax, "dart": cSyntax,
+	"rs": rustSyntax,
+	"py": pythonSyntax,
+	"rb": hashSyntax, "sh": hashSyntax, "bash": hashSyntax, "zsh": hashSyntax, "pl": hashSyntax,
+	"r": hashSyntax, "yaml": hashSyntax, "yml": hashSyntax, "toml": hashSyntax,
+	"sql": sqlSyntax,
+	"lua": luaSyntax,
+	"php": phpSyntax,
+	"css": cssSyntax, "scss": cssSyntax, "less": cssSyntax,
+	"html": markupSyntax, "htm": markupSyntax, "xml": markupSyntax,
+}
+
+// maskNonCode returns a copy of content with every comment and string
+// literal blanked out to spaces, keeping line breaks and byte offsets, so
+// a pattern run over the copy only matches in code. ok is false when the
+// extension has no known syntax; the copy is then unchanged. The scan is
+// lexical and doesn't know every language's corner cases, such as string
+// interpolation or nested comments.
+func maskNonCode(content []byte, ext string) (masked []byte, ok bool) {
+	syntax, ok := codeSyntaxes[strings.ToLower(strings.TrimPrefix(ext, "."))]
+	masked = bytes.Clone(content)
+	if !ok {
+		return masked, false
+	}
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if masked[i] != '\n' && masked[i] != '\r' {
+				masked[i] = ' '
+			}
+		}
+	}
+	// skipTo returns the offset just past the first closer at or after i,
+	// or the end of content.
+	skipTo := func(i int, closer string) int {
+		if j := bytes.Index(content[i:], []byte(closer)); j >= 0 {
+			return i + j + len(closer)
+		}
+		return len(content)
+	}
+
+scan:
+	for i := 0; i < len(content); {
+		rest := content[i:]
+		// Block comments come first, so Lua's --[[ wins over --.
+		for _, block := range syntax.blockComments {
+			if bytes.HasPrefix(rest, []byte(block[0])) {
+				end := skipTo(i+len(block[0]), block[1])
+				blank(i, end)
+				i = end
+				continue scan
+			}
+		}
+		for _, marker := range syntax.lineComments {
+			if bytes.HasPrefix(rest, []byte(marker)) {
+				end := i + len(rest)
+				if j := bytes.IndexByte(rest, '\n'); j >= 0 {
+					end = i + j
+				}
+				blank(i, end)
+				i = end
+				continue scan
+			}
+		}
+		c := content[i]
+		switch {
+		case syntax.tripleQuotes && (bytes.HasPrefix(rest, []byte(`"""`)) || bytes.HasPrefix(rest, []byte(`'''`))):
+			end := skipTo(i+3, string(rest[:3]))
+			blank(i, end)
+			i = end
+		case strings.IndexByte(syntax.rawQuotes, c) >= 0:
+			end := skipTo(i+1, string(c))
+			blank(i, end)
+			i = end
+		case strings.IndexByte(syntax.quotes, c) >= 0:
+			// A one-line string ends at its unescaped closing quote, or
+			// at the end of the line if it isn't closed.
+			end := i + 1
+			for end < len(content) && content[end] != c && content[end] != '\n' {
+				if content[end] == '\\' && end+1 < len(content) && content[end+1] != '\n' {
+					end++
+				}
+				end++
+			}
+			if end < len(content) && content[end] == c {
+				end++
+			}
+			blank(i, end)
+			i = end
+		default:
+			i++
+		}
+	}
+	return masked, true
+}
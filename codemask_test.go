@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+// TestMaskNonCode verifies that comments and strings are blanked without
+// moving any byte, per language, and that unknown types are left as is.
+func TestMaskNonCode(t *testing.T) {
+	tests := []struct {
+		ext, in, want string
+	}{
+		{".go", "x := y // y here\n", "x := y          \n"},
+		{".go", "a /* b\nc */ d", "a     \n     d"},
+		{".go", `f("a\"b", 'c') + g`, `f(      ,    ) + g`},
+		{".go", "s := `raw\nx` + x", "s :=     \n   + x"},
+		{".go", "\"open\nx", "     \nx"},
+		{".py", "x = '''doc\nx''' # x\nx", "x =       \n        \nx"},
+		{".sql", "x -- x\n'x' x", "x     \n    x"},
+		{".lua", "--[[ x\nx ]] x -- x", "      \n     x     "},
+		{".HTML", "<!-- x --> x", "           x"},
+		{".txt", "x // x", "x // x"},
+	}
+	for _, tt := range tests {
+		got, ok := maskNonCode([]byte(tt.in), tt.ext)
+		if string(got) != tt.want {
+			t.Errorf("maskNonCode(%q, %s) = %q, want %q", tt.in, tt.ext, got, tt.want)
+		}
+		if ok != (tt.ext != ".txt") {
+			t.Errorf("maskNonCode(%q, %s) ok = %v", tt.in, tt.ext, ok)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import "time"
+
+// collectionProgressInterval is how often a directory walk reports that it
+// is still running. Walks that finish sooner report nothing.
+const collectionProgressInterval = 500 * time.Millisecond
+
+// collectionHeartbeat emits collection-progress events while a search's
+// directory walk runs, at most once per interval, so a search over a large
+// or slow tree shows the files it has listed so far instead of a silent
+// pause before its started event.
+type collectionHeartbeat struct {
+	emit     func(CollectionProgress)
+	searchID string
+	root     string
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+}
+
+// newCollectionHeartbeat returns a heartbeat for the walk of root that
+// search searchID runs, emitting collection-progress events to the
+// frontend. Walks outside a search (searchID "") get nil, which reports
+// nothing.
+func (a *App) newCollectionHeartbeat(searchID, root string) *collectionHeartbeat {
+	if searchID == "" {
+		return nil
+	}
+	now := time.Now()
+	return &collectionHeartbeat{
+		emit:     func(p CollectionProgress) { a.safeEmitEvent("collection-progress", p) },
+		searchID: searchID,
+		root:     root,
+		interval: collectionProgressInterval,
+		start:    now,
+		last:     now,
+	}
+}
+
+// tick reports the walk's progress if the interval has passed since the
+// last report. path is the entry the walk just reached.
+func (h *collectionHeartbeat) tick(path string, dirsVisited, filesQueued int) {
+	if h == nil {
+		return
+	}
+	now := time.Now()
+	if now.Sub(h.last) < h.interval {
+		return
+	}
+	h.last = now
+	h.emit(CollectionProgress{
+		SearchID:    h.searchID,
+		Directory:   h.root,
+		CurrentPath: path,
+		DirsVisited: dirsVisited,
+		FilesQueued: filesQueued,
+		ElapsedMs:   now.Sub(h.start).Milliseconds(),
+	})
+}
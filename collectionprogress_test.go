@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCollectionHeartbeatThrottles verifies that the heartbeat reports only
+// once the interval has passed, with the counts it was given.
+func TestCollectionHeartbeatThrottles(t *testing.T) {
+	var events []CollectionProgress
+	now := time.Now()
+	beat := &collectionHeartbeat{
+		emit:     func(p CollectionProgress) { events = append(events, p) },
+		searchID: "search-1",
+		root:     "/repo",
+		interval: time.Hour,
+		start:    now,
+		last:     now,
+	}
+
+	beat.tick("/repo/a", 1, 2)
+	if len(events) != 0 {
+		t.Fatalf("expected no event before the interval, got %+v", events)
+	}
+
+	beat.last = now.Add(-2 * time.Hour)
+	beat.tick("/repo/b", 3, 7)
+	beat.tick("/repo/c", 4, 8)
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %+v", events)
+	}
+	got := events[0]
+	if got.SearchID != "search-1" || got.Directory != "/repo" || got.CurrentPath != "/repo/b" || got.DirsVisited != 3 || got.FilesQueued != 7 {
+		t.Errorf("unexpected event %+v", got)
+	}
+}
+
+// TestCollectionHeartbeatSearchOnly verifies that only the walk of a search
+// gets a heartbeat, so the walks of other tools can't pass for its
+// progress.
+func TestCollectionHeartbeatSearchOnly(t *testing.T) {
+	app := NewApp()
+	if beat := app.newCollectionHeartbeat("", "/repo"); beat != nil {
+		t.Errorf("expected no heartbeat outside a search, got %+v", beat)
+	}
+	var none *collectionHeartbeat
+	none.tick("/repo/a", 1, 1)
+	if beat := app.newCollectionHeartbeat("search-2", "/repo"); beat == nil || beat.searchID != "search-2" {
+		t.Errorf("expected a heartbeat for search-2, got %+v", beat)
+	}
+}
+
+// TestWalkDirectoryTreeCountsVisitedDirs verifies that the walk counts the
+// directories it enters, the root included, and not the skipped ones.
+func TestWalkDirectoryTreeCountsVisitedDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a", "a/b", ".git", "c"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "b", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	req, err := app.validateAndSetDefaults(SearchRequest{Directory: root, Query: "x", SearchSubdirs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, stats, err := app.walkDirectoryTree(req, false)
+	if err != nil {
+		t.Fatalf("walk failed: %v", err)
+	}
+	if stats.dirsVisited != 4 || stats.dirsSkipped != 1 {
+		t.Errorf("expected 4 dirs visited and 1 skipped, got %d and %d", stats.dirsVisited, stats.dirsSkipped)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ContentProvider opens the content of a file the matching pipeline reads.
+// Collectors attach one to each fileMeta they produce, so the same workers
+// can search the working tree, a git revision, or the entries of an
+// archive. Errors should wrap fs.ErrNotExist for missing files so they are
+// counted as vanished.
+type ContentProvider interface {
+	Open(path string) (io.ReadCloser, error)
+}
+
+// workingTree reads files from disk. It is the provider of every fileMeta
+// without one.
+type workingTree struct{}
+
+func (workingTree) Open(path string) (io.ReadCloser, error) {
+	return os.Open(toLongPath(path))
+}
+
+// contentProvider returns the provider the file is read through.
+func (m fileMeta) contentProvider() ContentProvider {
+	if m.content == nil {
+		return workingTree{}
+	}
+	return m.content
+}
+
+// readAllContent reads the whole file through the provider.
+func readAllContent(p ContentProvider, path string) ([]byte, error) {
+	r, err := p.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// gitRevision reads files as they were at a git revision, through
+// "git show <rev>:<path>". Paths are absolute paths inside the work tree
+// at root, so results point at where the file lives today.
+type gitRevision struct {
+	root string
+	rev  string
+}
+
+func (g gitRevision) Open(absPath string) (io.ReadCloser, error) {
+	rel, err := filepath.Rel(g.root, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, &fs.PathError{Op: "open", Path: absPath, Err: fs.ErrNotExist}
+	}
+	out, err := runGitOutput(g.root, "show", g.rev+":"+filepath.ToSlash(rel))
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: absPath, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(out)), nil
+}
+
+// listGitRevision returns the files of the revision under dir (inside the
+// work tree at root), with their sizes, read through a gitRevision
+// provider.
+func listGitRevision(root, rev, dir string) ([]fileMeta, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"ls-tree", "-r", "-z", "--long", rev}
+	if rel != "." {
+		args = append(args, "--", filepath.ToSlash(rel)+"/")
+	}
+	out, err := runGitOutput(root, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := gitRevision{root: root, rev: rev}
+	var files []fileMeta
+	// Each record is "<mode> <type> <object> <size>\t<path>", NUL-terminated.
+	for _, record := range strings.Split(string(out), "\x00") {
+		tab := strings.IndexByte(record, '\t')
+		if tab < 0 {
+			continue
+		}
+		fields := strings.Fields(record[:tab])
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, fileMeta{
+			absPath: filepath.Join(root, filepath.FromSlash(record[tab+1:])),
+			size:    size,
+			content: provider,
+		})
+	}
+	return files, nil
+}
+
+// zipArchive reads the entries of a zip file. Paths are the archive path
+// joined with the entry name ("/tmp/src.zip/pkg/main.go"), which keeps
+// results unambiguous when several archives are searched.
+type zipArchive struct {
+	path   string
+	reader *zip.ReadCloser
+	byName map[string]*zip.File
+}
+
+// openZipArchive opens a zip file for reading. Close it when the search is
+// done.
+func openZipArchive(archivePath string) (*zipArchive, error) {
+	rc, err := zip.OpenReader(toLongPath(archivePath))
+	if err != nil {
+		return nil, err
+	}
+	z := &zipArchive{path: archivePath, reader: rc, byName: make(map[string]*zip.File)}
+	for _, f := range rc.File {
+		z.byName[f.Name] = f
+	}
+	return z, nil
+}
+
+func (z *zipArchive) Open(entryPath string) (io.ReadCloser, error) {
+	rel, err := filepath.Rel(z.path, entryPath)
+	if err == nil {
+		if f, ok := z.byName[filepath.ToSlash(rel)]; ok {
+			return f.Open()
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: entryPath, Err: fs.ErrNotExist}
+}
+
+// files returns the regular-file entries of the archive, read through it.
+func (z *zipArchive) files() []fileMeta {
+	var files []fileMeta
+	for _, f := range z.reader.File {
+		if f.FileInfo().IsDir() || path.Clean(f.Name) != f.Name || strings.HasPrefix(f.Name, "../") {
+			continue
+		}
+		files = append(files, fileMeta{
+			absPath: filepath.Join(z.path, filepath.FromSlash(f.Name)),
+			size:    int64(f.UncompressedSize64),
+			content: z,
+		})
+	}
+	return files
+}
+
+// Close closes the archive.
+func (z *zipArchive) Close() error {
+	return z.reader.Close()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// runSearcherOver runs the real matcher over a fixed file list and returns
+// its results.
+func runSearcherOver(t *testing.T, files []fileMeta, query string) []SearchResult {
+	t.Helper()
+	app := NewApp()
+	s := &searcher{
+		collector: fakeCollector(files),
+		matcher:   appMatcher{app},
+		sink:      &recordingSink{},
+		log:       nopSearchLogger{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := SearchRequest{Query: query, MaxResults: 100, MaxFileSize: 10 * 1024 * 1024}
+	out, err := s.run(ctx, cancel, "search-x", req, regexp.MustCompile(query), "/")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	return out.results
+}
+
+// TestGitRevisionProvider verifies that files listed at a git revision are
+// searched as committed, not as they are in the working tree.
+func TestGitRevisionProvider(t *testing.T) {
+	root, file := initGitRepo(t, "")
+	if err := os.WriteFile(file, []byte("package main\n\n// edited\n"), 0o644); err != nil {
+		t.Fatalf("editing file: %v", err)
+	}
+
+	files, err := listGitRevision(root, "HEAD", root)
+	if err != nil {
+		t.Fatalf("listGitRevision failed: %v", err)
+	}
+	if len(files) != 1 || files[0].absPath != file {
+		t.Fatalf("expected %s at HEAD, got %+v", file, files)
+	}
+
+	results := runSearcherOver(t, files, "func main")
+	if len(results) != 1 || results[0].FilePath != file || results[0].LineNum != 3 {
+		t.Errorf("expected the committed line 3 of %s, got %+v", file, results)
+	}
+	if results := runSearcherOver(t, files, "edited"); len(results) != 0 {
+		t.Errorf("expected no match for the uncommitted edit, got %+v", results)
+	}
+
+	if _, err := (gitRevision{root: root, rev: "HEAD"}).Open(filepath.Join(root, "missing.go")); !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error for a missing path, got %v", err)
+	}
+}
+
+// TestZipArchiveProvider verifies that zip entries are searched in place and
+// reported under the archive path.
+func TestZipArchiveProvider(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "src.zip")
+	out, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("creating archive: %v", err)
+	}
+	zw := zip.NewWriter(out)
+	entries := map[string]string{
+		"pkg/main.go": "package main\n\nconst needle = 1\n",
+		"README.txt":  "no match here\n",
+	}
+	for name, body := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("creating entry %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("writing entry %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+	out.Close()
+
+	archive, err := openZipArchive(archivePath)
+	if err != nil {
+		t.Fatalf("openZipArchive failed: %v", err)
+	}
+	defer archive.Close()
+
+	files := archive.files()
+	if len(files) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(files))
+	}
+	results := runSearcherOver(t, files, "needle")
+	want := filepath.Join(archivePath, "pkg", "main.go")
+	if len(results) != 1 || results[0].FilePath != want || results[0].LineNum != 3 {
+		t.Errorf("expected a match at %s:3, got %+v", want, results)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxCooccurrenceMatches caps the matches of each pattern kept per file
+// while looking for the nearest pairs.
+const maxCooccurrenceMatches = 1000
+
+// SearchCooccurrence reports the files in which every pattern occurs, such
+// as the call sites of an API in files that also call its cleanup. Each
+// file comes with the nearest matches of every two patterns as evidence.
+//
+// The patterns are searched like BatchSearch queries: each file is read
+// once and every line is tested against every pattern, with the
+// directory, filters, and matching options of req.Search. MaxResults caps
+// the files returned. An invalid pattern fails with BATCH_QUERY_INVALID
+// and one whose operators select other files with BATCH_FILTERS_DIFFER,
+// both with the pattern's number. Files are returned sorted by path.
+func (a *App) SearchCooccurrence(req CooccurrenceRequest) ([]CooccurrenceFile, error) {
+	if len(req.Patterns) < 2 || len(req.Patterns) > maxBatchQueries {
+		return nil, newAppError(ErrCodeCooccurrencePatterns, maxBatchQueries)
+	}
+	reqs := make([]SearchRequest, len(req.Patterns))
+	for i, pattern := range req.Patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return nil, newAppError(ErrCodeCooccurrencePatterns, maxBatchQueries)
+		}
+		reqs[i] = req.Search
+		reqs[i].Query = pattern
+	}
+	queries, err := a.prepareBatch(reqs)
+	if err != nil {
+		a.logError("Co-occurrence search validation failed", err, logrus.Fields{"patterns": len(reqs)})
+		return nil, err
+	}
+	search := queries[0].req
+	maxFiles := search.MaxResults
+	for _, q := range queries {
+		q.req.MaxResults = maxCooccurrenceMatches
+	}
+
+	searchStart := time.Now()
+	searchID := a.newSearchID()
+	session := a.startSearchSession(searchID)
+	defer a.endSearchSession(session)
+	ctx, cancel := session.ctx, session.cancel
+
+	a.logInfo("Starting co-occurrence search", logrus.Fields{
+		"directory": search.Directory,
+		"patterns":  len(queries),
+	})
+	files, err := a.collectBatchFiles(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := eventSink{a}
+	sink.Progress(SearchProgress{SearchID: searchID, TotalFiles: len(files), Status: "started"})
+	found := []CooccurrenceFile{}
+	state := a.runBatch(ctx, cancel, searchID, files, search, queries, func(path string, fileResults [][]SearchResult) (int, bool) {
+		if len(found) >= maxFiles {
+			return 0, true
+		}
+		for _, results := range fileResults {
+			if len(results) == 0 {
+				return 0, false
+			}
+		}
+		found = append(found, CooccurrenceFile{FilePath: path, Pairs: nearestPairs(fileResults)})
+		return 1, len(found) >= maxFiles
+	})
+	sort.Slice(found, func(i, j int) bool { return found[i].FilePath < found[j].FilePath })
+
+	processed := int(atomic.LoadInt32(&state.processedFiles))
+	sink.Done(SearchProgress{
+		SearchID:       searchID,
+		ProcessedFiles: processed,
+		TotalFiles:     len(files),
+		ResultsCount:   len(found),
+		Status:         "completed",
+		Skipped:        state.skipStats(),
+	})
+
+	duration := time.Since(searchStart)
+	rootRemoved := atomic.LoadInt32(&state.rootRemoved) != 0
+	a.notifySearchFinished(ctx.Err() != nil && len(found) < maxFiles && !rootRemoved, len(found), duration)
+	a.logInfo("Co-occurrence search completed", logrus.Fields{
+		"patterns":        len(queries),
+		"files":           len(found),
+		"processedFiles":  processed,
+		"totalFiles":      len(files),
+		"durationSeconds": duration.Seconds(),
+	})
+	if rootRemoved {
+		return found, newAppError(ErrCodeSearchRootRemoved, search.Directory)
+	}
+	return found, nil
+}
+
+// nearestPairs returns the nearest matches of every two patterns, given
+// each pattern's matches in a file in line order.
+func nearestPairs(matches [][]SearchResult) []CooccurrencePair {
+	var pairs []CooccurrencePair
+	for i := range matches {
+		for j := i + 1; j < len(matches); j++ {
+			first, second, distance := nearestPair(matches[i], matches[j])
+			pairs = append(pairs, CooccurrencePair{
+				PatternA: i,
+				PatternB: j,
+				MatchA:   first,
+				MatchB:   second,
+				Distance: distance,
+			})
+		}
+	}
+	return pairs
+}
+
+// nearestPair finds the two matches, one from each list, with the fewest
+// lines between them. Both lists must be non-empty and in line order; the
+// earliest of equally near pairs wins.
+func nearestPair(a, b []SearchResult) (SearchResult, SearchResult, int) {
+	bestA, bestB, best := 0, 0, -1
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		distance := a[i].LineNum - b[j].LineNum
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < best {
+			bestA, bestB, best = i, j, distance
+		}
+		if a[i].LineNum < b[j].LineNum {
+			i++
+		} else {
+			j++
+		}
+	}
+	return a[bestA], b[bestB], best
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSearchCooccurrence verifies that only files containing every pattern
+// are reported, each with the nearest matches of every two patterns.
+func TestSearchCooccurrence(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"leak.go":  "f := Open(a)\nuse(f)\n\n\n\nOpen(b)\nf.Close()\n",
+		"clean.go": "x := Open(a)\ndefer x.Close()\nlog(x)\n",
+		"other.go": "Close()\n",
+		"none.go":  "nothing here\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	literal := false
+	found, err := app.SearchCooccurrence(CooccurrenceRequest{
+		Search:   SearchRequest{Directory: dir, UseRegex: &literal, CaseSensitive: true},
+		Patterns: []string{"Open(", ".Close()", "use("},
+	})
+	if err != nil {
+		t.Fatalf("SearchCooccurrence failed: %v", err)
+	}
+	if len(found) != 1 || filepath.Base(found[0].FilePath) != "leak.go" {
+		t.Fatalf("expected only leak.go, got %+v", found)
+	}
+	pairs := found[0].Pairs
+	if len(pairs) != 3 {
+		t.Fatalf("expected 3 pairs, got %+v", pairs)
+	}
+	if p := pairs[0]; p.PatternA != 0 || p.PatternB != 1 || p.MatchA.LineNum != 6 || p.MatchB.LineNum != 7 || p.Distance != 1 {
+		t.Errorf("unexpected Open/Close pair %+v", p)
+	}
+	if p := pairs[1]; p.PatternB != 2 || p.MatchA.LineNum != 1 || p.MatchB.LineNum != 2 || p.Distance != 1 {
+		t.Errorf("unexpected Open/use pair %+v", p)
+	}
+	if p := pairs[2]; p.PatternA != 1 || p.MatchA.LineNum != 7 || p.MatchB.LineNum != 2 || p.Distance != 5 {
+		t.Errorf("unexpected Close/use pair %+v", p)
+	}
+
+	found, err = app.SearchCooccurrence(CooccurrenceRequest{
+		Search:   SearchRequest{Directory: dir, UseRegex: &literal},
+		Patterns: []string{"Open(", "Close()"},
+	})
+	if err != nil || len(found) != 2 || filepath.Base(found[0].FilePath) != "clean.go" {
+		t.Errorf("expected clean.go and leak.go, got %+v, %v", found, err)
+	}
+
+	for _, patterns := range [][]string{{"Open("}, {"Open(", " "}} {
+		_, err := app.SearchCooccurrence(CooccurrenceRequest{Search: SearchRequest{Directory: dir}, Patterns: patterns})
+		if err == nil || err.(*AppError).Code != ErrCodeCooccurrencePatterns {
+			t.Errorf("%q: expected %s, got %v", patterns, ErrCodeCooccurrencePatterns, err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Limits of the logs GenerateDiagnostics includes: the newest few files in
+// the logs directory, and the end of each, where the recent entries are.
+const (
+	diagnosticsMaxLogFiles = 3
+	diagnosticsMaxLogBytes = 1 << 20
+)
+
+// defaultDiagnosticsFileName is where GenerateDiagnostics writes, in the
+// temp directory, when no path is given.
+const defaultDiagnosticsFileName = "code-search-diagnostics.zip"
+
+// redactedValue replaces secrets in the settings of a diagnostics bundle.
+const redactedValue = "[redacted]"
+
+// diagnosticsSystem is system.json of a diagnostics bundle.
+type diagnosticsSystem struct {
+	GeneratedAt string `json:"generatedAt"` // RFC 3339
+	GoVersion   string `json:"goVersion"`
+	Module      string `json:"module"`
+	Version     string `json:"version"` // Module version, "(devel)" for local builds
+	VCSRevision string `json:"vcsRevision,omitempty"`
+	NumCPU      int    `json:"numCPU"`
+	Locale      string `json:"locale"`
+}
+
+// diagnosticsSearch is last-search.json of a diagnostics bundle.
+type diagnosticsSearch struct {
+	ID          string          `json:"id"`
+	Request     SearchRequest   `json:"request"` // The validated request
+	ResultCount int             `json:"resultCount"`
+	Sampled     bool            `json:"sampled"`
+	Incomplete  bool            `json:"incomplete"`
+	FinishedAt  string          `json:"finishedAt"`         // RFC 3339
+	Progress    *SearchProgress `json:"progress,omitempty"` // Its last progress event, with the file counts and skip statistics
+}
+
+// redactSettings returns settings without values that may hold secrets:
+// hook arguments often carry tokens or webhook URLs, so only each hook's
+// program is kept.
+func redactSettings(settings Settings) Settings {
+	hooks := make([]SearchHook, len(settings.Hooks))
+	for i, hook := range settings.Hooks {
+		hooks[i] = hook
+		hooks[i].Command = nil
+		if len(hook.Command) > 0 {
+			hooks[i].Command = []string{hook.Command[0]}
+			for range hook.Command[1:] {
+				hooks[i].Command = append(hooks[i].Command, redactedValue)
+			}
+		}
+	}
+	settings.Hooks = hooks
+	return settings
+}
+
+// recentLogFiles returns the newest files in dir, at most
+// diagnosticsMaxLogFiles. A missing directory has none.
+func recentLogFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []logFile
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, logFile{filepath.Join(dir, entry.Name()), info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	var paths []string
+	for _, f := range files[:min(len(files), diagnosticsMaxLogFiles)] {
+		paths = append(paths, f.path)
+	}
+	return paths
+}
+
+// readLogTail returns the last diagnosticsMaxLogBytes of the file at path,
+// starting at a line boundary when the start is cut.
+func readLogTail(path string) ([]byte, error) {
+	file, err := os.Open(toLongPath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-diagnosticsMaxLogBytes, 0)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, diagnosticsMaxLogBytes))
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return data, nil
+}
+
+// diagnosticsSystemInfo describes the build and the machine.
+func (a *App) diagnosticsSystemInfo() diagnosticsSystem {
+	info := diagnosticsSystem{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		GoVersion:   runtime.Version(),
+		NumCPU:      runtime.NumCPU(),
+		Locale:      a.GetLocale(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Module, info.Version = build.Main.Path, build.Main.Version
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" {
+				info.VCSRevision = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// lastSearchDiagnostics describes the search that finished last, or returns
+// nil before the first one.
+func (a *App) lastSearchDiagnostics() *diagnosticsSearch {
+	a.searchesMu.Lock()
+	if len(a.searches) == 0 {
+		a.searchesMu.Unlock()
+		return nil
+	}
+	rec := a.searches[len(a.searches)-1]
+	a.searchesMu.Unlock()
+
+	last := &diagnosticsSearch{
+		ID:          rec.id,
+		Request:     rec.request,
+		ResultCount: len(rec.results),
+		Sampled:     rec.counts != nil,
+		Incomplete:  rec.incomplete,
+		FinishedAt:  rec.finishedAt.Format(time.RFC3339),
+	}
+	if progress, err := a.GetSearchProgress(rec.id); err == nil {
+		last.Progress = &progress
+	}
+	return last
+}
+
+// GenerateDiagnostics writes a zip archive to attach to bug reports and
+// returns its path. It holds the end of the newest log files (as logs/), the
+// settings with hook arguments redacted (settings.json), the capability
+// report (capabilities.json), the build and machine (system.json), the
+// search engine counters (metrics.txt), and the request and statistics of
+// the search that finished last (last-search.json, left out before the
+// first search). The logs and the last search name the searched paths and
+// queries, so the user should look the bundle over before sharing it. An
+// empty path writes code-search-diagnostics.zip in the temp directory; any
+// other path must be absolute.
+func (a *App) GenerateDiagnostics(path string) (string, error) {
+	if path == "" {
+		path = filepath.Join(os.TempDir(), defaultDiagnosticsFileName)
+	}
+	if !filepath.IsAbs(path) {
+		return "", newAppError(ErrCodeDiagnosticsFailed, path, errors.New("path must be absolute"))
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name string, data []byte) error {
+		w, err := zw.Create(name)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		return err
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	var metrics bytes.Buffer
+	writePrometheusMetrics(&metrics)
+	files := []string{"system.json", "settings.json", "capabilities.json", "metrics.txt"}
+	err := errors.Join(
+		addJSON("system.json", a.diagnosticsSystemInfo()),
+		addJSON("settings.json", redactSettings(a.currentSettings())),
+		addJSON("capabilities.json", a.GetCapabilities()),
+		add("metrics.txt", metrics.Bytes()),
+	)
+	if last := a.lastSearchDiagnostics(); last != nil && err == nil {
+		err = addJSON("last-search.json", last)
+		files = append(files, "last-search.json")
+	}
+	for _, logPath := range recentLogFiles(a.logDir) {
+		if err != nil {
+			break
+		}
+		data, readErr := readLogTail(logPath)
+		if readErr != nil {
+			a.logWarn("Skipping unreadable log file in diagnostics", logrus.Fields{"path": logPath, "error": readErr.Error()})
+			continue
+		}
+		name := "logs/" + filepath.Base(logPath)
+		err = add(name, data)
+		files = append(files, name)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return "", newAppError(ErrCodeDiagnosticsFailed, path, err)
+	}
+
+	snap := snapshotForUndo(path)
+	if err := os.WriteFile(toLongPath(path), buf.Bytes(), 0o644); err != nil {
+		return "", newAppError(ErrCodeDiagnosticsFailed, path, err)
+	}
+	a.recordUndo(snap, undoKindExport, "Generate diagnostics")
+	a.logInfo("Diagnostics bundle written", logrus.Fields{
+		"outputPath": path,
+		"files":      files,
+		"bytes":      buf.Len(),
+	})
+	return path, nil
+}
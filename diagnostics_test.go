@@ -0,0 +1,112 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readZip returns the entries of the zip archive at path by name.
+func readZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer zr.Close()
+	entries := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[f.Name] = string(data)
+	}
+	return entries
+}
+
+// TestGenerateDiagnostics verifies the bundle's entries: redacted hook
+// arguments, the capability report, the counters, and the last search.
+func TestGenerateDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("needle\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{Hooks: []SearchHook{{Event: "post-search", Command: []string{"curl", "-H", "Authorization: Bearer s3cret"}}}}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "diag.zip")
+	path, err := app.GenerateDiagnostics(out)
+	if err != nil || path != out {
+		t.Fatalf("GenerateDiagnostics = %q, %v", path, err)
+	}
+	entries := readZip(t, out)
+	for _, name := range []string{"system.json", "settings.json", "capabilities.json", "metrics.txt", "last-search.json"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("expected %s in the bundle, got %v", name, entries)
+		}
+	}
+	if settings := entries["settings.json"]; strings.Contains(settings, "s3cret") || !strings.Contains(settings, `"curl"`) {
+		t.Errorf("expected hook arguments redacted, got %s", settings)
+	}
+	var last diagnosticsSearch
+	if err := json.Unmarshal([]byte(entries["last-search.json"]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Request.Query != "needle" || last.ResultCount != 1 || last.Progress == nil || last.Progress.Status != "completed" {
+		t.Errorf("unexpected last search %+v", last)
+	}
+	if !strings.Contains(entries["metrics.txt"], "codesearch_searches_started_total") {
+		t.Errorf("expected the counters in metrics.txt, got %s", entries["metrics.txt"])
+	}
+}
+
+// TestGenerateDiagnosticsBeforeFirstSearch verifies that the bundle has no
+// last search before one ran, and that a relative path is rejected.
+func TestGenerateDiagnosticsBeforeFirstSearch(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	out := filepath.Join(t.TempDir(), "diag.zip")
+	if _, err := app.GenerateDiagnostics(out); err != nil {
+		t.Fatalf("GenerateDiagnostics failed: %v", err)
+	}
+	if _, ok := readZip(t, out)["last-search.json"]; ok {
+		t.Error("expected no last-search.json before the first search")
+	}
+
+	if _, err := app.GenerateDiagnostics("diag.zip"); err == nil || err.(*AppError).Code != ErrCodeDiagnosticsFailed {
+		t.Errorf("expected %s for a relative path, got %v", ErrCodeDiagnosticsFailed, err)
+	}
+}
+
+// TestReadLogTail verifies that a long log is cut to its last lines at a
+// line boundary.
+func TestReadLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	line := strings.Repeat("x", 99) + "\n"
+	if err := os.WriteFile(path, []byte(strings.Repeat(line, diagnosticsMaxLogBytes/100+50)+"last\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readLogTail(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > diagnosticsMaxLogBytes || !strings.HasPrefix(string(data), "xxx") || !strings.HasSuffix(string(data), "last\n") {
+		t.Errorf("unexpected tail: %d bytes, starts %q", len(data), string(data[:10]))
+	}
+	if (len(data)-len("last\n"))%len(line) != 0 {
+		t.Error("expected the tail to start at a line boundary")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// maxSelectedDirectories caps the directories one SelectDirectories call
+// collects.
+const maxSelectedDirectories = 20
+
+// dialogFilters converts the frontend's file filters for the Wails dialogs.
+// Filters without a pattern are dropped; one without a name is shown by its
+// pattern.
+func dialogFilters(filters []FileFilter) []wailsRuntime.FileFilter {
+	var out []wailsRuntime.FileFilter
+	for _, f := range filters {
+		pattern := strings.TrimSpace(f.Pattern)
+		if pattern == "" {
+			continue
+		}
+		name := strings.TrimSpace(f.DisplayName)
+		if name == "" {
+			name = pattern
+		}
+		out = append(out, wailsRuntime.FileFilter{DisplayName: name, Pattern: pattern})
+	}
+	return out
+}
+
+// SelectFile opens a native file selection dialog and returns the selected
+// path, or "" when the user cancels. filters restrict the files shown, e.g.
+// {displayName: "Go files", pattern: "*.go"}; an empty list shows all
+// files.
+func (a *App) SelectFile(title string, filters []FileFilter) (string, error) {
+	if title == "" {
+		title = "Select File"
+	}
+	if a.ctx == nil {
+		a.logError("No valid context available for file selection dialog", nil, logrus.Fields{})
+		return "", newAppError(ErrCodeDialogUnavailable)
+	}
+
+	selected, err := wailsRuntime.OpenFileDialog(a.ctx, wailsRuntime.OpenDialogOptions{
+		Title:   title,
+		Filters: dialogFilters(filters),
+	})
+	if err != nil {
+		a.logError("Failed to open file dialog", err, logrus.Fields{"title": title})
+		return "", newAppError(ErrCodeFileDialogFailed, err)
+	}
+	a.logDebug("File dialog closed", logrus.Fields{"selected": selected})
+	return selected, nil
+}
+
+// normalizeExtension returns ext with exactly one leading dot, or "" for an
+// empty extension.
+func normalizeExtension(ext string) string {
+	ext = strings.TrimLeft(strings.TrimSpace(ext), ".")
+	if ext == "" {
+		return ""
+	}
+	return "." + ext
+}
+
+// withExtension appends ext to path unless the file name already ends in it
+// (case-insensitively), so "results" saved as JSON becomes "results.json".
+// An empty path stays empty.
+func withExtension(path string, ext string) string {
+	if path == "" || ext == "" || strings.EqualFold(filepath.Ext(path), ext) {
+		return path
+	}
+	return path + ext
+}
+
+// SelectSavePath opens a native save dialog for exports and returns the
+// path to write, or "" when the user cancels. defaultName pre-fills the file
+// name; extension (e.g. "json" or ".ndjson") filters the files shown and is
+// appended to a chosen name that lacks it. The file is not created.
+func (a *App) SelectSavePath(title string, defaultName string, extension string) (string, error) {
+	if title == "" {
+		title = "Save As"
+	}
+	if a.ctx == nil {
+		a.logError("No valid context available for save dialog", nil, logrus.Fields{})
+		return "", newAppError(ErrCodeDialogUnavailable)
+	}
+
+	ext := normalizeExtension(extension)
+	options := wailsRuntime.SaveDialogOptions{
+		Title:                title,
+		DefaultFilename:      withExtension(defaultName, ext),
+		CanCreateDirectories: true,
+	}
+	if ext != "" {
+		options.Filters = dialogFilters([]FileFilter{{DisplayName: strings.ToUpper(ext[1:]) + " files (*" + ext + ")", Pattern: "*" + ext}})
+	}
+
+	selected, err := wailsRuntime.SaveFileDialog(a.ctx, options)
+	if err != nil {
+		a.logError("Failed to open save dialog", err, logrus.Fields{"title": title})
+		return "", newAppError(ErrCodeFileDialogFailed, err)
+	}
+	if selected == "" {
+		return "", nil
+	}
+	selected = withExtension(selected, ext)
+	a.logDebug("Save path selected", logrus.Fields{"path": selected})
+	return selected, nil
+}
+
+// SelectDirectories lets the user pick several directories, for multi-root
+// searches. The Wails dialogs can only pick one directory at a time, so the
+// dialog opens again after each pick, starting next to it, until the user
+// cancels; the title counts the directories picked so far. It returns the
+// distinct directories in the order picked, empty when the first dialog is
+// cancelled.
+func (a *App) SelectDirectories(title string) ([]string, error) {
+	if title == "" {
+		title = "Select Directories"
+	}
+	if a.ctx == nil {
+		a.logError("No valid context available for directory selection dialog", nil, logrus.Fields{})
+		return nil, newAppError(ErrCodeDialogUnavailable)
+	}
+
+	dirs := []string{}
+	options := wailsRuntime.OpenDialogOptions{Title: title}
+	for len(dirs) < maxSelectedDirectories {
+		selected, err := wailsRuntime.OpenDirectoryDialog(a.ctx, options)
+		if err != nil {
+			a.logError("Failed to open directory dialog", err, logrus.Fields{"title": title})
+			return nil, newAppError(ErrCodeDialogFailed, err)
+		}
+		if selected == "" {
+			break
+		}
+		if !containsString(dirs, selected) {
+			dirs = append(dirs, selected)
+		}
+		options.Title = title + " (" + strconv.Itoa(len(dirs)) + " selected, Cancel to finish)"
+		options.DefaultDirectory = filepath.Dir(selected)
+	}
+	a.logDebug("Directories selected", logrus.Fields{"count": len(dirs)})
+	return dirs, nil
+}
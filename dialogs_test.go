@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// TestDialogFilters verifies that empty patterns are dropped and unnamed
+// filters are labelled with their pattern.
+func TestDialogFilters(t *testing.T) {
+	got := dialogFilters([]FileFilter{
+		{DisplayName: "Go files", Pattern: "*.go;*.mod"},
+		{DisplayName: "Nothing", Pattern: " "},
+		{Pattern: "*.json"},
+	})
+	want := []wailsRuntime.FileFilter{
+		{DisplayName: "Go files", Pattern: "*.go;*.mod"},
+		{DisplayName: "*.json", Pattern: "*.json"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dialogFilters = %+v, want %+v", got, want)
+	}
+}
+
+// TestDialogsWithoutWindow verifies that the dialogs report
+// DIALOG_UNAVAILABLE before the window exists.
+func TestDialogsWithoutWindow(t *testing.T) {
+	app := NewApp()
+	if _, err := app.SelectFile("Pick", nil); err == nil || err.(*AppError).Code != ErrCodeDialogUnavailable {
+		t.Errorf("SelectFile: expected %s, got %v", ErrCodeDialogUnavailable, err)
+	}
+	if _, err := app.SelectDirectories("Pick"); err == nil || err.(*AppError).Code != ErrCodeDialogUnavailable {
+		t.Errorf("SelectDirectories: expected %s, got %v", ErrCodeDialogUnavailable, err)
+	}
+}
+
+// TestSaveExtension verifies extension normalization and that a chosen name
+// gets the extension only when it lacks it.
+func TestSaveExtension(t *testing.T) {
+	for in, want := range map[string]string{"json": ".json", ".ndjson": ".ndjson", " ..txt ": ".txt", "": ""} {
+		if got := normalizeExtension(in); got != want {
+			t.Errorf("normalizeExtension(%q) = %q, want %q", in, got, want)
+		}
+	}
+	cases := []struct{ path, ext, want string }{
+		{"/tmp/results", ".json", "/tmp/results.json"},
+		{"/tmp/results.JSON", ".json", "/tmp/results.JSON"},
+		{"/tmp/results.txt", ".json", "/tmp/results.txt.json"},
+		{"/tmp/results", "", "/tmp/results"},
+		{"", ".json", ""},
+	}
+	for _, c := range cases {
+		if got := withExtension(c.path, c.ext); got != c.want {
+			t.Errorf("withExtension(%q, %q) = %q, want %q", c.path, c.ext, got, c.want)
+		}
+	}
+	if _, err := NewApp().SelectSavePath("Save", "results", "json"); err == nil || err.(*AppError).Code != ErrCodeDialogUnavailable {
+		t.Errorf("SelectSavePath: expected %s, got %v", ErrCodeDialogUnavailable, err)
+	}
+}
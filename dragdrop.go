@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandleDroppedPaths turns paths dropped onto the window into search roots.
+// A dropped file stands for its parent directory. Each candidate gets the
+// same checks as a typed-in directory: no ".." traversal, no null bytes,
+// ValidateDirectory, and not a protected system directory. The accepted
+// roots are absolute, de-duplicated, and reduced to the outermost ones
+// (dropping /src and /src/app yields just /src). Rejected paths are returned
+// with a code and localized reason instead of failing the whole drop.
+func (a *App) HandleDroppedPaths(paths []string) DropResult {
+	a.logDebug("Handling dropped paths", logrus.Fields{"count": len(paths)})
+
+	result := DropResult{Roots: []string{}, Rejected: []RejectedPath{}}
+	reject := func(path string, err error) {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			appErr = newAppError(ErrCodeDirectoryNotAccessible, path)
+		}
+		result.Rejected = append(result.Rejected, RejectedPath{
+			Path:    path,
+			Code:    appErr.Code,
+			Message: appErr.Message(a.GetLocale()),
+		})
+	}
+
+	var candidates []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if containsDotDotComponent(path) {
+			reject(path, newAppError(ErrCodePathTraversal))
+			continue
+		}
+		if strings.Contains(path, "\x00") {
+			reject(path, newAppError(ErrCodePathNullByte))
+			continue
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			reject(path, newAppError(ErrCodeDirectoryInvalid, err))
+			continue
+		}
+		dir := absPath
+		if info, err := os.Stat(toLongPath(absPath)); err == nil && !info.IsDir() {
+			dir = filepath.Dir(absPath)
+		}
+
+		if _, err := a.ValidateDirectory(dir); err != nil {
+			reject(path, err)
+			continue
+		}
+		if isProtectedDirectory(dir) {
+			reject(path, newAppError(ErrCodeProtectedDirectory, dir))
+			continue
+		}
+		candidates = append(candidates, dir)
+	}
+
+	result.Roots = outermostRoots(candidates)
+	a.logInfo("Dropped paths handled", logrus.Fields{
+		"roots":    len(result.Roots),
+		"rejected": len(result.Rejected),
+	})
+	return result
+}
+
+// outermostRoots removes duplicates and any directory nested inside another
+// one in the list, keeping the first-seen order of the survivors.
+func outermostRoots(dirs []string) []string {
+	roots := []string{}
+	for _, dir := range dirs {
+		covered := false
+		for _, root := range roots {
+			if isWithinDir(dir, root) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		// A later, outer directory replaces earlier roots nested inside it.
+		kept := roots[:0]
+		for _, root := range roots {
+			if !isWithinDir(root, dir) {
+				kept = append(kept, root)
+			}
+		}
+		roots = append(kept, dir)
+	}
+	return roots
+}
+
+// isWithinDir reports whether path equals dir or lies beneath it. Both must
+// be clean absolute paths.
+func isWithinDir(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	prefix := dir
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, prefix)
+}
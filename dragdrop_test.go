@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestHandleDroppedPaths verifies that dropped files map to their parent
+// directory, nested and duplicate roots collapse to the outermost one, and
+// invalid paths are rejected with a code instead of failing the drop.
+func TestHandleDroppedPaths(t *testing.T) {
+	app := NewApp()
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	other := t.TempDir()
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("creating subdir: %v", err)
+	}
+	file := filepath.Join(other, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	result := app.HandleDroppedPaths([]string{
+		sub,
+		file,
+		root,
+		root + string(filepath.Separator),
+		"",
+		filepath.Join(root, "missing"),
+		root + "/../etc",
+		"/",
+	})
+
+	if want := []string{other, root}; !reflect.DeepEqual(result.Roots, want) {
+		t.Errorf("Roots = %v, want %v", result.Roots, want)
+	}
+
+	wantCodes := []ErrorCode{ErrCodeDirectoryNotFound, ErrCodePathTraversal, ErrCodeProtectedDirectory}
+	if len(result.Rejected) != len(wantCodes) {
+		t.Fatalf("expected %d rejected paths, got %+v", len(wantCodes), result.Rejected)
+	}
+	for i, code := range wantCodes {
+		if result.Rejected[i].Code != code {
+			t.Errorf("Rejected[%d].Code = %s, want %s", i, result.Rejected[i].Code, code)
+		}
+		if result.Rejected[i].Message == "" {
+			t.Errorf("Rejected[%d] has no message", i)
+		}
+	}
+}
+
+// TestHandleDroppedPathsEmpty verifies that an empty drop returns empty,
+// non-nil slices so the frontend can iterate them without null checks.
+func TestHandleDroppedPathsEmpty(t *testing.T) {
+	result := NewApp().HandleDroppedPaths(nil)
+	if result.Roots == nil || result.Rejected == nil {
+		t.Errorf("expected non-nil slices, got %+v", result)
+	}
+}
+
+// TestOutermostRoots covers the nesting reduction, including a sibling whose
+// name shares a prefix with a root.
+func TestOutermostRoots(t *testing.T) {
+	sep := string(filepath.Separator)
+	a := sep + "a"
+	ab := a + sep + "b"
+	abc := sep + "abc"
+	got := outermostRoots([]string{ab, abc, a, a})
+	if want := []string{abc, a}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outermostRoots = %v, want %v", got, want)
+	}
+}
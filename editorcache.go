@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// editorCacheFileName is the data-directory file holding the last editor
+// detection, so startup can skip probing while it is fresh.
+const editorCacheFileName = "editors.json"
+
+// Bounds of Settings.EditorCacheHours.
+const (
+	defaultEditorCacheHours = 24
+	maxEditorCacheHours     = 30 * 24
+)
+
+// editorCache is the on-disk layout of editorCacheFileName.
+type editorCache struct {
+	CheckedAt int64              `json:"checkedAt"` // Unix milliseconds
+	Editors   EditorAvailability `json:"editors"`
+	Paths     map[string]string  `json:"paths,omitempty"` // Binaries found outside PATH, by command (see discoverEditor)
+}
+
+// installLocation is a place an editor's installer puts its binary: a glob
+// pattern under the directory named by an environment variable.
+type installLocation struct {
+	env     string
+	pattern string
+}
+
+// findInstalledEditor returns the first binary found at locations, tried in
+// order. When a pattern matches several versioned directories, the last in
+// name order, usually the newest version, wins.
+func findInstalledEditor(locations []installLocation, getenv func(string) string) string {
+	for _, loc := range locations {
+		root := getenv(loc.env)
+		if root == "" {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(root, loc.pattern))
+		if err != nil {
+			continue
+		}
+		sort.Strings(matches)
+		for i := len(matches) - 1; i >= 0; i-- {
+			if info, err := os.Stat(matches[i]); err == nil && !info.IsDir() {
+				return matches[i]
+			}
+		}
+	}
+	return ""
+}
+
+// discoverEditor looks for an editor that is not on PATH where the platform
+// installs it (discoverEditorPath) and remembers the binary for
+// resolveEditorCommand. It reports whether the editor was found.
+func (a *App) discoverEditor(command string) bool {
+	path := discoverEditorPath(command)
+	if path == "" {
+		return false
+	}
+	a.editorsMu.Lock()
+	a.editorBinaries[command] = path
+	a.editorsMu.Unlock()
+	return true
+}
+
+// validateEditorPaths checks the manual editor paths from the settings:
+// each key must be an editorBindings name and each path an existing file
+// given as an absolute path.
+func validateEditorPaths(paths map[string]string) error {
+	for name, path := range paths {
+		if _, ok := editorBindings[name]; !ok {
+			return newAppError(ErrCodeUnknownEditor, name)
+		}
+		if !filepath.IsAbs(path) {
+			return newAppError(ErrCodeEditorPathInvalid, name, path)
+		}
+		if info, err := os.Stat(toLongPath(path)); err != nil || info.IsDir() {
+			return newAppError(ErrCodeEditorPathInvalid, name, path)
+		}
+	}
+	return nil
+}
+
+// editorPathFor returns the manual path set for the editor whose binding
+// runs command, or "".
+func editorPathFor(paths map[string]string, command string) string {
+	for name, path := range paths {
+		if editorBindings[name].command == command {
+			return path
+		}
+	}
+	return ""
+}
+
+// resolveEditorCommand returns the binary to run for an editor command: the
+// path the user set in Settings.EditorPaths, the one detection discovered
+// outside PATH, or the command itself, to be found in PATH.
+func (a *App) resolveEditorCommand(command string) string {
+	if path := editorPathFor(a.currentSettings().EditorPaths, command); path != "" {
+		return path
+	}
+	a.editorsMu.RLock()
+	path := a.editorBinaries[command]
+	a.editorsMu.RUnlock()
+	if path != "" {
+		return path
+	}
+	return command
+}
+
+// detectAvailableEditors sets the available editors at startup. A cached
+// detection younger than Settings.EditorCacheHours is used as it is;
+// otherwise every editor is probed again.
+func (a *App) detectAvailableEditors() {
+	var cache editorCache
+	found, err := a.loadJSON(editorCacheFileName, &cache)
+	if err != nil {
+		a.logWarn("Ignoring unreadable editor cache", logrus.Fields{"error": err.Error()})
+	}
+	ttl := time.Duration(a.currentSettings().EditorCacheHours) * time.Hour
+	if found && err == nil && time.Since(time.UnixMilli(cache.CheckedAt)) < ttl {
+		a.editorsMu.Lock()
+		a.availableEditors = cache.Editors
+		a.editorsCheckedAt = cache.CheckedAt
+		a.editorBinaries = make(map[string]string)
+		for command, path := range cache.Paths {
+			a.editorBinaries[command] = path
+		}
+		a.editorsMu.Unlock()
+
+		a.logDebug("Using cached editor detection", logrus.Fields{"checkedAt": cache.CheckedAt})
+		a.safeEmitEvent("editor-detection-complete", map[string]interface{}{
+			"message":    "Editor detection complete!",
+			"status":     "completed",
+			"totalFound": a.countAvailableEditors(),
+			"cached":     true,
+		})
+		return
+	}
+	a.probeEditors()
+}
+
+// saveEditorCache stores the current detection for the next startup.
+// Failures are logged; the cache is only an optimization.
+func (a *App) saveEditorCache() {
+	if a.dataDir == "" {
+		return
+	}
+	a.editorsMu.RLock()
+	cache := editorCache{CheckedAt: a.editorsCheckedAt, Editors: a.availableEditors, Paths: make(map[string]string)}
+	for command, path := range a.editorBinaries {
+		cache.Paths[command] = path
+	}
+	a.editorsMu.RUnlock()
+	if err := a.saveJSON(editorCacheFileName, cache); err != nil {
+		a.logWarn("Failed to save editor cache", logrus.Fields{"error": err.Error()})
+	}
+}
+
+// RefreshEditorDetection probes every editor again, ignoring the cache, and
+// returns the result. Use it after installing or removing an editor.
+func (a *App) RefreshEditorDetection() EditorAvailability {
+	a.probeEditors()
+	return a.GetAvailableEditors()
+}
+
+// SetEditorPath marks an editor as installed at an explicit binary path,
+// for editors outside PATH; an empty path removes the override. The path
+// is stored in Settings.EditorPaths and used whenever the editor is
+// launched. It returns the editors available afterwards.
+func (a *App) SetEditorPath(name string, path string) (EditorAvailability, error) {
+	settings := a.currentSettings()
+	paths := make(map[string]string)
+	for k, v := range settings.EditorPaths {
+		paths[k] = v
+	}
+	if path == "" {
+		delete(paths, name)
+	} else {
+		paths[name] = filepath.Clean(path)
+	}
+	settings.EditorPaths = paths
+
+	if _, err := a.UpdateSettings(settings); err != nil {
+		return EditorAvailability{}, err
+	}
+	a.logInfo("Editor path set", logrus.Fields{"editor": name, "path": path})
+	return a.RefreshEditorDetection(), nil
+}
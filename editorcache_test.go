@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestDetectAvailableEditorsUsesFreshCache verifies that startup takes a
+// fresh cached detection as it is and probes again once it has expired.
+func TestDetectAvailableEditorsUsesFreshCache(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+
+	cached := editorCache{CheckedAt: time.Now().UnixMilli(), Editors: EditorAvailability{VSCode: true, SystemDefault: true}}
+	if err := app.saveJSON(editorCacheFileName, cached); err != nil {
+		t.Fatal(err)
+	}
+	app.detectAvailableEditors()
+	if got := app.GetAvailableEditors(); !got.VSCode {
+		t.Error("expected the cached VSCode entry to be used without probing")
+	}
+
+	cached.CheckedAt = time.Now().Add(-48 * time.Hour).UnixMilli()
+	if err := app.saveJSON(editorCacheFileName, cached); err != nil {
+		t.Fatal(err)
+	}
+	app.detectAvailableEditors()
+	if got := app.GetAvailableEditors(); got.VSCode {
+		t.Error("expected an expired cache to be probed again, with no editors in PATH")
+	}
+
+	var saved editorCache
+	if _, err := app.loadJSON(editorCacheFileName, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(time.UnixMilli(saved.CheckedAt)) > time.Minute {
+		t.Errorf("expected the probe to refresh the cache, checkedAt is %d", saved.CheckedAt)
+	}
+}
+
+// TestSetEditorPath verifies that a manual path marks an editor available,
+// is used to launch it, and that invalid names and paths are rejected.
+func TestSetEditorPath(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+
+	binary := filepath.Join(t.TempDir(), "sublime_text")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	editors, err := app.SetEditorPath("Sublime", binary)
+	if err != nil {
+		t.Fatalf("SetEditorPath failed: %v", err)
+	}
+	if !editors.Sublime {
+		t.Error("expected Sublime to be available with a manual path")
+	}
+	if got := app.resolveEditorCommand("subl"); got != binary {
+		t.Errorf("resolveEditorCommand(subl) = %q, want %q", got, binary)
+	}
+
+	if _, err := app.SetEditorPath("Sublime", "sublime_text"); err == nil || err.(*AppError).Code != ErrCodeEditorPathInvalid {
+		t.Errorf("expected %s for a relative path, got %v", ErrCodeEditorPathInvalid, err)
+	}
+	if _, err := app.SetEditorPath("Sublime", filepath.Dir(binary)); err == nil || err.(*AppError).Code != ErrCodeEditorPathInvalid {
+		t.Errorf("expected %s for a directory, got %v", ErrCodeEditorPathInvalid, err)
+	}
+	if _, err := app.SetEditorPath("Kate", binary); err == nil || err.(*AppError).Code != ErrCodeUnknownEditor {
+		t.Errorf("expected %s for an unknown editor, got %v", ErrCodeUnknownEditor, err)
+	}
+
+	editors, err = app.SetEditorPath("Sublime", "")
+	if err != nil {
+		t.Fatalf("clearing the path failed: %v", err)
+	}
+	if editors.Sublime || app.resolveEditorCommand("subl") != "subl" {
+		t.Error("expected clearing the path to remove the override")
+	}
+}
+
+// TestFindInstalledEditor verifies install location lookup: unset variables
+// and directories are skipped, and the newest versioned directory wins.
+func TestFindInstalledEditor(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"GoLand 2023.3", "GoLand 2024.1"} {
+		if err := os.MkdirAll(filepath.Join(root, "JetBrains", dir, "bin"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "JetBrains", dir, "bin", "goland64.exe"), nil, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "Code.exe"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	getenv := func(name string) string {
+		if name == "ProgramFiles" {
+			return root
+		}
+		return ""
+	}
+
+	locations := []installLocation{
+		{"LOCALAPPDATA", "Programs/GoLand/bin/goland64.exe"},
+		{"ProgramFiles", "JetBrains/GoLand*/bin/goland64.exe"},
+	}
+	want := filepath.Join(root, "JetBrains", "GoLand 2024.1", "bin", "goland64.exe")
+	if got := findInstalledEditor(locations, getenv); got != want {
+		t.Errorf("findInstalledEditor = %q, want %q", got, want)
+	}
+	if got := findInstalledEditor([]installLocation{{"ProgramFiles", "Code.exe"}}, getenv); got != "" {
+		t.Errorf("expected a directory not to count as the editor, got %q", got)
+	}
+}
+
+// TestResolveEditorCommand verifies that a manual path takes precedence over
+// a discovered binary, which takes precedence over the bare command.
+func TestResolveEditorCommand(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	app.editorBinaries = map[string]string{"subl": "/opt/found/subl"}
+
+	if got := app.resolveEditorCommand("subl"); got != "/opt/found/subl" {
+		t.Errorf("expected the discovered binary, got %q", got)
+	}
+	if got := app.resolveEditorCommand("code"); got != "code" {
+		t.Errorf("expected an undiscovered command unchanged, got %q", got)
+	}
+
+	binary := filepath.Join(t.TempDir(), "subl")
+	if err := os.WriteFile(binary, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.UpdateSettings(Settings{EditorPaths: map[string]string{"Sublime": binary}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := app.resolveEditorCommand("subl"); got != binary {
+		t.Errorf("expected the manual path to win, got %q", got)
+	}
+}
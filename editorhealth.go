@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// editorCheckTimeout bounds a TestEditorLaunch run. Version checks return
+// at once; an editor still running after this is hung or has opened a
+// window instead.
+const editorCheckTimeout = 5 * time.Second
+
+// maxEditorCheckOutput caps the output kept from a version check.
+const maxEditorCheckOutput = 4096
+
+// editorVersionArgs are the arguments that make an editor print its version
+// and exit without opening a window, by binding name. Editors not listed
+// have no such flag (Notepad++, Visual Studio, the JetBrains IDEs, ...), so
+// TestEditorLaunch only looks them up.
+var editorVersionArgs = map[string][]string{
+	"VSCode":   {"--version"},
+	"VSCodium": {"--version"},
+	"Sublime":  {"--version"},
+	"Atom":     {"--version"},
+	"Geany":    {"--version"},
+	"Emacs":    {"--version"},
+	"Neovide":  {"--version"},
+	"Neovim":   {"--version"},
+	"Vim":      {"--version"},
+	"Nano":     {"--version"},
+	"Helix":    {"--version"},
+}
+
+// TestEditorLaunch checks that an editor can be started, for troubleshooting
+// "editor not found" and "failed to open" errors. It resolves the editor's
+// binary the way launching it would, then runs it with its version flag and
+// reports the exit status and output. The returned error is only for an
+// unknown editorID; problems with the editor are reported in the
+// diagnostic's Problem and Message.
+func (a *App) TestEditorLaunch(editorID string) (EditorDiagnostic, error) {
+	binding, ok := editorBindings[editorID]
+	if !ok {
+		return EditorDiagnostic{}, newAppError(ErrCodeUnknownEditor, editorID)
+	}
+
+	diag := EditorDiagnostic{Editor: editorID, Command: binding.command}
+	fail := func(err *AppError) (EditorDiagnostic, error) {
+		diag.Problem = err.Code
+		diag.Message = err.Message(a.GetLocale())
+		a.logWarn("Editor check failed", logrus.Fields{"editor": editorID, "problem": err.Error()})
+		return diag, nil
+	}
+
+	binary := a.resolveEditorCommand(binding.command)
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return fail(newAppError(ErrCodeEditorNotFound, binary, err))
+	}
+	diag.Path = path
+	switch {
+	case editorPathFor(a.currentSettings().EditorPaths, binding.command) != "":
+		diag.Source = "settings"
+	case binary != binding.command:
+		diag.Source = "discovered"
+	default:
+		diag.Source = "path"
+	}
+
+	args, ok := editorVersionArgs[editorID]
+	if !ok {
+		diag.OK = true
+		return diag, nil
+	}
+	diag.Args = args
+
+	ctx, cancel := context.WithTimeout(context.Background(), editorCheckTimeout)
+	defer cancel()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Electron editors leave helper processes holding the output pipes.
+	cmd.WaitDelay = time.Second
+	hideConsoleWindow(cmd)
+
+	start := time.Now()
+	err = cmd.Run()
+	diag.DurationMs = time.Since(start).Milliseconds()
+	output := out.String()
+	if len(output) > maxEditorCheckOutput {
+		output = output[:maxEditorCheckOutput]
+	}
+	diag.Output = output
+	diag.Version = strings.TrimSpace(strings.SplitN(strings.TrimSpace(output), "\n", 2)[0])
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		diag.Launched = true
+		return fail(newAppError(ErrCodeEditorCheckTimeout, path, strings.Join(args, " "), editorCheckTimeout))
+	case errors.As(err, &exitErr):
+		diag.Launched = true
+		diag.ExitCode = exitErr.ExitCode()
+		return fail(newAppError(ErrCodeEditorCheckExit, path, strings.Join(args, " "), diag.ExitCode))
+	case err != nil:
+		return fail(newAppError(ErrCodeEditorCheckFailed, path, err))
+	}
+
+	diag.Launched = true
+	diag.OK = true
+	a.logDebug("Editor check passed", logrus.Fields{"editor": editorID, "path": path, "version": diag.Version})
+	return diag, nil
+}
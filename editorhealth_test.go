@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestTestEditorLaunch verifies the diagnostics for a healthy editor, one
+// whose version check fails, one without a version check, a missing one,
+// and an unknown editor name.
+func TestTestEditorLaunch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as stand-in editors")
+	}
+	binDir := t.TempDir()
+	scripts := map[string]string{
+		"subl":      "#!/bin/sh\necho 'Sublime Text Build 4169'\n",
+		"geany":     "#!/bin/sh\necho 'cannot open display' >&2\nexit 3\n",
+		"notepad++": "#!/bin/sh\nexit 1\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	diag, err := app.TestEditorLaunch("Sublime")
+	if err != nil {
+		t.Fatalf("TestEditorLaunch failed: %v", err)
+	}
+	if !diag.OK || !diag.Launched || diag.Version != "Sublime Text Build 4169" || diag.Source != "path" {
+		t.Errorf("unexpected diagnostic for a healthy editor: %+v", diag)
+	}
+
+	diag, _ = app.TestEditorLaunch("Geany")
+	if diag.OK || diag.Problem != ErrCodeEditorCheckExit || diag.ExitCode != 3 {
+		t.Errorf("expected %s with exit code 3, got %+v", ErrCodeEditorCheckExit, diag)
+	}
+	if !strings.Contains(diag.Output, "cannot open display") {
+		t.Errorf("expected stderr in the output, got %q", diag.Output)
+	}
+
+	diag, _ = app.TestEditorLaunch("NotepadPlusPlus")
+	if !diag.OK || diag.Launched || len(diag.Args) != 0 {
+		t.Errorf("expected an editor without a version flag to be found but not run, got %+v", diag)
+	}
+
+	diag, _ = app.TestEditorLaunch("VSCode")
+	if diag.OK || diag.Problem != ErrCodeEditorNotFound || diag.Message == "" {
+		t.Errorf("expected %s for a missing editor, got %+v", ErrCodeEditorNotFound, diag)
+	}
+
+	if _, err := app.TestEditorLaunch("Kate"); err == nil || err.(*AppError).Code != ErrCodeUnknownEditor {
+		t.Errorf("expected %s for an unknown editor, got %v", ErrCodeUnknownEditor, err)
+	}
+}
@@ -0,0 +1,103 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// appPathsKey is where installers register their executables so the shell
+// can start them by name without them being on PATH.
+const appPathsKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths`
+
+// jetBrainsLocations returns the install locations of a JetBrains IDE: the
+// per-user Toolbox directory and the versioned machine-wide one.
+func jetBrainsLocations(product string, exe string) []installLocation {
+	return []installLocation{
+		{"LOCALAPPDATA", "Programs/" + product + "/bin/" + exe},
+		{"ProgramFiles", "JetBrains/" + product + "*/bin/" + exe},
+	}
+}
+
+// editorInstallLocations lists where each editor's installer puts it, by
+// command, for editors App Paths doesn't know about.
+var editorInstallLocations = map[string][]installLocation{
+	"code": {
+		{"LOCALAPPDATA", "Programs/Microsoft VS Code/Code.exe"},
+		{"ProgramFiles", "Microsoft VS Code/Code.exe"},
+	},
+	"codium": {
+		{"LOCALAPPDATA", "Programs/VSCodium/VSCodium.exe"},
+		{"ProgramFiles", "VSCodium/VSCodium.exe"},
+	},
+	"subl": {
+		{"ProgramFiles", "Sublime Text/subl.exe"},
+		{"ProgramFiles", "Sublime Text */subl.exe"},
+	},
+	"atom":  {{"LOCALAPPDATA", "atom/atom.exe"}},
+	"geany": {{"ProgramFiles", "Geany/bin/geany.exe"}, {"ProgramFiles(x86)", "Geany/bin/geany.exe"}},
+	"notepad++": {
+		{"ProgramFiles", "Notepad++/notepad++.exe"},
+		{"ProgramFiles(x86)", "Notepad++/notepad++.exe"},
+	},
+	"devenv":     {{"ProgramFiles", "Microsoft Visual Studio/*/*/Common7/IDE/devenv.exe"}},
+	"emacs":      {{"ProgramFiles", "Emacs/emacs-*/bin/runemacs.exe"}},
+	"nvim":       {{"ProgramFiles", "Neovim/bin/nvim.exe"}},
+	"vim":        {{"ProgramFiles", "Vim/vim*/vim.exe"}, {"ProgramFiles(x86)", "Vim/vim*/vim.exe"}},
+	"neovide":    {{"ProgramFiles", "Neovide/neovide.exe"}},
+	"codeblocks": {{"ProgramFiles", "CodeBlocks/codeblocks.exe"}, {"ProgramFiles(x86)", "CodeBlocks/codeblocks.exe"}},
+	"devcpp":     {{"ProgramFiles(x86)", "Dev-Cpp/devcpp.exe"}, {"ProgramFiles", "Dev-Cpp/devcpp.exe"}},
+	"netbeans":   {{"ProgramFiles", "NetBeans-*/netbeans/bin/netbeans64.exe"}},
+	"studio":     {{"ProgramFiles", "Android/Android Studio/bin/studio64.exe"}},
+	"goland":     jetBrainsLocations("GoLand", "goland64.exe"),
+	"pycharm":    jetBrainsLocations("PyCharm", "pycharm64.exe"),
+	"idea":       jetBrainsLocations("IntelliJ IDEA", "idea64.exe"),
+	"webstorm":   jetBrainsLocations("WebStorm", "webstorm64.exe"),
+	"phpstorm":   jetBrainsLocations("PhpStorm", "phpstorm64.exe"),
+	"clion":      jetBrainsLocations("CLion", "clion64.exe"),
+	"rider":      jetBrainsLocations("JetBrains Rider", "rider64.exe"),
+}
+
+// appPathsLookup returns the executable registered for command under App
+// Paths, per user first, or "".
+func appPathsLookup(command string) string {
+	name := command
+	if !strings.EqualFold(filepath.Ext(name), ".exe") {
+		name += ".exe"
+	}
+	for _, root := range []registry.Key{registry.CURRENT_USER, registry.LOCAL_MACHINE} {
+		key, err := registry.OpenKey(root, appPathsKey+`\`+name, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		value, valueType, err := key.GetStringValue("")
+		key.Close()
+		if err != nil {
+			continue
+		}
+		if valueType == registry.EXPAND_SZ {
+			if expanded, err := registry.ExpandString(value); err == nil {
+				value = expanded
+			}
+		}
+		// Some installers quote the path.
+		value = strings.Trim(value, `"`)
+		if info, err := os.Stat(value); err == nil && !info.IsDir() {
+			return value
+		}
+	}
+	return ""
+}
+
+// discoverEditorPath finds an editor that is not on PATH: through App Paths,
+// then at its usual install locations.
+func discoverEditorPath(command string) string {
+	if path := appPathsLookup(command); path != "" {
+		return path
+	}
+	return findInstalledEditor(editorInstallLocations[command], os.Getenv)
+}
@@ -0,0 +1,64 @@
+package main
+
+import "github.com/sirupsen/logrus"
+
+// Editor names that OpenResult and the editor settings accept besides the
+// editorBindings keys.
+const (
+	systemDefaultEditor = "SystemDefault" // The OS default app (OpenInDefaultEditor)
+	jetBrainsEditor     = "JetBrains"     // The JetBrains IDE for the file type (OpenInJetBrains)
+)
+
+// validEditorName reports whether name can be used as the default editor or
+// in the editor priority list.
+func validEditorName(name string) bool {
+	_, ok := editorBindings[name]
+	return ok || name == systemDefaultEditor || name == jetBrainsEditor
+}
+
+// editorOrder returns the editors OpenResult tries, in order: the default
+// editor, the priority list, and finally the system default, which is
+// always available. Duplicates are dropped.
+func editorOrder(settings Settings) []string {
+	var order []string
+	for _, name := range append([]string{settings.DefaultEditor}, settings.EditorPriority...) {
+		if name != "" && !containsString(order, name) {
+			order = append(order, name)
+		}
+	}
+	if !containsString(order, systemDefaultEditor) {
+		order = append(order, systemDefaultEditor)
+	}
+	return order
+}
+
+// OpenResult opens a result at its line in the user's default editor
+// (Settings.DefaultEditor). When that editor is not installed it falls back
+// through Settings.EditorPriority, and then to the system default app. It
+// returns the name of the editor it used. Only a missing editor falls
+// through; one that is installed but fails to start is reported.
+func (a *App) OpenResult(result SearchResult) (string, error) {
+	for _, name := range editorOrder(a.currentSettings()) {
+		var command string
+		switch name {
+		case systemDefaultEditor:
+			return name, a.OpenInDefaultEditor(result.FilePath)
+		case jetBrainsEditor:
+			command, _ = a.getJetBrainsEditor(result.FilePath)
+		default:
+			binding, ok := editorBindings[name]
+			if !ok {
+				continue
+			}
+			command = binding.command
+		}
+
+		if !a.isEditorAvailable(a.resolveEditorCommand(command)) {
+			a.logDebug("Preferred editor not installed, trying the next one", logrus.Fields{"editor": name})
+			continue
+		}
+		_, err := a.OpenResultsInEditor(name, []SearchResult{result}, 1)
+		return name, err
+	}
+	return "", newAppError(ErrCodeEditorNotFound, systemDefaultEditor, nil)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEditorOrder verifies that the default editor comes first, duplicates
+// are dropped, and the system default always ends the list.
+func TestEditorOrder(t *testing.T) {
+	got := editorOrder(Settings{DefaultEditor: "VSCode", EditorPriority: []string{"Sublime", "VSCode", "Vim"}})
+	want := []string{"VSCode", "Sublime", "Vim", systemDefaultEditor}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("editorOrder = %q, want %q", got, want)
+	}
+	if got := editorOrder(Settings{}); !reflect.DeepEqual(got, []string{systemDefaultEditor}) {
+		t.Errorf("expected only the system default without settings, got %q", got)
+	}
+}
+
+// TestOpenResultFallsBack verifies that OpenResult skips a missing default
+// editor, launches the next one in the priority list at the result's line,
+// and that unknown editor names are rejected in the settings.
+func TestOpenResultFallsBack(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a stand-in editor")
+	}
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "subl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{DefaultEditor: "Kate"}); err == nil || err.(*AppError).Code != ErrCodeUnknownEditor {
+		t.Errorf("expected %s for an unknown editor, got %v", ErrCodeUnknownEditor, err)
+	}
+	if _, err := app.UpdateSettings(Settings{DefaultEditor: "VSCode", EditorPriority: []string{"Sublime"}}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(target, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	used, err := app.OpenResult(SearchResult{FilePath: target, LineNum: 7})
+	if err != nil || used != "Sublime" {
+		t.Fatalf("OpenResult = %q, %v; want Sublime", used, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(argsFile)
+		if err == nil && len(data) > 0 {
+			if got := strings.TrimSpace(string(data)); got != target+":7" {
+				t.Errorf("expected the editor to get %s:7, got %q", target, got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stand-in editor was not launched")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Text encodings of a file, as detected from its byte order mark (or the
+// null bytes of UTF-16) and reported by ReadFile. Anything else is read as
+// UTF-8.
+const (
+	encodingUTF8    = "utf-8"
+	encodingUTF8BOM = "utf-8-bom"
+	encodingUTF16LE = "utf-16le"
+	encodingUTF16BE = "utf-16be"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectBOM returns the encoding named by the byte order mark at the start
+// of head and the length of the mark, or encodingUTF8 and 0 without one.
+func detectBOM(head []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return encodingUTF8BOM, len(bomUTF8)
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return encodingUTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return encodingUTF16BE, len(bomUTF16BE)
+	}
+	return encodingUTF8, 0
+}
+
+// detectEncoding is detectBOM that also recognizes UTF-16 without a BOM
+// by the pattern of its null bytes (utf16Pattern).
+func detectEncoding(head []byte) (string, int) {
+	if enc, n := detectBOM(head); n > 0 {
+		return enc, n
+	}
+	if enc := utf16Pattern(head); enc != "" {
+		return enc, 0
+	}
+	return encodingUTF8, 0
+}
+
+// utf16Pattern returns the byte order of UTF-16 text without a BOM, or "".
+// Mostly-ASCII UTF-16 has a zero high byte in at least half of its code
+// units, and a zero low byte (U+xx00) in hardly any.
+func utf16Pattern(head []byte) string {
+	n := len(head) &^ 1
+	if n < 8 {
+		return ""
+	}
+	var zeros [2]int
+	for i := 0; i < n; i++ {
+		if head[i] == 0 {
+			zeros[i&1]++
+		}
+	}
+	units := n / 2
+	switch {
+	case zeros[1]*2 >= units && zeros[0]*16 <= units:
+		return encodingUTF16LE
+	case zeros[0]*2 >= units && zeros[1]*16 <= units:
+		return encodingUTF16BE
+	}
+	return ""
+}
+
+// isUTF16 reports whether enc is one of the UTF-16 encodings.
+func isUTF16(enc string) bool {
+	return enc == encodingUTF16LE || enc == encodingUTF16BE
+}
+
+// utf16Decoder returns the decoder for a UTF-16 encoding, whose BOM the
+// caller has already skipped.
+func utf16Decoder(enc string) *encoding.Decoder {
+	if enc == encodingUTF16BE {
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	}
+	return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+}
+
+// decodeText returns content as UTF-8 without its byte order mark, and the
+// encoding it was in (detectEncoding). UTF-16 text is transcoded; invalid
+// sequences become U+FFFD.
+func decodeText(content []byte) ([]byte, string) {
+	enc, n := detectEncoding(content[:min(len(content), binarySampleSize)])
+	if !isUTF16(enc) {
+		return content[n:], enc
+	}
+	decoded, err := utf16Decoder(enc).Bytes(content[n:])
+	if err != nil {
+		return content[n:], enc
+	}
+	return decoded, enc
+}
+
+// newTextReader is decodeText for a stream: it skips the byte order mark
+// at the start of r and transcodes UTF-16 as it is read.
+func newTextReader(r io.Reader) (io.Reader, string) {
+	br := bufio.NewReader(r)
+	// Peek returns what it can of a shorter file along with an error;
+	// that is all there is to detect the encoding from.
+	head, _ := br.Peek(binarySampleSize)
+	enc, n := detectEncoding(head)
+	br.Discard(n)
+	if isUTF16(enc) {
+		return transform.NewReader(br, utf16Decoder(enc)), enc
+	}
+	return br, enc
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16 returns s as UTF-16 with a byte order mark.
+func encodeUTF16(s string, bigEndian bool) []byte {
+	out := []byte{0xFF, 0xFE}
+	if bigEndian {
+		out = []byte{0xFE, 0xFF}
+	}
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+	return out
+}
+
+// TestDecodeText verifies BOM detection and decoding, as a whole and as a
+// stream, for every supported encoding.
+func TestDecodeText(t *testing.T) {
+	const text = "package main // 検索\n"
+	cases := []struct {
+		raw  []byte
+		want string
+	}{
+		{[]byte(text), encodingUTF8},
+		{append([]byte{0xEF, 0xBB, 0xBF}, text...), encodingUTF8BOM},
+		{encodeUTF16(text, false), encodingUTF16LE},
+		{encodeUTF16(text, true), encodingUTF16BE},
+	}
+	for _, c := range cases {
+		decoded, enc := decodeText(c.raw)
+		if string(decoded) != text || enc != c.want {
+			t.Errorf("decodeText = %q, %s; want %q, %s", decoded, enc, text, c.want)
+		}
+
+		r, enc := newTextReader(strings.NewReader(string(c.raw)))
+		streamed, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(streamed) != text || enc != c.want {
+			t.Errorf("newTextReader = %q, %s; want %q, %s", streamed, enc, text, c.want)
+		}
+	}
+
+	if r, enc := newTextReader(strings.NewReader("a")); enc != encodingUTF8 {
+		t.Errorf("expected a one-byte file to read as UTF-8, got %s", enc)
+	} else if b, _ := io.ReadAll(r); string(b) != "a" {
+		t.Errorf("expected a one-byte file unchanged, got %q", b)
+	}
+}
+
+// TestBOMFiles verifies that searches match the first line of a file with
+// a BOM, find UTF-16 text in both the buffered and streaming paths, and
+// that ReadFile and GetFileSlice return the text without the BOM.
+func TestBOMFiles(t *testing.T) {
+	dir := t.TempDir()
+	const text = "package main\n\nfunc main() {}\n"
+	filler := strings.Repeat("// filler\n", 8000)
+	files := map[string][]byte{
+		"bom.go":     append([]byte{0xEF, 0xBB, 0xBF}, text...),
+		"le.dat":     encodeUTF16(text, false),
+		"be.dat":     encodeUTF16(text, true),
+		"stream.dat": encodeUTF16(text+filler, false),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if app.isBinary(files["le.dat"]) || app.isBinary(files["be.dat"]) {
+		t.Error("expected UTF-16 text with a BOM not to be binary")
+	}
+
+	useRegex := true
+	results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "^package main$", UseRegex: &useRegex, StreamingThreshold: minStreamingThreshold})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, r := range results {
+		found[filepath.Base(r.FilePath)] = true
+		if r.LineNum != 1 || r.Content != "package main" {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+	for name := range files {
+		if !found[name] {
+			t.Errorf("expected a match in %s, got %v", name, found)
+		}
+	}
+
+	file, err := app.ReadFile(filepath.Join(dir, "bom.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Content != text || file.Encoding != encodingUTF8BOM {
+		t.Errorf("ReadFile = %+v, want the text without its BOM", file)
+	}
+	file, err = app.ReadFile(filepath.Join(dir, "be.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Content != text || file.Encoding != encodingUTF16BE {
+		t.Errorf("ReadFile = %+v, want the decoded UTF-16 text", file)
+	}
+
+	slice, err := app.GetFileSlice(filepath.Join(dir, "le.dat"), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Lines) == 0 || slice.Lines[0] != "package main" {
+		t.Errorf("GetFileSlice lines = %q, want the decoded first line", slice.Lines)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Line endings reported by detectLineEnding, as FileInfo.LineEnding and
+// FileContent.LineEnding.
+const (
+	lineEndingLF   = "lf"
+	lineEndingCRLF = "crlf"
+	lineEndingCR   = "cr"
+)
+
+// detectLineEnding returns the line ending most lines of text end with,
+// or "" when it has no line break. Ties go to LF, then CRLF, so a file
+// mixing endings is written back in the style that keeps most lines as
+// they were.
+func detectLineEnding(text []byte) string {
+	lf, crlf, cr := 0, 0, 0
+	for i, c := range text {
+		switch {
+		case c == '\n' && i > 0 && text[i-1] == '\r':
+			crlf++
+		case c == '\n':
+			lf++
+		case c == '\r' && (i+1 == len(text) || text[i+1] != '\n'):
+			cr++
+		}
+	}
+	switch {
+	case lf == 0 && crlf == 0 && cr == 0:
+		return ""
+	case lf >= crlf && lf >= cr:
+		return lineEndingLF
+	case crlf >= cr:
+		return lineEndingCRLF
+	}
+	return lineEndingCR
+}
+
+// lineBreak returns the characters of a line ending from
+// detectLineEnding; "" (no line break yet) gives "\n".
+func lineBreak(lineEnding string) string {
+	switch lineEnding {
+	case lineEndingCRLF:
+		return "\r\n"
+	case lineEndingCR:
+		return "\r"
+	}
+	return "\n"
+}
+
+// trimCR removes the "\r" a CRLF line keeps after splitting on "\n", so
+// "$" anchors, matched text, and columns see the same line in CRLF and LF
+// files. The streaming paths get this from bufio.ScanLines.
+func trimCR(line []byte) []byte {
+	return bytes.TrimSuffix(line, []byte("\r"))
+}
+
+// lineSeparator returns the byte the lines of text end with: "\r" when its
+// only line breaks are lone CRs, as in classic Mac OS files, and "\n"
+// otherwise, CRLF lines included.
+func lineSeparator(text []byte) byte {
+	if bytes.IndexByte(text, '\n') < 0 && bytes.IndexByte(text, '\r') >= 0 {
+		return '\r'
+	}
+	return '\n'
+}
+
+// splitLines splits text into the lines the search numbers: after each
+// lineSeparator, without the "\r" of CRLF lines.
+func splitLines(text []byte) [][]byte {
+	sep := lineSeparator(text)
+	lines := bytes.Split(text, []byte{sep})
+	if sep == '\n' {
+		for i := range lines {
+			lines[i] = trimCR(lines[i])
+		}
+	}
+	return lines
+}
+
+// lineSniffSize is how much of a streamed file newLineScanner looks at to
+// pick the line separator.
+const lineSniffSize = 64 * 1024
+
+// newLineScanner returns a scanner of the lines of r, split as splitLines
+// splits them, that allows lines of up to bufferSize bytes. The separator
+// is chosen from the first 64KB, so a file with lone CRs there and LFs
+// only later is split on the CRs.
+func newLineScanner(r io.Reader, bufferSize int) *bufio.Scanner {
+	br := bufio.NewReaderSize(r, lineSniffSize)
+	head, _ := br.Peek(lineSniffSize)
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, bufferSize), bufferSize)
+	if lineSeparator(head) == '\r' {
+		scanner.Split(scanCRLines)
+	}
+	return scanner
+}
+
+// scanCRLines is the bufio.SplitFunc of files whose lines end with a lone
+// "\r".
+func scanCRLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\r'); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDetectLineEnding verifies the dominant line ending of LF, CRLF, CR,
+// mixed, and single-line text.
+func TestDetectLineEnding(t *testing.T) {
+	tests := map[string]string{
+		"a\nb\n":          lineEndingLF,
+		"a\r\nb\r\n":      lineEndingCRLF,
+		"a\rb\r":          lineEndingCR,
+		"a\r\nb\r\nc\n":   lineEndingCRLF,
+		"a\r\nb\nc\n":     lineEndingLF,
+		"a\r\nb\n":        lineEndingLF,
+		"no line break":   "",
+		"":                "",
+		"trailing cr\r\n": lineEndingCRLF,
+	}
+	for text, want := range tests {
+		if got := detectLineEnding([]byte(text)); got != want {
+			t.Errorf("detectLineEnding(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+// TestSearchCRLF verifies that CRLF files match "$" anchors and report
+// matches and context without "\r" in both the in-memory and streaming
+// paths, and that file metadata reports the line ending.
+func TestSearchCRLF(t *testing.T) {
+	dir := t.TempDir()
+	content := "first\r\nvalue = 1\r\nlast\r\n"
+	padding := strings.Repeat("filler line\r\n", 8000)
+	for name, text := range map[string]string{"small.txt": content, "big.txt": content + padding} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:          dir,
+		Query:              `= \d$`,
+		StreamingThreshold: minStreamingThreshold,
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected a match in each file, got %+v", results)
+	}
+	for _, r := range results {
+		if r.MatchedText != "= 1" || r.ContextBefore[len(r.ContextBefore)-1] != "first" || r.ContextAfter[0] != "last" {
+			t.Errorf("%s: unexpected match %q with context %q / %q", filepath.Base(r.FilePath), r.MatchedText, r.ContextBefore, r.ContextAfter)
+		}
+	}
+
+	info, err := app.GetFileInfo(filepath.Join(dir, "small.txt"))
+	if err != nil || info.LineEnding != lineEndingCRLF {
+		t.Errorf("expected GetFileInfo to report crlf, got %q, %v", info.LineEnding, err)
+	}
+	file, err := app.ReadFile(filepath.Join(dir, "small.txt"))
+	if err != nil || file.LineEnding != lineEndingCRLF {
+		t.Errorf("expected ReadFile to report crlf, got %q, %v", file.LineEnding, err)
+	}
+}
+
+// TestSearchCR verifies that files whose lines end with lone CRs are
+// split into lines in the in-memory and streaming paths, with the line
+// numbers that context expansion, file slices, and replacement patches
+// agree on.
+func TestSearchCR(t *testing.T) {
+	dir := t.TempDir()
+	content := "first\rvalue = 1\rlast\r"
+	padding := strings.Repeat("filler line\r", 8000)
+	for name, text := range map[string]string{"small.txt": content, "big.txt": content + padding} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	literal := false
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:          dir,
+		Query:              "value",
+		UseRegex:           &literal,
+		StreamingThreshold: minStreamingThreshold,
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected a match in each file, got %+v", results)
+	}
+	for _, r := range results {
+		if r.LineNum != 2 || r.Content != "value = 1" || r.ContextBefore[len(r.ContextBefore)-1] != "first" || r.ContextAfter[0] != "last" {
+			t.Errorf("%s: unexpected match on line %d: %q with context %q / %q", filepath.Base(r.FilePath), r.LineNum, r.Content, r.ContextBefore, r.ContextAfter)
+		}
+	}
+
+	small := filepath.Join(dir, "small.txt")
+	if slice, err := app.GetFileSlice(small, 2, 1); err != nil || strings.Join(slice.Lines, "|") != "first|value = 1|last" {
+		t.Errorf("GetFileSlice = %q, %v", slice.Lines, err)
+	}
+	for _, r := range results {
+		if r.FilePath != small {
+			continue
+		}
+		expanded, err := app.ExpandContext(r, 1, 1)
+		if err != nil || strings.Join(expanded.ContextBefore, "|") != "first" || strings.Join(expanded.ContextAfter, "|") != "last" {
+			t.Errorf("ExpandContext = %q / %q, %v", expanded.ContextBefore, expanded.ContextAfter, err)
+		}
+	}
+
+	patch, err := app.CreateReplacePatch(app.searches[len(app.searches)-1].id, "total", false)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	defer os.Remove(patch.Path)
+	cmd := exec.Command("git", "-C", patch.Root, "apply", patch.Path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply: %v\n%s", err, out)
+	}
+	if got, _ := os.ReadFile(small); string(got) != "first\rtotal = 1\rlast\r" {
+		t.Errorf("small.txt after git apply is %q", got)
+	}
+}
+
+// TestAddIgnoreRuleKeepsCRLF verifies that a rule appended to a CRLF
+// ignore file, including one without a final line break, ends in CRLF.
+func TestAddIgnoreRuleKeepsCRLF(t *testing.T) {
+	root := t.TempDir()
+	ignorePath := filepath.Join(root, ignoreFileName)
+	if err := os.WriteFile(ignorePath, []byte("# rules\r\nbuild"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	app.dataDir = ""
+	if _, err := app.AddIgnoreRule(root, "vendor"); err != nil {
+		t.Fatalf("AddIgnoreRule failed: %v", err)
+	}
+	if got, _ := os.ReadFile(ignorePath); string(got) != "# rules\r\nbuild\r\nvendor\r\n" {
+		t.Errorf("ignore file is %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode identifies a user-facing error independently of its wording, so
+// the frontend can branch on the code instead of matching message text.
+type ErrorCode string
+
+// Error codes returned to the frontend. Each has an entry per locale in
+// messageCatalog.
+const (
+	ErrCodeUnknown                 ErrorCode = "UNKNOWN"
+	ErrCodePathRequired            ErrorCode = "PATH_REQUIRED"
+	ErrCodePathTraversal           ErrorCode = "PATH_TRAVERSAL"
+	ErrCodePathNullByte            ErrorCode = "PATH_NULL_BYTE"
+	ErrCodeFileNotFound            ErrorCode = "FILE_NOT_FOUND"
+	ErrCodeFileStatFailed          ErrorCode = "FILE_STAT_FAILED"
+	ErrCodeFileTooLarge            ErrorCode = "FILE_TOO_LARGE"
+	ErrCodeFileReadFailed          ErrorCode = "FILE_READ_FAILED"
+	ErrCodeLineOutOfRange          ErrorCode = "LINE_OUT_OF_RANGE"
+	ErrCodeDirectoryRequired       ErrorCode = "DIRECTORY_REQUIRED"
+	ErrCodeDirectoryInvalid        ErrorCode = "DIRECTORY_INVALID"
+	ErrCodeDirectoryNotFound       ErrorCode = "DIRECTORY_NOT_FOUND"
+	ErrCodeNotADirectory           ErrorCode = "NOT_A_DIRECTORY"
+	ErrCodeDirectoryNotAccessible  ErrorCode = "DIRECTORY_NOT_ACCESSIBLE"
+	ErrCodeProtectedDirectory      ErrorCode = "PROTECTED_DIRECTORY"
+	ErrCodeInvalidPattern          ErrorCode = "INVALID_PATTERN"
+	ErrCodeNoActiveSearch          ErrorCode = "NO_ACTIVE_SEARCH"
+	ErrCodeEditorNotFound          ErrorCode = "EDITOR_NOT_FOUND"
+	ErrCodeUnknownEditor           ErrorCode = "UNKNOWN_EDITOR"
+	ErrCodeEditorPathInvalid       ErrorCode = "EDITOR_PATH_INVALID"
+	ErrCodeNoTerminal              ErrorCode = "NO_TERMINAL"
+	ErrCodeEditorCheckFailed       ErrorCode = "EDITOR_CHECK_FAILED"
+	ErrCodeEditorCheckExit         ErrorCode = "EDITOR_CHECK_EXIT"
+	ErrCodeEditorCheckTimeout      ErrorCode = "EDITOR_CHECK_TIMEOUT"
+	ErrCodeNoFileManager           ErrorCode = "NO_FILE_MANAGER"
+	ErrCodeEditorLaunchFailed      ErrorCode = "EDITOR_LAUNCH_FAILED"
+	ErrCodeDefaultEditorFailed     ErrorCode = "DEFAULT_EDITOR_FAILED"
+	ErrCodeNotImplemented          ErrorCode = "NOT_IMPLEMENTED"
+	ErrCodeUnsupportedPlatform     ErrorCode = "UNSUPPORTED_PLATFORM"
+	ErrCodeDialogUnavailable       ErrorCode = "DIALOG_UNAVAILABLE"
+	ErrCodeDialogFailed            ErrorCode = "DIALOG_FAILED"
+	ErrCodeFileDialogFailed        ErrorCode = "FILE_DIALOG_FAILED"
+	ErrCodeLargeFileConfirmation   ErrorCode = "LARGE_FILE_CONFIRMATION"
+	ErrCodeEditorNoProjects        ErrorCode = "EDITOR_NO_PROJECTS"
+	ErrCodeLicenseTemplateRequired ErrorCode = "LICENSE_TEMPLATE_REQUIRED"
+	ErrCodeImageUnsupported        ErrorCode = "IMAGE_UNSUPPORTED"
+	ErrCodeImageTooLarge           ErrorCode = "IMAGE_TOO_LARGE"
+	ErrCodeExportFormatUnsupported ErrorCode = "EXPORT_FORMAT_UNSUPPORTED"
+	ErrCodeSearchLinkInvalid       ErrorCode = "SEARCH_LINK_INVALID"
+	ErrCodeBucketURLInvalid        ErrorCode = "BUCKET_URL_INVALID"
+	ErrCodeBucketCredentials       ErrorCode = "BUCKET_CREDENTIALS"
+	ErrCodeBucketListFailed        ErrorCode = "BUCKET_LIST_FAILED"
+	ErrCodePluginNotFound          ErrorCode = "PLUGIN_NOT_FOUND"
+	ErrCodePluginInvalid           ErrorCode = "PLUGIN_INVALID"
+	ErrCodeHookInvalid             ErrorCode = "HOOK_INVALID"
+	ErrCodeQueryOperatorInvalid    ErrorCode = "QUERY_OPERATOR_INVALID"
+	ErrCodeFavoriteColorInvalid    ErrorCode = "FAVORITE_COLOR_INVALID"
+	ErrCodeFavoriteNotFound        ErrorCode = "FAVORITE_NOT_FOUND"
+	ErrCodeBatchTooLarge           ErrorCode = "BATCH_TOO_LARGE"
+	ErrCodeBatchQueryInvalid       ErrorCode = "BATCH_QUERY_INVALID"
+	ErrCodeBatchFiltersDiffer      ErrorCode = "BATCH_FILTERS_DIFFER"
+	ErrCodeCooccurrencePatterns    ErrorCode = "COOCCURRENCE_PATTERNS"
+	ErrCodeRegionStartRequired     ErrorCode = "REGION_START_REQUIRED"
+	ErrCodeModifiedRangeEmpty      ErrorCode = "MODIFIED_RANGE_EMPTY"
+	ErrCodeOwnerFiltersUnsupported ErrorCode = "OWNER_FILTERS_UNSUPPORTED"
+	ErrCodePermissionMaskInvalid   ErrorCode = "PERMISSION_MASK_INVALID"
+	ErrCodeReplaceUnsupported      ErrorCode = "REPLACE_UNSUPPORTED"
+	ErrCodeReplaceFileChanged      ErrorCode = "REPLACE_FILE_CHANGED"
+	ErrCodeReplaceUTF16            ErrorCode = "REPLACE_UTF16_UNSUPPORTED"
+	ErrCodeUndoNotFound            ErrorCode = "UNDO_NOT_FOUND"
+	ErrCodeUndoConflict            ErrorCode = "UNDO_CONFLICT"
+	ErrCodeUndoFailed              ErrorCode = "UNDO_FAILED"
+	ErrCodeRenameNameInvalid       ErrorCode = "RENAME_NAME_INVALID"
+	ErrCodeRenameUnchanged         ErrorCode = "RENAME_UNCHANGED"
+	ErrCodeFileTypeBundleUnknown   ErrorCode = "FILE_TYPE_BUNDLE_UNKNOWN"
+	ErrCodeContextFileChanged      ErrorCode = "CONTEXT_FILE_CHANGED"
+	ErrCodeDirectionInvalid        ErrorCode = "DIRECTION_INVALID"
+	ErrCodeFileNotInResults        ErrorCode = "FILE_NOT_IN_RESULTS"
+	ErrCodeMetricsAddrInvalid      ErrorCode = "METRICS_ADDR_INVALID"
+	ErrCodeMetricsUnavailable      ErrorCode = "METRICS_UNAVAILABLE"
+	ErrCodeDiagnosticsFailed       ErrorCode = "DIAGNOSTICS_FAILED"
+	ErrCodeUpdateChannelInvalid    ErrorCode = "UPDATE_CHANNEL_INVALID"
+	ErrCodeUpdateCheckFailed       ErrorCode = "UPDATE_CHECK_FAILED"
+	ErrCodeUpdateAssetNotFound     ErrorCode = "UPDATE_ASSET_NOT_FOUND"
+	ErrCodeUpdateDownloadFailed    ErrorCode = "UPDATE_DOWNLOAD_FAILED"
+	ErrCodeLogDirInvalid           ErrorCode = "LOG_DIR_INVALID"
+	ErrCodeLogLevelInvalid         ErrorCode = "LOG_LEVEL_INVALID"
+	ErrCodeLogFilterInvalid        ErrorCode = "LOG_FILTER_INVALID"
+	ErrCodeWorkspaceNameRequired   ErrorCode = "WORKSPACE_NAME_REQUIRED"
+	ErrCodeWorkspaceNameTaken      ErrorCode = "WORKSPACE_NAME_TAKEN"
+	ErrCodeWorkspaceRootInvalid    ErrorCode = "WORKSPACE_ROOT_INVALID"
+	ErrCodeWorkspaceNotFound       ErrorCode = "WORKSPACE_NOT_FOUND"
+	ErrCodeSavedSearchNameRequired ErrorCode = "SAVED_SEARCH_NAME_REQUIRED"
+	ErrCodeUnsupportedLocale       ErrorCode = "UNSUPPORTED_LOCALE"
+	ErrCodeInvalidHotkey           ErrorCode = "INVALID_HOTKEY"
+	ErrCodeHotkeyUnavailable       ErrorCode = "HOTKEY_UNAVAILABLE"
+	ErrCodeShellIntegrationFailed  ErrorCode = "SHELL_INTEGRATION_FAILED"
+	ErrCodeNoResultsToOpen         ErrorCode = "NO_RESULTS_TO_OPEN"
+	ErrCodeNoRemoteLink            ErrorCode = "NO_REMOTE_LINK"
+	ErrCodeInvalidIgnoreRule       ErrorCode = "INVALID_IGNORE_RULE"
+	ErrCodeIgnoreFileFailed        ErrorCode = "IGNORE_FILE_FAILED"
+	ErrCodeSearchNotFound          ErrorCode = "SEARCH_NOT_FOUND"
+	ErrCodeConfirmationRequired    ErrorCode = "CONFIRMATION_REQUIRED"
+	ErrCodeSearchRootRemoved       ErrorCode = "SEARCH_ROOT_REMOVED"
+	ErrCodeResultsExportFailed     ErrorCode = "RESULTS_EXPORT_FAILED"
+	ErrCodeInvalidStoreFilter      ErrorCode = "INVALID_STORE_FILTER"
+	ErrCodeResultStoreFailed       ErrorCode = "RESULT_STORE_FAILED"
+	ErrCodeIndexFailed             ErrorCode = "INDEX_FAILED"
+	ErrCodeNoIndex                 ErrorCode = "NO_INDEX"
+	ErrCodeFuzzyNeedsLiteral       ErrorCode = "FUZZY_NEEDS_LITERAL"
+	ErrCodeFuzzyQueryTooLong       ErrorCode = "FUZZY_QUERY_TOO_LONG"
+	ErrCodeExpandNeedsLiteral      ErrorCode = "EXPAND_NEEDS_LITERAL"
+	ErrCodeTemplateNameRequired    ErrorCode = "TEMPLATE_NAME_REQUIRED"
+	ErrCodeTemplateQueryRequired   ErrorCode = "TEMPLATE_QUERY_REQUIRED"
+	ErrCodeTemplateNotFound        ErrorCode = "TEMPLATE_NOT_FOUND"
+	ErrCodeTemplateVariableMissing ErrorCode = "TEMPLATE_VARIABLE_MISSING"
+	ErrCodeExtractNeedsRegex       ErrorCode = "EXTRACT_NEEDS_REGEX"
+	ErrCodeNoCaptureGroups         ErrorCode = "NO_CAPTURE_GROUPS"
+	ErrCodeNoCaptures              ErrorCode = "NO_CAPTURES"
+	ErrCodeUnknownCaptureGroup     ErrorCode = "UNKNOWN_CAPTURE_GROUP"
+	ErrCodeNoQuickfix              ErrorCode = "NO_QUICKFIX"
+	ErrCodeQuickfixUnsupported     ErrorCode = "QUICKFIX_UNSUPPORTED"
+)
+
+// AppError is an error with a stable code and the arguments for its message
+// template. Error() renders the English message, so logs and Go callers see
+// the same text as before codes existed; the frontend receives the message
+// in the selected locale through formatError.
+type AppError struct {
+	Code    ErrorCode
+	Args    []interface{}
+	Details interface{} // Structured data for the frontend, e.g. QueryCostWarning; nil for most codes
+}
+
+// newAppError returns an AppError for code with the given message arguments.
+func newAppError(code ErrorCode, args ...interface{}) *AppError {
+	metricErrors.Add(string(code), 1)
+	return &AppError{Code: code, Args: args}
+}
+
+// Error renders the English message.
+func (e *AppError) Error() string {
+	return e.Message(defaultLocale)
+}
+
+// Message renders the message in the given locale, falling back to English
+// for codes the locale's catalog doesn't cover.
+func (e *AppError) Message(locale string) string {
+	template, ok := messageCatalog[locale][e.Code]
+	if !ok {
+		template, ok = messageCatalog[defaultLocale][e.Code]
+	}
+	if !ok {
+		return string(e.Code)
+	}
+	return fmt.Sprintf(template, e.Args...)
+}
+
+// Unwrap returns the first error among the message arguments, so errors.Is
+// and errors.As still see an underlying OS or regexp error.
+func (e *AppError) Unwrap() error {
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrorResponse is the shape of an error as the frontend receives it from a
+// rejected binding call.
+type ErrorResponse struct {
+	Code    ErrorCode   `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// formatError is installed as the Wails ErrorFormatter. It turns the errors
+// returned by bound methods into an ErrorResponse with the message in the
+// current locale. Errors without a code are passed through as UNKNOWN with
+// their original text.
+func (a *App) formatError(err error) any {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return ErrorResponse{Code: appErr.Code, Message: appErr.Message(a.GetLocale()), Details: appErr.Details}
+	}
+	return ErrorResponse{Code: ErrCodeUnknown, Message: err.Error()}
+}
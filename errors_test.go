@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+)
+
+// fmtVerb matches the fmt verbs used in the message templates.
+var fmtVerb = regexp.MustCompile(`%[-+# 0]*[a-zA-Z]`)
+
+// TestMessageCatalogComplete verifies that every locale translates every
+// English code and that each translation takes the same fmt verbs in the
+// same order, so a template can never render with %!(EXTRA ...) noise.
+func TestMessageCatalogComplete(t *testing.T) {
+	english := messageCatalog[defaultLocale]
+	for locale, catalog := range messageCatalog {
+		for code, en := range english {
+			translated, ok := catalog[code]
+			if !ok {
+				t.Errorf("locale %q is missing %s", locale, code)
+				continue
+			}
+			wantVerbs := fmtVerb.FindAllString(en, -1)
+			gotVerbs := fmtVerb.FindAllString(translated, -1)
+			if len(wantVerbs) != len(gotVerbs) {
+				t.Errorf("locale %q %s: verbs %v, want %v", locale, code, gotVerbs, wantVerbs)
+				continue
+			}
+			for i := range wantVerbs {
+				if wantVerbs[i] != gotVerbs[i] {
+					t.Errorf("locale %q %s: verbs %v, want %v", locale, code, gotVerbs, wantVerbs)
+					break
+				}
+			}
+		}
+	}
+}
+
+// TestAppErrorMessages verifies that Error() keeps the English text while
+// Message renders the requested locale, and that Unwrap exposes the cause.
+func TestAppErrorMessages(t *testing.T) {
+	err := newAppError(ErrCodeFileNotFound, "/tmp/x.go")
+	if got := err.Error(); got != "file does not exist: /tmp/x.go" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := err.Message("id"); got != "file tidak ditemukan: /tmp/x.go" {
+		t.Errorf("Message(id) = %q", got)
+	}
+	if got := err.Message("xx"); got != err.Error() {
+		t.Errorf("expected English fallback for unknown locale, got %q", got)
+	}
+
+	wrapped := newAppError(ErrCodeFileReadFailed, os.ErrPermission)
+	if !errors.Is(wrapped, os.ErrPermission) {
+		t.Error("expected errors.Is to see the underlying error")
+	}
+}
+
+// TestSetLocale verifies locale normalization and rejection of locales
+// without a catalog.
+func TestSetLocale(t *testing.T) {
+	app := NewApp()
+	if got := app.GetLocale(); got != defaultLocale {
+		t.Errorf("expected default locale %q, got %q", defaultLocale, got)
+	}
+
+	for _, tag := range []string{"id", "id-ID", "ID_id.UTF-8"} {
+		if err := app.SetLocale(tag); err != nil {
+			t.Errorf("SetLocale(%q) failed: %v", tag, err)
+		}
+		if got := app.GetLocale(); got != "id" {
+			t.Errorf("SetLocale(%q) selected %q, want id", tag, got)
+		}
+	}
+
+	err := app.SetLocale("tlh")
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeUnsupportedLocale {
+		t.Errorf("expected UNSUPPORTED_LOCALE, got %v", err)
+	}
+	if got := app.GetLocale(); got != "id" {
+		t.Errorf("rejected locale must not change the selection, got %q", got)
+	}
+
+	locales := app.GetSupportedLocales()
+	if len(locales) < 2 || locales[0] != "en" {
+		t.Errorf("unexpected supported locales: %v", locales)
+	}
+}
+
+// TestFormatError verifies the payload the frontend receives for coded and
+// uncoded errors, localized by the selected locale.
+func TestFormatError(t *testing.T) {
+	app := NewApp()
+
+	_, err := app.ReadFile("")
+	resp, ok := app.formatError(err).(ErrorResponse)
+	if !ok {
+		t.Fatalf("expected ErrorResponse, got %T", app.formatError(err))
+	}
+	if resp.Code != ErrCodePathRequired || resp.Message != "file path is required" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	app.SetLocale("id")
+	resp = app.formatError(err).(ErrorResponse)
+	if resp.Message != "path file wajib diisi" {
+		t.Errorf("expected Indonesian message, got %q", resp.Message)
+	}
+
+	resp = app.formatError(errors.New("boom")).(ErrorResponse)
+	if resp.Code != ErrCodeUnknown || resp.Message != "boom" {
+		t.Errorf("unexpected response for plain error: %+v", resp)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExpandContext returns result with beforeN lines of context before the
+// match and afterN after it, re-read from disk, so the UI can offer "show
+// 10 more lines" without every search capturing wide context up front.
+// The counts replace the captured context rather than add to it; they are
+// capped at 500 (maxSliceRadius) and fewer lines come back at either end
+// of the file. Like GetFileSlice, the file is streamed and reading stops
+// after the last requested line. If the matched line no longer reads as
+// result.Content the file changed since the search, and the call fails
+// with CONTEXT_FILE_CHANGED instead of showing context around the wrong
+// line. A files-without-match result (LineNum 0) has no line to expand and
+// comes back unchanged.
+func (a *App) ExpandContext(result SearchResult, beforeN int, afterN int) (SearchResult, error) {
+	cleanPath, err := a.validateReadPath(result.FilePath)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	if result.LineNum < 1 {
+		return result, nil
+	}
+	beforeN = min(max(beforeN, 0), maxSliceRadius)
+	afterN = min(max(afterN, 0), maxSliceRadius)
+	startLine := max(result.LineNum-beforeN, 1)
+	endLine := result.LineNum + afterN
+
+	file, err := os.Open(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to open file for context", err, logrus.Fields{"filePath": cleanPath})
+		return SearchResult{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	defer file.Close()
+
+	text, _ := newTextReader(file)
+	// Same line splitting and long-line allowance as the streaming search path.
+	scanner := newLineScanner(text, defaultScannerBufferSize)
+
+	before := make([]string, 0, result.LineNum-startLine)
+	after := make([]string, 0, afterN)
+	matched := false
+	lineNum := 0
+	for lineNum < endLine && scanner.Scan() {
+		lineNum++
+		switch {
+		case lineNum < startLine:
+		case lineNum < result.LineNum:
+			before = append(before, scanner.Text())
+		case lineNum == result.LineNum:
+			matched = strings.TrimSpace(scanner.Text()) == result.Content
+		default:
+			after = append(after, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		a.logError("Failed to read context", err, logrus.Fields{"filePath": cleanPath})
+		return SearchResult{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	if lineNum < result.LineNum {
+		return SearchResult{}, newAppError(ErrCodeLineOutOfRange, result.LineNum, cleanPath, lineNum)
+	}
+	if !matched {
+		return SearchResult{}, newAppError(ErrCodeContextFileChanged, cleanPath)
+	}
+
+	result.ContextBefore = before
+	result.ContextAfter = after
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestExpandContext verifies the widened context in the middle and at both
+// ends of a file, the cap on the line counts, files-without-match results
+// left alone, and a changed or shortened file refused.
+func TestExpandContext(t *testing.T) {
+	app := NewApp()
+	path := writeNumberedFile(t, 100)
+	result := SearchResult{FilePath: path, LineNum: 50, Content: "line 50", ContextBefore: []string{"line 48", "line 49"}}
+
+	got, err := app.ExpandContext(result, 3, 2)
+	if err != nil {
+		t.Fatalf("ExpandContext failed: %v", err)
+	}
+	if want := []string{"line 47", "line 48", "line 49"}; !reflect.DeepEqual(got.ContextBefore, want) {
+		t.Errorf("ContextBefore = %v, want %v", got.ContextBefore, want)
+	}
+	if want := []string{"line 51", "line 52"}; !reflect.DeepEqual(got.ContextAfter, want) {
+		t.Errorf("ContextAfter = %v, want %v", got.ContextAfter, want)
+	}
+	if got.LineNum != 50 || got.Content != "line 50" {
+		t.Errorf("match changed: %+v", got)
+	}
+
+	result.LineNum, result.Content = 2, "line 2"
+	if got, err = app.ExpandContext(result, 10, 0); err != nil || len(got.ContextBefore) != 1 || len(got.ContextAfter) != 0 {
+		t.Errorf("top of file: got %+v, %v", got, err)
+	}
+	result.LineNum, result.Content = 99, "line 99"
+	if got, err = app.ExpandContext(result, -1, 10000); err != nil || len(got.ContextBefore) != 0 || !reflect.DeepEqual(got.ContextAfter, []string{"line 100"}) {
+		t.Errorf("bottom of file: got %+v, %v", got, err)
+	}
+
+	result = SearchResult{FilePath: writeNumberedFile(t, 2000), LineNum: 1000, Content: "line 1000"}
+	if got, err = app.ExpandContext(result, 10000, 10000); err != nil || len(got.ContextBefore) != maxSliceRadius || len(got.ContextAfter) != maxSliceRadius {
+		t.Errorf("expected %d lines each side, got %d and %d (%v)", maxSliceRadius, len(got.ContextBefore), len(got.ContextAfter), err)
+	}
+
+	fileOnly := SearchResult{FilePath: path}
+	if got, err = app.ExpandContext(fileOnly, 5, 5); err != nil || !reflect.DeepEqual(got, fileOnly) {
+		t.Errorf("files-without-match result: got %+v, %v", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("changed\nline 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = app.ExpandContext(SearchResult{FilePath: path, LineNum: 1, Content: "line 1"}, 1, 1); err == nil || err.(*AppError).Code != ErrCodeContextFileChanged {
+		t.Errorf("expected %s, got %v", ErrCodeContextFileChanged, err)
+	}
+	if _, err = app.ExpandContext(SearchResult{FilePath: path, LineNum: 50, Content: "line 50"}, 1, 1); err == nil || err.(*AppError).Code != ErrCodeLineOutOfRange {
+		t.Errorf("expected %s, got %v", ErrCodeLineOutOfRange, err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// favoritesFileName is the data-directory file holding the favorite
+// directories, in the order they were added.
+const favoritesFileName = "favorites.json"
+
+// favoriteColor matches the colors a favorite may have.
+var favoriteColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+// normalizeFavoriteColor validates a color and expands it to lower-case
+// #rrggbb. An empty color stays empty.
+func normalizeFavoriteColor(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	if color == "" {
+		return "", nil
+	}
+	if !favoriteColor.MatchString(color) {
+		return "", newAppError(ErrCodeFavoriteColorInvalid, color)
+	}
+	color = strings.ToLower(color)
+	if len(color) == 4 {
+		color = string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	return color, nil
+}
+
+// loadFavorites reads the favorites. Callers must hold favoritesMu.
+func (a *App) loadFavorites() ([]Favorite, error) {
+	var favorites []Favorite
+	if _, err := a.loadJSON(favoritesFileName, &favorites); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// AddFavorite pins a directory, which must exist, with a label and color.
+// The label defaults to the directory's name; the color is #rgb or
+// #rrggbb, or empty for the default. Adding a directory that is already a
+// favorite updates its label and color in place.
+func (a *App) AddFavorite(path, label, color string) (Favorite, error) {
+	dir, err := resolveDirectory(strings.TrimSpace(path))
+	if err != nil {
+		return Favorite{}, err
+	}
+	color, err = normalizeFavoriteColor(color)
+	if err != nil {
+		return Favorite{}, err
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = filepath.Base(dir)
+	}
+
+	a.favoritesMu.Lock()
+	defer a.favoritesMu.Unlock()
+
+	favorites, err := a.loadFavorites()
+	if err != nil {
+		return Favorite{}, err
+	}
+	fav := Favorite{Path: dir, Label: label, Color: color, AddedAt: time.Now().UnixMilli()}
+	i := findFavorite(favorites, dir)
+	if i >= 0 {
+		fav.AddedAt = favorites[i].AddedAt
+		favorites[i] = fav
+	} else {
+		favorites = append(favorites, fav)
+	}
+	if err := a.saveJSON(favoritesFileName, favorites); err != nil {
+		return Favorite{}, err
+	}
+	a.logInfo("Favorite saved", logrus.Fields{"path": dir, "label": label})
+	return fav, nil
+}
+
+// ListFavorites returns the favorites in the order they were added, with
+// Missing set on those whose directory no longer exists, so the frontend
+// can flag them rather than fail a search.
+func (a *App) ListFavorites() ([]Favorite, error) {
+	a.favoritesMu.Lock()
+	favorites, err := a.loadFavorites()
+	a.favoritesMu.Unlock()
+	if err != nil {
+		a.logError("Failed to load favorites", err, nil)
+		return nil, err
+	}
+	if favorites == nil {
+		return []Favorite{}, nil
+	}
+	for i := range favorites {
+		info, err := os.Stat(favorites[i].Path)
+		favorites[i].Missing = err != nil || !info.IsDir()
+	}
+	return favorites, nil
+}
+
+// RemoveFavorite unpins a directory. It fails with FAVORITE_NOT_FOUND when
+// the directory isn't a favorite.
+func (a *App) RemoveFavorite(path string) error {
+	dir := filepath.Clean(strings.TrimSpace(path))
+
+	a.favoritesMu.Lock()
+	defer a.favoritesMu.Unlock()
+
+	favorites, err := a.loadFavorites()
+	if err != nil {
+		return err
+	}
+	i := findFavorite(favorites, dir)
+	if i < 0 {
+		return newAppError(ErrCodeFavoriteNotFound, dir)
+	}
+	favorites = append(favorites[:i], favorites[i+1:]...)
+	return a.saveJSON(favoritesFileName, favorites)
+}
+
+// findFavorite returns the index of the favorite for dir, or -1.
+func findFavorite(favorites []Favorite, dir string) int {
+	for i := range favorites {
+		if favorites[i].Path == dir {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFavorites verifies adding, updating, listing, and removing favorites,
+// that they persist, and that a removed directory is flagged on listing.
+func TestFavorites(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	api, web := filepath.Join(root, "api"), filepath.Join(root, "web")
+	os.Mkdir(api, 0o755)
+	os.Mkdir(web, 0o755)
+
+	fav, err := app.AddFavorite(api+string(filepath.Separator), "", "#0AF")
+	if err != nil || fav.Path != api || fav.Label != "api" || fav.Color != "#00aaff" || fav.AddedAt == 0 {
+		t.Fatalf("AddFavorite = %+v, %v", fav, err)
+	}
+	if _, err := app.AddFavorite(web, " Web app ", ""); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := app.AddFavorite(api, "Backend", "#112233")
+	if err != nil || updated.Label != "Backend" || updated.AddedAt != fav.AddedAt {
+		t.Fatalf("AddFavorite update = %+v, %v", updated, err)
+	}
+
+	if _, err := app.AddFavorite(web, "", "red"); err == nil || err.(*AppError).Code != ErrCodeFavoriteColorInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeFavoriteColorInvalid, err)
+	}
+	if _, err := app.AddFavorite(filepath.Join(root, "nope"), "", ""); err == nil || err.(*AppError).Code != ErrCodeDirectoryNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeDirectoryNotFound, err)
+	}
+
+	os.Remove(web)
+	reloaded := NewApp()
+	reloaded.dataDir = app.dataDir
+	favorites, err := reloaded.ListFavorites()
+	if err != nil || len(favorites) != 2 {
+		t.Fatalf("ListFavorites = %+v, %v", favorites, err)
+	}
+	if favorites[0].Label != "Backend" || favorites[0].Missing || favorites[1].Label != "Web app" || !favorites[1].Missing {
+		t.Errorf("unexpected favorites %+v", favorites)
+	}
+
+	if err := reloaded.RemoveFavorite(web); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.RemoveFavorite(web); err == nil || err.(*AppError).Code != ErrCodeFavoriteNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeFavoriteNotFound, err)
+	}
+	if favorites, _ := app.ListFavorites(); len(favorites) != 1 || favorites[0].Path != api {
+		t.Errorf("expected only %s to remain, got %+v", api, favorites)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -18,11 +19,24 @@ import (
 // the caller can log a single summary line without passing the App's logger
 // deep into the walk.
 type collectStats struct {
-	filesCollected int
-	filesSkipped   int
-	dirsSkipped    int
+	filesCollected   int
+	filesSkipped     int
+	dirsSkipped      int
+	dirsVisited      int // Directories walked into, the root included
+	generatedSkipped int // Subset of filesSkipped dropped by the SkipGenerated name heuristic
+	dirsTruncated    int // Directories that hit the MaxFilesPerDir limit
 }
 
+// defaultMaxFilesPerDir is the per-directory file cap applied when the
+// request leaves MaxFilesPerDir at zero. Real source directories never get
+// close; build-output and cache directories with millions of tiny files do,
+// and without the cap they dominate collection time.
+const defaultMaxFilesPerDir = 100000
+
+// defaultMaxFileSize is the file size cap applied when the request leaves
+// MaxFileSize at zero.
+const defaultMaxFileSize = 10 * 1024 * 1024
+
 // walkDirectoryTree walks the directory tree and returns two slices:
 //
 //   - textCandidates: files that passed all cheap filters (extension, size,
@@ -48,6 +62,18 @@ type collectStats struct {
 //	On a multi-core machine this turns N sequential open+read+close
 //	operations into N/numWorkers parallel ones.
 func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates []fileMeta, binaryCheckCandidates []fileMeta, stats collectStats, err error) {
+	return a.walkDirectoryTreeReporting(context.Background(), "", req, debug, nil)
+}
+
+// walkDirectoryTreeReporting is walkDirectoryTree that also hands the
+// filesystem problems it meets to report: unreadable entries, broken
+// symlinks, empty files, and files without read permission (see
+// fsissues.go). The last three need an extra stat or open per file, so
+// they are only checked when report is non-nil; searches pass nil.
+// Cancelling ctx stops the walk at the next entry with ctx's error. The
+// walk of a search, named by searchID, emits collection-progress events;
+// other walks pass "" and emit none.
+func (a *App) walkDirectoryTreeReporting(ctx context.Context, searchID string, req SearchRequest, debug bool, report func(FilesystemIssue)) (textCandidates []fileMeta, binaryCheckCandidates []fileMeta, stats collectStats, err error) {
 	// Compute the absolute base directory and the current working directory
 	// ONCE, before the walk starts. The previous implementation called
 	// filepath.Abs(path) on EVERY file inside the WalkDir callback, which
@@ -86,7 +112,51 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 	// separator-terminated base is equivalent and allocation-free.
 	prefixCheck := absBaseDir + string(filepath.Separator)
 
-	err = filepath.WalkDir(req.Directory, func(path string, d fs.DirEntry, walkErr error) error {
+	// A root beyond MAX_PATH on Windows can only be walked through its
+	// \\?\ extended-length form. Entries come back with the prefix, so the
+	// callback strips it again before any filter or traversal check sees
+	// the path. On other platforms toLongPath is a no-op.
+	walkRoot := req.Directory
+	if longRoot := toLongPath(absBaseDir); longRoot != absBaseDir {
+		walkRoot = longRoot
+	}
+	rootPath := fromLongPath(walkRoot)
+
+	// Rules from the nearest .codesearchignore (see ignorefile.go). nil when
+	// there is none, which turns every ignore check below into a no-op.
+	// The rules match paths relative to the search directory, which walk
+	// paths are rooted at.
+	ignore := loadIgnoreRules(absBaseDir)
+	relToRoot := func(path string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(path, rootPath), string(filepath.Separator))
+	}
+
+	// Working trees of the enclosing repository's submodules (see
+	// submodules.go), skipped unless the request includes them.
+	var submodules map[string]bool
+	if !req.IncludeSubmodules {
+		submodules = loadSubmoduleDirs(absBaseDir)
+	}
+
+	// filesPerDir counts the file entries seen in each directory so the
+	// MaxFilesPerDir safety valve can truncate runaway directories. Only
+	// allocated when the limit is active.
+	var filesPerDir map[string]int
+	if req.MaxFilesPerDir > 0 {
+		filesPerDir = make(map[string]int)
+	}
+
+	beat := a.newCollectionHeartbeat(searchID, absBaseDir)
+	err = filepath.WalkDir(walkRoot, func(path string, d fs.DirEntry, walkErr error) error {
+		// A cancelled search stops here instead of walking the rest of the
+		// tree, which on an accidental search of a home directory could
+		// take minutes.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		path = fromLongPath(path)
+		// Liveness for long walks, reported at most every collectionProgressInterval
+		beat.tick(path, stats.dirsVisited, len(textCandidates)+len(binaryCheckCandidates))
 		if walkErr != nil {
 			if debug {
 				a.logDebug("Skipping file/directory due to access error", logrus.Fields{
@@ -94,13 +164,17 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 					"error": walkErr.Error(),
 				})
 			}
+			if report != nil {
+				report(FilesystemIssue{Path: path, Kind: issueUnreadable, Detail: walkErr.Error()})
+			}
 			return nil
 		}
 
 		// --- Directory handling (before the per-file optimization) ---
 		if d.IsDir() {
-			// Skip hidden directories that start with a dot (e.g., .git, .vscode)
-			if strings.HasPrefix(d.Name(), ".") {
+			// Skip hidden directories that start with a dot (e.g., .git, .vscode),
+			// except those holding CI configs when well-known files are included
+			if strings.HasPrefix(d.Name(), ".") && !(req.IncludeWellKnownFiles && wellKnownDirs[d.Name()]) {
 				if debug {
 					a.logDebug("Skipping hidden directory", logrus.Fields{
 						"directory": path,
@@ -109,14 +183,51 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 				stats.dirsSkipped++
 				return filepath.SkipDir
 			}
+			if path != rootPath && ignore.matches(relToRoot(path)) {
+				if debug {
+					a.logDebug("Skipping directory due to ignore file", logrus.Fields{
+						"directory": path,
+					})
+				}
+				stats.dirsSkipped++
+				return filepath.SkipDir
+			}
+			if submodules != nil && path != rootPath && submodules[filepath.Join(absBaseDir, relToRoot(path))] {
+				if debug {
+					a.logDebug("Skipping git submodule", logrus.Fields{
+						"directory": path,
+					})
+				}
+				stats.dirsSkipped++
+				return filepath.SkipDir
+			}
 			// If SearchSubdirs is false, skip all subdirectories beyond the root
-			if !req.SearchSubdirs && path != req.Directory {
+			if !req.SearchSubdirs && path != rootPath {
 				stats.dirsSkipped++
 				return filepath.SkipDir
 			}
+			stats.dirsVisited++
 			return nil
 		}
 
+		// --- Per-directory file cap ---
+		// Counted before any other filter so the cap bounds the work done
+		// per directory, not just the number of files that survive. The
+		// overflow files are skipped individually (instead of SkipDir) so
+		// subdirectories that sort after them are still walked.
+		if filesPerDir != nil {
+			dir := filepath.Dir(path)
+			filesPerDir[dir]++
+			if count := filesPerDir[dir]; count > req.MaxFilesPerDir {
+				if count == req.MaxFilesPerDir+1 {
+					stats.dirsTruncated++
+					a.warnDirectoryTruncated(dir, req.MaxFilesPerDir)
+				}
+				stats.filesSkipped++
+				return nil
+			}
+		}
+
 		// --- Opt 1: Compute absPath without per-file filepath.Abs ---
 		// filepath.Abs(path) does an os.Getwd() syscall (cached after the
 		// first call, but still string work per call). Since we already
@@ -153,8 +264,22 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 			return nil
 		}
 
+		// --- Well-known build and CI files ---
+		// Dockerfiles, Makefiles, and CI configs pass the extension filters
+		// below, and are the only files searched in the hidden directories
+		// walked for them.
+		wellKnown := false
+		if req.IncludeWellKnownFiles {
+			rel := filepath.ToSlash(relToRoot(path))
+			wellKnown = isWellKnownFile(rel)
+			if !wellKnown && inHiddenDir(rel) {
+				stats.filesSkipped++
+				return nil
+			}
+		}
+
 		// --- File extension filter ---
-		if req.Extension != "" {
+		if req.Extension != "" && !wellKnown {
 			if !matchExtension(path, req.Extension) {
 				if debug {
 					a.logDebug("Skipping file due to extension filter", logrus.Fields{
@@ -168,7 +293,7 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 		}
 
 		// --- File type allow-list ---
-		if len(req.AllowedFileTypes) > 0 {
+		if len(req.AllowedFileTypes) > 0 && !wellKnown {
 			isAllowed := false
 			for _, allowedExt := range req.AllowedFileTypes {
 				if matchExtension(path, allowedExt) {
@@ -197,8 +322,14 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 					"error": err.Error(),
 				})
 			}
+			if report != nil {
+				report(FilesystemIssue{Path: absPath, Kind: issueUnreadable, Detail: err.Error()})
+			}
 			return nil // Skip if we can't get file info
 		}
+		if report != nil {
+			checkFileIssues(absPath, fileInfo, report)
+		}
 
 		if fileInfo.Size() > req.MaxFileSize {
 			if debug {
@@ -224,6 +355,30 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 			return nil
 		}
 
+		// --- Modified-time filters ---
+		if !modifiedWanted(req, fileInfo.ModTime().UnixMilli()) {
+			if debug {
+				a.logDebug("Skipping file due to modified-time filter", logrus.Fields{
+					"path":     path,
+					"modified": fileInfo.ModTime(),
+				})
+			}
+			stats.filesSkipped++
+			return nil
+		}
+
+		// --- Owner and permission filters ---
+		if !ownerWanted(req, fileInfo) {
+			if debug {
+				a.logDebug("Skipping file due to owner or permission filter", logrus.Fields{
+					"path": path,
+					"mode": fileInfo.Mode(),
+				})
+			}
+			stats.filesSkipped++
+			return nil
+		}
+
 		// --- Exclude patterns ---
 		for _, patternStr := range req.ExcludePatterns {
 			if patternStr != "" && a.matchesPattern(path, patternStr) {
@@ -238,6 +393,42 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 			}
 		}
 
+		// --- Path filters (path: and -path: query operators) ---
+		if (len(req.IncludePaths) > 0 || len(req.ExcludePaths) > 0) && !pathFiltersMatch(req, relToRoot(path)) {
+			if debug {
+				a.logDebug("Skipping file due to path filter", logrus.Fields{
+					"path": path,
+				})
+			}
+			stats.filesSkipped++
+			return nil
+		}
+
+		// --- .codesearchignore rules ---
+		if ignore.matches(relToRoot(path)) {
+			if debug {
+				a.logDebug("Skipping file due to ignore file", logrus.Fields{
+					"path": path,
+				})
+			}
+			stats.filesSkipped++
+			return nil
+		}
+
+		// --- Generated/minified files (opt-in) ---
+		// Only the name-based heuristic runs here; the content markers are
+		// checked by the search workers, which read the file anyway.
+		if req.SkipGenerated && isGeneratedFileName(path) {
+			if debug {
+				a.logDebug("Skipping generated file", logrus.Fields{
+					"path": path,
+				})
+			}
+			stats.filesSkipped++
+			stats.generatedSkipped++
+			return nil
+		}
+
 		// --- Opt 3: Skip binary probe for known-text extensions ---
 		// If the file has a known-text extension (.go, .ts, .py, .md, etc.),
 		// it is NEVER binary, so we skip the open+read+close syscall
@@ -274,6 +465,31 @@ func (a *App) walkDirectoryTree(req SearchRequest, debug bool) (textCandidates [
 	return textCandidates, binaryCheckCandidates, stats, err
 }
 
+// modifiedWanted applies ModifiedAfter and ModifiedBefore to a file's
+// modification time in Unix milliseconds. The range includes its start
+// and excludes its end, so consecutive ranges don't overlap.
+func modifiedWanted(req SearchRequest, modified int64) bool {
+	if req.ModifiedAfter > 0 && modified < req.ModifiedAfter {
+		return false
+	}
+	return req.ModifiedBefore <= 0 || modified < req.ModifiedBefore
+}
+
+// warnDirectoryTruncated logs and emits a "directory-truncated" event the
+// first time a directory exceeds the MaxFilesPerDir limit, so the UI can tell
+// the user that part of the tree was not searched.
+func (a *App) warnDirectoryTruncated(dir string, limit int) {
+	a.logWarn("Directory truncated by file limit", logrus.Fields{
+		"directory": dir,
+		"limit":     limit,
+	})
+	a.safeEmitEvent("directory-truncated", map[string]interface{}{
+		"directory": dir,
+		"limit":     limit,
+		"message":   fmt.Sprintf("Only the first %d files in %s were searched", limit, dir),
+	})
+}
+
 // probeBinaryInParallel runs the 512-byte binary detection probe on each
 // candidate file in parallel using a worker pool sized to the CPU count.
 // Files that pass (are text) are appended to textCandidates; files that
@@ -338,7 +554,7 @@ func (a *App) probeBinaryInParallel(ctx context.Context, candidates []fileMeta,
 					if !ok {
 						return
 					}
-					isText := probeIsText(meta.absPath, buffer, debug, a)
+					isText := probeIsText(meta.absPath, meta.size, buffer, debug, a)
 					select {
 					case resultChan <- probeResult{meta: meta, isText: isText}:
 					case <-ctx.Done():
@@ -380,12 +596,14 @@ func (a *App) probeBinaryInParallel(ctx context.Context, candidates []fileMeta,
 	return textFiles, skipped
 }
 
-// probeIsText opens the file, reads the first 512 bytes, and reports
-// whether the content appears to be text. The buffer is borrowed from the
-// caller (the per-worker buffer) to avoid allocation. If the file can't be
-// opened or read, it's treated as non-text (skipped) — the safe default.
-func probeIsText(path string, buffer []byte, debug bool, a *App) bool {
-	file, err := os.Open(path)
+// probeIsText opens the file, reads the windows binary detection samples
+// (the first 512 bytes, plus the middle and end of large files), and
+// reports whether the content appears to be text. The buffer is borrowed
+// from the caller (the per-worker buffer) to avoid allocation. If the file
+// can't be opened or read, it's treated as non-text (skipped) — the safe
+// default.
+func probeIsText(path string, size int64, buffer []byte, debug bool, a *App) bool {
+	file, err := os.Open(toLongPath(path))
 	if err != nil {
 		if debug {
 			a.logDebug("Skipping file due to read error for binary check", logrus.Fields{
@@ -395,9 +613,9 @@ func probeIsText(path string, buffer []byte, debug bool, a *App) bool {
 		}
 		return false
 	}
-	n, _ := file.Read(buffer)
+	binary, err := a.fileIsBinary(file, size, buffer)
 	file.Close()
-	if n > 0 && a.isBinary(buffer[:n]) {
+	if err == nil && binary {
 		if debug {
 			a.logDebug("Skipping binary file", logrus.Fields{
 				"path": path,
@@ -427,9 +645,30 @@ func probeIsText(path string, buffer []byte, debug bool, a *App) bool {
 // and the walk is the only cost. On a mixed tree with unknown extensions,
 // Phase 2 parallelizes the binary probes across CPU cores.
 func (a *App) collectFilesToProcess(req SearchRequest, pattern *regexp.Regexp, baseDir string) ([]fileMeta, error) {
+	return a.collectFilesToProcessContext(context.Background(), req, pattern, baseDir)
+}
+
+// collectFilesToProcessContext is collectFilesToProcess for a search that
+// can be cancelled: cancelling ctx stops the walk and the binary probe, and
+// the collection fails with ctx's error.
+func (a *App) collectFilesToProcessContext(ctx context.Context, req SearchRequest, pattern *regexp.Regexp, baseDir string) ([]fileMeta, error) {
+	return a.collectSearchFiles(ctx, "", req, pattern, baseDir)
+}
+
+// collectSearchFiles is collectFilesToProcessContext for the search
+// searchID, whose walk reports collection-progress events (see
+// walkDirectoryTreeReporting).
+func (a *App) collectSearchFiles(ctx context.Context, searchID string, req SearchRequest, pattern *regexp.Regexp, baseDir string) ([]fileMeta, error) {
 	debug := a.logger != nil && a.logger.IsLevelEnabled(logrus.DebugLevel)
 
-	textCandidates, binaryCandidates, stats, err := a.walkDirectoryTree(req, debug)
+	textCandidates, binaryCandidates, stats, err := a.walkDirectoryTreeReporting(ctx, searchID, req, debug, nil)
+	if ctx.Err() != nil {
+		a.logInfo("File collection cancelled", logrus.Fields{
+			"directory":   req.Directory,
+			"dirsVisited": stats.dirsVisited,
+		})
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		a.logError("Error during file walk", err, logrus.Fields{
 			"directory": req.Directory,
@@ -437,16 +676,48 @@ func (a *App) collectFilesToProcess(req SearchRequest, pattern *regexp.Regexp, b
 		return nil, err
 	}
 
+	// Files an enabled plugin handles skip the probe: the plugin turns
+	// them into text, so a binary source such as a .class file is
+	// searched through it.
+	if len(binaryCandidates) > 0 {
+		probe := binaryCandidates[:0]
+		for _, meta := range binaryCandidates {
+			if a.pluginFor(meta.absPath) != nil {
+				textCandidates = append(textCandidates, meta)
+			} else {
+				probe = append(probe, meta)
+			}
+		}
+		binaryCandidates = probe
+	}
+
 	// Run the binary probe in parallel on the unknown-extension files.
-	// Use a background context so the probe completes even if the search
-	// is cancelled mid-collection (the results are cheap and the cancel
-	// will be checked by the search workers anyway).
+	// A cancelled search abandons the remaining probes.
+	//
+	// In slow-FS mode the probe is skipped: it would open every
+	// unknown-extension file twice (probe, then search), which is the most
+	// expensive thing to do on a network mount. The files are passed on
+	// with checkBinary set and the workers run isBinary on the content they
+	// read anyway.
 	var binarySkipped int
 	var probedText []fileMeta
-	if len(binaryCandidates) > 0 {
-		probedText, binarySkipped = a.probeBinaryInParallel(context.Background(), binaryCandidates, debug)
+	if len(binaryCandidates) > 0 && req.SlowFS {
+		probedText = make([]fileMeta, len(binaryCandidates))
+		for i, meta := range binaryCandidates {
+			meta.checkBinary = true
+			probedText[i] = meta
+		}
+	} else if len(binaryCandidates) > 0 {
+		probedText, binarySkipped = a.probeBinaryInParallel(ctx, binaryCandidates, debug)
 		stats.filesSkipped += binarySkipped
 	}
+	if ctx.Err() != nil {
+		a.logInfo("File collection cancelled", logrus.Fields{
+			"directory":   req.Directory,
+			"dirsVisited": stats.dirsVisited,
+		})
+		return nil, ctx.Err()
+	}
 
 	// Merge: known-text candidates + probed-text files.
 	allFiles := make([]fileMeta, 0, len(textCandidates)+len(probedText))
@@ -458,6 +729,9 @@ func (a *App) collectFilesToProcess(req SearchRequest, pattern *regexp.Regexp, b
 		"filesProcessed":      stats.filesCollected,
 		"filesSkipped":        stats.filesSkipped,
 		"dirsSkipped":         stats.dirsSkipped,
+		"dirsVisited":         stats.dirsVisited,
+		"generatedSkipped":    stats.generatedSkipped,
+		"dirsTruncated":       stats.dirsTruncated,
 		"binaryProbesRun":     len(binaryCandidates),
 		"binaryFilesSkipped":  binarySkipped,
 		"textExtShortlisted":  len(textCandidates),
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestIsKnownTextExtension verifies that common source-code extensions are
@@ -532,3 +535,176 @@ func TestCollectFilesToProcessParallelProbeScaling(t *testing.T) {
 		t.Errorf("expected %d files, got %d — some files were lost in the parallel probe", numFiles, len(files))
 	}
 }
+
+// TestWalkDirectoryTreeMaxFilesPerDir verifies the per-directory safety
+// valve: once a directory exceeds MaxFilesPerDir the remaining files in it
+// are skipped and the directory is counted as truncated, while files in its
+// subdirectories are still collected.
+func TestWalkDirectoryTreeMaxFilesPerDir(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	for i := 0; i < 5; i++ {
+		name := filepath.Join(tempDir, "file"+string(rune('a'+i))+".txt")
+		if err := os.WriteFile(name, []byte("content\n"), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+	subDir := filepath.Join(tempDir, "zz_sub")
+	if err := os.Mkdir(subDir, 0o755); err != nil {
+		t.Fatalf("creating subdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subDir, "nested.txt"), []byte("content\n"), 0o644); err != nil {
+		t.Fatalf("creating nested file: %v", err)
+	}
+
+	req := SearchRequest{
+		Directory:      tempDir,
+		Query:          "content",
+		SearchSubdirs:  true,
+		MaxFileSize:    10 * 1024 * 1024,
+		MaxResults:     1000,
+		MaxFilesPerDir: 3,
+	}
+	textCandidates, _, stats, err := app.walkDirectoryTree(req, false)
+	if err != nil {
+		t.Fatalf("walkDirectoryTree failed: %v", err)
+	}
+
+	if len(textCandidates) != 4 {
+		t.Errorf("expected 3 root files + 1 nested file, got %d candidates", len(textCandidates))
+	}
+	if stats.dirsTruncated != 1 {
+		t.Errorf("expected 1 truncated directory, got %d", stats.dirsTruncated)
+	}
+
+	foundNested := false
+	for _, meta := range textCandidates {
+		if filepath.Base(meta.absPath) == "nested.txt" {
+			foundNested = true
+		}
+	}
+	if !foundNested {
+		t.Error("expected files in subdirectories to be collected after the parent was truncated")
+	}
+}
+
+// TestValidateAndSetDefaultsMaxFilesPerDir verifies the default cap is
+// applied when the field is zero and that a negative value (unlimited) is
+// left untouched.
+func TestValidateAndSetDefaultsMaxFilesPerDir(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	req, err := app.validateAndSetDefaults(SearchRequest{Directory: tempDir, Query: "x"})
+	if err != nil {
+		t.Fatalf("validateAndSetDefaults failed: %v", err)
+	}
+	if req.MaxFilesPerDir != defaultMaxFilesPerDir {
+		t.Errorf("expected default MaxFilesPerDir %d, got %d", defaultMaxFilesPerDir, req.MaxFilesPerDir)
+	}
+
+	req, err = app.validateAndSetDefaults(SearchRequest{Directory: tempDir, Query: "x", MaxFilesPerDir: -1})
+	if err != nil {
+		t.Fatalf("validateAndSetDefaults failed: %v", err)
+	}
+	if req.MaxFilesPerDir != -1 {
+		t.Errorf("expected negative MaxFilesPerDir to be preserved, got %d", req.MaxFilesPerDir)
+	}
+}
+
+// TestWalkDirectoryTreeModifiedTime verifies that ModifiedAfter and
+// ModifiedBefore select files by modification time, with the range
+// including its start and excluding its end, and that an empty range is
+// rejected.
+func TestWalkDirectoryTreeModifiedTime(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	base := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	for name, age := range map[string]time.Duration{"old.txt": 72 * time.Hour, "friday.txt": 0, "new.txt": -time.Hour} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("flag\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mod := base.Add(-age)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(after, before time.Time) string {
+		t.Helper()
+		req := SearchRequest{Directory: tempDir, Query: "flag"}
+		if !after.IsZero() {
+			req.ModifiedAfter = after.UnixMilli()
+		}
+		if !before.IsZero() {
+			req.ModifiedBefore = before.UnixMilli()
+		}
+		req, err := app.validateAndSetDefaults(req)
+		if err != nil {
+			t.Fatalf("validateAndSetDefaults failed: %v", err)
+		}
+		files, err := app.collectFilesToProcess(req, nil, filepath.Clean(tempDir)+string(filepath.Separator))
+		if err != nil {
+			t.Fatalf("collectFilesToProcess failed: %v", err)
+		}
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(f.absPath))
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	if got := collect(base, time.Time{}); got != "friday.txt,new.txt" {
+		t.Errorf("modified since Friday: got %s", got)
+	}
+	if got := collect(time.Time{}, base); got != "old.txt" {
+		t.Errorf("modified before Friday: got %s", got)
+	}
+	if got := collect(base.Add(-time.Hour), base.Add(time.Minute)); got != "friday.txt" {
+		t.Errorf("modified around Friday noon: got %s", got)
+	}
+
+	_, err := app.validateAndSetDefaults(SearchRequest{Directory: tempDir, Query: "flag", ModifiedAfter: base.UnixMilli(), ModifiedBefore: base.UnixMilli()})
+	if err == nil || err.(*AppError).Code != ErrCodeModifiedRangeEmpty {
+		t.Errorf("expected %s, got %v", ErrCodeModifiedRangeEmpty, err)
+	}
+}
+
+// TestCollectFilesToProcessStopsWhenCancelled verifies that a cancelled
+// search stops the directory walk instead of listing the whole tree.
+func TestCollectFilesToProcessStopsWhenCancelled(t *testing.T) {
+	tempDir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		dir := filepath.Join(tempDir, "dir"+string(rune('a'+i)))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	req, err := app.validateAndSetDefaults(SearchRequest{Directory: tempDir, Query: "package", SearchSubdirs: true})
+	if err != nil {
+		t.Fatalf("validateAndSetDefaults failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	text, binary, stats, err := app.walkDirectoryTreeReporting(ctx, "", req, false, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the walk to fail with context.Canceled, got %v", err)
+	}
+	if len(text)+len(binary) != 0 || stats.dirsVisited != 0 {
+		t.Errorf("expected nothing walked, got %d files and %d dirs", len(text)+len(binary), stats.dirsVisited)
+	}
+
+	files, err := app.collectFilesToProcessContext(ctx, req, nil, filepath.Clean(tempDir)+string(filepath.Separator))
+	if !errors.Is(err, context.Canceled) || files != nil {
+		t.Errorf("expected no files and context.Canceled, got %d files and %v", len(files), err)
+	}
+}
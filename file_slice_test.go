@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeNumberedFile creates a file with lines "line 1" .. "line n".
+func writeNumberedFile(t *testing.T, n int) string {
+	t.Helper()
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	path := filepath.Join(t.TempDir(), "numbered.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+	return path
+}
+
+// TestGetFileSlice verifies the window bounds, the match index, and the
+// end-of-file flag for slices in the middle, at the top, and at the bottom
+// of a file.
+func TestGetFileSlice(t *testing.T) {
+	app := NewApp()
+	path := writeNumberedFile(t, 100)
+
+	cases := []struct {
+		name      string
+		center    int
+		radius    int
+		wantStart int
+		wantLen   int
+		wantMatch int
+		wantAtEOF bool
+	}{
+		{"Middle", 50, 5, 45, 11, 5, false},
+		{"ClampedAtTop", 2, 5, 1, 7, 1, false},
+		{"ClampedAtBottom", 98, 5, 93, 8, 5, true},
+		{"LastLineExact", 95, 5, 90, 11, 5, true},
+		{"DefaultRadius", 50, 0, 50 - defaultSliceRadius, 2*defaultSliceRadius + 1, defaultSliceRadius, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			slice, err := app.GetFileSlice(path, tc.center, tc.radius)
+			if err != nil {
+				t.Fatalf("GetFileSlice failed: %v", err)
+			}
+			if slice.StartLine != tc.wantStart {
+				t.Errorf("StartLine = %d, want %d", slice.StartLine, tc.wantStart)
+			}
+			if len(slice.Lines) != tc.wantLen {
+				t.Errorf("len(Lines) = %d, want %d", len(slice.Lines), tc.wantLen)
+			}
+			if slice.MatchIndex != tc.wantMatch {
+				t.Errorf("MatchIndex = %d, want %d", slice.MatchIndex, tc.wantMatch)
+			}
+			if slice.EndOfFile != tc.wantAtEOF {
+				t.Errorf("EndOfFile = %v, want %v", slice.EndOfFile, tc.wantAtEOF)
+			}
+			if got, want := slice.Lines[slice.MatchIndex], fmt.Sprintf("line %d", tc.center); got != want {
+				t.Errorf("Lines[MatchIndex] = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestGetFileSliceErrors verifies that the slice binding applies the same
+// path validation as ReadFile and rejects a center line past the end.
+func TestGetFileSliceErrors(t *testing.T) {
+	app := NewApp()
+	path := writeNumberedFile(t, 10)
+
+	if _, err := app.GetFileSlice("", 1, 5); err == nil {
+		t.Error("expected error for empty path")
+	}
+	if _, err := app.GetFileSlice(filepath.Dir(path)+"/../x/numbered.txt", 1, 5); err == nil {
+		t.Error("expected error for traversal path")
+	}
+	if _, err := app.GetFileSlice(path+".missing", 1, 5); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := app.GetFileSlice(path, 11, 5); err == nil {
+		t.Error("expected error for center line past the end of the file")
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxReadFileSize is the largest file ReadFile returns, and so the largest
+// the preview modal shows as text.
+const maxReadFileSize int64 = 50 * 1024 * 1024
+
+// Preview views GetFileInfo recommends for a file.
+const (
+	previewText     = "text"
+	previewHex      = "hex"
+	previewTooLarge = "too-large"
+)
+
+// lineCountSampleSize is how much of a file GetFileInfo reads to count its
+// lines. Smaller files are counted exactly; for larger ones the count is
+// extrapolated from the sample.
+const lineCountSampleSize = 1024 * 1024
+
+// hexPreviewBytes is how much of a binary file FileInfo.HexPreview dumps.
+const hexPreviewBytes = 4096
+
+// detectMimeType sniffs the MIME type of a file from its first bytes
+// (http.DetectContentType). Plain text and unrecognized content take the
+// type of the extension instead when the system knows one, so a .svg or
+// .json file is reported as such rather than as text/plain.
+func detectMimeType(path string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	if strings.HasPrefix(sniffed, "text/plain") || sniffed == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(path)); byExt != "" {
+			return byExt
+		}
+	}
+	return sniffed
+}
+
+// countLines returns the number of lines in text: its line breaks, plus
+// one for a last line without a break.
+func countLines(text []byte) int {
+	lines := bytes.Count(text, []byte("\n"))
+	if len(text) > 0 && text[len(text)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// GetFileInfo describes a file for the preview modal before it calls
+// ReadFile: size, modification time, MIME type, text encoding, a line
+// count, whether the file looks binary, and the view to show it in. Text
+// over maxReadFileSize is too large to preview; binary files come with a
+// hex dump of their first bytes instead.
+func (a *App) GetFileInfo(filePath string) (FileInfo, error) {
+	cleanPath, err := a.validateReadPath(filePath)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	stat, err := os.Stat(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to get file info", err, logrus.Fields{"filePath": cleanPath})
+		return FileInfo{}, newAppError(ErrCodeFileStatFailed, err)
+	}
+	file, err := os.Open(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to open file for info", err, logrus.Fields{"filePath": cleanPath})
+		return FileInfo{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	defer file.Close()
+
+	info := FileInfo{
+		Path:    cleanPath,
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().UnixMilli(),
+	}
+	info.Binary, err = a.fileIsBinary(file, info.Size, make([]byte, binarySampleSize))
+	if err != nil {
+		a.logError("Failed to read file for info", err, logrus.Fields{"filePath": cleanPath})
+		return FileInfo{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+
+	sample := int64(lineCountSampleSize)
+	if info.Binary {
+		sample = hexPreviewBytes
+	}
+	head := make([]byte, min(info.Size, sample))
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		a.logError("Failed to read file for info", err, logrus.Fields{"filePath": cleanPath})
+		return FileInfo{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	head = head[:n]
+	info.MimeType = detectMimeType(cleanPath, head)
+
+	if info.Binary {
+		info.View = previewHex
+		info.HexPreview = hex.Dump(head)
+		return info, nil
+	}
+
+	text, encoding := decodeText(head)
+	info.Encoding = encoding
+	info.LineEnding = detectLineEnding(text)
+	info.LineCount = countLines(text)
+	info.LineCountExact = int64(n) >= info.Size
+	if !info.LineCountExact && n > 0 {
+		info.LineCount = int(int64(info.LineCount) * info.Size / int64(n))
+	}
+	info.View = previewText
+	if info.Size > maxReadFileSize {
+		info.View = previewTooLarge
+	}
+	return info, nil
+}
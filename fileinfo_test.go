@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGetFileInfo verifies the description of a small text file, a UTF-16
+// file, a PNG image, and a text file too large to preview whose lines are
+// estimated.
+func TestGetFileInfo(t *testing.T) {
+	dir := t.TempDir()
+	app := NewApp()
+
+	textPath := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(textPath, []byte("package main\n\nfunc main() {}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := app.GetFileInfo(textPath)
+	if err != nil {
+		t.Fatalf("GetFileInfo failed: %v", err)
+	}
+	if info.Binary || info.View != previewText || info.LineCount != 3 || !info.LineCountExact || info.Encoding != encodingUTF8 {
+		t.Errorf("unexpected text file info %+v", info)
+	}
+	if info.Size != 28 || info.ModTime == 0 || !strings.HasPrefix(info.MimeType, "text/") {
+		t.Errorf("unexpected size, time, or type in %+v", info)
+	}
+
+	utf16Path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(utf16Path, encodeUTF16("one\ntwo\n", false), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := app.GetFileInfo(utf16Path); err != nil || info.Encoding != encodingUTF16LE || info.LineCount != 2 {
+		t.Errorf("unexpected UTF-16 file info %+v, %v", info, err)
+	}
+
+	pngPath := filepath.Join(dir, "logo.png")
+	png := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), make([]byte, 64)...)
+	if err := os.WriteFile(pngPath, png, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = app.GetFileInfo(pngPath)
+	if err != nil {
+		t.Fatalf("GetFileInfo failed: %v", err)
+	}
+	if !info.Binary || info.View != previewHex || info.MimeType != "image/png" || info.LineCount != 0 {
+		t.Errorf("unexpected image info %+v", info)
+	}
+	if !strings.HasPrefix(info.HexPreview, "00000000  89 50 4e 47") {
+		t.Errorf("unexpected hex preview %q", info.HexPreview)
+	}
+
+	bigPath := filepath.Join(dir, "big.log")
+	size := maxReadFileSize + 10*1024
+	if err := os.WriteFile(bigPath, bytes.Repeat([]byte("abcdefghi\n"), int(size/10)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = app.GetFileInfo(bigPath)
+	if err != nil {
+		t.Fatalf("GetFileInfo failed: %v", err)
+	}
+	want := int(size / 10)
+	if info.View != previewTooLarge || info.LineCountExact || info.LineCount < want*99/100 || info.LineCount > want*101/100 {
+		t.Errorf("expected a too-large view with about %d lines, got %+v", want, info)
+	}
+
+	if _, err := app.GetFileInfo(filepath.Join(dir, "missing.txt")); err == nil || err.(*AppError).Code != ErrCodeFileNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeFileNotFound, err)
+	}
+}
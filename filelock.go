@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// lockedErrnos are the errnos isLockedFileError treats as a lock.
+var lockedErrnos = []syscall.Errno{syscall.EBUSY}
+
+// isLockedFileError reports whether a read failed because another process
+// holds the file. Unix locks are advisory and never block a read, so only
+// EBUSY, which some FUSE and network filesystems return for files in use,
+// counts. See filelockWindows.go for sharing violations.
+func isLockedFileError(err error) bool {
+	for _, errno := range lockedErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockedErrnos are the errnos isLockedFileError treats as a lock.
+var lockedErrnos = []windows.Errno{windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION}
+
+// isLockedFileError reports whether a read failed because another process
+// holds the file: opened without read sharing (ERROR_SHARING_VIOLATION, the
+// usual case for files an editor, database, or antivirus scanner has open)
+// or with a byte-range lock over the part being read (ERROR_LOCK_VIOLATION).
+func isLockedFileError(err error) bool {
+	for _, errno := range lockedErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
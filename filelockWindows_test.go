@@ -0,0 +1,23 @@
+//go:build windows
+
+package main
+
+import (
+	"io/fs"
+	"testing"
+
+	"golang.org/x/sys/windows"
+)
+
+// TestIsLockedFileErrorWindows verifies that sharing and lock violations
+// are recognised through the *PathError the os package wraps them in.
+func TestIsLockedFileErrorWindows(t *testing.T) {
+	for _, errno := range []windows.Errno{windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION} {
+		if !isLockedFileError(&fs.PathError{Op: "open", Path: `C:\db.sqlite`, Err: errno}) {
+			t.Errorf("expected errno %d to be a locked-file error", errno)
+		}
+	}
+	if isLockedFileError(&fs.PathError{Op: "open", Path: `C:\x`, Err: windows.ERROR_ACCESS_DENIED}) {
+		t.Error("expected access denied not to be a locked-file error")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+// lockedErr returns an error isLockedFileError recognises on the current
+// platform.
+func lockedErr(t *testing.T) error {
+	t.Helper()
+	for _, errno := range lockedErrnos {
+		err := &fs.PathError{Op: "open", Path: "f", Err: errno}
+		if isLockedFileError(err) {
+			return err
+		}
+	}
+	t.Fatal("no locked-file errno for this platform")
+	return nil
+}
+
+// TestRetryIfLocked verifies that a locked read is retried once only with
+// RetryLocked, and that other errors are never retried.
+func TestRetryIfLocked(t *testing.T) {
+	locked := lockedErr(t)
+	ctx := context.Background()
+
+	calls := 0
+	read := func() error {
+		calls++
+		if calls == 1 {
+			return locked
+		}
+		return nil
+	}
+	if err := retryIfLocked(ctx, SearchRequest{}, read); err != locked || calls != 1 {
+		t.Errorf("expected no retry without RetryLocked, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	if err := retryIfLocked(ctx, SearchRequest{RetryLocked: true}, read); err != nil || calls != 2 {
+		t.Errorf("expected success on the retry, got err=%v after %d calls", err, calls)
+	}
+
+	calls = 0
+	denied := &fs.PathError{Op: "open", Path: "f", Err: fs.ErrPermission}
+	err := retryIfLocked(ctx, SearchRequest{RetryLocked: true}, func() error { calls++; return denied })
+	if !errors.Is(err, fs.ErrPermission) || calls != 1 {
+		t.Errorf("expected permission errors not to be retried, got err=%v after %d calls", err, calls)
+	}
+}
+
+// TestSkipUnreadableFileCategories verifies that locked and otherwise
+// unreadable files land in separate skip counters.
+func TestSkipUnreadableFileCategories(t *testing.T) {
+	app := NewApp()
+	state := &SearchState{}
+	req := SearchRequest{Directory: t.TempDir()}
+	cancel := func() {}
+
+	app.skipUnreadableFile("a", lockedErr(t), req, state, cancel)
+	app.skipUnreadableFile("b", &fs.PathError{Op: "open", Path: "b", Err: fs.ErrPermission}, req, state, cancel)
+	app.skipUnreadableFile("c", &fs.PathError{Op: "open", Path: "c", Err: fs.ErrNotExist}, req, state, cancel)
+
+	got := *state.skipStats()
+	want := SkipStats{Vanished: 1, Locked: 1, Unreadable: 1}
+	if got != want {
+		t.Errorf("skip stats = %+v, want %+v", got, want)
+	}
+}
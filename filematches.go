@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fileMatchContext is how many lines GetFileMatches shows around each
+// match.
+const fileMatchContext = 3
+
+// matchRanges returns the fewest line ranges that show every line in
+// lineNums with context lines on each side: overlapping and touching
+// windows are merged. Ranges start at line 1 at the earliest; the end of
+// the file is not known here, so the last range may run past it.
+func matchRanges(lineNums []int, context int) []LineRange {
+	sorted := append([]int(nil), lineNums...)
+	sort.Ints(sorted)
+	var ranges []LineRange
+	for _, n := range sorted {
+		if n < 1 {
+			continue
+		}
+		start, end := max(n-context, 1), n+context
+		if last := len(ranges) - 1; last >= 0 && start <= ranges[last].End+1 {
+			ranges[last].End = max(ranges[last].End, end)
+			continue
+		}
+		ranges = append(ranges, LineRange{Start: start, End: end})
+	}
+	return ranges
+}
+
+// readLineRanges fills in the Lines of each range from the file at path,
+// reading it once and stopping after the last range. Ranges past the end
+// of the file are cut short at the last line, or dropped when they start
+// after it.
+func readLineRanges(path string, ranges []LineRange) ([]LineRange, error) {
+	if len(ranges) == 0 {
+		return ranges, nil
+	}
+	file, err := os.Open(toLongPath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	text, _ := newTextReader(file)
+	scanner := bufio.NewScanner(text)
+	// Same long-line allowance as the streaming search path.
+	buf := make([]byte, 1024*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	lastLine := ranges[len(ranges)-1].End
+	i, lineNum := 0, 0
+	for lineNum < lastLine && scanner.Scan() {
+		lineNum++
+		for i < len(ranges) && lineNum > ranges[i].End {
+			i++
+		}
+		if lineNum >= ranges[i].Start {
+			ranges[i].Lines = append(ranges[i].Lines, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	kept := ranges[:0]
+	for _, r := range ranges {
+		if len(r.Lines) > 0 {
+			r.End = r.Start + len(r.Lines) - 1
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
+
+// GetFileMatches returns everything the results pane needs to show one
+// file of a completed search in a single call: its matches, in the order
+// they were found, and the fewest line ranges that show them with three
+// lines of context each, read from disk. A file edited since the search is
+// reported as Stale, since its lines may have moved. Bucket results have no
+// local file and come without ranges. A file the search has no results for
+// fails with FILE_NOT_IN_RESULTS; only the most recent searches are kept,
+// and an older ID fails with SEARCH_NOT_FOUND.
+func (a *App) GetFileMatches(searchID string, filePath string) (FileMatches, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return FileMatches{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	matches := FileMatches{SearchID: searchID, FilePath: filePath, Matches: []SearchResult{}, Ranges: []LineRange{}}
+	var lineNums []int
+	for _, r := range rec.results {
+		if r.FilePath == filePath {
+			matches.Matches = append(matches.Matches, r)
+			lineNums = append(lineNums, r.LineNum)
+		}
+	}
+	if len(matches.Matches) == 0 {
+		return FileMatches{}, newAppError(ErrCodeFileNotInResults, filePath, searchID)
+	}
+	if !filepath.IsAbs(filePath) {
+		return matches, nil
+	}
+
+	info, err := os.Stat(toLongPath(filePath))
+	if err != nil {
+		return FileMatches{}, newAppError(ErrCodeFileNotFound, filePath)
+	}
+	matches.Stale = info.ModTime().UnixMilli() != rec.modTimes[filePath]
+	ranges, err := readLineRanges(filePath, matchRanges(lineNums, fileMatchContext))
+	if err != nil {
+		a.logError("Failed to read file matches", err, logrus.Fields{"filePath": filePath})
+		return FileMatches{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	matches.Ranges = ranges
+	return matches, nil
+}
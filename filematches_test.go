@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestMatchRanges verifies merging of overlapping and touching windows,
+// unsorted input, the start of the file, and results without a line.
+func TestMatchRanges(t *testing.T) {
+	tests := []struct {
+		lines []int
+		want  []LineRange
+	}{
+		{nil, nil},
+		{[]int{0}, nil},
+		{[]int{2}, []LineRange{{Start: 1, End: 5}}},
+		{[]int{20, 10}, []LineRange{{Start: 7, End: 13}, {Start: 17, End: 23}}},
+		{[]int{10, 16}, []LineRange{{Start: 7, End: 19}}},
+		{[]int{10, 17, 10}, []LineRange{{Start: 7, End: 20}}},
+	}
+	for _, tt := range tests {
+		if got := matchRanges(tt.lines, 3); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchRanges(%v) = %+v, want %+v", tt.lines, got, tt.want)
+		}
+	}
+}
+
+// TestGetFileMatches verifies the matches and line ranges of one file of a
+// search, ranges cut at the end of the file, the stale flag after an edit,
+// and unknown files and searches.
+func TestGetFileMatches(t *testing.T) {
+	app := NewApp()
+	path := writeNumberedFile(t, 30)
+	other := filepath.Join(t.TempDir(), "other.txt")
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, results: []SearchResult{
+		{FilePath: path, LineNum: 29, Content: "line 29"},
+		{FilePath: other, LineNum: 1},
+		{FilePath: path, LineNum: 5, Content: "line 5"},
+		{FilePath: path, LineNum: 8, Content: "line 8"},
+	}})
+
+	got, err := app.GetFileMatches(id, path)
+	if err != nil {
+		t.Fatalf("GetFileMatches failed: %v", err)
+	}
+	if len(got.Matches) != 3 || got.Matches[0].LineNum != 29 || got.Stale {
+		t.Errorf("unexpected matches %+v", got)
+	}
+	if len(got.Ranges) != 2 {
+		t.Fatalf("expected 2 ranges, got %+v", got.Ranges)
+	}
+	if r := got.Ranges[0]; r.Start != 2 || r.End != 11 || len(r.Lines) != 10 || r.Lines[0] != "line 2" || r.Lines[9] != "line 11" {
+		t.Errorf("unexpected first range %+v", r)
+	}
+	if r := got.Ranges[1]; r.Start != 26 || r.End != 30 || !reflect.DeepEqual(r.Lines, []string{"line 26", "line 27", "line 28", "line 29", "line 30"}) {
+		t.Errorf("unexpected last range %+v", r)
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = app.GetFileMatches(id, path); err != nil || !got.Stale {
+		t.Errorf("expected a stale file, got %+v, %v", got, err)
+	}
+
+	if _, err := app.GetFileMatches(id, "/elsewhere.txt"); err == nil || err.(*AppError).Code != ErrCodeFileNotInResults {
+		t.Errorf("expected %s, got %v", ErrCodeFileNotInResults, err)
+	}
+	if _, err := app.GetFileMatches("search-999", path); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeSearchNotFound, err)
+	}
+}
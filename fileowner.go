@@ -0,0 +1,48 @@
+//go:build !windows
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// ownerFiltersSupported is reported by GetCapabilities. Unix files carry an
+// owner and permission bits for OwnedByMe and PermissionMask to check.
+const ownerFiltersSupported = true
+
+// currentUID is the user OwnedByMe compares file owners with.
+var currentUID = uint32(os.Getuid())
+
+// ownerWanted reports whether a file passes the OwnedByMe and
+// PermissionMask filters of req. Like find's -perm /MODE, PermissionMask
+// keeps files with any of its bits set, so 0o002 finds world-writable files.
+func ownerWanted(req SearchRequest, info fs.FileInfo) bool {
+	if req.PermissionMask != 0 && unixPermissions(info.Mode())&req.PermissionMask == 0 {
+		return false
+	}
+	if req.OwnedByMe {
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || st.Uid != currentUID {
+			return false
+		}
+	}
+	return true
+}
+
+// unixPermissions returns the permission bits of mode in their chmod
+// layout, with setuid, setgid, and sticky as 0o4000, 0o2000, and 0o1000.
+func unixPermissions(mode fs.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+	return perm
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import "io/fs"
+
+// ownerFiltersSupported is reported by GetCapabilities. Windows has ACLs
+// rather than an owner uid and mode bits, so OwnedByMe and PermissionMask
+// are rejected by setSearchDefaults.
+const ownerFiltersSupported = false
+
+// ownerWanted keeps every file; the filters never reach the walk on Windows.
+func ownerWanted(req SearchRequest, info fs.FileInfo) bool {
+	return true
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestWalkDirectoryTreeOwnerFilters verifies that PermissionMask keeps files
+// with any of its bits set, that OwnedByMe compares the owner with the
+// current user, and that masks beyond 07777 are rejected.
+func TestWalkDirectoryTreeOwnerFilters(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	for name, mode := range map[string]os.FileMode{"open.sh": 0o777, "group.sh": 0o775, "private.sh": 0o700} {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("rm -rf\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// Chmod sidesteps the umask applied by WriteFile.
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collect := func(req SearchRequest) string {
+		t.Helper()
+		req.Directory, req.Query = tempDir, "rm"
+		req, err := app.validateAndSetDefaults(req)
+		if err != nil {
+			t.Fatalf("validateAndSetDefaults failed: %v", err)
+		}
+		files, err := app.collectFilesToProcess(req, nil, filepath.Clean(tempDir)+string(filepath.Separator))
+		if err != nil {
+			t.Fatalf("collectFilesToProcess failed: %v", err)
+		}
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(f.absPath))
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	if got := collect(SearchRequest{PermissionMask: 0o002}); got != "open.sh" {
+		t.Errorf("world-writable: got %s", got)
+	}
+	if got := collect(SearchRequest{PermissionMask: 0o022}); got != "group.sh,open.sh" {
+		t.Errorf("group- or world-writable: got %s", got)
+	}
+	if got := collect(SearchRequest{OwnedByMe: true, PermissionMask: 0o100}); got != "group.sh,open.sh,private.sh" {
+		t.Errorf("own executables: got %s", got)
+	}
+
+	defer func(uid uint32) { currentUID = uid }(currentUID)
+	currentUID++
+	if got := collect(SearchRequest{OwnedByMe: true}); got != "" {
+		t.Errorf("owned by another user: got %s", got)
+	}
+
+	_, err := app.validateAndSetDefaults(SearchRequest{Directory: tempDir, Query: "rm", PermissionMask: 0o10000})
+	if err == nil || err.(*AppError).Code != ErrCodePermissionMaskInvalid {
+		t.Errorf("expected %s, got %v", ErrCodePermissionMaskInvalid, err)
+	}
+}
+
+// TestUnixPermissions verifies the chmod layout of the special mode bits.
+func TestUnixPermissions(t *testing.T) {
+	mode := os.FileMode(0o755) | os.ModeSetuid | os.ModeSticky
+	if got := unixPermissions(mode); got != 0o5755 {
+		t.Errorf("unixPermissions = %#o, want 05755", got)
+	}
+}
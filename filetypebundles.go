@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// fileTypeBundlePrefix marks an AllowedFileTypes entry as a bundle name
+// rather than an extension: "@web".
+const fileTypeBundlePrefix = "@"
+
+// fileTypeBundles is the registry of named extension sets that
+// AllowedFileTypes entries like "@web" stand for. Extensions have no dot,
+// as in AllowedFileTypes itself.
+var fileTypeBundles = []FileTypeBundle{
+	{Name: "web", Description: "Web front-end sources and styles", Extensions: []string{"js", "ts", "jsx", "tsx", "html", "css", "scss"}},
+	{Name: "backend-go", Description: "Go sources, templates, and module files", Extensions: []string{"go", "tmpl", "mod", "sum"}},
+	{Name: "python", Description: "Python sources and stubs", Extensions: []string{"py", "pyi"}},
+	{Name: "shell", Description: "Shell and PowerShell scripts", Extensions: []string{"sh", "bash", "zsh", "ps1"}},
+	{Name: "config", Description: "Configuration files", Extensions: []string{"json", "yaml", "yml", "toml", "ini"}},
+	{Name: "docs", Description: "Documentation markup", Extensions: []string{"md", "rst", "adoc"}},
+}
+
+// fileTypeBundleIndex maps each bundle name to its extensions, built once
+// from fileTypeBundles.
+var fileTypeBundleIndex = func() map[string][]string {
+	index := make(map[string][]string, len(fileTypeBundles))
+	for _, bundle := range fileTypeBundles {
+		index[bundle.Name] = bundle.Extensions
+	}
+	return index
+}()
+
+// expandFileTypeBundles replaces the "@name" entries of an AllowedFileTypes
+// list with the extensions of their bundles, dropping duplicates and
+// keeping the order of first appearance. Bundle names are matched case-
+// insensitively; an unknown one fails with FILE_TYPE_BUNDLE_UNKNOWN.
+func expandFileTypeBundles(types []string) ([]string, error) {
+	hasBundle := false
+	for _, t := range types {
+		if strings.HasPrefix(t, fileTypeBundlePrefix) {
+			hasBundle = true
+			break
+		}
+	}
+	if !hasBundle {
+		return types, nil
+	}
+	expanded := make([]string, 0, len(types))
+	seen := make(map[string]bool)
+	add := func(ext string) {
+		if key := strings.ToLower(ext); !seen[key] {
+			seen[key] = true
+			expanded = append(expanded, ext)
+		}
+	}
+	for _, t := range types {
+		name, isBundle := strings.CutPrefix(t, fileTypeBundlePrefix)
+		if !isBundle {
+			add(t)
+			continue
+		}
+		exts, ok := fileTypeBundleIndex[strings.ToLower(name)]
+		if !ok {
+			return nil, newAppError(ErrCodeFileTypeBundleUnknown, name)
+		}
+		for _, ext := range exts {
+			add(ext)
+		}
+	}
+	return expanded, nil
+}
+
+// ListFileTypeBundles returns the named extension sets that can be used in
+// AllowedFileTypes, and in ext: query operators, as "@name", sorted by
+// name.
+func (a *App) ListFileTypeBundles() []FileTypeBundle {
+	bundles := make([]FileTypeBundle, len(fileTypeBundles))
+	for i, bundle := range fileTypeBundles {
+		bundle.Extensions = append([]string(nil), bundle.Extensions...)
+		bundles[i] = bundle
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+	return bundles
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestExpandFileTypeBundles verifies that bundle names expand in place
+// without duplicates, case-insensitively, and that unknown names fail.
+func TestExpandFileTypeBundles(t *testing.T) {
+	tests := []struct {
+		in, want []string
+	}{
+		{nil, nil},
+		{[]string{"go", "txt"}, []string{"go", "txt"}},
+		{[]string{"@docs"}, []string{"md", "rst", "adoc"}},
+		{[]string{"txt", "@DOCS", "MD", "@docs"}, []string{"txt", "md", "rst", "adoc"}},
+	}
+	for _, tt := range tests {
+		got, err := expandFileTypeBundles(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandFileTypeBundles(%v) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := expandFileTypeBundles([]string{"@nope"}); err == nil || err.(*AppError).Code != ErrCodeFileTypeBundleUnknown {
+		t.Errorf("expected FILE_TYPE_BUNDLE_UNKNOWN, got %v", err)
+	}
+}
+
+// TestSearchFileTypeBundle verifies that a bundle narrows a search both as
+// an AllowedFileTypes entry and in an ext: query operator.
+func TestSearchFileTypeBundle(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.md", "b.rst", "c.go", "d.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("needle\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := NewApp()
+	for _, req := range []SearchRequest{
+		{Directory: dir, Query: "needle", AllowedFileTypes: []string{"@docs"}},
+		{Directory: dir, Query: "needle ext:@docs"},
+	} {
+		results, err := app.SearchWithProgress(req)
+		if err != nil {
+			t.Fatalf("search %+v failed: %v", req, err)
+		}
+		if len(results) != 2 {
+			t.Errorf("search %+v: expected the .md and .rst files, got %+v", req, results)
+		}
+	}
+}
+
+// TestListFileTypeBundles verifies that the bundles come sorted by name
+// and that changing the returned copy leaves the registry alone.
+func TestListFileTypeBundles(t *testing.T) {
+	app := NewApp()
+	bundles := app.ListFileTypeBundles()
+	if len(bundles) != len(fileTypeBundles) {
+		t.Fatalf("expected %d bundles, got %d", len(fileTypeBundles), len(bundles))
+	}
+	for i := 1; i < len(bundles); i++ {
+		if bundles[i-1].Name >= bundles[i].Name {
+			t.Errorf("bundles not sorted: %s before %s", bundles[i-1].Name, bundles[i].Name)
+		}
+	}
+	bundles[0].Extensions[0] = "changed"
+	if app.ListFileTypeBundles()[0].Extensions[0] == "changed" {
+		t.Error("ListFileTypeBundles returned the registry's own slice")
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resultsWatchInterval is how often the matched files of the latest search
+// are checked for edits. Polling keeps the watcher to a few stat calls per
+// interval, bounded by MaxResults, without a platform notification API.
+const resultsWatchInterval = 2 * time.Second
+
+// resultModTimes returns the modification times, in Unix milliseconds, of
+// the files a search matched. Paths that aren't local files (bucket object
+// URLs) or can no longer be read are left out and never reported stale.
+func resultModTimes(results []SearchResult, counts []FileMatchCount) map[string]int64 {
+	modTimes := make(map[string]int64)
+	add := func(path string) {
+		if _, seen := modTimes[path]; seen || !filepath.IsAbs(path) {
+			return
+		}
+		if info, err := os.Stat(toLongPath(path)); err == nil {
+			modTimes[path] = info.ModTime().UnixMilli()
+		}
+	}
+	for _, r := range results {
+		add(r.FilePath)
+	}
+	for _, c := range counts {
+		add(c.FilePath)
+	}
+	return modTimes
+}
+
+// resultsFreshness compares the stored modification times of a search
+// with the files on disk.
+func resultsFreshness(rec searchRecord) ResultsFreshness {
+	fresh := ResultsFreshness{
+		SearchID:     rec.id,
+		ChangedFiles: []string{},
+		DeletedFiles: []string{},
+	}
+	for path, modTime := range rec.modTimes {
+		info, err := os.Stat(toLongPath(path))
+		switch {
+		case os.IsNotExist(err):
+			fresh.DeletedFiles = append(fresh.DeletedFiles, path)
+		case err == nil && info.ModTime().UnixMilli() != modTime:
+			fresh.ChangedFiles = append(fresh.ChangedFiles, path)
+		}
+	}
+	sort.Strings(fresh.ChangedFiles)
+	sort.Strings(fresh.DeletedFiles)
+	fresh.Stale = len(fresh.ChangedFiles) > 0 || len(fresh.DeletedFiles) > 0
+	return fresh
+}
+
+// CheckResultsFreshness reports which matched files of a completed search
+// were edited or deleted since it finished, so the UI can offer to run it
+// again. Files are compared by modification time; bucket searches have no
+// local files and always come back fresh. Only the most recent searches
+// are kept; an older ID fails with SEARCH_NOT_FOUND.
+func (a *App) CheckResultsFreshness(searchID string) (ResultsFreshness, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return ResultsFreshness{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	return resultsFreshness(rec), nil
+}
+
+// watchResults starts watching the matched files of rec, replacing the
+// watch of the previous search, and emits "results-stale" with the
+// ResultsFreshness once any of them changes. Only the latest search is
+// watched, and only while the Wails runtime is up.
+func (a *App) watchResults(rec searchRecord) {
+	if a.ctx == nil || len(rec.modTimes) == 0 {
+		a.stopResultsWatch()
+		return
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.resultsWatchMu.Lock()
+	if a.resultsWatchStop != nil {
+		a.resultsWatchStop()
+	}
+	a.resultsWatchStop = cancel
+	a.resultsWatchMu.Unlock()
+
+	go func() {
+		defer cancel()
+		fresh, ok := pollResultsFreshness(ctx, rec, resultsWatchInterval)
+		if !ok {
+			return
+		}
+		a.logInfo("Search results are stale", logrus.Fields{
+			"searchId":     rec.id,
+			"changedFiles": len(fresh.ChangedFiles),
+			"deletedFiles": len(fresh.DeletedFiles),
+		})
+		a.safeEmitEvent("results-stale", fresh)
+	}()
+}
+
+// stopResultsWatch stops watching the files of the latest search, if any.
+func (a *App) stopResultsWatch() {
+	a.resultsWatchMu.Lock()
+	defer a.resultsWatchMu.Unlock()
+	if a.resultsWatchStop != nil {
+		a.resultsWatchStop()
+		a.resultsWatchStop = nil
+	}
+}
+
+// pollResultsFreshness checks the files of rec every interval until one of
+// them changes, returning the stale ResultsFreshness, or until ctx is done.
+func pollResultsFreshness(ctx context.Context, rec searchRecord, interval time.Duration) (ResultsFreshness, bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ResultsFreshness{}, false
+		case <-ticker.C:
+			if fresh := resultsFreshness(rec); fresh.Stale {
+				return fresh, true
+			}
+		}
+	}
+}
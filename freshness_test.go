@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCheckResultsFreshness verifies that edited and deleted result files
+// are reported, untouched files and bucket URLs are not, and an unknown
+// search fails with SEARCH_NOT_FOUND.
+func TestCheckResultsFreshness(t *testing.T) {
+	dir := t.TempDir()
+	app := NewApp()
+	results := []SearchResult{{FilePath: "s3://bucket/key.txt"}}
+	for _, name := range []string{"edited.go", "deleted.go", "kept.go"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("TODO\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, SearchResult{FilePath: path, LineNum: 1})
+	}
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: dir}, results: results})
+
+	fresh, err := app.CheckResultsFreshness(id)
+	if err != nil || fresh.Stale || len(fresh.ChangedFiles) != 0 || len(fresh.DeletedFiles) != 0 {
+		t.Fatalf("expected fresh results, got %+v, %v", fresh, err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "edited.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "deleted.go")); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err = app.CheckResultsFreshness(id)
+	if err != nil {
+		t.Fatalf("CheckResultsFreshness failed: %v", err)
+	}
+	if !fresh.Stale || fresh.SearchID != id ||
+		len(fresh.ChangedFiles) != 1 || filepath.Base(fresh.ChangedFiles[0]) != "edited.go" ||
+		len(fresh.DeletedFiles) != 1 || filepath.Base(fresh.DeletedFiles[0]) != "deleted.go" {
+		t.Errorf("unexpected freshness %+v", fresh)
+	}
+
+	if _, err := app.CheckResultsFreshness("search-missing"); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeSearchNotFound, err)
+	}
+}
+
+// TestPollResultsFreshness verifies that the watcher returns once a file
+// changes and gives up when its context is cancelled.
+func TestPollResultsFreshness(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(path, []byte("TODO\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec := searchRecord{id: "search-1", modTimes: resultModTimes([]SearchResult{{FilePath: path}}, nil)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := pollResultsFreshness(ctx, rec, time.Millisecond); ok {
+		t.Error("expected a cancelled watch to report nothing")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fresh, ok := pollResultsFreshness(ctx, rec, time.Millisecond)
+	if !ok || len(fresh.ChangedFiles) != 1 {
+		t.Errorf("expected the edit to be seen, got %+v, %v", fresh, ok)
+	}
+}
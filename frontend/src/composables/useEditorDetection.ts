@@ -28,6 +28,8 @@ export function makeDefaultEditorAvailability(): EditorAvailability {
     visualstudio: false,
     eclipse: false,
     netbeans: false,
+    nano: false,
+    helix: false,
   };
 }
 
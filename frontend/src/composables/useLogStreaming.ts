@@ -22,7 +22,8 @@ export interface LogEntry {
 //
 // The backend sends LogMessage objects: { type: "log", content: ... }
 // where content is either an already-parsed JSON object (from structured
-// logrus logs) or a plain string (from non-JSON log lines).
+// logrus logs) or a plain string (from non-JSON log lines). Noisy entries are
+// already dropped by the backend, per the logFilters setting.
 // ---------------------------------------------------------------------------
 
 /** Resolve the raw content value into a structured object or fallback string. */
@@ -51,15 +52,6 @@ function readField(
   return undefined;
 }
 
-/** Return true when the content should be filtered out (noisy / internal). */
-function isNoisy(raw: any): boolean {
-  const msg =
-    typeof raw === "string"
-      ? raw
-      : readField(raw, ["msg", "message"]) || "";
-  return msg.includes("Skipping") || msg.includes("Sending file");
-}
-
 /** Extract a display-friendly log level, always uppercased. */
 function pickLevel(obj: Record<string, any>): string {
   return (
@@ -83,12 +75,11 @@ function formatTime(obj: Record<string, any>): string {
 }
 
 /**
- * Parse a raw LogMessage (from the backend's Wails binding) into a LogEntry,
- * or return null to skip (noisy / internal messages).
+ * Parse a raw LogMessage (from the backend's Wails binding) into a LogEntry.
  *
  * This is exported so Vue templates and tests can access it directly.
  */
-export function parseLogEntry(data: any): LogEntry | null {
+export function parseLogEntry(data: any): LogEntry {
   const content = resolveContent(data.content);
 
   // Falsy / missing content — show a descriptive message rather than silently
@@ -103,7 +94,6 @@ export function parseLogEntry(data: any): LogEntry | null {
 
   // Plain-text content — no further parsing needed
   if (typeof content === "string") {
-    if (isNoisy(content)) return null;
     return {
       timestamp: new Date().toLocaleTimeString(),
       level: "INFO",
@@ -112,7 +102,6 @@ export function parseLogEntry(data: any): LogEntry | null {
   }
 
   // Structured JSON object from Logrus
-  if (isNoisy(content)) return null;
   return {
     timestamp: formatTime(content),
     level: pickLevel(content),
@@ -177,7 +166,6 @@ export function useLogStreaming() {
 
   function addLogEntryInternal(data: any) {
     const logEntry = parseLogEntry(data);
-    if (!logEntry) return;
 
     // Create a new array to trigger shallowRef reactivity
     logs.value = [...logs.value, logEntry];
@@ -196,12 +184,7 @@ export function useLogStreaming() {
 
         if (Array.isArray(result)) {
           // Populate preview logs from the backend's in-memory buffer
-          const preview: LogEntry[] = [];
-          result.forEach((log: any) => {
-            const entry = parseLogEntry(log);
-            if (entry) preview.push(entry);
-          });
-          previewLogs.value = preview;
+          previewLogs.value = result.map((log: any) => parseLogEntry(log));
 
           // Also add to live logs for streaming
           result.forEach((log: any) => {
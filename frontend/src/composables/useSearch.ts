@@ -4,9 +4,16 @@ import {
   SearchWithProgress as GoSearchWithProgress,
   CancelSearch as GoCancelSearch,
   GetKnownTextExtensions as GoGetKnownTextExtensions,
+  GetLaunchRequest as GoGetLaunchRequest,
+  FilterResults as GoFilterResults,
 } from "../../wailsjs/go/main/App";
 import { EventsOn } from "../../wailsjs/runtime";
-import { SearchRequest, SearchResult, SearchState } from "../types/search";
+import {
+  LaunchRequest,
+  SearchRequest,
+  SearchResult,
+  SearchState,
+} from "../types/search";
 import {
   loadRecentSearches,
   saveRecentSearches,
@@ -40,6 +47,10 @@ export function useSearch() {
     maxFileSize: DEFAULT_MAX_FILE_SIZE,
     maxResults: DEFAULT_MAX_RESULTS,
     searchSubdirs: true,
+    includeSubmodules: false,
+    expandIdentifiers: false,
+    extractGroups: false,
+    fuzziness: 0,
     resultText: "Please enter search parameters below 👇",
     searchResults: [] as SearchResult[],
     truncatedResults: false,
@@ -132,7 +143,11 @@ export function useSearch() {
     saveRecentSearches(data.recentSearches);
   };
 
-  const searchCode = async () => {
+  const searchCode = () => runSearch(false);
+
+  // runSearch runs the search; confirmExpensive re-sends a request the
+  // backend rejected with CONFIRMATION_REQUIRED after the user agreed.
+  const runSearch = async (confirmExpensive: boolean) => {
     data.error = null;
 
     if (!data.directory) {
@@ -214,14 +229,21 @@ export function useSearch() {
       minFileSize: Number(data.minFileSize) || 0,
       maxResults: Number(data.maxResults) || 1000,
       searchSubdirs: data.searchSubdirs,
+      includeSubmodules: !!data.includeSubmodules,
       useRegex: data.useRegex,
+      expandIdentifiers: !data.useRegex && !!data.expandIdentifiers,
+      extractGroups: data.useRegex && !!data.extractGroups,
+      fuzziness:
+        data.useRegex || data.expandIdentifiers ? 0 : Number(data.fuzziness) || 0,
       excludePatterns: Array.isArray(data.excludePatterns)
         ? data.excludePatterns.filter((s) => s.length > 0)
         : [],
       allowedFileTypes: Array.isArray(data.allowedFileTypes)
         ? data.allowedFileTypes.filter((s) => s.length > 0)
         : [],
+      confirmExpensive,
     };
+    let retryConfirmed = false;
 
     try {
       currentProgressCleanup = EventsOn(
@@ -235,12 +257,26 @@ export function useSearch() {
               resultsCount: progressData.resultsCount || 0,
               status: progressData.status || "",
             };
+            if (progressData.searchId) {
+              data.lastSearchId = progressData.searchId;
+            }
 
-            if (progressData.status === "in-progress") {
+            if (progressData.status === "collecting") {
+              data.resultText = "Collecting files...";
+            } else if (progressData.status === "in-progress") {
               data.resultText = `Searching... Processed ${progressData.processedFiles || 0} of ${progressData.totalFiles || 0} files, found ${progressData.resultsCount || 0} matches`;
             } else if (progressData.status === "completed") {
               data.resultText = `Search completed! Processed ${progressData.processedFiles || 0} files, found ${progressData.resultsCount || 0} matches`;
-              if (progressData.resultsCount > 0) {
+              const unread =
+                (progressData.skipped?.locked || 0) +
+                (progressData.skipped?.unreadable || 0);
+              if (unread > 0) {
+                data.resultText += ` (${unread} files locked or unreadable)`;
+              }
+              if (progressData.incomplete) {
+                // SearchWithProgress rejects with SEARCH_ROOT_REMOVED; the
+                // catch block below reports it.
+              } else if (progressData.resultsCount > 0) {
                 toastManager.success(
                   `Search completed! Found ${progressData.resultsCount} matches`,
                   "Search Complete",
@@ -318,9 +354,31 @@ export function useSearch() {
     } catch (error: any) {
       data.searchResults = [];
       const errorMessage = error.message || "Unknown error occurred";
-      data.error = errorMessage;
-      toastManager.error(errorMessage, "Search Error");
-      console.error("Search error:", error);
+      if (error?.code === "SEARCH_ROOT_REMOVED" && data.lastSearchId) {
+        // Keep what was found before the directory disappeared.
+        try {
+          const partial = await GoFilterResults(data.lastSearchId, []);
+          data.searchResults = partial.groups.flatMap((g) => g.results);
+        } catch (e) {
+          console.error("Failed to load partial results:", e);
+        }
+        data.truncatedResults = true;
+        data.resultText = `Found ${data.searchResults.length} matches (incomplete)`;
+        data.error = errorMessage;
+        toastManager.warning(errorMessage, "Search Incomplete");
+      } else if (error?.code === "CONFIRMATION_REQUIRED" && !confirmExpensive) {
+        const fileCount = error.details?.fileCount;
+        retryConfirmed = window.confirm(
+          fileCount
+            ? `${errorMessage} (${fileCount} files)`
+            : errorMessage,
+        );
+        data.resultText = "Search not started";
+      } else {
+        data.error = errorMessage;
+        toastManager.error(errorMessage, "Search Error");
+        console.error("Search error:", error);
+      }
     } finally {
       data.isSearching = false;
       data.showProgress = false;
@@ -332,6 +390,10 @@ export function useSearch() {
         currentProgressCleanup = null;
       }
     }
+
+    if (retryConfirmed) {
+      await runSearch(true);
+    }
   };
 
   const cancelSearch = async () => {
@@ -435,6 +497,51 @@ export function useSearch() {
   };
   void fetchKnownTextExtensions();
 
+  // applyLaunchRequest pre-fills the form from a command-line directory and
+  // query, a codesearch:// link, or the file manager's context menu. A link
+  // with a shared request sets every option of the form, so the search is
+  // the sender's; only a directory that doesn't exist here is left as is.
+  const applyLaunchRequest = (req: LaunchRequest | null | undefined) => {
+    if (req?.directory) {
+      data.directory = req.directory;
+    }
+    if (req?.query) {
+      data.query = req.query;
+    }
+    const shared = req?.request;
+    if (shared) {
+      data.extension = shared.extension || "";
+      data.caseSensitive = !!shared.caseSensitive;
+      data.useRegex = shared.useRegex ?? true;
+      data.includeBinary = !!shared.includeBinary;
+      data.searchSubdirs = !!shared.searchSubdirs;
+      data.includeSubmodules = !!shared.includeSubmodules;
+      data.expandIdentifiers = !!shared.expandIdentifiers;
+      data.extractGroups = !!shared.extractGroups;
+      data.fuzziness = shared.fuzziness || 0;
+      if (shared.maxFileSize) data.maxFileSize = shared.maxFileSize;
+      if (shared.maxResults) data.maxResults = shared.maxResults;
+      data.minFileSize = shared.minFileSize || 0;
+      data.excludePatterns = shared.excludePatterns || [];
+      data.allowedFileTypes = shared.allowedFileTypes || [];
+    }
+  };
+
+  // The launch arguments of this process are pulled once on load; later
+  // launches while the app is running arrive as "launch-request" events.
+  const fetchLaunchRequest = async () => {
+    try {
+      applyLaunchRequest(await GoGetLaunchRequest());
+    } catch (error: any) {
+      console.error("Failed to load launch request:", error);
+    }
+  };
+  void fetchLaunchRequest();
+  let launchRequestCleanup: (() => void) | null = EventsOn(
+    "launch-request",
+    applyLaunchRequest,
+  );
+
   // cleanup tears down every listener this composable registered so the
   // caller can release them on component unmount. Without this the
   // search-progress and editor-detection listeners would leak for the app
@@ -448,6 +555,10 @@ export function useSearch() {
       editorDetectionCleanup();
       editorDetectionCleanup = null;
     }
+    if (launchRequestCleanup) {
+      launchRequestCleanup();
+      launchRequestCleanup = null;
+    }
   };
 
   return {
@@ -7,6 +7,18 @@ export interface SearchResult {
   matchedText: string;
   contextBefore: string[];
   contextAfter: string[];
+  captures?: Record<string, string>; // Capture groups by name or number (extractGroups only)
+  spans?: MatchSpan[]; // Every match on the line, as offsets into content
+}
+
+// Where a match lies in SearchResult.content. Highlight with the rune
+// offsets over Array.from(content) (see highlightSpans); the byte offsets
+// split multibyte characters.
+export interface MatchSpan {
+  start: number; // Byte offsets
+  end: number;
+  runeStart: number; // Code point offsets
+  runeEnd: number;
 }
 
 export interface SearchRequest {
@@ -22,14 +34,246 @@ export interface SearchRequest {
   useRegex?: boolean;    // Optional for backward compatibility
   excludePatterns: string[];
   allowedFileTypes: string[]; // List of file extensions that are allowed to be searched (if empty, all types allowed)
+  includePaths?: string[]; // Only search files whose relative path contains one of these (path: operator)
+  excludePaths?: string[]; // Skip files whose relative path contains one of these (-path: operator)
+  skipGenerated?: boolean; // Skip minified/generated files (*.min.js, *.map, "Code generated" headers)
+  maxFilesPerDir?: number; // Per-directory file cap (0 = default 100000, negative = unlimited)
+  slowFs?: boolean; // Network-drive mode (auto-enabled by the backend for network mounts)
+  sampling?: boolean; // Return maxResults matches sampled evenly across files
+  samplingThreshold?: number; // Matches scanned before sampling (0 = default 50000)
+  confirmExpensive?: boolean; // Re-send after the user confirmed a CONFIRMATION_REQUIRED warning
+  retryLocked?: boolean; // Retry files locked by another process once after a short delay
+  streamingThreshold?: number; // Stream files larger than this many bytes (0 = setting, default 1MB)
+  scannerBufferSize?: number; // Longest line the streaming scanner accepts (0 = setting, default 1MB)
+  includeSubmodules?: boolean; // Search git submodule working trees (skipped by default)
+  includeWellKnownFiles?: boolean; // Always search Dockerfile, Makefile, .github/workflows/*.yml, and similar
+  expandIdentifiers?: boolean; // Also match getUserId as get_user_id / GetUserID (literal, case-insensitive)
+  extractGroups?: boolean; // Return each match's capture groups in SearchResult.captures (regex only)
+  fuzziness?: number; // Typos a literal match may have (0 = exact; capped by query length, at most 3)
+  resultLogPath?: string; // Absolute NDJSON file every match is written to; only maxResults are returned
+  invertFileMatch?: boolean; // Return the files without a match (lineNum 0) instead of matching lines
+  modifiedAfter?: number; // Only search files modified at or after this time (Unix milliseconds)
+  modifiedBefore?: number; // Only search files modified before this time (Unix milliseconds)
+  ownedByMe?: boolean; // Only search files owned by the current user (Unix only)
+  permissionMask?: number; // Only search files with any of these mode bits set, e.g. 0o002 (Unix only)
+  headLines?: number; // Only match in the first headLines lines of each file (0 = every line)
+  regionStart?: string; // Only match between lines containing regionStart and regionEnd (e.g. "// BEGIN CONFIG")
+  regionEnd?: string; // Closes a region; empty means regions run to the end of the file
+}
+
+// One query's result set from BatchSearch
+export interface BatchResult {
+  query: string; // The query as requested, operators included
+  results: SearchResult[];
+  truncated: boolean; // The query reached its maxResults; there may be more matches
+}
+
+// Search for the files in which every pattern occurs, run by SearchCooccurrence
+export interface CooccurrenceRequest {
+  search: SearchRequest; // query is ignored; maxResults caps the files returned
+  patterns: string[]; // Two or more
+}
+
+// The nearest matches of two patterns in a file
+export interface CooccurrencePair {
+  patternA: number; // Index into patterns
+  patternB: number;
+  matchA: SearchResult;
+  matchB: SearchResult;
+  distance: number; // Lines between the matches; 0 on the same line
+}
+
+// File returned by SearchCooccurrence
+export interface CooccurrenceFile {
+  filePath: string;
+  pairs: CooccurrencePair[]; // For every two patterns: 1-2, 1-3, ..., 2-3, ...
+}
+
+// Content plugin returned by ListPlugins
+export interface PluginInfo {
+  name: string;
+  description: string;
+  extensions: string[]; // Without the dot
+  builtin: boolean;
+  path?: string; // Plugin directory; missing for built-in plugins
+  enabled: boolean;
+  error?: string; // Why the plugin can't be enabled
+}
+
+// Favorite directory returned by AddFavorite and ListFavorites
+export interface Favorite {
+  path: string;
+  label: string;
+  color: string; // "#rrggbb", or empty for the default color
+  addedAt: number; // Unix milliseconds
+  missing: boolean; // The directory no longer exists (ListFavorites)
+}
+
+// File write that Undo can revert (ListUndoableActions)
+export interface UndoAction {
+  id: string;
+  kind: string; // "ignore-rule" or "export"
+  description: string;
+  path: string;
+  created: boolean; // The file didn't exist before; undoing deletes it
+  createdAt: number; // Unix milliseconds
+}
+
+// Search of an S3 or GCS bucket prefix, run by SearchBucket
+export interface BucketSearchRequest {
+  url: string; // s3://bucket/prefix or gs://bucket/prefix
+  search: SearchRequest; // directory is ignored
+  concurrency?: number; // Objects downloaded at once (0 = default 4, at most 16)
+}
+
+// Files a search skipped while processing, by reason ("completed" event)
+export interface SkipStats {
+  generated: number;
+  vanished: number; // Deleted after collection
+  locked: number; // Held by another process
+  unreadable: number; // Permission denied or another read error
+}
+
+// Details of a CONFIRMATION_REQUIRED error from SearchWithProgress
+export interface QueryCostWarning {
+  reasons: string[]; // "LEADING_WILDCARD", "SHORT_LITERAL"
+  fileCount?: number; // Files collected, for the tree-size check
+}
+
+// Details of a LARGE_FILE_CONFIRMATION error from the open-in-editor calls
+export interface LargeFileWarning {
+  files: { path: string; size: number }[]; // Files over the limit, sizes in bytes
+  limitBytes: number;
+}
+
+// Payload of the "collection-progress" events sent while a search lists
+// its files.
+export interface CollectionProgress {
+  searchId: string;
+  directory: string;
+  currentPath: string;
+  dirsVisited: number;
+  filesQueued: number; // Before binary files are dropped
+  elapsedMs: number;
 }
 
 export interface SearchProgress {
+  searchId?: string; // Search the event belongs to; unset only on the "idle" status
   processedFiles: number;
   totalFiles: number;
   currentFile: string;
   resultsCount: number;
-  status: string;
+  status: string; // "collecting", "started", "in-progress", "completed", or "cancelled"
+  sampled?: boolean; // Set on the "completed" event when the results are a sample
+  totalMatches?: number; // Matches found before sampling, or written to the result log
+  incomplete?: boolean; // Set on the "completed" event when the directory was removed mid-search
+  skipped?: SkipStats; // Set on the "completed" event
+}
+
+// File read by ReadFile for the preview modal
+export interface FileContent {
+  content: string; // UTF-8 text without a byte order mark
+  encoding: "utf-8" | "utf-8-bom" | "utf-16le" | "utf-16be"; // Detected from the BOM
+  lineEnding: "lf" | "crlf" | "cr" | ""; // Dominant line ending; empty without line breaks
+}
+
+// File description returned by GetFileInfo, read before the preview opens
+export interface FileInfo {
+  path: string;
+  size: number; // Bytes
+  modTime: number; // Unix milliseconds
+  mimeType: string;
+  encoding?: FileContent["encoding"]; // Unset for binary files
+  lineEnding?: FileContent["lineEnding"]; // From the first 1MB; unset for binary files
+  binary: boolean;
+  lineCount: number; // Estimated from the first 1MB unless lineCountExact
+  lineCountExact: boolean;
+  view: "text" | "hex" | "too-large";
+  hexPreview?: string; // Hex dump of the first 4KB, for the hex view
+}
+
+// Downscaled image returned by ReadFileThumbnail
+export interface Thumbnail {
+  data: string; // Base64 PNG
+  width: number;
+  height: number;
+  originalWidth: number;
+  originalHeight: number;
+  format: "png" | "jpeg" | "gif"; // Format of the source image
+}
+
+// Summary of a directory tree written by ExportTree
+export interface TreeExport {
+  path: string; // File written
+  files: number;
+  directories: number; // Directories below the root that hold files
+  totalSize: number; // Bytes
+  truncated: number; // Directories cut off at the per-directory file limit
+}
+
+// Window of lines around a match, returned by GetFileSlice for the inline preview
+export interface FileSlice {
+  filePath: string;
+  startLine: number; // Line number of lines[0] (1-indexed)
+  lines: string[];
+  matchIndex: number; // Index into lines of the requested center line
+  endOfFile: boolean;
+}
+
+// UI state persisted between runs (SaveSession / GetLastSession)
+export interface SessionState {
+  request: SearchRequest; // Last search form: directory, query, and filters
+  openFilePath: string; // File of the result open in the preview (empty if none)
+  openLineNum: number;
+  resultsScroll: number; // Scroll offsets in pixels
+  previewScroll: number;
+  savedAt: number; // Unix milliseconds, set by the backend
+}
+
+export interface SavedSearch {
+  name: string;
+  request: SearchRequest;
+}
+
+// Named group of roots, default filters, and saved searches
+export interface Workspace {
+  id: string; // Generated by the backend on create
+  name: string;
+  roots: string[];
+  defaultFilters: SearchRequest; // directory and query are ignored
+  savedSearches: SavedSearch[];
+  createdAt: number; // Unix milliseconds
+  updatedAt: number;
+}
+
+// One program ShowInFolder tried; the details of a NO_FILE_MANAGER error
+export interface FileManagerAttempt {
+  command: string;
+  found: boolean; // On PATH
+  error?: string;
+}
+
+// File type filter for SelectFile
+export interface FileFilter {
+  displayName: string;
+  pattern: string; // Semicolon-separated globs, e.g. "*.go;*.mod"
+}
+
+// Result of TestEditorLaunch
+export interface EditorDiagnostic {
+  editor: string;
+  command: string;
+  path: string; // Binary found; "" when not installed
+  source: "settings" | "discovered" | "path" | "";
+  args: string[]; // Version check arguments; empty when the editor has none
+  launched: boolean;
+  exitCode: number;
+  version: string;
+  output: string; // stdout and stderr of the check, up to 4KB
+  durationMs: number;
+  ok: boolean;
+  problem?: string; // EDITOR_NOT_FOUND, EDITOR_CHECK_FAILED, EDITOR_CHECK_EXIT, EDITOR_CHECK_TIMEOUT
+  message?: string;
 }
 
 // Interface for editor availability
@@ -60,6 +304,309 @@ export interface EditorAvailability {
   visualstudio: boolean;
   eclipse: boolean;
   netbeans: boolean;
+  nano: boolean;
+  helix: boolean;
+}
+
+// User settings persisted by the backend (GetSettings / UpdateSettings)
+export interface Settings {
+  notifyOnCompletion: boolean; // Desktop notification when a long search finishes
+  notifyMinSeconds: number; // Minimum search duration that triggers it (default 10)
+  hotkey: string; // Global shortcut that summons the window, e.g. "Ctrl+Shift+F" ("" disables)
+  streamingThreshold: number; // Default streaming threshold in bytes (1MB, 64KB–256MB)
+  scannerBufferSize: number; // Default scanner buffer in bytes (1MB, 64KB–64MB)
+  persistResults: boolean; // Keep completed searches in the result store (QueryResultStore)
+  metricsAddr?: string; // Loopback address serving /metrics, e.g. "127.0.0.1:9464" ("" disables)
+  logDir?: string; // Absolute directory for app.log, used from the next launch ("" means the per-OS default)
+  logFilters?: LogFilterRule[] | null; // Entries the log viewer hides (null restores the defaults, [] hides nothing)
+  checkUpdatesOnStartup?: boolean; // Look for a new release when the app starts
+  updateChannel?: 'stable' | 'beta'; // "beta" also offers pre-releases
+  defaultEditor: string; // Editor OpenResult uses: editor name, "JetBrains", or "SystemDefault"
+  editorPriority: string[]; // Fallback editors, in order, when the default isn't installed
+  editorCacheHours: number; // How long startup reuses the last editor detection (24, 1–720)
+  editorPaths: Record<string, string>; // Binaries of editors outside PATH, by editor name (SetEditorPath)
+  largeFileWarnMB: number; // Files above this size need confirmation before opening in an editor (100, 1–1048576)
+  hooks?: SearchHook[]; // Commands run before and after every search and at a result count
+}
+
+// Command run at one point of every search, with the search's details as JSON on stdin
+export interface SearchHook {
+  event: "pre-search" | "post-search" | "result-threshold";
+  command: string[]; // Program and arguments
+  threshold?: number; // result-threshold: result count that runs the hook, once per search
+}
+
+// Search kept in the result store (ListStoredSearches)
+export interface StoredSearch {
+  id: string;
+  request: SearchRequest;
+  resultCount: number;
+  totalMatches: number;
+  incomplete: boolean;
+  finishedAt: number; // Unix milliseconds
+}
+
+// Named extension set usable as "@name" in allowedFileTypes
+// (ListFileTypeBundles)
+export interface FileTypeBundle {
+  name: string;
+  description: string;
+  extensions: string[]; // Without the dot
+}
+
+// Saved search with {name} placeholders (ListTemplates / SaveTemplate / RunTemplate)
+export interface QueryTemplate {
+  id: string; // Empty for a new template; set by SaveTemplate
+  name: string;
+  description: string;
+  request: SearchRequest; // query and directory may contain {name} placeholders
+  variables: string[]; // Placeholder names, set by SaveTemplate
+  createdAt: number;
+  updatedAt: number;
+}
+
+// Full-text index built by IndexWorkspace
+export interface IndexInfo {
+  root: string;
+  files: number;
+  terms: number; // Distinct words
+  indexedAt: number; // Unix milliseconds
+}
+
+// Ranked answer of SearchIndexed
+export interface IndexedSearchResults {
+  hits: Array<{ filePath: string; score: number; lineNum: number; content: string }>; // Best first, at most 100
+  total: number; // Matching files, including those beyond hits
+  facets: Array<{ extension: string; count: number }>; // Matching files by extension
+}
+
+// What a search of a directory would walk (AnalyzeDirectory)
+export interface DirectoryAnalysis {
+  root: string;
+  files: number;
+  bytes: number;
+  lines: number; // Lines of the text files that were read
+  binaryFiles: number;
+  unreadFiles: number; // Over 64MB or unreadable; lines not counted
+  extensions: Array<{ extension: string; files: number; bytes: number; lines: number; binaryFiles: number }>; // Most files first
+  largestFiles: Array<{ filePath: string; size: number; lines: number }>; // Top 10
+  longestLines: Array<{ filePath: string; lineNum: number; length: number; preview: string }>; // Top 10, one per file
+}
+
+// Files without the expected license header (AuditLicenseHeaders)
+export interface LicenseAudit {
+  root: string;
+  filesChecked: number;
+  compliant: number;
+  missing: ResultGroup[]; // Each with the file's first line
+  mismatched: ResultGroup[]; // Each with the file's header comment lines
+  missingFiles: number; // Including files beyond the 1000 listed
+  mismatchedFiles: number;
+  truncated: boolean;
+}
+
+// External packages referenced by a project (ScanImports)
+export interface ImportScanReport {
+  root: string;
+  filesScanned: number;
+  packages: Array<{
+    name: string;
+    language: "go" | "javascript" | "python" | "java" | "ruby";
+    files: number; // Files importing it
+    imports: number; // Import statements referencing it
+    sampleFiles: string[]; // Up to five
+  }>; // Most importing files first
+}
+
+// Suspected credentials found by ScanSecrets; secrets are masked
+export interface SecretScanReport {
+  root: string;
+  filesScanned: number;
+  findings: Array<{
+    filePath: string;
+    lineNum: number;
+    rule: string; // e.g. "aws-access-key-id", "high-entropy-string"
+    ruleName: string;
+    match: string; // Masked secret
+    line: string; // Line with every secret masked
+    entropy?: number; // Bits per character, for high-entropy strings
+  }>;
+  truncated: boolean; // More than 1000 findings
+  durationMs: number;
+}
+
+// Files a search would skip or miss (ScanFilesystemIssues)
+export interface FilesystemReport {
+  root: string;
+  filesScanned: number;
+  issues: Array<{ path: string; kind: "broken-symlink" | "empty-file" | "unreadable"; detail: string }>; // By kind, then path
+  counts: Record<string, number>; // Issues per kind, including those beyond the cap
+  truncated: boolean; // Some kind had more than 1000 issues
+}
+
+// Answer to a QueryResultStore filter
+export interface StoreQueryResult {
+  results: Array<{ searchId: string; query: string; result: SearchResult }>;
+  files: string[]; // Distinct files of results, sorted
+  truncated: boolean; // More than 5000 results matched
+}
+
+// Grouped view of a completed search returned by FilterResults
+export interface ResultGroup {
+  filePath: string;
+  count: number;
+  matchCount: number; // Matches found in the file; larger than count for a sampled search
+  results: SearchResult[];
+}
+
+export interface FilteredResults {
+  searchId: string;
+  groups: ResultGroup[];
+  totalResults: number;
+  totalFiles: number;
+  hiddenResults: number;
+  hiddenFiles: number;
+  sampled: boolean;
+  totalMatches: number;
+  incomplete: boolean; // The search directory was removed before the search finished
+}
+
+// One file of a search for the results pane (GetFileMatches)
+export interface FileMatches {
+  searchId: string;
+  filePath: string;
+  matches: SearchResult[];
+  ranges: LineRange[]; // Fewest line runs showing every match with context; empty for bucket objects
+  stale: boolean; // The file changed since the search
+}
+
+export interface LineRange {
+  start: number; // 1-indexed
+  end: number; // Inclusive
+  lines: string[];
+}
+
+// Match GetAdjacentMatch moved to (n/p navigation)
+export interface AdjacentMatch {
+  searchId: string;
+  index: number; // Position among all matches of the search; -1 when it has none
+  total: number;
+  wrapped: boolean; // Went past the last (or first) match and started over
+  result: SearchResult;
+}
+
+// Patch file written by CreateReplacePatch
+export interface ReplacePatch {
+  path: string; // Empty when the replacement changes nothing
+  root: string; // Directory to run git apply in
+  files: number;
+  lines: number;
+  dirtyFiles: string[]; // Patched files with uncommitted git changes
+}
+
+// Rename preview written by RenameIdentifier
+export interface RenamePreview {
+  path: string; // Empty when no occurrence was found
+  root: string; // Directory to run git apply in
+  files: number;
+  occurrences: RenameOccurrence[];
+  skipped: number; // Occurrences left alone inside comments and strings
+  dirtyFiles: string[]; // Patched files with uncommitted git changes
+}
+
+export interface RenameOccurrence {
+  filePath: string;
+  lineNum: number;
+  old: string; // The spelling found, e.g. get_user
+  new: string; // What replaces it, e.g. fetch_account
+  content: string; // The line after renaming, trimmed
+}
+
+// Matched files changed since a search finished (CheckResultsFreshness and
+// the "results-stale" event)
+export interface ResultsFreshness {
+  searchId: string;
+  stale: boolean;
+  changedFiles: string[];
+  deletedFiles: string[];
+}
+
+// Distinct values of a capture group across a search (AggregateCaptures)
+export interface CaptureReport {
+  searchId: string;
+  group: string; // Name or number of the group
+  values: {
+    value: string;
+    count: number; // Results the value was captured from
+    files: number; // Distinct files among them
+    examples: { filePath: string; lineNum: number }[]; // First few results
+  }[]; // Most frequent first
+  captured: number; // Results that captured the group
+  results: number; // Results of the search
+}
+
+// Search to pre-fill from the command line or a codesearch:// link
+// (GetLaunchRequest and the "launch-request" event)
+export interface LaunchRequest {
+  directory: string;
+  query: string;
+  request?: SearchRequest; // Every setting of a codesearch://search?r=... link
+}
+
+// Result of HandleDroppedPaths for paths dropped onto the window
+export interface DropResult {
+  roots: string[]; // Absolute, de-duplicated, outermost directories
+  rejected: Array<{ path: string; code: string; message: string }>;
+}
+
+// Shape of a rejected backend call. Branch on `code`; `message` is already
+// localized to the locale selected via SetLocale.
+export interface BackendError {
+  code: string; // e.g. "FILE_NOT_FOUND", "PATH_TRAVERSAL"; "UNKNOWN" for uncoded errors
+  message: string;
+}
+
+// Backend capability report used by first-run onboarding (GetCapabilities)
+export interface Capabilities {
+  os: string;
+  arch: string;
+  editors: EditorAvailability;
+  editorCount: number;
+  git: boolean;
+  ripgrep: boolean;
+  fileManager: boolean; // "Show in folder" works
+  defaultEditor: boolean; // "Open in default editor" works
+  longPaths: boolean;
+  ownerFilters: boolean; // ownedByMe / permissionMask are available
+}
+
+// Hides the log entries meeting all of its conditions (at least one is set)
+export interface LogFilterRule {
+  level?: string; // This level or more verbose, e.g. "debug" for debug and trace
+  message?: string; // Regular expression matched against the message
+  component?: string; // Value of the entry's component field, ignoring case
+}
+
+// Page of in-memory log entries returned by GetLogs
+export interface LogPage {
+  entries: Array<{ type: string; content: any }>; // Oldest first; content is a logrus JSON object or a plain line
+  total: number; // Matches in the whole buffer
+  hasMore: boolean; // Older matches exist beyond this page
+}
+
+// Result of CheckForUpdates, also the payload of the "update-available" event
+export interface UpdateInfo {
+  currentVersion: string; // "dev" for local builds
+  latestVersion: string; // "" if the channel has no release
+  channel: string;
+  updateAvailable: boolean; // Never set for local builds
+  releaseName: string;
+  releaseURL: string;
+  notes: string; // Markdown
+  publishedAt: string;
+  assets: Array<{ name: string; url: string; size: number }>;
+  asset: string; // Download for this platform, "" if none fits
+  checkedAt: number; // Unix milliseconds
 }
 
 export interface EditorDetectionStatus {
@@ -82,11 +629,23 @@ export interface SearchState {
   maxFileSize: number;
   maxResults: number;
   searchSubdirs: boolean;
+  // Search the working trees of git submodules
+  includeSubmodules?: boolean;
+  // Always search build and CI files
+  includeWellKnownFiles?: boolean;
+  // Match the query's identifiers in other naming conventions
+  expandIdentifiers?: boolean;
+  // Return the regex capture groups of each match
+  extractGroups?: boolean;
+  // Typos allowed in a literal query (0 = exact match)
+  fuzziness?: number;
   resultText: string;
   searchResults: SearchResult[];
   truncatedResults: boolean;
   isSearching: boolean;
   searchProgress: SearchProgress;
+  // ID of the last search the backend reported, for FilterResults
+  lastSearchId?: string;
   showProgress: boolean;
   minFileSize: number;
   excludePatterns: string[];
@@ -26,11 +26,95 @@ declare module '../../wailsjs/go/main/App' {
   export function OpenInNetBeans(filePath: string): Promise<void>;
   export function OpenInDefaultEditor(filePath: string): Promise<void>;
   export function ShowInFolder(filePath: string): Promise<void>;
-  export function ReadFile(filePath: string): Promise<string>;
+  export function ReadFile(filePath: string): Promise<any>;
+  export function GetFileInfo(filePath: string): Promise<any>;
+  export function ReadFileThumbnail(filePath: string, maxDim: number): Promise<any>;
+  export function ExportTree(root: string, outputPath: string, format: string, excludePatterns: string[]): Promise<any>;
+  export function GetFileSlice(filePath: string, centerLine: number, radius: number): Promise<any>;
+  export function ExpandContext(result: any, beforeN: number, afterN: number): Promise<any>;
   export function SearchWithProgress(searchRequest: any): Promise<any[]>;
   export function SelectDirectory(title: string): Promise<string>;
+  export function SelectFile(title: string, filters: any[]): Promise<string>;
+  export function SelectDirectories(title: string): Promise<string[]>;
+  export function SelectSavePath(title: string, defaultName: string, extension: string): Promise<string>;
   export function ValidateDirectory(directory: string): Promise<boolean>;
   export function GetAvailableEditors(): Promise<any>;
   export function GetEditorDetectionStatus(): Promise<any>;
   export function CancelSearch(): Promise<void>;
+  export function GetSearchProgress(searchId: string): Promise<any>;
+  export function GetCapabilities(): Promise<any>;
+  export function HandleDroppedPaths(paths: string[]): Promise<any>;
+  export function FilterResults(searchId: string, excludePaths: string[]): Promise<any>;
+  export function GetFileMatches(searchId: string, filePath: string): Promise<any>;
+  export function GetAdjacentMatch(searchId: string, currentIndex: number, direction: string): Promise<any>;
+  export function CheckResultsFreshness(searchId: string): Promise<any>;
+  export function CreateReplacePatch(searchId: string, replacement: string, preserveCase: boolean): Promise<any>;
+  export function RenameIdentifier(root: string, oldName: string, newName: string): Promise<any>;
+  export function SearchToFile(req: any, outputPath: string): Promise<number>;
+  export function SearchBucket(req: any): Promise<any[]>;
+  export function BatchSearch(reqs: any[]): Promise<any[]>;
+  export function SearchCooccurrence(req: any): Promise<any[]>;
+  export function ListPlugins(): Promise<any[]>;
+  export function EnablePlugin(name: string, enabled: boolean): Promise<any>;
+  export function AddFavorite(path: string, label: string, color: string): Promise<any>;
+  export function ListFavorites(): Promise<any[]>;
+  export function RemoveFavorite(path: string): Promise<void>;
+  export function ListUndoableActions(): Promise<any[]>;
+  export function Undo(actionId: string): Promise<void>;
+  export function ListStoredSearches(): Promise<any[]>;
+  export function DeleteStoredSearch(id: string): Promise<void>;
+  export function QueryResultStore(filter: string): Promise<any>;
+  export function IndexWorkspace(root: string): Promise<any>;
+  export function SearchIndexed(query: string): Promise<any>;
+  export function ListTemplates(): Promise<any[]>;
+  export function SaveTemplate(template: any): Promise<any>;
+  export function DeleteTemplate(id: string): Promise<void>;
+  export function RunTemplate(templateId: string, vars: Record<string, string>): Promise<any[]>;
+  export function ListBuiltinPresets(): Promise<any[]>;
+  export function ListFileTypeBundles(): Promise<any[]>;
+  export function ClonePreset(presetId: string, name: string): Promise<any>;
+  export function AnalyzeDirectory(root: string): Promise<any>;
+  export function ScanSecrets(root: string): Promise<any>;
+  export function ScanImports(root: string): Promise<any>;
+  export function AuditLicenseHeaders(root: string, expectedHeaderTemplate: string): Promise<any>;
+  export function ScanFilesystemIssues(root: string): Promise<any>;
+  export function ExportResultsAsQuickfix(searchId: string, path: string): Promise<string>;
+  export function GenerateReport(searchId: string, format: string, path: string): Promise<string>;
+  export function GenerateDiagnostics(path: string): Promise<string>;
+  export function GetLogFilePath(): Promise<string>;
+  export function GetLogTail(lines: number): Promise<string>;
+  export function GetLogs(offset: number, limit: number, minLevel: string, search: string): Promise<any>;
+  export function CheckForUpdates(): Promise<any>;
+  export function DownloadUpdate(asset: string, path: string): Promise<string>;
+  export function OpenQuickfixInEditor(editorId: string): Promise<void>;
+  export function RefreshEditorDetection(): Promise<any>;
+  export function SetEditorPath(name: string, path: string): Promise<any>;
+  export function TestEditorLaunch(editorId: string): Promise<any>;
+  export function ConfirmLargeFileOpen(filePath: string): Promise<void>;
+  export function OpenProjectInEditor(editorId: string, filePath: string): Promise<string>;
+  export function OpenResult(result: any): Promise<string>;
+  export function AggregateCaptures(searchId: string, group: string): Promise<any>;
+  export function GetIgnoreRules(root: string): Promise<string[]>;
+  export function AddIgnoreRule(root: string, pattern: string): Promise<string[]>;
+  export function GetRemoteLink(filePath: string, line: number): Promise<string>;
+  export function FormatResult(result: any, template: string): Promise<string>;
+  export function OpenResultsInEditor(editorId: string, results: any[], limit: number): Promise<number>;
+  export function GetLaunchRequest(): Promise<any>;
+  export function SerializeSearchRequest(req: any): Promise<string>;
+  export function ParseSearchRequest(encoded: string): Promise<any>;
+  export function RegisterShellIntegration(): Promise<void>;
+  export function UnregisterShellIntegration(): Promise<void>;
+  export function GetSettings(): Promise<any>;
+  export function UpdateSettings(settings: any): Promise<any>;
+  export function SetLocale(locale: string): Promise<void>;
+  export function GetLocale(): Promise<string>;
+  export function GetSupportedLocales(): Promise<string[]>;
+  export function GetLastSession(): Promise<any>;
+  export function SaveSession(state: any): Promise<void>;
+  export function ListWorkspaces(): Promise<any[]>;
+  export function CreateWorkspace(workspace: any): Promise<any>;
+  export function UpdateWorkspace(workspace: any): Promise<any>;
+  export function DeleteWorkspace(id: string): Promise<void>;
+  export function SwitchWorkspace(id: string): Promise<any>;
+  export function GetActiveWorkspace(): Promise<any>;
 }
\ No newline at end of file
@@ -45,11 +45,6 @@ export const handleEditorSelect = async (event: Event, filePath: string) => {
   if (!editor) return;
 
   try {
-    if (filePath.endsWith(".log")) {
-      const { ReadFileLog } = await import("../../wailsjs/go/main/App");
-      filePath = await ReadFileLog(filePath);
-    }
-
     const { openInEditor } = await import("./searchUiUtils");
     await openInEditor(
       editor,
@@ -70,3 +65,10 @@ export const handleEditorSelect = async (event: Event, filePath: string) => {
     );
   }
 };
+
+// Open the app's own log (app.log in the backend's logs directory) in the
+// selected editor
+export const handleLogEditorSelect = async (event: Event) => {
+  const { GetLogFilePath } = await import("../../wailsjs/go/main/App");
+  await handleEditorSelect(event, await GetLogFilePath());
+};
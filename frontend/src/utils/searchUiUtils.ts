@@ -4,7 +4,7 @@
  */
 
 import DOMPurify from "dompurify";
-import { SearchState } from "../types/search";
+import { MatchSpan, SearchState } from "../types/search";
 
 /**
  * Highlights matches in text by wrapping them in HTML mark tags.
@@ -94,6 +94,35 @@ export const highlightMatch = (
   }
 };
 
+/**
+ * Highlights the backend's match spans in a result's content. The spans
+ * are rune offsets, so the text is split into code points with Array.from:
+ * indexing the string directly would count UTF-16 units and cut emoji and
+ * other astral characters in half.
+ * @param text The result content the spans refer to
+ * @param spans SearchResult.spans, in order and non-overlapping
+ * @returns The text with each span wrapped in a mark tag
+ */
+export const highlightSpans = (text: string, spans: MatchSpan[]): string => {
+  if (!text) return "";
+  const chars = Array.from(text);
+  let result = "";
+  let pos = 0;
+  for (const span of spans) {
+    const start = Math.max(span.runeStart, pos);
+    const end = Math.min(span.runeEnd, chars.length);
+    if (start >= end) continue;
+    result += chars.slice(pos, start).join("");
+    result += `<mark class="highlight">${chars.slice(start, end).join("")}</mark>`;
+    pos = end;
+  }
+  result += chars.slice(pos).join("");
+  return DOMPurify.sanitize(result, {
+    ALLOWED_TAGS: ["mark"],
+    ALLOWED_ATTR: ["class"],
+  });
+};
+
 // editorBindingName maps the frontend editor keys (emitted by EditorSelect.vue)
 // to the binding names expected by the backend's OpenInEditorByName dispatcher.
 // The backend's editorBindings map (system_integration.go) uses these exact
@@ -129,6 +158,8 @@ const editorBindingName: Record<string, string> = {
   netbeans: "NetBeans",
   neovim: "Neovim",
   vim: "Vim",
+  nano: "Nano",
+  helix: "Helix",
 };
 
 const editorDisplayName: Record<string, string> = {
@@ -156,6 +187,8 @@ const editorDisplayName: Record<string, string> = {
   netbeans: "NetBeans",
   neovim: "Neovim",
   vim: "Vim",
+  nano: "Nano",
+  helix: "Helix",
   default: "Default Editor",
 };
 
@@ -229,6 +262,19 @@ export const openInEditor = async (
     setResultText(`File opened in ${displayName}: ${filePath}`);
   } catch (error: any) {
     const displayName = editorDisplayName[editorKey] || editorKey;
+    // A file over the largeFileWarnMB setting needs the user's go-ahead;
+    // once confirmed, the backend lets it through for the rest of the session.
+    if (error?.code === "LARGE_FILE_CONFIRMATION") {
+      if (window.confirm(error.message)) {
+        const { ConfirmLargeFileOpen } = await import("../../wailsjs/go/main/App");
+        for (const file of error.details?.files ?? []) {
+          await ConfirmLargeFileOpen(file.path);
+        }
+        return openInEditor(editorKey, filePath, setResultText, setError);
+      }
+      setResultText(`Not opened in ${displayName}: ${filePath}`);
+      return;
+    }
     console.error(`Failed to open file in ${displayName}:`, error);
     setResultText(
       `Could not open file in ${displayName}: ${error.message || "Operation failed"}`,
@@ -8,7 +8,7 @@
 // The mock includes:
 //   - The generic OpenInEditorByName dispatcher (the frontend's primary path)
 //   - OpenInDefaultEditor (the "default" editor key's special case)
-//   - ReadFileLog (used by fileUtils.ts for .log files)
+//   - GetLogFilePath (used by fileUtils.ts to open app.log)
 //   - All individual OpenIn* methods (for backward compatibility — older
 //     tests or code paths may still reference them directly)
 import { vi } from "vitest";
@@ -17,12 +17,80 @@ import { vi } from "vitest";
 export const SearchCode = vi.fn();
 export const GetDirectoryContents = vi.fn();
 export const SelectDirectory = vi.fn();
+export const SelectFile = vi.fn().mockResolvedValue("");
+export const SelectDirectories = vi.fn().mockResolvedValue([]);
+export const SelectSavePath = vi.fn().mockResolvedValue("");
 export const ShowInFolder = vi.fn();
 export const SearchWithProgress = vi.fn().mockResolvedValue([]);
 export const CancelSearch = vi.fn();
+export const GetSearchProgress = vi.fn();
 export const ReadFile = vi.fn();
-export const ReadFileLog = vi.fn();
+export const GetFileInfo = vi.fn().mockResolvedValue({ view: "text", size: 0, lineCount: 0, binary: false });
+export const ReadFileThumbnail = vi.fn();
+export const GetFileSlice = vi.fn();
+export const ExpandContext = vi.fn();
+export const ExportTree = vi.fn();
+export const GetLogFilePath = vi.fn().mockResolvedValue("/tmp/code-search-golang/logs/app.log");
+export const GetLogTail = vi.fn().mockResolvedValue("");
 export const ValidateDirectory = vi.fn();
+export const FilterResults = vi.fn();
+export const GetAdjacentMatch = vi.fn();
+export const GetFileMatches = vi.fn();
+export const CreateReplacePatch = vi.fn();
+export const RenameIdentifier = vi.fn();
+export const CheckResultsFreshness = vi.fn().mockResolvedValue({ searchId: "", stale: false, changedFiles: [], deletedFiles: [] });
+export const SearchToFile = vi.fn().mockResolvedValue(0);
+export const SearchBucket = vi.fn().mockResolvedValue([]);
+export const BatchSearch = vi.fn().mockResolvedValue([]);
+export const SearchCooccurrence = vi.fn().mockResolvedValue([]);
+export const ListPlugins = vi.fn().mockResolvedValue([]);
+export const EnablePlugin = vi.fn();
+export const AddFavorite = vi.fn();
+export const ListFavorites = vi.fn().mockResolvedValue([]);
+export const RemoveFavorite = vi.fn();
+export const ListUndoableActions = vi.fn().mockResolvedValue([]);
+export const Undo = vi.fn();
+export const ListStoredSearches = vi.fn().mockResolvedValue([]);
+export const DeleteStoredSearch = vi.fn();
+export const QueryResultStore = vi.fn().mockResolvedValue({ results: [], files: [], truncated: false });
+export const IndexWorkspace = vi.fn();
+export const SearchIndexed = vi.fn().mockResolvedValue({ hits: [], total: 0, facets: [] });
+export const ListTemplates = vi.fn().mockResolvedValue([]);
+export const SaveTemplate = vi.fn();
+export const DeleteTemplate = vi.fn();
+export const RunTemplate = vi.fn().mockResolvedValue([]);
+export const ListBuiltinPresets = vi.fn().mockResolvedValue([]);
+export const ListFileTypeBundles = vi.fn().mockResolvedValue([]);
+export const ClonePreset = vi.fn().mockResolvedValue({});
+export const AnalyzeDirectory = vi.fn().mockResolvedValue({ files: 0, extensions: [], largestFiles: [], longestLines: [] });
+export const ScanSecrets = vi.fn().mockResolvedValue({ filesScanned: 0, findings: [], truncated: false });
+export const ScanImports = vi.fn().mockResolvedValue({ filesScanned: 0, packages: [] });
+export const AuditLicenseHeaders = vi.fn().mockResolvedValue({ filesChecked: 0, compliant: 0, missing: [], mismatched: [] });
+export const ScanFilesystemIssues = vi.fn().mockResolvedValue({ filesScanned: 0, issues: [], counts: {}, truncated: false });
+export const ExportResultsAsQuickfix = vi.fn().mockResolvedValue("/tmp/code-search-quickfix.txt");
+export const GenerateReport = vi.fn().mockResolvedValue("/tmp/report.html");
+export const GenerateDiagnostics = vi.fn().mockResolvedValue("/tmp/code-search-diagnostics.zip");
+export const CheckForUpdates = vi.fn().mockResolvedValue({ currentVersion: "dev", latestVersion: "", channel: "stable", updateAvailable: false, assets: [], asset: "" });
+export const DownloadUpdate = vi.fn().mockResolvedValue("/tmp/code-search-update");
+export const OpenQuickfixInEditor = vi.fn();
+export const RefreshEditorDetection = vi.fn().mockResolvedValue({});
+export const SetEditorPath = vi.fn().mockResolvedValue({});
+export const TestEditorLaunch = vi.fn().mockResolvedValue({ ok: true });
+export const ConfirmLargeFileOpen = vi.fn();
+export const OpenProjectInEditor = vi.fn().mockResolvedValue("/tmp/project");
+export const OpenResult = vi.fn().mockResolvedValue("SystemDefault");
+export const AggregateCaptures = vi.fn().mockResolvedValue({ values: [], captured: 0, results: 0 });
+export const GetIgnoreRules = vi.fn().mockResolvedValue([]);
+export const AddIgnoreRule = vi.fn().mockResolvedValue([]);
+export const GetRemoteLink = vi.fn().mockResolvedValue("");
+export const FormatResult = vi.fn().mockResolvedValue("");
+export const OpenResultsInEditor = vi.fn().mockResolvedValue(0);
+export const HandleDroppedPaths = vi.fn().mockResolvedValue({ roots: [], rejected: [] });
+export const GetLaunchRequest = vi.fn().mockResolvedValue({ directory: "", query: "" });
+export const SerializeSearchRequest = vi.fn().mockResolvedValue("");
+export const ParseSearchRequest = vi.fn();
+export const RegisterShellIntegration = vi.fn();
+export const UnregisterShellIntegration = vi.fn();
 export const GetEditorDetectionStatus = vi.fn();
 export const GetAvailableEditors = vi.fn();
 // Sample known-text extension list returned by the backend. The real
@@ -36,7 +104,34 @@ export const GetKnownTextExtensions = vi.fn().mockResolvedValue([
 ]);
 export const GetInitialLogs = vi.fn().mockResolvedValue([]);
 export const GetNewLogs = vi.fn().mockResolvedValue([]);
+export const GetLogs = vi.fn().mockResolvedValue({ entries: [], total: 0, hasMore: false });
 export const IsAppReady = vi.fn().mockResolvedValue(true);
+export const GetCapabilities = vi.fn().mockResolvedValue({});
+
+// User settings
+export const GetSettings = vi.fn().mockResolvedValue({
+  notifyOnCompletion: false,
+  notifyMinSeconds: 10,
+  hotkey: "",
+});
+export const UpdateSettings = vi.fn();
+
+// Error message locale
+export const SetLocale = vi.fn().mockResolvedValue(undefined);
+export const GetLocale = vi.fn().mockResolvedValue("en");
+export const GetSupportedLocales = vi.fn().mockResolvedValue(["en", "id"]);
+
+// Session restore
+export const GetLastSession = vi.fn().mockResolvedValue({});
+export const SaveSession = vi.fn().mockResolvedValue(undefined);
+
+// Workspaces
+export const ListWorkspaces = vi.fn().mockResolvedValue([]);
+export const CreateWorkspace = vi.fn();
+export const UpdateWorkspace = vi.fn();
+export const DeleteWorkspace = vi.fn().mockResolvedValue(undefined);
+export const SwitchWorkspace = vi.fn();
+export const GetActiveWorkspace = vi.fn().mockResolvedValue({});
 
 // Generic editor dispatcher — the frontend's primary path for opening files
 // in named editors. Calls the backend's OpenInEditorByName(name, filePath)
@@ -22,9 +22,9 @@ import {
 // Track the wrappers so afterEach can unmount them
 let wrappers: ReturnType<typeof mount>[] = [];
 
-// Mock fileUtils handleEditorSelect
+// Mock fileUtils handleLogEditorSelect
 vi.mock("../../../src/utils/fileUtils", () => ({
-  handleEditorSelect: vi.fn(),
+  handleLogEditorSelect: vi.fn(),
 }));
 
 const mockData = {
@@ -304,13 +304,13 @@ describe("LogViewer.vue", () => {
       expect(result!.level).toBe("INFO");
     });
 
-    test("parseLogEntry skips entries with 'Skipping' in message", () => {
+    test("parseLogEntry keeps entries the backend let through", () => {
       const result = parseLogEntry({
         type: "log",
         content: { msg: "Skipping hidden directory" },
       });
 
-      expect(result).toBeNull();
+      expect(result.message).toBe("Skipping hidden directory");
     });
 
     test("parseLogEntry handles plain text content", () => {
@@ -30,22 +30,24 @@ describe("parseLogEntry", () => {
     expect(result!.timestamp).toBeDefined();
   });
 
-  test("skips entries with 'Skipping' in message", () => {
+  // Noise filtering is the backend's job (the logFilters setting), so an
+  // entry the backend let through is always shown.
+  test("keeps entries with 'Skipping' in message", () => {
     const result = parseLogEntry({
       type: "log",
       content: { msg: "Skipping hidden directory" },
     });
 
-    expect(result).toBeNull();
+    expect(result.message).toBe("Skipping hidden directory");
   });
 
-  test("skips entries with 'Sending file' in message", () => {
+  test("keeps entries with 'Sending file' in message", () => {
     const result = parseLogEntry({
       type: "log",
       content: "Sending file progress: foo.go",
     });
 
-    expect(result).toBeNull();
+    expect(result.message).toBe("Sending file progress: foo.go");
   });
 
   test("handles plain text content", () => {
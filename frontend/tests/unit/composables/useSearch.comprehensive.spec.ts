@@ -47,9 +47,10 @@ describe("useSearch composable", () => {
     (AppModule.CancelSearch as any).mockResolvedValue(
       undefined,
     );
-    (AppModule.ReadFile as any).mockResolvedValue(
-      "file content",
-    );
+    (AppModule.ReadFile as any).mockResolvedValue({
+      content: "file content",
+      encoding: "utf-8",
+    });
     (AppModule.ValidateDirectory as any).mockResolvedValue(
       true,
     );
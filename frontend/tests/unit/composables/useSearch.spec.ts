@@ -39,7 +39,7 @@ describe('useSearch composable', () => {
     (AppModule.SelectDirectory as any).mockResolvedValue('/selected/directory');
     (AppModule.ShowInFolder as any).mockResolvedValue(undefined);
     (AppModule.CancelSearch as any).mockResolvedValue(undefined);
-    (AppModule.ReadFile as any).mockResolvedValue('file content');
+    (AppModule.ReadFile as any).mockResolvedValue({ content: 'file content', encoding: 'utf-8' });
     (AppModule.ValidateDirectory as any).mockResolvedValue(true);
     
     // Mock EventsOn to return a cleanup function
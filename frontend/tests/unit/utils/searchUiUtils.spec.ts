@@ -1,5 +1,5 @@
 import { describe, test, expect, vi, beforeEach } from "vitest";
-import { highlightMatch, openInEditor } from "../../../src/utils/searchUiUtils";
+import { highlightMatch, highlightSpans, openInEditor } from "../../../src/utils/searchUiUtils";
 import type { SearchState } from "../../../src/types/search";
 import { makeDefaultEditorAvailability } from "../../../src/composables/useEditorDetection";
 
@@ -216,6 +216,23 @@ describe("highlightMatch", () => {
   });
 });
 
+describe("highlightSpans", () => {
+  test("should mark rune spans after multibyte characters", () => {
+    // "🔍 検索 query": the emoji is two UTF-16 units but one rune.
+    const result = highlightSpans("🔍 検索 query", [
+      { start: 5, end: 11, runeStart: 2, runeEnd: 4 },
+      { start: 12, end: 17, runeStart: 5, runeEnd: 10 },
+    ]);
+    expect(result).toBe(
+      '🔍 <mark class="highlight">検索</mark> <mark class="highlight">query</mark>',
+    );
+  });
+
+  test("should ignore spans past the end of the text", () => {
+    expect(highlightSpans("abc", [{ start: 5, end: 6, runeStart: 5, runeEnd: 6 }])).toBe("abc");
+  });
+});
+
 describe("openInEditor", () => {
   const setResultText = vi.fn();
   const setError = vi.fn();
@@ -333,6 +350,7 @@ describe("openInEditor", () => {
       "phpstorm", "clion", "rider", "androidstudio", "emacs",
       "neovide", "codeblocks", "devcpp", "notepadplusplus",
       "visualstudio", "eclipse", "netbeans", "neovim", "vim",
+      "nano", "helix",
       "default", // special case — handled by OpenInDefaultEditor
     ];
 
@@ -2,22 +2,124 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AddFavorite(arg1:string,arg2:string,arg3:string):Promise<main.Favorite>;
+
+export function AddIgnoreRule(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function AggregateCaptures(arg1:string,arg2:string):Promise<main.CaptureReport>;
+
+export function AnalyzeDirectory(arg1:string):Promise<main.DirectoryAnalysis>;
+
+export function AuditLicenseHeaders(arg1:string,arg2:string):Promise<main.LicenseAudit>;
+
+export function BatchSearch(arg1:Array<main.SearchRequest>):Promise<Array<main.BatchResult>>;
+
 export function CancelSearch():Promise<void>;
 
+export function CheckForUpdates():Promise<main.UpdateInfo>;
+
+export function CheckResultsFreshness(arg1:string):Promise<main.ResultsFreshness>;
+
+export function ClonePreset(arg1:string,arg2:string):Promise<main.QueryTemplate>;
+
+export function ConfirmLargeFileOpen(arg1:string):Promise<void>;
+
+export function CreateReplacePatch(arg1:string,arg2:string,arg3:boolean):Promise<main.ReplacePatch>;
+
+export function CreateWorkspace(arg1:main.Workspace):Promise<main.Workspace>;
+
+export function DeleteStoredSearch(arg1:string):Promise<void>;
+
+export function DeleteTemplate(arg1:string):Promise<void>;
+
+export function DeleteWorkspace(arg1:string):Promise<void>;
+
+export function DownloadUpdate(arg1:string,arg2:string):Promise<string>;
+
+export function EnablePlugin(arg1:string,arg2:boolean):Promise<main.PluginInfo>;
+
+export function ExpandContext(arg1:main.SearchResult,arg2:number,arg3:number):Promise<main.SearchResult>;
+
+export function ExportResultsAsQuickfix(arg1:string,arg2:string):Promise<string>;
+
+export function ExportTree(arg1:string,arg2:string,arg3:string,arg4:Array<string>):Promise<main.TreeExport>;
+
+export function FilterResults(arg1:string,arg2:Array<string>):Promise<main.FilteredResults>;
+
+export function FormatResult(arg1:main.SearchResult,arg2:string):Promise<string>;
+
+export function GenerateDiagnostics(arg1:string):Promise<string>;
+
+export function GenerateReport(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function GetActiveWorkspace():Promise<main.Workspace>;
+
+export function GetAdjacentMatch(arg1:string,arg2:number,arg3:string):Promise<main.AdjacentMatch>;
+
 export function GetAvailableEditors():Promise<main.EditorAvailability>;
 
+export function GetCapabilities():Promise<main.Capabilities>;
+
 export function GetDirectoryContents(arg1:string):Promise<Array<string>>;
 
 export function GetEditorDetectionStatus():Promise<Record<string, any>>;
 
+export function GetFileInfo(arg1:string):Promise<main.FileInfo>;
+
+export function GetFileMatches(arg1:string,arg2:string):Promise<main.FileMatches>;
+
+export function GetFileSlice(arg1:string,arg2:number,arg3:number):Promise<main.FileSlice>;
+
+export function GetIgnoreRules(arg1:string):Promise<Array<string>>;
+
 export function GetInitialLogs():Promise<Array<main.LogMessage>>;
 
 export function GetKnownTextExtensions():Promise<Array<string>>;
 
+export function GetLastSession():Promise<main.SessionState>;
+
+export function GetLaunchRequest():Promise<main.LaunchRequest>;
+
+export function GetLocale():Promise<string>;
+
+export function GetLogFilePath():Promise<string>;
+
+export function GetLogTail(arg1:number):Promise<string>;
+
+export function GetLogs(arg1:number,arg2:number,arg3:string,arg4:string):Promise<main.LogPage>;
+
 export function GetNewLogs():Promise<Array<main.LogMessage>>;
 
+export function GetRemoteLink(arg1:string,arg2:number):Promise<string>;
+
+export function GetSearchProgress(arg1:string):Promise<main.SearchProgress>;
+
+export function GetSettings():Promise<main.Settings>;
+
+export function GetSupportedLocales():Promise<Array<string>>;
+
+export function HandleDroppedPaths(arg1:Array<string>):Promise<main.DropResult>;
+
+export function IndexWorkspace(arg1:string):Promise<main.IndexInfo>;
+
 export function IsAppReady():Promise<boolean>;
 
+export function ListBuiltinPresets():Promise<Array<main.QueryTemplate>>;
+
+export function ListFavorites():Promise<Array<main.Favorite>>;
+
+export function ListFileTypeBundles():Promise<Array<main.FileTypeBundle>>;
+
+export function ListPlugins():Promise<Array<main.PluginInfo>>;
+
+export function ListStoredSearches():Promise<Array<main.StoredSearch>>;
+
+export function ListTemplates():Promise<Array<main.QueryTemplate>>;
+
+export function ListUndoableActions():Promise<Array<main.UndoAction>>;
+
+export function ListWorkspaces():Promise<Array<main.Workspace>>;
+
 export function OpenInAndroidStudio(arg1:string):Promise<void>;
 
 export function OpenInAtom(arg1:string):Promise<void>;
@@ -70,14 +172,78 @@ export function OpenInVisualStudio(arg1:string):Promise<void>;
 
 export function OpenInWebStorm(arg1:string):Promise<void>;
 
-export function ReadFile(arg1:string):Promise<string>;
+export function OpenProjectInEditor(arg1:string,arg2:string):Promise<string>;
+
+export function OpenQuickfixInEditor(arg1:string):Promise<void>;
+
+export function OpenResult(arg1:main.SearchResult):Promise<string>;
+
+export function OpenResultsInEditor(arg1:string,arg2:Array<main.SearchResult>,arg3:number):Promise<number>;
+
+export function ParseSearchRequest(arg1:string):Promise<main.SearchRequest>;
+
+export function QueryResultStore(arg1:string):Promise<main.StoreQueryResult>;
+
+export function ReadFile(arg1:string):Promise<main.FileContent>;
+
+export function ReadFileThumbnail(arg1:string,arg2:number):Promise<main.Thumbnail>;
+
+export function RefreshEditorDetection():Promise<main.EditorAvailability>;
+
+export function RegisterShellIntegration():Promise<void>;
+
+export function RemoveFavorite(arg1:string):Promise<void>;
+
+export function RenameIdentifier(arg1:string,arg2:string,arg3:string):Promise<main.RenamePreview>;
+
+export function RunTemplate(arg1:string,arg2:Record<string, string>):Promise<Array<main.SearchResult>>;
 
-export function ReadFileLog(arg1:string):Promise<string>;
+export function SaveSession(arg1:main.SessionState):Promise<void>;
+
+export function SaveTemplate(arg1:main.QueryTemplate):Promise<main.QueryTemplate>;
+
+export function ScanFilesystemIssues(arg1:string):Promise<main.FilesystemReport>;
+
+export function ScanImports(arg1:string):Promise<main.ImportScanReport>;
+
+export function ScanSecrets(arg1:string):Promise<main.SecretScanReport>;
+
+export function SearchBucket(arg1:main.BucketSearchRequest):Promise<Array<main.SearchResult>>;
+
+export function SearchCooccurrence(arg1:main.CooccurrenceRequest):Promise<Array<main.CooccurrenceFile>>;
+
+export function SearchIndexed(arg1:string):Promise<main.IndexedSearchResults>;
+
+export function SearchToFile(arg1:main.SearchRequest,arg2:string):Promise<number>;
 
 export function SearchWithProgress(arg1:main.SearchRequest):Promise<Array<main.SearchResult>>;
 
+export function SelectDirectories(arg1:string):Promise<Array<string>>;
+
 export function SelectDirectory(arg1:string):Promise<string>;
 
+export function SelectFile(arg1:string,arg2:Array<main.FileFilter>):Promise<string>;
+
+export function SelectSavePath(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function SerializeSearchRequest(arg1:main.SearchRequest):Promise<string>;
+
+export function SetEditorPath(arg1:string,arg2:string):Promise<main.EditorAvailability>;
+
+export function SetLocale(arg1:string):Promise<void>;
+
 export function ShowInFolder(arg1:string):Promise<void>;
 
+export function SwitchWorkspace(arg1:string):Promise<main.Workspace>;
+
+export function TestEditorLaunch(arg1:string):Promise<main.EditorDiagnostic>;
+
+export function Undo(arg1:string):Promise<void>;
+
+export function UnregisterShellIntegration():Promise<void>;
+
+export function UpdateSettings(arg1:main.Settings):Promise<main.Settings>;
+
+export function UpdateWorkspace(arg1:main.Workspace):Promise<main.Workspace>;
+
 export function ValidateDirectory(arg1:string):Promise<boolean>;
@@ -2,14 +2,122 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddFavorite(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddFavorite'](arg1, arg2, arg3);
+}
+
+export function AddIgnoreRule(arg1, arg2) {
+  return window['go']['main']['App']['AddIgnoreRule'](arg1, arg2);
+}
+
+export function AggregateCaptures(arg1, arg2) {
+  return window['go']['main']['App']['AggregateCaptures'](arg1, arg2);
+}
+
+export function AnalyzeDirectory(arg1) {
+  return window['go']['main']['App']['AnalyzeDirectory'](arg1);
+}
+
+export function AuditLicenseHeaders(arg1, arg2) {
+  return window['go']['main']['App']['AuditLicenseHeaders'](arg1, arg2);
+}
+
+export function BatchSearch(arg1) {
+  return window['go']['main']['App']['BatchSearch'](arg1);
+}
+
 export function CancelSearch() {
   return window['go']['main']['App']['CancelSearch']();
 }
 
+export function CheckForUpdates() {
+  return window['go']['main']['App']['CheckForUpdates']();
+}
+
+export function CheckResultsFreshness(arg1) {
+  return window['go']['main']['App']['CheckResultsFreshness'](arg1);
+}
+
+export function ClonePreset(arg1, arg2) {
+  return window['go']['main']['App']['ClonePreset'](arg1, arg2);
+}
+
+export function ConfirmLargeFileOpen(arg1) {
+  return window['go']['main']['App']['ConfirmLargeFileOpen'](arg1);
+}
+
+export function CreateReplacePatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateReplacePatch'](arg1, arg2, arg3);
+}
+
+export function CreateWorkspace(arg1) {
+  return window['go']['main']['App']['CreateWorkspace'](arg1);
+}
+
+export function DeleteStoredSearch(arg1) {
+  return window['go']['main']['App']['DeleteStoredSearch'](arg1);
+}
+
+export function DeleteTemplate(arg1) {
+  return window['go']['main']['App']['DeleteTemplate'](arg1);
+}
+
+export function DeleteWorkspace(arg1) {
+  return window['go']['main']['App']['DeleteWorkspace'](arg1);
+}
+
+export function DownloadUpdate(arg1, arg2) {
+  return window['go']['main']['App']['DownloadUpdate'](arg1, arg2);
+}
+
+export function EnablePlugin(arg1, arg2) {
+  return window['go']['main']['App']['EnablePlugin'](arg1, arg2);
+}
+
+export function ExpandContext(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExpandContext'](arg1, arg2, arg3);
+}
+
+export function ExportResultsAsQuickfix(arg1, arg2) {
+  return window['go']['main']['App']['ExportResultsAsQuickfix'](arg1, arg2);
+}
+
+export function ExportTree(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportTree'](arg1, arg2, arg3, arg4);
+}
+
+export function FilterResults(arg1, arg2) {
+  return window['go']['main']['App']['FilterResults'](arg1, arg2);
+}
+
+export function FormatResult(arg1, arg2) {
+  return window['go']['main']['App']['FormatResult'](arg1, arg2);
+}
+
+export function GenerateDiagnostics(arg1) {
+  return window['go']['main']['App']['GenerateDiagnostics'](arg1);
+}
+
+export function GenerateReport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GenerateReport'](arg1, arg2, arg3);
+}
+
+export function GetActiveWorkspace() {
+  return window['go']['main']['App']['GetActiveWorkspace']();
+}
+
+export function GetAdjacentMatch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetAdjacentMatch'](arg1, arg2, arg3);
+}
+
 export function GetAvailableEditors() {
   return window['go']['main']['App']['GetAvailableEditors']();
 }
 
+export function GetCapabilities() {
+  return window['go']['main']['App']['GetCapabilities']();
+}
+
 export function GetDirectoryContents(arg1) {
   return window['go']['main']['App']['GetDirectoryContents'](arg1);
 }
@@ -18,6 +126,22 @@ export function GetEditorDetectionStatus() {
   return window['go']['main']['App']['GetEditorDetectionStatus']();
 }
 
+export function GetFileInfo(arg1) {
+  return window['go']['main']['App']['GetFileInfo'](arg1);
+}
+
+export function GetFileMatches(arg1, arg2) {
+  return window['go']['main']['App']['GetFileMatches'](arg1, arg2);
+}
+
+export function GetFileSlice(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetFileSlice'](arg1, arg2, arg3);
+}
+
+export function GetIgnoreRules(arg1) {
+  return window['go']['main']['App']['GetIgnoreRules'](arg1);
+}
+
 export function GetInitialLogs() {
   return window['go']['main']['App']['GetInitialLogs']();
 }
@@ -26,14 +150,94 @@ export function GetKnownTextExtensions() {
   return window['go']['main']['App']['GetKnownTextExtensions']();
 }
 
+export function GetLastSession() {
+  return window['go']['main']['App']['GetLastSession']();
+}
+
+export function GetLaunchRequest() {
+  return window['go']['main']['App']['GetLaunchRequest']();
+}
+
+export function GetLocale() {
+  return window['go']['main']['App']['GetLocale']();
+}
+
+export function GetLogFilePath() {
+  return window['go']['main']['App']['GetLogFilePath']();
+}
+
+export function GetLogTail(arg1) {
+  return window['go']['main']['App']['GetLogTail'](arg1);
+}
+
+export function GetLogs(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetLogs'](arg1, arg2, arg3, arg4);
+}
+
 export function GetNewLogs() {
   return window['go']['main']['App']['GetNewLogs']();
 }
 
+export function GetRemoteLink(arg1, arg2) {
+  return window['go']['main']['App']['GetRemoteLink'](arg1, arg2);
+}
+
+export function GetSearchProgress(arg1) {
+  return window['go']['main']['App']['GetSearchProgress'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSupportedLocales() {
+  return window['go']['main']['App']['GetSupportedLocales']();
+}
+
+export function HandleDroppedPaths(arg1) {
+  return window['go']['main']['App']['HandleDroppedPaths'](arg1);
+}
+
+export function IndexWorkspace(arg1) {
+  return window['go']['main']['App']['IndexWorkspace'](arg1);
+}
+
 export function IsAppReady() {
   return window['go']['main']['App']['IsAppReady']();
 }
 
+export function ListBuiltinPresets() {
+  return window['go']['main']['App']['ListBuiltinPresets']();
+}
+
+export function ListFavorites() {
+  return window['go']['main']['App']['ListFavorites']();
+}
+
+export function ListFileTypeBundles() {
+  return window['go']['main']['App']['ListFileTypeBundles']();
+}
+
+export function ListPlugins() {
+  return window['go']['main']['App']['ListPlugins']();
+}
+
+export function ListStoredSearches() {
+  return window['go']['main']['App']['ListStoredSearches']();
+}
+
+export function ListTemplates() {
+  return window['go']['main']['App']['ListTemplates']();
+}
+
+export function ListUndoableActions() {
+  return window['go']['main']['App']['ListUndoableActions']();
+}
+
+export function ListWorkspaces() {
+  return window['go']['main']['App']['ListWorkspaces']();
+}
+
 export function OpenInAndroidStudio(arg1) {
   return window['go']['main']['App']['OpenInAndroidStudio'](arg1);
 }
@@ -138,26 +342,154 @@ export function OpenInWebStorm(arg1) {
   return window['go']['main']['App']['OpenInWebStorm'](arg1);
 }
 
+export function OpenProjectInEditor(arg1, arg2) {
+  return window['go']['main']['App']['OpenProjectInEditor'](arg1, arg2);
+}
+
+export function OpenQuickfixInEditor(arg1) {
+  return window['go']['main']['App']['OpenQuickfixInEditor'](arg1);
+}
+
+export function OpenResult(arg1) {
+  return window['go']['main']['App']['OpenResult'](arg1);
+}
+
+export function OpenResultsInEditor(arg1, arg2, arg3) {
+  return window['go']['main']['App']['OpenResultsInEditor'](arg1, arg2, arg3);
+}
+
+export function ParseSearchRequest(arg1) {
+  return window['go']['main']['App']['ParseSearchRequest'](arg1);
+}
+
+export function QueryResultStore(arg1) {
+  return window['go']['main']['App']['QueryResultStore'](arg1);
+}
+
 export function ReadFile(arg1) {
   return window['go']['main']['App']['ReadFile'](arg1);
 }
 
-export function ReadFileLog(arg1) {
-  return window['go']['main']['App']['ReadFileLog'](arg1);
+export function ReadFileThumbnail(arg1, arg2) {
+  return window['go']['main']['App']['ReadFileThumbnail'](arg1, arg2);
+}
+
+export function RefreshEditorDetection() {
+  return window['go']['main']['App']['RefreshEditorDetection']();
+}
+
+export function RegisterShellIntegration() {
+  return window['go']['main']['App']['RegisterShellIntegration']();
+}
+
+export function RemoveFavorite(arg1) {
+  return window['go']['main']['App']['RemoveFavorite'](arg1);
+}
+
+export function RenameIdentifier(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RenameIdentifier'](arg1, arg2, arg3);
+}
+
+export function RunTemplate(arg1, arg2) {
+  return window['go']['main']['App']['RunTemplate'](arg1, arg2);
+}
+
+export function SaveSession(arg1) {
+  return window['go']['main']['App']['SaveSession'](arg1);
+}
+
+export function SaveTemplate(arg1) {
+  return window['go']['main']['App']['SaveTemplate'](arg1);
+}
+
+export function ScanFilesystemIssues(arg1) {
+  return window['go']['main']['App']['ScanFilesystemIssues'](arg1);
+}
+
+export function ScanImports(arg1) {
+  return window['go']['main']['App']['ScanImports'](arg1);
+}
+
+export function ScanSecrets(arg1) {
+  return window['go']['main']['App']['ScanSecrets'](arg1);
+}
+
+export function SearchBucket(arg1) {
+  return window['go']['main']['App']['SearchBucket'](arg1);
+}
+
+export function SearchCooccurrence(arg1) {
+  return window['go']['main']['App']['SearchCooccurrence'](arg1);
+}
+
+export function SearchIndexed(arg1) {
+  return window['go']['main']['App']['SearchIndexed'](arg1);
+}
+
+export function SearchToFile(arg1, arg2) {
+  return window['go']['main']['App']['SearchToFile'](arg1, arg2);
 }
 
 export function SearchWithProgress(arg1) {
   return window['go']['main']['App']['SearchWithProgress'](arg1);
 }
 
+export function SelectDirectories(arg1) {
+  return window['go']['main']['App']['SelectDirectories'](arg1);
+}
+
 export function SelectDirectory(arg1) {
   return window['go']['main']['App']['SelectDirectory'](arg1);
 }
 
+export function SelectFile(arg1, arg2) {
+  return window['go']['main']['App']['SelectFile'](arg1, arg2);
+}
+
+export function SelectSavePath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SelectSavePath'](arg1, arg2, arg3);
+}
+
+export function SerializeSearchRequest(arg1) {
+  return window['go']['main']['App']['SerializeSearchRequest'](arg1);
+}
+
+export function SetEditorPath(arg1, arg2) {
+  return window['go']['main']['App']['SetEditorPath'](arg1, arg2);
+}
+
+export function SetLocale(arg1) {
+  return window['go']['main']['App']['SetLocale'](arg1);
+}
+
 export function ShowInFolder(arg1) {
   return window['go']['main']['App']['ShowInFolder'](arg1);
 }
 
+export function SwitchWorkspace(arg1) {
+  return window['go']['main']['App']['SwitchWorkspace'](arg1);
+}
+
+export function TestEditorLaunch(arg1) {
+  return window['go']['main']['App']['TestEditorLaunch'](arg1);
+}
+
+export function Undo(arg1) {
+  return window['go']['main']['App']['Undo'](arg1);
+}
+
+export function UnregisterShellIntegration() {
+  return window['go']['main']['App']['UnregisterShellIntegration']();
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function UpdateWorkspace(arg1) {
+  return window['go']['main']['App']['UpdateWorkspace'](arg1);
+}
+
 export function ValidateDirectory(arg1) {
   return window['go']['main']['App']['ValidateDirectory'](arg1);
 }
@@ -1,5 +1,257 @@
 export namespace main {
 	
+	export class MatchSpan {
+	    start: number;
+	    end: number;
+	    runeStart: number;
+	    runeEnd: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MatchSpan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.runeStart = source["runeStart"];
+	        this.runeEnd = source["runeEnd"];
+	    }
+	}
+	export class SearchResult {
+	    filePath: string;
+	    lineNum: number;
+	    content: string;
+	    matchedText: string;
+	    contextBefore: string[];
+	    contextAfter: string[];
+	    captures?: Record<string, string>;
+	    spans?: MatchSpan[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.lineNum = source["lineNum"];
+	        this.content = source["content"];
+	        this.matchedText = source["matchedText"];
+	        this.contextBefore = source["contextBefore"];
+	        this.contextAfter = source["contextAfter"];
+	        this.captures = source["captures"];
+	        this.spans = this.convertValues(source["spans"], MatchSpan);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AdjacentMatch {
+	    searchId: string;
+	    index: number;
+	    total: number;
+	    wrapped: boolean;
+	    result: SearchResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new AdjacentMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.index = source["index"];
+	        this.total = source["total"];
+	        this.wrapped = source["wrapped"];
+	        this.result = this.convertValues(source["result"], SearchResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BatchResult {
+	    query: string;
+	    results: SearchResult[];
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.query = source["query"];
+	        this.results = this.convertValues(source["results"], SearchResult);
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchRequest {
+	    directory: string;
+	    query: string;
+	    extension: string;
+	    caseSensitive: boolean;
+	    includeBinary: boolean;
+	    maxFileSize: number;
+	    minFileSize: number;
+	    maxResults: number;
+	    searchSubdirs: boolean;
+	    useRegex?: boolean;
+	    excludePatterns: string[];
+	    allowedFileTypes: string[];
+	    includePaths: string[];
+	    excludePaths: string[];
+	    skipGenerated: boolean;
+	    maxFilesPerDir: number;
+	    slowFs: boolean;
+	    sampling: boolean;
+	    samplingThreshold: number;
+	    confirmExpensive: boolean;
+	    retryLocked: boolean;
+	    streamingThreshold: number;
+	    scannerBufferSize: number;
+	    includeSubmodules: boolean;
+	    includeWellKnownFiles: boolean;
+	    expandIdentifiers: boolean;
+	    fuzziness: number;
+	    extractGroups: boolean;
+	    resultLogPath: string;
+	    invertFileMatch: boolean;
+	    headLines: number;
+	    regionStart: string;
+	    regionEnd: string;
+	    modifiedAfter: number;
+	    modifiedBefore: number;
+	    ownedByMe: boolean;
+	    permissionMask: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.directory = source["directory"];
+	        this.query = source["query"];
+	        this.extension = source["extension"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.includeBinary = source["includeBinary"];
+	        this.maxFileSize = source["maxFileSize"];
+	        this.minFileSize = source["minFileSize"];
+	        this.maxResults = source["maxResults"];
+	        this.searchSubdirs = source["searchSubdirs"];
+	        this.useRegex = source["useRegex"];
+	        this.excludePatterns = source["excludePatterns"];
+	        this.allowedFileTypes = source["allowedFileTypes"];
+	        this.includePaths = source["includePaths"];
+	        this.excludePaths = source["excludePaths"];
+	        this.skipGenerated = source["skipGenerated"];
+	        this.maxFilesPerDir = source["maxFilesPerDir"];
+	        this.slowFs = source["slowFs"];
+	        this.sampling = source["sampling"];
+	        this.samplingThreshold = source["samplingThreshold"];
+	        this.confirmExpensive = source["confirmExpensive"];
+	        this.retryLocked = source["retryLocked"];
+	        this.streamingThreshold = source["streamingThreshold"];
+	        this.scannerBufferSize = source["scannerBufferSize"];
+	        this.includeSubmodules = source["includeSubmodules"];
+	        this.includeWellKnownFiles = source["includeWellKnownFiles"];
+	        this.expandIdentifiers = source["expandIdentifiers"];
+	        this.fuzziness = source["fuzziness"];
+	        this.extractGroups = source["extractGroups"];
+	        this.resultLogPath = source["resultLogPath"];
+	        this.invertFileMatch = source["invertFileMatch"];
+	        this.headLines = source["headLines"];
+	        this.regionStart = source["regionStart"];
+	        this.regionEnd = source["regionEnd"];
+	        this.modifiedAfter = source["modifiedAfter"];
+	        this.modifiedBefore = source["modifiedBefore"];
+	        this.ownedByMe = source["ownedByMe"];
+	        this.permissionMask = source["permissionMask"];
+	    }
+	}
+	export class BucketSearchRequest {
+	    url: string;
+	    search: SearchRequest;
+	    concurrency: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketSearchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.search = this.convertValues(source["search"], SearchRequest);
+	        this.concurrency = source["concurrency"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class EditorAvailability {
 	    vscode: boolean;
 	    vscodium: boolean;
@@ -26,6 +278,8 @@ export namespace main {
 	    visualstudio: boolean;
 	    eclipse: boolean;
 	    netbeans: boolean;
+	    nano: boolean;
+	    helix: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new EditorAvailability(source);
@@ -58,78 +312,1757 @@ export namespace main {
 	        this.visualstudio = source["visualstudio"];
 	        this.eclipse = source["eclipse"];
 	        this.netbeans = source["netbeans"];
+	        this.nano = source["nano"];
+	        this.helix = source["helix"];
 	    }
 	}
-	export class LogMessage {
-	    type: string;
-	    content: any;
+	export class Capabilities {
+	    os: string;
+	    arch: string;
+	    editors: EditorAvailability;
+	    editorCount: number;
+	    git: boolean;
+	    ripgrep: boolean;
+	    fileManager: boolean;
+	    defaultEditor: boolean;
+	    longPaths: boolean;
+	    ownerFilters: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new LogMessage(source);
+	        return new Capabilities(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.type = source["type"];
-	        this.content = source["content"];
+	        this.os = source["os"];
+	        this.arch = source["arch"];
+	        this.editors = this.convertValues(source["editors"], EditorAvailability);
+	        this.editorCount = source["editorCount"];
+	        this.git = source["git"];
+	        this.ripgrep = source["ripgrep"];
+	        this.fileManager = source["fileManager"];
+	        this.defaultEditor = source["defaultEditor"];
+	        this.longPaths = source["longPaths"];
+	        this.ownerFilters = source["ownerFilters"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class SearchRequest {
-	    directory: string;
-	    query: string;
-	    extension: string;
-	    caseSensitive: boolean;
-	    includeBinary: boolean;
-	    maxFileSize: number;
-	    minFileSize: number;
-	    maxResults: number;
-	    searchSubdirs: boolean;
-	    useRegex?: boolean;
-	    excludePatterns: string[];
-	    allowedFileTypes: string[];
+	export class CaptureLocation {
+	    filePath: string;
+	    lineNum: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchRequest(source);
+	        return new CaptureLocation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.directory = source["directory"];
-	        this.query = source["query"];
-	        this.extension = source["extension"];
-	        this.caseSensitive = source["caseSensitive"];
-	        this.includeBinary = source["includeBinary"];
-	        this.maxFileSize = source["maxFileSize"];
-	        this.minFileSize = source["minFileSize"];
-	        this.maxResults = source["maxResults"];
-	        this.searchSubdirs = source["searchSubdirs"];
-	        this.useRegex = source["useRegex"];
-	        this.excludePatterns = source["excludePatterns"];
-	        this.allowedFileTypes = source["allowedFileTypes"];
+	        this.filePath = source["filePath"];
+	        this.lineNum = source["lineNum"];
 	    }
 	}
-	export class SearchResult {
+	export class CaptureValue {
+	    value: string;
+	    count: number;
+	    files: number;
+	    examples: CaptureLocation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureValue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.count = source["count"];
+	        this.files = source["files"];
+	        this.examples = this.convertValues(source["examples"], CaptureLocation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CaptureReport {
+	    searchId: string;
+	    group: string;
+	    values: CaptureValue[];
+	    captured: number;
+	    results: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.group = source["group"];
+	        this.values = this.convertValues(source["values"], CaptureValue);
+	        this.captured = source["captured"];
+	        this.results = source["results"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CooccurrencePair {
+	    patternA: number;
+	    patternB: number;
+	    matchA: SearchResult;
+	    matchB: SearchResult;
+	    distance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CooccurrencePair(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.patternA = source["patternA"];
+	        this.patternB = source["patternB"];
+	        this.matchA = this.convertValues(source["matchA"], SearchResult);
+	        this.matchB = this.convertValues(source["matchB"], SearchResult);
+	        this.distance = source["distance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CooccurrenceFile {
+	    filePath: string;
+	    pairs: CooccurrencePair[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CooccurrenceFile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.pairs = this.convertValues(source["pairs"], CooccurrencePair);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class CooccurrenceRequest {
+	    search: SearchRequest;
+	    patterns: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CooccurrenceRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.search = this.convertValues(source["search"], SearchRequest);
+	        this.patterns = source["patterns"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LineStat {
 	    filePath: string;
 	    lineNum: number;
-	    content: string;
-	    matchedText: string;
-	    contextBefore: string[];
-	    contextAfter: string[];
+	    length: number;
+	    preview: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SearchResult(source);
+	        return new LineStat(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.filePath = source["filePath"];
 	        this.lineNum = source["lineNum"];
-	        this.content = source["content"];
-	        this.matchedText = source["matchedText"];
-	        this.contextBefore = source["contextBefore"];
-	        this.contextAfter = source["contextAfter"];
+	        this.length = source["length"];
+	        this.preview = source["preview"];
+	    }
+	}
+	export class FileStat {
+	    filePath: string;
+	    size: number;
+	    lines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.size = source["size"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class ExtensionStats {
+	    extension: string;
+	    files: number;
+	    bytes: number;
+	    lines: number;
+	    binaryFiles: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExtensionStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.extension = source["extension"];
+	        this.files = source["files"];
+	        this.bytes = source["bytes"];
+	        this.lines = source["lines"];
+	        this.binaryFiles = source["binaryFiles"];
+	    }
+	}
+	export class DirectoryAnalysis {
+	    root: string;
+	    files: number;
+	    bytes: number;
+	    lines: number;
+	    binaryFiles: number;
+	    unreadFiles: number;
+	    extensions: ExtensionStats[];
+	    largestFiles: FileStat[];
+	    longestLines: LineStat[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DirectoryAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.files = source["files"];
+	        this.bytes = source["bytes"];
+	        this.lines = source["lines"];
+	        this.binaryFiles = source["binaryFiles"];
+	        this.unreadFiles = source["unreadFiles"];
+	        this.extensions = this.convertValues(source["extensions"], ExtensionStats);
+	        this.largestFiles = this.convertValues(source["largestFiles"], FileStat);
+	        this.longestLines = this.convertValues(source["longestLines"], LineStat);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RejectedPath {
+	    path: string;
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RejectedPath(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class DropResult {
+	    roots: string[];
+	    rejected: RejectedPath[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DropResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.roots = source["roots"];
+	        this.rejected = this.convertValues(source["rejected"], RejectedPath);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class EditorDiagnostic {
+	    editor: string;
+	    command: string;
+	    path: string;
+	    source: string;
+	    args: string[];
+	    launched: boolean;
+	    exitCode: number;
+	    version: string;
+	    output: string;
+	    durationMs: number;
+	    ok: boolean;
+	    problem?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorDiagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.editor = source["editor"];
+	        this.command = source["command"];
+	        this.path = source["path"];
+	        this.source = source["source"];
+	        this.args = source["args"];
+	        this.launched = source["launched"];
+	        this.exitCode = source["exitCode"];
+	        this.version = source["version"];
+	        this.output = source["output"];
+	        this.durationMs = source["durationMs"];
+	        this.ok = source["ok"];
+	        this.problem = source["problem"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ExtensionFacet {
+	    extension: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExtensionFacet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.extension = source["extension"];
+	        this.count = source["count"];
 	    }
 	}
+	
+	export class Favorite {
+	    path: string;
+	    label: string;
+	    color: string;
+	    addedAt: number;
+	    missing: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Favorite(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	        this.color = source["color"];
+	        this.addedAt = source["addedAt"];
+	        this.missing = source["missing"];
+	    }
+	}
+	export class FileContent {
+	    content: string;
+	    encoding: string;
+	    lineEnding: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.encoding = source["encoding"];
+	        this.lineEnding = source["lineEnding"];
+	    }
+	}
+	export class FileFilter {
+	    displayName: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.displayName = source["displayName"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class FileInfo {
+	    path: string;
+	    size: number;
+	    modTime: number;
+	    mimeType: string;
+	    encoding?: string;
+	    binary: boolean;
+	    lineEnding?: string;
+	    lineCount: number;
+	    lineCountExact: boolean;
+	    view: string;
+	    hexPreview?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modTime = source["modTime"];
+	        this.mimeType = source["mimeType"];
+	        this.encoding = source["encoding"];
+	        this.binary = source["binary"];
+	        this.lineEnding = source["lineEnding"];
+	        this.lineCount = source["lineCount"];
+	        this.lineCountExact = source["lineCountExact"];
+	        this.view = source["view"];
+	        this.hexPreview = source["hexPreview"];
+	    }
+	}
+	export class LineRange {
+	    start: number;
+	    end: number;
+	    lines: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LineRange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.lines = source["lines"];
+	    }
+	}
+	export class FileMatches {
+	    searchId: string;
+	    filePath: string;
+	    matches: SearchResult[];
+	    ranges: LineRange[];
+	    stale: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileMatches(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.filePath = source["filePath"];
+	        this.matches = this.convertValues(source["matches"], SearchResult);
+	        this.ranges = this.convertValues(source["ranges"], LineRange);
+	        this.stale = source["stale"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileSlice {
+	    filePath: string;
+	    startLine: number;
+	    lines: string[];
+	    matchIndex: number;
+	    endOfFile: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileSlice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.startLine = source["startLine"];
+	        this.lines = source["lines"];
+	        this.matchIndex = source["matchIndex"];
+	        this.endOfFile = source["endOfFile"];
+	    }
+	}
+	
+	export class FileTypeBundle {
+	    name: string;
+	    description: string;
+	    extensions: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FileTypeBundle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.extensions = source["extensions"];
+	    }
+	}
+	export class FilesystemIssue {
+	    path: string;
+	    kind: string;
+	    detail: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilesystemIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class FilesystemReport {
+	    root: string;
+	    filesScanned: number;
+	    issues: FilesystemIssue[];
+	    counts: Record<string, number>;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilesystemReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.filesScanned = source["filesScanned"];
+	        this.issues = this.convertValues(source["issues"], FilesystemIssue);
+	        this.counts = source["counts"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ResultGroup {
+	    filePath: string;
+	    count: number;
+	    matchCount: number;
+	    results: SearchResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.count = source["count"];
+	        this.matchCount = source["matchCount"];
+	        this.results = this.convertValues(source["results"], SearchResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FilteredResults {
+	    searchId: string;
+	    groups: ResultGroup[];
+	    totalResults: number;
+	    totalFiles: number;
+	    hiddenResults: number;
+	    hiddenFiles: number;
+	    sampled: boolean;
+	    totalMatches: number;
+	    incomplete: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilteredResults(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.groups = this.convertValues(source["groups"], ResultGroup);
+	        this.totalResults = source["totalResults"];
+	        this.totalFiles = source["totalFiles"];
+	        this.hiddenResults = source["hiddenResults"];
+	        this.hiddenFiles = source["hiddenFiles"];
+	        this.sampled = source["sampled"];
+	        this.totalMatches = source["totalMatches"];
+	        this.incomplete = source["incomplete"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportedPackage {
+	    name: string;
+	    language: string;
+	    files: number;
+	    imports: number;
+	    sampleFiles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportedPackage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.language = source["language"];
+	        this.files = source["files"];
+	        this.imports = source["imports"];
+	        this.sampleFiles = source["sampleFiles"];
+	    }
+	}
+	export class ImportScanReport {
+	    root: string;
+	    filesScanned: number;
+	    packages: ImportedPackage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportScanReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.filesScanned = source["filesScanned"];
+	        this.packages = this.convertValues(source["packages"], ImportedPackage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class IndexInfo {
+	    root: string;
+	    files: number;
+	    terms: number;
+	    indexedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.files = source["files"];
+	        this.terms = source["terms"];
+	        this.indexedAt = source["indexedAt"];
+	    }
+	}
+	export class IndexedHit {
+	    filePath: string;
+	    score: number;
+	    lineNum: number;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexedHit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.score = source["score"];
+	        this.lineNum = source["lineNum"];
+	        this.content = source["content"];
+	    }
+	}
+	export class IndexedSearchResults {
+	    hits: IndexedHit[];
+	    total: number;
+	    facets: ExtensionFacet[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexedSearchResults(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hits = this.convertValues(source["hits"], IndexedHit);
+	        this.total = source["total"];
+	        this.facets = this.convertValues(source["facets"], ExtensionFacet);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LaunchRequest {
+	    directory: string;
+	    query: string;
+	    request?: SearchRequest;
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.directory = source["directory"];
+	        this.query = source["query"];
+	        this.request = this.convertValues(source["request"], SearchRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LicenseAudit {
+	    root: string;
+	    filesChecked: number;
+	    compliant: number;
+	    missing: ResultGroup[];
+	    mismatched: ResultGroup[];
+	    missingFiles: number;
+	    mismatchedFiles: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LicenseAudit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.filesChecked = source["filesChecked"];
+	        this.compliant = source["compliant"];
+	        this.missing = this.convertValues(source["missing"], ResultGroup);
+	        this.mismatched = this.convertValues(source["mismatched"], ResultGroup);
+	        this.missingFiles = source["missingFiles"];
+	        this.mismatchedFiles = source["mismatchedFiles"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class LogFilterRule {
+	    level?: string;
+	    message?: string;
+	    component?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogFilterRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.level = source["level"];
+	        this.message = source["message"];
+	        this.component = source["component"];
+	    }
+	}
+	export class LogMessage {
+	    type: string;
+	    content: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogMessage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.content = source["content"];
+	    }
+	}
+	export class LogPage {
+	    entries: LogMessage[];
+	    total: number;
+	    hasMore: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], LogMessage);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PluginInfo {
+	    name: string;
+	    description: string;
+	    extensions: string[];
+	    builtin: boolean;
+	    path?: string;
+	    enabled: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PluginInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.extensions = source["extensions"];
+	        this.builtin = source["builtin"];
+	        this.path = source["path"];
+	        this.enabled = source["enabled"];
+	        this.error = source["error"];
+	    }
+	}
+	export class QueryTemplate {
+	    id: string;
+	    name: string;
+	    description: string;
+	    request: SearchRequest;
+	    variables: string[];
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QueryTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.request = this.convertValues(source["request"], SearchRequest);
+	        this.variables = source["variables"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RenameOccurrence {
+	    filePath: string;
+	    lineNum: number;
+	    old: string;
+	    new: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RenameOccurrence(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.lineNum = source["lineNum"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	        this.content = source["content"];
+	    }
+	}
+	export class RenamePreview {
+	    path: string;
+	    root: string;
+	    files: number;
+	    occurrences: RenameOccurrence[];
+	    skipped: number;
+	    dirtyFiles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RenamePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.root = source["root"];
+	        this.files = source["files"];
+	        this.occurrences = this.convertValues(source["occurrences"], RenameOccurrence);
+	        this.skipped = source["skipped"];
+	        this.dirtyFiles = source["dirtyFiles"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReplacePatch {
+	    path: string;
+	    root: string;
+	    files: number;
+	    lines: number;
+	    dirtyFiles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplacePatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.root = source["root"];
+	        this.files = source["files"];
+	        this.lines = source["lines"];
+	        this.dirtyFiles = source["dirtyFiles"];
+	    }
+	}
+	
+	export class ResultsFreshness {
+	    searchId: string;
+	    stale: boolean;
+	    changedFiles: string[];
+	    deletedFiles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ResultsFreshness(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.stale = source["stale"];
+	        this.changedFiles = source["changedFiles"];
+	        this.deletedFiles = source["deletedFiles"];
+	    }
+	}
+	export class SavedSearch {
+	    name: string;
+	    request: SearchRequest;
+	
+	    static createFrom(source: any = {}) {
+	        return new SavedSearch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.request = this.convertValues(source["request"], SearchRequest);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchHook {
+	    event: string;
+	    command: string[];
+	    threshold?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchHook(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.event = source["event"];
+	        this.command = source["command"];
+	        this.threshold = source["threshold"];
+	    }
+	}
+	export class SkipStats {
+	    generated: number;
+	    vanished: number;
+	    locked: number;
+	    unreadable: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SkipStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.generated = source["generated"];
+	        this.vanished = source["vanished"];
+	        this.locked = source["locked"];
+	        this.unreadable = source["unreadable"];
+	    }
+	}
+	export class SearchProgress {
+	    searchId: string;
+	    processedFiles: number;
+	    totalFiles: number;
+	    currentFile: string;
+	    resultsCount: number;
+	    status: string;
+	    sampled?: boolean;
+	    totalMatches?: number;
+	    incomplete?: boolean;
+	    skipped?: SkipStats;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.processedFiles = source["processedFiles"];
+	        this.totalFiles = source["totalFiles"];
+	        this.currentFile = source["currentFile"];
+	        this.resultsCount = source["resultsCount"];
+	        this.status = source["status"];
+	        this.sampled = source["sampled"];
+	        this.totalMatches = source["totalMatches"];
+	        this.incomplete = source["incomplete"];
+	        this.skipped = this.convertValues(source["skipped"], SkipStats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class SecretFinding {
+	    filePath: string;
+	    lineNum: number;
+	    rule: string;
+	    ruleName: string;
+	    match: string;
+	    line: string;
+	    entropy?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecretFinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.lineNum = source["lineNum"];
+	        this.rule = source["rule"];
+	        this.ruleName = source["ruleName"];
+	        this.match = source["match"];
+	        this.line = source["line"];
+	        this.entropy = source["entropy"];
+	    }
+	}
+	export class SecretScanReport {
+	    root: string;
+	    filesScanned: number;
+	    findings: SecretFinding[];
+	    truncated: boolean;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SecretScanReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.filesScanned = source["filesScanned"];
+	        this.findings = this.convertValues(source["findings"], SecretFinding);
+	        this.truncated = source["truncated"];
+	        this.durationMs = source["durationMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionState {
+	    request: SearchRequest;
+	    openFilePath: string;
+	    openLineNum: number;
+	    resultsScroll: number;
+	    previewScroll: number;
+	    savedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request = this.convertValues(source["request"], SearchRequest);
+	        this.openFilePath = source["openFilePath"];
+	        this.openLineNum = source["openLineNum"];
+	        this.resultsScroll = source["resultsScroll"];
+	        this.previewScroll = source["previewScroll"];
+	        this.savedAt = source["savedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Settings {
+	    notifyOnCompletion: boolean;
+	    notifyMinSeconds: number;
+	    hotkey: string;
+	    streamingThreshold: number;
+	    scannerBufferSize: number;
+	    persistResults: boolean;
+	    metricsAddr: string;
+	    logDir: string;
+	    checkUpdatesOnStartup: boolean;
+	    updateChannel: string;
+	    defaultEditor: string;
+	    editorPriority: string[];
+	    editorCacheHours: number;
+	    editorPaths: Record<string, string>;
+	    largeFileWarnMB: number;
+	    hooks: SearchHook[];
+	    logFilters: LogFilterRule[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.notifyOnCompletion = source["notifyOnCompletion"];
+	        this.notifyMinSeconds = source["notifyMinSeconds"];
+	        this.hotkey = source["hotkey"];
+	        this.streamingThreshold = source["streamingThreshold"];
+	        this.scannerBufferSize = source["scannerBufferSize"];
+	        this.persistResults = source["persistResults"];
+	        this.metricsAddr = source["metricsAddr"];
+	        this.logDir = source["logDir"];
+	        this.checkUpdatesOnStartup = source["checkUpdatesOnStartup"];
+	        this.updateChannel = source["updateChannel"];
+	        this.defaultEditor = source["defaultEditor"];
+	        this.editorPriority = source["editorPriority"];
+	        this.editorCacheHours = source["editorCacheHours"];
+	        this.editorPaths = source["editorPaths"];
+	        this.largeFileWarnMB = source["largeFileWarnMB"];
+	        this.hooks = this.convertValues(source["hooks"], SearchHook);
+	        this.logFilters = this.convertValues(source["logFilters"], LogFilterRule);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StoredResult {
+	    searchId: string;
+	    query: string;
+	    result: SearchResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoredResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.searchId = source["searchId"];
+	        this.query = source["query"];
+	        this.result = this.convertValues(source["result"], SearchResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StoreQueryResult {
+	    results: StoredResult[];
+	    files: string[];
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoreQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.results = this.convertValues(source["results"], StoredResult);
+	        this.files = source["files"];
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StoredSearch {
+	    id: string;
+	    request: SearchRequest;
+	    resultCount: number;
+	    totalMatches: number;
+	    incomplete: boolean;
+	    finishedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoredSearch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.request = this.convertValues(source["request"], SearchRequest);
+	        this.resultCount = source["resultCount"];
+	        this.totalMatches = source["totalMatches"];
+	        this.incomplete = source["incomplete"];
+	        this.finishedAt = source["finishedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Thumbnail {
+	    data: string;
+	    width: number;
+	    height: number;
+	    originalWidth: number;
+	    originalHeight: number;
+	    format: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Thumbnail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = source["data"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.originalWidth = source["originalWidth"];
+	        this.originalHeight = source["originalHeight"];
+	        this.format = source["format"];
+	    }
+	}
+	export class TreeExport {
+	    path: string;
+	    files: number;
+	    directories: number;
+	    totalSize: number;
+	    truncated: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TreeExport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.files = source["files"];
+	        this.directories = source["directories"];
+	        this.totalSize = source["totalSize"];
+	        this.truncated = source["truncated"];
+	    }
+	}
+	export class UndoAction {
+	    id: string;
+	    kind: string;
+	    description: string;
+	    path: string;
+	    created: boolean;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoAction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.description = source["description"];
+	        this.path = source["path"];
+	        this.created = source["created"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class UpdateAsset {
+	    name: string;
+	    url: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateAsset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.size = source["size"];
+	    }
+	}
+	export class UpdateInfo {
+	    currentVersion: string;
+	    latestVersion: string;
+	    channel: string;
+	    updateAvailable: boolean;
+	    releaseName: string;
+	    releaseURL: string;
+	    notes: string;
+	    publishedAt: string;
+	    assets: UpdateAsset[];
+	    asset: string;
+	    checkedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.currentVersion = source["currentVersion"];
+	        this.latestVersion = source["latestVersion"];
+	        this.channel = source["channel"];
+	        this.updateAvailable = source["updateAvailable"];
+	        this.releaseName = source["releaseName"];
+	        this.releaseURL = source["releaseURL"];
+	        this.notes = source["notes"];
+	        this.publishedAt = source["publishedAt"];
+	        this.assets = this.convertValues(source["assets"], UpdateAsset);
+	        this.asset = source["asset"];
+	        this.checkedAt = source["checkedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Workspace {
+	    id: string;
+	    name: string;
+	    roots: string[];
+	    defaultFilters: SearchRequest;
+	    savedSearches: SavedSearch[];
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Workspace(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.roots = source["roots"];
+	        this.defaultFilters = this.convertValues(source["defaultFilters"], SearchRequest);
+	        this.savedSearches = this.convertValues(source["savedSearches"], SavedSearch);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
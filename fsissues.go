@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of FilesystemIssue.
+const (
+	issueBrokenSymlink = "broken-symlink"
+	issueEmptyFile     = "empty-file"
+	issueUnreadable    = "unreadable"
+)
+
+// maxFilesystemIssues is how many issues of each kind ScanFilesystemIssues
+// returns. A tree with millions of empty files would otherwise produce a
+// report too large to show.
+const maxFilesystemIssues = 1000
+
+// checkFileIssues runs the per-file checks of a filesystem scan on a walked
+// file. info comes from the walk and describes the entry itself, not the
+// target of a symlink.
+func checkFileIssues(path string, info fs.FileInfo, report func(FilesystemIssue)) {
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if _, err := os.Stat(toLongPath(path)); err != nil {
+			target, _ := os.Readlink(toLongPath(path))
+			report(FilesystemIssue{Path: path, Kind: issueBrokenSymlink, Detail: target})
+		}
+		return
+	}
+	if !info.Mode().IsRegular() {
+		return
+	}
+	if info.Size() == 0 {
+		report(FilesystemIssue{Path: path, Kind: issueEmptyFile})
+	}
+	f, err := os.Open(toLongPath(path))
+	if err != nil {
+		// A file deleted since the walk saw it is not an issue.
+		if !errors.Is(err, fs.ErrNotExist) {
+			report(FilesystemIssue{Path: path, Kind: issueUnreadable, Detail: err.Error()})
+		}
+		return
+	}
+	f.Close()
+}
+
+// ScanFilesystemIssues walks root like a search does and reports what would
+// quietly go missing from results: broken symlinks, empty files, and files
+// or directories that can't be read. Hidden directories and
+// .codesearchignore rules are skipped as in a search. Each kind is capped
+// at 1000 entries; the counts include the ones beyond that.
+func (a *App) ScanFilesystemIssues(root string) (FilesystemReport, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return FilesystemReport{}, err
+	}
+
+	start := time.Now()
+	report := FilesystemReport{Root: absRoot, Issues: []FilesystemIssue{}, Counts: map[string]int{}}
+	var mu sync.Mutex
+	collect := func(issue FilesystemIssue) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Counts[issue.Kind]++
+		if report.Counts[issue.Kind] > maxFilesystemIssues {
+			report.Truncated = true
+			return
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	req := SearchRequest{
+		Directory:      absRoot,
+		SearchSubdirs:  true,
+		IncludeBinary:  true,
+		MaxFileSize:    math.MaxInt64,
+		MaxFilesPerDir: -1,
+	}
+	files, _, _, err := a.walkDirectoryTreeReporting(context.Background(), "", req, false, collect)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return FilesystemReport{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	report.FilesScanned = len(files)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Kind != report.Issues[j].Kind {
+			return report.Issues[i].Kind < report.Issues[j].Kind
+		}
+		return report.Issues[i].Path < report.Issues[j].Path
+	})
+	a.logInfo("Filesystem scanned for issues", logrus.Fields{
+		"root":            absRoot,
+		"files":           report.FilesScanned,
+		"issues":          report.Counts,
+		"durationSeconds": time.Since(start).Seconds(),
+	})
+	return report, nil
+}
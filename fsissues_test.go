@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestScanFilesystemIssues verifies that broken symlinks, empty files, and
+// unreadable files and directories are reported, and healthy ones are not.
+func TestScanFilesystemIssues(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	write := func(name, body string, perm os.FileMode) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), perm); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+		return path
+	}
+	target := write("ok.txt", "content\n", 0o644)
+	empty := write("sub/empty.go", "", 0o644)
+	write(".hidden/empty.txt", "", 0o644)
+
+	symlinks := true
+	if err := os.Symlink(target, filepath.Join(tempDir, "good-link")); err != nil {
+		symlinks = false // Creating symlinks needs a privilege on Windows
+	}
+	if symlinks {
+		if err := os.Symlink(filepath.Join(tempDir, "gone.txt"), filepath.Join(tempDir, "bad-link")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Permission bits don't stop root or Windows from reading.
+	permissions := os.Geteuid() > 0
+	if permissions {
+		write("secret.txt", "x\n", 0o000)
+		locked := filepath.Join(tempDir, "locked")
+		if err := os.Mkdir(locked, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		write("locked/inner.txt", "x\n", 0o644)
+		if err := os.Chmod(locked, 0o000); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(locked, 0o755) })
+	}
+
+	report, err := app.ScanFilesystemIssues(tempDir)
+	if err != nil {
+		t.Fatalf("ScanFilesystemIssues failed: %v", err)
+	}
+	found := make(map[string]string)
+	for _, issue := range report.Issues {
+		found[filepath.Base(issue.Path)] = issue.Kind
+	}
+
+	if found[filepath.Base(empty)] != issueEmptyFile || report.Counts[issueEmptyFile] != 1 {
+		t.Errorf("expected only sub/empty.go reported as empty, got %+v", report)
+	}
+	if _, ok := found["ok.txt"]; ok {
+		t.Errorf("expected ok.txt not to be reported, got %+v", report.Issues)
+	}
+	if symlinks {
+		if found["bad-link"] != issueBrokenSymlink {
+			t.Errorf("expected bad-link reported as a broken symlink, got %+v", report.Issues)
+		}
+		if _, ok := found["good-link"]; ok {
+			t.Errorf("expected good-link not to be reported, got %+v", report.Issues)
+		}
+	}
+	if permissions && (found["secret.txt"] != issueUnreadable || found["locked"] != issueUnreadable) {
+		t.Errorf("expected secret.txt and locked reported as unreadable, got %+v", report.Issues)
+	}
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fullTextIndexPrefix starts the data-directory file name of each root's
+// full-text index ("fulltext-<hash>.json").
+const fullTextIndexPrefix = "fulltext-"
+
+// maxIndexedTokenLength is the longest word that is indexed. Longer runs
+// are hashes, base64 blobs, and minified identifiers nobody searches for.
+const maxIndexedTokenLength = 64
+
+// maxIndexedHits caps the hits SearchIndexed returns, best first.
+const maxIndexedHits = 100
+
+// BM25 parameters: term-frequency saturation and length normalization.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fullTextIndex is the inverted index of one root. It is stored as JSON,
+// so its fields are exported to encoding/json only.
+type fullTextIndex struct {
+	Root      string                   `json:"root"`
+	IndexedAt int64                    `json:"indexedAt"` // Unix milliseconds
+	Docs      []indexedDoc             `json:"docs"`
+	Postings  map[string][]termPosting `json:"postings"` // Word -> documents containing it, by ascending Doc
+	AvgTokens float64                  `json:"avgTokens"`
+}
+
+// indexedDoc is one file of a fullTextIndex.
+type indexedDoc struct {
+	Path       string `json:"path"` // Absolute path
+	Ext        string `json:"ext"`  // Lowercased extension, "" if none
+	Tokens     int    `json:"tokens"`
+	LineStarts []int  `json:"lineStarts"` // Position of the first word of each line; LineStarts[i] is line i+1
+}
+
+// termPosting lists the positions of a word in one document. Positions
+// count words from the start of the file.
+type termPosting struct {
+	Doc       int   `json:"d"`
+	Positions []int `json:"p"`
+}
+
+// fullTextIndexFileName returns the data-directory file of root's index.
+func fullTextIndexFileName(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return fullTextIndexPrefix + hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// addDocument tokenizes content and adds it to the index.
+func (idx *fullTextIndex) addDocument(path string, content []byte) {
+	doc := indexedDoc{
+		Path: path,
+		Ext:  strings.ToLower(filepath.Ext(path)),
+	}
+	docID := len(idx.Docs)
+	pos := 0
+	for _, line := range splitLines(content) {
+		doc.LineStarts = append(doc.LineStarts, pos)
+		for _, word := range ftsTokens(string(line)) {
+			if len(word) > maxIndexedTokenLength {
+				continue
+			}
+			postings := idx.Postings[word]
+			if n := len(postings); n > 0 && postings[n-1].Doc == docID {
+				postings[n-1].Positions = append(postings[n-1].Positions, pos)
+			} else {
+				idx.Postings[word] = append(postings, termPosting{Doc: docID, Positions: []int{pos}})
+			}
+			pos++
+		}
+	}
+	doc.Tokens = pos
+	idx.Docs = append(idx.Docs, doc)
+}
+
+// lineOf returns the 1-indexed line of a word position in the document.
+func (d indexedDoc) lineOf(pos int) int {
+	return sort.Search(len(d.LineStarts), func(i int) bool { return d.LineStarts[i] > pos })
+}
+
+// indexQueryClause is one required part of a SearchIndexed query: a word,
+// a prefix (word*), or a quoted phrase.
+type indexQueryClause struct {
+	words  []string
+	prefix bool
+}
+
+// parseIndexQuery splits a query into clauses. Quoted text is a phrase; an
+// unterminated quote runs to the end of the query.
+func parseIndexQuery(query string) []indexQueryClause {
+	var clauses []indexQueryClause
+	for query != "" {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			break
+		}
+		if query[0] == '"' {
+			rest := query[1:]
+			phrase := rest
+			query = ""
+			if end := strings.IndexByte(rest, '"'); end >= 0 {
+				phrase, query = rest[:end], rest[end+1:]
+			}
+			if words := ftsTokens(phrase); len(words) > 0 {
+				clauses = append(clauses, indexQueryClause{words: words})
+			}
+			continue
+		}
+		field := query
+		if i := strings.IndexAny(query, " \t\""); i >= 0 {
+			field, query = query[:i], query[i:]
+		} else {
+			query = ""
+		}
+		prefix := strings.HasSuffix(field, "*")
+		words := ftsTokens(field)
+		for i, word := range words {
+			clauses = append(clauses, indexQueryClause{
+				words:  []string{word},
+				prefix: prefix && i == len(words)-1,
+			})
+		}
+	}
+	return clauses
+}
+
+// clauseHits returns, per matching document, the positions where the
+// clause matches and its BM25 score.
+func (idx *fullTextIndex) clauseHits(clause indexQueryClause) map[int]*indexHit {
+	hits := make(map[int]*indexHit)
+	n := float64(len(idx.Docs))
+
+	score := func(doc, tf, df int) float64 {
+		idf := math.Log(1 + (n-float64(df)+0.5)/(float64(df)+0.5))
+		length := float64(idx.Docs[doc].Tokens) / math.Max(idx.AvgTokens, 1)
+		t := float64(tf)
+		return idf * t * (bm25K1 + 1) / (t + bm25K1*(1-bm25B+bm25B*length))
+	}
+
+	if clause.prefix {
+		for word, postings := range idx.Postings {
+			if !strings.HasPrefix(word, clause.words[0]) {
+				continue
+			}
+			for _, p := range postings {
+				hit := hits[p.Doc]
+				if hit == nil {
+					hit = &indexHit{}
+					hits[p.Doc] = hit
+				}
+				hit.positions = append(hit.positions, p.Positions...)
+				hit.score += score(p.Doc, len(p.Positions), len(postings))
+			}
+		}
+		return hits
+	}
+
+	// A phrase matches where its first word is followed by the others in
+	// order; a single word is a one-word phrase.
+	first := idx.Postings[clause.words[0]]
+	for _, p := range first {
+		var matches []int
+		for _, start := range p.Positions {
+			if idx.phraseAt(p.Doc, start, clause.words[1:]) {
+				matches = append(matches, start)
+			}
+		}
+		if len(matches) > 0 {
+			df := len(first)
+			hits[p.Doc] = &indexHit{positions: matches, score: score(p.Doc, len(matches), df) * float64(len(clause.words))}
+		}
+	}
+	return hits
+}
+
+// phraseAt reports whether rest follows the word at start in doc.
+func (idx *fullTextIndex) phraseAt(doc, start int, rest []string) bool {
+	for i, word := range rest {
+		if !idx.hasPosition(word, doc, start+i+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPosition reports whether word occurs in doc at pos.
+func (idx *fullTextIndex) hasPosition(word string, doc, pos int) bool {
+	postings := idx.Postings[word]
+	i := sort.Search(len(postings), func(i int) bool { return postings[i].Doc >= doc })
+	if i == len(postings) || postings[i].Doc != doc {
+		return false
+	}
+	positions := postings[i].Positions
+	j := sort.SearchInts(positions, pos)
+	return j < len(positions) && positions[j] == pos
+}
+
+// indexHit is a document matching a query so far.
+type indexHit struct {
+	positions []int
+	score     float64
+}
+
+// search returns the documents matching every clause with their scores.
+func (idx *fullTextIndex) search(clauses []indexQueryClause) map[int]*indexHit {
+	var result map[int]*indexHit
+	for _, clause := range clauses {
+		hits := idx.clauseHits(clause)
+		if result == nil {
+			result = hits
+			continue
+		}
+		for doc, hit := range result {
+			other, ok := hits[doc]
+			if !ok {
+				delete(result, doc)
+				continue
+			}
+			hit.score += other.score
+			hit.positions = append(hit.positions, other.positions...)
+		}
+	}
+	return result
+}
+
+// IndexWorkspace builds the full-text index of root, replacing any earlier
+// index of it, and saves it in the data directory. Files are collected with
+// the search defaults: subdirectories, the 10MB size limit, ignore files,
+// and no binary or generated files.
+func (a *App) IndexWorkspace(root string) (IndexInfo, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return IndexInfo{}, err
+	}
+
+	start := time.Now()
+	req := SearchRequest{
+		Directory:      absRoot,
+		SearchSubdirs:  true,
+		SkipGenerated:  true,
+		MaxFileSize:    10 * 1024 * 1024,
+		MaxFilesPerDir: defaultMaxFilesPerDir,
+	}
+	files, err := a.collectFilesToProcess(req, nil, absRoot+string(filepath.Separator))
+	if err != nil {
+		return IndexInfo{}, newAppError(ErrCodeIndexFailed, absRoot, err)
+	}
+
+	idx := &fullTextIndex{
+		Root:      absRoot,
+		IndexedAt: time.Now().UnixMilli(),
+		Postings:  make(map[string][]termPosting),
+	}
+	total := 0
+	for _, meta := range files {
+		content, err := os.ReadFile(toLongPath(meta.absPath))
+		if err != nil {
+			a.logDebug("Skipping unreadable file while indexing", logrus.Fields{"filePath": meta.absPath, "error": err.Error()})
+			continue
+		}
+		if (meta.checkBinary && a.isBinary(content)) || looksGenerated(content) {
+			continue
+		}
+		idx.addDocument(meta.absPath, content)
+		total += idx.Docs[len(idx.Docs)-1].Tokens
+	}
+	if len(idx.Docs) > 0 {
+		idx.AvgTokens = float64(total) / float64(len(idx.Docs))
+	}
+
+	if a.dataDir != "" {
+		if err := a.saveJSON(fullTextIndexFileName(absRoot), idx); err != nil {
+			a.logError("Failed to save full-text index", err, logrus.Fields{"root": absRoot})
+			return IndexInfo{}, newAppError(ErrCodeIndexFailed, absRoot, err)
+		}
+	}
+
+	a.indexesMu.Lock()
+	a.loadFullTextIndexes()
+	a.indexes[absRoot] = idx
+	a.indexesMu.Unlock()
+
+	info := IndexInfo{Root: absRoot, Files: len(idx.Docs), Terms: len(idx.Postings), IndexedAt: idx.IndexedAt}
+	a.logInfo("Workspace indexed", logrus.Fields{
+		"root":            absRoot,
+		"files":           info.Files,
+		"terms":           info.Terms,
+		"durationSeconds": time.Since(start).Seconds(),
+	})
+	return info, nil
+}
+
+// loadFullTextIndexes reads the saved indexes on first use. Unreadable
+// index files are skipped. Callers must hold indexesMu.
+func (a *App) loadFullTextIndexes() {
+	if a.indexes != nil {
+		return
+	}
+	a.indexes = make(map[string]*fullTextIndex)
+	if a.dataDir == "" {
+		return
+	}
+	names, _ := filepath.Glob(filepath.Join(a.dataDir, fullTextIndexPrefix+"*.json"))
+	for _, name := range names {
+		var idx fullTextIndex
+		if _, err := a.loadJSON(filepath.Base(name), &idx); err != nil || idx.Root == "" {
+			a.logWarn("Skipping unreadable full-text index", logrus.Fields{"file": name})
+			continue
+		}
+		if idx.Postings == nil {
+			idx.Postings = make(map[string][]termPosting)
+		}
+		a.indexes[idx.Root] = &idx
+	}
+}
+
+// SearchIndexed searches every root indexed with IndexWorkspace. The query
+// is a list of words that must all appear in a file; "quoted words" must
+// appear as a phrase and word* matches any word with that prefix. Hits are
+// ranked by BM25 relevance, best first, and point at the first line where
+// the query matches. Facets count every matching file by extension.
+func (a *App) SearchIndexed(query string) (IndexedSearchResults, error) {
+	a.indexesMu.Lock()
+	defer a.indexesMu.Unlock()
+
+	a.loadFullTextIndexes()
+	if len(a.indexes) == 0 {
+		return IndexedSearchResults{}, newAppError(ErrCodeNoIndex)
+	}
+
+	out := IndexedSearchResults{Hits: []IndexedHit{}, Facets: []ExtensionFacet{}}
+	clauses := parseIndexQuery(query)
+	if len(clauses) == 0 {
+		return out, nil
+	}
+
+	type ranked struct {
+		doc   indexedDoc
+		hit   *indexHit
+		score float64
+	}
+	var all []ranked
+	facets := make(map[string]int)
+	for _, idx := range a.indexes {
+		for docID, hit := range idx.search(clauses) {
+			doc := idx.Docs[docID]
+			all = append(all, ranked{doc: doc, hit: hit, score: hit.score})
+			facets[doc.Ext]++
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].score != all[j].score {
+			return all[i].score > all[j].score
+		}
+		return all[i].doc.Path < all[j].doc.Path
+	})
+
+	out.Total = len(all)
+	for i, r := range all {
+		if i == maxIndexedHits {
+			break
+		}
+		first := r.hit.positions[0]
+		for _, pos := range r.hit.positions {
+			if pos < first {
+				first = pos
+			}
+		}
+		hit := IndexedHit{FilePath: r.doc.Path, Score: r.score, LineNum: r.doc.lineOf(first)}
+		// The line is read from disk, so it reflects the file as it is now.
+		if slice, err := a.GetFileSlice(r.doc.Path, hit.LineNum, 0); err == nil && slice.MatchIndex < len(slice.Lines) {
+			hit.Content = slice.Lines[slice.MatchIndex]
+		}
+		out.Hits = append(out.Hits, hit)
+	}
+	for ext, count := range facets {
+		out.Facets = append(out.Facets, ExtensionFacet{Extension: ext, Count: count})
+	}
+	sort.Slice(out.Facets, func(i, j int) bool {
+		if out.Facets[i].Count != out.Facets[j].Count {
+			return out.Facets[i].Count > out.Facets[j].Count
+		}
+		return out.Facets[i].Extension < out.Facets[j].Extension
+	})
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newIndexedApp indexes a directory of small files and returns the app and
+// the directory.
+func newIndexedApp(t *testing.T) (*App, string) {
+	t.Helper()
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	files := map[string]string{
+		"server.go":    "package main\n\n// openConnection dials the server\nfunc openConnection() {}\n",
+		"client.go":    "package main\n\nfunc dial() { openConnection() }\n",
+		"notes.md":     "The server connection is opened lazily.\nopen connection pool\n",
+		"unrelated.ts": "export const x = 1;\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+	info, err := app.IndexWorkspace(root)
+	if err != nil {
+		t.Fatalf("IndexWorkspace failed: %v", err)
+	}
+	if info.Files != 4 {
+		t.Fatalf("expected 4 indexed files, got %+v", info)
+	}
+	return app, root
+}
+
+// hitNames returns the base names of the hits in order.
+func hitNames(results IndexedSearchResults) []string {
+	var names []string
+	for _, hit := range results.Hits {
+		names = append(names, filepath.Base(hit.FilePath))
+	}
+	return names
+}
+
+// TestSearchIndexedQueries verifies words, phrases, and prefixes, the line
+// a hit points at, and the extension facets.
+func TestSearchIndexedQueries(t *testing.T) {
+	app, _ := newIndexedApp(t)
+
+	results, err := app.SearchIndexed("server")
+	if err != nil {
+		t.Fatalf("SearchIndexed failed: %v", err)
+	}
+	if got := hitNames(results); len(got) != 2 || results.Total != 2 {
+		t.Errorf("expected server.go and notes.md, got %v", got)
+	}
+
+	results, _ = app.SearchIndexed(`"open connection"`)
+	if got := hitNames(results); !reflect.DeepEqual(got, []string{"notes.md"}) {
+		t.Errorf("expected only notes.md for the phrase, got %v", got)
+	} else if results.Hits[0].LineNum != 2 || results.Hits[0].Content != "open connection pool" {
+		t.Errorf("expected the hit on line 2, got %+v", results.Hits[0])
+	}
+
+	results, _ = app.SearchIndexed("openconn* func")
+	if got := hitNames(results); len(got) != 2 {
+		t.Errorf("expected server.go and client.go for the prefix query, got %v", got)
+	}
+	want := []ExtensionFacet{{Extension: ".go", Count: 2}}
+	if !reflect.DeepEqual(results.Facets, want) {
+		t.Errorf("expected facets %+v, got %+v", want, results.Facets)
+	}
+
+	results, _ = app.SearchIndexed("server missingword")
+	if results.Total != 0 {
+		t.Errorf("expected every word to be required, got %v", hitNames(results))
+	}
+}
+
+// TestSearchIndexedRanking verifies that a file where the word is frequent
+// ranks above one where it appears once.
+func TestSearchIndexedRanking(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	files := map[string]string{
+		"many.txt": "cache cache cache\ncache miss\n",
+		"once.txt": "a cache of some other words in a longer line\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+	if _, err := app.IndexWorkspace(root); err != nil {
+		t.Fatalf("IndexWorkspace failed: %v", err)
+	}
+	results, err := app.SearchIndexed("cache")
+	if err != nil {
+		t.Fatalf("SearchIndexed failed: %v", err)
+	}
+	if got := hitNames(results); !reflect.DeepEqual(got, []string{"many.txt", "once.txt"}) {
+		t.Errorf("expected many.txt first, got %v", got)
+	}
+}
+
+// TestSearchIndexedPersists verifies that an index saved by IndexWorkspace
+// is used after a restart, and that searching without one fails.
+func TestSearchIndexedPersists(t *testing.T) {
+	app, _ := newIndexedApp(t)
+
+	reloaded := NewApp()
+	reloaded.dataDir = app.dataDir
+	results, err := reloaded.SearchIndexed("dial")
+	if err != nil || len(results.Hits) != 1 {
+		t.Errorf("expected one hit from the saved index, got %+v (err %v)", results, err)
+	}
+
+	empty := NewApp()
+	empty.dataDir = t.TempDir()
+	if _, err := empty.SearchIndexed("dial"); err == nil {
+		t.Error("expected an error without an index")
+	} else if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeNoIndex {
+		t.Errorf("expected %s, got %v", ErrCodeNoIndex, err)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// maxFuzziness is the most edits a fuzzy search tolerates. Beyond three,
+// almost any line of code matches a short identifier.
+const maxFuzziness = 3
+
+// maxFuzzyQueryLength is the longest fuzzy query in bytes: the bitap
+// matcher keeps the pattern state in one 64-bit word.
+const maxFuzzyQueryLength = 63
+
+// effectiveFuzziness caps the requested fuzziness by the query length: one
+// edit per three characters, so "recieve" (7) allows two and "get" allows
+// one, and never more than maxFuzziness.
+func effectiveFuzziness(query string, requested int) int {
+	limit := utf8.RuneCountInString(query) / 3
+	if limit > maxFuzziness {
+		limit = maxFuzziness
+	}
+	if requested > limit {
+		return limit
+	}
+	if requested < 0 {
+		return 0
+	}
+	return requested
+}
+
+// lineMatcher finds the query in a line. *regexp.Regexp implements it; so
+// does bitapMatcher for fuzzy searches.
+type lineMatcher interface {
+	Match(line []byte) bool
+	Find(line []byte) []byte
+	MatchString(line string) bool
+	FindString(line string) string
+	FindAllStringIndex(line string, n int) [][]int
+}
+
+// searchLineMatcher returns the matcher processFile runs on each line: a
+// bitap matcher for fuzzy searches, a captureMatcher when capture groups
+// are extracted, the compiled pattern otherwise.
+func searchLineMatcher(req SearchRequest, pattern *regexp.Regexp) lineMatcher {
+	if req.Fuzziness > 0 {
+		return newBitapMatcher(req.Query, req.Fuzziness, req.CaseSensitive)
+	}
+	if req.ExtractGroups {
+		return captureMatcher{pattern}
+	}
+	return pattern
+}
+
+// bitapMatcher is an agrep-style approximate matcher: it finds the query
+// with up to k insertions, deletions, or substitutions (Levenshtein
+// distance), counted in bytes. It is a good deal slower than the regexp
+// path, which skips ahead on literal prefixes; every byte of every line
+// goes through k+1 shift-and steps.
+type bitapMatcher struct {
+	masks    [256]uint64 // Bit i is set for the bytes that match query[i]
+	length   int
+	k        int
+	backward *bitapMatcher // The reversed query, to find where a match starts
+}
+
+// newBitapMatcher builds a matcher for a query of at most
+// maxFuzzyQueryLength bytes. Without caseSensitive, ASCII letters match
+// either case.
+func newBitapMatcher(query string, k int, caseSensitive bool) *bitapMatcher {
+	m := buildBitap(query, k, caseSensitive)
+	reversed := make([]byte, len(query))
+	for i := range query {
+		reversed[len(query)-1-i] = query[i]
+	}
+	m.backward = buildBitap(string(reversed), k, caseSensitive)
+	return m
+}
+
+func buildBitap(query string, k int, caseSensitive bool) *bitapMatcher {
+	m := &bitapMatcher{length: len(query), k: k}
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		m.masks[c] |= 1 << uint(i)
+		if !caseSensitive {
+			switch {
+			case 'a' <= c && c <= 'z':
+				m.masks[c-'a'+'A'] |= 1 << uint(i)
+			case 'A' <= c && c <= 'Z':
+				m.masks[c-'A'+'a'] |= 1 << uint(i)
+			}
+		}
+	}
+	return m
+}
+
+// firstEnd returns the index of the last byte of the first match in text,
+// or -1. Bit i of state[d] is set when query[:i+1] matches a suffix of the
+// text read so far with at most d edits.
+func (m *bitapMatcher) firstEnd(text []byte) int {
+	if m.length == 0 {
+		return -1
+	}
+	done := uint64(1) << uint(m.length-1)
+	var state [maxFuzziness + 1]uint64
+	for d := 1; d <= m.k; d++ {
+		// Up to d leading query bytes can be deleted before any text.
+		state[d] = 1<<uint(d) - 1
+	}
+
+	for i, c := range text {
+		mask := m.masks[c]
+		prev := state[0]
+		state[0] = (state[0]<<1 | 1) & mask
+		for d := 1; d <= m.k; d++ {
+			old := state[d]
+			// match | insertion | substitution and deletion
+			state[d] = (old<<1|1)&mask | prev | (prev|state[d-1])<<1 | 1
+			prev = old
+		}
+		if state[m.k]&done != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// find returns the bounds of the first match in text.
+func (m *bitapMatcher) find(text []byte) (int, int, bool) {
+	end := m.firstEnd(text)
+	if end < 0 {
+		return 0, 0, false
+	}
+	// Scan backwards from the end with the reversed query; the first
+	// completion is the closest, and so shortest, start.
+	reversed := make([]byte, end+1)
+	for i := 0; i <= end; i++ {
+		reversed[i] = text[end-i]
+	}
+	start := 0
+	if back := m.backward.firstEnd(reversed); back >= 0 {
+		start = end - back
+	}
+	return start, end + 1, true
+}
+
+func (m *bitapMatcher) Match(line []byte) bool {
+	return m.firstEnd(line) >= 0
+}
+
+func (m *bitapMatcher) Find(line []byte) []byte {
+	if start, end, ok := m.find(line); ok {
+		return line[start:end]
+	}
+	return nil
+}
+
+func (m *bitapMatcher) MatchString(line string) bool {
+	return m.Match([]byte(line))
+}
+
+func (m *bitapMatcher) FindString(line string) string {
+	return string(m.Find([]byte(line)))
+}
+
+// FindAllStringIndex returns the bounds of up to n successive
+// non-overlapping matches in line (all of them for n < 0), like
+// regexp.Regexp.FindAllStringIndex.
+func (m *bitapMatcher) FindAllStringIndex(line string, n int) [][]int {
+	var locs [][]int
+	text := []byte(line)
+	for offset := 0; n < 0 || len(locs) < n; {
+		start, end, ok := m.find(text[offset:])
+		if !ok || end == 0 {
+			break
+		}
+		locs = append(locs, []int{offset + start, offset + end})
+		offset += end
+	}
+	return locs
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEffectiveFuzziness verifies the cap of one edit per three query
+// characters and maxFuzziness overall.
+func TestEffectiveFuzziness(t *testing.T) {
+	tests := []struct {
+		query     string
+		requested int
+		want      int
+	}{
+		{"ab", 2, 0},
+		{"get", 2, 1},
+		{"recieve", 5, 2},
+		{"recieve", 1, 1},
+		{"aVeryLongIdentifierName", 9, maxFuzziness},
+		{"recieve", -1, 0},
+	}
+	for _, tt := range tests {
+		if got := effectiveFuzziness(tt.query, tt.requested); got != tt.want {
+			t.Errorf("effectiveFuzziness(%q, %d) = %d, want %d", tt.query, tt.requested, got, tt.want)
+		}
+	}
+}
+
+// minSubstringDistance is the reference: the smallest edit distance between
+// query and any substring of text (Sellers' algorithm).
+func minSubstringDistance(query, text string) int {
+	prev := make([]int, len(text)+1)
+	for i := 1; i <= len(query); i++ {
+		cur := make([]int, len(text)+1)
+		cur[0] = i
+		for j := 1; j <= len(text); j++ {
+			cost := 1
+			if query[i-1] == text[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+		}
+		prev = cur
+	}
+	best := prev[0]
+	for _, d := range prev {
+		best = min(best, d)
+	}
+	return best
+}
+
+// TestBitapMatcherAgainstReference verifies the bitap matcher against the
+// dynamic-programming reference on random strings, and that Find returns a
+// substring within the allowed distance.
+func TestBitapMatcherAgainstReference(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 2000; i++ {
+		query := randString(3 + rng.Intn(6))
+		text := randString(rng.Intn(20))
+		k := rng.Intn(3)
+		m := newBitapMatcher(query, k, true)
+
+		want := minSubstringDistance(query, text) <= k
+		if got := m.MatchString(text); got != want {
+			t.Fatalf("Match(%q in %q, k=%d) = %v, want %v", query, text, k, got, want)
+		}
+		if found := m.FindString(text); want && minSubstringDistance(query, found) > k {
+			t.Fatalf("Find(%q in %q, k=%d) = %q, more than %d edits away", query, text, k, found, k)
+		}
+	}
+}
+
+// TestFuzzySearch verifies that a misspelled query finds the word end to
+// end, that case folding applies, and that regex mode is rejected.
+func TestFuzzySearch(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	content := "func Receive(msg string) {}\nfunc send() {}\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "net.go"), []byte(content), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	results, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "recieve", Fuzziness: 2})
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 1 || results[0].LineNum != 1 || results[0].MatchedText != "Receive" {
+		t.Errorf("expected Receive on line 1, got %+v", results)
+	}
+
+	exact, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "recieve"})
+	if err != nil || len(exact) != 0 {
+		t.Errorf("expected no exact match, got %+v (err %v)", exact, err)
+	}
+
+	useRegex := true
+	_, err = app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "recieve", Fuzziness: 2, UseRegex: &useRegex})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeFuzzyNeedsLiteral {
+		t.Errorf("expected %s, got %v", ErrCodeFuzzyNeedsLiteral, err)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIsGeneratedFileName verifies the name-based heuristic used during the
+// directory walk: minified bundles, source maps, and bundler outputs are
+// flagged, ordinary source files are not.
+func TestIsGeneratedFileName(t *testing.T) {
+	generated := []string{
+		"app.min.js", "styles.min.css", "APP.MIN.JS", "app.js.map",
+		"main.bundle.js", "0.chunk.js", "vendor.js", "dist/bundle.js",
+	}
+	for _, name := range generated {
+		if !isGeneratedFileName(name) {
+			t.Errorf("expected %q to be flagged as generated", name)
+		}
+	}
+
+	regular := []string{
+		"app.js", "main.go", "minimal.js", "bundle.go", "sitemap.xml", "map.ts",
+	}
+	for _, name := range regular {
+		if isGeneratedFileName(name) {
+			t.Errorf("expected %q NOT to be flagged as generated", name)
+		}
+	}
+}
+
+// TestLooksGenerated covers the content heuristic: generator markers in the
+// file head, mentions of them in prose, comments, and string literals that
+// don't count, and a first line longer than the inspected window.
+func TestLooksGenerated(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"GoMarker", "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb\n", true},
+		{"GoMarkerCRLF", "// Code generated by stringer. DO NOT EDIT.\r\npackage pb\r\n", true},
+		{"AtGeneratedMarker", "/**\n * @generated\n */\nexport const x = 1;\n", true},
+		{"AtGeneratedHashComment", "# @generated by pip-compile\nrequests==2.31.0\n", true},
+		{"GoMarkerInProse", "# Tools\n\nGenerators write a Code generated header; @generated is another tag.\n", false},
+		{"GoMarkerInComment", "package main\n\n// Files starting with \"// Code generated ... DO NOT EDIT.\" are skipped.\n", false},
+		{"GoMarkerWithoutDoNotEdit", "// Code generated by hand, edit freely.\npackage main\n", false},
+		{"MarkersInStringLiterals", "package main\n\nconst header = \"// Code generated by gen. DO NOT EDIT.\"\nconst tag = \"@generated\"\n", false},
+		{"AtGeneratedInCommentProse", "// Files tagged @generated are skipped.\npackage main\n", false},
+		{"Minified", strings.Repeat("var a=1;", generatedHeadBytes/8+10), true},
+		{"Handwritten", "package main\n\nfunc main() {}\n", false},
+		{"ShortSingleLine", "const x = 1;", false},
+		{"Empty", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := looksGenerated([]byte(tc.content)); got != tc.want {
+				t.Errorf("looksGenerated = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// The detector's own source describes both markers.
+	self, err := os.ReadFile("generated_files.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if looksGenerated(self) {
+		t.Error("expected generated_files.go NOT to be flagged as generated")
+	}
+}
+
+// TestSearchSkipGenerated verifies that SkipGenerated drops both name-matched
+// and content-matched generated files, and that they are still searched when
+// the option is off.
+func TestSearchSkipGenerated(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"handwritten.go": "package main\n// needle in source\n",
+		"api.pb.go":      "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb\n// needle in generated\n",
+		"app.min.js":     "var needle=1;\n",
+		"big.js":         "var needle=1;" + strings.Repeat("var a=1;", generatedHeadBytes/8+10),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		results, err := app.SearchWithProgress(SearchRequest{
+			Directory:     tempDir,
+			Query:         "needle",
+			SearchSubdirs: true,
+			SkipGenerated: true,
+		})
+		if err != nil {
+			t.Fatalf("SearchWithProgress failed: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result with SkipGenerated, got %d", len(results))
+		}
+		if filepath.Base(results[0].FilePath) != "handwritten.go" {
+			t.Errorf("expected the match from handwritten.go, got %s", results[0].FilePath)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		results, err := app.SearchWithProgress(SearchRequest{
+			Directory:     tempDir,
+			Query:         "needle",
+			SearchSubdirs: true,
+		})
+		if err != nil {
+			t.Fatalf("SearchWithProgress failed: %v", err)
+		}
+		if len(results) != len(files) {
+			t.Errorf("expected %d results without SkipGenerated, got %d", len(files), len(results))
+		}
+	})
+}
+
+// TestWalkDirectoryTreeCountsGeneratedSkips verifies that files dropped by
+// the name heuristic are recorded in the collection skip statistics.
+func TestWalkDirectoryTreeCountsGeneratedSkips(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	for _, name := range []string{"a.min.js", "b.js.map", "c.js"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("x\n"), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	req := SearchRequest{
+		Directory:     tempDir,
+		Query:         "x",
+		SearchSubdirs: true,
+		MaxFileSize:   10 * 1024 * 1024,
+		MaxResults:    1000,
+		SkipGenerated: true,
+	}
+	textCandidates, binaryCandidates, stats, err := app.walkDirectoryTree(req, false)
+	if err != nil {
+		t.Fatalf("walkDirectoryTree failed: %v", err)
+	}
+
+	if got := len(textCandidates) + len(binaryCandidates); got != 1 {
+		t.Errorf("expected 1 candidate, got %d", got)
+	}
+	if stats.generatedSkipped != 2 {
+		t.Errorf("expected generatedSkipped=2, got %d", stats.generatedSkipped)
+	}
+	if stats.filesSkipped < stats.generatedSkipped {
+		t.Errorf("generated skips must be counted in filesSkipped (%d < %d)", stats.filesSkipped, stats.generatedSkipped)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gitCommandTimeout bounds each git invocation, so a hung credential helper
+// or a huge repository on a network drive can't stall a copy action.
+const gitCommandTimeout = 5 * time.Second
+
+// gitRepoInfo describes the git work tree a file belongs to.
+type gitRepoInfo struct {
+	root   string // Top-level directory of the work tree
+	origin string // URL of the origin remote; empty if there is none
+	commit string // Full SHA of HEAD
+}
+
+// runGit runs git in dir and returns its trimmed standard output.
+func runGit(dir string, args ...string) (string, error) {
+	out, err := runGitOutput(dir, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// runGitOutput is runGit without trimming, for output that is file content.
+func runGitOutput(dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	hideConsoleWindow(cmd)
+	return cmd.Output()
+}
+
+// lookupGitRepo returns the work tree containing filePath. A repository
+// without an origin remote is not an error; origin is left empty.
+func lookupGitRepo(filePath string) (gitRepoInfo, error) {
+	dir := filepath.Dir(filePath)
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return gitRepoInfo{}, err
+	}
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return gitRepoInfo{}, err
+	}
+	origin, _ := runGit(dir, "remote", "get-url", "origin")
+
+	return gitRepoInfo{root: filepath.FromSlash(root), origin: origin, commit: commit}, nil
+}
+
+// repoRelativePath returns filePath relative to the work tree root, with
+// forward slashes. Both sides have symlinks resolved because git reports
+// the physical top-level directory.
+func (info gitRepoInfo) repoRelativePath(filePath string) (string, error) {
+	root, err := filepath.EvalSymlinks(info.root)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside %s", filePath, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// parseRemoteURL splits a git remote URL into host and repository path
+// ("org/repo"), accepting the https, ssh://, git://, and scp-like
+// (git@host:org/repo.git) forms.
+func parseRemoteURL(remote string) (host, repoPath string, ok bool) {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return "", "", false
+	}
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Hostname() == "" {
+			return "", "", false
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		at := strings.LastIndex(remote, "@")
+		colon := strings.Index(remote, ":")
+		if colon < 0 || colon < at {
+			return "", "", false
+		}
+		host, repoPath = remote[at+1:colon], remote[colon+1:]
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return "", "", false
+	}
+	return strings.ToLower(host), repoPath, true
+}
+
+// escapeURLPath escapes each segment of a slash-separated path.
+func escapeURLPath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// forgeLinkFormats holds, per code-hosting flavour, the format of a file
+// URL at a commit (host, repo path, commit, file path) and of the line
+// anchor appended to it.
+var forgeLinkFormats = map[string]struct {
+	file   string
+	anchor string
+}{
+	"github":    {"https://%s/%s/blob/%s/%s", "#L%d"},
+	"gitlab":    {"https://%s/%s/-/blob/%s/%s", "#L%d"},
+	"bitbucket": {"https://%s/%s/src/%s/%s", "#lines-%d"},
+	"gitea":     {"https://%s/%s/src/commit/%s/%s", "#L%d"},
+}
+
+// forgeFlavour guesses the code-hosting software from the remote host name.
+// Self-hosted instances are recognised when their host name says what they
+// run (gitlab.example.com, gitea.example.org).
+func forgeFlavour(host string) string {
+	switch {
+	case strings.Contains(host, "github"):
+		return "github"
+	case strings.Contains(host, "gitlab"):
+		return "gitlab"
+	case strings.Contains(host, "bitbucket"):
+		return "bitbucket"
+	case strings.Contains(host, "gitea"), strings.Contains(host, "codeberg"):
+		return "gitea"
+	}
+	return ""
+}
+
+// permalink returns the web URL of line in the file at relPath on the
+// origin's code host, pinned to the HEAD commit so it keeps pointing at the
+// same code. ok is false when there is no origin or its host isn't
+// recognised.
+func (info gitRepoInfo) permalink(relPath string, line int) (link string, ok bool) {
+	host, repoPath, ok := parseRemoteURL(info.origin)
+	if !ok || info.commit == "" {
+		return "", false
+	}
+	format, ok := forgeLinkFormats[forgeFlavour(host)]
+	if !ok {
+		return "", false
+	}
+
+	link = fmt.Sprintf(format.file, host, repoPath, info.commit, escapeURLPath(relPath))
+	if line > 0 {
+		link += fmt.Sprintf(format.anchor, line)
+	}
+	return link, true
+}
+
+// GetRemoteLink returns a browsable URL of line in filePath on the code host
+// of the containing repository's origin remote, pinned to the checked-out
+// commit, e.g. https://github.com/org/repo/blob/<sha>/path#L42. GitHub,
+// GitLab, Bitbucket, and Gitea/Codeberg URL layouts are supported. line <= 0
+// links to the file without a line anchor. The link points at the local
+// HEAD commit, which only resolves once that commit has been pushed.
+func (a *App) GetRemoteLink(filePath string, line int) (string, error) {
+	cleanPath, err := a.validatePathForEditor(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := lookupGitRepo(cleanPath)
+	if err != nil {
+		a.logDebug("No git repository for remote link", logrus.Fields{
+			"filePath": cleanPath,
+			"error":    err.Error(),
+		})
+		return "", newAppError(ErrCodeNoRemoteLink, cleanPath)
+	}
+	rel, err := info.repoRelativePath(cleanPath)
+	if err != nil {
+		return "", newAppError(ErrCodeNoRemoteLink, cleanPath)
+	}
+	link, ok := info.permalink(rel, line)
+	if !ok {
+		a.logDebug("Origin remote has no supported web URL", logrus.Fields{
+			"filePath": cleanPath,
+			"origin":   info.origin,
+		})
+		return "", newAppError(ErrCodeNoRemoteLink, cleanPath)
+	}
+	return link, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initGitRepo creates a git repository with one committed file,
+// src/main.go, and the given origin remote (none if empty). It returns the
+// repository root and the file path, skipping the test when git is missing.
+func initGitRepo(t *testing.T, origin string) (string, string) {
+	t.Helper()
+	if !commandAvailable("git") {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	file := filepath.Join(root, "src", "main.go")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatalf("creating src: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	commands := [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	}
+	if origin != "" {
+		commands = append(commands, []string{"remote", "add", "origin", origin})
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return root, file
+}
+
+// TestParseRemoteURL verifies the supported remote URL forms.
+func TestParseRemoteURL(t *testing.T) {
+	cases := []struct {
+		remote, host, path string
+	}{
+		{"https://github.com/org/repo.git", "github.com", "org/repo"},
+		{"https://user@GitHub.com/org/repo/", "github.com", "org/repo"},
+		{"git@github.com:org/repo.git", "github.com", "org/repo"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/repo.git", "gitlab.example.com", "group/sub/repo"},
+		{"git://example.org/repo", "example.org", "repo"},
+	}
+	for _, tc := range cases {
+		host, path, ok := parseRemoteURL(tc.remote)
+		if !ok || host != tc.host || path != tc.path {
+			t.Errorf("parseRemoteURL(%q) = %q, %q, %v; want %q, %q", tc.remote, host, path, ok, tc.host, tc.path)
+		}
+	}
+
+	for _, bad := range []string{"", "/srv/git/repo.git", "https:///repo"} {
+		if _, _, ok := parseRemoteURL(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+// TestLookupGitRepo verifies the root, origin, commit, and repo-relative
+// path reported for a file in a repository.
+func TestLookupGitRepo(t *testing.T) {
+	_, file := initGitRepo(t, "git@github.com:org/repo.git")
+
+	info, err := lookupGitRepo(file)
+	if err != nil {
+		t.Fatalf("lookupGitRepo failed: %v", err)
+	}
+	if info.origin != "git@github.com:org/repo.git" {
+		t.Errorf("unexpected origin %q", info.origin)
+	}
+	if len(info.commit) != 40 {
+		t.Errorf("expected a full commit SHA, got %q", info.commit)
+	}
+	rel, err := info.repoRelativePath(file)
+	if err != nil || rel != "src/main.go" {
+		t.Errorf("repoRelativePath = %q, %v; want src/main.go", rel, err)
+	}
+
+	if _, err := lookupGitRepo(filepath.Join(t.TempDir(), "loose.go")); err == nil {
+		t.Error("expected an error for a file outside any repository")
+	}
+}
+
+// TestPermalinkFlavours verifies the URL layout for each supported code
+// host and that unknown hosts yield no link.
+func TestPermalinkFlavours(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	cases := map[string]string{
+		"git@github.com:org/repo.git":                "https://github.com/org/repo/blob/" + sha + "/dir/a%20b.go#L42",
+		"https://gitlab.com/group/sub/repo.git":      "https://gitlab.com/group/sub/repo/-/blob/" + sha + "/dir/a%20b.go#L42",
+		"git@bitbucket.org:team/repo.git":            "https://bitbucket.org/team/repo/src/" + sha + "/dir/a%20b.go#lines-42",
+		"https://codeberg.org/user/repo":             "https://codeberg.org/user/repo/src/commit/" + sha + "/dir/a%20b.go#L42",
+		"ssh://git@gitlab.corp.example:2222/x/y.git": "https://gitlab.corp.example/x/y/-/blob/" + sha + "/dir/a%20b.go#L42",
+	}
+	for origin, want := range cases {
+		info := gitRepoInfo{origin: origin, commit: sha}
+		if got, ok := info.permalink("dir/a b.go", 42); !ok || got != want {
+			t.Errorf("permalink for %q = %q, %v; want %q", origin, got, ok, want)
+		}
+	}
+
+	if _, ok := (gitRepoInfo{origin: "git@git.example.com:x/y.git", commit: sha}).permalink("a.go", 1); ok {
+		t.Error("expected an unrecognised host to yield no link")
+	}
+	if got, _ := (gitRepoInfo{origin: "git@github.com:o/r.git", commit: sha}).permalink("a.go", 0); strings.Contains(got, "#") {
+		t.Errorf("expected no line anchor for line 0, got %q", got)
+	}
+}
+
+// TestGetRemoteLink verifies the link for a committed file and the
+// NO_REMOTE_LINK error for a repository without an origin.
+func TestGetRemoteLink(t *testing.T) {
+	app := NewApp()
+
+	_, file := initGitRepo(t, "https://gitlab.com/group/repo.git")
+	link, err := app.GetRemoteLink(file, 3)
+	if err != nil {
+		t.Fatalf("GetRemoteLink failed: %v", err)
+	}
+	if !strings.HasPrefix(link, "https://gitlab.com/group/repo/-/blob/") || !strings.HasSuffix(link, "/src/main.go#L3") {
+		t.Errorf("unexpected link %q", link)
+	}
+
+	_, noOrigin := initGitRepo(t, "")
+	var appErr *AppError
+	if _, err := app.GetRemoteLink(noOrigin, 1); !errors.As(err, &appErr) || appErr.Code != ErrCodeNoRemoteLink {
+		t.Errorf("expected %s, got %v", ErrCodeNoRemoteLink, err)
+	}
+}
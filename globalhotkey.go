@@ -0,0 +1,188 @@
+//go:build linux
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// D-Bus names of the XDG GlobalShortcuts desktop portal. The portal is the
+// only way to grab a global shortcut on Wayland, and works on X11 desktops
+// that ship it (GNOME 48+, KDE Plasma 5.27+).
+const (
+	portalBusName        = "org.freedesktop.portal.Desktop"
+	portalObjectPath     = "/org/freedesktop/portal/desktop"
+	portalShortcutsIface = "org.freedesktop.portal.GlobalShortcuts"
+	portalRequestIface   = "org.freedesktop.portal.Request"
+	portalSessionIface   = "org.freedesktop.portal.Session"
+)
+
+// summonShortcutID identifies our single shortcut within the portal session.
+const summonShortcutID = "summon"
+
+// portalResponseTimeout bounds how long a portal request may take. Binding
+// can show a confirmation dialog, so this allows time for the user to answer.
+const portalResponseTimeout = 2 * time.Minute
+
+// portalShortcut is the (sa{sv}) struct BindShortcuts takes.
+type portalShortcut struct {
+	ID    string
+	Props map[string]dbus.Variant
+}
+
+// registerGlobalHotkey binds hk through the GlobalShortcuts portal and calls
+// onPress whenever it is activated. The returned function closes the portal
+// session, which releases the shortcut.
+func registerGlobalHotkey(hk hotkey, onPress func()) (func(), error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to session bus: %w", err)
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(portalRequestIface), dbus.WithMatchMember("Response")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(portalShortcutsIface), dbus.WithMatchMember("Activated")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	portal := conn.Object(portalBusName, portalObjectPath)
+
+	results, err := portalRequest(conn, portal, signals, "CreateSession", func(token string) []interface{} {
+		return []interface{}{map[string]dbus.Variant{
+			"handle_token":         dbus.MakeVariant(token),
+			"session_handle_token": dbus.MakeVariant(token),
+		}}
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	session, err := sessionHandle(results)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	shortcuts := []portalShortcut{{
+		ID: summonShortcutID,
+		Props: map[string]dbus.Variant{
+			"description":       dbus.MakeVariant("Show code search"),
+			"preferred_trigger": dbus.MakeVariant(hk.portalTrigger()),
+		},
+	}}
+	_, err = portalRequest(conn, portal, signals, "BindShortcuts", func(token string) []interface{} {
+		return []interface{}{session, shortcuts, "", map[string]dbus.Variant{
+			"handle_token": dbus.MakeVariant(token),
+		}}
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig, ok := <-signals:
+				if !ok {
+					return
+				}
+				if sig.Name != portalShortcutsIface+".Activated" || len(sig.Body) < 2 {
+					continue
+				}
+				if path, _ := sig.Body[0].(dbus.ObjectPath); path != session {
+					continue
+				}
+				if id, _ := sig.Body[1].(string); id == summonShortcutID {
+					onPress()
+				}
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		conn.Object(portalBusName, session).Call(portalSessionIface+".Close", 0)
+		conn.Close()
+	}
+	return stop, nil
+}
+
+// portalRequest calls a portal method that answers through a Request object
+// and waits for that request's Response signal. args builds the method
+// arguments around the handle token, which determines the request path.
+func portalRequest(conn *dbus.Conn, portal dbus.BusObject, signals <-chan *dbus.Signal, method string, args func(token string) []interface{}) (map[string]dbus.Variant, error) {
+	token, err := portalToken()
+	if err != nil {
+		return nil, err
+	}
+	names := conn.Names()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("session bus connection has no unique name")
+	}
+	// The request path is derived from our unique bus name and the token, so
+	// it is known before the call returns and a fast Response isn't missed.
+	sender := strings.ReplaceAll(strings.TrimPrefix(names[0], ":"), ".", "_")
+	requestPath := dbus.ObjectPath(portalObjectPath + "/request/" + sender + "/" + token)
+
+	if call := portal.Call(portalShortcutsIface+"."+method, 0, args(token)...); call.Err != nil {
+		return nil, fmt.Errorf("%s: %w", method, call.Err)
+	}
+
+	timeout := time.After(portalResponseTimeout)
+	for {
+		select {
+		case sig, ok := <-signals:
+			if !ok {
+				return nil, fmt.Errorf("%s: session bus closed", method)
+			}
+			if sig.Path != requestPath || sig.Name != portalRequestIface+".Response" || len(sig.Body) < 2 {
+				continue
+			}
+			if code, _ := sig.Body[0].(uint32); code != 0 {
+				return nil, fmt.Errorf("%s: request denied or cancelled (response %d)", method, code)
+			}
+			results, _ := sig.Body[1].(map[string]dbus.Variant)
+			return results, nil
+		case <-timeout:
+			return nil, fmt.Errorf("%s: no response from desktop portal", method)
+		}
+	}
+}
+
+// sessionHandle extracts the session object path from a CreateSession
+// response. Portal versions differ on whether it is sent as a string or an
+// object path.
+func sessionHandle(results map[string]dbus.Variant) (dbus.ObjectPath, error) {
+	switch v := results["session_handle"].Value().(type) {
+	case string:
+		return dbus.ObjectPath(v), nil
+	case dbus.ObjectPath:
+		return v, nil
+	}
+	return "", fmt.Errorf("CreateSession: portal returned no session handle")
+}
+
+// portalToken returns a random token for handle_token options. Tokens must
+// be valid D-Bus object path elements.
+func portalToken() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "codesearch_" + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,85 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThreadId")
+)
+
+var user32 = syscall.NewLazyDLL("user32.dll")
+
+const (
+	wmHotkey     = 0x0312
+	wmQuit       = 0x0012
+	summonHotkey = 1 // Hotkey ID passed to RegisterHotKey
+)
+
+// winMsg mirrors the Win32 MSG structure.
+type winMsg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// registerGlobalHotkey registers hk with RegisterHotKey and calls onPress
+// whenever it is pressed. Hotkeys are bound to the registering thread's
+// message queue, so registration and the GetMessage loop run on a goroutine
+// locked to its OS thread. The returned function posts WM_QUIT to that
+// thread, which unregisters the hotkey and ends the loop.
+func registerGlobalHotkey(hk hotkey, onPress func()) (func(), error) {
+	modifiers, vk := hk.windowsKeys()
+	type started struct {
+		threadID uintptr
+		err      error
+	}
+	ready := make(chan started, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		threadID, _, _ := procGetCurrentThreadId.Call()
+		ok, _, err := procRegisterHotKey.Call(0, summonHotkey, uintptr(modifiers), uintptr(vk))
+		if ok == 0 {
+			// Most often ERROR_HOTKEY_ALREADY_REGISTERED: another app owns it.
+			ready <- started{err: fmt.Errorf("RegisterHotKey: %w", err)}
+			return
+		}
+		defer procUnregisterHotKey.Call(0, summonHotkey)
+		ready <- started{threadID: threadID}
+
+		var msg winMsg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
+			if int32(r) <= 0 { // WM_QUIT or error
+				return
+			}
+			if msg.message == wmHotkey && msg.wParam == summonHotkey {
+				go onPress()
+			}
+		}
+	}()
+
+	s := <-ready
+	if s.err != nil {
+		return nil, s.err
+	}
+	stop := func() {
+		procPostThreadMessageW.Call(s.threadID, wmQuit, 0, 0)
+	}
+	return stop, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// searchGoroutineFrames name the functions whose goroutines a search
+// starts. One still running after the search returned is a leak.
+var searchGoroutineFrames = []string{
+	"main.(*searcher).processFiles",
+	"main.(*App).probeBinaryInParallel",
+	"main.(*App).runBatch",
+}
+
+// checkNoSearchGoroutines fails the test if a goroutine started by a search
+// is still running, like goleak.VerifyNone restricted to the search
+// pipeline: other tests' goroutines (log tailing, hooks) are not counted.
+// Goroutines get a moment to finish exiting first.
+func checkNoSearchGoroutines(t *testing.T) {
+	t.Helper()
+	var leaked []string
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		leaked = leakedSearchGoroutines()
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+	for _, stack := range leaked {
+		t.Errorf("leaked search goroutine:\n%s", stack)
+	}
+}
+
+// leakedSearchGoroutines returns the stacks of the running goroutines with
+// a frame from searchGoroutineFrames.
+func leakedSearchGoroutines() []string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var leaked []string
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		for _, frame := range searchGoroutineFrames {
+			if strings.Contains(stack, frame) {
+				leaked = append(leaked, stack)
+				break
+			}
+		}
+	}
+	return leaked
+}
+
+// slowSink takes its time over each result, so the workers fill the results
+// channel while the searcher is still reading it.
+type slowSink struct{ recordingSink }
+
+func (s *slowSink) AddResult(SearchResult) error {
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+// slowMatcher takes a few milliseconds over each file, so workers are
+// mid-file when the search stops.
+type slowMatcher struct{ fakeMatcher }
+
+func (m slowMatcher) Match(ctx context.Context, meta fileMeta, pattern *regexp.Regexp, req SearchRequest, state *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult) {
+	time.Sleep(5 * time.Millisecond)
+	return m.fakeMatcher.Match(ctx, meta, pattern, req, state, searchCancelled, cancel)
+}
+
+// lateEventSink records whether a progress event arrived after Done.
+type lateEventSink struct {
+	mu   sync.Mutex
+	done bool
+	late int
+}
+
+func (s *lateEventSink) AddResult(SearchResult) error { return nil }
+
+func (s *lateEventSink) Progress(SearchProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		s.late++
+	}
+}
+
+func (s *lateEventSink) Done(SearchProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+}
+
+// manyFiles returns n fake files for the searcher.
+func manyFiles(n int) fakeCollector {
+	files := make(fakeCollector, n)
+	for i := range files {
+		files[i] = fileMeta{absPath: "/f", size: 1}
+	}
+	return files
+}
+
+// TestSearcherStopsWithoutLeaks verifies that every way a search stops
+// early (the result limit, a failing sink, a cancel) leaves no worker
+// behind once run returns, even with the results channel full.
+func TestSearcherStopsWithoutLeaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		sink       ResultSink
+		maxResults int
+		cancelSoon bool
+	}{
+		{name: "result limit", sink: &slowSink{}, maxResults: 150},
+		{name: "failing sink", sink: &errSink{}, maxResults: 10000},
+		{name: "cancelled", sink: &slowSink{}, maxResults: 10000, cancelSoon: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &searcher{collector: manyFiles(2000), matcher: slowMatcher{fakeMatcher{perFile: 5}}, sink: tt.sink, log: nopSearchLogger{}}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelSoon {
+				time.AfterFunc(20*time.Millisecond, cancel)
+			}
+			if _, err := s.run(ctx, cancel, "id", SearchRequest{MaxResults: tt.maxResults}, regexp.MustCompile("x"), "/"); err != nil && tt.name != "failing sink" {
+				t.Fatalf("run failed: %v", err)
+			}
+			checkNoSearchGoroutines(t)
+		})
+	}
+}
+
+// TestSearcherNoProgressAfterDone verifies that the workers have finished
+// by the time the completed event is sent.
+func TestSearcherNoProgressAfterDone(t *testing.T) {
+	sink := &lateEventSink{}
+	s := &searcher{collector: manyFiles(2000), matcher: slowMatcher{fakeMatcher{perFile: 5}}, sink: sink, log: nopSearchLogger{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := s.run(ctx, cancel, "id", SearchRequest{MaxResults: 50}, regexp.MustCompile("x"), "/"); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if !sink.done || sink.late != 0 {
+		t.Errorf("expected no progress after the completed event, got %d late events", sink.late)
+	}
+}
+
+// TestProbeBinaryInParallelCancelledWithoutLeaks verifies that a cancelled
+// probe returns without leaving its workers or feeder behind.
+func TestProbeBinaryInParallelCancelledWithoutLeaks(t *testing.T) {
+	app := NewApp()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	candidates := make([]fileMeta, 500)
+	for i := range candidates {
+		candidates[i] = fileMeta{absPath: "/nonexistent/file.dat"}
+	}
+	app.probeBinaryInParallel(ctx, candidates, false)
+	checkNoSearchGoroutines(t)
+}
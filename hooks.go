@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Search hooks run the user's commands at points of every search, so
+// searches can feed a notification system or a ticketing tool:
+//
+//   - pre-search: the request is validated and the search is about to start;
+//   - post-search: the search completed, was cancelled, or failed;
+//   - result-threshold: the search has produced Threshold results.
+//
+// Each command reads a hookEvent as JSON on stdin. Hooks run in the
+// background and never delay or change the search; their output and exit
+// status go to the log.
+
+// Events a SearchHook can run on.
+const (
+	hookPreSearch       = "pre-search"
+	hookPostSearch      = "post-search"
+	hookResultThreshold = "result-threshold"
+)
+
+// hookTimeout bounds a hook command. One that runs longer is killed.
+const hookTimeout = time.Minute
+
+// maxHookOutputLogged is how much of a hook's output is logged.
+const maxHookOutputLogged = 2048
+
+// hookShutdownWait is how long shutdown waits for running hooks to exit.
+const hookShutdownWait = 5 * time.Second
+
+// hookEvent is the JSON a hook command reads on stdin.
+type hookEvent struct {
+	Event        string        `json:"event"`
+	SearchID     string        `json:"searchId"` // Search ID of the progress events and FilterResults
+	Request      SearchRequest `json:"request"`  // The validated request
+	Time         int64         `json:"time"`     // Unix milliseconds
+	ResultCount  int           `json:"resultCount"`
+	Status       string        `json:"status,omitempty"`       // post-search: "completed", "cancelled", "incomplete" (directory removed), or "failed"
+	Error        string        `json:"error,omitempty"`        // post-search: why the search failed
+	TotalMatches int           `json:"totalMatches,omitempty"` // post-search: matches found, including those beyond maxResults
+	TotalFiles   int           `json:"totalFiles,omitempty"`   // post-search: files searched
+	DurationMs   int64         `json:"durationMs,omitempty"`   // post-search
+}
+
+// validateHooks checks the hooks of the settings. Hooks are numbered from
+// 1 in errors.
+func validateHooks(hooks []SearchHook) error {
+	for i, h := range hooks {
+		switch h.Event {
+		case hookPreSearch, hookPostSearch:
+		case hookResultThreshold:
+			if h.Threshold <= 0 {
+				return newAppError(ErrCodeHookInvalid, i+1, "threshold must be positive")
+			}
+		default:
+			return newAppError(ErrCodeHookInvalid, i+1, fmt.Sprintf("unknown event %q", h.Event))
+		}
+		if len(h.Command) == 0 || strings.TrimSpace(h.Command[0]) == "" {
+			return newAppError(ErrCodeHookInvalid, i+1, "command is empty")
+		}
+	}
+	return nil
+}
+
+// runHooks starts the given hooks in the background with event on stdin.
+func (a *App) runHooks(hooks []SearchHook, event hookEvent) {
+	if len(hooks) == 0 {
+		return
+	}
+	event.Time = time.Now().UnixMilli()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		a.logError("Failed to encode hook event", err, logrus.Fields{"event": event.Event})
+		return
+	}
+	for _, h := range hooks {
+		a.hooksRunning.Add(1)
+		go func(h SearchHook) {
+			defer a.hooksRunning.Done()
+			a.runHook(h, event.Event, payload)
+		}(h)
+	}
+}
+
+// waitForHooks waits up to timeout for the hook commands still running,
+// so that a post-search hook started just before quitting gets to finish.
+// It reports whether they all exited.
+func (a *App) waitForHooks(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		a.hooksRunning.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		a.logWarn("Search hooks still running at shutdown", logrus.Fields{"waited": timeout.String()})
+		return false
+	}
+}
+
+// runHook runs one hook command and logs how it went.
+func (a *App) runHook(h SearchHook, event string, payload []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	hideConsoleWindow(cmd)
+	cmd.Stdin = bytes.NewReader(payload)
+	out, err := cmd.CombinedOutput()
+	if len(out) > maxHookOutputLogged {
+		out = out[:maxHookOutputLogged]
+	}
+	fields := logrus.Fields{
+		"event":   event,
+		"command": h.Command[0],
+		"output":  strings.TrimSpace(string(out)),
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("killed after %s", hookTimeout)
+		}
+		a.logError("Search hook failed", err, fields)
+		return
+	}
+	a.logInfo("Search hook ran", fields)
+}
+
+// searchHooks runs the configured hooks of one search.
+type searchHooks struct {
+	a     *App
+	hooks []SearchHook
+	base  hookEvent
+	start time.Time
+}
+
+// startSearchHooks runs the pre-search hooks of a search about to start
+// and returns the hooks for the rest of it.
+func (a *App) startSearchHooks(searchID string, req SearchRequest) *searchHooks {
+	h := &searchHooks{
+		a:     a,
+		hooks: a.currentSettings().Hooks,
+		base:  hookEvent{SearchID: searchID, Request: req},
+		start: time.Now(),
+	}
+	event := h.base
+	event.Event = hookPreSearch
+	a.runHooks(h.on(hookPreSearch), event)
+	return h
+}
+
+// on returns the hooks for an event.
+func (h *searchHooks) on(event string) []SearchHook {
+	var hooks []SearchHook
+	for _, hook := range h.hooks {
+		if hook.Event == event {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// thresholdSink returns the sink that runs the result-threshold hooks, or
+// nil when there are none.
+func (h *searchHooks) thresholdSink() ResultSink {
+	hooks := h.on(hookResultThreshold)
+	if len(hooks) == 0 {
+		return nil
+	}
+	return &thresholdSink{h: h, hooks: hooks}
+}
+
+// finish runs the post-search hooks with the outcome of the search.
+func (h *searchHooks) finish(out searchOutcome, err error) {
+	hooks := h.on(hookPostSearch)
+	if len(hooks) == 0 {
+		return
+	}
+	event := h.base
+	event.Event = hookPostSearch
+	event.DurationMs = time.Since(h.start).Milliseconds()
+	switch {
+	case err != nil:
+		event.Status = "failed"
+		event.Error = err.Error()
+	case out.rootRemoved:
+		event.Status = "incomplete"
+	case out.cancelled:
+		event.Status = "cancelled"
+	default:
+		event.Status = "completed"
+	}
+	event.ResultCount = len(out.results)
+	event.TotalMatches = out.totalMatches
+	event.TotalFiles = out.totalFiles
+	h.a.runHooks(hooks, event)
+}
+
+// thresholdSink counts results and runs each result-threshold hook when
+// the count reaches its threshold.
+type thresholdSink struct {
+	h     *searchHooks
+	hooks []SearchHook
+	count int
+}
+
+func (s *thresholdSink) AddResult(SearchResult) error {
+	s.count++
+	var due []SearchHook
+	for _, hook := range s.hooks {
+		if hook.Threshold == s.count {
+			due = append(due, hook)
+		}
+	}
+	if len(due) > 0 {
+		event := s.h.base
+		event.Event = hookResultThreshold
+		event.ResultCount = s.count
+		s.h.a.runHooks(due, event)
+	}
+	return nil
+}
+
+func (s *thresholdSink) Progress(SearchProgress) {}
+
+func (s *thresholdSink) Done(SearchProgress) {}
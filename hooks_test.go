@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHookHelperProcess is the hook command of TestSearchHooks, run by the
+// test binary itself. It saves the event it reads to a file named for the
+// event and result count in the directory CODESEARCH_HOOK_HELPER names,
+// after waiting for the duration in CODESEARCH_HOOK_DELAY, if set.
+func TestHookHelperProcess(t *testing.T) {
+	dir := os.Getenv("CODESEARCH_HOOK_HELPER")
+	if dir == "" {
+		return
+	}
+	if delay, err := time.ParseDuration(os.Getenv("CODESEARCH_HOOK_DELAY")); err == nil {
+		time.Sleep(delay)
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	var event hookEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		os.Exit(2)
+	}
+	os.WriteFile(filepath.Join(dir, fmt.Sprintf("%s-%d.json", event.Event, event.ResultCount)), data, 0o644)
+	os.Exit(0)
+}
+
+// TestSearchHooks verifies that the pre-search, result-threshold, and
+// post-search hooks each run once with the search's details, and that a
+// failing hook doesn't affect the search.
+func TestSearchHooks(t *testing.T) {
+	out := t.TempDir()
+	t.Setenv("CODESEARCH_HOOK_HELPER", out)
+	helper := []string{os.Args[0], "-test.run=^TestHookHelperProcess$"}
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	_, err := app.UpdateSettings(Settings{Hooks: []SearchHook{
+		{Event: hookPreSearch, Command: helper},
+		{Event: hookResultThreshold, Command: helper, Threshold: 2},
+		{Event: hookResultThreshold, Command: helper, Threshold: 100},
+		{Event: hookPostSearch, Command: helper},
+		{Event: hookPostSearch, Command: []string{filepath.Join(out, "missing-program")}},
+	}})
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.txt", i)), []byte("needle\n"), 0o644)
+	}
+	results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle"})
+	if err != nil || len(results) != 3 {
+		t.Fatalf("search = %d results, %v", len(results), err)
+	}
+	if !app.waitForHooks(hookTimeout) {
+		t.Fatal("hooks still running")
+	}
+
+	entries, _ := os.ReadDir(out)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if fmt.Sprint(names) != "[post-search-3.json pre-search-0.json result-threshold-2.json]" {
+		t.Fatalf("unexpected hook runs %v", names)
+	}
+	read := func(name string) hookEvent {
+		data, _ := os.ReadFile(filepath.Join(out, name))
+		var event hookEvent
+		json.Unmarshal(data, &event)
+		return event
+	}
+	pre, post := read("pre-search-0.json"), read("post-search-3.json")
+	if pre.SearchID == "" || pre.SearchID != post.SearchID || pre.Request.Query != "needle" || pre.Request.MaxResults == 0 || pre.Time == 0 {
+		t.Errorf("unexpected pre-search event %+v", pre)
+	}
+	if post.Status != "completed" || post.TotalFiles != 3 || post.TotalMatches != 3 {
+		t.Errorf("unexpected post-search event %+v", post)
+	}
+}
+
+// TestWaitForHooks verifies that shutdown's wait for running hooks gives
+// up after its timeout and otherwise returns once they exited.
+func TestWaitForHooks(t *testing.T) {
+	out := t.TempDir()
+	t.Setenv("CODESEARCH_HOOK_HELPER", out)
+	t.Setenv("CODESEARCH_HOOK_DELAY", "300ms")
+	app := NewApp()
+
+	app.runHooks([]SearchHook{{Event: hookPostSearch, Command: []string{os.Args[0], "-test.run=^TestHookHelperProcess$"}}}, hookEvent{Event: hookPostSearch})
+	if app.waitForHooks(10 * time.Millisecond) {
+		t.Error("expected the wait to time out while the hook sleeps")
+	}
+	if !app.waitForHooks(hookShutdownWait) {
+		t.Fatal("expected the hook to exit within the shutdown wait")
+	}
+	if _, err := os.Stat(filepath.Join(out, "post-search-0.json")); err != nil {
+		t.Errorf("expected the hook to have finished: %v", err)
+	}
+}
+
+// TestValidateHooks verifies that hooks with an unknown event, no command,
+// or no threshold are rejected with their number.
+func TestValidateHooks(t *testing.T) {
+	for _, hooks := range [][]SearchHook{
+		{{Event: "on-match", Command: []string{"notify"}}},
+		{{Event: hookPreSearch, Command: []string{"notify"}}, {Event: hookPostSearch}},
+		{{Event: hookResultThreshold, Command: []string{"notify"}}},
+	} {
+		err := validateHooks(hooks)
+		if err == nil || err.(*AppError).Code != ErrCodeHookInvalid || err.(*AppError).Args[0] != len(hooks) {
+			t.Errorf("validateHooks(%+v): expected %s for hook %d, got %v", hooks, ErrCodeHookInvalid, len(hooks), err)
+		}
+	}
+	if err := validateHooks([]SearchHook{{Event: hookResultThreshold, Command: []string{"notify"}, Threshold: 10}}); err != nil {
+		t.Errorf("expected a valid hook, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// hotkey is a parsed global shortcut such as "Ctrl+Shift+F".
+type hotkey struct {
+	Ctrl, Shift, Alt, Super bool
+	Key                     string // Canonical key name: "A"-"Z", "0"-"9", "F1"-"F24", or "Space"
+}
+
+// parseHotkey parses a "+"-separated shortcut. Modifier names are
+// case-insensitive and accept the usual aliases (Control, Option, Win, Cmd,
+// Meta). Exactly one non-modifier key and at least one modifier are
+// required: a bare global key would swallow that key in every other app.
+func parseHotkey(spec string) (hotkey, error) {
+	var hk hotkey
+	for _, part := range strings.Split(spec, "+") {
+		part = strings.TrimSpace(part)
+		switch strings.ToLower(part) {
+		case "ctrl", "control":
+			hk.Ctrl = true
+		case "shift":
+			hk.Shift = true
+		case "alt", "option":
+			hk.Alt = true
+		case "super", "win", "cmd", "meta", "logo":
+			hk.Super = true
+		default:
+			key, ok := canonicalHotkeyKey(part)
+			if !ok || hk.Key != "" {
+				return hotkey{}, newAppError(ErrCodeInvalidHotkey, spec)
+			}
+			hk.Key = key
+		}
+	}
+	if hk.Key == "" || !(hk.Ctrl || hk.Shift || hk.Alt || hk.Super) {
+		return hotkey{}, newAppError(ErrCodeInvalidHotkey, spec)
+	}
+	return hk, nil
+}
+
+// canonicalHotkeyKey validates a key name and returns its canonical form.
+func canonicalHotkeyKey(name string) (string, bool) {
+	upper := strings.ToUpper(name)
+	if len(upper) == 1 && (upper[0] >= 'A' && upper[0] <= 'Z' || upper[0] >= '0' && upper[0] <= '9') {
+		return upper, true
+	}
+	if upper == "SPACE" {
+		return "Space", true
+	}
+	if strings.HasPrefix(upper, "F") {
+		if n, err := strconv.Atoi(upper[1:]); err == nil && n >= 1 && n <= 24 {
+			return upper, true
+		}
+	}
+	return "", false
+}
+
+// String returns the canonical "Ctrl+Shift+F" form.
+func (hk hotkey) String() string {
+	var parts []string
+	if hk.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if hk.Shift {
+		parts = append(parts, "Shift")
+	}
+	if hk.Alt {
+		parts = append(parts, "Alt")
+	}
+	if hk.Super {
+		parts = append(parts, "Super")
+	}
+	return strings.Join(append(parts, hk.Key), "+")
+}
+
+// portalTrigger returns the shortcut in the XDG shortcuts format used by the
+// GlobalShortcuts desktop portal ("CTRL+SHIFT+f"): upper-case modifier
+// names and the key's xkb keysym name.
+func (hk hotkey) portalTrigger() string {
+	var parts []string
+	if hk.Ctrl {
+		parts = append(parts, "CTRL")
+	}
+	if hk.Shift {
+		parts = append(parts, "SHIFT")
+	}
+	if hk.Alt {
+		parts = append(parts, "ALT")
+	}
+	if hk.Super {
+		parts = append(parts, "LOGO")
+	}
+	key := hk.Key
+	switch {
+	case key == "Space":
+		key = "space"
+	case len(key) == 1:
+		key = strings.ToLower(key)
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// Win32 RegisterHotKey modifier flags.
+const (
+	winModAlt      = 0x0001
+	winModControl  = 0x0002
+	winModShift    = 0x0004
+	winModWin      = 0x0008
+	winModNoRepeat = 0x4000
+)
+
+// windowsKeys returns the RegisterHotKey modifier flags and virtual-key code.
+// MOD_NOREPEAT keeps a held shortcut from re-firing.
+func (hk hotkey) windowsKeys() (modifiers uint32, vk uint32) {
+	modifiers = winModNoRepeat
+	if hk.Ctrl {
+		modifiers |= winModControl
+	}
+	if hk.Shift {
+		modifiers |= winModShift
+	}
+	if hk.Alt {
+		modifiers |= winModAlt
+	}
+	if hk.Super {
+		modifiers |= winModWin
+	}
+	switch {
+	case hk.Key == "Space":
+		vk = 0x20
+	case len(hk.Key) == 1:
+		// VK codes for letters and digits are their ASCII upper-case values.
+		vk = uint32(hk.Key[0])
+	default:
+		n, _ := strconv.Atoi(hk.Key[1:])
+		vk = 0x70 + uint32(n-1) // VK_F1 is 0x70
+	}
+	return modifiers, vk
+}
+
+// hotkeyState tracks the currently registered global shortcut.
+type hotkeyState struct {
+	mu      sync.Mutex
+	current string // Canonical form of the registered shortcut, "" if none
+	stop    func() // Unregisters the shortcut
+}
+
+// applyHotkey registers spec as the global shortcut, replacing any previous
+// one. An empty spec just unregisters. The old shortcut is released before
+// the new one is grabbed, so re-applying the same combination works.
+func (a *App) applyHotkey(spec string) error {
+	a.hotkey.mu.Lock()
+	defer a.hotkey.mu.Unlock()
+
+	var hk hotkey
+	if spec != "" {
+		var err error
+		if hk, err = parseHotkey(spec); err != nil {
+			return err
+		}
+		if hk.String() == a.hotkey.current {
+			return nil
+		}
+	}
+
+	if a.hotkey.stop != nil {
+		a.hotkey.stop()
+		a.hotkey.stop = nil
+		a.hotkey.current = ""
+	}
+	if spec == "" {
+		return nil
+	}
+
+	stop, err := registerGlobalHotkey(hk, a.summonWindow)
+	if err != nil {
+		a.logWarn("Failed to register global hotkey", logrus.Fields{
+			"hotkey": hk.String(),
+			"error":  err.Error(),
+		})
+		return newAppError(ErrCodeHotkeyUnavailable, hk.String(), err)
+	}
+	a.hotkey.stop = stop
+	a.hotkey.current = hk.String()
+	a.logInfo("Global hotkey registered", logrus.Fields{"hotkey": hk.String()})
+	return nil
+}
+
+// releaseHotkey unregisters the global shortcut, if any.
+func (a *App) releaseHotkey() {
+	a.hotkey.mu.Lock()
+	defer a.hotkey.mu.Unlock()
+	if a.hotkey.stop != nil {
+		a.hotkey.stop()
+		a.hotkey.stop = nil
+		a.hotkey.current = ""
+	}
+}
+
+// summonWindow raises the window and tells the frontend to focus the query
+// box. It runs when the global hotkey is pressed.
+func (a *App) summonWindow() {
+	if a.ctx == nil {
+		return
+	}
+	func() {
+		defer func() {
+			recover()
+		}()
+		wailsRuntime.WindowUnminimise(a.ctx)
+		wailsRuntime.WindowShow(a.ctx)
+	}()
+	a.safeEmitEvent("focus-query", nil)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseHotkey verifies accepted spellings, canonicalization, and the
+// rejection of shortcuts without a modifier or with more than one key.
+func TestParseHotkey(t *testing.T) {
+	valid := map[string]string{
+		"Ctrl+Shift+F":        "Ctrl+Shift+F",
+		"control + shift + f": "Ctrl+Shift+F",
+		"shift+ctrl+f":        "Ctrl+Shift+F",
+		"Alt+Space":           "Alt+Space",
+		"Win+F12":             "Super+F12",
+		"cmd+option+5":        "Alt+Super+5",
+	}
+	for spec, want := range valid {
+		hk, err := parseHotkey(spec)
+		if err != nil {
+			t.Errorf("parseHotkey(%q) failed: %v", spec, err)
+			continue
+		}
+		if got := hk.String(); got != want {
+			t.Errorf("parseHotkey(%q) = %q, want %q", spec, got, want)
+		}
+	}
+
+	invalid := []string{"", "F", "Ctrl", "Ctrl+Shift", "Ctrl+F+G", "Ctrl+F25", "Ctrl+Tab", "Ctrl+é"}
+	for _, spec := range invalid {
+		_, err := parseHotkey(spec)
+		var appErr *AppError
+		if !errors.As(err, &appErr) || appErr.Code != ErrCodeInvalidHotkey {
+			t.Errorf("parseHotkey(%q): expected INVALID_HOTKEY, got %v", spec, err)
+		}
+	}
+}
+
+// TestHotkeyPlatformEncodings verifies the portal trigger string and the
+// RegisterHotKey modifier/virtual-key values.
+func TestHotkeyPlatformEncodings(t *testing.T) {
+	cases := []struct {
+		spec    string
+		trigger string
+		mods    uint32
+		vk      uint32
+	}{
+		{"Ctrl+Shift+F", "CTRL+SHIFT+f", winModNoRepeat | winModControl | winModShift, 'F'},
+		{"Alt+Space", "ALT+space", winModNoRepeat | winModAlt, 0x20},
+		{"Super+F1", "LOGO+F1", winModNoRepeat | winModWin, 0x70},
+		{"Ctrl+F24", "CTRL+F24", winModNoRepeat | winModControl, 0x87},
+		{"Ctrl+7", "CTRL+7", winModNoRepeat | winModControl, '7'},
+	}
+	for _, tc := range cases {
+		hk, err := parseHotkey(tc.spec)
+		if err != nil {
+			t.Fatalf("parseHotkey(%q) failed: %v", tc.spec, err)
+		}
+		if got := hk.portalTrigger(); got != tc.trigger {
+			t.Errorf("%s: portalTrigger = %q, want %q", tc.spec, got, tc.trigger)
+		}
+		mods, vk := hk.windowsKeys()
+		if mods != tc.mods || vk != tc.vk {
+			t.Errorf("%s: windowsKeys = (%#x, %#x), want (%#x, %#x)", tc.spec, mods, vk, tc.mods, tc.vk)
+		}
+	}
+}
+
+// TestUpdateSettingsHotkey verifies that the hotkey setting is stored in
+// canonical form and that an invalid one is rejected without saving.
+func TestUpdateSettingsHotkey(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	saved, err := app.UpdateSettings(Settings{Hotkey: " ctrl+shift+f "})
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if saved.Hotkey != "Ctrl+Shift+F" {
+		t.Errorf("expected canonical hotkey, got %q", saved.Hotkey)
+	}
+
+	if _, err := app.UpdateSettings(Settings{Hotkey: "F"}); err == nil {
+		t.Error("expected error for hotkey without modifier")
+	}
+	if got := app.GetSettings().Hotkey; got != "Ctrl+Shift+F" {
+		t.Errorf("rejected update must keep the previous hotkey, got %q", got)
+	}
+}
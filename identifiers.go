@@ -0,0 +1,145 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// identifierRun finds the identifier-like parts of a query: letters and
+// digits, joined by underscores or hyphens.
+var identifierRun = regexp.MustCompile(`[\pL\pN]+(?:[_-]+[\pL\pN]+)*`)
+
+// identifierSeparator is what may stand between the words of an expanded
+// identifier: nothing (camelCase, PascalCase), an underscore (snake_case),
+// or a hyphen (kebab-case).
+const identifierSeparator = `[_-]?`
+
+// splitIdentifier splits an identifier into its words at underscores,
+// hyphens, and case changes. An acronym stays one word up to the capital
+// that starts the next word, and digits stay with the word before them:
+//
+//	getUserId, get_user_id, GetUserID -> get User Id / get user id / Get User ID
+//	HTTPServer2Config                 -> HTTP Server2 Config
+func splitIdentifier(ident string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(ident, func(r rune) bool { return r == '_' || r == '-' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			lowerToUpper := (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur)
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// expandIdentifierQuery turns a literal query into a case-insensitive
+// pattern that also matches its identifiers in other naming conventions:
+// getUserId matches get_user_id, GetUserID, and get-user-id. Text between
+// identifiers is matched literally.
+func expandIdentifierQuery(query string) string {
+	var b strings.Builder
+	b.WriteString("(?i)")
+	last := 0
+	for _, loc := range identifierRun.FindAllStringIndex(query, -1) {
+		b.WriteString(regexp.QuoteMeta(query[last:loc[0]]))
+		for i, word := range splitIdentifier(query[loc[0]:loc[1]]) {
+			if i > 0 {
+				b.WriteString(identifierSeparator)
+			}
+			b.WriteString(regexp.QuoteMeta(word))
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(query[last:]))
+	return b.String()
+}
+
+// matchCase recases a replacement to follow the casing of the text it
+// replaces, for case-preserving replace: foo->bar, Foo->Bar, FOO->BAR. A
+// match of several words also passes on its naming convention, so
+// replacing get_user with fetchAccount writes fetch_account, GetUser
+// FetchAccount, and GET-USER FETCH-ACCOUNT. A match with no clear casing,
+// like fOO, leaves the replacement as typed.
+func matchCase(match, replacement string) string {
+	if replacement == "" {
+		return replacement
+	}
+	words := splitIdentifier(match)
+	upper, lower := strings.ToUpper(match), strings.ToLower(match)
+	if len(words) < 2 {
+		switch {
+		case match == upper && match != lower:
+			if parts := splitIdentifier(replacement); len(parts) > 1 && identifierRun.FindString(replacement) == replacement {
+				return strings.ToUpper(strings.Join(parts, "_"))
+			}
+			return strings.ToUpper(replacement)
+		case match == lower:
+			return withFirstRune(replacement, unicode.ToLower)
+		case isTitleWord(match):
+			return withFirstRune(replacement, unicode.ToUpper)
+		}
+		return replacement
+	}
+
+	// Only a replacement that is itself one identifier can take on a
+	// naming convention.
+	if identifierRun.FindString(replacement) != replacement {
+		return replacement
+	}
+	sep := ""
+	if i := strings.IndexAny(match, "_-"); i >= 0 {
+		sep = match[i : i+1]
+	}
+	parts := splitIdentifier(replacement)
+	switch {
+	case match == upper:
+		return strings.ToUpper(strings.Join(parts, sep))
+	case match == lower:
+		return strings.ToLower(strings.Join(parts, sep))
+	}
+	for _, w := range words[1:] {
+		if !isTitleWord(w) {
+			return replacement
+		}
+	}
+	for i, p := range parts {
+		parts[i] = withFirstRune(strings.ToLower(p), unicode.ToUpper)
+	}
+	switch {
+	case isTitleWord(words[0]):
+	case words[0] == strings.ToLower(words[0]):
+		parts[0] = strings.ToLower(parts[0]) // camelCase
+	default:
+		return replacement
+	}
+	return strings.Join(parts, sep)
+}
+
+// isTitleWord reports whether word is capitalized: an upper-case letter
+// followed by no upper-case letters (digits may follow).
+func isTitleWord(word string) bool {
+	for i, r := range word {
+		if i == 0 && !unicode.IsUpper(r) || i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// withFirstRune returns s with f applied to its first rune.
+func withFirstRune(s string, f func(rune) rune) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(f(r)) + s[size:]
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// TestSplitIdentifier verifies word splitting across naming conventions,
+// acronyms, and digits.
+func TestSplitIdentifier(t *testing.T) {
+	tests := map[string][]string{
+		"getUserId":         {"get", "User", "Id"},
+		"get_user_id":       {"get", "user", "id"},
+		"GetUserID":         {"Get", "User", "ID"},
+		"HTTPServer2Config": {"HTTP", "Server2", "Config"},
+		"max-results":       {"max", "results"},
+		"ALL_CAPS":          {"ALL", "CAPS"},
+		"x":                 {"x"},
+	}
+	for ident, want := range tests {
+		if got := splitIdentifier(ident); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitIdentifier(%q) = %q, want %q", ident, got, want)
+		}
+	}
+}
+
+// TestExpandIdentifierQuery verifies which spellings the expanded pattern
+// matches, and that the text around identifiers stays literal.
+func TestExpandIdentifierQuery(t *testing.T) {
+	pattern := regexp.MustCompile(expandIdentifierQuery("getUserId"))
+	for _, s := range []string{"getUserId", "get_user_id", "GetUserID", "GET_USER_ID", "get-user-id"} {
+		if !pattern.MatchString(s) {
+			t.Errorf("expected %q to match", s)
+		}
+	}
+	for _, s := range []string{"get_id", "getUser", "get__user_id"} {
+		if pattern.MatchString(s) {
+			t.Errorf("expected %q not to match", s)
+		}
+	}
+
+	call := regexp.MustCompile(expandIdentifierQuery("user.getName("))
+	if !call.MatchString("user.get_name()") || call.MatchString("userXgetName(") {
+		t.Error("expected the dot and parenthesis to be matched literally")
+	}
+}
+
+// TestSearchExpandIdentifiers verifies the option end to end and its
+// rejection in regex mode.
+func TestSearchExpandIdentifiers(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"api.go":  "func GetUserID() int {}\n",
+		"db.py":   "def get_user_id():\n    pass\n",
+		"misc.js": "const unrelated = 1;\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	results, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "getUserId", ExpandIdentifiers: true, SearchSubdirs: true})
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected matches in api.go and db.py, got %+v", results)
+	}
+
+	useRegex := true
+	_, err = app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "getUserId", ExpandIdentifiers: true, UseRegex: &useRegex})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeExpandNeedsLiteral {
+		t.Errorf("expected %s, got %v", ErrCodeExpandNeedsLiteral, err)
+	}
+}
+
+// TestMatchCase verifies that a replacement takes on the casing and naming
+// convention of the text it replaces.
+func TestMatchCase(t *testing.T) {
+	tests := []struct{ match, replacement, want string }{
+		{"foo", "bar", "bar"},
+		{"Foo", "bar", "Bar"},
+		{"FOO", "bar", "BAR"},
+		{"foo", "Bar", "bar"},
+		{"FOO", "barBaz", "BAR_BAZ"},
+		{"foo", "barBaz", "barBaz"},
+		{"getUser", "fetch_account", "fetchAccount"},
+		{"GetUser", "fetchAccount", "FetchAccount"},
+		{"get_user", "fetchAccount", "fetch_account"},
+		{"GET_USER", "fetchAccount", "FETCH_ACCOUNT"},
+		{"get-user", "FetchAccount", "fetch-account"},
+		{"Get_User", "fetchAccount", "Fetch_Account"},
+		{"fOO", "bar", "bar"},
+		{"HTTPServer", "webHost", "webHost"},
+		{"getUser", "fetch account", "fetch account"},
+	}
+	for _, tt := range tests {
+		if got := matchCase(tt.match, tt.replacement); got != tt.want {
+			t.Errorf("matchCase(%q, %q) = %q, want %q", tt.match, tt.replacement, got, tt.want)
+		}
+	}
+}
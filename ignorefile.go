@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ignoreFileName is the per-project ignore file. It lists one pattern per
+// line; blank lines and lines starting with # are ignored. A pattern
+// without a slash matches any path component (like ExcludePatterns), so
+// "testdata" skips every testdata directory and "*.snap" every snapshot. A
+// pattern with a slash (leading, inner, or both) is relative to the
+// directory holding the ignore file, e.g. "/build", "web/vendor", or
+// "docs/*.pdf".
+const ignoreFileName = ".codesearchignore"
+
+// ignoreRules are the patterns of the ignore file that applies to a search.
+type ignoreRules struct {
+	patterns []string
+	// prefix is the search directory relative to the ignore file's
+	// directory, in slash form ("" when they are the same). The walk
+	// produces paths relative to the search directory, so rooted patterns
+	// need it prepended.
+	prefix string
+}
+
+// parseIgnoreRules returns the patterns in an ignore file's content.
+func parseIgnoreRules(data []byte) []string {
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// loadIgnoreRules finds the ignore file for a search of dir: the nearest
+// one in dir or its ancestors, so searching a subdirectory of a project
+// still honours the project's rules. It returns nil when there is none.
+func loadIgnoreRules(dir string) *ignoreRules {
+	var rel []string
+	for current := dir; ; {
+		data, err := os.ReadFile(toLongPath(filepath.Join(current, ignoreFileName)))
+		if err == nil {
+			patterns := parseIgnoreRules(data)
+			if len(patterns) == 0 {
+				return nil
+			}
+			for i, j := 0, len(rel)-1; i < j; i, j = i+1, j-1 {
+				rel[i], rel[j] = rel[j], rel[i]
+			}
+			return &ignoreRules{patterns: patterns, prefix: strings.Join(rel, "/")}
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil
+		}
+		rel = append(rel, filepath.Base(current))
+		current = parent
+	}
+}
+
+// matches reports whether relPath (relative to the search directory, in
+// OS form) is ignored. Every leading sub-path is tried, so a rule naming a
+// directory also covers the files below it when the walk starts inside it.
+func (r *ignoreRules) matches(relPath string) bool {
+	if r == nil || relPath == "" || relPath == "." {
+		return false
+	}
+	full := filepath.ToSlash(relPath)
+	if r.prefix != "" {
+		full = r.prefix + "/" + full
+	}
+	components := strings.Split(full, "/")
+
+	for _, pattern := range r.patterns {
+		rooted := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+		pattern = strings.Trim(pattern, "/")
+		for i := range components {
+			candidate := components[i]
+			if rooted {
+				candidate = strings.Join(components[:i+1], "/")
+			}
+			if candidate == pattern {
+				return true
+			}
+			if matched, err := path.Match(pattern, candidate); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globEscaper escapes the characters path.Match treats specially.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// normalizeIgnoreRule turns the pattern AddIgnoreRule receives into the
+// line stored in the ignore file. An absolute path inside root (as sent when
+// the user excludes a result's folder) becomes a root-relative rule.
+func normalizeIgnoreRule(root, pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.ContainsAny(pattern, "\r\n\x00") || strings.HasPrefix(pattern, "#") {
+		return "", newAppError(ErrCodeInvalidIgnoreRule, pattern)
+	}
+
+	if filepath.IsAbs(pattern) {
+		rel, err := filepath.Rel(root, pattern)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", newAppError(ErrCodeInvalidIgnoreRule, pattern)
+		}
+		// The leading slash keeps even a top-level directory rooted
+		// instead of matching that name at every depth. Glob characters
+		// in real names are escaped so they match literally.
+		pattern = "/" + globEscaper.Replace(filepath.ToSlash(rel))
+	}
+
+	if _, err := path.Match(strings.Trim(pattern, "/"), ""); err != nil {
+		return "", newAppError(ErrCodeInvalidIgnoreRule, pattern)
+	}
+	return pattern, nil
+}
+
+// validateIgnoreRoot checks that root is an existing directory and returns
+// its absolute form.
+func (a *App) validateIgnoreRoot(root string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	if ok, err := a.ValidateDirectory(absRoot); !ok {
+		return "", err
+	}
+	return absRoot, nil
+}
+
+// GetIgnoreRules returns the patterns in root's .codesearchignore, or an
+// empty list when the file doesn't exist.
+func (a *App) GetIgnoreRules(root string) ([]string, error) {
+	absRoot, err := a.validateIgnoreRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(toLongPath(filepath.Join(absRoot, ignoreFileName)))
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, newAppError(ErrCodeIgnoreFileFailed, filepath.Join(absRoot, ignoreFileName), err)
+	}
+	patterns := parseIgnoreRules(data)
+	if patterns == nil {
+		patterns = []string{}
+	}
+	return patterns, nil
+}
+
+// AddIgnoreRule appends pattern to root's .codesearchignore, creating the
+// file if needed, so future searches of root skip the matching paths. An
+// absolute path inside root is stored relative to it. Adding a rule that
+// is already present is a no-op. It returns the rules after the change.
+func (a *App) AddIgnoreRule(root string, pattern string) ([]string, error) {
+	absRoot, err := a.validateIgnoreRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := normalizeIgnoreRule(absRoot, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := a.GetIgnoreRules(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range rules {
+		if existing == rule {
+			return rules, nil
+		}
+	}
+
+	ignorePath := filepath.Join(absRoot, ignoreFileName)
+	data, err := os.ReadFile(toLongPath(ignorePath))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, newAppError(ErrCodeIgnoreFileFailed, ignorePath, err)
+	}
+	// New lines follow the file's line endings, so editing a CRLF file
+	// doesn't leave it with mixed endings.
+	eol := lineBreak(detectLineEnding(data))
+	if len(data) == 0 {
+		data = []byte("# Paths code-search skips in this directory. One pattern per line." + eol)
+	} else if data[len(data)-1] != '\n' && data[len(data)-1] != '\r' {
+		data = append(data, eol...)
+	}
+	data = append(data, rule+eol...)
+
+	snap := snapshotForUndo(ignorePath)
+	if err := os.WriteFile(toLongPath(ignorePath), data, 0o644); err != nil {
+		a.logError("Failed to write ignore file", err, logrus.Fields{
+			"path": ignorePath,
+		})
+		return nil, newAppError(ErrCodeIgnoreFileFailed, ignorePath, err)
+	}
+
+	a.recordUndo(snap, undoKindIgnoreRule, "Add ignore rule "+rule)
+	a.logInfo("Added ignore rule", logrus.Fields{
+		"root": absRoot,
+		"rule": rule,
+	})
+	return append(rules, rule), nil
+}
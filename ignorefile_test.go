@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+// TestIgnoreRulesMatches verifies component patterns, rooted patterns, and
+// the prefix applied when the search starts below the ignore file.
+func TestIgnoreRulesMatches(t *testing.T) {
+	rules := &ignoreRules{patterns: []string{"testdata", "*.snap", "/build", "web/vendor", "docs/*.pdf", "logs/"}}
+	cases := map[string]bool{
+		"testdata":                               true,
+		filepath.Join("a", "testdata"):           true,
+		filepath.Join("a", "x.snap"):             true,
+		"build":                                  true,
+		filepath.Join("a", "build"):              false,
+		filepath.Join("web", "vendor"):           true,
+		filepath.Join("web", "vendor", "lib.js"): true,
+		filepath.Join("docs", "a.pdf"):           true,
+		filepath.Join("a", "logs"):               true,
+		filepath.Join("src", "main.go"):          false,
+	}
+	for rel, want := range cases {
+		if got := rules.matches(rel); got != want {
+			t.Errorf("matches(%q) = %v, want %v", rel, got, want)
+		}
+	}
+
+	nested := &ignoreRules{patterns: []string{"web/vendor"}, prefix: "web"}
+	if !nested.matches("vendor") {
+		t.Error("expected a rooted rule to apply when searching a subdirectory")
+	}
+	var none *ignoreRules
+	if none.matches("anything") {
+		t.Error("expected nil rules to match nothing")
+	}
+}
+
+// TestIgnoreFileAppliedToSearch verifies that collection skips paths listed
+// in .codesearchignore, including when the search starts in a subdirectory.
+func TestIgnoreFileAppliedToSearch(t *testing.T) {
+	app := NewApp()
+	root := t.TempDir()
+	for _, rel := range []string{"src/main.go", "src/gen/out.go", "tests/main_test.go", "notes.log"} {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("needle\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := "# noisy\n/tests\nsrc/gen\n*.log\n"
+	if err := os.WriteFile(filepath.Join(root, ignoreFileName), []byte(ignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	collect := func(dir string) []string {
+		req := SearchRequest{Directory: dir, Query: "needle", SearchSubdirs: true, MaxFileSize: 1 << 20, MaxResults: 100}
+		files, err := app.collectFilesToProcess(req, regexp.MustCompile("needle"), dir)
+		if err != nil {
+			t.Fatalf("collectFilesToProcess failed: %v", err)
+		}
+		var rels []string
+		for _, f := range files {
+			rel, _ := filepath.Rel(root, f.absPath)
+			rels = append(rels, filepath.ToSlash(rel))
+		}
+		sort.Strings(rels)
+		return rels
+	}
+
+	if got, want := collect(root), []string{".codesearchignore", "src/main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("collected %v, want %v", got, want)
+	}
+	if got, want := collect(filepath.Join(root, "src")), []string{"src/main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("collected %v from src, want %v", got, want)
+	}
+}
+
+// TestAddIgnoreRule verifies creating the ignore file, de-duplication,
+// conversion of absolute paths inside the root, and invalid rules.
+func TestAddIgnoreRule(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+
+	rules, err := app.GetIgnoreRules(root)
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules, got %v, %v", rules, err)
+	}
+
+	if _, err := app.AddIgnoreRule(root, "*.min.js"); err != nil {
+		t.Fatalf("AddIgnoreRule failed: %v", err)
+	}
+	if _, err := app.AddIgnoreRule(root, filepath.Join(root, "tests", "fixtures[1]")); err != nil {
+		t.Fatalf("AddIgnoreRule with an absolute path failed: %v", err)
+	}
+	rules, err = app.AddIgnoreRule(root, "*.min.js")
+	if err != nil {
+		t.Fatalf("adding a duplicate rule failed: %v", err)
+	}
+	want := []string{"*.min.js", `/tests/fixtures\[1]`}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules = %q, want %q", rules, want)
+	}
+	if got, _ := app.GetIgnoreRules(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIgnoreRules = %q, want %q", got, want)
+	}
+	if !(&ignoreRules{patterns: rules}).matches(filepath.Join("tests", "fixtures[1]")) {
+		t.Error("expected the escaped rule to match the literal directory name")
+	}
+
+	var appErr *AppError
+	for _, bad := range []string{"", "  ", "a\nb", "# comment", "[", filepath.Join(filepath.Dir(root), "elsewhere")} {
+		if _, err := app.AddIgnoreRule(root, bad); !errors.As(err, &appErr) || appErr.Code != ErrCodeInvalidIgnoreRule {
+			t.Errorf("AddIgnoreRule(%q): expected %s, got %v", bad, ErrCodeInvalidIgnoreRule, err)
+		}
+	}
+}
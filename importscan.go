@@ -0,0 +1,385 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportScanFileSize is the largest file ScanImports reads; bigger
+// source files are generated or bundled.
+const maxImportScanFileSize = 2 * 1024 * 1024
+
+// importSampleFiles is how many files ImportedPackage.SampleFiles lists.
+const importSampleFiles = 5
+
+// importScanExcludes are the dependency, build, and virtualenv directories
+// ScanImports skips. Their imports are the dependencies' own, not the
+// project's.
+var importScanExcludes = []string{"node_modules", "vendor", "dist", "build", "target", "venv", "site-packages", "__pycache__"}
+
+// importLanguages maps the extensions ScanImports reads to their language.
+var importLanguages = map[string]string{
+	".go":   "go",
+	".js":   "javascript",
+	".jsx":  "javascript",
+	".mjs":  "javascript",
+	".cjs":  "javascript",
+	".ts":   "javascript",
+	".tsx":  "javascript",
+	".vue":  "javascript",
+	".py":   "python",
+	".java": "java",
+	".kt":   "java",
+	".rb":   "ruby",
+}
+
+var (
+	jsImportPattern     = regexp.MustCompile(`(?m)^\s*(?:import|export)\s+(?:[\w*{}\s,$]+?\s+from\s+)?["']([^"'\n]+)["']`)
+	jsRequirePattern    = regexp.MustCompile(`\b(?:require|import)\(\s*["']([^"'\n]+)["']\s*\)`)
+	pyImportPattern     = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+([\w. \t,]+)`)
+	pyFromPattern       = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+([\w.]+)[ \t]+import\b`)
+	javaImportPattern   = regexp.MustCompile(`(?m)^\s*import\s+(?:static\s+)?([\w.]+)`)
+	javaPackagePattern  = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)`)
+	rubyRequirePattern  = regexp.MustCompile(`(?m)^\s*require\s*\(?\s*["']([^"'\n]+)["']`)
+	goModModulePattern  = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+	goModRequirePattern = regexp.MustCompile(`(?m)^(?:require\s+|\t)([^\s()/]+\.[^\s()]+)\s+v\S+`)
+)
+
+// nodeBuiltinModules are Node.js core modules, imported with or without
+// the "node:" prefix.
+var nodeBuiltinModules = map[string]bool{
+	"assert": true, "buffer": true, "child_process": true, "cluster": true, "crypto": true, "dgram": true,
+	"dns": true, "events": true, "fs": true, "http": true, "http2": true, "https": true, "module": true,
+	"net": true, "os": true, "path": true, "perf_hooks": true, "process": true, "querystring": true,
+	"readline": true, "stream": true, "string_decoder": true, "timers": true, "tls": true, "tty": true,
+	"url": true, "util": true, "v8": true, "vm": true, "worker_threads": true, "zlib": true,
+}
+
+// pythonStdlibModules are the Python standard library modules projects
+// commonly import.
+var pythonStdlibModules = map[string]bool{
+	"__future__": true, "abc": true, "argparse": true, "array": true, "ast": true, "asyncio": true,
+	"base64": true, "binascii": true, "bisect": true, "builtins": true, "calendar": true, "collections": true,
+	"concurrent": true, "configparser": true, "contextlib": true, "copy": true, "csv": true, "ctypes": true,
+	"dataclasses": true, "datetime": true, "decimal": true, "difflib": true, "email": true, "enum": true,
+	"errno": true, "fnmatch": true, "fractions": true, "functools": true, "gc": true, "getpass": true,
+	"glob": true, "gzip": true, "hashlib": true, "heapq": true, "hmac": true, "html": true, "http": true,
+	"importlib": true, "inspect": true, "io": true, "ipaddress": true, "itertools": true, "json": true,
+	"logging": true, "lzma": true, "math": true, "mimetypes": true, "multiprocessing": true, "operator": true,
+	"os": true, "pathlib": true, "pickle": true, "platform": true, "pprint": true, "queue": true,
+	"random": true, "re": true, "secrets": true, "select": true, "shlex": true, "shutil": true,
+	"signal": true, "socket": true, "sqlite3": true, "ssl": true, "stat": true, "statistics": true,
+	"string": true, "struct": true, "subprocess": true, "sys": true, "tarfile": true, "tempfile": true,
+	"textwrap": true, "threading": true, "time": true, "timeit": true, "tkinter": true, "traceback": true,
+	"types": true, "typing": true, "unicodedata": true, "unittest": true, "urllib": true, "uuid": true,
+	"warnings": true, "weakref": true, "xml": true, "zipfile": true, "zlib": true, "zoneinfo": true,
+}
+
+// rubyStdlibLibraries are the Ruby standard libraries projects commonly
+// require.
+var rubyStdlibLibraries = map[string]bool{
+	"benchmark": true, "csv": true, "date": true, "digest": true, "erb": true, "fileutils": true,
+	"json": true, "logger": true, "net": true, "open3": true, "optparse": true, "ostruct": true,
+	"pathname": true, "pp": true, "securerandom": true, "set": true, "shellwords": true, "socket": true,
+	"stringio": true, "tempfile": true, "time": true, "timeout": true, "uri": true, "yaml": true,
+}
+
+// javaPlatformPrefixes are the JDK and Kotlin standard library packages.
+var javaPlatformPrefixes = []string{"java.", "javax.", "jdk.", "sun.", "kotlin.", "kotlinx."}
+
+// goModule is what ScanImports needs from the root's go.mod: the module's
+// own path, to tell its packages from dependencies, and the required
+// modules, to attribute packages to them.
+type goModule struct {
+	path     string
+	requires []string
+}
+
+// readGoModule reads root/go.mod; without one, the zero goModule is
+// returned.
+func readGoModule(root string) goModule {
+	data, err := os.ReadFile(toLongPath(filepath.Join(root, "go.mod")))
+	if err != nil {
+		return goModule{}
+	}
+	var mod goModule
+	if m := goModModulePattern.FindSubmatch(data); m != nil {
+		mod.path = string(m[1])
+	}
+	for _, m := range goModRequirePattern.FindAllSubmatch(data, -1) {
+		mod.requires = append(mod.requires, string(m[1]))
+	}
+	return mod
+}
+
+// goPackage returns the dependency a Go import path belongs to: the
+// longest required module containing it, or for an unlisted one the
+// repository part of the path ("github.com/org/repo"). Standard library and
+// the module's own packages return "".
+func (mod goModule) goPackage(path string) string {
+	first, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(first, ".") {
+		return ""
+	}
+	if mod.path != "" && (path == mod.path || strings.HasPrefix(path, mod.path+"/")) {
+		return ""
+	}
+	best := ""
+	for _, req := range mod.requires {
+		if (path == req || strings.HasPrefix(path, req+"/")) && len(req) > len(best) {
+			best = req
+		}
+	}
+	if best != "" {
+		return best
+	}
+	parts := strings.Split(path, "/")
+	switch first {
+	case "github.com", "gitlab.com", "bitbucket.org", "golang.org", "google.golang.org":
+		if len(parts) > 3 {
+			parts = parts[:3]
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// jsPackage returns the npm package a JavaScript import specifier names,
+// "@scope/name" or "name", or "" for relative paths, path aliases, and
+// Node.js core modules.
+func jsPackage(spec string) string {
+	if spec == "" || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") ||
+		strings.HasPrefix(spec, "@/") || strings.HasPrefix(spec, "~") || strings.HasPrefix(spec, "node:") {
+		return ""
+	}
+	parts := strings.Split(spec, "/")
+	if strings.HasPrefix(spec, "@") {
+		if len(parts) < 2 {
+			return ""
+		}
+		return parts[0] + "/" + parts[1]
+	}
+	if nodeBuiltinModules[parts[0]] {
+		return ""
+	}
+	return parts[0]
+}
+
+// pythonModules returns the top-level modules a Python "import a.b as c, d"
+// statement list names.
+func pythonModules(list string) []string {
+	var modules []string
+	for _, item := range strings.Split(list, ",") {
+		fields := strings.Fields(item)
+		if len(fields) > 0 {
+			modules = append(modules, fields[0])
+		}
+	}
+	return modules
+}
+
+// javaPackage returns the group a Java or Kotlin import belongs to, its
+// first three package segments ("org.apache.commons"), or "" for the
+// platform libraries.
+func javaPackage(path string) string {
+	for _, prefix := range javaPlatformPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return ""
+		}
+	}
+	parts := strings.Split(path, ".")
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	return strings.Join(parts, ".")
+}
+
+// importScanner holds what ScanImports learns about the project while it
+// reads the files.
+type importScanner struct {
+	goMod          goModule
+	pythonLocal    map[string]bool // Top-level modules and packages of the project itself
+	javaOwnPackage map[string]bool // javaPackage groups the project's own files declare
+}
+
+// fileImports returns the external packages one file imports, with the
+// number of import statements for each. Java and Kotlin packages the
+// project declares itself are filtered out by ScanImports, once all files
+// have been read.
+func (s *importScanner) fileImports(path string, lang string, src []byte) map[string]int {
+	imports := make(map[string]int)
+	add := func(pkg string) {
+		if pkg != "" {
+			imports[pkg]++
+		}
+	}
+	switch lang {
+	case "go":
+		file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.ImportsOnly)
+		if err != nil {
+			return imports
+		}
+		for _, spec := range file.Imports {
+			if p, err := strconv.Unquote(spec.Path.Value); err == nil {
+				add(s.goMod.goPackage(p))
+			}
+		}
+	case "javascript":
+		for _, pattern := range []*regexp.Regexp{jsImportPattern, jsRequirePattern} {
+			for _, m := range pattern.FindAllSubmatch(src, -1) {
+				add(jsPackage(string(m[1])))
+			}
+		}
+	case "python":
+		var modules []string
+		for _, m := range pyImportPattern.FindAllSubmatch(src, -1) {
+			modules = append(modules, pythonModules(string(m[1]))...)
+		}
+		for _, m := range pyFromPattern.FindAllSubmatch(src, -1) {
+			modules = append(modules, string(m[1]))
+		}
+		for _, module := range modules {
+			top, _, _ := strings.Cut(module, ".")
+			if top != "" && !pythonStdlibModules[top] && !s.pythonLocal[top] {
+				add(top)
+			}
+		}
+	case "java":
+		for _, m := range javaImportPattern.FindAllSubmatch(src, -1) {
+			add(javaPackage(string(m[1])))
+		}
+		if m := javaPackagePattern.FindSubmatch(src); m != nil {
+			s.javaOwnPackage[javaPackage(string(m[1]))] = true
+		}
+	case "ruby":
+		for _, m := range rubyRequirePattern.FindAllSubmatch(src, -1) {
+			top, _, _ := strings.Cut(string(m[1]), "/")
+			if !rubyStdlibLibraries[top] {
+				add(top)
+			}
+		}
+	}
+	return imports
+}
+
+// pythonLocalModules returns the names a Python import of the project's
+// own code starts with: the .py files and directories at the root and
+// under src/.
+func pythonLocalModules(root string) map[string]bool {
+	local := make(map[string]bool)
+	for _, dir := range []string{root, filepath.Join(root, "src")} {
+		entries, err := os.ReadDir(toLongPath(dir))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			switch name := e.Name(); {
+			case e.IsDir():
+				local[name] = true
+			case strings.HasSuffix(name, ".py"):
+				local[strings.TrimSuffix(name, ".py")] = true
+			}
+		}
+	}
+	return local
+}
+
+// ScanImports finds the import, require, and use statements of the Go,
+// JavaScript/TypeScript (including Vue), Python, Java/Kotlin, and Ruby
+// files under root and ranks the external packages they reference by the
+// number of files importing them. Standard libraries, relative imports,
+// and the project's own packages are left out. It shows the blast radius
+// of upgrading a dependency. Dependency directories such as node_modules
+// and vendor are skipped.
+func (a *App) ScanImports(root string) (ImportScanReport, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return ImportScanReport{}, err
+	}
+
+	start := time.Now()
+	req := SearchRequest{
+		Directory:       absRoot,
+		SearchSubdirs:   true,
+		IncludeBinary:   true,
+		MaxFileSize:     maxImportScanFileSize,
+		MaxFilesPerDir:  defaultMaxFilesPerDir,
+		ExcludePatterns: importScanExcludes,
+	}
+	for ext := range importLanguages {
+		req.AllowedFileTypes = append(req.AllowedFileTypes, strings.TrimPrefix(ext, "."))
+	}
+	files, _, _, err := a.walkDirectoryTree(req, false)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return ImportScanReport{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].absPath < files[j].absPath })
+
+	scanner := &importScanner{
+		goMod:          readGoModule(absRoot),
+		pythonLocal:    pythonLocalModules(absRoot),
+		javaOwnPackage: make(map[string]bool),
+	}
+	report := ImportScanReport{Root: absRoot}
+	packages := make(map[string]*ImportedPackage)
+	for _, meta := range files {
+		lang := importLanguages[strings.ToLower(filepath.Ext(meta.absPath))]
+		src, err := os.ReadFile(toLongPath(meta.absPath))
+		if err != nil {
+			a.logDebug("Skipping unreadable file while scanning imports", logrus.Fields{"filePath": meta.absPath, "error": err.Error()})
+			continue
+		}
+		report.FilesScanned++
+		for name, count := range scanner.fileImports(meta.absPath, lang, src) {
+			key := lang + "\x00" + name
+			pkg := packages[key]
+			if pkg == nil {
+				pkg = &ImportedPackage{Name: name, Language: lang}
+				packages[key] = pkg
+			}
+			pkg.Files++
+			pkg.Imports += count
+			if len(pkg.SampleFiles) < importSampleFiles {
+				pkg.SampleFiles = append(pkg.SampleFiles, meta.absPath)
+			}
+		}
+	}
+
+	report.Packages = make([]ImportedPackage, 0, len(packages))
+	for _, pkg := range packages {
+		if pkg.Language == "java" && scanner.javaOwnPackage[pkg.Name] {
+			continue
+		}
+		report.Packages = append(report.Packages, *pkg)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool {
+		pi, pj := report.Packages[i], report.Packages[j]
+		if pi.Files != pj.Files {
+			return pi.Files > pj.Files
+		}
+		if pi.Imports != pj.Imports {
+			return pi.Imports > pj.Imports
+		}
+		if pi.Name != pj.Name {
+			return pi.Name < pj.Name
+		}
+		return pi.Language < pj.Language
+	})
+
+	a.logInfo("Import scan complete", logrus.Fields{
+		"root":            absRoot,
+		"files":           report.FilesScanned,
+		"packages":        len(report.Packages),
+		"durationSeconds": time.Since(start).Seconds(),
+	})
+	return report, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestImportPackageNames verifies how import paths map to packages in each
+// language, with standard libraries, relative imports, and the project's
+// own code left out.
+func TestImportPackageNames(t *testing.T) {
+	mod := goModule{path: "example.com/app", requires: []string{"github.com/wailsapp/wails/v2", "golang.org/x/sys"}}
+	for path, want := range map[string]string{
+		"fmt":                            "",
+		"example.com/app/internal/store": "",
+		"github.com/wailsapp/wails/v2/pkg/runtime": "github.com/wailsapp/wails/v2",
+		"golang.org/x/sys/windows/registry":        "golang.org/x/sys",
+		"github.com/stretchr/testify/assert":       "github.com/stretchr/testify",
+		"gopkg.in/yaml.v3":                         "gopkg.in/yaml.v3",
+	} {
+		if got := mod.goPackage(path); got != want {
+			t.Errorf("goPackage(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	for spec, want := range map[string]string{
+		"vue":              "vue",
+		"@vue/test-utils":  "@vue/test-utils",
+		"lodash/debounce":  "lodash",
+		"./utils":          "",
+		"@/components/App": "",
+		"fs":               "",
+		"node:path":        "",
+	} {
+		if got := jsPackage(spec); got != want {
+			t.Errorf("jsPackage(%q) = %q, want %q", spec, got, want)
+		}
+	}
+
+	if got := pythonModules("os.path as p, requests,  numpy as np"); !reflect.DeepEqual(got, []string{"os.path", "requests", "numpy"}) {
+		t.Errorf("pythonModules = %v", got)
+	}
+	if got := javaPackage("org.apache.commons.lang3.StringUtils"); got != "org.apache.commons" {
+		t.Errorf("javaPackage = %q", got)
+	}
+	if got := javaPackage("java.util.List"); got != "" {
+		t.Errorf("expected the JDK to be left out, got %q", got)
+	}
+}
+
+// TestScanImports verifies a scan of a mixed project: packages are ranked
+// by importing files, local and standard imports are left out, and
+// node_modules is skipped.
+func TestScanImports(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                    "module example.com/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/sirupsen/logrus v1.9.3\n)\n",
+		"main.go":                   "package main\n\nimport (\n\t\"fmt\"\n\tlog \"github.com/sirupsen/logrus\"\n\t\"example.com/app/store\"\n)\n",
+		"store/s.go":                "package store\n\nimport \"github.com/sirupsen/logrus\"\n",
+		"web/a.ts":                  "import { ref } from 'vue';\nimport type { Props } from \"./types\";\nimport {\n  mount,\n} from '@vue/test-utils';\n",
+		"web/b.js":                  "const vue = require('vue');\nconst fs = require('fs');\nconst lazy = import('lodash/debounce');\n",
+		"web/C.vue":                 "<template></template>\n<script setup>\nimport { computed } from 'vue'\n</script>\n",
+		"tool/x.py":                 "import os, requests\nfrom tool import helpers\nfrom numpy.linalg import norm\n",
+		"node_modules/vue/index.js": "import 'should-not-count';\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := NewApp().ScanImports(root)
+	if err != nil {
+		t.Fatalf("ScanImports failed: %v", err)
+	}
+	if report.FilesScanned != 6 {
+		t.Errorf("expected 6 files scanned, got %d", report.FilesScanned)
+	}
+
+	type row struct {
+		name, lang     string
+		files, imports int
+	}
+	var got []row
+	for _, p := range report.Packages {
+		got = append(got, row{p.Name, p.Language, p.Files, p.Imports})
+	}
+	want := []row{
+		{"vue", "javascript", 3, 3},
+		{"github.com/sirupsen/logrus", "go", 2, 2},
+		{"@vue/test-utils", "javascript", 1, 1},
+		{"lodash", "javascript", 1, 1},
+		{"numpy", "python", 1, 1},
+		{"requests", "python", 1, 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("packages = %+v, want %+v", got, want)
+	}
+	if len(report.Packages) > 0 && len(report.Packages[0].SampleFiles) != 3 {
+		t.Errorf("expected 3 sample files for vue, got %v", report.Packages[0].SampleFiles)
+	}
+}
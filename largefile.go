@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Bounds of Settings.LargeFileWarnMB. Editors such as VS Code start to
+// struggle with files of a few hundred megabytes and can freeze the
+// session on multi-gigabyte logs.
+const (
+	defaultLargeFileWarnMB = 100
+	maxLargeFileWarnMB     = 1 << 20
+)
+
+// LargeFile is a file over the size limit, listed in a LargeFileWarning.
+type LargeFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"` // Bytes
+}
+
+// LargeFileWarning is the details of a LARGE_FILE_CONFIRMATION error: the
+// files over Settings.LargeFileWarnMB that an editor was asked to open. The
+// frontend shows it and, if the user goes ahead, calls ConfirmLargeFileOpen
+// for each file and repeats the open.
+type LargeFileWarning struct {
+	Files      []LargeFile `json:"files"`
+	LimitBytes int64       `json:"limitBytes"`
+}
+
+// checkLargeFiles rejects opening paths in an editor when any of them is
+// larger than Settings.LargeFileWarnMB and hasn't been confirmed with
+// ConfirmLargeFileOpen. Files that can't be stat'ed are left to the
+// caller's own checks.
+func (a *App) checkLargeFiles(paths ...string) error {
+	limitMB := a.currentSettings().LargeFileWarnMB
+	limit := int64(limitMB) << 20
+
+	a.largeFileMu.Lock()
+	var warning LargeFileWarning
+	for _, path := range paths {
+		info, err := os.Stat(toLongPath(path))
+		if err != nil || info.Size() <= limit || a.largeFileAcks[path] {
+			continue
+		}
+		warning.Files = append(warning.Files, LargeFile{Path: path, Size: info.Size()})
+	}
+	a.largeFileMu.Unlock()
+
+	if len(warning.Files) == 0 {
+		return nil
+	}
+	warning.LimitBytes = limit
+	a.logWarn("Large file needs confirmation before opening in an editor", logrus.Fields{
+		"files":   len(warning.Files),
+		"limitMB": limitMB,
+	})
+	err := newAppError(ErrCodeLargeFileConfirmation, warning.Files[0].Path, limitMB)
+	err.Details = warning
+	return err
+}
+
+// ConfirmLargeFileOpen records that the user acknowledged a
+// LARGE_FILE_CONFIRMATION warning for a file, so it opens in editors
+// without asking again until the app restarts.
+func (a *App) ConfirmLargeFileOpen(filePath string) error {
+	cleanPath, err := a.validatePathForEditor(filePath)
+	if err != nil {
+		return err
+	}
+	a.largeFileMu.Lock()
+	if a.largeFileAcks == nil {
+		a.largeFileAcks = make(map[string]bool)
+	}
+	a.largeFileAcks[cleanPath] = true
+	a.largeFileMu.Unlock()
+
+	a.logInfo("Large file confirmed for opening", logrus.Fields{"filePath": cleanPath})
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLargeFileConfirmation verifies that opening a file over the size
+// limit needs confirmation, with the file listed in the details, and that
+// ConfirmLargeFileOpen lets it through to the editor launch.
+func TestLargeFileConfirmation(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+	if _, err := app.UpdateSettings(Settings{LargeFileWarnMB: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	large := filepath.Join(dir, "huge.log")
+	if err := os.WriteFile(large, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(large, 2<<20); err != nil {
+		t.Fatal(err)
+	}
+	small := filepath.Join(dir, "small.go")
+	if err := os.WriteFile(small, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := app.OpenInEditorByName("VSCode", large)
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeLargeFileConfirmation {
+		t.Fatalf("expected %s, got %v", ErrCodeLargeFileConfirmation, err)
+	}
+	warning, ok := appErr.Details.(LargeFileWarning)
+	if !ok || len(warning.Files) != 1 || warning.Files[0].Path != large || warning.Files[0].Size != 2<<20 || warning.LimitBytes != 1<<20 {
+		t.Errorf("unexpected details %+v", appErr.Details)
+	}
+
+	results := []SearchResult{{FilePath: small, LineNum: 1}, {FilePath: large, LineNum: 1}}
+	if _, err := app.OpenResultsInEditor("VSCode", results, 0); err == nil || err.(*AppError).Code != ErrCodeLargeFileConfirmation {
+		t.Errorf("OpenResultsInEditor: expected %s, got %v", ErrCodeLargeFileConfirmation, err)
+	}
+	if err := app.OpenInEditorByName("VSCode", small); err == nil || err.(*AppError).Code != ErrCodeEditorNotFound {
+		t.Errorf("expected a small file to go straight to the editor lookup, got %v", err)
+	}
+
+	if err := app.ConfirmLargeFileOpen(large); err != nil {
+		t.Fatalf("ConfirmLargeFileOpen failed: %v", err)
+	}
+	if err := app.OpenInEditorByName("VSCode", large); err == nil || err.(*AppError).Code != ErrCodeEditorNotFound {
+		t.Errorf("expected the confirmed file to go to the editor lookup, got %v", err)
+	}
+	if err := app.ConfirmLargeFileOpen(filepath.Join(dir, "missing.log")); err == nil || err.(*AppError).Code != ErrCodeFileNotFound {
+		t.Errorf("expected %s for a missing file, got %v", ErrCodeFileNotFound, err)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/wailsapp/wails/v2/pkg/options"
+)
+
+// launchDirFlag is the command-line flag the OS context-menu entry passes the
+// right-clicked folder with, e.g. `code-search-golang --dir /home/me/project`.
+const launchDirFlag = "--dir"
+
+// deepLinkScheme is the URL scheme of links that open a search, e.g.
+// codesearch://search?dir=/home/me/project&q=TODO, or with a whole request
+// from SerializeSearchRequest, codesearch://search?r=AXq….
+const deepLinkScheme = "codesearch"
+
+// parseLaunchArgs extracts the search to pre-fill from command-line
+// arguments. It understands, in order of precedence:
+//
+//	code-search-golang codesearch://search?dir=/path&q=query
+//	code-search-golang --dir /path        (or --dir=/path)
+//	code-search-golang /path "optional query"
+//
+// A relative directory is resolved against workingDir, which for a second
+// instance is that process's working directory rather than ours. Unknown
+// flags are skipped.
+func parseLaunchArgs(args []string, workingDir string) LaunchRequest {
+	var req LaunchRequest
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(strings.ToLower(arg), deepLinkScheme+"://"):
+			if link, ok := parseDeepLink(arg); ok {
+				return link
+			}
+		case arg == launchDirFlag && i+1 < len(args):
+			i++
+			req.Directory = args[i]
+		case strings.HasPrefix(arg, launchDirFlag+"="):
+			req.Directory = strings.TrimPrefix(arg, launchDirFlag+"=")
+		case strings.HasPrefix(arg, "-"):
+			// Not ours; Wails and the OS may add their own flags.
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if req.Directory == "" && len(positional) > 0 {
+		req.Directory = positional[0]
+		positional = positional[1:]
+	}
+	if len(positional) > 0 {
+		req.Query = positional[0]
+	}
+	if req.Directory != "" && !filepath.IsAbs(req.Directory) && workingDir != "" {
+		req.Directory = filepath.Join(workingDir, req.Directory)
+	}
+	return req
+}
+
+// parseDeepLink parses codesearch://search?dir=...&q=... and
+// codesearch://search?r=.... Only the "search" action is defined; anything
+// else is rejected so a malformed link doesn't pre-fill the form with
+// garbage. A serialized request that doesn't decode is ignored, leaving dir
+// and q.
+func parseDeepLink(link string) (LaunchRequest, bool) {
+	u, err := url.Parse(link)
+	if err != nil || !strings.EqualFold(u.Scheme, deepLinkScheme) {
+		return LaunchRequest{}, false
+	}
+	// codesearch://search?… puts the action in the host; tolerate the
+	// codesearch:search?… and codesearch:///search?… spellings too.
+	action := u.Host
+	if action == "" {
+		action = strings.Trim(u.Opaque+u.Path, "/")
+	}
+	if action != "search" {
+		return LaunchRequest{}, false
+	}
+
+	query := u.Query()
+	if encoded := query.Get(searchLinkParam); encoded != "" {
+		if shared, err := decodeSearchRequest(encoded); err == nil {
+			return LaunchRequest{Directory: shared.Directory, Query: shared.Query, Request: &shared}, true
+		}
+	}
+	return LaunchRequest{Directory: query.Get("dir"), Query: query.Get("q")}, true
+}
+
+// validatedLaunchRequest drops a launch directory that doesn't exist or isn't
+// an absolute path, keeping the query and the rest of a shared request.
+func (a *App) validatedLaunchRequest(req LaunchRequest) LaunchRequest {
+	req.Directory = a.validatedLaunchDirectory(req.Directory)
+	if req.Request != nil {
+		// Copy so that a.launch keeps the directory it was given.
+		shared := *req.Request
+		shared.Directory = req.Directory
+		req.Request = &shared
+	}
+	return req
+}
+
+// validatedLaunchDirectory returns dir cleaned, or "" if it is empty,
+// relative, or not a directory that can be searched.
+func (a *App) validatedLaunchDirectory(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(dir) {
+		a.logWarn("Ignoring relative launch directory", logrus.Fields{
+			"directory": dir,
+		})
+		return ""
+	}
+	clean := filepath.Clean(dir)
+	if ok, err := a.ValidateDirectory(clean); !ok {
+		a.logWarn("Ignoring invalid launch directory", logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		})
+		return ""
+	}
+	return clean
+}
+
+// GetLaunchRequest returns the directory and query the app was launched
+// with, so the frontend can pre-fill the search form on load. Both fields
+// are empty when the app was started without arguments.
+func (a *App) GetLaunchRequest() LaunchRequest {
+	return a.validatedLaunchRequest(a.launch)
+}
+
+// onSecondInstanceLaunch runs when the app is started again while already
+// running (from a script, an editor, the context menu, or a codesearch://
+// link). The new process exits; its arguments are forwarded here, and the
+// existing window is raised and pre-filled via the "launch-request" event.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	req := a.validatedLaunchRequest(parseLaunchArgs(data.Args, data.WorkingDirectory))
+	a.logInfo("Second instance launched", logrus.Fields{
+		"directory": req.Directory,
+		"query":     req.Query,
+	})
+	a.summonWindow()
+	if req.Directory != "" || req.Query != "" {
+		a.safeEmitEvent("launch-request", req)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestParseLaunchArgs verifies positional arguments, the --dir flag, and
+// codesearch:// links, including relative directories and skipped flags.
+func TestParseLaunchArgs(t *testing.T) {
+	wd := filepath.Join(t.TempDir(), "work")
+	abs := filepath.Join(t.TempDir(), "project")
+
+	cases := []struct {
+		name string
+		args []string
+		want LaunchRequest
+	}{
+		{"none", nil, LaunchRequest{}},
+		{"directory only", []string{abs}, LaunchRequest{Directory: abs}},
+		{"directory and query", []string{abs, "func main"}, LaunchRequest{Directory: abs, Query: "func main"}},
+		{"relative directory", []string{"src"}, LaunchRequest{Directory: filepath.Join(wd, "src")}},
+		{"dir flag", []string{"--dir", abs}, LaunchRequest{Directory: abs}},
+		{"dir flag with equals", []string{"--dir=" + abs, "TODO"}, LaunchRequest{Directory: abs, Query: "TODO"}},
+		{"dir flag without value", []string{"--dir"}, LaunchRequest{}},
+		{"unknown flags skipped", []string{"-v", abs}, LaunchRequest{Directory: abs}},
+		{"deep link", []string{"codesearch://search?dir=/srv/app&q=needle%20here"}, LaunchRequest{Directory: "/srv/app", Query: "needle here"}},
+		{"deep link wins", []string{abs, "codesearch://search?q=x"}, LaunchRequest{Query: "x"}},
+		{"deep link unknown action", []string{"codesearch://delete?dir=/srv"}, LaunchRequest{}},
+	}
+	for _, tc := range cases {
+		if got := parseLaunchArgs(tc.args, wd); got != tc.want {
+			t.Errorf("%s: parseLaunchArgs(%q) = %+v, want %+v", tc.name, tc.args, got, tc.want)
+		}
+	}
+}
+
+// TestParseDeepLinkSpellings verifies the accepted forms of the search
+// action and that other schemes are rejected.
+func TestParseDeepLinkSpellings(t *testing.T) {
+	for _, link := range []string{
+		"codesearch://search?q=a",
+		"codesearch:///search?q=a",
+		"codesearch:search?q=a",
+		"CodeSearch://search?q=a",
+	} {
+		if req, ok := parseDeepLink(link); !ok || req.Query != "a" {
+			t.Errorf("parseDeepLink(%q) = %+v, %v; want query a", link, req, ok)
+		}
+	}
+	if _, ok := parseDeepLink("https://search?q=a"); ok {
+		t.Error("expected a non-codesearch link to be rejected")
+	}
+}
+
+// TestGetLaunchRequest verifies that a valid launch directory is returned
+// cleaned, and that an invalid or relative one is dropped while the query
+// is kept.
+func TestGetLaunchRequest(t *testing.T) {
+	app := NewApp()
+	if got := app.GetLaunchRequest(); got != (LaunchRequest{}) {
+		t.Errorf("expected an empty launch request, got %+v", got)
+	}
+
+	dir := t.TempDir()
+	app.launch = LaunchRequest{Directory: dir + string(filepath.Separator), Query: "q"}
+	if got := app.GetLaunchRequest(); got.Directory != dir || got.Query != "q" {
+		t.Errorf("expected %q/q, got %+v", dir, got)
+	}
+
+	for _, bad := range []string{filepath.Join(dir, "missing"), "relative"} {
+		app.launch = LaunchRequest{Directory: bad, Query: "q"}
+		if got := app.GetLaunchRequest(); got.Directory != "" || got.Query != "q" {
+			t.Errorf("expected %q to be dropped and the query kept, got %+v", bad, got)
+		}
+	}
+}
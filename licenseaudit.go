@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// licenseHeaderScanLines is how many lines from the top of a file the
+// license audit reads looking for the header comment.
+const licenseHeaderScanLines = 64
+
+// maxLicenseAuditFiles caps the files listed in each group of a
+// LicenseAudit; the counts include the rest.
+const maxLicenseAuditFiles = 1000
+
+// licenseYearPattern is what {year} in a header template stands for: a
+// year, a range, or a list, as in "2019-2024" or "2021, 2023".
+const licenseYearPattern = `\d{4}(?:\s*[-–,]\s*\d{4})*`
+
+// licenseCommentStyles are the comment markers a source file's header
+// lines start with, and the extensions using them. The extensions are also
+// the files the audit checks, through SearchRequest.AllowedFileTypes.
+var licenseCommentStyles = []struct {
+	prefixes   []string
+	extensions []string
+}{
+	{[]string{"//", "/*", "*"}, []string{
+		"go", "js", "jsx", "mjs", "cjs", "ts", "tsx", "java", "kt", "kts", "scala", "groovy", "dart", "swift",
+		"c", "h", "cc", "cpp", "cxx", "hpp", "cs", "rs", "php", "css", "scss", "less",
+	}},
+	{[]string{"#"}, []string{"py", "sh", "bash", "zsh", "rb", "pl", "r", "ps1", "yaml", "yml", "toml"}},
+	{[]string{"<!--"}, []string{"html", "htm", "xml", "vue", "svelte"}},
+	{[]string{"--"}, []string{"sql", "lua", "hs"}},
+}
+
+// licenseCommentPrefixes returns the header comment markers for a file
+// extension (without the dot), or nil for files the audit doesn't check.
+func licenseCommentPrefixes(ext string) []string {
+	for _, style := range licenseCommentStyles {
+		for _, e := range style.extensions {
+			if e == ext {
+				return style.prefixes
+			}
+		}
+	}
+	return nil
+}
+
+// licenseMarkers are the words that make a header comment a license
+// header, so a file whose first comment is plain documentation counts as
+// missing one rather than as a mismatch.
+var licenseMarkers = []string{"copyright", "license", "spdx", "(c)", "©"}
+
+// stripCommentMarkers returns a header line without its comment syntax and
+// surrounding space: "// Copyright", " * Copyright", and "# Copyright" all
+// become "Copyright". Template lines go through it too, so a template may
+// be written with or without comment markers.
+func stripCommentMarkers(line string) string {
+	s := strings.TrimSpace(line)
+	for _, suffix := range []string{"*/", "-->"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+	}
+	for _, prefix := range []string{"<!--", "/**", "/*", "//", "--", "#", "*"} {
+		if strings.HasPrefix(s, prefix) {
+			s = strings.TrimSpace(strings.TrimPrefix(s, prefix))
+			break
+		}
+	}
+	return s
+}
+
+// normalizeHeader joins header lines, stripped of comment syntax, into one
+// string with single spaces, so line wrapping and comment style don't
+// affect the comparison.
+func normalizeHeader(lines []string) string {
+	var words []string
+	for _, line := range lines {
+		words = append(words, strings.Fields(stripCommentMarkers(line))...)
+	}
+	return strings.Join(words, " ")
+}
+
+// compileLicenseTemplate turns an expected header into a pattern matched
+// against the start of a file's normalized header. {year} matches a year,
+// range, or list; any other {name} placeholder matches any text; {{name}}
+// stands for a literal "{name}", as in query templates.
+func compileLicenseTemplate(template string) (*regexp.Regexp, error) {
+	normalized := normalizeHeader(strings.Split(template, "\n"))
+	if normalized == "" {
+		return nil, newAppError(ErrCodeLicenseTemplateRequired)
+	}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, m := range templatePlaceholder.FindAllStringSubmatchIndex(normalized, -1) {
+		pattern.WriteString(regexp.QuoteMeta(normalized[last:m[0]]))
+		switch {
+		case m[2] >= 0:
+			pattern.WriteString(regexp.QuoteMeta("{" + normalized[m[2]:m[3]] + "}"))
+		case normalized[m[4]:m[5]] == "year":
+			pattern.WriteString(licenseYearPattern)
+		default:
+			pattern.WriteString(".+?")
+		}
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(normalized[last:]))
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, newAppError(ErrCodeInvalidPattern, err)
+	}
+	return re, nil
+}
+
+// licenseHeader is the leading comment block of a file.
+type licenseHeader struct {
+	lines     []SearchResult // The comment lines, or the first code line when there is no comment
+	isComment bool
+}
+
+// readLicenseHeader reads the comment block at the top of a file, after a
+// shebang or "<?php"/"<?xml" line and blank lines. A /* */ or <!-- -->
+// block counts as comment up to its closing marker.
+func readLicenseHeader(path string, prefixes []string) (licenseHeader, error) {
+	f, err := os.Open(toLongPath(path))
+	if err != nil {
+		return licenseHeader{}, err
+	}
+	defer f.Close()
+
+	var header licenseHeader
+	closer := ""
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; lineNum <= licenseHeaderScanLines && scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if len(header.lines) == 0 && (trimmed == "" || (lineNum == 1 && (strings.HasPrefix(trimmed, "#!") || strings.HasPrefix(trimmed, "<?")))) {
+			continue
+		}
+
+		comment := closer != ""
+		if !comment {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(trimmed, prefix) {
+					comment = true
+					break
+				}
+			}
+			switch {
+			case strings.HasPrefix(trimmed, "/*") && !strings.Contains(trimmed[2:], "*/"):
+				closer = "*/"
+			case strings.HasPrefix(trimmed, "<!--") && !strings.Contains(trimmed[4:], "-->"):
+				closer = "-->"
+			}
+		} else if strings.Contains(trimmed, closer) {
+			closer = ""
+		}
+
+		if !comment {
+			if len(header.lines) == 0 {
+				header.lines = []SearchResult{{FilePath: path, LineNum: lineNum, Content: line}}
+			}
+			return header, nil
+		}
+		header.isComment = true
+		header.lines = append(header.lines, SearchResult{FilePath: path, LineNum: lineNum, Content: line})
+	}
+	return header, scanner.Err()
+}
+
+// hasLicenseMarker reports whether a normalized header reads like a
+// license header.
+func hasLicenseMarker(header string) bool {
+	lower := strings.ToLower(header)
+	for _, marker := range licenseMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLicenseHeaders checks that the source files under root start with
+// the expected header, given as plain text or as a comment, with {year}
+// for the copyright year and {name} for any other varying text. Files are
+// matched by their first comment block, ignoring comment syntax and line
+// wrapping, so one template covers every language. Files without a license
+// comment are reported as missing, those with a different one as
+// mismatched; each file is a ResultGroup holding its header lines (or its
+// first line), as FilterResults groups results.
+func (a *App) AuditLicenseHeaders(root string, expectedHeaderTemplate string) (LicenseAudit, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return LicenseAudit{}, err
+	}
+	expected, err := compileLicenseTemplate(expectedHeaderTemplate)
+	if err != nil {
+		return LicenseAudit{}, err
+	}
+
+	start := time.Now()
+	req := SearchRequest{
+		Directory:      absRoot,
+		SearchSubdirs:  true,
+		IncludeBinary:  true,
+		MaxFileSize:    math.MaxInt64,
+		MaxFilesPerDir: defaultMaxFilesPerDir,
+	}
+	for _, style := range licenseCommentStyles {
+		req.AllowedFileTypes = append(req.AllowedFileTypes, style.extensions...)
+	}
+	files, _, _, err := a.walkDirectoryTree(req, false)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return LicenseAudit{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].absPath < files[j].absPath })
+
+	audit := LicenseAudit{Root: absRoot, Missing: []ResultGroup{}, Mismatched: []ResultGroup{}}
+	for _, meta := range files {
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(meta.absPath), "."))
+		header, err := readLicenseHeader(meta.absPath, licenseCommentPrefixes(ext))
+		if err != nil {
+			a.logDebug("Skipping unreadable file in license audit", logrus.Fields{"filePath": meta.absPath, "error": err.Error()})
+			continue
+		}
+		audit.FilesChecked++
+
+		lines := make([]string, len(header.lines))
+		for i, line := range header.lines {
+			lines[i] = line.Content
+		}
+		normalized := normalizeHeader(lines)
+		group := ResultGroup{FilePath: meta.absPath, Count: len(header.lines), MatchCount: len(header.lines), Results: header.lines}
+		switch {
+		case header.isComment && expected.MatchString(normalized):
+			audit.Compliant++
+		case header.isComment && hasLicenseMarker(normalized):
+			audit.MismatchedFiles++
+			if len(audit.Mismatched) < maxLicenseAuditFiles {
+				audit.Mismatched = append(audit.Mismatched, group)
+			}
+		default:
+			audit.MissingFiles++
+			if len(audit.Missing) < maxLicenseAuditFiles {
+				audit.Missing = append(audit.Missing, group)
+			}
+		}
+	}
+	audit.Truncated = audit.MissingFiles > len(audit.Missing) || audit.MismatchedFiles > len(audit.Mismatched)
+
+	a.logInfo("License header audit complete", logrus.Fields{
+		"root":            absRoot,
+		"files":           audit.FilesChecked,
+		"missing":         audit.MissingFiles,
+		"mismatched":      audit.MismatchedFiles,
+		"durationSeconds": time.Since(start).Seconds(),
+	})
+	return audit, nil
+}
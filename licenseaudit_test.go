@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompileLicenseTemplate verifies that {year} takes years, ranges, and
+// lists, that comment syntax and wrapping in the template don't matter,
+// and that an empty template is rejected.
+func TestCompileLicenseTemplate(t *testing.T) {
+	re, err := compileLicenseTemplate("// Copyright {year} {holder}.\n// SPDX-License-Identifier: MIT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range []string{
+		"Copyright 2024 Acme Inc. SPDX-License-Identifier: MIT",
+		"Copyright 2019-2024 Acme Inc. SPDX-License-Identifier: MIT Extra notes",
+		"Copyright 2021, 2023 Someone Else. SPDX-License-Identifier: MIT",
+	} {
+		if !re.MatchString(header) {
+			t.Errorf("expected %q to match", header)
+		}
+	}
+	for _, header := range []string{
+		"Copyright Acme Inc. SPDX-License-Identifier: MIT",
+		"Copyright 2024 Acme Inc. SPDX-License-Identifier: Apache-2.0",
+	} {
+		if re.MatchString(header) {
+			t.Errorf("expected %q not to match", header)
+		}
+	}
+
+	if _, err := compileLicenseTemplate(" // \n#"); err == nil || err.(*AppError).Code != ErrCodeLicenseTemplateRequired {
+		t.Errorf("expected %s, got %v", ErrCodeLicenseTemplateRequired, err)
+	}
+}
+
+// TestAuditLicenseHeaders verifies the audit across comment styles: block
+// and line comments, shebangs, missing headers, a doc comment that is not
+// a license, a mismatched holder, and files of unchecked types.
+func TestAuditLicenseHeaders(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":     "// Copyright 2024 Acme Inc.\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"lib/util.js": "/*\n * Copyright 2020-2024 Acme Inc.\n * SPDX-License-Identifier: MIT\n */\nexport const x = 1;\n",
+		"tool.py":     "#!/usr/bin/env python3\n# Copyright 2023 Acme Inc.\n# SPDX-License-Identifier: MIT\nprint(1)\n",
+		"nohead.go":   "package main\n",
+		"doc.go":      "// Package main does things.\npackage main\n",
+		"other.ts":    "// Copyright 2024 Other Corp.\n// SPDX-License-Identifier: MIT\nexport {};\n",
+		"README.md":   "# Not checked\n",
+		"App.vue":     "<!--\n  Copyright 2024 Acme Inc.\n  SPDX-License-Identifier: MIT\n-->\n<template></template>\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	audit, err := NewApp().AuditLicenseHeaders(root, "Copyright {year} Acme Inc.\nSPDX-License-Identifier: MIT")
+	if err != nil {
+		t.Fatalf("AuditLicenseHeaders failed: %v", err)
+	}
+	if audit.FilesChecked != 7 || audit.Compliant != 4 {
+		t.Errorf("expected 7 files checked and 4 compliant, got %d and %d", audit.FilesChecked, audit.Compliant)
+	}
+
+	if audit.MissingFiles != 2 || len(audit.Missing) != 2 {
+		t.Fatalf("expected 2 missing, got %+v", audit.Missing)
+	}
+	if g := audit.Missing[0]; filepath.Base(g.FilePath) != "doc.go" || g.Count != 1 || g.Results[0].Content != "// Package main does things." {
+		t.Errorf("unexpected missing group %+v", g)
+	}
+	if g := audit.Missing[1]; filepath.Base(g.FilePath) != "nohead.go" || g.Results[0].LineNum != 1 {
+		t.Errorf("unexpected missing group %+v", g)
+	}
+
+	if audit.MismatchedFiles != 1 || len(audit.Mismatched) != 1 {
+		t.Fatalf("expected 1 mismatched, got %+v", audit.Mismatched)
+	}
+	if g := audit.Mismatched[0]; filepath.Base(g.FilePath) != "other.ts" || g.Count != 2 {
+		t.Errorf("unexpected mismatched group %+v", g)
+	}
+}
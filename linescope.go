@@ -0,0 +1,54 @@
+package main
+
+import "bytes"
+
+// lineScope limits matching to the first HeadLines lines of a file and to
+// the regions between lines containing RegionStart and RegionEnd, such as
+// "// BEGIN CONFIG" and "// END CONFIG". The marker lines themselves are
+// not matched; without RegionEnd a region runs to the end of the file.
+//
+// A lineScope follows the regions of one file, so it is made per file and
+// fed every line in order. A nil *lineScope puts every line in scope.
+type lineScope struct {
+	headLines int
+	start     []byte
+	end       []byte
+	inRegion  bool
+}
+
+// newLineScope returns the scope of a request, or nil when it matches in
+// every line.
+func newLineScope(req SearchRequest) *lineScope {
+	if req.HeadLines <= 0 && req.RegionStart == "" {
+		return nil
+	}
+	return &lineScope{
+		headLines: req.HeadLines,
+		start:     []byte(req.RegionStart),
+		end:       []byte(req.RegionEnd),
+	}
+}
+
+// next takes the line numbered lineNum (from 1) and reports whether it is
+// in scope, and whether no later line can be.
+func (s *lineScope) next(lineNum int, line []byte) (in, done bool) {
+	if s == nil {
+		return true, false
+	}
+	if s.headLines > 0 && lineNum > s.headLines {
+		return false, true
+	}
+	done = s.headLines > 0 && lineNum == s.headLines
+	if len(s.start) == 0 {
+		return true, done
+	}
+	if s.inRegion {
+		if len(s.end) > 0 && bytes.Contains(line, s.end) {
+			s.inRegion = false
+			return false, done
+		}
+		return true, done
+	}
+	s.inRegion = bytes.Contains(line, s.start)
+	return false, done
+}
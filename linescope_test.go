@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestLineScope verifies HeadLines and the region markers on their own and
+// together, including a region left open at the end of the file.
+func TestLineScope(t *testing.T) {
+	lines := []string{"x", "BEGIN", "x", "END", "x", "BEGIN", "x", "x"}
+	tests := []struct {
+		req  SearchRequest
+		want string // Lines in scope, by number
+	}{
+		{SearchRequest{}, "12345678"},
+		{SearchRequest{HeadLines: 3}, "123"},
+		{SearchRequest{RegionStart: "BEGIN", RegionEnd: "END"}, "378"},
+		{SearchRequest{RegionStart: "BEGIN"}, "345678"},
+		{SearchRequest{RegionStart: "BEGIN", RegionEnd: "END", HeadLines: 7}, "37"},
+	}
+	for _, tt := range tests {
+		scope := newLineScope(tt.req)
+		var got strings.Builder
+		for i, line := range lines {
+			in, done := scope.next(i+1, []byte(line))
+			if in {
+				got.WriteByte(byte('1' + i))
+			}
+			if done != (tt.req.HeadLines > 0 && i+1 >= tt.req.HeadLines) {
+				t.Errorf("%+v: line %d: done = %v", tt.req, i+1, done)
+			}
+		}
+		if got.String() != tt.want {
+			t.Errorf("%+v: lines in scope %s, want %s", tt.req, got.String(), tt.want)
+		}
+	}
+}
+
+// TestLineScopeSearch verifies that both matching paths, and batch
+// searches, only match inside the scope, and that an end marker without a
+// start marker is rejected.
+func TestLineScopeSearch(t *testing.T) {
+	dir := t.TempDir()
+	content := "secret = 1\n// BEGIN CONFIG\nsecret = 2\n// END CONFIG\nsecret = 3\n"
+	// Padding after the markers makes big.txt stream line by line.
+	padding := strings.Repeat("filler line\n", 8000)
+	for name, text := range map[string]string{"small.txt": content, "big.txt": content + padding} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lineNums := func(results []SearchResult) string {
+		var got []string
+		for _, r := range results {
+			got = append(got, filepath.Base(r.FilePath)+":"+string(rune('0'+r.LineNum)))
+		}
+		sort.Strings(got)
+		return strings.Join(got, " ")
+	}
+
+	app := NewApp()
+	req := SearchRequest{
+		Directory:          dir,
+		Query:              "secret",
+		RegionStart:        "// BEGIN CONFIG",
+		RegionEnd:          "// END CONFIG",
+		StreamingThreshold: minStreamingThreshold,
+	}
+	results, err := app.SearchWithProgress(req)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := lineNums(results); got != "big.txt:3 small.txt:3" {
+		t.Errorf("region search matched %s", got)
+	}
+
+	req.RegionStart, req.RegionEnd, req.HeadLines = "", "", 3
+	results, err = app.SearchWithProgress(req)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := lineNums(results); got != "big.txt:1 big.txt:3 small.txt:1 small.txt:3" {
+		t.Errorf("head search matched %s", got)
+	}
+
+	other := req
+	other.Query = "secret = 3"
+	batch, err := app.BatchSearch([]SearchRequest{req, other})
+	if err != nil {
+		t.Fatalf("BatchSearch failed: %v", err)
+	}
+	if got := lineNums(batch[0].Results); got != "big.txt:1 big.txt:3 small.txt:1 small.txt:3" || len(batch[1].Results) != 0 {
+		t.Errorf("batch head search matched %s and %d results", got, len(batch[1].Results))
+	}
+
+	req.HeadLines, req.RegionEnd = 0, "// END CONFIG"
+	if _, err := app.SearchWithProgress(req); err == nil || err.(*AppError).Code != ErrCodeRegionStartRequired {
+		t.Errorf("expected %s, got %v", ErrCodeRegionStartRequired, err)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logFilter is a compiled LogFilterRule.
+type logFilter struct {
+	level     *logrus.Level  // Matches entries at this level or more verbose; nil matches any level
+	message   *regexp.Regexp // Matches the message; nil matches any message
+	component string         // Matches the component field, ignoring case; "" matches any entry
+}
+
+// activeLogFilters are the filters the log viewer applies, replaced whole
+// when the settings change. Until the settings are first applied, at
+// startup, it is nil and defaultLogFilters apply.
+var activeLogFilters atomic.Pointer[[]logFilter]
+
+// defaultCompiledLogFilters are defaultLogFilters, compiled.
+var defaultCompiledLogFilters, _ = compileLogFilters(defaultLogFilters())
+
+// defaultLogFilters hide the per-file progress lines that flood the log
+// during a search and add no value in the viewer.
+func defaultLogFilters() []LogFilterRule {
+	return []LogFilterRule{{Message: "Skipping"}, {Message: "Sending file"}}
+}
+
+// compileLogFilters checks and compiles rules. Every rule needs at least
+// one condition, so a blank rule can't hide the whole log.
+func compileLogFilters(rules []LogFilterRule) ([]logFilter, error) {
+	filters := make([]logFilter, 0, len(rules))
+	for i, rule := range rules {
+		var f logFilter
+		if rule.Level == "" && rule.Message == "" && rule.Component == "" {
+			return nil, newAppError(ErrCodeLogFilterInvalid, i+1, "no level, message, or component to match")
+		}
+		if rule.Level != "" {
+			level, err := logrus.ParseLevel(rule.Level)
+			if err != nil {
+				return nil, newAppError(ErrCodeLogFilterInvalid, i+1, fmt.Sprintf("unknown level %q", rule.Level))
+			}
+			f.level = &level
+		}
+		if rule.Message != "" {
+			re, err := regexp.Compile(rule.Message)
+			if err != nil {
+				return nil, newAppError(ErrCodeLogFilterInvalid, i+1, err.Error())
+			}
+			f.message = re
+		}
+		f.component = rule.Component
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// validateLogFilters checks rules for UpdateSettings.
+func validateLogFilters(rules []LogFilterRule) error {
+	_, err := compileLogFilters(rules)
+	return err
+}
+
+// applyLogFilters makes rules the filters of the log viewer, for entries
+// read from then on. Saved rules that no longer compile are logged and the
+// defaults apply instead.
+func (a *App) applyLogFilters(rules []LogFilterRule) {
+	filters, err := compileLogFilters(rules)
+	if err != nil {
+		a.logWarn("Ignoring invalid saved log filters", logrus.Fields{"error": err.Error()})
+		filters = defaultCompiledLogFilters
+	}
+	activeLogFilters.Store(&filters)
+}
+
+// currentLogFilters returns the filters in effect.
+func currentLogFilters() []logFilter {
+	if filters := activeLogFilters.Load(); filters != nil {
+		return *filters
+	}
+	return defaultCompiledLogFilters
+}
+
+// matches reports whether an entry with the given level, message, and
+// component meets every condition of f.
+func (f logFilter) matches(level logrus.Level, msg, component string) bool {
+	return (f.level == nil || level >= *f.level) &&
+		(f.message == nil || f.message.MatchString(msg)) &&
+		(f.component == "" || strings.EqualFold(f.component, component))
+}
+
+// logContentFiltered reports whether a log entry matches one of the
+// filters in effect. content is a plain line, whose message is the line
+// itself and whose level is info, or a structured logrus entry.
+func logContentFiltered(content interface{}) bool {
+	var msg, component string
+	switch v := content.(type) {
+	case string:
+		msg = v
+	case map[string]interface{}:
+		msg, _ = v["msg"].(string)
+		component, _ = v["component"].(string)
+	}
+	level := logContentLevel(content)
+	for _, f := range currentLogFilters() {
+		if f.matches(level, msg, component) {
+			return true
+		}
+	}
+	return false
+}
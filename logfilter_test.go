@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// restoreLogFilters puts the log filters in effect back when the test ends,
+// since they are process-wide.
+func restoreLogFilters(t *testing.T) {
+	t.Helper()
+	saved := activeLogFilters.Load()
+	t.Cleanup(func() { activeLogFilters.Store(saved) })
+}
+
+// TestLogFiltersFromSettings verifies that the rules of the logFilters
+// setting replace the defaults, by level, message, and component, and that
+// the tail path (AddLogEntry) and the file-reading path (parseLogLine)
+// agree on every entry.
+func TestLogFiltersFromSettings(t *testing.T) {
+	restoreLogFilters(t)
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{LogFilters: []LogFilterRule{
+		{Level: "debug"},
+		{Component: "hooks", Message: "^Hook finished"},
+		{Message: `^Heartbeat \d+`},
+	}}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	tests := []struct {
+		entry  map[string]interface{}
+		hidden bool
+	}{
+		{map[string]interface{}{"level": "debug", "msg": "Collecting files"}, true},
+		{map[string]interface{}{"level": "trace", "msg": "Walk entry"}, true},
+		{map[string]interface{}{"level": "warning", "msg": "Skipping unreadable stored search"}, false},
+		{map[string]interface{}{"level": "info", "msg": "Hook finished", "component": "Hooks"}, true},
+		{map[string]interface{}{"level": "info", "msg": "Hook finished"}, false},
+		{map[string]interface{}{"level": "info", "msg": "Hook started", "component": "hooks"}, false},
+		{map[string]interface{}{"level": "info", "msg": "Heartbeat 42"}, true},
+	}
+	for _, tt := range tests {
+		line, _ := json.Marshal(tt.entry)
+		if _, skip := parseLogLine(string(line)); skip != tt.hidden {
+			t.Errorf("parseLogLine(%s): hidden = %v, want %v", line, skip, tt.hidden)
+		}
+		if _, skip := parseLogEntryMessage(tt.entry); skip != tt.hidden {
+			t.Errorf("parseLogEntryMessage(%v): hidden = %v, want %v", tt.entry, skip, tt.hidden)
+		}
+	}
+	if _, skip := parseLogLine("Heartbeat 7 from a plain line"); !skip {
+		t.Error("expected a plain line to be matched by its text")
+	}
+}
+
+// TestLogFiltersDefaults verifies that no saved rules mean the default
+// rules, and that an empty list hides nothing.
+func TestLogFiltersDefaults(t *testing.T) {
+	restoreLogFilters(t)
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	saved, err := app.UpdateSettings(Settings{})
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if len(saved.LogFilters) != len(defaultLogFilters()) {
+		t.Errorf("expected the default rules, got %+v", saved.LogFilters)
+	}
+	if _, skip := parseLogEntryMessage("Skipping binary file"); !skip {
+		t.Error("expected the default rules to hide Skipping lines")
+	}
+
+	if saved, err = app.UpdateSettings(Settings{LogFilters: []LogFilterRule{}}); err != nil || len(saved.LogFilters) != 0 {
+		t.Fatalf("expected an empty rule list to be kept, got %+v, %v", saved.LogFilters, err)
+	}
+	if _, skip := parseLogEntryMessage("Skipping binary file"); skip {
+		t.Error("expected nothing hidden without rules")
+	}
+}
+
+// TestLogFiltersInvalid verifies that a rule without conditions, an unknown
+// level, or a bad pattern is rejected.
+func TestLogFiltersInvalid(t *testing.T) {
+	restoreLogFilters(t)
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	for _, rule := range []LogFilterRule{{}, {Level: "loud"}, {Message: "("}} {
+		_, err := app.UpdateSettings(Settings{LogFilters: []LogFilterRule{{Message: "ok"}, rule}})
+		if err == nil || err.(*AppError).Code != ErrCodeLogFilterInvalid {
+			t.Errorf("rule %+v: expected %s, got %v", rule, ErrCodeLogFilterInvalid, err)
+		}
+	}
+}
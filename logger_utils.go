@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"runtime"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
@@ -25,7 +27,7 @@ func (a *App) setupLogger() {
 	logger.SetLevel(logrus.DebugLevel)
 
 	// Create logs directory if it doesn't exist
-	err := os.MkdirAll("logs", 0o755)
+	err := os.MkdirAll(a.logDir, 0o755)
 	if err != nil {
 		fmt.Printf("Failed to create logs directory: %v\n", err)
 		logger.SetOutput(os.Stdout) // fallback to stdout
@@ -34,7 +36,7 @@ func (a *App) setupLogger() {
 	}
 
 	// Create log file
-	logFile, err := os.OpenFile("logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o666)
+	logFile, err := os.OpenFile(filepath.Join(a.logDir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o666)
 	if err == nil {
 		// Create a multi-writer to write to both file and stdout
 		logger.SetOutput(io.MultiWriter(logFile, os.Stdout))
@@ -76,6 +78,30 @@ func (a *App) startup(ctx context.Context) {
 	// registration are not ordered).
 	a.markReady()
 
+	// Hide the log entries the user's filters select from the log viewer.
+	a.applyLogFilters(a.currentSettings().LogFilters)
+
+	// Desktop notifications for long searches (see notifySearchFinished).
+	a.initNotifications()
+
+	// Register the saved global hotkey. The Linux desktop portal may ask the
+	// user to confirm the binding, so this must not block startup.
+	if hk := a.currentSettings().Hotkey; hk != "" {
+		go a.applyHotkey(hk)
+	}
+
+	// Serve the search metrics if the user turned the endpoint on. A port
+	// taken by another program is logged and the app starts without it.
+	if addr := a.currentSettings().MetricsAddr; addr != "" {
+		_ = a.applyMetricsAddr(addr)
+	}
+
+	// Look for a new release if the user opted in. The check goes to the
+	// network, so it runs in the background and failures are only logged.
+	if a.currentSettings().CheckUpdatesOnStartup {
+		go a.checkForUpdatesOnStartup()
+	}
+
 	// Detect available editors in the background (this will emit its own
 	// progress/completion events as results come in).
 	go a.detectAvailableEditors()
@@ -125,47 +151,135 @@ func (a *App) logDebug(message string, fields logrus.Fields) {
 	}
 }
 
-// isBinary checks if content appears to be binary by looking for null bytes
-// and a high proportion of non-text characters
+// Binary detection samples binarySampleSize bytes from the start of a file,
+// and for files of binaryMultiSampleMin bytes or more also from the middle
+// and the end: archives, PDFs, and media often turn binary after a textual
+// header.
+const (
+	binarySampleSize     = 512
+	binaryMultiSampleMin = 64 * 1024
+)
+
+// binarySampleOffsets returns the offsets of the n windows sampled in a
+// file of size bytes. An array keeps isBinary free of allocations.
+func binarySampleOffsets(size int64) (offsets [3]int64, n int) {
+	if size < binaryMultiSampleMin {
+		return offsets, 1
+	}
+	offsets[1] = size/2 - binarySampleSize/2
+	offsets[2] = size - binarySampleSize
+	return offsets, 3
+}
+
+// isBinary checks if content appears to be binary. It samples the windows
+// given by binarySampleOffsets; UTF-16 text, recognized by its BOM or by
+// its null bytes, is judged on its decoded first window instead.
 func (a *App) isBinary(content []byte) bool {
 	if len(content) == 0 {
 		return false
 	}
+	head := content[:min(len(content), binarySampleSize)]
+	if enc, bom := detectEncoding(head); isUTF16(enc) {
+		return utf16IsBinary(head[bom:], enc)
+	}
+	offsets, n := binarySampleOffsets(int64(len(content)))
+	for i, off := range offsets[:n] {
+		end := min(int(off)+binarySampleSize, len(content))
+		if windowIsBinary(content[off:end], i > 0) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Check for null bytes in the first 512 bytes using bytes.Contains so we
-	// don't allocate a string just to scan for a single byte (#7). The
-	// previous implementation did string(content[:min(512,len(content))])
-	// on every file, which is a measurable cost on large corpora.
-	checkLen := 512
-	if len(content) < checkLen {
-		checkLen = len(content)
+// fileIsBinary is isBinary for an open file of size bytes. It reads only
+// the sampled windows, into buf (binarySampleSize bytes).
+func (a *App) fileIsBinary(file io.ReaderAt, size int64, buf []byte) (bool, error) {
+	offsets, n := binarySampleOffsets(size)
+	for i, off := range offsets[:n] {
+		m, err := file.ReadAt(buf, off)
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		window := buf[:m]
+		if i == 0 {
+			if enc, bom := detectEncoding(window); isUTF16(enc) {
+				return utf16IsBinary(window[bom:], enc), nil
+			}
+		}
+		if windowIsBinary(window, i > 0) {
+			return true, nil
+		}
 	}
-	if bytes.Contains(content[:checkLen], nullByte) {
+	return false, nil
+}
+
+// windowIsBinary judges one sample window. A null byte means binary.
+// Otherwise valid UTF-8 is text unless over a tenth of it is control
+// characters. Anything else is legacy 8-bit text or binary data: random
+// bytes are about one tenth control characters, text next to none, so
+// over 3% means binary. mid is set for windows that start inside the
+// file, whose first bytes may belong to a rune cut at the window edge.
+func windowIsBinary(w []byte, mid bool) bool {
+	if bytes.IndexByte(w, 0) >= 0 {
 		return true
 	}
+	w = trimCutRunes(w, mid)
+	if len(w) == 0 {
+		return false
+	}
+	control := 0
+	for _, b := range w {
+		if isControlByte(b) {
+			control++
+		}
+	}
+	if utf8.Valid(w) {
+		return control*10 > len(w)
+	}
+	return control*100 > len(w)*3
+}
 
-	// Count printable vs non-printable characters in first part of file
-	// For UTF-8 text, we need to be more lenient as many Unicode characters have high bytes
-	printableCount := 0
-	for i, b := range content {
-		if i >= checkLen { // Only check first 512 bytes for performance
-			break
+// isControlByte reports whether b is an ASCII control character other than
+// whitespace and the escape that starts terminal color codes.
+func isControlByte(b byte) bool {
+	switch b {
+	case '\t', '\n', '\v', '\f', '\r', 0x1B:
+		return false
+	}
+	return b < 0x20 || b == 0x7F
+}
+
+// trimCutRunes drops the bytes of a rune cut by the end of w and, with mid,
+// the continuation bytes of one cut by its start, so utf8.Valid only fails
+// on genuinely invalid text.
+func trimCutRunes(w []byte, mid bool) []byte {
+	if mid {
+		for i := 0; i < utf8.UTFMax-1 && len(w) > 0 && !utf8.RuneStart(w[0]); i++ {
+			w = w[1:]
 		}
-		// Printable ASCII range (space through ~) and common whitespace
-		// Also consider high-byte values as potentially printable for UTF-8
-		if (b >= 32 && b <= 126) || b == '\n' || b == '\r' || b == '\t' || (b >= 127 && b <= 255) {
-			printableCount++
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(w); i++ {
+		if utf8.RuneStart(w[len(w)-i]) {
+			if !utf8.FullRune(w[len(w)-i:]) {
+				w = w[:len(w)-i]
+			}
+			break
 		}
 	}
-
-	// If less than 70% of characters are printable, consider it binary
-	// For UTF-8 content, we'll be more lenient
-	return float64(printableCount)/float64(checkLen) < 0.5
+	return w
 }
 
-// nullByte is a single-element slice used by bytes.Contains for the binary
-// detection check. Declared once to avoid per-call allocation.
-var nullByte = []byte{0}
+// utf16IsBinary judges a window of UTF-16 text by its decoded form, so
+// binary data that merely has the null byte pattern of UTF-16 (arrays of
+// small 16-bit integers) still shows up as control characters.
+func utf16IsBinary(w []byte, enc string) bool {
+	decoded, err := utf16Decoder(enc).Bytes(w[:len(w)&^1])
+	if err != nil {
+		return true
+	}
+	return windowIsBinary(decoded, false)
+}
 
 // matchesPattern checks if a path matches an exclude pattern.
 // It matches against individual path components so that patterns like "git"
@@ -257,20 +371,38 @@ func matchExtension(path string, requestedExt string) bool {
 	return false
 }
 
+// isProtectedDirectory reports whether dir (absolute and cleaned) is a
+// system-critical directory that must not be searched. This helps prevent
+// system hangs when traversal resolves to high-level directories. Only exact
+// matches are blocked, not parent directories like /tmp.
+func isProtectedDirectory(dir string) bool {
+	var protectedPaths []string
+	if runtime.GOOS == "windows" {
+		protectedPaths = []string{
+			"C:\\", "C:\\Windows", "C:\\Windows\\System32", "C:\\Windows\\System",
+			"C:\\Program Files", "C:\\Program Files (x86)", "C:\\Users", "C:\\Documents and Settings",
+		}
+	} else {
+		protectedPaths = []string{"/", "/usr", "/bin", "/sbin", "/lib", "/lib64", "/proc", "/sys", "/dev", "/etc"}
+	}
+	for _, protected := range protectedPaths {
+		if dir == protected {
+			return true
+		}
+	}
+	return false
+}
+
 // validateAndSetDefaults validates the search request and sets default values
 func (a *App) validateAndSetDefaults(req SearchRequest) (SearchRequest, error) {
-	// Set default values for optional parameters
-	modifiedReq := req
-	if modifiedReq.MaxFileSize == 0 {
-		modifiedReq.MaxFileSize = 10 * 1024 * 1024 // 10MB default
-	}
-	if modifiedReq.MaxResults <= 0 {
-		modifiedReq.MaxResults = 1000 // 1000 results default
+	modifiedReq, err := a.setSearchDefaults(req)
+	if err != nil {
+		return req, err
 	}
 
 	// Validate directory is not empty
 	if modifiedReq.Directory == "" {
-		return req, fmt.Errorf("directory does not exist: empty directory path provided")
+		return req, newAppError(ErrCodeDirectoryRequired)
 	}
 
 	// Before proceeding with file operations, validate that the final resolved directory is not a result of
@@ -279,34 +411,134 @@ func (a *App) validateAndSetDefaults(req SearchRequest) (SearchRequest, error) {
 
 	// Validate directory exists before starting the search
 	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
-		return req, fmt.Errorf("directory does not exist: %s", cleanPath)
+		return req, newAppError(ErrCodeDirectoryNotFound, cleanPath)
 	}
 
 	// Get absolute path for internal processing
 	absDir, err := filepath.Abs(cleanPath)
 	if err != nil {
-		return req, fmt.Errorf("failed to get absolute path for directory: %v", err)
+		return req, newAppError(ErrCodeDirectoryInvalid, err)
 	}
 
 	// Additional check: prevent searching system-critical directories
-	// This helps prevent system hangs when traversal resolves to high-level directories
-	// Only block exact matches of critical system directories (not parent directories like /tmp)
-	var protectedPaths []string
-	if runtime.GOOS == "windows" {
-		protectedPaths = []string{
-			"C:\\", "C:\\Windows", "C:\\Windows\\System32", "C:\\Windows\\System",
-			"C:\\Program Files", "C:\\Program Files (x86)", "C:\\Users", "C:\\Documents and Settings",
+	cleanBaseDir := filepath.Clean(absDir)
+	if isProtectedDirectory(cleanBaseDir) {
+		return req, newAppError(ErrCodeProtectedDirectory, cleanBaseDir)
+	}
+
+	// Network shares and FUSE mounts get slow-FS mode even when the user
+	// didn't ask for it; the parallel opens of the normal mode are what make
+	// searching them so painful.
+	if !modifiedReq.SlowFS && isNetworkPath(cleanBaseDir) {
+		modifiedReq.SlowFS = true
+	}
+
+	// Obviously expensive patterns need the user's go-ahead; the file-count
+	// check runs after collection in SearchWithProgress.
+	if err := checkPatternCost(modifiedReq); err != nil {
+		return req, err
+	}
+
+	return modifiedReq, nil
+}
+
+// setSearchDefaults fills in the defaults of a request's optional settings
+// and checks that its modes can be combined. It doesn't look at the
+// directory, so searches that read from elsewhere (SearchBucket) share it.
+func (a *App) setSearchDefaults(req SearchRequest) (SearchRequest, error) {
+	// Filters typed into the query (ext:go, size:<1mb) become fields first,
+	// so the defaults and checks below see them.
+	modifiedReq, err := applyQueryOperators(req)
+	if err != nil {
+		return req, err
+	}
+	if modifiedReq.AllowedFileTypes, err = expandFileTypeBundles(modifiedReq.AllowedFileTypes); err != nil {
+		return req, err
+	}
+	if modifiedReq.MaxFileSize == 0 {
+		modifiedReq.MaxFileSize = defaultMaxFileSize
+	}
+	if modifiedReq.MaxResults <= 0 {
+		modifiedReq.MaxResults = 1000 // 1000 results default
+	}
+	if modifiedReq.ModifiedAfter > 0 && modifiedReq.ModifiedBefore > 0 && modifiedReq.ModifiedAfter >= modifiedReq.ModifiedBefore {
+		return req, newAppError(ErrCodeModifiedRangeEmpty)
+	}
+	if (modifiedReq.OwnedByMe || modifiedReq.PermissionMask != 0) && !ownerFiltersSupported {
+		return req, newAppError(ErrCodeOwnerFiltersUnsupported)
+	}
+	if modifiedReq.PermissionMask > 0o7777 {
+		return req, newAppError(ErrCodePermissionMaskInvalid, modifiedReq.PermissionMask)
+	}
+	if modifiedReq.RegionEnd != "" && modifiedReq.RegionStart == "" {
+		return req, newAppError(ErrCodeRegionStartRequired)
+	}
+	if modifiedReq.MaxFilesPerDir == 0 {
+		modifiedReq.MaxFilesPerDir = defaultMaxFilesPerDir
+	}
+	if modifiedReq.StreamingThreshold <= 0 || modifiedReq.ScannerBufferSize <= 0 {
+		settings := a.currentSettings()
+		if modifiedReq.StreamingThreshold <= 0 {
+			modifiedReq.StreamingThreshold = settings.StreamingThreshold
+		}
+		if modifiedReq.ScannerBufferSize <= 0 {
+			modifiedReq.ScannerBufferSize = settings.ScannerBufferSize
 		}
-	} else {
-		protectedPaths = []string{"/", "/usr", "/bin", "/sbin", "/lib", "/lib64", "/proc", "/sys", "/dev", "/etc"}
 	}
-	cleanBaseDir := filepath.Clean(absDir)
-	for _, protected := range protectedPaths {
-		if cleanBaseDir == protected {
-			return req, fmt.Errorf("searching in protected system directory not allowed: %s", cleanBaseDir)
+	modifiedReq.StreamingThreshold = clampInt64(modifiedReq.StreamingThreshold, minStreamingThreshold, maxStreamingThreshold)
+	modifiedReq.ScannerBufferSize = int(clampInt64(int64(modifiedReq.ScannerBufferSize), minScannerBufferSize, maxScannerBufferSize))
+	// Identifier expansion builds its own pattern from the literal query.
+	if modifiedReq.ExpandIdentifiers {
+		if modifiedReq.UseRegex != nil && *modifiedReq.UseRegex {
+			return req, newAppError(ErrCodeExpandNeedsLiteral)
+		}
+		if modifiedReq.Fuzziness != 0 {
+			return req, newAppError(ErrCodeFuzzyNeedsLiteral)
+		}
+		literal := false
+		modifiedReq.UseRegex = &literal
+	}
+	// Capture groups only exist in a regex, and the naming-variant and
+	// fuzzy modes build their own pattern from a literal query.
+	if modifiedReq.ExtractGroups {
+		if (modifiedReq.UseRegex != nil && !*modifiedReq.UseRegex) || modifiedReq.Fuzziness != 0 {
+			return req, newAppError(ErrCodeExtractNeedsRegex)
+		}
+	}
+	// Fuzzy matching runs the bitap matcher on the literal query, so the
+	// regexp compiled from it is only used for its literal form.
+	if modifiedReq.Fuzziness != 0 {
+		if modifiedReq.UseRegex != nil && *modifiedReq.UseRegex {
+			return req, newAppError(ErrCodeFuzzyNeedsLiteral)
+		}
+		if len(modifiedReq.Query) > maxFuzzyQueryLength {
+			return req, newAppError(ErrCodeFuzzyQueryTooLong, maxFuzzyQueryLength)
+		}
+		literal := false
+		modifiedReq.UseRegex = &literal
+		modifiedReq.Fuzziness = effectiveFuzziness(modifiedReq.Query, modifiedReq.Fuzziness)
+	}
+	// With a result log every match is kept on disk, so there is nothing
+	// to sample.
+	if modifiedReq.ResultLogPath != "" {
+		if !filepath.IsAbs(modifiedReq.ResultLogPath) {
+			return req, newAppError(ErrCodeResultsExportFailed, modifiedReq.ResultLogPath, errors.New("path must be absolute"))
+		}
+		modifiedReq.Sampling = false
+	}
+	if modifiedReq.Sampling {
+		if modifiedReq.SamplingThreshold <= 0 {
+			modifiedReq.SamplingThreshold = defaultSamplingThreshold
+		}
+		if modifiedReq.SamplingThreshold > maxSamplingThreshold {
+			modifiedReq.SamplingThreshold = maxSamplingThreshold
+		}
+		// Sampling from fewer matches than are returned is just a
+		// truncated search.
+		if modifiedReq.SamplingThreshold < modifiedReq.MaxResults {
+			modifiedReq.SamplingThreshold = modifiedReq.MaxResults
 		}
 	}
-
 	return modifiedReq, nil
 }
 
@@ -321,7 +553,11 @@ func (a *App) compileSearchPattern(req SearchRequest) (*regexp.Regexp, error) {
 		useRegex = *req.UseRegex
 	}
 
-	if useRegex {
+	if req.ExpandIdentifiers {
+		// Naming conventions differ in case, so the expansion is always
+		// case-insensitive.
+		pattern, err = regexp.Compile(expandIdentifierQuery(req.Query))
+	} else if useRegex {
 		// If using regex, use the query as-is (with case sensitivity flag)
 		searchPattern := req.Query
 		if !req.CaseSensitive {
@@ -345,7 +581,10 @@ func (a *App) compileSearchPattern(req SearchRequest) (*regexp.Regexp, error) {
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("invalid search pattern: %v", err)
+		return nil, newAppError(ErrCodeInvalidPattern, err)
+	}
+	if req.ExtractGroups && pattern.NumSubexp() == 0 {
+		return nil, newAppError(ErrCodeNoCaptureGroups)
 	}
 
 	return pattern, nil
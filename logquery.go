@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Page sizes of GetLogs. The buffer holds at most maxLogEntries entries, so
+// a larger page could never be filled.
+const (
+	defaultLogPageSize = 100
+	maxLogPageSize     = maxLogEntries
+)
+
+// LogPage is a page of log entries returned by GetLogs.
+type LogPage struct {
+	Entries []LogMessage `json:"entries"` // Oldest first
+	Total   int          `json:"total"`   // Entries matching the filters in the whole buffer
+	HasMore bool         `json:"hasMore"` // Older matching entries exist beyond this page
+}
+
+// logContentLevel returns the level of a log entry's content. Plain-text
+// lines and entries without a recognizable level count as info.
+func logContentLevel(content interface{}) logrus.Level {
+	if fields, ok := content.(map[string]interface{}); ok {
+		if name, ok := fields["level"].(string); ok {
+			if level, err := logrus.ParseLevel(name); err == nil {
+				return level
+			}
+		}
+	}
+	return logrus.InfoLevel
+}
+
+// logEntryText returns the text of a buffered entry that search matches:
+// the line itself, or the message and field values of a structured entry.
+// Field names are left out so searching for "level" doesn't match every
+// entry.
+func logEntryText(entry LogMessage) string {
+	fields, ok := entry.Content.(map[string]interface{})
+	if !ok {
+		return fmt.Sprint(entry.Content)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, fmt.Sprint(fields[key]))
+	}
+	return strings.Join(values, "\n")
+}
+
+// QueryLogEntries returns a page of the buffered entries at or above
+// minLevel whose text contains search, ignoring case. offset counts matching
+// entries back from the newest, so offset 0 is the latest page. It doesn't
+// move the GetNewLogEntries cursor.
+func (p *PollingLogManager) QueryLogEntries(offset, limit int, minLevel logrus.Level, search string) LogPage {
+	search = strings.ToLower(search)
+
+	p.mutex.RLock()
+	matches := []LogMessage{}
+	for _, entry := range p.logEntries {
+		if logContentLevel(entry.Content) > minLevel {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(logEntryText(entry)), search) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	p.mutex.RUnlock()
+
+	end := max(len(matches)-offset, 0)
+	start := max(end-limit, 0)
+	return LogPage{
+		Entries: matches[start:end:end],
+		Total:   len(matches),
+		HasMore: start > 0,
+	}
+}
+
+// GetLogs returns a page of the log entries kept in memory, newest page
+// first: limit entries (100 when 0 or less, at most 1000) after skipping the
+// offset newest matches, in chronological order. minLevel keeps entries at
+// that severity or above ("warn" keeps warnings and errors; empty keeps
+// all), and search keeps those whose message or fields contain it, ignoring
+// case. Like GetNewLogs it is a Wails binding, so the log panel needs no
+// socket of its own.
+func (a *App) GetLogs(offset, limit int, minLevel, search string) (LogPage, error) {
+	level := logrus.TraceLevel
+	if minLevel != "" {
+		parsed, err := logrus.ParseLevel(minLevel)
+		if err != nil {
+			return LogPage{}, newAppError(ErrCodeLogLevelInvalid, minLevel)
+		}
+		level = parsed
+	}
+	if limit <= 0 {
+		limit = defaultLogPageSize
+	}
+	limit = min(limit, maxLogPageSize)
+	offset = max(offset, 0)
+
+	pm := GetPollingManager()
+	if pm == nil {
+		return LogPage{Entries: []LogMessage{}}, nil
+	}
+	return pm.QueryLogEntries(offset, limit, level, search), nil
+}
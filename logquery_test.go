@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fillLogBuffer installs a fresh polling manager holding entries, as the
+// tailer would have added them.
+func fillLogBuffer(t *testing.T, entries ...LogMessage) {
+	t.Helper()
+	InitializePollingLogManager()
+	t.Cleanup(func() { _ = GetPollingManager().Shutdown() })
+	for _, entry := range entries {
+		GetPollingManager().AddLogEntry(entry)
+	}
+}
+
+// structuredLog returns a buffered logrus JSON entry.
+func structuredLog(level, msg string, fields map[string]interface{}) LogMessage {
+	content := map[string]interface{}{"level": level, "msg": msg}
+	for k, v := range fields {
+		content[k] = v
+	}
+	return LogMessage{Type: "log", Content: content}
+}
+
+// logMessages returns the msg of each entry, or the line of plain ones.
+func logMessages(page LogPage) []string {
+	var msgs []string
+	for _, entry := range page.Entries {
+		if fields, ok := entry.Content.(map[string]interface{}); ok {
+			msgs = append(msgs, fields["msg"].(string))
+		} else {
+			msgs = append(msgs, fmt.Sprint(entry.Content))
+		}
+	}
+	return msgs
+}
+
+// TestGetLogsFilters verifies the level and search filters, plain-text lines
+// counting as info, and that field values are searched but field names are
+// not.
+func TestGetLogsFilters(t *testing.T) {
+	fillLogBuffer(t,
+		structuredLog("debug", "Collecting files", nil),
+		structuredLog("info", "Search completed", map[string]interface{}{"directory": "/repo/api"}),
+		LogMessage{Type: "log", Content: "plain line"},
+		structuredLog("warning", "Slow search", nil),
+		structuredLog("error", "Failed to save settings", nil),
+	)
+	app := NewApp()
+
+	tests := []struct {
+		minLevel, search string
+		want             []string
+	}{
+		{"", "", []string{"Collecting files", "Search completed", "plain line", "Slow search", "Failed to save settings"}},
+		{"info", "", []string{"Search completed", "plain line", "Slow search", "Failed to save settings"}},
+		{"warn", "", []string{"Slow search", "Failed to save settings"}},
+		{"", "SEARCH", []string{"Search completed", "Slow search"}},
+		{"", "/repo/api", []string{"Search completed"}},
+		{"", "level", nil},
+	}
+	for _, tt := range tests {
+		page, err := app.GetLogs(0, 0, tt.minLevel, tt.search)
+		if err != nil {
+			t.Fatalf("GetLogs(%q, %q) failed: %v", tt.minLevel, tt.search, err)
+		}
+		if got := logMessages(page); fmt.Sprint(got) != fmt.Sprint(tt.want) || page.Total != len(tt.want) {
+			t.Errorf("GetLogs(%q, %q) = %v (total %d), want %v", tt.minLevel, tt.search, got, page.Total, tt.want)
+		}
+	}
+
+	if _, err := app.GetLogs(0, 0, "loud", ""); err == nil || err.(*AppError).Code != ErrCodeLogLevelInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeLogLevelInvalid, err)
+	}
+}
+
+// TestGetLogsPagination verifies that pages are counted back from the
+// newest entry, come out oldest first, and leave the GetNewLogs cursor
+// alone.
+func TestGetLogsPagination(t *testing.T) {
+	var entries []LogMessage
+	for i := range 5 {
+		entries = append(entries, structuredLog("info", fmt.Sprintf("entry %d", i), nil))
+	}
+	fillLogBuffer(t, entries...)
+	app := NewApp()
+
+	tests := []struct {
+		offset, limit int
+		want          string
+		hasMore       bool
+	}{
+		{0, 2, "[entry 3 entry 4]", true},
+		{2, 2, "[entry 1 entry 2]", true},
+		{4, 2, "[entry 0]", false},
+		{10, 2, "[]", false},
+	}
+	for _, tt := range tests {
+		page, err := app.GetLogs(tt.offset, tt.limit, "", "")
+		if err != nil {
+			t.Fatalf("GetLogs failed: %v", err)
+		}
+		if got := fmt.Sprint(logMessages(page)); got != tt.want || page.HasMore != tt.hasMore || page.Total != 5 {
+			t.Errorf("GetLogs(%d, %d) = %s (hasMore %v, total %d), want %s (hasMore %v)", tt.offset, tt.limit, got, page.HasMore, page.Total, tt.want, tt.hasMore)
+		}
+	}
+
+	if got := len(app.GetNewLogs()); got != 5 {
+		t.Errorf("expected GetLogs to leave the 5 entries for GetNewLogs, got %d", got)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+// longPathsSupported is reported by GetCapabilities. Outside Windows there is
+// no 260-character limit to work around.
+const longPathsSupported = true
+
+// toLongPath returns the path unchanged. Only Windows limits paths to
+// MAX_PATH (260 characters); see longpathWindows.go for the \\?\ handling.
+func toLongPath(path string) string {
+	return path
+}
+
+// fromLongPath returns the path unchanged. It is the inverse of toLongPath.
+func fromLongPath(path string) string {
+	return path
+}
+
+// shellPath returns the path unchanged. On Windows it converts long paths to
+// a form that external programs (editors, explorer) can open.
+func shellPath(path string) string {
+	return path
+}
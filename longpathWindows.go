@@ -0,0 +1,76 @@
+//go:build windows
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// longPathPrefix is the Win32 "extended-length path" prefix. Paths carrying
+// it bypass the MAX_PATH (260 character) limit in the file APIs, which is
+// what makes deep node_modules trees reachable.
+const longPathPrefix = `\\?\`
+
+// longPathUNCPrefix is the extended-length form of a UNC share path
+// (\\server\share becomes \\?\UNC\server\share).
+const longPathUNCPrefix = `\\?\UNC\`
+
+// longPathsSupported is reported by GetCapabilities. File access goes
+// through toLongPath and the manifest declares longPathAware, so paths beyond
+// MAX_PATH are searchable and openable.
+const longPathsSupported = true
+
+// maxShortPath is the length at which a path needs the extended-length
+// prefix. It is MAX_PATH minus room for an 8.3 file name, the same threshold
+// the Go runtime uses for directory operations.
+const maxShortPath = 248
+
+// toLongPath converts a path that is too long for the classic Win32 APIs into
+// its \\?\ extended-length form. Short paths and paths that already carry the
+// prefix are returned unchanged, as are paths that cannot be made absolute
+// (the prefix is only valid on absolute paths).
+func toLongPath(path string) string {
+	if len(path) < maxShortPath || strings.HasPrefix(path, longPathPrefix) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return longPathUNCPrefix + abs[2:]
+	}
+	return longPathPrefix + abs
+}
+
+// fromLongPath strips the extended-length prefix added by toLongPath so paths
+// reported to the user (results, logs, events) look like ordinary paths.
+func fromLongPath(path string) string {
+	if strings.HasPrefix(path, longPathUNCPrefix) {
+		return `\\` + path[len(longPathUNCPrefix):]
+	}
+	return strings.TrimPrefix(path, longPathPrefix)
+}
+
+// shellPath returns a form of path that external programs can open. Editors
+// and explorer.exe are not reliably long-path aware, and most of them reject
+// the \\?\ prefix, so long paths are converted to their 8.3 short name. If
+// the short name is unavailable (8.3 generation disabled on the volume) the
+// original path is returned and the program gets its best chance.
+func shellPath(path string) string {
+	if len(path) < maxShortPath {
+		return path
+	}
+	long, err := syscall.UTF16PtrFromString(toLongPath(path))
+	if err != nil {
+		return path
+	}
+	buf := make([]uint16, len(path)+1)
+	n, err := syscall.GetShortPathName(long, &buf[0], uint32(len(buf)))
+	if err != nil || n == 0 || int(n) > len(buf) {
+		return path
+	}
+	return fromLongPath(syscall.UTF16ToString(buf[:n]))
+}
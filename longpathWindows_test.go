@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestToLongPathRoundTrip verifies that long absolute paths gain the
+// extended-length prefix, short paths are untouched, and fromLongPath undoes
+// the conversion for both drive-letter and UNC paths.
+func TestToLongPathRoundTrip(t *testing.T) {
+	short := `C:\src\project\main.go`
+	if got := toLongPath(short); got != short {
+		t.Errorf("expected short path unchanged, got %q", got)
+	}
+
+	long := `C:\` + strings.Repeat(`node_modules\pkg\`, 20) + `index.js`
+	converted := toLongPath(long)
+	if !strings.HasPrefix(converted, longPathPrefix) {
+		t.Fatalf("expected %q prefix on long path, got %q", longPathPrefix, converted)
+	}
+	if back := fromLongPath(converted); back != long {
+		t.Errorf("round trip mismatch: got %q, want %q", back, long)
+	}
+
+	unc := `\\server\share\` + strings.Repeat(`deep\`, 60) + `file.txt`
+	convertedUNC := toLongPath(unc)
+	if !strings.HasPrefix(convertedUNC, longPathUNCPrefix) {
+		t.Fatalf("expected %q prefix on long UNC path, got %q", longPathUNCPrefix, convertedUNC)
+	}
+	if back := fromLongPath(convertedUNC); back != unc {
+		t.Errorf("UNC round trip mismatch: got %q, want %q", back, unc)
+	}
+
+	if again := toLongPath(converted); again != converted {
+		t.Errorf("expected already-prefixed path unchanged, got %q", again)
+	}
+}
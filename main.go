@@ -16,12 +16,10 @@ var assets embed.FS
 func main() {
 	// Create an instance of the app structure
 	app := NewApp()
-
-	// Ensure the logs directory exists
-	logDir := "logs"
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		log.Printf("Error creating logs directory: %v", err)
-	}
+	// A directory and query from the command line, a codesearch:// link, or
+	// the file manager's context menu; the frontend pre-fills them on load.
+	wd, _ := os.Getwd()
+	app.launch = parseLaunchArgs(os.Args[1:], wd)
 
 	// Initialize the polling log manager and start tailing the log file.
 	// The manager's in-memory buffer is consumed by the frontend via the
@@ -30,7 +28,7 @@ func main() {
 
 	pollingManager := GetPollingManager()
 	if pollingManager != nil {
-		pollingManager.StartLogTailing()
+		pollingManager.StartLogTailing(app.logDir)
 	}
 
 	// Create application with options
@@ -44,6 +42,24 @@ func main() {
 		BackgroundColour: &options.RGBA{R: 255, G: 255, B: 255, A: 1},
 		OnStartup:        app.startup,
 		OnShutdown:       app.shutdown,
+		// Native file drop delivers absolute paths to the frontend's
+		// OnFileDrop handler, which passes them to HandleDroppedPaths. The
+		// webview's own drop handling is disabled so a dropped file isn't
+		// opened as a page.
+		DragAndDrop: &options.DragAndDrop{
+			EnableFileDrop:     true,
+			DisableWebViewDrop: true,
+		},
+		// Launching the app again (a script, an editor, a codesearch://
+		// link) raises this window and forwards the arguments instead of
+		// opening a second one.
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "code-search-golang-8e5c1f8a",
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
+		// Rejected binding calls carry {code, message} instead of a bare
+		// string; see formatError.
+		ErrorFormatter: app.formatError,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSpansPerLine caps SearchResult.Spans; a minified line can hold
+// thousands of matches of a short query.
+const maxSpansPerLine = 100
+
+// matchSpans returns the matches on line as spans of its trimmed form,
+// SearchResult.Content. Matching runs on the untrimmed line, so anchored
+// patterns behave as they do for the match itself; spans are then shifted
+// past the trimmed indentation and clipped to the content. Empty matches
+// are skipped.
+func matchSpans(matcher lineMatcher, line string) []MatchSpan {
+	locs := matcher.FindAllStringIndex(line, maxSpansPerLine)
+	if len(locs) == 0 {
+		return nil
+	}
+	content := strings.TrimSpace(line)
+	lead := len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+
+	var spans []MatchSpan
+	runePos, bytePos := 0, 0
+	for _, loc := range locs {
+		start := min(max(loc[0]-lead, 0), len(content))
+		end := min(max(loc[1]-lead, 0), len(content))
+		if start >= end {
+			continue
+		}
+		// Matches come in order, so rune offsets are counted incrementally.
+		runePos += utf8.RuneCountInString(content[bytePos:start])
+		runeStart := runePos
+		runePos += utf8.RuneCountInString(content[start:end])
+		bytePos = end
+		spans = append(spans, MatchSpan{Start: start, End: end, RuneStart: runeStart, RuneEnd: runePos})
+	}
+	return spans
+}
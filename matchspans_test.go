@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestMatchSpans verifies byte and rune offsets into the trimmed content
+// for multibyte text, matches inside the trimmed indentation, the fuzzy
+// matcher, and the per-line cap.
+func TestMatchSpans(t *testing.T) {
+	got := matchSpans(regexp.MustCompile(`検索|🔍`), "\t  x := \"🔍 検索\" // 検索")
+	want := []MatchSpan{
+		{Start: 6, End: 10, RuneStart: 6, RuneEnd: 7},
+		{Start: 11, End: 17, RuneStart: 8, RuneEnd: 10},
+		{Start: 22, End: 28, RuneStart: 15, RuneEnd: 17},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchSpans = %+v, want %+v", got, want)
+	}
+	content := strings.TrimSpace("\t  x := \"🔍 検索\" // 検索")
+	for _, s := range got {
+		if runes := []rune(content); string(runes[s.RuneStart:s.RuneEnd]) != content[s.Start:s.End] {
+			t.Errorf("rune span %+v does not cover the same text as its byte span", s)
+		}
+	}
+
+	if got := matchSpans(regexp.MustCompile(`^\s+`), "    indented"); got != nil {
+		t.Errorf("expected a match inside the trimmed indentation to be dropped, got %+v", got)
+	}
+	if got := matchSpans(regexp.MustCompile(`x*`), "abc"); got != nil {
+		t.Errorf("expected empty matches to be skipped, got %+v", got)
+	}
+
+	fuzzy := newBitapMatcher("receive", 1, false)
+	if got := matchSpans(fuzzy, "ünï receve and receive"); len(got) != 2 || got[0].RuneStart != 4 || got[1].RuneStart != 15 {
+		t.Errorf("unexpected fuzzy spans %+v", got)
+	}
+
+	if got := matchSpans(regexp.MustCompile(`a`), strings.Repeat("a", 500)); len(got) != maxSpansPerLine {
+		t.Errorf("expected %d spans, got %d", maxSpansPerLine, len(got))
+	}
+}
+
+// TestSearchResultSpans verifies that both file reading paths, buffered
+// and streamed, fill in the spans of every match on a line.
+func TestSearchResultSpans(t *testing.T) {
+	dir := t.TempDir()
+	content := "    ✅ done ✅ done\n" + strings.Repeat("filler line\n", 8000)
+	if err := os.WriteFile(filepath.Join(dir, "emoji.txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := []MatchSpan{{Start: 4, End: 8, RuneStart: 2, RuneEnd: 6}, {Start: 13, End: 17, RuneStart: 9, RuneEnd: 13}}
+
+	for _, threshold := range []int64{0, minStreamingThreshold} {
+		useRegex := false
+		app := NewApp()
+		app.dataDir = t.TempDir()
+		results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "done", UseRegex: &useRegex, StreamingThreshold: threshold})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		if len(results) != 1 || !reflect.DeepEqual(results[0].Spans, want) {
+			t.Errorf("threshold %d: spans = %+v, want %+v", threshold, results, want)
+		}
+	}
+}
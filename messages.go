@@ -0,0 +1,294 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultLocale is the locale used until the frontend calls SetLocale, and
+// the fallback for codes a locale's catalog doesn't cover.
+const defaultLocale = "en"
+
+// messageCatalog maps locale -> error code -> fmt template. The templates of
+// one code must take the same arguments, in the same order, in every locale.
+// The English templates are the messages the backend returned before error
+// codes existed; keep them stable, since Go tests and older frontend code
+// match on their text.
+var messageCatalog = map[string]map[ErrorCode]string{
+	"en": {
+		ErrCodePathRequired:            "file path is required",
+		ErrCodePathTraversal:           "invalid file path: contains directory traversal",
+		ErrCodePathNullByte:            "invalid file path: contains null bytes",
+		ErrCodeFileNotFound:            "file does not exist: %s",
+		ErrCodeFileStatFailed:          "failed to get file info: %v",
+		ErrCodeFileTooLarge:            "file too large to read: %s (size: %d, max: %d)",
+		ErrCodeFileReadFailed:          "failed to read file: %v",
+		ErrCodeLineOutOfRange:          "line %d out of range: %s has %d lines",
+		ErrCodeDirectoryRequired:       "directory does not exist: empty directory path provided",
+		ErrCodeDirectoryInvalid:        "invalid directory path: %v",
+		ErrCodeDirectoryNotFound:       "directory does not exist: %s",
+		ErrCodeNotADirectory:           "path is not a directory: %s",
+		ErrCodeDirectoryNotAccessible:  "directory is not accessible: %s",
+		ErrCodeProtectedDirectory:      "searching in protected system directory not allowed: %s",
+		ErrCodeInvalidPattern:          "invalid search pattern: %v",
+		ErrCodeNoActiveSearch:          "no active search to cancel",
+		ErrCodeEditorNotFound:          "editor '%s' not found in system PATH: %v",
+		ErrCodeUnknownEditor:           "unknown editor binding: %q",
+		ErrCodeEditorPathInvalid:       "path for editor %s must be an existing file given as an absolute path: %q",
+		ErrCodeNoTerminal:              "no terminal emulator found to run %s; install x-terminal-emulator, gnome-terminal, konsole, or xterm, or set $TERMINAL",
+		ErrCodeEditorCheckFailed:       "could not start %s: %v",
+		ErrCodeEditorCheckExit:         "%s %s exited with status %d",
+		ErrCodeEditorCheckTimeout:      "%s %s did not exit within %s",
+		ErrCodeNoFileManager:           "could not open %s in a file manager (tried %s)",
+		ErrCodeEditorLaunchFailed:      "failed to open file in %s: %v",
+		ErrCodeDefaultEditorFailed:     "failed to open file in default editor: %v",
+		ErrCodeNotImplemented:          "%s folder opening not implemented",
+		ErrCodeUnsupportedPlatform:     "unsupported platform: %s",
+		ErrCodeDialogUnavailable:       "no valid context available for dialog - application may not be fully initialized",
+		ErrCodeDialogFailed:            "failed to open directory dialog: %v",
+		ErrCodeFileDialogFailed:        "failed to open file dialog: %v",
+		ErrCodeLargeFileConfirmation:   "%s is larger than %d MB and may freeze the editor; confirm to open it anyway",
+		ErrCodeEditorNoProjects:        "%s cannot open a project folder",
+		ErrCodeLicenseTemplateRequired: "expected license header is required",
+		ErrCodeImageUnsupported:        "%s is not a PNG, JPEG, or GIF image",
+		ErrCodeImageTooLarge:           "%s is %dx%d pixels, more than a thumbnail is made from",
+		ErrCodeExportFormatUnsupported: "unsupported export format %q; use %s",
+		ErrCodeSearchLinkInvalid:       "search link is not valid: %v",
+		ErrCodeBucketURLInvalid:        "%s is not a bucket URL; use s3://bucket/prefix or gs://bucket/prefix",
+		ErrCodeBucketCredentials:       "could not load %s credentials: %v",
+		ErrCodeBucketListFailed:        "could not list %s: %v",
+		ErrCodePluginNotFound:          "no plugin named %s",
+		ErrCodePluginInvalid:           "plugin %s cannot be enabled: %s",
+		ErrCodeHookInvalid:             "hook %d is not valid: %s",
+		ErrCodeQueryOperatorInvalid:    "%s is not a valid filter: %s",
+		ErrCodeFavoriteColorInvalid:    "%s is not a color; use #rgb or #rrggbb",
+		ErrCodeFavoriteNotFound:        "%s is not a favorite",
+		ErrCodeBatchTooLarge:           "a batch of %d queries is too large; at most %d can run at once",
+		ErrCodeBatchQueryInvalid:       "query %d of the batch: %s",
+		ErrCodeBatchFiltersDiffer:      "query %d of the batch selects other files than the first; only queries and matching options may differ",
+		ErrCodeCooccurrencePatterns:    "a co-occurrence search needs 2 to %d non-empty patterns",
+		ErrCodeRegionStartRequired:     "a region end marker needs a region start marker",
+		ErrCodeModifiedRangeEmpty:      "the modified-after time must be earlier than the modified-before time",
+		ErrCodeOwnerFiltersUnsupported: "owner and permission filters need a local Unix filesystem",
+		ErrCodePermissionMaskInvalid:   "permission mask %#o has bits outside 07777",
+		ErrCodeReplaceUnsupported:      "a search with typos allowed or files without match has no exact matches to replace",
+		ErrCodeReplaceFileChanged:      "%s changed since the search; run it again before replacing",
+		ErrCodeReplaceUTF16:            "%s is UTF-16, which replacement patches don't support",
+		ErrCodeUndoNotFound:            "action %s can no longer be undone",
+		ErrCodeUndoConflict:            "%s changed after the action; undoing would lose those changes",
+		ErrCodeUndoFailed:              "could not restore %s: %v",
+		ErrCodeRenameNameInvalid:       "%q is not an identifier",
+		ErrCodeRenameUnchanged:         "%s and %s differ only in case or naming style",
+		ErrCodeFileTypeBundleUnknown:   "unknown file type bundle @%s",
+		ErrCodeContextFileChanged:      "%s changed since the search; run it again to see more context",
+		ErrCodeDirectionInvalid:        "direction must be \"next\" or \"previous\", not %q",
+		ErrCodeFileNotInResults:        "%s has no results in search %s",
+		ErrCodeMetricsAddrInvalid:      "metrics address %q must be a loopback host and port, e.g. 127.0.0.1:9464",
+		ErrCodeMetricsUnavailable:      "could not serve metrics on %s: %v",
+		ErrCodeDiagnosticsFailed:       "could not write diagnostics bundle %s: %v",
+		ErrCodeUpdateChannelInvalid:    "update channel must be \"stable\" or \"beta\", not %q",
+		ErrCodeUpdateCheckFailed:       "could not check for updates: %v",
+		ErrCodeUpdateAssetNotFound:     "release %s has no %s download",
+		ErrCodeUpdateDownloadFailed:    "could not download update to %s: %v",
+		ErrCodeLogDirInvalid:           "logs directory must be an absolute path: %s",
+		ErrCodeLogLevelInvalid:         "unknown log level %q; use trace, debug, info, warn, error, or fatal",
+		ErrCodeLogFilterInvalid:        "log filter %d is not valid: %s",
+		ErrCodeWorkspaceNameRequired:   "workspace name is required",
+		ErrCodeWorkspaceNameTaken:      "a workspace named %q already exists",
+		ErrCodeWorkspaceRootInvalid:    "workspace root must be an absolute path: %s",
+		ErrCodeWorkspaceNotFound:       "workspace not found: %s",
+		ErrCodeSavedSearchNameRequired: "saved search name is required",
+		ErrCodeUnsupportedLocale:       "unsupported locale: %s",
+		ErrCodeInvalidHotkey:           "invalid hotkey %q: use modifiers plus one key, e.g. Ctrl+Shift+F",
+		ErrCodeHotkeyUnavailable:       "could not register hotkey %s: %v",
+		ErrCodeShellIntegrationFailed:  "failed to update the context-menu entry: %v",
+		ErrCodeNoResultsToOpen:         "none of the matched files could be found",
+		ErrCodeNoRemoteLink:            "no GitHub, GitLab, Bitbucket, or Gitea remote found for %s",
+		ErrCodeInvalidIgnoreRule:       "invalid ignore rule %q",
+		ErrCodeIgnoreFileFailed:        "failed to access %s: %v",
+		ErrCodeSearchNotFound:          "search %s is no longer available; run it again",
+		ErrCodeConfirmationRequired:    "this search is likely to be slow; confirm to run it anyway",
+		ErrCodeSearchRootRemoved:       "directory %s was removed during the search; results are incomplete",
+		ErrCodeResultsExportFailed:     "could not write results to %s: %v",
+		ErrCodeInvalidStoreFilter:      "invalid result store filter: %s",
+		ErrCodeResultStoreFailed:       "could not read the result store: %v",
+		ErrCodeIndexFailed:             "could not index %s: %v",
+		ErrCodeNoIndex:                 "no directory has been indexed yet",
+		ErrCodeFuzzyNeedsLiteral:       "fuzzy matching works on literal queries; turn off regex search",
+		ErrCodeFuzzyQueryTooLong:       "fuzzy queries can be at most %d bytes long",
+		ErrCodeExpandNeedsLiteral:      "naming-variant matching works on literal queries; turn off regex search",
+		ErrCodeTemplateNameRequired:    "template name is required",
+		ErrCodeTemplateQueryRequired:   "template query is required",
+		ErrCodeTemplateNotFound:        "template not found: %s",
+		ErrCodeTemplateVariableMissing: "no value given for template variable {%s}",
+		ErrCodeExtractNeedsRegex:       "capture groups can only be extracted from regex searches",
+		ErrCodeNoCaptureGroups:         "the pattern has no capture groups to extract; wrap the parts you want in ( )",
+		ErrCodeNoCaptures:              "search %s did not extract capture groups; run it again with Extract Groups on",
+		ErrCodeUnknownCaptureGroup:     "the pattern has no capture group %q",
+		ErrCodeNoQuickfix:              "no results have been exported as a quickfix list yet",
+		ErrCodeQuickfixUnsupported:     "%s cannot load a quickfix list; use Vim, Neovim, Neovide, or Emacs",
+	},
+	"id": {
+		ErrCodePathRequired:            "path file wajib diisi",
+		ErrCodePathTraversal:           "path file tidak valid: mengandung penelusuran direktori",
+		ErrCodePathNullByte:            "path file tidak valid: mengandung byte null",
+		ErrCodeFileNotFound:            "file tidak ditemukan: %s",
+		ErrCodeFileStatFailed:          "gagal membaca info file: %v",
+		ErrCodeFileTooLarge:            "file terlalu besar untuk dibaca: %s (ukuran: %d, maks: %d)",
+		ErrCodeFileReadFailed:          "gagal membaca file: %v",
+		ErrCodeLineOutOfRange:          "baris %d di luar jangkauan: %s memiliki %d baris",
+		ErrCodeDirectoryRequired:       "direktori tidak ditemukan: path direktori kosong",
+		ErrCodeDirectoryInvalid:        "path direktori tidak valid: %v",
+		ErrCodeDirectoryNotFound:       "direktori tidak ditemukan: %s",
+		ErrCodeNotADirectory:           "path bukan direktori: %s",
+		ErrCodeDirectoryNotAccessible:  "direktori tidak dapat diakses: %s",
+		ErrCodeProtectedDirectory:      "pencarian di direktori sistem yang dilindungi tidak diizinkan: %s",
+		ErrCodeInvalidPattern:          "pola pencarian tidak valid: %v",
+		ErrCodeNoActiveSearch:          "tidak ada pencarian aktif untuk dibatalkan",
+		ErrCodeEditorNotFound:          "editor '%s' tidak ditemukan di PATH sistem: %v",
+		ErrCodeUnknownEditor:           "binding editor tidak dikenal: %q",
+		ErrCodeEditorPathInvalid:       "path editor %s harus berupa file yang ada dengan path absolut: %q",
+		ErrCodeNoTerminal:              "tidak ada emulator terminal untuk menjalankan %s; pasang x-terminal-emulator, gnome-terminal, konsole, atau xterm, atau atur $TERMINAL",
+		ErrCodeEditorCheckFailed:       "tidak dapat menjalankan %s: %v",
+		ErrCodeEditorCheckExit:         "%s %s keluar dengan status %d",
+		ErrCodeEditorCheckTimeout:      "%s %s tidak selesai dalam %s",
+		ErrCodeNoFileManager:           "tidak dapat membuka %s di pengelola file (dicoba %s)",
+		ErrCodeEditorLaunchFailed:      "gagal membuka file di %s: %v",
+		ErrCodeDefaultEditorFailed:     "gagal membuka file di editor bawaan: %v",
+		ErrCodeNotImplemented:          "membuka folder di %s belum diimplementasikan",
+		ErrCodeUnsupportedPlatform:     "platform tidak didukung: %s",
+		ErrCodeDialogUnavailable:       "dialog tidak tersedia - aplikasi mungkin belum selesai dimuat",
+		ErrCodeDialogFailed:            "gagal membuka dialog direktori: %v",
+		ErrCodeFileDialogFailed:        "gagal membuka dialog file: %v",
+		ErrCodeLargeFileConfirmation:   "%s lebih besar dari %d MB dan dapat membuat editor macet; konfirmasi untuk tetap membukanya",
+		ErrCodeEditorNoProjects:        "%s tidak dapat membuka folder proyek",
+		ErrCodeLicenseTemplateRequired: "header lisensi yang diharapkan wajib diisi",
+		ErrCodeImageUnsupported:        "%s bukan gambar PNG, JPEG, atau GIF",
+		ErrCodeImageTooLarge:           "%s berukuran %dx%d piksel, terlalu besar untuk dibuat thumbnail",
+		ErrCodeExportFormatUnsupported: "format ekspor %q tidak didukung; gunakan %s",
+		ErrCodeSearchLinkInvalid:       "tautan pencarian tidak valid: %v",
+		ErrCodeBucketURLInvalid:        "%s bukan URL bucket; gunakan s3://bucket/prefix atau gs://bucket/prefix",
+		ErrCodeBucketCredentials:       "tidak dapat memuat kredensial %s: %v",
+		ErrCodeBucketListFailed:        "tidak dapat menampilkan daftar %s: %v",
+		ErrCodePluginNotFound:          "tidak ada plugin bernama %s",
+		ErrCodePluginInvalid:           "plugin %s tidak dapat diaktifkan: %s",
+		ErrCodeHookInvalid:             "hook %d tidak valid: %s",
+		ErrCodeQueryOperatorInvalid:    "%s bukan filter yang valid: %s",
+		ErrCodeFavoriteColorInvalid:    "%s bukan warna; gunakan #rgb atau #rrggbb",
+		ErrCodeFavoriteNotFound:        "%s bukan favorit",
+		ErrCodeBatchTooLarge:           "batch berisi %d kueri terlalu besar; paling banyak %d dapat dijalankan sekaligus",
+		ErrCodeBatchQueryInvalid:       "kueri %d dalam batch: %s",
+		ErrCodeBatchFiltersDiffer:      "kueri %d dalam batch memilih file yang berbeda dari kueri pertama; hanya kueri dan opsi pencocokan yang boleh berbeda",
+		ErrCodeCooccurrencePatterns:    "pencarian kemunculan bersama membutuhkan 2 sampai %d pola yang tidak kosong",
+		ErrCodeRegionStartRequired:     "penanda akhir region membutuhkan penanda awal region",
+		ErrCodeModifiedRangeEmpty:      "waktu diubah-setelah harus lebih awal dari waktu diubah-sebelum",
+		ErrCodeOwnerFiltersUnsupported: "filter pemilik dan izin memerlukan sistem berkas Unix lokal",
+		ErrCodePermissionMaskInvalid:   "mask izin %#o memiliki bit di luar 07777",
+		ErrCodeReplaceUnsupported:      "pencarian dengan salah ketik atau berkas tanpa kecocokan tidak memiliki kecocokan persis untuk diganti",
+		ErrCodeReplaceFileChanged:      "%s berubah sejak pencarian; jalankan ulang sebelum mengganti",
+		ErrCodeReplaceUTF16:            "%s berkodekan UTF-16, yang tidak didukung patch penggantian",
+		ErrCodeUndoNotFound:            "aksi %s tidak dapat dibatalkan lagi",
+		ErrCodeUndoConflict:            "%s berubah setelah aksi; membatalkan akan menghilangkan perubahan itu",
+		ErrCodeUndoFailed:              "tidak dapat memulihkan %s: %v",
+		ErrCodeRenameNameInvalid:       "%q bukan pengenal",
+		ErrCodeRenameUnchanged:         "%s dan %s hanya berbeda huruf besar/kecil atau gaya penamaan",
+		ErrCodeFileTypeBundleUnknown:   "kumpulan tipe file @%s tidak dikenal",
+		ErrCodeContextFileChanged:      "%s berubah sejak pencarian; jalankan lagi untuk melihat konteks lebih banyak",
+		ErrCodeDirectionInvalid:        "arah harus \"next\" atau \"previous\", bukan %q",
+		ErrCodeFileNotInResults:        "%s tidak memiliki hasil dalam pencarian %s",
+		ErrCodeMetricsAddrInvalid:      "alamat metrik %q harus berupa host loopback dan port, mis. 127.0.0.1:9464",
+		ErrCodeMetricsUnavailable:      "tidak dapat menyajikan metrik di %s: %v",
+		ErrCodeDiagnosticsFailed:       "tidak dapat menulis paket diagnostik %s: %v",
+		ErrCodeUpdateChannelInvalid:    "kanal pembaruan harus \"stable\" atau \"beta\", bukan %q",
+		ErrCodeUpdateCheckFailed:       "tidak dapat memeriksa pembaruan: %v",
+		ErrCodeUpdateAssetNotFound:     "rilis %s tidak memiliki unduhan %s",
+		ErrCodeUpdateDownloadFailed:    "tidak dapat mengunduh pembaruan ke %s: %v",
+		ErrCodeLogDirInvalid:           "direktori log harus berupa path absolut: %s",
+		ErrCodeLogLevelInvalid:         "level log %q tidak dikenal; gunakan trace, debug, info, warn, error, atau fatal",
+		ErrCodeLogFilterInvalid:        "filter log %d tidak valid: %s",
+		ErrCodeWorkspaceNameRequired:   "nama workspace wajib diisi",
+		ErrCodeWorkspaceNameTaken:      "workspace bernama %q sudah ada",
+		ErrCodeWorkspaceRootInvalid:    "root workspace harus berupa path absolut: %s",
+		ErrCodeWorkspaceNotFound:       "workspace tidak ditemukan: %s",
+		ErrCodeSavedSearchNameRequired: "nama pencarian tersimpan wajib diisi",
+		ErrCodeUnsupportedLocale:       "locale tidak didukung: %s",
+		ErrCodeInvalidHotkey:           "hotkey %q tidak valid: gunakan modifier dan satu tombol, mis. Ctrl+Shift+F",
+		ErrCodeHotkeyUnavailable:       "tidak dapat mendaftarkan hotkey %s: %v",
+		ErrCodeShellIntegrationFailed:  "gagal memperbarui entri menu konteks: %v",
+		ErrCodeNoResultsToOpen:         "tidak ada file hasil pencarian yang ditemukan",
+		ErrCodeNoRemoteLink:            "tidak ditemukan remote GitHub, GitLab, Bitbucket, atau Gitea untuk %s",
+		ErrCodeInvalidIgnoreRule:       "aturan pengabaian %q tidak valid",
+		ErrCodeIgnoreFileFailed:        "gagal mengakses %s: %v",
+		ErrCodeSearchNotFound:          "pencarian %s sudah tidak tersedia; jalankan ulang",
+		ErrCodeConfirmationRequired:    "pencarian ini kemungkinan lambat; konfirmasi untuk tetap menjalankannya",
+		ErrCodeSearchRootRemoved:       "direktori %s dihapus saat pencarian; hasil tidak lengkap",
+		ErrCodeResultsExportFailed:     "tidak dapat menulis hasil ke %s: %v",
+		ErrCodeInvalidStoreFilter:      "filter penyimpanan hasil tidak valid: %s",
+		ErrCodeResultStoreFailed:       "tidak dapat membaca penyimpanan hasil: %v",
+		ErrCodeIndexFailed:             "tidak dapat mengindeks %s: %v",
+		ErrCodeNoIndex:                 "belum ada direktori yang diindeks",
+		ErrCodeFuzzyNeedsLiteral:       "pencocokan fuzzy hanya untuk kueri literal; matikan pencarian regex",
+		ErrCodeFuzzyQueryTooLong:       "kueri fuzzy paling panjang %d byte",
+		ErrCodeExpandNeedsLiteral:      "pencocokan variasi penamaan hanya untuk kueri literal; matikan pencarian regex",
+		ErrCodeTemplateNameRequired:    "nama templat wajib diisi",
+		ErrCodeTemplateQueryRequired:   "kueri templat wajib diisi",
+		ErrCodeTemplateNotFound:        "templat tidak ditemukan: %s",
+		ErrCodeTemplateVariableMissing: "variabel templat {%s} belum diberi nilai",
+		ErrCodeExtractNeedsRegex:       "grup tangkapan hanya dapat diambil dari pencarian regex",
+		ErrCodeNoCaptureGroups:         "pola tidak memiliki grup tangkapan untuk diambil; bungkus bagian yang diinginkan dengan ( )",
+		ErrCodeNoCaptures:              "pencarian %s tidak mengambil grup tangkapan; jalankan ulang dengan Extract Groups aktif",
+		ErrCodeUnknownCaptureGroup:     "pola tidak memiliki grup tangkapan %q",
+		ErrCodeNoQuickfix:              "belum ada hasil yang diekspor sebagai daftar quickfix",
+		ErrCodeQuickfixUnsupported:     "%s tidak dapat memuat daftar quickfix; gunakan Vim, Neovim, Neovide, atau Emacs",
+	},
+}
+
+// normalizeLocale reduces a BCP 47 / POSIX locale tag ("id-ID", "en_US.UTF-8")
+// to the language code the catalog is keyed by.
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_."); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// SetLocale selects the language of error messages returned to the frontend.
+// Region and encoding suffixes are ignored ("id-ID" selects "id"). The UI
+// calls this on startup with its own language preference; the selection is
+// not persisted by the backend.
+func (a *App) SetLocale(locale string) error {
+	normalized := normalizeLocale(locale)
+	if _, ok := messageCatalog[normalized]; !ok {
+		return newAppError(ErrCodeUnsupportedLocale, locale)
+	}
+
+	a.localeMu.Lock()
+	a.locale = normalized
+	a.localeMu.Unlock()
+
+	a.logDebug("Locale changed", logrus.Fields{"locale": normalized})
+	return nil
+}
+
+// GetLocale returns the locale error messages are currently rendered in.
+func (a *App) GetLocale() string {
+	a.localeMu.RLock()
+	defer a.localeMu.RUnlock()
+	if a.locale == "" {
+		return defaultLocale
+	}
+	return a.locale
+}
+
+// GetSupportedLocales returns the locales SetLocale accepts, sorted.
+func (a *App) GetSupportedLocales() []string {
+	locales := make([]string, 0, len(messageCatalog))
+	for locale := range messageCatalog {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
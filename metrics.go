@@ -0,0 +1,197 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Search engine counters, published with expvar under "codesearch" for the
+// life of the process. Searches count every SearchWithProgress,
+// SearchToFile, BatchSearch, SearchCooccurrence, and SearchBucket run.
+var (
+	metricsVars             = expvar.NewMap("codesearch")
+	metricSearchesStarted   = new(expvar.Int)
+	metricSearchesCancelled = new(expvar.Int)
+	metricFilesScanned      = new(expvar.Int)
+	metricBytesScanned      = new(expvar.Int)
+	metricErrors            = new(expvar.Map).Init()
+)
+
+func init() {
+	metricsVars.Set("searches_started", metricSearchesStarted)
+	metricsVars.Set("searches_cancelled", metricSearchesCancelled)
+	metricsVars.Set("files_scanned", metricFilesScanned)
+	metricsVars.Set("bytes_scanned", metricBytesScanned)
+	metricsVars.Set("errors", metricErrors)
+}
+
+// metricsCounters describes the counters for the Prometheus exposition, in
+// output order.
+var metricsCounters = []struct {
+	name string
+	help string
+	v    *expvar.Int
+}{
+	{"codesearch_searches_started_total", "Searches started.", metricSearchesStarted},
+	{"codesearch_searches_cancelled_total", "Searches stopped by CancelSearch.", metricSearchesCancelled},
+	{"codesearch_files_scanned_total", "Files searched, not counting files skipped as binary or generated.", metricFilesScanned},
+	{"codesearch_bytes_scanned_total", "Size of the files searched, in bytes.", metricBytesScanned},
+}
+
+// countFileScanned records a file a search read.
+func countFileScanned(meta fileMeta) {
+	metricFilesScanned.Add(1)
+	metricBytesScanned.Add(meta.size)
+}
+
+// writePrometheusMetrics writes the counters in the Prometheus text
+// exposition format. Errors are one series per error code.
+func writePrometheusMetrics(w io.Writer) {
+	for _, c := range metricsCounters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.v.Value())
+	}
+	fmt.Fprint(w, "# HELP codesearch_errors_total Errors raised, by error code.\n# TYPE codesearch_errors_total counter\n")
+	var codes []string
+	metricErrors.Do(func(kv expvar.KeyValue) { codes = append(codes, kv.Key) })
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "codesearch_errors_total{code=%q} %s\n", code, metricErrors.Get(code).String())
+	}
+}
+
+// metricsHandler serves the counters: /metrics for Prometheus and
+// /debug/vars as expvar JSON, gzip-compressed for clients that accept it.
+// Requests share one rate limit (see rateLimitHandler).
+func metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheusMetrics(w)
+	})
+	mux.Handle("/debug/vars", expvar.Handler())
+	return rateLimitHandler(gzipHandler(mux), newTokenBucket(localEndpointRate, localEndpointBurst))
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip: listed,
+// or covered by "*", and not with q=0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter compresses the body written through it.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+// gzipHandler compresses the responses of next for clients that accept
+// gzip. Large expvar dumps shrink several times over.
+func gzipHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		next.ServeHTTP(gzipResponseWriter{w, gz}, r)
+	})
+}
+
+// validateMetricsAddr checks that addr, unless empty, is a host:port on
+// the loopback interface. The counters include file counts of private
+// trees, so they are never served to the network.
+func validateMetricsAddr(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || port == "" {
+		return newAppError(ErrCodeMetricsAddrInvalid, addr)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return newAppError(ErrCodeMetricsAddrInvalid, addr)
+	}
+	return nil
+}
+
+// metricsServer tracks the running /metrics endpoint.
+type metricsServer struct {
+	mu     sync.Mutex
+	addr   string       // Address the server listens on, "" if none
+	server *http.Server // Running server
+}
+
+// applyMetricsAddr serves the metrics on addr, replacing any previous
+// server. An empty addr just stops it.
+func (a *App) applyMetricsAddr(addr string) error {
+	a.metrics.mu.Lock()
+	defer a.metrics.mu.Unlock()
+
+	if addr == a.metrics.addr {
+		return nil
+	}
+	if a.metrics.server != nil {
+		a.metrics.server.Close()
+		a.metrics.server = nil
+		a.metrics.addr = ""
+	}
+	if addr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		a.logWarn("Failed to start metrics endpoint", logrus.Fields{
+			"addr":  addr,
+			"error": err.Error(),
+		})
+		return newAppError(ErrCodeMetricsUnavailable, addr, err)
+	}
+	server := &http.Server{Handler: metricsHandler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.logError("Metrics endpoint stopped", err, logrus.Fields{"addr": addr})
+		}
+	}()
+	a.metrics.server = server
+	a.metrics.addr = addr
+	a.logInfo("Metrics endpoint started", logrus.Fields{"url": "http://" + listener.Addr().String() + "/metrics"})
+	return nil
+}
+
+// stopMetrics stops the metrics endpoint, if any.
+func (a *App) stopMetrics() {
+	_ = a.applyMetricsAddr("")
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// errorCount returns how many errors with code were counted so far.
+func errorCount(code ErrorCode) int64 {
+	if v, ok := metricErrors.Get(string(code)).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+// TestSearchMetricsCount verifies that a search counts as started and
+// counts the files it read and their size, and that errors are counted by
+// code. The counters are process-wide, so the test compares deltas.
+func TestSearchMetricsCount(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("needle\n")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	started, files, size := metricSearchesStarted.Value(), metricFilesScanned.Value(), metricBytesScanned.Value()
+	notFound := errorCount(ErrCodeSearchNotFound)
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if _, err := app.GetFileMatches("search-missing", "/x"); err == nil {
+		t.Fatal("expected SEARCH_NOT_FOUND")
+	}
+
+	if got := metricSearchesStarted.Value() - started; got != 1 {
+		t.Errorf("expected 1 search started, got %d", got)
+	}
+	if got := metricFilesScanned.Value() - files; got != 2 {
+		t.Errorf("expected 2 files scanned, got %d", got)
+	}
+	if got := metricBytesScanned.Value() - size; got != int64(2*len(content)) {
+		t.Errorf("expected %d bytes scanned, got %d", 2*len(content), got)
+	}
+	if got := errorCount(ErrCodeSearchNotFound) - notFound; got != 1 {
+		t.Errorf("expected 1 %s error counted, got %d", ErrCodeSearchNotFound, got)
+	}
+}
+
+// TestMetricsHandler verifies the Prometheus and expvar outputs.
+func TestMetricsHandler(t *testing.T) {
+	newAppError(ErrCodeSearchNotFound, "x")
+	server := httptest.NewServer(metricsHandler())
+	defer server.Close()
+
+	get := func(path string) string {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	metrics := get("/metrics")
+	for _, want := range []string{
+		"# TYPE codesearch_searches_started_total counter\ncodesearch_searches_started_total ",
+		"codesearch_bytes_scanned_total ",
+		`codesearch_errors_total{code="SEARCH_NOT_FOUND"} `,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("expected %q in /metrics, got:\n%s", want, metrics)
+		}
+	}
+	if vars := get("/debug/vars"); !strings.Contains(vars, `"codesearch": {`) {
+		t.Errorf("expected the codesearch map in /debug/vars, got:\n%s", vars)
+	}
+}
+
+// TestMetricsHandlerGzip verifies that responses are compressed only for
+// clients that accept gzip.
+func TestMetricsHandlerGzip(t *testing.T) {
+	server := httptest.NewServer(metricsHandler())
+	defer server.Close()
+	// A transport that leaves decompression to the test.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	for _, path := range []string{"/metrics", "/debug/vars"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		if resp.Header.Get("Content-Encoding") != "gzip" {
+			t.Fatalf("GET %s: expected a gzip response, got headers %v", path, resp.Header)
+		}
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatalf("GET %s: bad gzip stream: %v", path, err)
+		}
+		body, err := io.ReadAll(zr)
+		resp.Body.Close()
+		if err != nil || !strings.Contains(string(body), "codesearch") {
+			t.Errorf("GET %s: expected the counters after decompressing, got %q, %v", path, body, err)
+		}
+	}
+
+	resp, err := client.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.Header.Get("Content-Encoding") != "" || !strings.HasPrefix(string(body), "# HELP") {
+		t.Errorf("expected a plain response without Accept-Encoding, got %v: %q", resp.Header, body)
+	}
+}
+
+// TestAcceptsGzip verifies Accept-Encoding parsing.
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, GZIP;q=0.5":  true,
+		"*":                    true,
+		"gzip;q=0":             false,
+		"gzip; q=0.0, deflate": false,
+		"br, deflate":          false,
+	}
+	for header, want := range tests {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+// TestValidateMetricsAddr verifies that only loopback addresses are
+// accepted.
+func TestValidateMetricsAddr(t *testing.T) {
+	for _, addr := range []string{"", "127.0.0.1:9464", "localhost:9464", "[::1]:9464"} {
+		if err := validateMetricsAddr(addr); err != nil {
+			t.Errorf("validateMetricsAddr(%q) failed: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"0.0.0.0:9464", ":9464", "192.168.1.2:9464", "example.com:9464", "127.0.0.1"} {
+		if err := validateMetricsAddr(addr); err == nil || err.(*AppError).Code != ErrCodeMetricsAddrInvalid {
+			t.Errorf("validateMetricsAddr(%q): expected %s, got %v", addr, ErrCodeMetricsAddrInvalid, err)
+		}
+	}
+}
+
+// TestMetricsEndpointFromSettings verifies that the metricsAddr setting
+// starts the endpoint and clearing it stops it, and that a non-loopback
+// address is rejected without saving.
+func TestMetricsEndpointFromSettings(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	defer app.stopMetrics()
+
+	if _, err := app.UpdateSettings(Settings{MetricsAddr: "0.0.0.0:9464"}); err == nil || err.(*AppError).Code != ErrCodeMetricsAddrInvalid {
+		t.Fatalf("expected %s, got %v", ErrCodeMetricsAddrInvalid, err)
+	}
+
+	if _, err := app.UpdateSettings(Settings{MetricsAddr: "127.0.0.1:0"}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	app.metrics.mu.Lock()
+	server := app.metrics.server
+	app.metrics.mu.Unlock()
+	if server == nil {
+		t.Fatal("expected the metrics endpoint to be running")
+	}
+
+	if _, err := app.UpdateSettings(Settings{}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	app.metrics.mu.Lock()
+	defer app.metrics.mu.Unlock()
+	if app.metrics.server != nil || app.metrics.addr != "" {
+		t.Error("expected the metrics endpoint stopped")
+	}
+}
+
+// TestMetricsEndpointPortInUse verifies that a taken port is reported.
+func TestMetricsEndpointPortInUse(t *testing.T) {
+	taken := httptest.NewServer(http.NotFoundHandler())
+	defer taken.Close()
+	addr := strings.TrimPrefix(taken.URL, "http://")
+
+	app := NewApp()
+	err := app.applyMetricsAddr(addr)
+	if err == nil || err.(*AppError).Code != ErrCodeMetricsUnavailable {
+		t.Errorf("expected %s, got %v", ErrCodeMetricsUnavailable, err)
+	}
+	var buf bytes.Buffer
+	writePrometheusMetrics(&buf)
+	if !strings.Contains(buf.String(), `code="METRICS_UNAVAILABLE"`) {
+		t.Errorf("expected the error counted, got:\n%s", buf.String())
+	}
+}
@@ -9,23 +9,628 @@ type SearchResult struct {
 	MatchedText   string   `json:"matchedText"`   // The specific text that matched the query
 	ContextBefore []string `json:"contextBefore"` // Lines before the match for context
 	ContextAfter  []string `json:"contextAfter"`  // Lines after the match for context
+
+	Captures map[string]string `json:"captures,omitempty"` // Capture groups of the first match on the line, by name or number (ExtractGroups only)
+
+	Spans []MatchSpan `json:"spans,omitempty"` // Every match on the line, as offsets into Content
+}
+
+// MatchSpan is where one match lies in SearchResult.Content, in bytes and
+// in runes (Unicode code points). Frontend highlighting should use the
+// rune offsets, e.g. over Array.from(content): with emoji or CJK text the
+// byte offsets point into the middle of characters.
+type MatchSpan struct {
+	Start     int `json:"start"`     // Byte offset of the first matched byte
+	End       int `json:"end"`       // Byte offset just past the match
+	RuneStart int `json:"runeStart"` // Rune offset of the first matched character
+	RuneEnd   int `json:"runeEnd"`   // Rune offset just past the match
 }
 
 // SearchRequest contains all parameters needed for a search operation.
 // It defines what to search for and where to search.
 type SearchRequest struct {
-	Directory        string   `json:"directory"`        // Path to the directory to search in
-	Query            string   `json:"query"`            // Text to search for
-	Extension        string   `json:"extension"`        // File extension to filter by (empty means all extensions)
-	CaseSensitive    bool     `json:"caseSensitive"`    // Whether the search should be case sensitive
-	IncludeBinary    bool     `json:"includeBinary"`    // Whether to include binary files in search
-	MaxFileSize      int64    `json:"maxFileSize"`      // Maximum file size in bytes (default 10MB if 0)
-	MinFileSize      int64    `json:"minFileSize"`      // Minimum file size in bytes (default 0 if not specified)
-	MaxResults       int      `json:"maxResults"`       // Maximum number of results to return (default 1000 if 0)
-	SearchSubdirs    bool     `json:"searchSubdirs"`    // Whether to search subdirectories (default true)
-	UseRegex         *bool    `json:"useRegex"`         // Whether to treat query as regex (default true for backward compatibility)
-	ExcludePatterns  []string `json:"excludePatterns"`  // Patterns to exclude from search (e.g., node_modules, *.log)
-	AllowedFileTypes []string `json:"allowedFileTypes"` // List of file extensions that are allowed to be searched (if empty, all types allowed)
+	Directory             string   `json:"directory"`             // Path to the directory to search in
+	Query                 string   `json:"query"`                 // Text to search for
+	Extension             string   `json:"extension"`             // File extension to filter by (empty means all extensions)
+	CaseSensitive         bool     `json:"caseSensitive"`         // Whether the search should be case sensitive
+	IncludeBinary         bool     `json:"includeBinary"`         // Whether to include binary files in search
+	MaxFileSize           int64    `json:"maxFileSize"`           // Maximum file size in bytes (default 10MB if 0)
+	MinFileSize           int64    `json:"minFileSize"`           // Minimum file size in bytes (default 0 if not specified)
+	MaxResults            int      `json:"maxResults"`            // Maximum number of results to return (default 1000 if 0)
+	SearchSubdirs         bool     `json:"searchSubdirs"`         // Whether to search subdirectories (default true)
+	UseRegex              *bool    `json:"useRegex"`              // Whether to treat query as regex (default true for backward compatibility)
+	ExcludePatterns       []string `json:"excludePatterns"`       // Patterns to exclude from search (e.g., node_modules, *.log)
+	AllowedFileTypes      []string `json:"allowedFileTypes"`      // List of file extensions that are allowed to be searched (if empty, all types allowed); "@web" stands for a bundle (ListFileTypeBundles)
+	IncludePaths          []string `json:"includePaths"`          // Only search files whose path relative to Directory contains one of these (slash-separated; path: operator)
+	ExcludePaths          []string `json:"excludePaths"`          // Skip files whose path relative to Directory contains one of these (slash-separated; -path: operator)
+	SkipGenerated         bool     `json:"skipGenerated"`         // Whether to skip minified/generated files (*.min.js, *.map, "Code generated" headers)
+	MaxFilesPerDir        int      `json:"maxFilesPerDir"`        // Maximum files collected from a single directory (default 100000 if 0, negative means unlimited)
+	SlowFS                bool     `json:"slowFs"`                // Network-drive mode: fewer workers, throttled progress, no separate binary probe (auto-enabled for network mounts)
+	Sampling              bool     `json:"sampling"`              // Return MaxResults matches sampled evenly across files instead of the first MaxResults in walk order
+	SamplingThreshold     int      `json:"samplingThreshold"`     // Matches scanned before sampling when Sampling is set (default 50000 if 0)
+	ConfirmExpensive      bool     `json:"confirmExpensive"`      // The user acknowledged a CONFIRMATION_REQUIRED warning for this search
+	RetryLocked           bool     `json:"retryLocked"`           // Retry a file locked by another process once after a short delay
+	StreamingThreshold    int64    `json:"streamingThreshold"`    // Files larger than this are streamed line by line (0 uses the setting, default 1MB)
+	ScannerBufferSize     int      `json:"scannerBufferSize"`     // Longest line the streaming scanner accepts, in bytes (0 uses the setting, default 1MB)
+	IncludeSubmodules     bool     `json:"includeSubmodules"`     // Search the working trees of the repository's git submodules (listed in .gitmodules); skipped by default
+	IncludeWellKnownFiles bool     `json:"includeWellKnownFiles"` // Always search build and CI files such as Dockerfile, Makefile, and .github/workflows/*.yml, even in hidden directories or outside the extension filters
+	ExpandIdentifiers     bool     `json:"expandIdentifiers"`     // Also match the query's identifiers in other naming conventions (getUserId ~ get_user_id ~ GetUserID); literal, case-insensitive
+	Fuzziness             int      `json:"fuzziness"`             // Edits (insertions, deletions, substitutions) a fuzzy literal match may have; capped at one per three query characters and 3 (0 = exact)
+	ExtractGroups         bool     `json:"extractGroups"`         // Return each match's regex capture groups in SearchResult.Captures (regex searches with at least one group)
+	ResultLogPath         string   `json:"resultLogPath"`         // Absolute path of an NDJSON file every match is written to; the search then runs past MaxResults and returns only the first MaxResults
+	InvertFileMatch       bool     `json:"invertFileMatch"`       // Return the files without a match, one result each (LineNum 0), instead of the matching lines
+	HeadLines             int      `json:"headLines"`             // Only match in the first HeadLines lines of each file (0 = every line)
+	RegionStart           string   `json:"regionStart"`           // Only match between a line containing RegionStart and one containing RegionEnd (e.g. "// BEGIN CONFIG"); marker lines are not matched
+	RegionEnd             string   `json:"regionEnd"`             // Closes a region; empty means regions run to the end of the file. Requires RegionStart
+	ModifiedAfter         int64    `json:"modifiedAfter"`         // Only search files modified at or after this time, in Unix milliseconds (0 = no limit)
+	ModifiedBefore        int64    `json:"modifiedBefore"`        // Only search files modified before this time, in Unix milliseconds (0 = no limit)
+	OwnedByMe             bool     `json:"ownedByMe"`             // Only search files owned by the current user (Unix only)
+	PermissionMask        uint32   `json:"permissionMask"`        // Only search files with any of these mode bits set, e.g. 0o002 for world-writable (Unix only, 0 = any)
+}
+
+// BucketSearchRequest is a search of the objects under a cloud storage
+// prefix, run by SearchBucket.
+type BucketSearchRequest struct {
+	URL         string        `json:"url"`         // s3://bucket/prefix or gs://bucket/prefix
+	Search      SearchRequest `json:"search"`      // Query, matching options, and filters; Directory and ResultLogPath are ignored
+	Concurrency int           `json:"concurrency"` // Objects downloaded at once (default 4, at most 16)
+}
+
+// BatchResult is the result set of one query of a BatchSearch.
+type BatchResult struct {
+	Query     string         `json:"query"` // The query as requested, operators included
+	Results   []SearchResult `json:"results"`
+	Truncated bool           `json:"truncated"` // The query reached its maxResults; there may be more matches
+}
+
+// CooccurrenceRequest is a search for the files in which every pattern
+// occurs, run by SearchCooccurrence.
+type CooccurrenceRequest struct {
+	Search   SearchRequest `json:"search"`   // Directory, filters, and matching options; Query is ignored and MaxResults caps the files returned
+	Patterns []string      `json:"patterns"` // Two or more, each searched like Search.Query would be
+}
+
+// CooccurrenceFile is a file in which every pattern of a
+// CooccurrenceRequest occurs.
+type CooccurrenceFile struct {
+	FilePath string             `json:"filePath"`
+	Pairs    []CooccurrencePair `json:"pairs"` // For every two patterns, in pattern order: 1-2, 1-3, ..., 2-3, ...
+}
+
+// CooccurrencePair is the evidence that two patterns occur in a file: the
+// match of each that lie nearest to each other.
+type CooccurrencePair struct {
+	PatternA int          `json:"patternA"` // Index of the first pattern in Patterns
+	PatternB int          `json:"patternB"` // Index of the second pattern
+	MatchA   SearchResult `json:"matchA"`
+	MatchB   SearchResult `json:"matchB"`
+	Distance int          `json:"distance"` // Lines between the two matches; 0 when they share a line
+}
+
+// PluginInfo describes a content plugin for ListPlugins.
+type PluginInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Extensions  []string `json:"extensions"`     // Extensions the plugin transforms, without the dot
+	Builtin     bool     `json:"builtin"`        // Shipped with the app rather than installed in the plugins directory
+	Path        string   `json:"path,omitempty"` // Plugin directory; empty for built-in plugins
+	Enabled     bool     `json:"enabled"`
+	Error       string   `json:"error,omitempty"` // Why the plugin can't be used; such a plugin can't be enabled
+}
+
+// Favorite is a directory pinned for quick access, kept apart from the
+// search history.
+type Favorite struct {
+	Path    string `json:"path"`    // Absolute directory; unique among favorites
+	Label   string `json:"label"`   // Display name; defaults to the directory's name
+	Color   string `json:"color"`   // "#rrggbb", or empty for the default color
+	AddedAt int64  `json:"addedAt"` // Unix milliseconds
+	Missing bool   `json:"missing"` // Set by ListFavorites when the directory no longer exists
+}
+
+// FileContent is a file returned by ReadFile for the preview modal.
+type FileContent struct {
+	Content    string `json:"content"`    // The text as UTF-8, without a byte order mark
+	Encoding   string `json:"encoding"`   // Encoding detected from the BOM: utf-8, utf-8-bom, utf-16le, or utf-16be
+	LineEnding string `json:"lineEnding"` // Dominant line ending: lf, crlf, or cr; empty without line breaks
+}
+
+// FileInfo describes a file for the preview modal, returned by GetFileInfo.
+type FileInfo struct {
+	Path           string `json:"path"`                 // Cleaned path of the file
+	Size           int64  `json:"size"`                 // Size in bytes
+	ModTime        int64  `json:"modTime"`              // Last modification, Unix milliseconds
+	MimeType       string `json:"mimeType"`             // Sniffed from the content, or from the extension for plain text
+	Encoding       string `json:"encoding,omitempty"`   // Text encoding, as in FileContent; empty for binary files
+	Binary         bool   `json:"binary"`               // The file looks binary (isBinary)
+	LineEnding     string `json:"lineEnding,omitempty"` // Dominant line ending, as in FileContent; from the first 1MB
+	LineCount      int    `json:"lineCount"`            // Lines of text; 0 for binary files
+	LineCountExact bool   `json:"lineCountExact"`       // False when LineCount is extrapolated from the first 1MB
+	View           string `json:"view"`                 // Preview to show: text, hex, or too-large
+	HexPreview     string `json:"hexPreview,omitempty"` // Hex dump of the first 4KB, for the hex view
+}
+
+// Thumbnail is a downscaled image returned by ReadFileThumbnail.
+type Thumbnail struct {
+	Data           string `json:"data"`           // The thumbnail as a base64 PNG
+	Width          int    `json:"width"`          // Thumbnail width in pixels
+	Height         int    `json:"height"`         // Thumbnail height in pixels
+	OriginalWidth  int    `json:"originalWidth"`  // Width of the source image
+	OriginalHeight int    `json:"originalHeight"` // Height of the source image
+	Format         string `json:"format"`         // Source format: png, jpeg, or gif
+}
+
+// TreeExport summarizes a directory tree written by ExportTree.
+type TreeExport struct {
+	Path        string `json:"path"`        // File written
+	Files       int    `json:"files"`       // Files in the tree
+	Directories int    `json:"directories"` // Directories below the root that hold files
+	TotalSize   int64  `json:"totalSize"`   // Total size of the files in bytes
+	Truncated   int    `json:"truncated"`   // Directories whose listing stopped at the per-directory file limit
+}
+
+// FileSlice is a window of lines around a match, returned by GetFileSlice for
+// the results pane's inline preview.
+type FileSlice struct {
+	FilePath   string   `json:"filePath"`   // Cleaned path of the file the slice was read from
+	StartLine  int      `json:"startLine"`  // Line number of Lines[0] (1-indexed)
+	Lines      []string `json:"lines"`      // The slice content, one entry per line
+	MatchIndex int      `json:"matchIndex"` // Index into Lines of the requested center line
+	EndOfFile  bool     `json:"endOfFile"`  // True when the slice reaches the last line of the file
+}
+
+// SessionState is the UI state persisted between runs by SaveSession and
+// restored by GetLastSession.
+type SessionState struct {
+	Request       SearchRequest `json:"request"`       // Last search form: directory, query, and filters
+	OpenFilePath  string        `json:"openFilePath"`  // File of the result open in the preview (empty if none)
+	OpenLineNum   int           `json:"openLineNum"`   // Line of the open result (1-indexed)
+	ResultsScroll int           `json:"resultsScroll"` // Scroll offset of the results list, in pixels
+	PreviewScroll int           `json:"previewScroll"` // Scroll offset of the preview pane, in pixels
+	SavedAt       int64         `json:"savedAt"`       // When the session was saved (Unix milliseconds), set by SaveSession
+}
+
+// SavedSearch is a named search request stored in a workspace.
+type SavedSearch struct {
+	Name    string        `json:"name"`    // Display name in the workspace's saved-search list
+	Request SearchRequest `json:"request"` // The search to run
+}
+
+// Workspace groups the roots, default filters, and saved searches of one
+// project. Each workspace also keeps its own last session.
+type Workspace struct {
+	ID             string        `json:"id"`             // Generated by CreateWorkspace
+	Name           string        `json:"name"`           // Unique (case-insensitive) display name
+	Roots          []string      `json:"roots"`          // Absolute root directories searched in this workspace
+	DefaultFilters SearchRequest `json:"defaultFilters"` // Filters applied to new searches; Directory and Query are ignored
+	SavedSearches  []SavedSearch `json:"savedSearches"`  // Named searches for quick re-run
+	CreatedAt      int64         `json:"createdAt"`      // Unix milliseconds
+	UpdatedAt      int64         `json:"updatedAt"`      // Unix milliseconds
+}
+
+// FileTypeBundle is a named set of extensions that AllowedFileTypes
+// entries like "@web" stand for (ListFileTypeBundles).
+type FileTypeBundle struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Extensions  []string `json:"extensions"` // Without the dot
+}
+
+// QueryTemplate is a saved search whose query (and directory) may contain
+// {name} placeholders, filled in by RunTemplate.
+type QueryTemplate struct {
+	ID          string        `json:"id"`          // Generated by SaveTemplate
+	Name        string        `json:"name"`        // Display name
+	Description string        `json:"description"` // What the template is for, shown in the template list
+	Request     SearchRequest `json:"request"`     // The search; Query and Directory may contain {name} placeholders ({{name}} for a literal "{name}")
+	Variables   []string      `json:"variables"`   // Placeholder names in order of first use, set by SaveTemplate
+	CreatedAt   int64         `json:"createdAt"`   // Unix milliseconds
+	UpdatedAt   int64         `json:"updatedAt"`   // Unix milliseconds
+}
+
+// Capabilities describes what the backend can do on this machine, returned by
+// GetCapabilities for first-run onboarding.
+type Capabilities struct {
+	OS            string             `json:"os"`            // runtime.GOOS
+	Arch          string             `json:"arch"`          // runtime.GOARCH
+	Editors       EditorAvailability `json:"editors"`       // Editors found by startup detection
+	EditorCount   int                `json:"editorCount"`   // Number of available editors
+	Git           bool               `json:"git"`           // git is on PATH
+	Ripgrep       bool               `json:"ripgrep"`       // rg is on PATH
+	FileManager   bool               `json:"fileManager"`   // ShowInFolder can launch the OS file manager
+	DefaultEditor bool               `json:"defaultEditor"` // OpenInDefaultEditor can hand files to the OS
+	LongPaths     bool               `json:"longPaths"`     // Paths beyond 260 characters can be searched and opened
+	OwnerFilters  bool               `json:"ownerFilters"`  // OwnedByMe and PermissionMask are available
+}
+
+// FileFilter restricts the files a SelectFile dialog shows.
+type FileFilter struct {
+	DisplayName string `json:"displayName"` // e.g. "Go files (*.go)"; defaults to the pattern
+	Pattern     string `json:"pattern"`     // Semicolon-separated globs, e.g. "*.go;*.mod"
+}
+
+// FileManagerAttempt is one program ShowInFolder tried, reported in the
+// details of a NO_FILE_MANAGER error.
+type FileManagerAttempt struct {
+	Command string `json:"command"`
+	Found   bool   `json:"found"`           // The program is on PATH
+	Error   string `json:"error,omitempty"` // Why it failed to start, or its launcher's exit status
+}
+
+// Settings holds user preferences persisted by UpdateSettings.
+type Settings struct {
+	NotifyOnCompletion bool   `json:"notifyOnCompletion"` // Send a desktop notification when a long search completes or is cancelled
+	NotifyMinSeconds   int    `json:"notifyMinSeconds"`   // Minimum search duration that triggers a notification (default 10)
+	Hotkey             string `json:"hotkey"`             // Global shortcut that summons the window, e.g. "Ctrl+Shift+F" (empty disables)
+	StreamingThreshold int64  `json:"streamingThreshold"` // Default for SearchRequest.StreamingThreshold (1MB, 64KB–256MB)
+	ScannerBufferSize  int    `json:"scannerBufferSize"`  // Default for SearchRequest.ScannerBufferSize (1MB, 64KB–64MB)
+	PersistResults     bool   `json:"persistResults"`     // Keep completed searches and their results in the result store (see QueryResultStore)
+	MetricsAddr        string `json:"metricsAddr"`        // Loopback address serving /metrics, e.g. "127.0.0.1:9464" (empty disables)
+	LogDir             string `json:"logDir"`             // Absolute directory for app.log, used from the next launch (empty means the per-OS default, see defaultLogDir)
+
+	CheckUpdatesOnStartup bool   `json:"checkUpdatesOnStartup"` // Look for a new release when the app starts (see CheckForUpdates)
+	UpdateChannel         string `json:"updateChannel"`         // Releases to offer: "stable", or "beta" to include pre-releases (default stable)
+
+	DefaultEditor  string   `json:"defaultEditor"`  // Editor OpenResult uses: an editorBindings name, "JetBrains", or "SystemDefault" (empty means the system default)
+	EditorPriority []string `json:"editorPriority"` // Editors OpenResult falls back to, in order, when the default editor is not installed
+
+	EditorCacheHours int               `json:"editorCacheHours"` // How long startup reuses the last editor detection (24h, 1h–30 days)
+	EditorPaths      map[string]string `json:"editorPaths"`      // Binaries of editors installed outside PATH, by editor name (see SetEditorPath)
+	LargeFileWarnMB  int               `json:"largeFileWarnMB"`  // Files above this size need confirmation before opening in an editor (100MB, 1MB–1TB)
+
+	Hooks []SearchHook `json:"hooks"` // Commands run before and after every search and when its results reach a threshold
+
+	LogFilters []LogFilterRule `json:"logFilters"` // Entries the log viewer hides (null restores defaultLogFilters, [] hides nothing)
+}
+
+// SearchHook is a command run at one point of every search, with the
+// search's details as JSON on stdin (see hooks.go).
+type SearchHook struct {
+	Event     string   `json:"event"`               // "pre-search", "post-search", or "result-threshold"
+	Command   []string `json:"command"`             // Program and arguments
+	Threshold int      `json:"threshold,omitempty"` // result-threshold: the result count that runs the hook, once per search
+}
+
+// LogFilterRule hides the log entries that meet all of its conditions from
+// the log viewer (see logfilter.go). Each rule needs at least one.
+type LogFilterRule struct {
+	Level     string `json:"level,omitempty"`     // Entries at this level or more verbose, e.g. "debug" for debug and trace
+	Message   string `json:"message,omitempty"`   // Regular expression matched against the message
+	Component string `json:"component,omitempty"` // Value of the entry's component field, ignoring case
+}
+
+// StoredSearch is a completed search kept in the result store.
+type StoredSearch struct {
+	ID           string        `json:"id"`           // Store ID, unique across restarts (not the search-progress search ID)
+	Request      SearchRequest `json:"request"`      // The validated request the search ran with
+	ResultCount  int           `json:"resultCount"`  // Results stored for the search
+	TotalMatches int           `json:"totalMatches"` // Matches found, including any beyond ResultCount
+	Incomplete   bool          `json:"incomplete"`   // The search directory was removed before the search finished
+	FinishedAt   int64         `json:"finishedAt"`   // Unix milliseconds
+}
+
+// StoredResult is one result returned by QueryResultStore.
+type StoredResult struct {
+	SearchID string       `json:"searchId"` // StoredSearch.ID of the search the result belongs to
+	Query    string       `json:"query"`    // Query of that search
+	Result   SearchResult `json:"result"`
+}
+
+// IndexInfo describes a full-text index built by IndexWorkspace.
+type IndexInfo struct {
+	Root      string `json:"root"`      // Absolute indexed directory
+	Files     int    `json:"files"`     // Files in the index
+	Terms     int    `json:"terms"`     // Distinct words in the index
+	IndexedAt int64  `json:"indexedAt"` // Unix milliseconds
+}
+
+// IndexedHit is one file returned by SearchIndexed.
+type IndexedHit struct {
+	FilePath string  `json:"filePath"` // Absolute path of the file
+	Score    float64 `json:"score"`    // BM25 relevance; higher is better
+	LineNum  int     `json:"lineNum"`  // First line where the query matches (1-indexed)
+	Content  string  `json:"content"`  // That line as it is on disk now; empty if unreadable
+}
+
+// ExtensionFacet counts the matching files with one extension.
+type ExtensionFacet struct {
+	Extension string `json:"extension"` // Lowercased, with the dot; "" for files without one
+	Count     int    `json:"count"`
+}
+
+// IndexedSearchResults is the answer to a SearchIndexed query.
+type IndexedSearchResults struct {
+	Hits   []IndexedHit     `json:"hits"`   // Best first, at most 100
+	Total  int              `json:"total"`  // Matching files, including those beyond Hits
+	Facets []ExtensionFacet `json:"facets"` // Matching files by extension, most first
+}
+
+// StoreQueryResult is the answer to a QueryResultStore filter.
+type StoreQueryResult struct {
+	Results   []StoredResult `json:"results"`   // Matching results, newest search first
+	Files     []string       `json:"files"`     // Distinct files of Results, sorted
+	Truncated bool           `json:"truncated"` // More results matched than the 5000 returned
+}
+
+// ResultGroup is the results of one file in a FilteredResults view.
+type ResultGroup struct {
+	FilePath   string         `json:"filePath"`   // File the results belong to
+	Count      int            `json:"count"`      // Number of results in the file
+	MatchCount int            `json:"matchCount"` // Matches found in the file; larger than Count when the search was sampled
+	Results    []SearchResult `json:"results"`    // Results in the order they were found
+}
+
+// FileMatchCount is the number of matches a sampled search found in a file.
+type FileMatchCount struct {
+	FilePath string `json:"filePath"`
+	Count    int    `json:"count"`
+}
+
+// ResultsFreshness reports the matched files of a completed search that
+// changed since it finished, returned by CheckResultsFreshness and sent on
+// the "results-stale" event.
+type ResultsFreshness struct {
+	SearchID     string   `json:"searchId"`
+	Stale        bool     `json:"stale"`        // Some matched file was edited or deleted
+	ChangedFiles []string `json:"changedFiles"` // Files whose modification time changed, sorted
+	DeletedFiles []string `json:"deletedFiles"` // Files that no longer exist, sorted
+}
+
+// UndoAction is a write operation that Undo can revert, listed by
+// ListUndoableActions.
+type UndoAction struct {
+	ID          string `json:"id"`
+	Kind        string `json:"kind"`        // "ignore-rule" or "export"
+	Description string `json:"description"` // e.g. "Add ignore rule vendor/"
+	Path        string `json:"path"`        // File the operation wrote
+	Created     bool   `json:"created"`     // The file didn't exist before, so undoing deletes it
+	CreatedAt   int64  `json:"createdAt"`   // Unix milliseconds
+}
+
+// ReplacePatch describes the patch file written by CreateReplacePatch.
+type ReplacePatch struct {
+	Path       string   `json:"path"`       // Patch file; empty when the replacement changes nothing
+	Root       string   `json:"root"`       // Directory to run git apply in: the git work tree, or the search directory outside git
+	Files      int      `json:"files"`      // Files the patch changes
+	Lines      int      `json:"lines"`      // Lines the patch changes
+	DirtyFiles []string `json:"dirtyFiles"` // Patched files with uncommitted git changes
+}
+
+// RenamePreview is the patch RenameIdentifier writes to rename an
+// identifier across a tree, and every occurrence it renames.
+type RenamePreview struct {
+	Path        string             `json:"path"`        // Patch file; empty when no occurrence was found
+	Root        string             `json:"root"`        // Directory to run git apply in: the git work tree, or root outside git
+	Files       int                `json:"files"`       // Files the patch changes
+	Occurrences []RenameOccurrence `json:"occurrences"` // Renamed occurrences, by file and line
+	Skipped     int                `json:"skipped"`     // Occurrences left alone inside comments and strings
+	DirtyFiles  []string           `json:"dirtyFiles"`  // Patched files with uncommitted git changes
+}
+
+// RenameOccurrence is one occurrence of a renamed identifier.
+type RenameOccurrence struct {
+	FilePath string `json:"filePath"`
+	LineNum  int    `json:"lineNum"`
+	Old      string `json:"old"`     // The spelling found, e.g. get_user
+	New      string `json:"new"`     // What replaces it, e.g. fetch_account
+	Content  string `json:"content"` // The line after renaming, trimmed
+}
+
+// FilteredResults is the grouped view of a completed search returned by
+// FilterResults.
+type FilteredResults struct {
+	SearchID      string        `json:"searchId"`
+	Groups        []ResultGroup `json:"groups"`        // Files in the order they first appeared in the results
+	TotalResults  int           `json:"totalResults"`  // Results left after filtering
+	TotalFiles    int           `json:"totalFiles"`    // Files left after filtering
+	HiddenResults int           `json:"hiddenResults"` // Results removed by the exclude paths
+	HiddenFiles   int           `json:"hiddenFiles"`   // Files removed by the exclude paths
+	Sampled       bool          `json:"sampled"`       // Results are an even sample; see ResultGroup.MatchCount
+	TotalMatches  int           `json:"totalMatches"`  // Matches left after filtering, counting unsampled ones
+	Incomplete    bool          `json:"incomplete"`    // The search stopped early because its directory was removed
+}
+
+// FileMatches is one file of a completed search as GetFileMatches returns
+// it for the results pane.
+type FileMatches struct {
+	SearchID string         `json:"searchId"`
+	FilePath string         `json:"filePath"`
+	Matches  []SearchResult `json:"matches"` // The file's results, in the order they were found
+	Ranges   []LineRange    `json:"ranges"`  // The fewest line ranges showing every match with context; empty for bucket objects
+	Stale    bool           `json:"stale"`   // The file changed since the search; lines may have moved
+}
+
+// LineRange is a run of consecutive lines of a file.
+type LineRange struct {
+	Start int      `json:"start"` // First line (1-indexed)
+	End   int      `json:"end"`   // Last line, inclusive
+	Lines []string `json:"lines"` // Lines Start through End
+}
+
+// AdjacentMatch is the match GetAdjacentMatch moved to.
+type AdjacentMatch struct {
+	SearchID string       `json:"searchId"`
+	Index    int          `json:"index"`   // Position of Result among all matches of the search; -1 when it has none
+	Total    int          `json:"total"`   // Matches of the search
+	Wrapped  bool         `json:"wrapped"` // Navigation went past the last (or first) match and started over
+	Result   SearchResult `json:"result"`
+}
+
+// DirectoryAnalysis describes the files under a directory, returned by
+// AnalyzeDirectory.
+type DirectoryAnalysis struct {
+	Root         string           `json:"root"`
+	Files        int              `json:"files"`
+	Bytes        int64            `json:"bytes"`
+	Lines        int              `json:"lines"`        // Lines of the text files that were read
+	BinaryFiles  int              `json:"binaryFiles"`  // Files that looked binary; their lines are not counted
+	UnreadFiles  int              `json:"unreadFiles"`  // Files too large or unreadable to count lines in
+	Extensions   []ExtensionStats `json:"extensions"`   // Most files first
+	LargestFiles []FileStat       `json:"largestFiles"` // Largest first
+	LongestLines []LineStat       `json:"longestLines"` // Longest line of each file, longest first
+}
+
+// LicenseAudit is the result of AuditLicenseHeaders. Each group is one
+// file with its header comment lines, or its first line when it has none.
+type LicenseAudit struct {
+	Root            string        `json:"root"`
+	FilesChecked    int           `json:"filesChecked"`
+	Compliant       int           `json:"compliant"`       // Files whose header matches the template
+	Missing         []ResultGroup `json:"missing"`         // Files without a license header, by path
+	Mismatched      []ResultGroup `json:"mismatched"`      // Files with a license header that doesn't match, by path
+	MissingFiles    int           `json:"missingFiles"`    // Including files beyond the 1000 listed
+	MismatchedFiles int           `json:"mismatchedFiles"` // Including files beyond the 1000 listed
+	Truncated       bool          `json:"truncated"`       // Missing or Mismatched was cut at 1000 files
+}
+
+// ImportScanReport is the result of ScanImports.
+type ImportScanReport struct {
+	Root         string            `json:"root"`
+	FilesScanned int               `json:"filesScanned"`
+	Packages     []ImportedPackage `json:"packages"` // Most importing files first
+}
+
+// ImportedPackage is an external package referenced by the files under a
+// ScanImports root.
+type ImportedPackage struct {
+	Name        string   `json:"name"`        // e.g. "github.com/sirupsen/logrus", "@vue/test-utils", "requests"
+	Language    string   `json:"language"`    // "go", "javascript", "python", "java", or "ruby"
+	Files       int      `json:"files"`       // Files importing it
+	Imports     int      `json:"imports"`     // Import statements referencing it, across those files
+	SampleFiles []string `json:"sampleFiles"` // Up to five of the files, in path order
+}
+
+// SecretScanReport is the result of ScanSecrets.
+type SecretScanReport struct {
+	Root         string          `json:"root"`
+	FilesScanned int             `json:"filesScanned"`
+	Findings     []SecretFinding `json:"findings"`   // By file, then line
+	Truncated    bool            `json:"truncated"`  // More than 1000 findings; the rest were dropped
+	DurationMs   int64           `json:"durationMs"` // Wall-clock time of the scan
+}
+
+// SecretFinding is one suspected credential found by ScanSecrets. The
+// secret itself is masked in Match and Line.
+type SecretFinding struct {
+	FilePath string  `json:"filePath"`
+	LineNum  int     `json:"lineNum"`
+	Rule     string  `json:"rule"`              // e.g. "aws-access-key-id", or "high-entropy-string"
+	RuleName string  `json:"ruleName"`          // e.g. "AWS access key ID"
+	Match    string  `json:"match"`             // The secret, masked: "AKIA**************LE"
+	Line     string  `json:"line"`              // The trimmed line with every secret in it masked, up to 200 bytes
+	Entropy  float64 `json:"entropy,omitempty"` // Bits per character, for high-entropy strings
+}
+
+// ExtensionStats totals the files with one extension in a
+// DirectoryAnalysis.
+type ExtensionStats struct {
+	Extension   string `json:"extension"` // Lowercase with the dot, e.g. ".go"; empty for files without one
+	Files       int    `json:"files"`
+	Bytes       int64  `json:"bytes"`
+	Lines       int    `json:"lines"`
+	BinaryFiles int    `json:"binaryFiles"`
+}
+
+// FileStat is a file in DirectoryAnalysis.LargestFiles.
+type FileStat struct {
+	FilePath string `json:"filePath"`
+	Size     int64  `json:"size"`
+	Lines    int    `json:"lines"` // 0 for binary and unread files
+}
+
+// LineStat is a line in DirectoryAnalysis.LongestLines.
+type LineStat struct {
+	FilePath string `json:"filePath"`
+	LineNum  int    `json:"lineNum"`
+	Length   int    `json:"length"`  // In bytes, without the line ending
+	Preview  string `json:"preview"` // The start of the line
+}
+
+// FilesystemIssue is a file or directory a search would skip or miss,
+// found by ScanFilesystemIssues.
+type FilesystemIssue struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`   // "broken-symlink", "empty-file", or "unreadable"
+	Detail string `json:"detail"` // Symlink target, or the error for unreadable entries
+}
+
+// FilesystemReport is the result of ScanFilesystemIssues.
+type FilesystemReport struct {
+	Root         string            `json:"root"`
+	FilesScanned int               `json:"filesScanned"`
+	Issues       []FilesystemIssue `json:"issues"`    // By kind, then path
+	Counts       map[string]int    `json:"counts"`    // Issues per kind, including those beyond the cap
+	Truncated    bool              `json:"truncated"` // Some kind had more than 1000 issues
+}
+
+// CaptureLocation is a result a captured value was taken from.
+type CaptureLocation struct {
+	FilePath string `json:"filePath"`
+	LineNum  int    `json:"lineNum"`
+}
+
+// CaptureValue is one distinct value of a capture group in a
+// CaptureReport.
+type CaptureValue struct {
+	Value    string            `json:"value"`
+	Count    int               `json:"count"`    // Results the value was captured from
+	Files    int               `json:"files"`    // Distinct files among them
+	Examples []CaptureLocation `json:"examples"` // The first few results, in result order
+}
+
+// CaptureReport lists the distinct values one capture group took across a
+// search's results, returned by AggregateCaptures.
+type CaptureReport struct {
+	SearchID string         `json:"searchId"`
+	Group    string         `json:"group"`    // Name or number of the group
+	Values   []CaptureValue `json:"values"`   // Most frequent first, ties by value
+	Captured int            `json:"captured"` // Results that captured the group
+	Results  int            `json:"results"`  // Results of the search
+}
+
+// LaunchRequest is a search to pre-fill from the command line, a
+// codesearch:// link, or the OS context menu (see parseLaunchArgs).
+type LaunchRequest struct {
+	Directory string `json:"directory"` // Absolute directory to search; empty if none was given
+	Query     string `json:"query"`     // Search query; empty if none was given
+
+	Request *SearchRequest `json:"request,omitempty"` // Every setting of a search shared as codesearch://search?r=...; Directory matches the field above
+}
+
+// DropResult is returned by HandleDroppedPaths: the search roots accepted
+// from a drop, and the dropped paths that were rejected.
+type DropResult struct {
+	Roots    []string       `json:"roots"`    // Absolute, de-duplicated, outermost directories
+	Rejected []RejectedPath `json:"rejected"` // Paths that can't be searched, with the reason
+}
+
+// RejectedPath is a dropped path that HandleDroppedPaths refused.
+type RejectedPath struct {
+	Path    string    `json:"path"`    // The path as dropped
+	Code    ErrorCode `json:"code"`    // Why it was rejected
+	Message string    `json:"message"` // Reason in the current locale
+}
+
+// UpdateInfo is the result of CheckForUpdates.
+type UpdateInfo struct {
+	CurrentVersion  string        `json:"currentVersion"`  // Version of this build, "dev" for local builds
+	LatestVersion   string        `json:"latestVersion"`   // Tag of the newest release on the channel, empty if it has none
+	Channel         string        `json:"channel"`         // Channel that was checked
+	UpdateAvailable bool          `json:"updateAvailable"` // LatestVersion is newer than CurrentVersion; never set for local builds
+	ReleaseName     string        `json:"releaseName"`
+	ReleaseURL      string        `json:"releaseURL"`  // Release page on GitHub
+	Notes           string        `json:"notes"`       // Release notes, in Markdown
+	PublishedAt     string        `json:"publishedAt"` // RFC 3339
+	Assets          []UpdateAsset `json:"assets"`      // Downloads of the release
+	Asset           string        `json:"asset"`       // Name of the download for this platform, empty if none fits
+	CheckedAt       int64         `json:"checkedAt"`   // Unix milliseconds
+}
+
+// UpdateAsset is a download of a release.
+type UpdateAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"` // Bytes
 }
 
 // ProgressCallback is a function type for reporting search progress
@@ -58,19 +663,68 @@ type EditorAvailability struct {
 	VisualStudio    bool `json:"visualstudio"`
 	Eclipse         bool `json:"eclipse"`
 	NetBeans        bool `json:"netbeans"`
+	Nano            bool `json:"nano"`
+	Helix           bool `json:"helix"`
+}
+
+// EditorDiagnostic is the result of TestEditorLaunch.
+type EditorDiagnostic struct {
+	Editor     string    `json:"editor"`            // Binding name
+	Command    string    `json:"command"`           // Command the binding runs
+	Path       string    `json:"path"`              // Binary found; empty when the editor is not installed
+	Source     string    `json:"source"`            // Where Path came from: "settings" (SetEditorPath), "discovered" (install folders), or "path"
+	Args       []string  `json:"args"`              // Arguments of the version check; empty for editors without one
+	Launched   bool      `json:"launched"`          // The check process started
+	ExitCode   int       `json:"exitCode"`          // Exit status of the check
+	Version    string    `json:"version"`           // First line of the check's output
+	Output     string    `json:"output"`            // Standard output and error of the check, up to 4KB
+	DurationMs int64     `json:"durationMs"`        // How long the check ran
+	OK         bool      `json:"ok"`                // The editor was found and, if checked, exited cleanly
+	Problem    ErrorCode `json:"problem,omitempty"` // What went wrong, e.g. EDITOR_NOT_FOUND or EDITOR_CHECK_EXIT
+	Message    string    `json:"message,omitempty"` // Problem in the current locale
 }
 
 // SearchProgress represents the progress of a search operation
 type SearchProgress struct {
-	ProcessedFiles int    `json:"processedFiles"`
-	TotalFiles     int    `json:"totalFiles"`
-	CurrentFile    string `json:"currentFile"`
-	ResultsCount   int    `json:"resultsCount"`
-	Status         string `json:"status"`
+	SearchID       string     `json:"searchId"` // Search the event belongs to; pass it to FilterResults
+	ProcessedFiles int        `json:"processedFiles"`
+	TotalFiles     int        `json:"totalFiles"`
+	CurrentFile    string     `json:"currentFile"`
+	ResultsCount   int        `json:"resultsCount"`
+	Status         string     `json:"status"`                 // "collecting", "started", "in-progress", "completed", or "cancelled"
+	Sampled        bool       `json:"sampled,omitempty"`      // Set on the completed event when the results are a sample
+	TotalMatches   int        `json:"totalMatches,omitempty"` // Matches found before sampling, or written to the result log (completed event)
+	Incomplete     bool       `json:"incomplete,omitempty"`   // Set on the completed event when the search directory was removed mid-search
+	Skipped        *SkipStats `json:"skipped,omitempty"`      // Files skipped during processing, by reason (completed event only)
+}
+
+// CollectionProgress is the payload of the collection-progress events a
+// directory walk emits while it lists the files to search.
+type CollectionProgress struct {
+	SearchID    string `json:"searchId"`    // Search the walk collects files for
+	Directory   string `json:"directory"`   // Root of the walk
+	CurrentPath string `json:"currentPath"` // Entry the walk just reached
+	DirsVisited int    `json:"dirsVisited"` // Directories walked into so far
+	FilesQueued int    `json:"filesQueued"` // Files listed for searching so far, before the binary probe
+	ElapsedMs   int64  `json:"elapsedMs"`   // Time since the walk started
+}
+
+// SkipStats counts the collected files a search skipped while processing,
+// by reason. Files filtered out during collection are not included.
+type SkipStats struct {
+	Generated  int `json:"generated"`  // Dropped by the SkipGenerated content heuristic
+	Vanished   int `json:"vanished"`   // Deleted after collection
+	Locked     int `json:"locked"`     // Held by another process, after the retry if RetryLocked is set
+	Unreadable int `json:"unreadable"` // Permission denied or another read error
 }
 
 // SearchState holds the atomic counters for the search process
 type SearchState struct {
-	processedFiles int32
-	resultsCount   int32
+	processedFiles   int32
+	resultsCount     int32
+	generatedSkipped int32 // Files dropped by the SkipGenerated content heuristic
+	vanishedFiles    int32 // Files deleted between collection and processing
+	lockedFiles      int32 // Files another process held locked
+	unreadableFiles  int32 // Files that failed to read for any other reason
+	rootRemoved      int32 // Set to 1 once the search directory itself is gone
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// searchNotificationID is reused for every search notification so a new one
+// replaces the previous instead of stacking up in the notification center.
+const searchNotificationID = "search-finished"
+
+// initNotifications sets up the platform notification service (D-Bus on
+// Linux, WinRT toasts on Windows). Failure only disables notifications.
+func (a *App) initNotifications() {
+	defer func() {
+		recover()
+	}()
+	if err := wailsRuntime.InitializeNotifications(a.ctx); err != nil {
+		a.logWarn("Desktop notifications unavailable", logrus.Fields{"error": err.Error()})
+		return
+	}
+	atomic.StoreInt32(&a.notificationsReady, 1)
+}
+
+// cleanupNotifications releases the notification service's resources.
+func (a *App) cleanupNotifications() {
+	if atomic.LoadInt32(&a.notificationsReady) == 0 {
+		return
+	}
+	defer func() {
+		recover()
+	}()
+	wailsRuntime.CleanupNotifications(a.ctx)
+}
+
+// shouldNotifySearch reports whether a search that ran for duration should
+// raise a desktop notification under the given settings.
+func shouldNotifySearch(settings Settings, duration time.Duration) bool {
+	return settings.NotifyOnCompletion &&
+		duration >= time.Duration(settings.NotifyMinSeconds)*time.Second
+}
+
+// notifySearchFinished sends a desktop notification with the match count
+// and duration of a long search that completed or was cancelled, so a
+// search left running in a background window doesn't finish unnoticed.
+func (a *App) notifySearchFinished(cancelled bool, resultsCount int, duration time.Duration) {
+	if a.ctx == nil || atomic.LoadInt32(&a.notificationsReady) == 0 || !shouldNotifySearch(a.currentSettings(), duration) {
+		return
+	}
+
+	title := "Search completed"
+	if cancelled {
+		title = "Search cancelled"
+	}
+	body := fmt.Sprintf("%d matches in %s", resultsCount, duration.Round(100*time.Millisecond))
+
+	// Like safeEmitEvent: the runtime panics outside a live Wails context.
+	defer func() {
+		recover()
+	}()
+	if err := wailsRuntime.SendNotification(a.ctx, wailsRuntime.NotificationOptions{
+		ID:    searchNotificationID,
+		Title: title,
+		Body:  body,
+	}); err != nil {
+		a.logWarn("Failed to send search notification", logrus.Fields{"error": err.Error()})
+	}
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Content plugins rewrite a file before it is matched: a decompiler for
+// .class files, a pretty-printer for minified JSON, a decrypter for .enc
+// files. An installed plugin is a directory in <data dir>/plugins holding a
+// plugin.json manifest and usually the program it names. The program is
+// started on first use and kept running. It reads one JSON request per
+// line on stdin and answers each with one JSON line on stdout:
+//
+//	{"id": 1, "path": "/src/A.class", "content": "<base64>"}
+//	{"id": 1, "content": "<base64>"}   or   {"id": 1, "error": "message"}
+//
+// Plugins are off until enabled with EnablePlugin, since an enabled plugin
+// runs its program on every file it handles.
+
+const (
+	pluginsDirName     = "plugins"      // Data-directory subdirectory holding installed plugins
+	pluginManifestName = "plugin.json"  // Manifest file in each plugin directory
+	pluginsFileName    = "plugins.json" // Data-directory file listing the enabled plugins
+)
+
+// pluginRequestTimeout bounds one request to a plugin program. A program
+// that misses it is stopped, and started again for the next file.
+const pluginRequestTimeout = 30 * time.Second
+
+// pluginManifest is the content of plugin.json.
+type pluginManifest struct {
+	Name        string   `json:"name"` // Defaults to the directory name
+	Description string   `json:"description"`
+	Extensions  []string `json:"extensions"` // With or without the dot
+	Command     []string `json:"command"`    // Program and arguments; a relative program is looked up in the plugin directory first
+}
+
+// pluginState is the on-disk layout of pluginsFileName.
+type pluginState struct {
+	Enabled []string `json:"enabled"`
+}
+
+// contentTransformer rewrites the content of a file before it is matched.
+// transform may be called from several search workers at once.
+type contentTransformer interface {
+	transform(path string, content []byte) ([]byte, error)
+	close()
+}
+
+// plugin is a built-in or installed plugin.
+type plugin struct {
+	info        PluginInfo
+	manifest    pluginManifest     // Installed plugins only
+	transformer contentTransformer // nil when info.Error is set
+}
+
+// builtinPlugins returns the plugins that ship with the app.
+func builtinPlugins() []*plugin {
+	return []*plugin{{
+		info: PluginInfo{
+			Name:        "json-pretty",
+			Description: "Pretty-prints minified JSON so each value is on its own line",
+			Extensions:  []string{"json"},
+			Builtin:     true,
+		},
+		transformer: prettyJSON{},
+	}}
+}
+
+// prettyJSON indents JSON files. Files that aren't strict JSON, such as
+// JSON with comments, are searched as they are.
+type prettyJSON struct{}
+
+func (prettyJSON) transform(_ string, content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, content, "", "  "); err != nil {
+		return content, nil
+	}
+	return buf.Bytes(), nil
+}
+
+func (prettyJSON) close() {}
+
+// pluginExtension normalizes an extension to the form pluginExts is keyed
+// by: lower case, without the dot.
+func pluginExtension(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+}
+
+// loadPlugin reads the manifest of the plugin installed in dir. Problems
+// are reported in info.Error, so ListPlugins can show them.
+func (a *App) loadPlugin(dir string) *plugin {
+	p := &plugin{info: PluginInfo{Name: filepath.Base(dir), Path: dir, Extensions: []string{}}}
+	data, err := os.ReadFile(filepath.Join(dir, pluginManifestName))
+	if err != nil {
+		p.info.Error = err.Error()
+		return p
+	}
+	if err := json.Unmarshal(data, &p.manifest); err != nil {
+		p.info.Error = fmt.Sprintf("%s: %v", pluginManifestName, err)
+		return p
+	}
+
+	m := p.manifest
+	if m.Name != "" {
+		p.info.Name = m.Name
+	}
+	p.info.Description = m.Description
+	for _, ext := range m.Extensions {
+		if ext = pluginExtension(ext); ext != "" {
+			p.info.Extensions = append(p.info.Extensions, ext)
+		}
+	}
+	switch {
+	case len(p.info.Extensions) == 0:
+		p.info.Error = pluginManifestName + " lists no extensions"
+	case len(m.Command) == 0 || m.Command[0] == "":
+		p.info.Error = pluginManifestName + " has no command"
+	default:
+		p.transformer = &execTransformer{a: a, name: p.info.Name, dir: dir, command: m.Command}
+	}
+	return p
+}
+
+// refreshPluginsLocked rescans the plugins directory and the enabled list.
+// Installed plugins whose manifest didn't change keep their running
+// program; the programs of the others are stopped. Callers hold pluginsMu
+// for writing.
+func (a *App) refreshPluginsLocked() error {
+	var state pluginState
+	_, stateErr := a.loadJSON(pluginsFileName, &state)
+	enabled := make(map[string]bool, len(state.Enabled))
+	for _, name := range state.Enabled {
+		enabled[name] = true
+	}
+
+	previous := make(map[string]*plugin, len(a.plugins))
+	for _, p := range a.plugins {
+		if !p.info.Builtin {
+			previous[p.info.Name] = p
+		}
+	}
+
+	plugins := builtinPlugins()
+	taken := make(map[string]bool)
+	for _, p := range plugins {
+		taken[p.info.Name] = true
+	}
+	var dirErr error
+	if a.dataDir != "" {
+		entries, err := os.ReadDir(filepath.Join(a.dataDir, pluginsDirName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			dirErr = fmt.Errorf("failed to read plugins directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			p := a.loadPlugin(filepath.Join(a.dataDir, pluginsDirName, entry.Name()))
+			if taken[p.info.Name] {
+				p.info.Error = "another plugin is named " + p.info.Name
+				p.transformer = nil
+			}
+			taken[p.info.Name] = true
+			if prev := previous[p.info.Name]; prev != nil && p.info.Error == "" && prev.info.Path == p.info.Path && reflect.DeepEqual(prev.manifest, p.manifest) {
+				p.transformer = prev.transformer
+				delete(previous, p.info.Name)
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	for _, p := range previous {
+		if p.transformer != nil {
+			p.transformer.close()
+		}
+	}
+
+	// The first enabled plugin for an extension handles it: built-ins
+	// before installed plugins, then by directory name.
+	exts := make(map[string]contentTransformer)
+	for _, p := range plugins {
+		p.info.Enabled = enabled[p.info.Name] && p.info.Error == ""
+		if !p.info.Enabled {
+			continue
+		}
+		for _, ext := range p.info.Extensions {
+			if _, ok := exts[ext]; !ok {
+				exts[ext] = p.transformer
+			}
+		}
+	}
+	a.plugins = plugins
+	a.pluginExts = exts
+	return errors.Join(stateErr, dirErr)
+}
+
+// pluginFor returns the transformer of the enabled plugin handling the
+// file's extension, or nil. Plugins are loaded on first use.
+func (a *App) pluginFor(path string) contentTransformer {
+	ext := pluginExtension(filepath.Ext(path))
+	a.pluginsMu.RLock()
+	loaded := a.plugins != nil
+	t := a.pluginExts[ext]
+	a.pluginsMu.RUnlock()
+	if loaded {
+		return t
+	}
+
+	a.pluginsMu.Lock()
+	defer a.pluginsMu.Unlock()
+	if a.plugins == nil {
+		if err := a.refreshPluginsLocked(); err != nil {
+			a.logWarn("Failed to load plugins", logrus.Fields{"error": err.Error()})
+		}
+	}
+	return a.pluginExts[ext]
+}
+
+// withPlugin routes the file through the enabled plugin for its extension,
+// if there is one. A plugin's output is text, so the binary check is
+// dropped.
+func (a *App) withPlugin(meta fileMeta) fileMeta {
+	t := a.pluginFor(meta.absPath)
+	if t == nil {
+		return meta
+	}
+	meta.content = pluginContent{inner: meta.contentProvider(), transformer: t}
+	meta.checkBinary = false
+	return meta
+}
+
+// pluginContent reads files through another provider and a plugin.
+type pluginContent struct {
+	inner       ContentProvider
+	transformer contentTransformer
+}
+
+func (p pluginContent) Open(path string) (io.ReadCloser, error) {
+	content, err := readAllContent(p.inner, path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.transformer.transform(path, content)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(out)), nil
+}
+
+// ListPlugins returns the built-in plugins followed by those installed in
+// the plugins directory, which is rescanned on every call.
+func (a *App) ListPlugins() ([]PluginInfo, error) {
+	a.pluginsMu.Lock()
+	defer a.pluginsMu.Unlock()
+	if err := a.refreshPluginsLocked(); err != nil {
+		return nil, err
+	}
+	infos := make([]PluginInfo, len(a.plugins))
+	for i, p := range a.plugins {
+		infos[i] = p.info
+	}
+	return infos, nil
+}
+
+// EnablePlugin turns a plugin on or off for later searches and remembers
+// the choice. Disabling a plugin stops its program.
+func (a *App) EnablePlugin(name string, enabled bool) (PluginInfo, error) {
+	a.pluginsMu.Lock()
+	defer a.pluginsMu.Unlock()
+	if err := a.refreshPluginsLocked(); err != nil {
+		return PluginInfo{}, err
+	}
+
+	var target *plugin
+	var names []string
+	for _, p := range a.plugins {
+		if p.info.Name == name && target == nil {
+			target = p
+		}
+		if p.info.Enabled && p.info.Name != name {
+			names = append(names, p.info.Name)
+		}
+	}
+	if target == nil {
+		return PluginInfo{}, newAppError(ErrCodePluginNotFound, name)
+	}
+	if enabled && target.info.Error != "" {
+		return PluginInfo{}, newAppError(ErrCodePluginInvalid, name, target.info.Error)
+	}
+
+	if enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := a.saveJSON(pluginsFileName, pluginState{Enabled: names}); err != nil {
+		return PluginInfo{}, err
+	}
+	if !enabled && target.transformer != nil {
+		target.transformer.close()
+	}
+	if err := a.refreshPluginsLocked(); err != nil {
+		return PluginInfo{}, err
+	}
+	a.logInfo("Plugin setting changed", logrus.Fields{"plugin": name, "enabled": enabled})
+	for _, p := range a.plugins {
+		if p.info.Name == name {
+			return p.info, nil
+		}
+	}
+	return target.info, nil
+}
+
+// closePlugins stops the programs of installed plugins.
+func (a *App) closePlugins() {
+	a.pluginsMu.Lock()
+	defer a.pluginsMu.Unlock()
+	for _, p := range a.plugins {
+		if p.transformer != nil {
+			p.transformer.close()
+		}
+	}
+}
+
+// pluginRequest and pluginResponse are the lines exchanged with a plugin
+// program. Content is base64 in JSON.
+type (
+	pluginRequest struct {
+		ID      int64  `json:"id"`
+		Path    string `json:"path"`
+		Content []byte `json:"content"`
+	}
+	pluginResponse struct {
+		ID      int64  `json:"id"`
+		Content []byte `json:"content"`
+		Error   string `json:"error"`
+	}
+)
+
+// execTransformer is the transformer of an installed plugin: a running
+// program it sends one request at a time.
+type execTransformer struct {
+	a       *App
+	name    string
+	dir     string
+	command []string
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	replies chan []byte   // Lines of the program's stdout; closed when it ends
+	done    chan struct{} // Closed when the program is stopped
+	nextID  int64
+}
+
+// startLocked starts the program. Callers hold t.mu.
+func (t *execTransformer) startLocked() error {
+	program := t.command[0]
+	if !filepath.IsAbs(program) {
+		if local, err := exec.LookPath(filepath.Join(t.dir, program)); err == nil {
+			program = local
+		}
+	}
+	cmd := exec.Command(program, t.command[1:]...)
+	cmd.Dir = t.dir
+	hideConsoleWindow(cmd)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	replies := make(chan []byte)
+	done := make(chan struct{})
+	go func() {
+		defer close(replies)
+		r := bufio.NewReader(stdout)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				select {
+				case replies <- line:
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	// The program's stderr goes to the log, for plugin authors.
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			t.a.logWarn("Plugin output", logrus.Fields{"plugin": t.name, "line": scanner.Text()})
+		}
+	}()
+
+	t.cmd, t.stdin, t.replies, t.done = cmd, stdin, replies, done
+	t.a.logInfo("Started plugin", logrus.Fields{"plugin": t.name, "pid": cmd.Process.Pid})
+	return nil
+}
+
+// stopLocked stops the program, if it runs. Callers hold t.mu.
+func (t *execTransformer) stopLocked() {
+	if t.cmd == nil {
+		return
+	}
+	t.stdin.Close()
+	t.cmd.Process.Kill()
+	t.cmd.Wait()
+	close(t.done)
+	t.cmd, t.stdin, t.replies, t.done = nil, nil, nil, nil
+}
+
+func (t *execTransformer) transform(path string, content []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cmd == nil {
+		if err := t.startLocked(); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", t.name, err)
+		}
+	}
+	t.nextID++
+	line, err := json.Marshal(pluginRequest{ID: t.nextID, Path: path, Content: content})
+	if err != nil {
+		return nil, err
+	}
+
+	// Killing a program that misses the timeout unblocks both the write
+	// and the read below.
+	var timedOut atomic.Bool
+	process := t.cmd.Process
+	watchdog := time.AfterFunc(pluginRequestTimeout, func() {
+		timedOut.Store(true)
+		process.Kill()
+	})
+	defer watchdog.Stop()
+
+	var reply []byte
+	_, err = t.stdin.Write(append(line, '\n'))
+	if err == nil {
+		var ok bool
+		if reply, ok = <-t.replies; !ok {
+			err = errors.New("exited")
+		}
+	}
+	var resp pluginResponse
+	if err == nil {
+		if json.Unmarshal(reply, &resp) != nil || resp.ID != t.nextID {
+			err = errors.New("sent an invalid reply")
+		}
+	}
+	if err != nil {
+		t.stopLocked()
+		if timedOut.Load() {
+			err = fmt.Errorf("did not answer within %s", pluginRequestTimeout)
+		}
+		t.a.logWarn("Plugin stopped", logrus.Fields{"plugin": t.name, "filePath": path, "error": err.Error()})
+		return nil, fmt.Errorf("plugin %s: %w", t.name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", t.name, resp.Error)
+	}
+	return resp.Content, nil
+}
+
+func (t *execTransformer) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopLocked()
+}
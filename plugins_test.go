@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPluginHelperProcess is the plugin program of TestPlugins, run by the
+// test binary itself. It strips NUL bytes and upper-cases the content, and
+// fails or exits on files named for it.
+func TestPluginHelperProcess(t *testing.T) {
+	if os.Getenv("CODESEARCH_PLUGIN_HELPER") != "1" {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "helper started")
+	in := bufio.NewReader(os.Stdin)
+	for {
+		line, err := in.ReadBytes('\n')
+		if err != nil {
+			os.Exit(0)
+		}
+		var req pluginRequest
+		json.Unmarshal(line, &req)
+		resp := pluginResponse{ID: req.ID}
+		switch filepath.Base(req.Path) {
+		case "crash.class":
+			os.Exit(1)
+		case "fail.class":
+			resp.Error = "cannot decompile"
+		default:
+			resp.Content = bytes.ToUpper(bytes.ReplaceAll(req.Content, []byte{0}, nil))
+		}
+		out, _ := json.Marshal(resp)
+		os.Stdout.Write(append(out, '\n'))
+	}
+}
+
+// installPlugin writes a plugin directory with the given manifest.
+func installPlugin(t *testing.T, dataDir, dir, manifest string) {
+	t.Helper()
+	pluginDir := filepath.Join(dataDir, pluginsDirName, dir)
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if manifest != "" {
+		if err := os.WriteFile(filepath.Join(pluginDir, pluginManifestName), []byte(manifest), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestPlugins verifies plugin discovery and manifest errors, that enabling
+// is remembered, and that enabled plugins transform the files they handle
+// before matching: binary files become searchable, plugin errors and
+// crashes only skip the file, and the built-in JSON plugin changes lines.
+func TestPlugins(t *testing.T) {
+	t.Setenv("CODESEARCH_PLUGIN_HELPER", "1")
+	dataDir := t.TempDir()
+	command, _ := json.Marshal([]string{os.Args[0], "-test.run=^TestPluginHelperProcess$"})
+	installPlugin(t, dataDir, "upper", `{"name": "upper", "description": "Upper-cases", "extensions": [".CLASS"], "command": `+string(command)+`}`)
+	installPlugin(t, dataDir, "broken", "")
+	installPlugin(t, dataDir, "shadow", `{"name": "json-pretty", "extensions": ["json"], "command": ["cat"]}`)
+	installPlugin(t, dataDir, "nocmd", `{"extensions": ["enc"]}`)
+
+	app := NewApp()
+	app.dataDir = dataDir
+	t.Cleanup(app.closePlugins)
+
+	plugins, err := app.ListPlugins()
+	if err != nil {
+		t.Fatalf("ListPlugins failed: %v", err)
+	}
+	byName := map[string]PluginInfo{}
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+		if _, dup := byName[p.Name]; !dup {
+			byName[p.Name] = p
+		}
+	}
+	if strings.Join(names, ",") != "json-pretty,broken,nocmd,json-pretty,upper" {
+		t.Errorf("unexpected plugin order %v", names)
+	}
+	if p := byName["upper"]; p.Error != "" || p.Enabled || len(p.Extensions) != 1 || p.Extensions[0] != "class" {
+		t.Errorf("unexpected upper plugin %+v", p)
+	}
+	if !byName["json-pretty"].Builtin || plugins[3].Error == "" || byName["broken"].Error == "" || byName["nocmd"].Error == "" {
+		t.Errorf("expected manifest errors for broken, nocmd, and the shadowing plugin, got %+v", plugins)
+	}
+
+	if _, err := app.EnablePlugin("broken", true); err == nil || err.(*AppError).Code != ErrCodePluginInvalid {
+		t.Errorf("expected %s, got %v", ErrCodePluginInvalid, err)
+	}
+	if _, err := app.EnablePlugin("missing", true); err == nil || err.(*AppError).Code != ErrCodePluginNotFound {
+		t.Errorf("expected %s, got %v", ErrCodePluginNotFound, err)
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"A.class":     "\x00\x01needle in a\x00",
+		"B.class":     "\x00\x01needle in b\x00",
+		"crash.class": "\x00needle",
+		"fail.class":  "\x00needle",
+		"notes.txt":   "needle stays lower case",
+		"data.json":   `{"a":1,"needle":2}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	search := func(query string) map[string]SearchResult {
+		t.Helper()
+		results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: query, CaseSensitive: true})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		byFile := map[string]SearchResult{}
+		for _, r := range results {
+			byFile[filepath.Base(r.FilePath)] = r
+		}
+		return byFile
+	}
+
+	if got := search("NEEDLE"); len(got) != 0 {
+		t.Errorf("expected no matches without plugins, got %+v", got)
+	}
+	if info, err := app.EnablePlugin("upper", true); err != nil || !info.Enabled {
+		t.Fatalf("EnablePlugin = %+v, %v", info, err)
+	}
+	got := search("NEEDLE")
+	if len(got) != 2 || got["A.class"].Content != "\x01NEEDLE IN A" || got["B.class"].LineNum != 1 {
+		t.Errorf("expected the transformed A.class and B.class, got %+v", got)
+	}
+
+	if _, err := app.EnablePlugin("json-pretty", true); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := search(`"needle": 2`)["data.json"]; !ok || r.LineNum != 3 {
+		t.Errorf("expected the pretty-printed line 3 of data.json, got %+v", r)
+	}
+
+	reloaded := NewApp()
+	reloaded.dataDir = dataDir
+	plugins, err = reloaded.ListPlugins()
+	if err != nil || !plugins[0].Enabled || !plugins[4].Enabled || plugins[1].Enabled {
+		t.Errorf("expected json-pretty and upper to stay enabled, got %+v, %v", plugins, err)
+	}
+
+	if info, err := app.EnablePlugin("upper", false); err != nil || info.Enabled {
+		t.Fatalf("EnablePlugin = %+v, %v", info, err)
+	}
+	if got := search("NEEDLE"); len(got) != 0 {
+		t.Errorf("expected no matches after disabling, got %+v", got)
+	}
+}
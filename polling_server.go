@@ -5,7 +5,6 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 
@@ -158,43 +157,29 @@ func parseLogLine(line string) (LogMessage, bool) {
 
 // parseLogEntryMessage is the single source of truth for noise filtering. It
 // accepts either a raw string (plain-text log line) or a parsed JSON object
-// (structured logrus entry) and returns (content, skip). Both the initial-load
-// path (readLastNLines via parseLogLine) and the live tail path (AddLogEntry)
-// route through here so they apply identical rules (#1).
+// (structured logrus entry) and returns (content, skip). Both the file-reading
+// path (parseLogLine) and the live tail path (AddLogEntry) route through here
+// so they apply identical rules (#1): the logFilters setting, or
+// defaultLogFilters (see logContentFiltered).
 //
 // The returned content is the value that should be stored on LogMessage.Content
 // (for a string input, the same string; for an object, the same object). When
 // skip is true the caller must drop the entry.
 func parseLogEntryMessage(raw interface{}) (interface{}, bool) {
-	switch v := raw.(type) {
-	case string:
-		if isNoisyMessage(v) {
+	switch raw.(type) {
+	case string, map[string]interface{}:
+		if logContentFiltered(raw) {
 			return nil, true
 		}
-		return v, false
-	case map[string]interface{}:
-		if msg, ok := v["msg"].(string); ok && isNoisyMessage(msg) {
-			return nil, true
-		}
-		return v, false
-	default:
-		return raw, false
 	}
+	return raw, false
 }
 
-// isNoisyMessage reports whether a log message should be filtered out of the
-// viewer. The current rule is "contains 'Skipping' or 'Sending file'" — the
-// per-file progress lines that flood the log during a search and add no value
-// in the UI.
-func isNoisyMessage(msg string) bool {
-	return strings.Contains(msg, "Skipping") || strings.Contains(msg, "Sending file")
-}
-
-// StartLogTailing starts tailing the log file in a goroutine. The tailed
+// StartLogTailing starts tailing app.log in logDir in a goroutine. The tailed
 // entries are added to the in-memory buffer and consumed by the frontend via
 // the GetInitialLogs() and GetNewLogs() Wails bindings.
-func (p *PollingLogManager) StartLogTailing() {
-	logFilePath := filepath.Join("logs", "app.log")
+func (p *PollingLogManager) StartLogTailing(logDir string) {
+	logFilePath := filepath.Join(logDir, logFileName)
 	go p.TailFile(logFilePath)
 }
 
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// builtinPresetPrefix starts the ID of every built-in preset, which keeps
+// them apart from the random hex IDs of saved templates.
+const builtinPresetPrefix = "builtin-"
+
+// presetExcludes are the dependency and build directories the presets skip;
+// a leftover console.log in node_modules is not the user's to fix.
+var presetExcludes = []string{"node_modules", "vendor", "dist", "build", "*.min.js"}
+
+// builtinPresets are the preset searches for common code smells. They are
+// query templates whose directory is the {directory} placeholder, so
+// RunTemplate runs them like any saved template.
+var builtinPresets = []QueryTemplate{
+	{
+		ID:          builtinPresetPrefix + "hardcoded-credentials",
+		Name:        "Hardcoded credentials",
+		Description: "Passwords, secrets, API keys, and tokens assigned string literals",
+		Request: SearchRequest{
+			Query: `(?i)\b(password|passwd|pwd|secret|api[_-]?key|access[_-]?key|auth[_-]?token|access[_-]?token|client[_-]?secret)["']?\s*(:=|=|:)\s*["'][^"'\s]{4,}["']`,
+		},
+	},
+	{
+		ID:          builtinPresetPrefix + "todo-fixme",
+		Name:        "TODO and FIXME comments",
+		Description: "TODO, FIXME, XXX, and HACK markers left in the code",
+		Request: SearchRequest{
+			Query:         `\b(TODO|FIXME|XXX|HACK)\b`,
+			CaseSensitive: true,
+		},
+	},
+	{
+		ID:          builtinPresetPrefix + "debug-leftovers",
+		Name:        "Debugging leftovers",
+		Description: "console.log, debugger statements, and print calls left from debugging",
+		Request: SearchRequest{
+			Query:         `\bconsole\.(log|debug|trace|dir)\(|\bdebugger\b|\bprintln!?\(|\bfmt\.Print(ln|f)?\(|\bSystem\.out\.print(ln)?\(|\bvar_dump\(|\bprint_r\(`,
+			CaseSensitive: true,
+		},
+	},
+	{
+		ID:          builtinPresetPrefix + "focused-tests",
+		Name:        "Focused tests",
+		Description: "Tests narrowed with .only( or fit/fdescribe, which silently skip the rest of the suite",
+		Request: SearchRequest{
+			Query:         `\b(describe|it|test|context|suite)\.only\(|\b(fit|fdescribe|fcontext)\(`,
+			CaseSensitive: true,
+		},
+	},
+}
+
+// ListBuiltinPresets returns the built-in preset searches. Run one with
+// RunTemplate(id, {"directory": dir}); ClonePreset copies one into the
+// saved templates for customizing.
+func (a *App) ListBuiltinPresets() []QueryTemplate {
+	presets := make([]QueryTemplate, 0, len(builtinPresets))
+	for _, preset := range builtinPresets {
+		presets = append(presets, presetTemplate(preset))
+	}
+	return presets
+}
+
+// presetTemplate completes a built-in preset: the {directory} placeholder,
+// the shared excludes, and its Variables. The preset queries have no
+// placeholders of their own; TestBuiltinPresets checks they stay valid
+// templates.
+func presetTemplate(preset QueryTemplate) QueryTemplate {
+	preset.Request.Directory = "{directory}"
+	preset.Request.ExcludePatterns = append([]string(nil), presetExcludes...)
+	preset.Request.SearchSubdirs = true
+	preset.Variables = []string{"directory"}
+	return preset
+}
+
+// builtinPreset returns the built-in preset with the given ID.
+func builtinPreset(id string) (QueryTemplate, bool) {
+	if !strings.HasPrefix(id, builtinPresetPrefix) {
+		return QueryTemplate{}, false
+	}
+	for _, preset := range builtinPresets {
+		if preset.ID == id {
+			return presetTemplate(preset), true
+		}
+	}
+	return QueryTemplate{}, false
+}
+
+// ClonePreset saves a copy of a built-in preset as a template of the user's
+// own, which can then be edited with SaveTemplate. An empty name uses the
+// preset's name with " (copy)" appended.
+func (a *App) ClonePreset(presetID string, name string) (QueryTemplate, error) {
+	preset, ok := builtinPreset(presetID)
+	if !ok {
+		return QueryTemplate{}, newAppError(ErrCodeTemplateNotFound, presetID)
+	}
+	if strings.TrimSpace(name) == "" {
+		name = preset.Name + " (copy)"
+	}
+	preset.ID = ""
+	preset.Name = name
+
+	clone, err := a.SaveTemplate(preset)
+	if err != nil {
+		return QueryTemplate{}, err
+	}
+	a.logInfo("Preset cloned", logrus.Fields{"preset": presetID, "id": clone.ID})
+	return clone, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// TestBuiltinPresets verifies that every preset is a valid template with
+// only the {directory} placeholder, and that its pattern flags the smell it
+// is meant for and leaves clean code alone.
+func TestBuiltinPresets(t *testing.T) {
+	samples := map[string]struct{ hits, misses []string }{
+		builtinPresetPrefix + "hardcoded-credentials": {
+			hits:   []string{`password = "hunter22"`, `const apiKey := "sk-live-123456"`, `"client_secret": "abcd1234"`},
+			misses: []string{`password = os.Getenv("PASSWORD")`, `token = ""`},
+		},
+		builtinPresetPrefix + "todo-fixme": {
+			hits:   []string{"// TODO: handle errors", "# FIXME later"},
+			misses: []string{"todoList := nil", "mastodon"},
+		},
+		builtinPresetPrefix + "debug-leftovers": {
+			hits:   []string{`console.log("here")`, `fmt.Println(x)`, `println!("{}", x);`, "debugger;"},
+			misses: []string{`logger.log("ready")`, `log.Printf("x")`},
+		},
+		builtinPresetPrefix + "focused-tests": {
+			hits:   []string{`describe.only("suite", () => {`, `it.only("works", async () => {`, `fit("works", () => {`},
+			misses: []string{`it("works", () => {`, `profit(x)`},
+		},
+	}
+
+	presets := NewApp().ListBuiltinPresets()
+	if len(presets) != len(samples) {
+		t.Fatalf("expected %d presets, got %d", len(samples), len(presets))
+	}
+	for _, preset := range presets {
+		check := preset
+		if err := normalizeTemplate(&check); err != nil {
+			t.Errorf("%s: invalid template: %v", preset.ID, err)
+			continue
+		}
+		if !reflect.DeepEqual(check.Variables, preset.Variables) {
+			t.Errorf("%s: Variables = %v, want %v", preset.ID, preset.Variables, check.Variables)
+		}
+
+		sample, ok := samples[preset.ID]
+		if !ok {
+			t.Errorf("no samples for preset %s", preset.ID)
+			continue
+		}
+		pattern := preset.Request.Query
+		if !preset.Request.CaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		re := regexp.MustCompile(pattern)
+		for _, line := range sample.hits {
+			if !re.MatchString(line) {
+				t.Errorf("%s: expected a match in %q", preset.ID, line)
+			}
+		}
+		for _, line := range sample.misses {
+			if re.MatchString(line) {
+				t.Errorf("%s: unexpected match in %q", preset.ID, line)
+			}
+		}
+	}
+}
+
+// TestRunAndClonePreset verifies that a preset runs through RunTemplate and
+// that a clone becomes an editable saved template.
+func TestRunAndClonePreset(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\n// TODO: remove\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	id := builtinPresetPrefix + "todo-fixme"
+	results, err := app.RunTemplate(id, map[string]string{"directory": root})
+	if err != nil {
+		t.Fatalf("RunTemplate failed: %v", err)
+	}
+	if len(results) != 1 || results[0].LineNum != 3 {
+		t.Errorf("expected the TODO on line 3, got %+v", results)
+	}
+
+	clone, err := app.ClonePreset(id, "")
+	if err != nil {
+		t.Fatalf("ClonePreset failed: %v", err)
+	}
+	if clone.ID == "" || clone.ID == id || clone.Name != "TODO and FIXME comments (copy)" {
+		t.Errorf("unexpected clone %+v", clone)
+	}
+	clone.Request.Query = `\bTODO\b`
+	if _, err := app.SaveTemplate(clone); err != nil {
+		t.Errorf("expected the clone to be editable: %v", err)
+	}
+	if _, err := app.SaveTemplate(QueryTemplate{ID: id, Name: "mine", Request: SearchRequest{Query: "x"}}); err == nil {
+		t.Error("expected a built-in preset not to be editable in place")
+	}
+	if _, err := app.ClonePreset("builtin-missing", ""); err == nil || err.(*AppError).Code != ErrCodeTemplateNotFound {
+		t.Errorf("expected %s for an unknown preset, got %v", ErrCodeTemplateNotFound, err)
+	}
+}
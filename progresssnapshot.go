@@ -0,0 +1,54 @@
+package main
+
+// maxProgressSnapshots is how many searches GetSearchProgress remembers,
+// counting the one running.
+const maxProgressSnapshots = maxStoredSearches + 1
+
+// recordProgress keeps p as the latest progress of its search. Events
+// without a search ID are ignored.
+func (a *App) recordProgress(p SearchProgress) {
+	a.progressMu.Lock()
+	defer a.progressMu.Unlock()
+
+	if p.SearchID == "" {
+		return
+	}
+	if a.progressSnapshots == nil {
+		a.progressSnapshots = make(map[string]SearchProgress)
+	}
+	if _, known := a.progressSnapshots[p.SearchID]; !known {
+		a.progressOrder = append(a.progressOrder, p.SearchID)
+		if excess := len(a.progressOrder) - maxProgressSnapshots; excess > 0 {
+			for _, id := range a.progressOrder[:excess] {
+				delete(a.progressSnapshots, id)
+			}
+			a.progressOrder = append([]string(nil), a.progressOrder[excess:]...)
+		}
+		a.progressCurrent = p.SearchID
+	}
+	a.progressSnapshots[p.SearchID] = p
+}
+
+// GetSearchProgress returns the latest search-progress event of a search,
+// with its SearchID filled in, so a webview that missed events (a reload, a
+// dropped event) can resynchronize its progress bar instead of showing it
+// stuck. An empty ID returns the search that started last, which is what a
+// reloaded webview that lost the ID needs; with no search yet its Status is
+// "idle". Only the last few searches are remembered; an older ID fails with
+// SEARCH_NOT_FOUND.
+func (a *App) GetSearchProgress(searchID string) (SearchProgress, error) {
+	a.progressMu.Lock()
+	defer a.progressMu.Unlock()
+
+	if searchID == "" {
+		if a.progressCurrent == "" {
+			return SearchProgress{Status: "idle"}, nil
+		}
+		searchID = a.progressCurrent
+	}
+	p, ok := a.progressSnapshots[searchID]
+	if !ok {
+		return SearchProgress{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	return p, nil
+}
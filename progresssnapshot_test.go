@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGetSearchProgress verifies the idle state, the snapshot of a finished
+// search by ID and as the latest one, in-progress and cancelled events
+// filed under their search, events without an ID ignored, and old searches
+// forgotten.
+func TestGetSearchProgress(t *testing.T) {
+	app := NewApp()
+	if p, err := app.GetSearchProgress(""); err != nil || p.Status != "idle" {
+		t.Fatalf("expected idle before any search, got %+v, %v", p, err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("needle\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle"}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	id := app.searches[len(app.searches)-1].id
+	for _, query := range []string{id, ""} {
+		p, err := app.GetSearchProgress(query)
+		if err != nil || p.SearchID != id || p.Status != "completed" || p.ResultsCount != 1 || p.TotalFiles != 1 {
+			t.Errorf("GetSearchProgress(%q) = %+v, %v", query, p, err)
+		}
+	}
+
+	app.recordProgress(SearchProgress{SearchID: "search-running", TotalFiles: 10, Status: "started"})
+	app.recordProgress(SearchProgress{SearchID: "search-running", ProcessedFiles: 4, TotalFiles: 10, Status: "in-progress"})
+	app.recordProgress(SearchProgress{ProcessedFiles: 5, TotalFiles: 10, Status: "in-progress"})
+	if p, _ := app.GetSearchProgress(""); p.SearchID != "search-running" || p.ProcessedFiles != 4 {
+		t.Errorf("expected the running search's latest event, got %+v", p)
+	}
+	app.recordProgress(SearchProgress{SearchID: "search-running", Status: "cancelled"})
+	if p, _ := app.GetSearchProgress("search-running"); p.Status != "cancelled" {
+		t.Errorf("expected the cancelled event, got %+v", p)
+	}
+
+	for i := 0; i < maxProgressSnapshots; i++ {
+		app.recordProgress(SearchProgress{SearchID: fmt.Sprintf("search-x%d", i), Status: "started"})
+	}
+	if _, err := app.GetSearchProgress(id); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s for an old search, got %v", ErrCodeSearchNotFound, err)
+	}
+}
+
+// gatedMatcher matches every file, holding files whose path ends in
+// "/2" until release is closed.
+type gatedMatcher struct{ release chan struct{} }
+
+func (m gatedMatcher) Match(ctx context.Context, meta fileMeta, _ *regexp.Regexp, _ SearchRequest, _ *SearchState, _ *int32, _ context.CancelFunc) (string, []SearchResult) {
+	if strings.HasSuffix(meta.absPath, "/2") {
+		<-m.release
+	}
+	return meta.absPath, []SearchResult{{FilePath: meta.absPath, LineNum: 1}}
+}
+
+// TestSearchProgressOverlappingSearches runs two searches at once and
+// verifies that each one's in-progress and completed events are filed
+// under its own ID.
+func TestSearchProgressOverlappingSearches(t *testing.T) {
+	app := NewApp()
+	release := make(chan struct{})
+	searches := map[string][]fileMeta{
+		"search-a": {{absPath: "/a/1", size: 1}, {absPath: "/a/2", size: 1}},
+		"search-b": {{absPath: "/b/1", size: 1}, {absPath: "/b/2", size: 1}, {absPath: "/b/3", size: 1}},
+	}
+
+	var wg sync.WaitGroup
+	for id, files := range searches {
+		wg.Add(1)
+		go func(id string, files []fileMeta) {
+			defer wg.Done()
+			s := &searcher{collector: fakeCollector(files), matcher: gatedMatcher{release}, sink: eventSink{app}, log: nopSearchLogger{}}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if _, err := s.run(ctx, cancel, id, SearchRequest{MaxResults: 100}, regexp.MustCompile("x"), "/"); err != nil {
+				t.Errorf("search %s failed: %v", id, err)
+			}
+		}(id, files)
+	}
+
+	// Both searches are held on their second file; their in-progress
+	// events must not be filed under the other one.
+	deadline := time.Now().Add(5 * time.Second)
+	for id, files := range searches {
+		prefix := filepath.Dir(files[0].absPath) + "/"
+		for {
+			p, err := app.GetSearchProgress(id)
+			if err == nil && p.Status == "in-progress" {
+				if p.SearchID != id || !strings.HasPrefix(p.CurrentFile, prefix) || p.TotalFiles != len(files) {
+					t.Errorf("unexpected in-progress event for %s: %+v", id, p)
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("no in-progress event for %s, last %+v, %v", id, p, err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	for id, files := range searches {
+		p, err := app.GetSearchProgress(id)
+		if err != nil || p.Status != "completed" || p.ProcessedFiles != len(files) || p.ResultsCount != len(files) {
+			t.Errorf("unexpected final progress for %s: %+v, %v", id, p, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// projectMarkers are the entries that make a directory a project root for
+// OpenProjectInEditor: module and package manifests, and the repository
+// root.
+var projectMarkers = []string{
+	"go.mod",
+	"package.json",
+	"Cargo.toml",
+	"pyproject.toml",
+	"pom.xml",
+	"build.gradle",
+	".git",
+}
+
+// projectEditorArgs lists the editors that can open a directory as a
+// project, by editorBindings name, with the arguments that go before it.
+// The JetBrains IDEs, VS Code, Sublime, and the Vim family all take the
+// directory as it is.
+var projectEditorArgs = map[string][]string{
+	"VSCode":          nil,
+	"VSCodium":        nil,
+	"Sublime":         nil,
+	"Atom":            nil,
+	"GoLand":          nil,
+	"PyCharm":         nil,
+	"IntelliJ":        nil,
+	"WebStorm":        nil,
+	"PhpStorm":        nil,
+	"CLion":           nil,
+	"Rider":           nil,
+	"AndroidStudio":   nil,
+	"Emacs":           nil,
+	"Neovide":         nil,
+	"NotepadPlusPlus": {"-openFoldersAsWorkspace"},
+	"VisualStudio":    nil,
+	"NetBeans":        {"--open"},
+	"Neovim":          nil,
+	"Vim":             nil,
+	"Helix":           nil,
+}
+
+// projectRoot returns the nearest directory at or above the file's
+// directory that holds one of projectMarkers, so a Go package inside a
+// monorepo opens its module rather than the whole repository. Without a
+// marker it returns the file's directory.
+func projectRoot(filePath string) string {
+	dir := filepath.Dir(filePath)
+	for current := dir; ; {
+		for _, marker := range projectMarkers {
+			if _, err := os.Lstat(toLongPath(filepath.Join(current, marker))); err == nil {
+				return current
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// OpenProjectInEditor opens the project containing a file, rather than the
+// file alone, so an IDE starts with the whole project's context. The
+// project root is the nearest directory with a go.mod, package.json, .git,
+// or another projectMarkers entry. editorID is an editorBindings name or
+// "JetBrains" (the IDE for the file type). It returns the directory opened.
+func (a *App) OpenProjectInEditor(editorID string, filePath string) (string, error) {
+	cleanPath, err := a.validatePathForEditor(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	var command string
+	var args []string
+	if editorID == jetBrainsEditor {
+		command, _ = a.getJetBrainsEditor(cleanPath)
+	} else {
+		binding, ok := editorBindings[editorID]
+		if !ok {
+			return "", newAppError(ErrCodeUnknownEditor, editorID)
+		}
+		if args, ok = projectEditorArgs[editorID]; !ok {
+			return "", newAppError(ErrCodeEditorNoProjects, editorID)
+		}
+		command = binding.command
+	}
+
+	root := projectRoot(cleanPath)
+	command = a.resolveEditorCommand(command)
+	if err := a.lookUpEditor(command); err != nil {
+		return "", err
+	}
+	if err := startEditor(command, append(append([]string{}, args...), root)); err != nil {
+		a.logError("Failed to open project in editor", err, logrus.Fields{
+			"editor": command,
+			"root":   root,
+		})
+		return "", newAppError(ErrCodeEditorLaunchFailed, command, err)
+	}
+
+	a.logInfo("Opened project in editor", logrus.Fields{
+		"editor":   command,
+		"root":     root,
+		"filePath": cleanPath,
+	})
+	return root, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestProjectRoot verifies that the nearest marker wins, so a nested module
+// beats the repository root, and that a file outside any project falls
+// back to its own directory.
+func TestProjectRoot(t *testing.T) {
+	repo := t.TempDir()
+	module := filepath.Join(repo, "services", "api")
+	pkg := filepath.Join(module, "internal", "handler")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	docs := filepath.Join(repo, "docs")
+	if err := os.Mkdir(docs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		filepath.Join(pkg, "handler.go"): module,
+		filepath.Join(module, "main.go"): module,
+		filepath.Join(docs, "README.md"): repo,
+		filepath.Join(repo, "Makefile"):  repo,
+	}
+	for file, want := range cases {
+		if got := projectRoot(file); got != want {
+			t.Errorf("projectRoot(%q) = %q, want %q", file, got, want)
+		}
+	}
+
+	loose := t.TempDir()
+	if got := projectRoot(filepath.Join(loose, "notes.txt")); got != loose {
+		t.Errorf("expected a file outside any project to use its directory, got %q", got)
+	}
+}
+
+// TestOpenProjectInEditorErrors verifies unknown editors, editors that
+// cannot open a folder, and a missing editor binary are reported.
+func TestOpenProjectInEditorErrors(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		editor string
+		want   ErrorCode
+	}{
+		{"Kate", ErrCodeUnknownEditor},
+		{"Nano", ErrCodeEditorNoProjects},
+		{"VSCode", ErrCodeEditorNotFound},
+		{"JetBrains", ErrCodeEditorNotFound},
+	}
+	for _, c := range cases {
+		if _, err := app.OpenProjectInEditor(c.editor, file); err == nil || err.(*AppError).Code != c.want {
+			t.Errorf("OpenProjectInEditor(%s): expected %s, got %v", c.editor, c.want, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// expensiveFileCount is the tree size above which a single-character
+// literal query needs confirmation: it matches on nearly every line, so the
+// search fills MaxResults from the first files and the rest of the scan is
+// spent on matches nobody will look at.
+const expensiveFileCount = 100000
+
+// Reasons a search needs confirmation, reported in QueryCostWarning.Reasons.
+const (
+	costReasonLeadingWildcard = "LEADING_WILDCARD"
+	costReasonShortLiteral    = "SHORT_LITERAL"
+)
+
+// QueryCostWarning is the details of a CONFIRMATION_REQUIRED error: why the
+// search is expected to be expensive. The frontend shows it and re-sends
+// the request with ConfirmExpensive set if the user goes ahead.
+type QueryCostWarning struct {
+	Reasons   []string `json:"reasons"`
+	FileCount int      `json:"fileCount,omitempty"` // Files collected; 0 when the walk hasn't run yet
+}
+
+// usesRegex reports whether the query is a regular expression, which is the
+// default when UseRegex is unset.
+func usesRegex(req SearchRequest) bool {
+	return req.UseRegex == nil || *req.UseRegex
+}
+
+// hasLeadingWildcard reports whether a regex query starts with ".*" or
+// ".+", optionally after a "^" anchor. Such a pattern makes the engine try
+// every position of every line, and the matches span whole lines anyway.
+func hasLeadingWildcard(req SearchRequest) bool {
+	if !usesRegex(req) {
+		return false
+	}
+	q := strings.TrimPrefix(req.Query, "^")
+	return strings.HasPrefix(q, ".*") || strings.HasPrefix(q, ".+")
+}
+
+// isShortLiteral reports whether the query is a single literal character.
+func isShortLiteral(req SearchRequest) bool {
+	if utf8.RuneCountInString(req.Query) != 1 {
+		return false
+	}
+	return !usesRegex(req) || regexp.QuoteMeta(req.Query) == req.Query
+}
+
+// checkPatternCost rejects an unconfirmed request whose pattern alone makes
+// it expensive. It runs in validateAndSetDefaults, before any file I/O.
+func checkPatternCost(req SearchRequest) error {
+	if req.ConfirmExpensive || !hasLeadingWildcard(req) {
+		return nil
+	}
+	return newConfirmationRequired(QueryCostWarning{Reasons: []string{costReasonLeadingWildcard}})
+}
+
+// checkTreeCost rejects an unconfirmed single-character literal search over
+// more than expensiveFileCount files. It needs the file count, so it runs
+// after collection and before any file is read.
+func checkTreeCost(req SearchRequest, fileCount int) error {
+	if req.ConfirmExpensive || fileCount <= expensiveFileCount || !isShortLiteral(req) {
+		return nil
+	}
+	return newConfirmationRequired(QueryCostWarning{Reasons: []string{costReasonShortLiteral}, FileCount: fileCount})
+}
+
+// newConfirmationRequired returns the CONFIRMATION_REQUIRED error carrying
+// the warning as its details.
+func newConfirmationRequired(warning QueryCostWarning) *AppError {
+	err := newAppError(ErrCodeConfirmationRequired)
+	err.Details = warning
+	return err
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckPatternCost verifies which patterns need confirmation and that
+// ConfirmExpensive lets them through.
+func TestCheckPatternCost(t *testing.T) {
+	no := false
+	cases := []struct {
+		req  SearchRequest
+		want bool
+	}{
+		{SearchRequest{Query: ".*foo"}, true},
+		{SearchRequest{Query: "^.+bar"}, true},
+		{SearchRequest{Query: "foo.*"}, false},
+		{SearchRequest{Query: ".*foo", UseRegex: &no}, false},
+		{SearchRequest{Query: ".*foo", ConfirmExpensive: true}, false},
+	}
+	for _, tc := range cases {
+		err := checkPatternCost(tc.req)
+		if got := err != nil; got != tc.want {
+			t.Errorf("checkPatternCost(%q) error = %v, want error %v", tc.req.Query, err, tc.want)
+		}
+	}
+}
+
+// TestCheckTreeCost verifies that a single-character literal needs
+// confirmation only above the file-count threshold, and that the error
+// carries the reason and file count for the frontend.
+func TestCheckTreeCost(t *testing.T) {
+	if err := checkTreeCost(SearchRequest{Query: "x"}, expensiveFileCount); err != nil {
+		t.Errorf("expected no confirmation at the threshold, got %v", err)
+	}
+	if err := checkTreeCost(SearchRequest{Query: "xy"}, expensiveFileCount+1); err != nil {
+		t.Errorf("expected no confirmation for a longer query, got %v", err)
+	}
+	if err := checkTreeCost(SearchRequest{Query: "."}, expensiveFileCount+1); err != nil {
+		t.Errorf("expected no confirmation for a regex metacharacter, got %v", err)
+	}
+	if err := checkTreeCost(SearchRequest{Query: "x", ConfirmExpensive: true}, expensiveFileCount+1); err != nil {
+		t.Errorf("expected confirmed request to pass, got %v", err)
+	}
+
+	err := checkTreeCost(SearchRequest{Query: "é"}, expensiveFileCount+1)
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeConfirmationRequired {
+		t.Fatalf("expected CONFIRMATION_REQUIRED, got %v", err)
+	}
+	resp := NewApp().formatError(err).(ErrorResponse)
+	warning, ok := resp.Details.(QueryCostWarning)
+	if !ok || warning.FileCount != expensiveFileCount+1 || len(warning.Reasons) != 1 || warning.Reasons[0] != costReasonShortLiteral {
+		t.Errorf("unexpected error details %+v", resp.Details)
+	}
+}
+
+// TestSearchWithProgressRequiresConfirmation verifies that SearchWithProgress
+// rejects a leading-wildcard regex until the request is confirmed.
+func TestSearchWithProgressRequiresConfirmation(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("some needle here\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	req := SearchRequest{Directory: tempDir, Query: ".*needle", SearchSubdirs: true}
+	_, err := app.SearchWithProgress(req)
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeConfirmationRequired {
+		t.Fatalf("expected CONFIRMATION_REQUIRED, got %v", err)
+	}
+
+	req.ConfirmExpensive = true
+	results, err := app.SearchWithProgress(req)
+	if err != nil {
+		t.Fatalf("confirmed search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
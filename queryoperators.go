@@ -0,0 +1,182 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Inline query operators let the query carry filters that otherwise take
+// form fields, in the style of search engines and browser dev tools:
+//
+//	TODO ext:go -path:vendor/ size:<1mb case:yes
+//
+// Operators are whitespace-separated tokens; the rest of the query is
+// searched as usual. A token written with a leading backslash (\ext:go) is
+// searched literally, without the backslash.
+
+// queryOperator matches an operator token and the whitespace before it.
+// Values may be double-quoted to include spaces.
+var queryOperator = regexp.MustCompile(`(^|\s)(-?)(ext|path|case|size):("[^"]*"|\S+)`)
+
+// escapedQueryOperator matches an operator token escaped with a backslash.
+var escapedQueryOperator = regexp.MustCompile(`(^|\s)\\(-?(?:ext|path|case|size):)`)
+
+// querySizeBound matches the value of a size: operator: a comparison and
+// a size with an optional unit.
+var querySizeBound = regexp.MustCompile(`^(<=|>=|<|>)([0-9]+(?:\.[0-9]+)?)([kmg]?b?)$`)
+
+// querySizeUnits are the multipliers of the size units, which are binary
+// like the rest of the app's sizes.
+var querySizeUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30,
+}
+
+// applyQueryOperators removes the operators from the query and merges
+// them into the request:
+//
+//   - ext:go,ts adds to AllowedFileTypes; -ext:log adds "*.log" to
+//     ExcludePatterns;
+//   - path:src/ adds to IncludePaths and -path:vendor/ to ExcludePaths;
+//   - case:yes or case:no sets CaseSensitive;
+//   - size:<1mb lowers MaxFileSize and size:>10kb raises MinFileSize,
+//     with <= and >= for inclusive bounds. A minimum above the 10MB
+//     default MaxFileSize lifts that default, so size:>20mb finds every
+//     file over 20MB unless the query also has a size:< bound; a minimum
+//     above a maximum the request or query set explicitly fails.
+//
+// Operators narrow what the request's fields already select, except
+// case:, which overrides CaseSensitive. An unknown value fails with
+// QUERY_OPERATOR_INVALID.
+func applyQueryOperators(req SearchRequest) (SearchRequest, error) {
+	var opErr error
+	var sizeToken string // The last size: operator, for the bounds check
+	maxFromQuery := false
+	query := queryOperator.ReplaceAllStringFunc(req.Query, func(token string) string {
+		if opErr != nil {
+			return token
+		}
+		m := queryOperator.FindStringSubmatch(token)
+		negated, name, value := m[2] == "-", m[3], m[4]
+		if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+		invalid := func(reason string) string {
+			opErr = newAppError(ErrCodeQueryOperatorInvalid, strings.TrimSpace(token), reason)
+			return token
+		}
+		if value == "" {
+			return invalid("the value is empty")
+		}
+
+		switch name {
+		case "ext":
+			for _, ext := range strings.Split(value, ",") {
+				ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+				if ext == "" {
+					continue
+				}
+				if negated && strings.HasPrefix(ext, fileTypeBundlePrefix) {
+					return invalid("file type bundles can't be negated")
+				}
+				if negated {
+					req.ExcludePatterns = append(req.ExcludePatterns, "*."+ext)
+				} else {
+					req.AllowedFileTypes = append(req.AllowedFileTypes, ext)
+				}
+			}
+		case "path":
+			value = filepath.ToSlash(value)
+			if negated {
+				req.ExcludePaths = append(req.ExcludePaths, value)
+			} else {
+				req.IncludePaths = append(req.IncludePaths, value)
+			}
+		case "case":
+			if negated {
+				return invalid("case: can't be negated")
+			}
+			switch strings.ToLower(value) {
+			case "yes", "true", "on":
+				req.CaseSensitive = true
+			case "no", "false", "off":
+				req.CaseSensitive = false
+			default:
+				return invalid("use case:yes or case:no")
+			}
+		case "size":
+			if negated {
+				return invalid("size: can't be negated")
+			}
+			m := querySizeBound.FindStringSubmatch(strings.ToLower(value))
+			if m == nil {
+				return invalid("use a bound such as size:<1mb or size:>=10kb")
+			}
+			sizeToken = strings.TrimSpace(token)
+			n, _ := strconv.ParseFloat(m[2], 64)
+			bytes := int64(math.Round(n * querySizeUnits[m[3]]))
+			switch m[1] {
+			case "<":
+				bytes--
+				fallthrough
+			case "<=":
+				if bytes < 0 {
+					return invalid("no file is that small")
+				}
+				if req.MaxFileSize == 0 || bytes < req.MaxFileSize {
+					req.MaxFileSize = bytes
+				}
+				maxFromQuery = true
+			case ">":
+				bytes++
+				fallthrough
+			case ">=":
+				req.MinFileSize = max(req.MinFileSize, bytes)
+			}
+		}
+		// Keep the whitespace before the operator out of the query.
+		return ""
+	})
+	if opErr != nil {
+		return req, opErr
+	}
+	if sizeToken != "" {
+		defaultMax := !maxFromQuery && (req.MaxFileSize == 0 || req.MaxFileSize == defaultMaxFileSize)
+		if defaultMax && req.MinFileSize > defaultMaxFileSize {
+			req.MaxFileSize = math.MaxInt64
+		}
+		if req.MaxFileSize > 0 && req.MinFileSize > req.MaxFileSize {
+			return req, newAppError(ErrCodeQueryOperatorInvalid, sizeToken, "no file is larger than the minimum size and smaller than the maximum")
+		}
+	}
+	req.Query = strings.TrimSpace(escapedQueryOperator.ReplaceAllString(query, "$1$2"))
+	return req, nil
+}
+
+// pathFiltersMatch applies IncludePaths and ExcludePaths to a path
+// relative to the search directory: it must contain one of the include
+// paths, if there are any, and none of the exclude paths. Paths compare
+// with forward slashes, and a leading slash anchors a filter to the start
+// of the relative path.
+func pathFiltersMatch(req SearchRequest, rel string) bool {
+	rel = "/" + filepath.ToSlash(rel)
+	for _, p := range req.ExcludePaths {
+		if p != "" && strings.Contains(rel, p) {
+			return false
+		}
+	}
+	if len(req.IncludePaths) == 0 {
+		return true
+	}
+	for _, p := range req.IncludePaths {
+		if strings.Contains(rel, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestApplyQueryOperators verifies how each operator is taken out of the
+// query and merged with the request's own fields.
+func TestApplyQueryOperators(t *testing.T) {
+	got, err := applyQueryOperators(SearchRequest{
+		Query:            `ext:go,.TS  TODO -ext:log path:"my src/" -path:vendor/ case:YES size:<1mb size:>=2kb \path:keep`,
+		AllowedFileTypes: []string{"md"},
+		ExcludePatterns:  []string{"node_modules"},
+		MaxFileSize:      512 * 1024,
+	})
+	if err != nil {
+		t.Fatalf("applyQueryOperators failed: %v", err)
+	}
+	want := SearchRequest{
+		Query:            "TODO path:keep",
+		AllowedFileTypes: []string{"md", "go", "TS"},
+		ExcludePatterns:  []string{"node_modules", "*.log"},
+		IncludePaths:     []string{"my src/"},
+		ExcludePaths:     []string{"vendor/"},
+		CaseSensitive:    true,
+		MaxFileSize:      512 * 1024,
+		MinFileSize:      2048,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	bounds := map[string][2]int64{
+		"size:<1mb":    {1<<20 - 1, 0},
+		"size:<=1.5k":  {1536, 0},
+		"size:>10kb":   {0, 10241},
+		"size:>=1GB":   {math.MaxInt64, 1 << 30},
+		"case:no x":    {0, 0},
+		"plain: query": {0, 0},
+	}
+	for query, want := range bounds {
+		got, err := applyQueryOperators(SearchRequest{Query: query, CaseSensitive: true})
+		if err != nil || got.MaxFileSize != want[0] || got.MinFileSize != want[1] {
+			t.Errorf("%s: got max %d, min %d, %v; want %v", query, got.MaxFileSize, got.MinFileSize, err, want)
+		}
+		if strings.HasPrefix(query, "case:") && got.CaseSensitive {
+			t.Errorf("%s: expected case:no to override CaseSensitive", query)
+		}
+	}
+
+	// A minimum above the default maximum lifts it; one above a maximum
+	// that was set explicitly can't be met.
+	for _, max := range []int64{0, defaultMaxFileSize} {
+		got, err := applyQueryOperators(SearchRequest{Query: "foo size:>20mb", MaxFileSize: max})
+		if err != nil || got.MinFileSize != 20<<20+1 || got.MaxFileSize < got.MinFileSize {
+			t.Errorf("size:>20mb with max %d: got max %d, min %d, %v", max, got.MaxFileSize, got.MinFileSize, err)
+		}
+	}
+	if got, err := applyQueryOperators(SearchRequest{Query: "foo size:>2mb"}); err != nil || got.MaxFileSize != 0 {
+		t.Errorf("size:>2mb: expected the default maximum kept, got max %d, %v", got.MaxFileSize, err)
+	}
+	if got, err := applyQueryOperators(SearchRequest{Query: "foo size:>20mb size:<30mb"}); err != nil || got.MaxFileSize != 30<<20-1 {
+		t.Errorf("size:>20mb size:<30mb: got max %d, %v", got.MaxFileSize, err)
+	}
+	for _, req := range []SearchRequest{
+		{Query: "foo size:>20mb", MaxFileSize: 5 << 20},
+		{Query: "foo size:>2mb size:<1mb"},
+		{Query: "foo size:<1mb", MinFileSize: 2 << 20},
+	} {
+		if _, err := applyQueryOperators(req); err == nil || err.(*AppError).Code != ErrCodeQueryOperatorInvalid {
+			t.Errorf("%q with max %d, min %d: expected %s, got %v", req.Query, req.MaxFileSize, req.MinFileSize, ErrCodeQueryOperatorInvalid, err)
+		}
+	}
+
+	for _, query := range []string{"x case:maybe", "x -case:yes", "x size:1mb", "x size:<0", "x size:<1tb", `x path:""`, "x -ext:@web"} {
+		if _, err := applyQueryOperators(SearchRequest{Query: query}); err == nil || err.(*AppError).Code != ErrCodeQueryOperatorInvalid {
+			t.Errorf("%q: expected %s, got %v", query, ErrCodeQueryOperatorInvalid, err)
+		}
+	}
+}
+
+// TestQueryOperatorsSearch verifies that operators in a search's query
+// filter the files searched and are not searched for themselves.
+func TestQueryOperatorsSearch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/main.go":         "// Needle in main\n",
+		"src/lower.go":        "// needle in lower case\n",
+		"src/big.go":          "// Needle\n" + strings.Repeat("x", 4096),
+		"src/notes.md":        "Needle in markdown\n",
+		"vendor/lib/lib.go":   "// Needle in vendor\n",
+		"tools/gen/gen.go":    "// Needle in tools\n",
+		"src/vendored/ok.go":  "// Needle in vendored\n",
+		"src/sub/deep/app.go": "// Needle deep\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	useRegex := false
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:     dir,
+		Query:         "Needle ext:go path:src/ -path:/vendor/ case:yes size:<1kb",
+		SearchSubdirs: true,
+		UseRegex:      &useRegex,
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		rel, _ := filepath.Rel(dir, r.FilePath)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := map[string]bool{"src/main.go": true, "src/vendored/ok.go": true, "src/sub/deep/app.go": true}
+	if len(got) != len(want) {
+		t.Fatalf("expected matches in %v, got %v", want, got)
+	}
+	for _, rel := range got {
+		if !want[rel] {
+			t.Errorf("unexpected match in %s", rel)
+		}
+	}
+}
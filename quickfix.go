@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultQuickfixFileName is the file in the temp directory that
+// ExportResultsAsQuickfix writes when no path is given.
+const defaultQuickfixFileName = "code-search-quickfix.txt"
+
+// writeQuickfix writes results in the "file:line: text" form that Vim's
+// default errorformat, Emacs grep-mode, and a VS Code problem matcher with
+// the pattern ^(.*):(\d+): (.*)$ all understand.
+func writeQuickfix(w io.Writer, results []SearchResult) error {
+	bw := bufio.NewWriter(w)
+	for _, r := range results {
+		text := strings.Map(func(c rune) rune {
+			if c == '\r' || c == '\n' {
+				return ' '
+			}
+			return c
+		}, r.Content)
+		if _, err := fmt.Fprintf(bw, "%s:%d: %s\n", r.FilePath, r.LineNum, text); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ExportResultsAsQuickfix writes the results of a completed search to path
+// as a quickfix list, one "file:line: text" line per result, and returns the
+// path written. An empty path writes code-search-quickfix.txt in the temp
+// directory; any other path must be absolute. The file is remembered for
+// OpenQuickfixInEditor.
+func (a *App) ExportResultsAsQuickfix(searchID string, path string) (string, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return "", newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	if path == "" {
+		path = filepath.Join(os.TempDir(), defaultQuickfixFileName)
+	}
+	if !filepath.IsAbs(path) {
+		return "", newAppError(ErrCodeResultsExportFailed, path, errors.New("path must be absolute"))
+	}
+
+	snap := snapshotForUndo(path)
+	file, err := os.Create(toLongPath(path))
+	if err != nil {
+		return "", newAppError(ErrCodeResultsExportFailed, path, err)
+	}
+	writeErr := writeQuickfix(file, rec.results)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		return "", newAppError(ErrCodeResultsExportFailed, path, errors.Join(writeErr, closeErr))
+	}
+
+	a.recordUndo(snap, undoKindExport, "Export quickfix list")
+	a.quickfixMu.Lock()
+	a.quickfixPath = path
+	a.quickfixMu.Unlock()
+	a.logInfo("Search results exported as quickfix list", logrus.Fields{
+		"searchId":     searchID,
+		"outputPath":   path,
+		"resultsCount": len(rec.results),
+	})
+	return path, nil
+}
+
+// quickfixArgs returns the arguments that make an editor load a quickfix
+// file and jump to its first entry, for the editors that can.
+func quickfixArgs(editorID string, path string) ([]string, bool) {
+	switch editorID {
+	case "Vim", "Neovim":
+		return []string{"-q", path}, true
+	case "Neovide":
+		return []string{"--", "-q", path}, true
+	case "Emacs":
+		return []string{"--eval", "(progn (find-file " + strconv.Quote(path) + ") (grep-mode))"}, true
+	}
+	return nil, false
+}
+
+// OpenQuickfixInEditor opens the last list written by
+// ExportResultsAsQuickfix in an editor with native quickfix navigation:
+// Vim or Neovim (:cnext), Neovide, or Emacs (grep-mode, next-error).
+// editorID is an editorBindings name.
+func (a *App) OpenQuickfixInEditor(editorID string) error {
+	binding, ok := editorBindings[editorID]
+	if !ok {
+		return newAppError(ErrCodeUnknownEditor, editorID)
+	}
+	a.quickfixMu.Lock()
+	path := a.quickfixPath
+	a.quickfixMu.Unlock()
+	if path == "" {
+		return newAppError(ErrCodeNoQuickfix)
+	}
+	args, ok := quickfixArgs(editorID, path)
+	if !ok {
+		return newAppError(ErrCodeQuickfixUnsupported, editorID)
+	}
+	if _, err := os.Stat(toLongPath(path)); err != nil {
+		return newAppError(ErrCodeFileNotFound, path)
+	}
+	command := a.resolveEditorCommand(binding.command)
+	if err := a.lookUpEditor(command); err != nil {
+		return err
+	}
+
+	if err := startEditor(command, args); err != nil {
+		a.logError("Failed to open quickfix list in editor", err, logrus.Fields{
+			"editor": command,
+			"args":   args,
+		})
+		return newAppError(ErrCodeEditorLaunchFailed, command, err)
+	}
+	a.logDebug("Opened quickfix list in editor", logrus.Fields{"editor": command, "path": path})
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestExportResultsAsQuickfix verifies the quickfix line format, the
+// absolute-path requirement, and that the file is remembered for
+// OpenQuickfixInEditor.
+func TestExportResultsAsQuickfix(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, results: []SearchResult{
+		{FilePath: "/src/main.go", LineNum: 12, Content: "func main() {"},
+		{FilePath: "/src/util.go", LineNum: 3, Content: "x := \"a\rb\""},
+	}})
+
+	if err := app.OpenQuickfixInEditor("Vim"); err == nil || err.(*AppError).Code != ErrCodeNoQuickfix {
+		t.Errorf("expected %s before any export, got %v", ErrCodeNoQuickfix, err)
+	}
+
+	out := filepath.Join(t.TempDir(), "results.qf")
+	written, err := app.ExportResultsAsQuickfix(id, out)
+	if err != nil || written != out {
+		t.Fatalf("ExportResultsAsQuickfix = %q, %v", written, err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "/src/main.go:12: func main() {\n/src/util.go:3: x := \"a b\"\n"
+	if string(data) != want {
+		t.Errorf("quickfix file = %q, want %q", data, want)
+	}
+	if app.quickfixPath != out {
+		t.Errorf("expected the export to be remembered, got %q", app.quickfixPath)
+	}
+
+	if _, err := app.ExportResultsAsQuickfix(id, "relative.qf"); err == nil || err.(*AppError).Code != ErrCodeResultsExportFailed {
+		t.Errorf("expected %s for a relative path, got %v", ErrCodeResultsExportFailed, err)
+	}
+	if _, err := app.ExportResultsAsQuickfix("search-404", out); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeSearchNotFound, err)
+	}
+	if err := app.OpenQuickfixInEditor("Sublime"); err == nil || err.(*AppError).Code != ErrCodeQuickfixUnsupported {
+		t.Errorf("expected %s, got %v", ErrCodeQuickfixUnsupported, err)
+	}
+}
+
+// TestQuickfixArgs verifies the arguments each supported editor gets.
+func TestQuickfixArgs(t *testing.T) {
+	tests := map[string][]string{
+		"Vim":     {"-q", "/tmp/r.qf"},
+		"Neovim":  {"-q", "/tmp/r.qf"},
+		"Neovide": {"--", "-q", "/tmp/r.qf"},
+		"Emacs":   {"--eval", `(progn (find-file "/tmp/r.qf") (grep-mode))`},
+	}
+	for editor, want := range tests {
+		if got, ok := quickfixArgs(editor, "/tmp/r.qf"); !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("quickfixArgs(%s) = %q, want %q", editor, got, want)
+		}
+	}
+	if _, ok := quickfixArgs("VSCode", "/tmp/r.qf"); ok {
+		t.Error("expected VSCode to have no quickfix arguments")
+	}
+}
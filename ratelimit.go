@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Request budget of the local HTTP endpoints: plenty for a Prometheus scrape
+// or a person with a browser, while a client polling in a tight loop gets
+// 429s instead of the CPU.
+const (
+	localEndpointRate  = 10 // Requests per second, sustained
+	localEndpointBurst = 20
+)
+
+// tokenBucket is a token-bucket rate limiter: it holds up to burst tokens,
+// refills rate of them per second, and each request takes one.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time // time.Now, replaced in tests
+}
+
+// newTokenBucket returns a full bucket.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now(), now: time.Now}
+}
+
+// take takes a token if one is left. Otherwise it reports how long until
+// the next one.
+func (b *tokenBucket) take() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// rateLimitHandler serves next within the budget of bucket, and answers
+// 429 Too Many Requests with a Retry-After header, in whole seconds, beyond
+// it. Every local HTTP route goes through one.
+func rateLimitHandler(next http.Handler, bucket *tokenBucket) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := bucket.take(); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestTokenBucket verifies the burst, the refill rate, and the wait
+// reported when the bucket is empty.
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(2, 3)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	for i := range 3 {
+		if ok, _ := b.take(); !ok {
+			t.Fatalf("request %d of the burst was refused", i+1)
+		}
+	}
+	ok, wait := b.take()
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("expected a refusal with a 500ms wait, got %v, %v", ok, wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := b.take(); !ok {
+		t.Error("expected a token refilled after 500ms")
+	}
+	now = now.Add(time.Hour)
+	for i := range 3 {
+		if ok, _ := b.take(); !ok {
+			t.Fatalf("request %d after a long pause was refused", i+1)
+		}
+	}
+	if ok, _ := b.take(); ok {
+		t.Error("expected the refill capped at the burst")
+	}
+}
+
+// TestRateLimitHandler verifies that requests beyond the budget get 429
+// with Retry-After, without reaching the handler.
+func TestRateLimitHandler(t *testing.T) {
+	served := 0
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { served++ })
+	handler := rateLimitHandler(next, newTokenBucket(0.25, 2))
+
+	var codes []int
+	var retryAfter string
+	for range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		codes = append(codes, rec.Code)
+		retryAfter = rec.Header().Get("Retry-After")
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("expected 200, 200, 429, got %v", codes)
+	}
+	if retryAfter != "4" {
+		t.Errorf("expected Retry-After: 4, got %q", retryAfter)
+	}
+	if served != 2 {
+		t.Errorf("expected 2 requests served, got %d", served)
+	}
+}
+
+// TestMetricsHandlerRateLimited verifies that the metrics endpoint answers
+// a tight polling loop with 429s.
+func TestMetricsHandlerRateLimited(t *testing.T) {
+	handler := metricsHandler()
+	limited := 0
+	for range localEndpointBurst + 5 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if rec.Code == http.StatusTooManyRequests {
+			limited++
+		}
+	}
+	if limited == 0 {
+		t.Errorf("expected requests beyond the burst of %d to be limited", localEndpointBurst)
+	}
+}
@@ -28,7 +28,8 @@ func TestReadFile(t *testing.T) {
 	
 	t.Run("ReadExistingFile", func(t *testing.T) {
 		filePath := filepath.Join(tempDir, "test1.go")
-		content, err := app.ReadFile(filePath)
+		contentFile, err := app.ReadFile(filePath)
+		content := contentFile.Content
 		
 		if err != nil {
 			t.Fatalf("ReadFile returned error: %v", err)
@@ -72,7 +73,8 @@ func TestReadFile(t *testing.T) {
 	t.Run("ReadDifferentFileTypes", func(t *testing.T) {
 		// Test reading JavaScript file
 		jsFile := filepath.Join(tempDir, "test2.js")
-		jsContent, err := app.ReadFile(jsFile)
+		jsContentFile, err := app.ReadFile(jsFile)
+		jsContent := jsContentFile.Content
 		
 		if err != nil {
 			t.Fatalf("ReadFile returned error for JS file: %v", err)
@@ -89,7 +91,8 @@ func TestReadFile(t *testing.T) {
 		
 		// Test reading text file
 		txtFile := filepath.Join(tempDir, "test3.txt")
-		txtContent, err := app.ReadFile(txtFile)
+		txtContentFile, err := app.ReadFile(txtFile)
+		txtContent := txtContentFile.Content
 		
 		if err != nil {
 			t.Fatalf("ReadFile returned error for TXT file: %v", err)
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// isIdentifierRune reports whether r can be part of an identifier, so a
+// match next to one is inside a longer name and not a whole word.
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wholeWordMatches returns the matches of pattern in content that aren't
+// part of a longer identifier. Go's \b only knows ASCII word characters,
+// so the boundaries are checked here instead.
+func wholeWordMatches(pattern *regexp.Regexp, content []byte) [][]int {
+	var matches [][]int
+	for _, m := range pattern.FindAllIndex(content, -1) {
+		if before, _ := utf8.DecodeLastRune(content[:m[0]]); m[0] > 0 && isIdentifierRune(before) {
+			continue
+		}
+		if after, _ := utf8.DecodeRune(content[m[1]:]); m[1] < len(content) && isIdentifierRune(after) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// validateRename checks that oldName and newName are single identifiers
+// that differ by more than case and naming style, which case-preserving
+// replace would undo.
+func validateRename(oldName, newName string) error {
+	for _, name := range []string{oldName, newName} {
+		if name == "" || identifierRun.FindString(name) != name {
+			return newAppError(ErrCodeRenameNameInvalid, name)
+		}
+	}
+	if strings.EqualFold(strings.Join(splitIdentifier(oldName), ""), strings.Join(splitIdentifier(newName), "")) {
+		return newAppError(ErrCodeRenameUnchanged, oldName, newName)
+	}
+	return nil
+}
+
+// RenameIdentifier previews renaming an identifier across every file a
+// search of root would read, as a unified diff to review and apply with
+// git apply from the returned Root, like CreateReplacePatch; no file is
+// changed. oldName is matched as a whole word in any naming convention
+// (see expandIdentifierQuery), and each occurrence is recased like the
+// text it replaces (see matchCase), so renaming getUser to fetchAccount
+// also turns get_user into fetch_account and GET_USER into FETCH_ACCOUNT.
+// Occurrences inside comments and string literals are left alone in the
+// languages maskNonCode knows and counted as Skipped; in other files every
+// occurrence is renamed. UTF-16 files and files handled by plugins are not
+// renamed. Names that aren't identifiers fail with RENAME_NAME_INVALID,
+// and names that differ only in case or naming style with RENAME_UNCHANGED.
+func (a *App) RenameIdentifier(root, oldName, newName string) (RenamePreview, error) {
+	if err := validateRename(oldName, newName); err != nil {
+		return RenamePreview{}, err
+	}
+	dir, err := resolveDirectory(root)
+	if err != nil {
+		return RenamePreview{}, err
+	}
+	req, err := a.validateAndSetDefaults(SearchRequest{Directory: dir, Query: oldName, SearchSubdirs: true})
+	if err != nil {
+		return RenamePreview{}, err
+	}
+	files, err := a.collectFilesToProcess(req, nil, filepath.Clean(dir)+string(filepath.Separator))
+	if err != nil {
+		a.logError("Failed to collect files to process", err, logrus.Fields{"directory": dir})
+		return RenamePreview{}, err
+	}
+	pattern := regexp.MustCompile(expandIdentifierQuery(oldName))
+
+	// The walk order depends on the file system; sort for a stable patch.
+	sort.Slice(files, func(i, j int) bool { return files[i].absPath < files[j].absPath })
+	builder := newPatchBuilder(dir)
+	preview := RenamePreview{Root: builder.base, Occurrences: []RenameOccurrence{}}
+	for _, meta := range files {
+		path := meta.absPath
+		if a.pluginFor(path) != nil {
+			continue
+		}
+		content, err := os.ReadFile(toLongPath(path))
+		if err != nil {
+			a.logDebug("Skipping unreadable file while renaming", logrus.Fields{"filePath": path, "error": err.Error()})
+			continue
+		}
+		if !pattern.Match(content) || a.isBinary(content[:min(len(content), binarySampleSize)]) {
+			continue
+		}
+		if _, enc := decodeText(content); isUTF16(enc) {
+			continue
+		}
+		occurrences, skipped, err := a.renameInFile(builder, path, content, pattern, newName)
+		if err != nil {
+			return RenamePreview{}, err
+		}
+		preview.Occurrences = append(preview.Occurrences, occurrences...)
+		preview.Skipped += skipped
+	}
+	preview.Files, preview.DirtyFiles = builder.files, builder.dirtyFiles
+	if preview.Path, err = builder.write("code-search-rename-*.patch"); err != nil || preview.Path == "" {
+		return preview, err
+	}
+	a.logInfo("Rename patch written", logrus.Fields{
+		"oldName":     oldName,
+		"newName":     newName,
+		"outputPath":  preview.Path,
+		"files":       preview.Files,
+		"occurrences": len(preview.Occurrences),
+		"skipped":     preview.Skipped,
+	})
+	return preview, nil
+}
+
+// renameInFile adds the renamed lines of one file to builder and returns
+// its occurrences, and how many matches it left alone in comments and
+// strings.
+func (a *App) renameInFile(builder *patchBuilder, path string, content []byte, pattern *regexp.Regexp, newName string) ([]RenameOccurrence, int, error) {
+	masked, _ := maskNonCode(content, filepath.Ext(path))
+	matches := wholeWordMatches(pattern, masked)
+	skipped := len(wholeWordMatches(pattern, content)) - len(matches)
+	if len(matches) == 0 {
+		return nil, skipped, nil
+	}
+
+	lines := splitPatchLines(content)
+	eol := lineBreak(detectLineEnding(content))
+	replaced := make(map[int][]patchLine)
+	var occurrences []RenameOccurrence
+	// Identifiers don't span lines, so each match falls within one line.
+	lineStart, lineIdx := 0, 0
+	var b strings.Builder
+	last := 0
+	flush := func() {
+		if b.Len() == 0 {
+			return
+		}
+		line := lines[lineIdx]
+		b.WriteString(line.text[last:])
+		replaced[lineIdx] = replacementLines(line, b.String(), eol)
+		b.Reset()
+	}
+	for _, m := range matches {
+		for m[0] >= lineStart+len(lines[lineIdx].text)+len(lines[lineIdx].newline) {
+			flush()
+			lineStart += len(lines[lineIdx].text) + len(lines[lineIdx].newline)
+			lineIdx++
+			last = 0
+		}
+		line := lines[lineIdx].text
+		from, to := m[0]-lineStart, m[1]-lineStart
+		old := line[from:to]
+		renamed := matchCase(old, newName)
+		b.WriteString(line[last:from])
+		b.WriteString(renamed)
+		last = to
+		occurrences = append(occurrences, RenameOccurrence{FilePath: path, LineNum: lineIdx + 1, Old: old, New: renamed})
+	}
+	flush()
+
+	for i := range occurrences {
+		occurrences[i].Content = strings.TrimSpace(replaced[occurrences[i].LineNum-1][0].text)
+	}
+	if err := builder.addFile(path, lines, replaced); err != nil {
+		return nil, 0, err
+	}
+	return occurrences, skipped, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRenameIdentifier verifies that a rename matches whole identifiers in
+// every naming convention, recases each one, skips comments and strings in
+// known languages, and produces a patch that git apply accepts.
+func TestRenameIdentifier(t *testing.T) {
+	root, mainFile := initGitRepo(t, "")
+	mainContent := "package main\n\n// getUser loads a user.\nfunc getUser() {}\n\nfunc main() {\n" +
+		"\tGetUser := getUser\n\tprintln(\"getUser\", GetUser, getUserName)\n}\n"
+	confFile := filepath.Join(root, "src", "conf.yaml")
+	confContent := "get_user: true # get-user\nGET_USER: 1\n"
+	for path, content := range map[string]string{mainFile: mainContent, confFile: confContent} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	preview, err := app.RenameIdentifier(root, "getUser", "fetchAccount")
+	if err != nil {
+		t.Fatalf("RenameIdentifier failed: %v", err)
+	}
+	defer os.Remove(preview.Path)
+	if name := filepath.Base(preview.Path); filepath.Dir(preview.Path) != filepath.Clean(os.TempDir()) || strings.Contains(name, "getUser") {
+		t.Errorf("expected a temp file not named after the identifiers, got %s", preview.Path)
+	}
+	if preview.Files != 2 || len(preview.Occurrences) != 6 || preview.Skipped != 3 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if first := preview.Occurrences[0]; first.FilePath != confFile || first.Old != "get_user" || first.New != "fetch_account" || first.Content != "fetch_account: true # get-user" {
+		t.Errorf("unexpected first occurrence %+v", first)
+	}
+
+	text, _ := os.ReadFile(preview.Path)
+	cmd := exec.Command("git", "-C", preview.Root, "apply", preview.Path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply: %v\n%s\n%s", err, out, text)
+	}
+	wantMain := "package main\n\n// getUser loads a user.\nfunc fetchAccount() {}\n\nfunc main() {\n" +
+		"\tFetchAccount := fetchAccount\n\tprintln(\"getUser\", FetchAccount, getUserName)\n}\n"
+	wantConf := "fetch_account: true # get-user\nFETCH_ACCOUNT: 1\n"
+	for path, want := range map[string]string{mainFile: wantMain, confFile: wantConf} {
+		if got, _ := os.ReadFile(path); string(got) != want {
+			t.Errorf("%s after git apply:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+		}
+	}
+
+	preview, err = app.RenameIdentifier(root, "missingName", "otherName")
+	if err != nil || preview.Path != "" || len(preview.Occurrences) != 0 {
+		t.Errorf("expected an empty preview, got %+v, %v", preview, err)
+	}
+}
+
+// TestRenameIdentifierInvalid verifies the name checks.
+func TestRenameIdentifierInvalid(t *testing.T) {
+	app := NewApp()
+	dir := t.TempDir()
+	tests := []struct {
+		oldName, newName string
+		code             ErrorCode
+	}{
+		{"", "x", ErrCodeRenameNameInvalid},
+		{"get user", "x", ErrCodeRenameNameInvalid},
+		{"x", "a.b", ErrCodeRenameNameInvalid},
+		{"getUser", "get_user", ErrCodeRenameUnchanged},
+		{"user", "USER", ErrCodeRenameUnchanged},
+	}
+	for _, tt := range tests {
+		_, err := app.RenameIdentifier(dir, tt.oldName, tt.newName)
+		if appErr, ok := err.(*AppError); !ok || appErr.Code != tt.code {
+			t.Errorf("RenameIdentifier(%q, %q) error = %v, want %s", tt.oldName, tt.newName, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// replacePatchContext is the number of unchanged lines around each hunk,
+// the default of diff -u and git diff.
+const replacePatchContext = 3
+
+// patchLine is one line of a file being patched, split after its line
+// separator (see lineSeparator). The last line of a file may have no
+// newline.
+type patchLine struct {
+	text    string // Without the line ending
+	newline string // "\n", "\r\n", "\r", or "" for a last line without one
+}
+
+// splitPatchLines splits content into lines, as the search numbers them,
+// keeping each line's ending.
+func splitPatchLines(content []byte) []patchLine {
+	sep := lineSeparator(content)
+	var lines []patchLine
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, sep)
+		if i < 0 {
+			lines = append(lines, patchLine{text: string(content)})
+			break
+		}
+		line := patchLine{text: string(content[:i]), newline: string(sep)}
+		if sep == '\n' && strings.HasSuffix(line.text, "\r") {
+			line.text, line.newline = line.text[:len(line.text)-1], "\r\n"
+		}
+		lines = append(lines, line)
+		content = content[i+1:]
+	}
+	return lines
+}
+
+// joinCRLines turns the lines of a file whose lines end with lone CRs
+// into the single line without a newline that git and patch see, with the
+// replaced lines applied, so the diff applies with git apply. Other files
+// are returned as they are.
+func joinCRLines(lines []patchLine, replaced map[int][]patchLine) ([]patchLine, map[int][]patchLine) {
+	if len(lines) == 0 || lines[0].newline != "\r" {
+		return lines, replaced
+	}
+	var old, updated strings.Builder
+	for i, line := range lines {
+		old.WriteString(line.text + line.newline)
+		repl, ok := replaced[i]
+		if !ok {
+			repl = []patchLine{line}
+		}
+		for _, r := range repl {
+			updated.WriteString(r.text + r.newline)
+		}
+	}
+	return []patchLine{{text: old.String()}}, map[int][]patchLine{0: {{text: updated.String()}}}
+}
+
+// restoreBOM puts the UTF-8 byte order mark that decodeText removed back
+// at the start of the first line, and of its replacement, so the hunk
+// matches the file and the patched file keeps its BOM.
+func restoreBOM(lines []patchLine, replaced map[int][]patchLine) {
+	if len(lines) == 0 {
+		return
+	}
+	lines[0].text = string(bomUTF8) + lines[0].text
+	if repl := replaced[0]; len(repl) > 0 {
+		repl[0].text = string(bomUTF8) + repl[0].text
+	}
+}
+
+// writeHunkLine writes a line of a hunk with its prefix, followed by the
+// "\ No newline at end of file" marker when it has no line ending.
+func writeHunkLine(buf *bytes.Buffer, prefix byte, line patchLine) {
+	buf.WriteByte(prefix)
+	buf.WriteString(line.text)
+	if line.newline == "" {
+		buf.WriteString("\n\\ No newline at end of file\n")
+		return
+	}
+	buf.WriteString(line.newline)
+}
+
+// writeFileDiff appends the unified diff of one file to buf. replaced maps
+// the 0-based index of each changed line to the lines replacing it; every
+// replacement keeps the original line ending, except that a replacement
+// of the last line keeps its missing newline.
+func writeFileDiff(buf *bytes.Buffer, rel string, lines []patchLine, replaced map[int][]patchLine, changed []int) {
+	fmt.Fprintf(buf, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", rel, rel, rel, rel)
+
+	// shift is how many more lines the new file has before the current hunk.
+	shift := 0
+	for start := 0; start < len(changed); {
+		// Extend the hunk while the next change is within the context of
+		// the previous one.
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*replacePatchContext {
+			end++
+		}
+		from := max(changed[start]-replacePatchContext, 0)
+		to := min(changed[end]+replacePatchContext+1, len(lines))
+
+		var hunk bytes.Buffer
+		oldCount, newCount := 0, 0
+		for i := from; i < to; i++ {
+			repl, ok := replaced[i]
+			if !ok {
+				writeHunkLine(&hunk, ' ', lines[i])
+				oldCount++
+				newCount++
+				continue
+			}
+			writeHunkLine(&hunk, '-', lines[i])
+			oldCount++
+			for _, line := range repl {
+				writeHunkLine(&hunk, '+', line)
+				newCount++
+			}
+		}
+		fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", from+1, oldCount, from+1+shift, newCount)
+		buf.Write(hunk.Bytes())
+		shift += newCount - oldCount
+		start = end + 1
+	}
+}
+
+// replacementLines splits the replaced text of line into the lines that
+// take its place, giving each the original line ending. A last line
+// without one gets eol, the file's line break, between its parts.
+func replacementLines(line patchLine, text string, eol string) []patchLine {
+	parts := strings.Split(text, "\n")
+	out := make([]patchLine, len(parts))
+	for i, part := range parts {
+		out[i] = patchLine{text: part, newline: line.newline}
+		if i < len(parts)-1 && line.newline == "" {
+			out[i].newline = eol
+		}
+	}
+	return out
+}
+
+// lineReplacer replaces the matches of a search in one line.
+type lineReplacer struct {
+	pattern      *regexp.Regexp
+	replacement  string
+	literal      bool // Insert the replacement as is rather than expanding $1
+	preserveCase bool // Recase each replacement like the text it replaces (matchCase)
+}
+
+// replace returns line with every match replaced.
+func (r lineReplacer) replace(line string) string {
+	if !r.preserveCase {
+		if r.literal {
+			return r.pattern.ReplaceAllLiteralString(line, r.replacement)
+		}
+		return r.pattern.ReplaceAllString(line, r.replacement)
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range r.pattern.FindAllStringSubmatchIndex(line, -1) {
+		text := r.replacement
+		if !r.literal {
+			text = string(r.pattern.ExpandString(nil, r.replacement, line, m))
+		}
+		b.WriteString(line[last:m[0]])
+		b.WriteString(matchCase(line[m[0]:m[1]], text))
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// patchBase returns the directory the paths of a replacement patch are
+// relative to: the top of the git work tree containing dir, or dir itself
+// outside git. inGit reports which.
+func patchBase(dir string) (base string, inGit bool) {
+	if root, err := runGit(dir, "rev-parse", "--show-toplevel"); err == nil && root != "" {
+		return filepath.FromSlash(root), true
+	}
+	return dir, false
+}
+
+// dirtyGitFiles returns the paths, relative to the work tree root, of the
+// tracked files with uncommitted changes.
+func dirtyGitFiles(root string) map[string]bool {
+	out, err := runGitOutput(root, "status", "--porcelain", "-z", "--untracked-files=no")
+	if err != nil {
+		return nil
+	}
+	dirty := make(map[string]bool)
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		dirty[entry[3:]] = true
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++ // The next entry is the original path of a rename or copy
+		}
+	}
+	return dirty
+}
+
+// patchBuilder collects the diffs of the files a patch changes, with
+// paths relative to the git work tree holding its directory, or to the
+// directory itself outside git.
+type patchBuilder struct {
+	base       string
+	inGit      bool
+	dirty      map[string]bool // Files with uncommitted changes, relative to base
+	buf        bytes.Buffer
+	files      int
+	dirtyFiles []string // Changed files listed in dirty, as absolute paths
+}
+
+// newPatchBuilder starts a patch for files under dir.
+func newPatchBuilder(dir string) *patchBuilder {
+	p := &patchBuilder{dirtyFiles: []string{}}
+	p.base, p.inGit = patchBase(dir)
+	if p.inGit {
+		p.dirty = dirtyGitFiles(p.base)
+	}
+	return p
+}
+
+// addFile appends the diff of the file at path, whose lines are replaced
+// as in writeFileDiff. A file without replaced lines is skipped, and one
+// with lone-CR line endings is diffed as a single line (see joinCRLines).
+func (p *patchBuilder) addFile(path string, lines []patchLine, replaced map[int][]patchLine) error {
+	if len(replaced) == 0 {
+		return nil
+	}
+	lines, replaced = joinCRLines(lines, replaced)
+	changed := make([]int, 0, len(replaced))
+	for i := range replaced {
+		changed = append(changed, i)
+	}
+	sort.Ints(changed)
+
+	var rel string
+	var err error
+	if p.inGit {
+		rel, err = gitRepoInfo{root: p.base}.repoRelativePath(path)
+	} else {
+		rel, err = filepath.Rel(p.base, path)
+		rel = filepath.ToSlash(rel)
+	}
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return newAppError(ErrCodeResultsExportFailed, path, errors.New("file is outside "+p.base))
+	}
+	writeFileDiff(&p.buf, rel, lines, replaced, changed)
+	p.files++
+	if p.dirty[rel] {
+		p.dirtyFiles = append(p.dirtyFiles, path)
+	}
+	return nil
+}
+
+// write saves the patch to a new file in the temp directory, named after
+// pattern as by os.CreateTemp, and returns its path, or "" without writing
+// when no file changed. Each patch gets a file of its own, created
+// exclusively, so it neither replaces an earlier patch nor follows a
+// symlink planted in a shared temp directory.
+func (p *patchBuilder) write(pattern string) (string, error) {
+	if p.files == 0 {
+		return "", nil
+	}
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", newAppError(ErrCodeResultsExportFailed, filepath.Join(os.TempDir(), pattern), err)
+	}
+	path := file.Name()
+	_, err = file.Write(p.buf.Bytes())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", newAppError(ErrCodeResultsExportFailed, path, err)
+	}
+	return path, nil
+}
+
+// CreateReplacePatch writes the replacement of every match of a completed
+// search as a unified diff instead of changing any file, so the change can
+// be reviewed and applied with git apply (or patch -p1) from the returned
+// Root: the top of the git work tree holding the search directory, or the
+// search directory outside git.
+//
+// Only the lines the search returned are changed. The replacement follows
+// the search's own matching: in a regex search $1 and ${name} expand to
+// capture groups, and a literal search inserts it as is. With preserveCase
+// each occurrence is recased like the text it replaces (Foo->Bar,
+// FOO->BAR, foo_bar->baz_qux; see matchCase), which suits case-insensitive
+// and naming-variant searches that rename an identifier. Searches with
+// typos allowed or inverted file matching have no exact matches to replace
+// and fail with REPLACE_UNSUPPORTED, and UTF-16 files fail with
+// REPLACE_UTF16_UNSUPPORTED. Lines are compared and replaced as the
+// search read them, after decodeText, and a UTF-8 BOM is kept. A file edited since the search fails
+// with REPLACE_FILE_CHANGED, since the patch would no longer match what
+// was reviewed. Files with uncommitted git changes are listed in
+// DirtyFiles. The patch goes to the temp directory; a search whose
+// replacement changes nothing returns an empty Path.
+func (a *App) CreateReplacePatch(searchID string, replacement string, preserveCase bool) (ReplacePatch, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return ReplacePatch{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	req := rec.request
+	if req.Fuzziness > 0 || req.InvertFileMatch {
+		return ReplacePatch{}, newAppError(ErrCodeReplaceUnsupported)
+	}
+	pattern, err := a.compileSearchPattern(req)
+	if err != nil {
+		return ReplacePatch{}, err
+	}
+	replacer := lineReplacer{
+		pattern:      pattern,
+		replacement:  replacement,
+		literal:      req.ExpandIdentifiers || (req.UseRegex != nil && !*req.UseRegex),
+		preserveCase: preserveCase,
+	}
+
+	dir, err := filepath.Abs(req.Directory)
+	if err != nil {
+		dir = req.Directory
+	}
+	builder := newPatchBuilder(dir)
+
+	// Results are grouped by file in the order the files first appear.
+	patch := ReplacePatch{Root: builder.base}
+	for _, group := range groupResults(rec.results) {
+		path := group.FilePath
+		content, err := os.ReadFile(toLongPath(path))
+		if err != nil {
+			return ReplacePatch{}, newAppError(ErrCodeReplaceFileChanged, path)
+		}
+		if info, err := os.Stat(toLongPath(path)); err != nil || info.ModTime().UnixMilli() != rec.modTimes[path] {
+			return ReplacePatch{}, newAppError(ErrCodeReplaceFileChanged, path)
+		}
+
+		// Compare with the text the search matched: without a UTF-8 BOM,
+		// which goes back into the hunk below. UTF-16 can't be patched in
+		// place with UTF-8 lines.
+		text, enc := decodeText(content)
+		if isUTF16(enc) {
+			return ReplacePatch{}, newAppError(ErrCodeReplaceUTF16, path)
+		}
+		lines := splitPatchLines(text)
+		eol := lineBreak(detectLineEnding(text))
+		replaced := make(map[int][]patchLine)
+		for _, r := range group.Results {
+			i := r.LineNum - 1
+			if _, done := replaced[i]; done {
+				continue
+			}
+			if i < 0 || i >= len(lines) || strings.TrimSpace(lines[i].text) != r.Content {
+				return ReplacePatch{}, newAppError(ErrCodeReplaceFileChanged, path)
+			}
+			text := replacer.replace(lines[i].text)
+			if text == lines[i].text {
+				continue
+			}
+			replaced[i] = replacementLines(lines[i], text, eol)
+			patch.Lines++
+		}
+		if enc == encodingUTF8BOM {
+			restoreBOM(lines, replaced)
+		}
+		if err := builder.addFile(path, lines, replaced); err != nil {
+			return ReplacePatch{}, err
+		}
+	}
+	patch.Files, patch.DirtyFiles = builder.files, builder.dirtyFiles
+	if patch.Path, err = builder.write("code-search-replace-*.patch"); err != nil || patch.Path == "" {
+		return patch, err
+	}
+	a.logInfo("Replacement patch written", logrus.Fields{
+		"searchId":   searchID,
+		"outputPath": patch.Path,
+		"files":      patch.Files,
+		"lines":      patch.Lines,
+		"dirtyFiles": len(patch.DirtyFiles),
+	})
+	return patch, nil
+}
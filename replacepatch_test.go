@@ -0,0 +1,191 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestCreateReplacePatch verifies that the patch of a regex replacement
+// applies with git apply from the work tree root, with separate hunks,
+// capture group expansion, and a last line without a newline, that files
+// with uncommitted changes are listed, and that an edited file or a fuzzy
+// search is refused.
+func TestCreateReplacePatch(t *testing.T) {
+	root, mainFile := initGitRepo(t, "")
+	mainContent := "package main\n\nfunc main() {\n\tlog.Printf(\"start\")\n" +
+		strings.Repeat("\tstep()\n", 10) + "\tlog.Printf(\"done\")\n}\n"
+	utilFile := filepath.Join(root, "src", "util.go")
+	utilContent := "package main\n\nvar _ = log.Printf"
+	for path, content := range map[string]string{mainFile: mainContent, utilFile: utilContent} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: filepath.Join(root, "src"), Query: `log\.(Printf)`}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	searchID := app.searches[len(app.searches)-1].id
+
+	patch, err := app.CreateReplacePatch(searchID, "slog.${1}Info", false)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	if patch.Files != 2 || patch.Lines != 3 || len(patch.DirtyFiles) != 1 || patch.DirtyFiles[0] != mainFile {
+		t.Fatalf("unexpected patch %+v", patch)
+	}
+	defer os.Remove(patch.Path)
+	text, err := os.ReadFile(patch.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(text), "--- a/src/main.go") || strings.Count(string(text), "@@ -") != 3 {
+		t.Errorf("expected repo-relative paths and three hunks, got:\n%s", text)
+	}
+
+	cmd := exec.Command("git", "-C", patch.Root, "apply", patch.Path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply: %v\n%s\n%s", err, out, text)
+	}
+	for path, content := range map[string]string{mainFile: mainContent, utilFile: utilContent} {
+		got, _ := os.ReadFile(path)
+		if want := strings.ReplaceAll(content, "log.Printf", "slog.PrintfInfo"); string(got) != want {
+			t.Errorf("%s after git apply:\n%s\nwant:\n%s", filepath.Base(path), got, want)
+		}
+	}
+
+	if _, err := app.CreateReplacePatch(searchID, "x", false); err == nil || err.(*AppError).Code != ErrCodeReplaceFileChanged {
+		t.Errorf("expected %s after the files changed, got %v", ErrCodeReplaceFileChanged, err)
+	}
+
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: root, Query: "log", Fuzziness: 1}})
+	if _, err := app.CreateReplacePatch(id, "x", false); err == nil || err.(*AppError).Code != ErrCodeReplaceUnsupported {
+		t.Errorf("expected %s for a fuzzy search, got %v", ErrCodeReplaceUnsupported, err)
+	}
+}
+
+// TestCreateReplacePatchLiteral verifies that a literal replacement is
+// inserted as is, CRLF endings are kept, and a replacement that changes
+// nothing writes no patch.
+func TestCreateReplacePatchLiteral(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("price: 5\r\ncost: 5\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	literal := false
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "price", UseRegex: &literal}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	searchID := app.searches[len(app.searches)-1].id
+
+	if patch, err := app.CreateReplacePatch(searchID, "price", false); err != nil || patch.Path != "" || patch.Files != 0 {
+		t.Errorf("expected no patch for a no-op replacement, got %+v, %v", patch, err)
+	}
+	patch, err := app.CreateReplacePatch(searchID, "$cost", false)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	defer os.Remove(patch.Path)
+	text, _ := os.ReadFile(patch.Path)
+	want := "diff --git a/a.txt b/a.txt\n--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n-price: 5\r\n+$cost: 5\r\n cost: 5\r\n"
+	if patch.Root != dir || string(text) != want {
+		t.Errorf("unexpected patch in %s:\n%q\nwant:\n%q", patch.Root, text, want)
+	}
+
+	// A second patch of the same search gets a file of its own.
+	again, err := app.CreateReplacePatch(searchID, "total", false)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	defer os.Remove(again.Path)
+	if again.Path == patch.Path {
+		t.Errorf("expected a new patch file, got %s twice", patch.Path)
+	}
+	if text, _ := os.ReadFile(patch.Path); string(text) != want {
+		t.Errorf("expected the first patch kept, got %q", text)
+	}
+	info, err := os.Stat(again.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a private patch file, got mode %v", info.Mode().Perm())
+	}
+}
+
+// TestCreateReplacePatchEncodings verifies that a match on the first line
+// of a UTF-8 file with a BOM is replaced with the BOM kept, and that a
+// UTF-16 file is reported as unsupported rather than changed.
+func TestCreateReplacePatchEncodings(t *testing.T) {
+	dir := t.TempDir()
+	bomFile := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(bomFile, []byte("\xEF\xBB\xBFpackage pin\n\nvar pin = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	literal := false
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "package", UseRegex: &literal}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	patch, err := app.CreateReplacePatch(app.searches[len(app.searches)-1].id, "module", false)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	defer os.Remove(patch.Path)
+	cmd := exec.Command("git", "-C", patch.Root, "apply", patch.Path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply: %v\n%s", err, out)
+	}
+	if got, _ := os.ReadFile(bomFile); string(got) != "\xEF\xBB\xBFmodule pin\n\nvar pin = 1\n" {
+		t.Errorf("a.go after git apply is %q", got)
+	}
+
+	os.Remove(bomFile)
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), encodeUTF16("package b\n", false), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "package", UseRegex: &literal})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected the UTF-16 file to match, got %d results, %v", len(results), err)
+	}
+	_, err = app.CreateReplacePatch(app.searches[len(app.searches)-1].id, "module", false)
+	if err == nil || err.(*AppError).Code != ErrCodeReplaceUTF16 {
+		t.Errorf("expected %s, got %v", ErrCodeReplaceUTF16, err)
+	}
+}
+
+// TestCreateReplacePatchPreserveCase verifies that a naming-variant search
+// replaced with preserveCase renames every variant in its own convention.
+func TestCreateReplacePatchPreserveCase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	content := "getUser()\nGetUser()\nget_user()\nGET_USER = 1\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "getUser", ExpandIdentifiers: true}); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	searchID := app.searches[len(app.searches)-1].id
+
+	patch, err := app.CreateReplacePatch(searchID, "fetchAccount", true)
+	if err != nil {
+		t.Fatalf("CreateReplacePatch failed: %v", err)
+	}
+	defer os.Remove(patch.Path)
+	text, _ := os.ReadFile(patch.Path)
+	for _, want := range []string{"+fetchAccount()\n", "+FetchAccount()\n", "+fetch_account()\n", "+FETCH_ACCOUNT = 1\n"} {
+		if !strings.Contains(string(text), want) {
+			t.Errorf("expected %q in the patch:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats GenerateReport writes.
+const (
+	reportFormatHTML     = "html"
+	reportFormatMarkdown = "markdown"
+)
+
+// reportLine is a line of a report snippet: a match, or a line of context
+// around one.
+type reportLine struct {
+	Num     int
+	Text    string
+	Spans   []MatchSpan // Matches on the line; nil for context
+	IsMatch bool
+	Gap     bool // Lines are left out between the previous line and this one
+}
+
+// reportFile is the snippets of one file in a report.
+type reportFile struct {
+	Path    string // Relative to the search directory when below it
+	Matches int
+	Lines   []reportLine
+}
+
+// reportFilter is a search setting listed in a report.
+type reportFilter struct {
+	Name  string
+	Value string
+}
+
+// reportData is everything a report shows, in either format.
+type reportData struct {
+	Query       string
+	Directory   string
+	Filters     []reportFilter
+	GeneratedAt string
+	FinishedAt  string
+	Results     int
+	Matches     int
+	Files       []reportFile
+	Sampled     bool // Only a sample of the matches was kept
+	Incomplete  bool // The directory was removed before the search finished
+}
+
+// reportFilters lists the settings of req that narrowed or changed the
+// search, leaving out those at their defaults.
+func reportFilters(req SearchRequest) []reportFilter {
+	var filters []reportFilter
+	add := func(name, value string) {
+		filters = append(filters, reportFilter{Name: name, Value: value})
+	}
+	if req.UseRegex == nil || *req.UseRegex {
+		add("Mode", "regular expression")
+	} else {
+		add("Mode", "literal")
+	}
+	if req.CaseSensitive {
+		add("Case sensitive", "yes")
+	}
+	if req.Extension != "" {
+		add("Extension", req.Extension)
+	}
+	if len(req.AllowedFileTypes) > 0 {
+		add("File types", strings.Join(req.AllowedFileTypes, ", "))
+	}
+	if len(req.ExcludePatterns) > 0 {
+		add("Excluded", strings.Join(req.ExcludePatterns, ", "))
+	}
+	if len(req.IncludePaths) > 0 {
+		add("Paths", strings.Join(req.IncludePaths, ", "))
+	}
+	if len(req.ExcludePaths) > 0 {
+		add("Excluded paths", strings.Join(req.ExcludePaths, ", "))
+	}
+	if req.MinFileSize > 0 {
+		add("Minimum file size", strconv.FormatInt(req.MinFileSize, 10)+" bytes")
+	}
+	if req.MaxFileSize > 0 {
+		add("Maximum file size", strconv.FormatInt(req.MaxFileSize, 10)+" bytes")
+	}
+	if !req.SearchSubdirs {
+		add("Subdirectories", "not searched")
+	}
+	if req.IncludeBinary {
+		add("Binary files", "included")
+	}
+	if req.SkipGenerated {
+		add("Generated files", "skipped")
+	}
+	if req.IncludeWellKnownFiles {
+		add("Build and CI files", "always searched")
+	}
+	if req.ExpandIdentifiers {
+		add("Identifier variants", "yes")
+	}
+	if req.Fuzziness > 0 {
+		add("Fuzziness", strconv.Itoa(req.Fuzziness))
+	}
+	if req.InvertFileMatch {
+		add("Files", "without matches")
+	}
+	if req.ModifiedAfter > 0 {
+		add("Modified after", time.UnixMilli(req.ModifiedAfter).Format(time.RFC3339))
+	}
+	if req.ModifiedBefore > 0 {
+		add("Modified before", time.UnixMilli(req.ModifiedBefore).Format(time.RFC3339))
+	}
+	if req.OwnedByMe {
+		add("Owner", "current user")
+	}
+	if req.PermissionMask != 0 {
+		add("Permissions", fmt.Sprintf("any of %#o", req.PermissionMask))
+	}
+	if req.HeadLines > 0 {
+		add("First lines", strconv.Itoa(req.HeadLines))
+	}
+	if req.RegionStart != "" {
+		add("Region", strings.TrimSpace(req.RegionStart+" … "+req.RegionEnd))
+	}
+	return filters
+}
+
+// buildReport collects what a report of rec shows. Files are sorted by
+// path and their snippets by line; context that overlaps a neighbouring
+// match is shown once.
+func buildReport(rec searchRecord) reportData {
+	data := reportData{
+		Query:       rec.request.Query,
+		Directory:   rec.request.Directory,
+		Filters:     reportFilters(rec.request),
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		FinishedAt:  rec.finishedAt.Format(time.RFC3339),
+		Results:     len(rec.results),
+		Sampled:     rec.counts != nil,
+		Incomplete:  rec.incomplete,
+	}
+	baseDir, err := filepath.Abs(rec.request.Directory)
+	if err != nil {
+		baseDir = rec.request.Directory
+	}
+
+	for _, g := range groupResults(rec.results) {
+		results := append([]SearchResult(nil), g.Results...)
+		sort.SliceStable(results, func(i, j int) bool { return results[i].LineNum < results[j].LineNum })
+
+		file := reportFile{Path: g.FilePath}
+		if rel, err := filepath.Rel(baseDir, g.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+			file.Path = filepath.ToSlash(rel)
+		}
+		lines := make(map[int]reportLine)
+		for _, r := range results {
+			for i, text := range r.ContextBefore {
+				num := r.LineNum - len(r.ContextBefore) + i
+				if _, ok := lines[num]; !ok {
+					lines[num] = reportLine{Num: num, Text: text}
+				}
+			}
+			for i, text := range r.ContextAfter {
+				num := r.LineNum + 1 + i
+				if _, ok := lines[num]; !ok {
+					lines[num] = reportLine{Num: num, Text: text}
+				}
+			}
+			// A match replaces context of the same line; several results on
+			// one line keep the first.
+			if line, ok := lines[r.LineNum]; !ok || !line.IsMatch {
+				lines[r.LineNum] = reportLine{Num: r.LineNum, Text: r.Content, Spans: r.Spans, IsMatch: true}
+			}
+			file.Matches += max(1, len(r.Spans))
+		}
+		for _, line := range lines {
+			file.Lines = append(file.Lines, line)
+		}
+		sort.Slice(file.Lines, func(i, j int) bool { return file.Lines[i].Num < file.Lines[j].Num })
+		for i := 1; i < len(file.Lines); i++ {
+			file.Lines[i].Gap = file.Lines[i].Num > file.Lines[i-1].Num+1
+		}
+		data.Matches += file.Matches
+		data.Files = append(data.Files, file)
+	}
+	sort.Slice(data.Files, func(i, j int) bool { return data.Files[i].Path < data.Files[j].Path })
+	return data
+}
+
+// matchCount returns "1 match" or "n matches".
+func matchCount(n int) string {
+	if n == 1 {
+		return "1 match"
+	}
+	return strconv.Itoa(n) + " matches"
+}
+
+// highlightHTML escapes text and wraps each span in a <mark>.
+func highlightHTML(text string, spans []MatchSpan) template.HTML {
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.Start < pos || s.End > len(text) || s.Start >= s.End {
+			continue
+		}
+		b.WriteString(template.HTMLEscapeString(text[pos:s.Start]))
+		b.WriteString("<mark>")
+		b.WriteString(template.HTMLEscapeString(text[s.Start:s.End]))
+		b.WriteString("</mark>")
+		pos = s.End
+	}
+	b.WriteString(template.HTMLEscapeString(text[pos:]))
+	return template.HTML(b.String())
+}
+
+// reportTemplate is the HTML report. Styles are inline so the file can be
+// opened or mailed on its own.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"highlight": highlightHTML,
+	"matches":   matchCount,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Search report: {{.Query}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table.summary td { padding: 0.15rem 1rem 0.15rem 0; vertical-align: top; }
+table.summary td:first-child { color: #666; }
+h2 { font-size: 1rem; margin: 1.5rem 0 0.4rem; font-family: ui-monospace, monospace; }
+h2 small { color: #666; font-weight: normal; }
+pre { background: #f6f8fa; border: 1px solid #ddd; border-radius: 4px; padding: 0.5rem 0; overflow-x: auto; margin: 0; }
+pre span { display: block; min-height: 1.2em; padding: 0 0.75rem; }
+pre span.context { color: #777; }
+pre span.gap { color: #aaa; }
+pre .num { display: inline-block; min-width: 3.5em; color: #999; user-select: none; }
+mark { background: #fde68a; }
+p.note { color: #92400e; }
+</style>
+</head>
+<body>
+<h1>Search report</h1>
+<table class="summary">
+<tr><td>Query</td><td><code>{{.Query}}</code></td></tr>
+<tr><td>Directory</td><td><code>{{.Directory}}</code></td></tr>
+{{- range .Filters}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+<tr><td>Results</td><td>{{.Matches}} matches on {{.Results}} lines in {{len .Files}} files</td></tr>
+<tr><td>Searched</td><td>{{.FinishedAt}}</td></tr>
+<tr><td>Generated</td><td>{{.GeneratedAt}}</td></tr>
+</table>
+{{- if .Sampled}}
+<p class="note">The matches below are a sample spread across the files; the search found more.</p>
+{{- end}}
+{{- if .Incomplete}}
+<p class="note">The search directory was removed before the search finished, so results may be missing.</p>
+{{- end}}
+{{- range .Files}}
+<h2>{{.Path}} <small>{{matches .Matches}}</small></h2>
+<pre>
+{{- range .Lines}}
+{{- if .Gap}}<span class="gap">…</span>{{end}}
+{{- if .IsMatch}}<span class="match"><span class="num">{{.Num}}</span>{{highlight .Text .Spans}}</span>
+{{- else}}<span class="context"><span class="num">{{.Num}}</span>{{.Text}}</span>
+{{- end}}
+{{- end}}
+</pre>
+{{- end}}
+</body>
+</html>
+`))
+
+// mdFence returns a run of backticks longer than any in text, at least
+// min long, to open and close a Markdown code span or block around it.
+func mdFence(text string, min int) string {
+	longest, run := 0, 0
+	for _, c := range text {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(min, longest+1))
+}
+
+// mdCode formats text as a Markdown code span.
+func mdCode(text string) string {
+	fence := mdFence(text, 1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// writeReportMarkdown writes data as Markdown. Markdown has no portable way
+// to highlight inside a code block, so each match line is followed by a
+// line of carets under the matched characters, as compilers mark errors.
+func writeReportMarkdown(w io.Writer, data reportData) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Search report\n\n")
+	fmt.Fprintf(bw, "| | |\n|---|---|\n")
+	row := func(name, value string) {
+		fmt.Fprintf(bw, "| %s | %s |\n", name, strings.ReplaceAll(value, "|", `\|`))
+	}
+	row("Query", mdCode(data.Query))
+	row("Directory", mdCode(data.Directory))
+	for _, f := range data.Filters {
+		row(f.Name, f.Value)
+	}
+	row("Results", fmt.Sprintf("%d matches on %d lines in %d files", data.Matches, data.Results, len(data.Files)))
+	row("Searched", data.FinishedAt)
+	row("Generated", data.GeneratedAt)
+	if data.Sampled {
+		fmt.Fprintf(bw, "\n> The matches below are a sample spread across the files; the search found more.\n")
+	}
+	if data.Incomplete {
+		fmt.Fprintf(bw, "\n> The search directory was removed before the search finished, so results may be missing.\n")
+	}
+
+	for _, file := range data.Files {
+		fmt.Fprintf(bw, "\n## %s (%s)\n\n", mdCode(file.Path), matchCount(file.Matches))
+		width := len(strconv.Itoa(file.Lines[len(file.Lines)-1].Num))
+		var block strings.Builder
+		for _, line := range file.Lines {
+			if line.Gap {
+				fmt.Fprintf(&block, " %*s |\n", width, "…")
+			}
+			marker := " "
+			if line.IsMatch {
+				marker = ">"
+			}
+			fmt.Fprintf(&block, "%s%*d | %s\n", marker, width, line.Num, line.Text)
+			if carets := spanCarets(line.Text, line.Spans); carets != "" {
+				fmt.Fprintf(&block, " %*s | %s\n", width, "", carets)
+			}
+		}
+		fence := mdFence(block.String(), 3)
+		fmt.Fprintf(bw, "%s\n%s%s\n", fence, block.String(), fence)
+	}
+	return bw.Flush()
+}
+
+// spanCarets returns a line with a caret under each character of text that
+// a span covers, and spaces before them, or "" without spans. Tabs are
+// kept so the carets line up however the reader displays them.
+func spanCarets(text string, spans []MatchSpan) string {
+	if len(spans) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.Start < pos || s.End > len(text) || s.Start >= s.End {
+			continue
+		}
+		for _, c := range text[pos:s.Start] {
+			if c == '\t' {
+				b.WriteByte('\t')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(strings.Repeat("^", utf8.RuneCountInString(text[s.Start:s.End])))
+		pos = s.End
+	}
+	return b.String()
+}
+
+// GenerateReport writes a self-contained report of a completed search to
+// path, for sharing findings with people who don't have the app: the
+// query, the settings that filtered it, totals, and every match grouped by
+// file with its context and the matched text highlighted. format is "html"
+// (one file with inline styles) or "markdown". The file is created or
+// truncated; path must be absolute. It returns the path written.
+func (a *App) GenerateReport(searchID string, format string, path string) (string, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return "", newAppError(ErrCodeSearchNotFound, searchID)
+	}
+	format = strings.ToLower(format)
+	if format == "md" {
+		format = reportFormatMarkdown
+	}
+	if format != reportFormatHTML && format != reportFormatMarkdown {
+		return "", newAppError(ErrCodeExportFormatUnsupported, format, "html, markdown")
+	}
+	if path == "" {
+		return "", newAppError(ErrCodePathRequired)
+	}
+	if !filepath.IsAbs(path) {
+		return "", newAppError(ErrCodeResultsExportFailed, path, errors.New("path must be absolute"))
+	}
+
+	data := buildReport(rec)
+	snap := snapshotForUndo(path)
+	file, err := os.Create(toLongPath(path))
+	if err != nil {
+		return "", newAppError(ErrCodeResultsExportFailed, path, err)
+	}
+	var writeErr error
+	if format == reportFormatHTML {
+		bw := bufio.NewWriter(file)
+		if writeErr = reportTemplate.Execute(bw, data); writeErr == nil {
+			writeErr = bw.Flush()
+		}
+	} else {
+		writeErr = writeReportMarkdown(file, data)
+	}
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		return "", newAppError(ErrCodeResultsExportFailed, path, errors.Join(writeErr, closeErr))
+	}
+
+	a.recordUndo(snap, undoKindExport, "Generate report")
+	a.logInfo("Search report generated", logrus.Fields{
+		"searchId":     searchID,
+		"outputPath":   path,
+		"format":       format,
+		"resultsCount": len(rec.results),
+	})
+	return path, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateReport verifies the HTML and Markdown reports of a stored
+// search: escaping and highlighting, grouping by relative path, merged
+// context, and the rejected formats and paths.
+func TestGenerateReport(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	useRegex := false
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{
+		Directory:       "/src",
+		Query:           "<token>",
+		UseRegex:        &useRegex,
+		ExcludePatterns: []string{"vendor"},
+		SearchSubdirs:   true,
+	}, results: []SearchResult{
+		{FilePath: "/src/web/page.html", LineNum: 4, Content: `<b><token></b> "x"`, Spans: []MatchSpan{{Start: 3, End: 10, RuneStart: 3, RuneEnd: 10}},
+			ContextBefore: []string{"<div>"}, ContextAfter: []string{"</div>"}},
+		{FilePath: "/src/web/page.html", LineNum: 5, Content: "<token> again", Spans: []MatchSpan{{Start: 0, End: 7, RuneStart: 0, RuneEnd: 7}}},
+		{FilePath: "/src/api/auth.go", LineNum: 20, Content: "\tv := \"<token>\"", Spans: []MatchSpan{{Start: 7, End: 14, RuneStart: 7, RuneEnd: 14}}},
+	}})
+	dir := t.TempDir()
+
+	htmlPath := filepath.Join(dir, "report.html")
+	if written, err := app.GenerateReport(id, "HTML", htmlPath); err != nil || written != htmlPath {
+		t.Fatalf("GenerateReport = %q, %v", written, err)
+	}
+	data, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"<code>&lt;token&gt;</code>",
+		"<td>Excluded</td><td>vendor</td>",
+		"3 matches on 3 lines in 2 files",
+		"&lt;b&gt;<mark>&lt;token&gt;</mark>&lt;/b&gt; &#34;x&#34;",
+		"<h2>web/page.html <small>2 matches</small></h2>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the HTML report to contain %q", want)
+		}
+	}
+	if strings.Index(html, "api/auth.go") > strings.Index(html, "web/page.html") {
+		t.Error("expected files sorted by path")
+	}
+	// Line 5 is both the context after line 4 and a match; it appears once.
+	if n := strings.Count(html, "again"); n != 1 {
+		t.Errorf("expected line 5 once, got %d times", n)
+	}
+
+	mdPath := filepath.Join(dir, "report.md")
+	if _, err := app.GenerateReport(id, "md", mdPath); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+	data, err = os.ReadFile(mdPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## `api/auth.go` (1 match)\n\n```\n>20 | \tv := \"<token>\"\n    | \t      ^^^^^^^\n```\n"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected the Markdown report to contain %q, got\n%s", want, data)
+	}
+	if !strings.Contains(string(data), "| Mode | literal |") {
+		t.Errorf("expected the search mode in the summary, got\n%s", data)
+	}
+
+	if _, err := app.GenerateReport(id, "pdf", htmlPath); err == nil || err.(*AppError).Code != ErrCodeExportFormatUnsupported {
+		t.Errorf("expected %s, got %v", ErrCodeExportFormatUnsupported, err)
+	}
+	if _, err := app.GenerateReport(id, "html", "report.html"); err == nil || err.(*AppError).Code != ErrCodeResultsExportFailed {
+		t.Errorf("expected %s for a relative path, got %v", ErrCodeResultsExportFailed, err)
+	}
+	if _, err := app.GenerateReport("search-404", "html", htmlPath); err == nil || err.(*AppError).Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeSearchNotFound, err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultResultTemplate is the grep-style reference used when FormatResult
+// is given an empty template.
+const defaultResultTemplate = "{relpath}:{line}: {content}"
+
+// resultTemplatePresets are named templates FormatResult accepts in place of
+// a template string.
+var resultTemplatePresets = map[string]string{
+	"grep":             defaultResultTemplate,
+	"path-line":        "{path}:{line}",
+	"relpath-line":     "{relpath}:{line}",
+	"permalink":        "{permalink}",
+	"github-permalink": "{permalink}",
+}
+
+// FormatResult renders result as text for the clipboard, so copied
+// references look the same everywhere. template is either a preset name
+// (grep, path-line, relpath-line, permalink; github-permalink is an alias)
+// or a string with these
+// placeholders:
+//
+//	{path}      absolute file path
+//	{relpath}   path relative to the git work tree root (the absolute
+//	            path when the file isn't in a repository)
+//	{file}      file name
+//	{line}      line number
+//	{content}   the matched line, trimmed
+//	{match}     the matched text
+//	{permalink} web URL of the line at the current commit (see GetRemoteLink)
+//
+// Git is only consulted when {relpath} or {permalink} is used. A template
+// with {permalink} fails with NO_REMOTE_LINK when the file isn't in a
+// repository whose origin is on a recognised code host.
+func (a *App) FormatResult(result SearchResult, template string) (string, error) {
+	if template == "" {
+		template = defaultResultTemplate
+	}
+	if preset, ok := resultTemplatePresets[template]; ok {
+		template = preset
+	}
+
+	relPath := result.FilePath
+	permalink := ""
+	needsRel := strings.Contains(template, "{relpath}")
+	needsLink := strings.Contains(template, "{permalink}")
+	if needsRel || needsLink {
+		info, err := lookupGitRepo(result.FilePath)
+		if err == nil {
+			if rel, err := info.repoRelativePath(result.FilePath); err == nil {
+				relPath = rel
+				permalink, _ = info.permalink(rel, result.LineNum)
+			}
+		}
+		if needsLink && permalink == "" {
+			return "", newAppError(ErrCodeNoRemoteLink, result.FilePath)
+		}
+	}
+
+	replacer := strings.NewReplacer(
+		"{path}", result.FilePath,
+		"{relpath}", relPath,
+		"{file}", filepath.Base(result.FilePath),
+		"{line}", strconv.Itoa(result.LineNum),
+		"{content}", strings.TrimSpace(result.Content),
+		"{match}", result.MatchedText,
+		"{permalink}", permalink,
+	)
+	return replacer.Replace(template), nil
+}
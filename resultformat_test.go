@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFormatResultPlaceholders verifies placeholder substitution, presets,
+// and the fallback to the absolute path outside a repository.
+func TestFormatResultPlaceholders(t *testing.T) {
+	app := NewApp()
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	result := SearchResult{FilePath: path, LineNum: 7, Content: "  TODO: fix  ", MatchedText: "TODO"}
+
+	cases := map[string]string{
+		"":                         path + ":7: TODO: fix",
+		"path-line":                path + ":7",
+		"{file} L{line} [{match}]": "notes.txt L7 [TODO]",
+		"{relpath}":                path,
+		"no placeholders":          "no placeholders",
+	}
+	for template, want := range cases {
+		got, err := app.FormatResult(result, template)
+		if err != nil || got != want {
+			t.Errorf("FormatResult(%q) = %q, %v; want %q", template, got, err, want)
+		}
+	}
+}
+
+// TestFormatResultInRepo verifies the repo-relative path and the GitHub
+// permalink pinned to HEAD.
+func TestFormatResultInRepo(t *testing.T) {
+	_, file := initGitRepo(t, "https://github.com/org/repo.git")
+	app := NewApp()
+	result := SearchResult{FilePath: file, LineNum: 3, Content: "func main() {}"}
+
+	got, err := app.FormatResult(result, "grep")
+	if err != nil || got != "src/main.go:3: func main() {}" {
+		t.Errorf("grep preset = %q, %v", got, err)
+	}
+
+	link, err := app.FormatResult(result, "github-permalink")
+	if err != nil {
+		t.Fatalf("github-permalink failed: %v", err)
+	}
+	if !strings.HasPrefix(link, "https://github.com/org/repo/blob/") || !strings.HasSuffix(link, "/src/main.go#L3") {
+		t.Errorf("unexpected permalink %q", link)
+	}
+}
+
+// TestFormatResultNoRemote verifies NO_REMOTE_LINK for a repository
+// without a GitHub origin.
+func TestFormatResultNoRemote(t *testing.T) {
+	_, file := initGitRepo(t, "")
+	app := NewApp()
+
+	var appErr *AppError
+	_, err := app.FormatResult(SearchResult{FilePath: file, LineNum: 1}, "github-permalink")
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeNoRemoteLink {
+		t.Errorf("expected %s, got %v", ErrCodeNoRemoteLink, err)
+	}
+}
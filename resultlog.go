@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxResultLogMatches caps a search that writes its results to a result
+// log. The log lives on disk, so the cap is far above MaxResults; it only
+// keeps a runaway pattern from filling the disk.
+const maxResultLogMatches = 5000000
+
+// searchScanLimit returns how many matches the workers look for: the
+// sampling threshold when sampling, maxResultLogMatches when the results
+// go to a result log, and MaxResults otherwise.
+func searchScanLimit(req SearchRequest) int {
+	switch {
+	case req.ResultLogPath != "":
+		return maxResultLogMatches
+	case req.Sampling:
+		return req.SamplingThreshold
+	default:
+		return req.MaxResults
+	}
+}
+
+// openResultLog creates (or truncates) the request's result log and returns
+// it with the sink that writes to it.
+func (a *App) openResultLog(req SearchRequest) (*os.File, *ndjsonSink, error) {
+	file, err := os.Create(toLongPath(req.ResultLogPath))
+	if err != nil {
+		a.logError("Failed to create result log", err, logrus.Fields{
+			"resultLogPath": req.ResultLogPath,
+		})
+		return nil, nil, newAppError(ErrCodeResultsExportFailed, req.ResultLogPath, err)
+	}
+	return file, newNDJSONSink(file), nil
+}
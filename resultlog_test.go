@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSearchWithResultLog verifies that a search with a result log writes
+// every match to disk, runs past MaxResults, and returns only the first
+// MaxResults.
+func TestSearchWithResultLog(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		body := "needle one\nneedle two\n"
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("f%02d.txt", i)), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating file: %v", err)
+		}
+	}
+	logPath := filepath.Join(t.TempDir(), "results.ndjson")
+
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:     tempDir,
+		Query:         "needle",
+		SearchSubdirs: true,
+		MaxResults:    5,
+		ResultLogPath: logPath,
+	})
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 5 {
+		t.Errorf("expected 5 results in memory, got %d", len(results))
+	}
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("opening result log: %v", err)
+	}
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		lines++
+	}
+	if lines != 40 {
+		t.Errorf("expected all 40 matches in the result log, got %d", lines)
+	}
+}
+
+// TestResultLogPathMustBeAbsolute verifies that a relative result log path
+// is rejected before the search starts.
+func TestResultLogPathMustBeAbsolute(t *testing.T) {
+	app := NewApp()
+	_, err := app.SearchWithProgress(SearchRequest{
+		Directory:     t.TempDir(),
+		Query:         "needle",
+		ResultLogPath: "results.ndjson",
+	})
+	if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeResultsExportFailed {
+		t.Errorf("expected %s, got %v", ErrCodeResultsExportFailed, err)
+	}
+}
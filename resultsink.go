@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// ResultSink receives the output of a search as it runs. AddResult is
+// called once per final result, from a single goroutine; an error stops
+// the search. Progress is called with the "started" and "in-progress"
+// events, possibly from several workers at once. Done is called last with
+// the "completed" event.
+//
+// Results arrive as soon as they are final: while the search runs, or
+// after the scan for a sampled search.
+type ResultSink interface {
+	AddResult(result SearchResult) error
+	Progress(p SearchProgress)
+	Done(final SearchProgress)
+}
+
+// eventSink sends progress to the frontend as search-progress events and
+// keeps the latest one for GetSearchProgress. The results themselves reach
+// the frontend as the SearchWithProgress return value, so AddResult does
+// nothing.
+type eventSink struct{ a *App }
+
+func (s eventSink) AddResult(SearchResult) error { return nil }
+
+func (s eventSink) Progress(p SearchProgress) {
+	s.a.recordProgress(p)
+	s.a.safeEmitEvent("search-progress", &p)
+}
+
+func (s eventSink) Done(final SearchProgress) {
+	s.a.recordProgress(final)
+	s.a.safeEmitEvent("search-progress", &final)
+}
+
+// memorySink collects results and the final progress in memory, for
+// callers that embed the searcher without a frontend.
+type memorySink struct {
+	mu      sync.Mutex
+	results []SearchResult
+	final   *SearchProgress
+}
+
+func (s *memorySink) AddResult(result SearchResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *memorySink) Progress(SearchProgress) {}
+
+func (s *memorySink) Done(final SearchProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.final = &final
+}
+
+// ndjsonSink writes each result as one JSON object per line. Progress is
+// not written. Done flushes the buffer; a failed write or flush is kept and
+// returned by Err, and fails every later AddResult.
+type ndjsonSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+	err error
+}
+
+// newNDJSONSink returns an ndjsonSink writing to w.
+func newNDJSONSink(w io.Writer) *ndjsonSink {
+	bw := bufio.NewWriter(w)
+	return &ndjsonSink{w: bw, enc: json.NewEncoder(bw)}
+}
+
+func (s *ndjsonSink) AddResult(result SearchResult) error {
+	if s.err == nil {
+		// Encode appends the newline that ends the record.
+		s.err = s.enc.Encode(result)
+	}
+	return s.err
+}
+
+func (s *ndjsonSink) Progress(SearchProgress) {}
+
+func (s *ndjsonSink) Done(SearchProgress) {
+	if err := s.w.Flush(); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the first write error.
+func (s *ndjsonSink) Err() error {
+	return s.err
+}
+
+// multiSink passes everything to each of its sinks in order. AddResult
+// stops at the first error.
+type multiSink []ResultSink
+
+func (m multiSink) AddResult(result SearchResult) error {
+	for _, s := range m {
+		if err := s.AddResult(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiSink) Progress(p SearchProgress) {
+	for _, s := range m {
+		s.Progress(p)
+	}
+}
+
+func (m multiSink) Done(final SearchProgress) {
+	for _, s := range m {
+		s.Done(final)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+// TestNDJSONSink verifies one JSON object per line and that a write error
+// sticks.
+func TestNDJSONSink(t *testing.T) {
+	var buf strings.Builder
+	sink := newNDJSONSink(&buf)
+	for i := 1; i <= 2; i++ {
+		if err := sink.AddResult(SearchResult{FilePath: "/a.go", LineNum: i}); err != nil {
+			t.Fatalf("AddResult failed: %v", err)
+		}
+	}
+	sink.Done(SearchProgress{})
+	if sink.Err() != nil {
+		t.Fatalf("unexpected error %v", sink.Err())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	var r SearchResult
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil || r.LineNum != 2 {
+		t.Errorf("expected line 2 to decode to LineNum 2, got %+v (err %v)", r, err)
+	}
+
+	broken := newNDJSONSink(failingWriter{})
+	broken.AddResult(SearchResult{Content: strings.Repeat("x", 8192)})
+	if err := broken.AddResult(SearchResult{}); err == nil {
+		t.Error("expected the write error to fail later results")
+	}
+}
+
+// TestSearcherSinks verifies that results reach the sink as they are found,
+// that a sampled search passes only the sample, and that Done gets the
+// completed event.
+func TestSearcherSinks(t *testing.T) {
+	files := fakeCollector{{absPath: "/a", size: 1}, {absPath: "/b", size: 1}}
+	for _, sampling := range []bool{false, true} {
+		sink := &memorySink{}
+		s := &searcher{collector: files, matcher: fakeMatcher{perFile: 10}, sink: sink, log: nopSearchLogger{}}
+		ctx, cancel := context.WithCancel(context.Background())
+		req := SearchRequest{MaxResults: 4, Sampling: sampling, SamplingThreshold: 100}
+		if !sampling {
+			req.MaxResults = 100
+		}
+		out, err := s.run(ctx, cancel, "id", req, regexp.MustCompile("x"), "/")
+		cancel()
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		if len(sink.results) != len(out.results) {
+			t.Errorf("sampling=%v: sink got %d results, search returned %d", sampling, len(sink.results), len(out.results))
+		}
+		if sink.final == nil || sink.final.Status != "completed" || sink.final.Sampled != sampling {
+			t.Errorf("sampling=%v: unexpected final event %+v", sampling, sink.final)
+		}
+	}
+}
+
+// errSink fails every result.
+type errSink struct{ memorySink }
+
+func (s *errSink) AddResult(SearchResult) error { return errors.New("sink closed") }
+
+// TestSearcherSinkErrorStopsSearch verifies that a failing sink cancels the
+// search and its error is returned.
+func TestSearcherSinkErrorStopsSearch(t *testing.T) {
+	s := &searcher{collector: fakeCollector{{absPath: "/a", size: 1}}, matcher: fakeMatcher{perFile: 5}, sink: &errSink{}, log: nopSearchLogger{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, err := s.run(ctx, cancel, "id", SearchRequest{MaxResults: 100}, regexp.MustCompile("x"), "/")
+	if err == nil || err.Error() != "sink closed" {
+		t.Errorf("expected the sink error, got %v", err)
+	}
+	if ctx.Err() == nil || out.cancelled {
+		t.Errorf("expected the search cancelled but not reported as a user cancel, got ctx %v, cancelled %v", ctx.Err(), out.cancelled)
+	}
+}
+
+// TestSearchToFile verifies the NDJSON export of a real search and the
+// rejection of a relative output path.
+func TestSearchToFile(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("needle\nhay\nneedle\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "results.ndjson")
+
+	n, err := app.SearchToFile(SearchRequest{Directory: tempDir, Query: "needle", SearchSubdirs: true}, outPath)
+	if err != nil {
+		t.Fatalf("SearchToFile failed: %v", err)
+	}
+	f, err := os.Open(outPath)
+	if err != nil {
+		t.Fatalf("opening output: %v", err)
+	}
+	defer f.Close()
+	lines := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); lines++ {
+	}
+	if n != 2 || lines != 2 {
+		t.Errorf("expected 2 results and 2 lines, got %d and %d", n, lines)
+	}
+
+	_, err = app.SearchToFile(SearchRequest{Directory: tempDir, Query: "needle"}, "relative.ndjson")
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeResultsExportFailed {
+		t.Errorf("expected RESULTS_EXPORT_FAILED for a relative path, got %v", err)
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resultStoreFileName is the data-directory file listing the stored
+// searches. Each search's results live in their own file, named by
+// storedResultsFileName, so browsing history doesn't load every result.
+const resultStoreFileName = "resultstore.json"
+
+// maxPersistedSearches is how many searches the result store keeps. The
+// oldest search and its results file are dropped beyond that.
+const maxPersistedSearches = 50
+
+// maxStoreQueryResults caps the results one QueryResultStore call returns.
+const maxStoreQueryResults = 5000
+
+// resultStoreIndex is the on-disk layout of resultStoreFileName.
+type resultStoreIndex struct {
+	Searches []StoredSearch `json:"searches"` // Oldest first
+}
+
+// storedResults is a stored search's results, loaded with their full-text
+// index.
+type storedResults struct {
+	results []SearchResult
+	index   ftsIndex
+}
+
+// storedResultsFileName returns the name of the file holding the results of
+// the stored search id.
+func storedResultsFileName(id string) string {
+	return "results-" + id + ".json"
+}
+
+// persistSearch adds a completed search to the result store when the
+// PersistResults setting is on. Failures are logged; they never fail the
+// search.
+func (a *App) persistSearch(rec searchRecord, totalMatches int) {
+	if a.dataDir == "" || !a.currentSettings().PersistResults {
+		return
+	}
+
+	a.resultStoreMu.Lock()
+	defer a.resultStoreMu.Unlock()
+
+	var store resultStoreIndex
+	if _, err := a.loadJSON(resultStoreFileName, &store); err != nil {
+		a.logWarn("Not storing search, result store is unreadable", logrus.Fields{"error": err.Error()})
+		return
+	}
+
+	// IDs sort by time and stay unique across restarts, unlike search IDs.
+	stored := StoredSearch{
+		ID:           strconv.FormatInt(time.Now().UnixNano(), 36),
+		Request:      rec.request,
+		ResultCount:  len(rec.results),
+		TotalMatches: totalMatches,
+		Incomplete:   rec.incomplete,
+		FinishedAt:   time.Now().UnixMilli(),
+	}
+	results := rec.results
+	if results == nil {
+		results = []SearchResult{}
+	}
+	if err := a.saveJSON(storedResultsFileName(stored.ID), results); err != nil {
+		a.logWarn("Failed to store search results", logrus.Fields{"error": err.Error()})
+		return
+	}
+
+	store.Searches = append(store.Searches, stored)
+	var evicted []StoredSearch
+	if excess := len(store.Searches) - maxPersistedSearches; excess > 0 {
+		evicted = append(evicted, store.Searches[:excess]...)
+		store.Searches = append([]StoredSearch(nil), store.Searches[excess:]...)
+	}
+	if err := a.saveJSON(resultStoreFileName, store); err != nil {
+		a.logWarn("Failed to update result store", logrus.Fields{"error": err.Error()})
+		a.removeDataFile(storedResultsFileName(stored.ID))
+		return
+	}
+	for _, old := range evicted {
+		a.removeDataFile(storedResultsFileName(old.ID))
+		delete(a.resultStoreCache, old.ID)
+	}
+	a.logDebug("Search stored", logrus.Fields{"id": stored.ID, "results": stored.ResultCount})
+}
+
+// loadStoredResults returns the results of a stored search, reading and
+// indexing them on first use. Callers must hold resultStoreMu.
+func (a *App) loadStoredResults(id string) (*storedResults, error) {
+	if cached, ok := a.resultStoreCache[id]; ok {
+		return cached, nil
+	}
+
+	var results []SearchResult
+	if _, err := a.loadJSON(storedResultsFileName(id), &results); err != nil {
+		return nil, err
+	}
+	loaded := &storedResults{results: results, index: newFTSIndex(results)}
+	if a.resultStoreCache == nil {
+		a.resultStoreCache = make(map[string]*storedResults)
+	}
+	a.resultStoreCache[id] = loaded
+	return loaded, nil
+}
+
+// ListStoredSearches returns the searches in the result store, newest
+// first.
+func (a *App) ListStoredSearches() ([]StoredSearch, error) {
+	a.resultStoreMu.Lock()
+	defer a.resultStoreMu.Unlock()
+
+	var store resultStoreIndex
+	if _, err := a.loadJSON(resultStoreFileName, &store); err != nil {
+		a.logError("Failed to load result store", err, nil)
+		return nil, newAppError(ErrCodeResultStoreFailed, err)
+	}
+	searches := make([]StoredSearch, 0, len(store.Searches))
+	for i := len(store.Searches) - 1; i >= 0; i-- {
+		searches = append(searches, store.Searches[i])
+	}
+	return searches, nil
+}
+
+// DeleteStoredSearch removes a search and its results from the result
+// store.
+func (a *App) DeleteStoredSearch(id string) error {
+	a.resultStoreMu.Lock()
+	defer a.resultStoreMu.Unlock()
+
+	var store resultStoreIndex
+	if _, err := a.loadJSON(resultStoreFileName, &store); err != nil {
+		return newAppError(ErrCodeResultStoreFailed, err)
+	}
+	for i, stored := range store.Searches {
+		if stored.ID != id {
+			continue
+		}
+		store.Searches = append(store.Searches[:i], store.Searches[i+1:]...)
+		if err := a.saveJSON(resultStoreFileName, store); err != nil {
+			a.logError("Failed to update result store", err, nil)
+			return newAppError(ErrCodeResultStoreFailed, err)
+		}
+		a.removeDataFile(storedResultsFileName(id))
+		delete(a.resultStoreCache, id)
+		return nil
+	}
+	return newAppError(ErrCodeSearchNotFound, id)
+}
+
+// QueryResultStore returns the stored results matching a filter, newest
+// search first. The filter is a list of conditions joined by AND:
+//
+//	query = 'TODO' AND path LIKE '%.go' AND content MATCH 'fix*'
+//
+// search, query, and directory compare the stored search; path, line, and
+// content compare each result. content MATCH uses the full-text index: every
+// word must appear in the line, and a trailing * matches a prefix. Two or
+// more query = conditions with different values ask which files matched
+// all of those queries: the results of searches for any of them are
+// returned, limited to files that have results for every one.
+func (a *App) QueryResultStore(filter string) (StoreQueryResult, error) {
+	conds, err := parseStoreFilter(filter)
+	if err != nil {
+		return StoreQueryResult{}, err
+	}
+
+	// Split off the query = conditions that ask for a cross-search
+	// intersection.
+	var crossQueries []string
+	for _, cond := range conds {
+		if cond.field == "query" && cond.op == "=" && !containsFold(crossQueries, cond.value) {
+			crossQueries = append(crossQueries, cond.value)
+		}
+	}
+	var searchConds, rowConds []storeCondition
+	for _, cond := range conds {
+		switch {
+		case len(crossQueries) > 1 && cond.field == "query" && cond.op == "=":
+		case cond.searchLevel():
+			searchConds = append(searchConds, cond)
+		default:
+			rowConds = append(rowConds, cond)
+		}
+	}
+
+	a.resultStoreMu.Lock()
+	defer a.resultStoreMu.Unlock()
+
+	var store resultStoreIndex
+	if _, err := a.loadJSON(resultStoreFileName, &store); err != nil {
+		a.logError("Failed to load result store", err, nil)
+		return StoreQueryResult{}, newAppError(ErrCodeResultStoreFailed, err)
+	}
+
+	type searchHits struct {
+		search StoredSearch
+		loaded *storedResults
+		rows   []int
+	}
+	var hits []searchHits
+	filesByQuery := make(map[string]map[string]bool)
+	for i := len(store.Searches) - 1; i >= 0; i-- {
+		stored := store.Searches[i]
+		if !matchStoredSearch(stored, searchConds) {
+			continue
+		}
+		if len(crossQueries) > 1 && !containsFold(crossQueries, stored.Request.Query) {
+			continue
+		}
+		loaded, err := a.loadStoredResults(stored.ID)
+		if err != nil {
+			a.logWarn("Skipping unreadable stored search", logrus.Fields{"id": stored.ID, "error": err.Error()})
+			continue
+		}
+		rows := matchStoredRows(loaded, rowConds)
+		if len(rows) == 0 {
+			continue
+		}
+		hits = append(hits, searchHits{search: stored, loaded: loaded, rows: rows})
+
+		key := strings.ToLower(stored.Request.Query)
+		if filesByQuery[key] == nil {
+			filesByQuery[key] = make(map[string]bool)
+		}
+		for _, row := range rows {
+			filesByQuery[key][loaded.results[row].FilePath] = true
+		}
+	}
+
+	out := StoreQueryResult{Results: []StoredResult{}, Files: []string{}}
+	files := make(map[string]bool)
+collect:
+	for _, hit := range hits {
+		for _, row := range hit.rows {
+			result := hit.loaded.results[row]
+			if len(crossQueries) > 1 && !matchedByAll(result.FilePath, crossQueries, filesByQuery) {
+				continue
+			}
+			if len(out.Results) == maxStoreQueryResults {
+				out.Truncated = true
+				break collect
+			}
+			out.Results = append(out.Results, StoredResult{
+				SearchID: hit.search.ID,
+				Query:    hit.search.Request.Query,
+				Result:   result,
+			})
+			if !files[result.FilePath] {
+				files[result.FilePath] = true
+				out.Files = append(out.Files, result.FilePath)
+			}
+		}
+	}
+	sort.Strings(out.Files)
+
+	a.logDebug("Result store queried", logrus.Fields{
+		"filter":  filter,
+		"results": len(out.Results),
+		"files":   len(out.Files),
+	})
+	return out, nil
+}
+
+// matchStoredSearch applies the search-level conditions to a stored search.
+func matchStoredSearch(stored StoredSearch, conds []storeCondition) bool {
+	for _, cond := range conds {
+		var value string
+		switch cond.field {
+		case "search":
+			value = stored.ID
+		case "query":
+			value = stored.Request.Query
+		case "directory":
+			value = filepath.ToSlash(stored.Request.Directory)
+		}
+		if !cond.matchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchStoredRows returns the indexes of the results matching the
+// result-level conditions. MATCH conditions narrow the rows through the
+// full-text index before the others are checked.
+func matchStoredRows(loaded *storedResults, conds []storeCondition) []int {
+	var rows []int
+	indexed := false
+	for _, cond := range conds {
+		if cond.op != "MATCH" {
+			continue
+		}
+		matched := loaded.index.match(ftsTerms(cond.value))
+		if indexed {
+			rows = intersectSorted(rows, matched)
+		} else {
+			rows, indexed = matched, true
+		}
+	}
+	if !indexed {
+		rows = make([]int, len(loaded.results))
+		for i := range rows {
+			rows[i] = i
+		}
+	}
+
+	kept := rows[:0:0]
+	for _, row := range rows {
+		if matchStoredResult(loaded.results[row], conds) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// matchStoredResult applies the result-level conditions other than MATCH.
+func matchStoredResult(result SearchResult, conds []storeCondition) bool {
+	for _, cond := range conds {
+		switch cond.field {
+		case "path":
+			if !cond.matchString(filepath.ToSlash(result.FilePath)) {
+				return false
+			}
+		case "content":
+			if cond.op != "MATCH" && !cond.matchString(result.Content) {
+				return false
+			}
+		case "line":
+			if (result.LineNum == cond.line) != (cond.op == "=") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// intersectSorted returns the values present in both ascending slices.
+func intersectSorted(a, b []int) []int {
+	var out []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// matchedByAll reports whether every query has a result in the file.
+func matchedByAll(filePath string, queries []string, filesByQuery map[string]map[string]bool) bool {
+	for _, query := range queries {
+		if !filesByQuery[strings.ToLower(query)][filePath] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newStoringApp returns an App with a temporary data directory and the
+// PersistResults setting on.
+func newStoringApp(t *testing.T) *App {
+	t.Helper()
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	settings := defaultSettings()
+	settings.PersistResults = true
+	if _, err := app.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	return app
+}
+
+// TestResultStorePersistsSearches verifies that completed searches are
+// stored when PersistResults is on, listed newest first, and survive a
+// restart.
+func TestResultStorePersistsSearches(t *testing.T) {
+	app := newStoringApp(t)
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "a.go"), []byte("alpha\nbeta\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+	for _, query := range []string{"alpha", "beta"} {
+		if _, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: query, SearchSubdirs: true}); err != nil {
+			t.Fatalf("SearchWithProgress(%q) failed: %v", query, err)
+		}
+	}
+
+	reloaded := NewApp()
+	reloaded.dataDir = app.dataDir
+	searches, err := reloaded.ListStoredSearches()
+	if err != nil {
+		t.Fatalf("ListStoredSearches failed: %v", err)
+	}
+	if len(searches) != 2 || searches[0].Request.Query != "beta" || searches[1].ResultCount != 1 {
+		t.Fatalf("expected beta then alpha with one result each, got %+v", searches)
+	}
+
+	if err := reloaded.DeleteStoredSearch(searches[0].ID); err != nil {
+		t.Fatalf("DeleteStoredSearch failed: %v", err)
+	}
+	if err := reloaded.DeleteStoredSearch(searches[0].ID); err == nil {
+		t.Error("expected deleting a missing search to fail")
+	}
+	if _, err := os.Stat(filepath.Join(app.dataDir, storedResultsFileName(searches[0].ID))); !os.IsNotExist(err) {
+		t.Errorf("expected the results file to be removed, got %v", err)
+	}
+}
+
+// TestResultStoreNotPersistedByDefault verifies that nothing is written
+// without the PersistResults setting.
+func TestResultStoreNotPersistedByDefault(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "a.go"), []byte("alpha\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+	if _, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: "alpha"}); err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if searches, err := app.ListStoredSearches(); err != nil || len(searches) != 0 {
+		t.Errorf("expected no stored searches, got %+v (err %v)", searches, err)
+	}
+}
+
+// TestQueryResultStore verifies filtering by path, line, and full-text
+// content, and the cross-search "files matched by both" query.
+func TestQueryResultStore(t *testing.T) {
+	app := newStoringApp(t)
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"both.go":   "func openFile() {} // TODO\nreturn err\n",
+		"todo.go":   "// TODO later\n",
+		"errors.md": "return err from handler\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+	for _, query := range []string{"TODO", "return err"} {
+		if _, err := app.SearchWithProgress(SearchRequest{Directory: tempDir, Query: query, SearchSubdirs: true}); err != nil {
+			t.Fatalf("SearchWithProgress(%q) failed: %v", query, err)
+		}
+	}
+
+	tests := []struct {
+		filter string
+		files  []string
+		count  int
+	}{
+		{"", []string{"both.go", "errors.md", "todo.go"}, 4},
+		{"query = 'todo' AND path LIKE '%.go'", []string{"both.go", "todo.go"}, 2},
+		{"content MATCH 'open*'", []string{"both.go"}, 1},
+		{"content MATCH 'return handler'", []string{"errors.md"}, 1},
+		{"query = 'TODO' AND query = 'return err'", []string{"both.go"}, 2},
+		{"path LIKE '%both.go' AND line = 2", []string{"both.go"}, 1},
+	}
+	for _, tt := range tests {
+		got, err := app.QueryResultStore(tt.filter)
+		if err != nil {
+			t.Errorf("QueryResultStore(%q) failed: %v", tt.filter, err)
+			continue
+		}
+		var names []string
+		for _, f := range got.Files {
+			names = append(names, filepath.Base(f))
+		}
+		if len(got.Results) != tt.count || len(names) != len(tt.files) {
+			t.Errorf("QueryResultStore(%q): expected %d results in %v, got %d in %v", tt.filter, tt.count, tt.files, len(got.Results), names)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.files[i] {
+				t.Errorf("QueryResultStore(%q): expected files %v, got %v", tt.filter, tt.files, names)
+				break
+			}
+		}
+	}
+}
+
+// TestParseStoreFilterErrors verifies that malformed filters are rejected
+// with INVALID_STORE_FILTER.
+func TestParseStoreFilterErrors(t *testing.T) {
+	for _, filter := range []string{
+		"size = 3",
+		"path MATCH 'x'",
+		"line = 'two'",
+		"query = 'a' OR query = 'b'",
+		"query = 'unterminated",
+		"path LIKE",
+		"content MATCH '***'",
+	} {
+		_, err := parseStoreFilter(filter)
+		if appErr, ok := err.(*AppError); !ok || appErr.Code != ErrCodeInvalidStoreFilter {
+			t.Errorf("parseStoreFilter(%q): expected %s, got %v", filter, ErrCodeInvalidStoreFilter, err)
+		}
+	}
+}
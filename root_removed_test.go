@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// TestProcessFileVanishedFile verifies that a file deleted after collection
+// is counted without stopping the search while its directory still exists.
+func TestProcessFileVanishedFile(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := SearchRequest{Directory: tempDir, Query: "needle", MaxResults: 1000}
+	meta := fileMeta{absPath: filepath.Join(tempDir, "gone.txt"), size: 10}
+	state := &SearchState{}
+	if path, _ := app.processFile(ctx, meta, regexp.MustCompile("needle"), req, state, new(int32), cancel); path != "" {
+		t.Errorf("expected the missing file to be skipped, got %q", path)
+	}
+	if state.vanishedFiles != 1 || state.rootRemoved != 0 {
+		t.Errorf("expected 1 vanished file and the root present, got %d and %d", state.vanishedFiles, state.rootRemoved)
+	}
+	if ctx.Err() != nil {
+		t.Error("expected the search to keep running")
+	}
+}
+
+// TestProcessFilesRootRemoved verifies that removing the search directory
+// after collection stops the workers, flags the root as removed, and still
+// counts every file the workers picked up as processed.
+func TestProcessFilesRootRemoved(t *testing.T) {
+	app := NewApp()
+	root := filepath.Join(t.TempDir(), "root")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("creating root: %v", err)
+	}
+	for _, name := range []string{"a.txt", "b.txt", filepath.Join("sub", "c.txt")} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("needle\n"), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	req := SearchRequest{Directory: root, Query: "needle", SearchSubdirs: true, MaxResults: 1000, MaxFileSize: 1024 * 1024}
+	pattern := regexp.MustCompile("needle")
+	files, err := app.collectFilesToProcess(req, pattern, root+string(filepath.Separator))
+	if err != nil {
+		t.Fatalf("collectFilesToProcess failed: %v", err)
+	}
+	if err := os.RemoveAll(root); err != nil {
+		t.Fatalf("removing root: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resultsChan, state := app.processFilesWithWorkers(ctx, cancel, "", files, req, pattern, len(files))
+	for range resultsChan {
+		t.Error("expected no results from a removed directory")
+	}
+	if state.rootRemoved != 1 {
+		t.Error("expected the root to be flagged as removed")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected the search to be cancelled")
+	}
+	if state.vanishedFiles < 1 || state.processedFiles != state.vanishedFiles {
+		t.Errorf("expected every picked-up file counted as processed, got %d processed and %d vanished", state.processedFiles, state.vanishedFiles)
+	}
+}
+
+// TestFilterResultsIncomplete verifies that a search stored after its root
+// was removed is reported as incomplete.
+func TestFilterResultsIncomplete(t *testing.T) {
+	app := NewApp()
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: "/"}, results: []SearchResult{{FilePath: "/a.txt"}}, incomplete: true})
+
+	view, err := app.FilterResults(id, nil)
+	if err != nil {
+		t.Fatalf("FilterResults failed: %v", err)
+	}
+	if !view.Incomplete || view.TotalResults != 1 {
+		t.Errorf("expected 1 incomplete result, got %+v", view)
+	}
+}
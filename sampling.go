@@ -0,0 +1,100 @@
+package main
+
+import "sort"
+
+// Sampling limits. With Sampling on, the search scans up to
+// SamplingThreshold matches instead of stopping at MaxResults, then returns
+// MaxResults of them spread evenly across the files. The scan budget is
+// what bounds memory and time; the maximum keeps a typo from holding
+// millions of results.
+const (
+	defaultSamplingThreshold = 50000
+	maxSamplingThreshold     = 500000
+)
+
+// fileResults is the results of one file, in line order.
+type fileResults struct {
+	path    string
+	results []SearchResult
+}
+
+// groupByFile groups results by file in order of first appearance and sorts
+// each file's results by line. Workers interleave files, so the incoming
+// order within a file isn't guaranteed.
+func groupByFile(results []SearchResult) []fileResults {
+	index := make(map[string]int)
+	var files []fileResults
+	for _, r := range results {
+		i, ok := index[r.FilePath]
+		if !ok {
+			i = len(files)
+			index[r.FilePath] = i
+			files = append(files, fileResults{path: r.FilePath})
+		}
+		files[i].results = append(files[i].results, r)
+	}
+	for _, f := range files {
+		sort.SliceStable(f.results, func(i, j int) bool { return f.results[i].LineNum < f.results[j].LineNum })
+	}
+	return files
+}
+
+// evenQuotas splits n slots across buckets of the given sizes as evenly as
+// possible: every bucket gets the same share, capped at its size, and slots
+// a small bucket can't use go to the others. When fewer slots than buckets
+// remain, they go to evenly spaced buckets rather than the first ones.
+func evenQuotas(sizes []int, n int) []int {
+	quotas := make([]int, len(sizes))
+	for n > 0 {
+		var open []int
+		for i, size := range sizes {
+			if quotas[i] < size {
+				open = append(open, i)
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+
+		if n < len(open) {
+			for k := 0; k < n; k++ {
+				quotas[open[k*len(open)/n]]++
+			}
+			break
+		}
+
+		share := n / len(open)
+		for _, i := range open {
+			take := share
+			if room := sizes[i] - quotas[i]; take > room {
+				take = room
+			}
+			quotas[i] += take
+			n -= take
+		}
+	}
+	return quotas
+}
+
+// sampleResults picks n results spread evenly across files, and within each
+// file spread evenly across its matches, instead of the first n in walk
+// order. It also returns the number of matches found per file, in order of
+// first appearance. Results are ordered by file, then line.
+func sampleResults(results []SearchResult, n int) ([]SearchResult, []FileMatchCount) {
+	files := groupByFile(results)
+	sizes := make([]int, len(files))
+	counts := make([]FileMatchCount, len(files))
+	for i, f := range files {
+		sizes[i] = len(f.results)
+		counts[i] = FileMatchCount{FilePath: f.path, Count: len(f.results)}
+	}
+
+	quotas := evenQuotas(sizes, n)
+	sample := make([]SearchResult, 0, n)
+	for i, f := range files {
+		for j := 0; j < quotas[i]; j++ {
+			sample = append(sample, f.results[j*len(f.results)/quotas[i]])
+		}
+	}
+	return sample, counts
+}
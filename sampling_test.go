@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEvenQuotas verifies that slots are shared evenly, that slots a small
+// bucket can't use go to the others, and that a shortage of slots is spread
+// across the buckets rather than given to the first ones.
+func TestEvenQuotas(t *testing.T) {
+	cases := []struct {
+		sizes []int
+		n     int
+		want  []int
+	}{
+		{[]int{100, 100, 100}, 30, []int{10, 10, 10}},
+		{[]int{2, 100, 100}, 30, []int{2, 14, 14}},
+		{[]int{5, 5}, 30, []int{5, 5}},
+		{[]int{9, 9, 9, 9}, 2, []int{1, 0, 1, 0}},
+		{[]int{1, 1, 1}, 0, []int{0, 0, 0}},
+	}
+	for _, tc := range cases {
+		got := evenQuotas(tc.sizes, tc.n)
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("evenQuotas(%v, %d) = %v, want %v", tc.sizes, tc.n, got, tc.want)
+		}
+	}
+}
+
+// TestSampleResults verifies that the sample is spread across files and
+// across each file's lines, and that the per-file counts cover every match.
+func TestSampleResults(t *testing.T) {
+	var results []SearchResult
+	for i := 1; i <= 100; i++ {
+		results = append(results, SearchResult{FilePath: "/a.go", LineNum: i})
+	}
+	results = append(results, SearchResult{FilePath: "/b.go", LineNum: 7})
+	for i := 10; i >= 1; i-- {
+		results = append(results, SearchResult{FilePath: "/c.go", LineNum: i})
+	}
+
+	sample, counts := sampleResults(results, 9)
+	if len(sample) != 9 {
+		t.Fatalf("expected 9 sampled results, got %d", len(sample))
+	}
+	if fmt.Sprint(counts) != "[{/a.go 100} {/b.go 1} {/c.go 10}]" {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	var lines []string
+	for _, r := range sample {
+		lines = append(lines, fmt.Sprintf("%s:%d", filepath.Base(r.FilePath), r.LineNum))
+	}
+	want := "a.go:1 a.go:26 a.go:51 a.go:76 b.go:7 c.go:1 c.go:3 c.go:6 c.go:8"
+	if got := strings.Join(lines, " "); got != want {
+		t.Errorf("sample = %s, want %s", got, want)
+	}
+}
+
+// TestSearchWithSampling verifies that a sampled search returns matches
+// from every file instead of only the first ones walked, and that
+// FilterResults reports the full per-file counts.
+func TestSearchWithSampling(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	lines := strings.Repeat("needle\n", 50)
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(lines), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:     tempDir,
+		Query:         "needle",
+		SearchSubdirs: true,
+		MaxResults:    20,
+		Sampling:      true,
+	})
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 20 {
+		t.Fatalf("expected 20 sampled results, got %d", len(results))
+	}
+	perFile := make(map[string]int)
+	for _, r := range results {
+		perFile[filepath.Base(r.FilePath)]++
+	}
+	for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+		if perFile[name] != 5 {
+			t.Errorf("expected 5 results from %s, got %d", name, perFile[name])
+		}
+	}
+
+	view, err := app.FilterResults("search-1", []string{"d.txt"})
+	if err != nil {
+		t.Fatalf("FilterResults failed: %v", err)
+	}
+	if !view.Sampled || view.TotalMatches != 150 || view.TotalResults != 15 || view.HiddenFiles != 1 {
+		t.Errorf("unexpected sampled view %+v", view)
+	}
+	for _, g := range view.Groups {
+		if g.MatchCount != 50 || g.Count != 5 {
+			t.Errorf("expected 5 of 50 matches in %s, got %d of %d", g.FilePath, g.Count, g.MatchCount)
+		}
+	}
+}
+
+// TestSampledGroupsKeepUnsampledFiles verifies that files without a single
+// sampled result still appear in a sampled view with their match count.
+func TestSampledGroupsKeepUnsampledFiles(t *testing.T) {
+	app := NewApp()
+	var results []SearchResult
+	for i := 0; i < 4; i++ {
+		results = append(results, SearchResult{FilePath: fmt.Sprintf("/f%d.go", i), LineNum: 1})
+	}
+	sample, counts := sampleResults(results, 2)
+
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: "/"}, results: sample, counts: counts})
+	view, err := app.FilterResults(id, nil)
+	if err != nil {
+		t.Fatalf("FilterResults failed: %v", err)
+	}
+	if view.TotalFiles != 4 || view.TotalResults != 2 || view.TotalMatches != 4 {
+		t.Errorf("unexpected view %+v", view)
+	}
+	if view.Groups[1].Count != 0 || view.Groups[1].MatchCount != 1 || view.Groups[1].Results == nil {
+		t.Errorf("expected an empty group for the unsampled file, got %+v", view.Groups[1])
+	}
+}
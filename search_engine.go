@@ -1,10 +1,9 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"fmt"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,6 +18,12 @@ import (
 
 // SearchWithProgress performs a search and emits progress updates to the frontend
 func (a *App) SearchWithProgress(req SearchRequest) ([]SearchResult, error) {
+	return a.search(req)
+}
+
+// search runs a search with progress sent as search-progress events and
+// the results also passed to the extra sinks.
+func (a *App) search(req SearchRequest, extra ...ResultSink) ([]SearchResult, error) {
 	// Log the start of the search operation
 	searchStart := time.Now()
 	a.logInfo("Starting search operation", logrus.Fields{
@@ -71,115 +76,93 @@ func (a *App) SearchWithProgress(req SearchRequest) ([]SearchResult, error) {
 		a.logError("Failed to get absolute path for directory", err, logrus.Fields{
 			"directory": req.Directory,
 		})
-		return nil, fmt.Errorf("failed to get absolute path for directory: %v", err)
+		return nil, newAppError(ErrCodeDirectoryInvalid, err)
 	}
 	baseDir := filepath.Clean(absDir) + string(filepath.Separator)
 
-	// Collect all files to process based on search criteria
-	a.logDebug("Collecting files to process", logrus.Fields{
-		"directory": req.Directory,
-	})
-	filesToProcess, err := a.collectFilesToProcess(req, pattern, baseDir)
-	if err != nil {
-		a.logError("Failed to collect files to process", err, logrus.Fields{
-			"directory": req.Directory,
+	if req.Fuzziness > 0 {
+		a.logInfo("Fuzzy search: every line goes through the bitap matcher, expect it to be slower", logrus.Fields{
 			"query":     req.Query,
+			"fuzziness": req.Fuzziness,
 		})
-		return nil, err
 	}
 
-	totalFiles := len(filesToProcess)
-	a.logInfo("File collection completed", logrus.Fields{
-		"totalFiles": totalFiles,
-		"directory":  req.Directory,
-	})
-
-	// Emit initial progress using the SearchProgress struct
-	initialProgress := &SearchProgress{
-		ProcessedFiles: 0,
-		TotalFiles:     totalFiles,
-		CurrentFile:    "",
-		ResultsCount:   0,
-		Status:         "started",
+	// Every match also goes to the result log, if there is one; only the
+	// first MaxResults stay in memory.
+	var resultLog *ndjsonSink
+	var resultLogFile *os.File
+	if req.ResultLogPath != "" {
+		resultLogFile, resultLog, err = a.openResultLog(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resultLogFile.Close()
+		extra = append(extra, resultLog)
 	}
 
-	a.logInfo("Sending initial search progress", logrus.Fields{
-		"status":       "started",
-		"totalFiles":   totalFiles,
-		"currentFile":  "",
-		"resultsCount": 0,
-	})
-
-	a.safeEmitEvent("search-progress", initialProgress)
-
-	// Create search context with cancellation
-	ctx, cancel := a.createSearchContext()
-	defer func() {
-		// Clear the cancel function when the search completes
-		a.clearSearchCancel()
-		cancel()
-	}()
-
-	// Log search start
-	a.logInfo("Starting file processing with worker pool", logrus.Fields{
-		"totalFiles": totalFiles,
-		"workers":    numCPU(),
-		"maxResults": req.MaxResults,
-	})
+	// Register the search so CancelSearch can stop it
+	searchID := a.newSearchID()
+	session := a.startSearchSession(searchID)
+	defer a.endSearchSession(session)
+	ctx, cancel := session.ctx, session.cancel
 
-	// Process files using worker pool
-	resultsChan, searchState := a.processFilesWithWorkers(ctx, cancel, filesToProcess, req, pattern, totalFiles)
-
-	// Collect results
-	var results []SearchResult
-	for result := range resultsChan {
-		results = append(results, result)
-
-		// Check if we've reached the result limit
-		if len(results) >= req.MaxResults {
-			a.logInfo("Reached maximum results limit, stopping search", logrus.Fields{
-				"resultsCount": len(results),
-				"maxResults":   req.MaxResults,
-			})
-			// The context is already cancelled by the workers, but we'll do it again just in case
-			cancel()
-			// Trim results to max results if somehow we got more
-			if len(results) > req.MaxResults {
-				results = results[:req.MaxResults]
-			}
-			break
-		}
+	hooks := a.startSearchHooks(searchID, req)
+	if sink := hooks.thresholdSink(); sink != nil {
+		extra = append(extra, sink)
 	}
-
-	// Emit final progress using the SearchProgress struct
-	finalProgress := &SearchProgress{
-		ProcessedFiles: int(atomic.LoadInt32(&searchState.processedFiles)),
-		TotalFiles:     totalFiles,
-		CurrentFile:    "",
-		ResultsCount:   len(results),
-		Status:         "completed",
+	out, err := a.newSearcher(extra...).run(ctx, cancel, searchID, req, pattern, baseDir)
+	hooks.finish(out, err)
+	if err != nil {
+		return nil, err
+	}
+	results, searchState := out.results, out.state
+	if resultLog != nil {
+		if err := resultLog.Err(); err != nil {
+			return results, newAppError(ErrCodeResultsExportFailed, req.ResultLogPath, err)
+		}
+		if err := resultLogFile.Close(); err != nil {
+			return results, newAppError(ErrCodeResultsExportFailed, req.ResultLogPath, err)
+		}
+		a.logInfo("Search results written to result log", logrus.Fields{
+			"resultLogPath": req.ResultLogPath,
+			"totalMatches":  out.totalMatches,
+		})
 	}
 
-	a.logInfo("Sending final search progress", logrus.Fields{
-		"status":         "completed",
-		"processedFiles": int(atomic.LoadInt32(&searchState.processedFiles)),
-		"totalFiles":     totalFiles,
-		"resultsCount":   len(results),
+	a.storeSearch(searchRecord{
+		id:         searchID,
+		request:    req,
+		results:    results,
+		counts:     out.fileCounts,
+		incomplete: out.rootRemoved,
 	})
-
-	a.safeEmitEvent("search-progress", finalProgress)
+	a.persistSearch(searchRecord{
+		request:    req,
+		results:    results,
+		incomplete: out.rootRemoved,
+	}, out.totalMatches)
 
 	// Log search completion
 	duration := time.Since(searchStart)
+	a.notifySearchFinished(out.cancelled, len(results), duration)
 	a.logInfo("Search operation completed", logrus.Fields{
-		"resultsCount":    len(results),
-		"processedFiles":  int(atomic.LoadInt32(&searchState.processedFiles)),
-		"totalFiles":      totalFiles,
-		"durationSeconds": duration.Seconds(),
-		"directory":       req.Directory,
-		"query":           req.Query,
+		"resultsCount":     len(results),
+		"processedFiles":   int(atomic.LoadInt32(&searchState.processedFiles)),
+		"totalFiles":       out.totalFiles,
+		"generatedSkipped": int(atomic.LoadInt32(&searchState.generatedSkipped)),
+		"vanishedFiles":    int(atomic.LoadInt32(&searchState.vanishedFiles)),
+		"lockedFiles":      int(atomic.LoadInt32(&searchState.lockedFiles)),
+		"unreadableFiles":  int(atomic.LoadInt32(&searchState.unreadableFiles)),
+		"durationSeconds":  duration.Seconds(),
+		"directory":        req.Directory,
+		"query":            req.Query,
 	})
 
+	// The partial results stay available through FilterResults under the
+	// search ID; Go callers get them alongside the error.
+	if out.rootRemoved {
+		return results, newAppError(ErrCodeSearchRootRemoved, req.Directory)
+	}
 	return results, nil
 }
 
@@ -188,8 +171,10 @@ func (a *App) SearchWithProgress(req SearchRequest) ([]SearchResult, error) {
 // and size are computed once in collectFilesToProcess (file_collection.go);
 // reusing them avoids a second os.Stat and filepath.Abs per file.
 type fileMeta struct {
-	absPath string
-	size    int64
+	absPath     string
+	size        int64
+	checkBinary bool            // Binary probe deferred to the worker (slow-FS mode)
+	content     ContentProvider // Where the content is read from; nil means the working tree
 }
 
 // binaryCheckBufPool reuses the 512-byte scratch buffer used by the binary
@@ -214,28 +199,39 @@ const streamContextLines = 2
 // Context lines (up to streamContextLines before and after each match) are captured
 // the same way as the small-file path: a rolling buffer holds recent lines for
 // ContextBefore, and matches stay "pending" until enough following lines are read
-// to fill ContextAfter.
-func (a *App) processFileLineByLine(ctx context.Context, filePath string, pattern *regexp.Regexp, maxResults int) ([]SearchResult, error) {
+// to fill ContextAfter. bufferSize is the longest line the scanner accepts;
+// 0 means defaultScannerBufferSize.
+func (a *App) processFileLineByLine(ctx context.Context, filePath string, pattern lineMatcher, maxResults int, bufferSize int) ([]SearchResult, error) {
+	return a.processContentLineByLine(ctx, workingTree{}, filePath, pattern, nil, maxResults, bufferSize)
+}
+
+// processContentLineByLine is processFileLineByLine for a file read through
+// any ContentProvider, matching only the lines in scope (nil for all).
+func (a *App) processContentLineByLine(ctx context.Context, provider ContentProvider, filePath string, pattern lineMatcher, scope *lineScope, maxResults int, bufferSize int) ([]SearchResult, error) {
 	a.logDebug("Starting line-by-line file processing", logrus.Fields{
 		"filePath":   filePath,
 		"maxResults": maxResults,
 	})
 
-	file, err := os.Open(filePath)
+	file, err := provider.Open(filePath)
 	if err != nil {
-		a.logError("Failed to open file for line-by-line processing", err, logrus.Fields{
-			"filePath": filePath,
-		})
+		// A file deleted mid-search is routine; the caller counts it.
+		if !errors.Is(err, fs.ErrNotExist) {
+			a.logError("Failed to open file for line-by-line processing", err, logrus.Fields{
+				"filePath": filePath,
+			})
+		}
 		return nil, err
 	}
 	defer file.Close()
 
 	var results []SearchResult
-	scanner := bufio.NewScanner(file)
-
-	// Set a larger buffer for very long lines (1MB)
-	buf := make([]byte, 1024*1024)
-	scanner.Buffer(buf, 1024*1024)
+	// Set a larger buffer for very long lines (1MB unless configured)
+	if bufferSize <= 0 {
+		bufferSize = defaultScannerBufferSize
+	}
+	text, _ := newTextReader(file)
+	scanner := newLineScanner(text, bufferSize)
 
 	// prev holds up to streamContextLines preceding lines for ContextBefore.
 	prev := make([]string, 0, streamContextLines)
@@ -249,6 +245,7 @@ func (a *App) processFileLineByLine(ctx context.Context, filePath string, patter
 	lineNum := 1
 	linesProcessed := 0
 	for scanner.Scan() {
+		inScope, scopeDone := scope.next(lineNum, scanner.Bytes())
 		line := scanner.Text()
 
 		// Fill ContextAfter for matches found on earlier lines.
@@ -265,7 +262,7 @@ func (a *App) processFileLineByLine(ctx context.Context, filePath string, patter
 		}
 
 		// Record a new match (unless we've already hit the result limit).
-		if len(results) < maxResults && pattern.MatchString(line) {
+		if len(results) < maxResults && inScope && pattern.MatchString(line) {
 			contextBefore := make([]string, len(prev))
 			copy(contextBefore, prev)
 			results = append(results, SearchResult{
@@ -275,6 +272,8 @@ func (a *App) processFileLineByLine(ctx context.Context, filePath string, patter
 				MatchedText:   pattern.FindString(line),
 				ContextBefore: contextBefore,
 				ContextAfter:  []string{},
+				Captures:      lineCaptures(pattern, line),
+				Spans:         matchSpans(pattern, line),
 			})
 			pending = append(pending, pendingMatch{idx: len(results) - 1, remaining: streamContextLines})
 		}
@@ -288,8 +287,9 @@ func (a *App) processFileLineByLine(ctx context.Context, filePath string, patter
 		lineNum++
 		linesProcessed++
 
-		// Stop once the result limit is reached and every match has its trailing context.
-		if len(results) >= maxResults && len(pending) == 0 {
+		// Stop once the result limit is reached, or no later line is in
+		// scope, and every match has its trailing context.
+		if (len(results) >= maxResults || scopeDone) && len(pending) == 0 {
 			break
 		}
 
@@ -322,10 +322,55 @@ func (a *App) processFileLineByLine(ctx context.Context, filePath string, patter
 	return results, nil
 }
 
-// streamingThreshold is the file size (in bytes) above which files are processed
-// line-by-line instead of being read entirely into memory.
+// streamingThreshold is the default file size (in bytes) above which files
+// are processed line-by-line instead of being read entirely into memory.
+// Settings.StreamingThreshold and SearchRequest.StreamingThreshold override it.
 const streamingThreshold = 1024 * 1024 // 1MB
 
+// defaultScannerBufferSize is the default maximum line length, in bytes, of
+// the line-by-line scanner. A longer line ends the scan of that file.
+const defaultScannerBufferSize = 1024 * 1024 // 1MB
+
+// Bounds for the configurable streaming threshold and scanner buffer.
+// Below 64KB the scanner is no better than bufio's default; above the
+// maxima a single file or line could take a large share of memory on every
+// worker at once.
+const (
+	minStreamingThreshold = 64 * 1024
+	maxStreamingThreshold = 256 * 1024 * 1024
+	minScannerBufferSize  = 64 * 1024
+	maxScannerBufferSize  = 64 * 1024 * 1024
+)
+
+// clampInt64 limits v to [lo, hi].
+func clampInt64(v, lo, hi int64) int64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// searchStreamingThreshold returns the streaming threshold for the request,
+// falling back to the default when it is unset.
+func searchStreamingThreshold(req SearchRequest) int64 {
+	if req.StreamingThreshold <= 0 {
+		return streamingThreshold
+	}
+	return req.StreamingThreshold
+}
+
+// searchScannerBufferSize returns the scanner buffer size for the request,
+// falling back to the default when it is unset.
+func searchScannerBufferSize(req SearchRequest) int {
+	if req.ScannerBufferSize <= 0 {
+		return defaultScannerBufferSize
+	}
+	return req.ScannerBufferSize
+}
+
 // Helper function to get number of CPUs
 func numCPU() int {
 	n := runtime.NumCPU()
@@ -335,91 +380,29 @@ func numCPU() int {
 	return n
 }
 
-// createSearchContext creates a context for the search operation with associated cancellation
-func (a *App) createSearchContext() (context.Context, context.CancelFunc) {
-	ctx, cancel := context.WithCancel(context.Background())
-	// Store the cancel function so it can be called externally to cancel the search
-	a.setSearchCancel(cancel)
-	return ctx, cancel
-}
-
-// processFilesWithWorkers processes files using a worker pool and returns a channel of results
-func (a *App) processFilesWithWorkers(ctx context.Context, cancel context.CancelFunc, filesToProcess []fileMeta, req SearchRequest, pattern *regexp.Regexp, totalFiles int) (chan SearchResult, *SearchState) {
-	numWorkers := numCPU()
-	if len(filesToProcess) < numWorkers {
-		numWorkers = len(filesToProcess)
-	}
-
-	a.logDebug("Initializing worker pool", logrus.Fields{
-		"numWorkers":         numWorkers,
-		"totalFiles":         totalFiles,
-		"maxResults":         req.MaxResults,
-		"streamingThreshold": int64(streamingThreshold),
-	})
-
-	filesChan := make(chan fileMeta, len(filesToProcess))
-	resultsChan := make(chan SearchResult, 100)
-
-	searchState := &SearchState{}
-	var searchCancelled int32
-
-	var wg sync.WaitGroup
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		workerID := i
-		go func() {
-			defer wg.Done()
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case meta, ok := <-filesChan:
-					if !ok {
-						return
-					}
-
-					if !a.workerShouldContinue(ctx, &searchCancelled, cancel, &searchState.resultsCount, req.MaxResults, workerID) {
-						return
-					}
-
-					absFilePath, fileResults := a.processFile(ctx, meta, pattern, req, searchState, &searchCancelled, cancel)
-					if absFilePath == "" {
-						continue
-					}
-
-					// Send results and emit progress
-					a.emitFileResults(ctx, fileResults, resultsChan, searchState, &searchCancelled, cancel, req.MaxResults)
-					a.emitFileProgress(searchState, totalFiles, absFilePath)
-				}
-			}
-		}()
+// slowFSWorkers is the worker count used in slow-FS mode. Network mounts
+// serve parallel opens poorly, so two in-flight files keep the pipe busy
+// without flooding the server.
+const slowFSWorkers = 2
+
+// slowFSProgressInterval is how many files a slow-FS search processes
+// between progress events. Per-file events are dropped in that mode: each one
+// is a round trip to the webview, and on a slow mount they add up to a
+// visible share of the search time.
+const slowFSProgressInterval = 50
+
+// searchWorkers returns the worker pool size for the request.
+func searchWorkers(req SearchRequest) int {
+	if req.SlowFS {
+		return slowFSWorkers
 	}
-
-	// Send files to channel
-	go func() {
-		defer close(filesChan)
-		for _, file := range filesToProcess {
-			select {
-			case <-ctx.Done():
-				return
-			case filesChan <- file:
-			}
-		}
-	}()
-
-	// Close results when all workers finish
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	return resultsChan, searchState
+	return numCPU()
 }
 
 // workerShouldContinue checks whether the worker should stop (context cancelled
 // or max results reached). If max results is reached, it cancels the context
 // atomically to prevent duplicate cancellations.
-func (a *App) workerShouldContinue(ctx context.Context, searchCancelled *int32, cancel context.CancelFunc, resultsCount *int32, maxResults int, workerID int) bool {
+func workerShouldContinue(ctx context.Context, searchCancelled *int32, cancel context.CancelFunc, resultsCount *int32, maxResults int) bool {
 	if int(atomic.LoadInt32(resultsCount)) >= maxResults {
 		if atomic.CompareAndSwapInt32(searchCancelled, 0, 1) {
 			cancel()
@@ -449,19 +432,66 @@ func (a *App) workerShouldContinue(ctx context.Context, searchCancelled *int32,
 // search it regardless.
 func (a *App) processFile(ctx context.Context, meta fileMeta, pattern *regexp.Regexp, req SearchRequest, searchState *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult) {
 	absFilePath := meta.absPath
-
-	if meta.size > int64(streamingThreshold) {
-		results, procErr := a.processFileLineByLine(ctx, absFilePath, pattern, req.MaxResults-int(atomic.LoadInt32(&searchState.resultsCount)))
+	provider := meta.contentProvider()
+	matcher := searchLineMatcher(req, pattern)
+
+	if meta.size > searchStreamingThreshold(req) {
+		if req.SkipGenerated || meta.checkBinary {
+			var head []byte
+			err := retryIfLocked(ctx, req, func() (err error) {
+				head, err = readFileHead(provider, absFilePath)
+				return err
+			})
+			if err != nil {
+				a.skipUnreadableFile(absFilePath, err, req, searchState, cancel)
+				return "", nil
+			}
+			if meta.checkBinary && a.isBinary(head) {
+				a.logDebug("Skipping binary file", logrus.Fields{"filePath": absFilePath})
+				return "", nil
+			}
+			if req.SkipGenerated && looksGenerated(head) {
+				a.logDebug("Skipping generated file", logrus.Fields{"filePath": absFilePath})
+				atomic.AddInt32(&searchState.generatedSkipped, 1)
+				return "", nil
+			}
+		}
+		var results []SearchResult
+		procErr := retryIfLocked(ctx, req, func() (err error) {
+			results, err = a.processContentLineByLine(ctx, provider, absFilePath, matcher, newLineScope(req), req.MaxResults-int(atomic.LoadInt32(&searchState.resultsCount)), searchScannerBufferSize(req))
+			return err
+		})
 		if procErr != nil {
-			a.logDebug("Error processing file with streaming", logrus.Fields{"filePath": absFilePath, "error": procErr.Error()})
+			a.skipUnreadableFile(absFilePath, procErr, req, searchState, cancel)
 			return "", nil
 		}
 		return absFilePath, results
 	}
 
-	content, err := os.ReadFile(absFilePath)
+	var content []byte
+	err := retryIfLocked(ctx, req, func() (err error) {
+		content, err = readAllContent(provider, absFilePath)
+		return err
+	})
 	if err != nil {
-		a.logDebug("Skipping file due to read error", logrus.Fields{"filePath": absFilePath, "error": err.Error()})
+		a.skipUnreadableFile(absFilePath, err, req, searchState, cancel)
+		return "", nil
+	}
+
+	// Lines are matched as UTF-8 without a byte order mark, so UTF-16 files
+	// are searchable and a BOM never sticks to the first line.
+	content, _ = decodeText(content)
+
+	// In slow-FS mode the collection phase skipped the binary probe so the
+	// file is only opened once; the check runs here on the bytes just read.
+	if meta.checkBinary && a.isBinary(content) {
+		a.logDebug("Skipping binary file", logrus.Fields{"filePath": absFilePath})
+		return "", nil
+	}
+
+	if req.SkipGenerated && looksGenerated(content) {
+		a.logDebug("Skipping generated file", logrus.Fields{"filePath": absFilePath})
+		atomic.AddInt32(&searchState.generatedSkipped, 1)
 		return "", nil
 	}
 
@@ -476,18 +506,20 @@ func (a *App) processFile(ctx context.Context, meta fileMeta, pattern *regexp.Re
 	// 900KB file with 15k lines that's ~16k allocations. bytes.Split keeps
 	// the line slices as views into the original []byte, and we only convert
 	// a line to string when we need to put it on a SearchResult field.
-	lines := bytes.Split(content, []byte("\n"))
+	lines := splitLines(content)
 	var fileResults []SearchResult
+	scope := newLineScope(req)
 
 	for i, line := range lines {
-		if !a.workerShouldContinue(ctx, searchCancelled, cancel, &searchState.resultsCount, req.MaxResults, -1) {
+		if !workerShouldContinue(ctx, searchCancelled, cancel, &searchState.resultsCount, req.MaxResults) {
 			break
 		}
 
-		if pattern.Match(line) {
+		inScope, scopeDone := scope.next(i+1, line)
+		if inScope && matcher.Match(line) {
 			contextBefore := safeContextLinesBytes(lines, i-2, i)
 			contextAfter := safeContextLinesBytes(lines, i+1, i+3)
-			matchedText := pattern.Find(line)
+			matchedText := matcher.Find(line)
 
 			fileResults = append(fileResults, SearchResult{
 				FilePath:      absFilePath,
@@ -496,16 +528,95 @@ func (a *App) processFile(ctx context.Context, meta fileMeta, pattern *regexp.Re
 				MatchedText:   string(matchedText),
 				ContextBefore: bytesToStrings(contextBefore),
 				ContextAfter:  bytesToStrings(contextAfter),
+				Captures:      lineCaptures(matcher, string(line)),
+				Spans:         matchSpans(matcher, string(line)),
 			})
 		}
+		if scopeDone {
+			break
+		}
 	}
 
 	return absFilePath, fileResults
 }
 
+// lockedFileRetryDelay is how long a worker waits before its single retry
+// of a file another process has locked, when RetryLocked is set. Antivirus
+// scans and editor saves usually release a file well within it.
+const lockedFileRetryDelay = 250 * time.Millisecond
+
+// retryIfLocked runs read and, with RetryLocked, runs it once more after
+// lockedFileRetryDelay if it failed because the file was locked.
+func retryIfLocked(ctx context.Context, req SearchRequest, read func() error) error {
+	err := read()
+	if err == nil || !req.RetryLocked || !isLockedFileError(err) {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return err
+	case <-time.After(lockedFileRetryDelay):
+	}
+	return read()
+}
+
+// skipUnreadableFile records a file the worker couldn't read in the skip
+// statistics. A file that was deleted since collection (a build clean, a
+// branch switch) also triggers a check of whether the search directory
+// itself is gone; if it is, the search is cancelled rather than letting
+// every remaining file fail the same way.
+func (a *App) skipUnreadableFile(path string, err error, req SearchRequest, searchState *SearchState, cancel context.CancelFunc) {
+	if isLockedFileError(err) {
+		atomic.AddInt32(&searchState.lockedFiles, 1)
+		a.logDebug("Skipping locked file", logrus.Fields{"filePath": path, "error": err.Error()})
+		return
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		atomic.AddInt32(&searchState.unreadableFiles, 1)
+		a.logDebug("Skipping file due to read error", logrus.Fields{"filePath": path, "error": err.Error()})
+		return
+	}
+
+	atomic.AddInt32(&searchState.vanishedFiles, 1)
+	if atomic.LoadInt32(&searchState.rootRemoved) != 0 {
+		return
+	}
+	if info, statErr := os.Stat(toLongPath(req.Directory)); statErr == nil && info.IsDir() {
+		return
+	}
+	if atomic.CompareAndSwapInt32(&searchState.rootRemoved, 0, 1) {
+		a.logWarn("Search directory was removed, stopping search", logrus.Fields{
+			"directory": req.Directory,
+		})
+		cancel()
+	}
+}
+
+// skipStats returns the skip counters for the completed progress event.
+func (s *SearchState) skipStats() *SkipStats {
+	return &SkipStats{
+		Generated:  int(atomic.LoadInt32(&s.generatedSkipped)),
+		Vanished:   int(atomic.LoadInt32(&s.vanishedFiles)),
+		Locked:     int(atomic.LoadInt32(&s.lockedFiles)),
+		Unreadable: int(atomic.LoadInt32(&s.unreadableFiles)),
+	}
+}
+
+// processFilesWithWorkers runs the files through the App's searcher worker
+// pool; see searcher.processFiles.
+func (a *App) processFilesWithWorkers(ctx context.Context, cancel context.CancelFunc, searchID string, filesToProcess []fileMeta, req SearchRequest, pattern *regexp.Regexp, totalFiles int) (chan SearchResult, *SearchState) {
+	return a.newSearcher().processFiles(ctx, cancel, searchID, filesToProcess, req, pattern, totalFiles)
+}
+
+// emitFileProgress counts a processed file and emits a progress event; see
+// searcher.fileProgress.
+func (a *App) emitFileProgress(searchID string, searchState *SearchState, totalFiles int, absFilePath string, slowFS bool) {
+	a.newSearcher().fileProgress(searchID, searchState, totalFiles, absFilePath, slowFS)
+}
+
 // emitFileResults sends each result from processing a file to the results channel,
 // respecting context cancellation and max results limits.
-func (a *App) emitFileResults(ctx context.Context, fileResults []SearchResult, resultsChan chan<- SearchResult, searchState *SearchState, searchCancelled *int32, cancel context.CancelFunc, maxResults int) {
+func emitFileResults(ctx context.Context, fileResults []SearchResult, resultsChan chan<- SearchResult, searchState *SearchState, searchCancelled *int32, cancel context.CancelFunc, maxResults int) {
 	for _, result := range fileResults {
 		if int(atomic.LoadInt32(&searchState.resultsCount)) >= maxResults {
 			if atomic.CompareAndSwapInt32(searchCancelled, 0, 1) {
@@ -528,19 +639,6 @@ func (a *App) emitFileResults(ctx context.Context, fileResults []SearchResult, r
 	}
 }
 
-// emitFileProgress increments the processed file counter and sends a progress event.
-func (a *App) emitFileProgress(searchState *SearchState, totalFiles int, absFilePath string) {
-	newCount := atomic.AddInt32(&searchState.processedFiles, 1)
-	progressData := &SearchProgress{
-		ProcessedFiles: int(newCount),
-		TotalFiles:     totalFiles,
-		CurrentFile:    absFilePath,
-		ResultsCount:   int(atomic.LoadInt32(&searchState.resultsCount)),
-		Status:         "in-progress",
-	}
-	a.safeEmitEvent("search-progress", progressData)
-}
-
 // safeContextLines returns a slice of lines[start:end] that is safe even when
 // start or end are out of bounds.
 func safeContextLines(lines []string, start, end int) []string {
@@ -590,30 +688,36 @@ func bytesToStrings(lines [][]byte) []string {
 	return out
 }
 
-// CancelSearch cancels any active search operation by calling the cancel function
+// CancelSearch cancels every running search by calling its session's cancel
+// function, and emits a cancelled progress event for each of them
 func (a *App) CancelSearch() error {
-	if a.cancelActiveSearch() {
-		a.logInfo("Cancelling active search", logrus.Fields{})
-		// Emit cancellation progress event
-		cancelData := &SearchProgress{
-			ProcessedFiles: 0,
-			TotalFiles:     0,
-			CurrentFile:    "",
-			ResultsCount:   0,
-			Status:         "cancelled",
-		}
+	if ids := a.cancelActiveSearches(); len(ids) > 0 {
+		a.logInfo("Cancelling active search", logrus.Fields{"searches": len(ids)})
+		for _, id := range ids {
+			// Emit cancellation progress event
+			cancelData := &SearchProgress{
+				SearchID:       id,
+				ProcessedFiles: 0,
+				TotalFiles:     0,
+				CurrentFile:    "",
+				ResultsCount:   0,
+				Status:         "cancelled",
+			}
 
-		a.logInfo("Sending cancellation progress event", logrus.Fields{
-			"status":         "cancelled",
-			"processedFiles": 0,
-			"totalFiles":     0,
-			"resultsCount":   0,
-		})
-		a.safeEmitEvent("search-progress", cancelData)
+			a.logInfo("Sending cancellation progress event", logrus.Fields{
+				"searchId":       id,
+				"status":         "cancelled",
+				"processedFiles": 0,
+				"totalFiles":     0,
+				"resultsCount":   0,
+			})
+			a.recordProgress(*cancelData)
+			a.safeEmitEvent("search-progress", cancelData)
+		}
 
 		return nil
 	}
 	// If there's no active search to cancel, return an appropriate message
 	a.logDebug("No active search to cancel", logrus.Fields{})
-	return fmt.Errorf("no active search to cancel")
+	return newAppError(ErrCodeNoActiveSearch)
 }
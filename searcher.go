@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Collector lists the files a search reads. searchID names the search,
+// for the progress events of a long collection. baseDir is the clean
+// absolute search directory with a trailing separator. Collect returns
+// ctx's error once ctx is cancelled. The App implementation is
+// collectSearchFiles.
+type Collector interface {
+	Collect(ctx context.Context, searchID string, req SearchRequest, pattern *regexp.Regexp, baseDir string) ([]fileMeta, error)
+}
+
+// Matcher searches one file. It returns the path that was searched, or ""
+// when the file was skipped, and records skips in state. It stops early
+// once state.resultsCount reaches req.MaxResults, cancelling the search the
+// first time (searchCancelled guards against cancelling twice). The App
+// implementation is processFile.
+type Matcher interface {
+	Match(ctx context.Context, meta fileMeta, pattern *regexp.Regexp, req SearchRequest, state *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult)
+}
+
+// searchLogger is the logging the searcher needs. *App implements it.
+type searchLogger interface {
+	logInfo(message string, fields logrus.Fields)
+	logWarn(message string, fields logrus.Fields)
+	logDebug(message string, fields logrus.Fields)
+	logError(message string, err error, fields logrus.Fields)
+}
+
+// searcher runs the search core: collection, the worker pool, result
+// collection and sampling, and progress reporting. It knows nothing about
+// Wails or the App, so other frontends can drive it with their own
+// Collector, Matcher, and ResultSink, and tests can run it with fakes.
+type searcher struct {
+	collector Collector
+	matcher   Matcher
+	sink      ResultSink
+	log       searchLogger
+}
+
+// searchOutcome is what a searcher run produced.
+type searchOutcome struct {
+	results      []SearchResult
+	fileCounts   []FileMatchCount // Per-file match counts when the results were sampled; nil otherwise
+	totalMatches int              // Matches found before sampling
+	totalFiles   int
+	state        *SearchState
+	cancelled    bool // Stopped by cancel before reaching the result limit
+	rootRemoved  bool // Stopped because the search directory disappeared
+}
+
+// invertMatcher turns a Matcher's per-line results into per-file ones for
+// InvertFileMatch: a searched file without matches becomes one result with
+// only its path, and a file with matches yields nothing. Skipped files
+// stay skipped, so every filter still applies.
+type invertMatcher struct{ Matcher }
+
+func (m invertMatcher) Match(ctx context.Context, meta fileMeta, pattern *regexp.Regexp, req SearchRequest, state *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult) {
+	path, results := m.Matcher.Match(ctx, meta, pattern, req, state, searchCancelled, cancel)
+	if path == "" || len(results) > 0 {
+		return path, nil
+	}
+	return path, []SearchResult{{FilePath: path, ContextBefore: []string{}, ContextAfter: []string{}}}
+}
+
+// App adapters for the searcher interfaces.
+type (
+	appCollector struct{ a *App }
+	appMatcher   struct{ a *App }
+)
+
+func (c appCollector) Collect(ctx context.Context, searchID string, req SearchRequest, pattern *regexp.Regexp, baseDir string) ([]fileMeta, error) {
+	return c.a.collectSearchFiles(ctx, searchID, req, pattern, baseDir)
+}
+
+func (m appMatcher) Match(ctx context.Context, meta fileMeta, pattern *regexp.Regexp, req SearchRequest, state *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult) {
+	path, results := m.a.processFile(ctx, m.a.withPlugin(meta), pattern, req, state, searchCancelled, cancel)
+	if path != "" {
+		countFileScanned(meta)
+	}
+	return path, results
+}
+
+// newSearcher returns the searcher SearchWithProgress uses: the App's file
+// collection and matching, with progress sent as search-progress events
+// and results also passed to any extra sinks.
+func (a *App) newSearcher(extra ...ResultSink) *searcher {
+	var sink ResultSink = eventSink{a}
+	if len(extra) > 0 {
+		sink = multiSink(append([]ResultSink{sink}, extra...))
+	}
+	return &searcher{
+		collector: appCollector{a},
+		matcher:   appMatcher{a},
+		sink:      sink,
+		log:       a,
+	}
+}
+
+// run executes a validated request. cancel must cancel ctx; the workers
+// call it once the result limit is reached. searchID is sent on every
+// progress event. If the sink fails to take a result, the
+// search is cancelled and the sink's error returned with the outcome so far.
+func (s *searcher) run(ctx context.Context, cancel context.CancelFunc, searchID string, req SearchRequest, pattern *regexp.Regexp, baseDir string) (searchOutcome, error) {
+	// Collect all files to process based on search criteria
+	s.log.logDebug("Collecting files to process", logrus.Fields{
+		"directory": req.Directory,
+	})
+	// The collecting event marks the walk, which can take a while on large
+	// trees; the walk itself reports collection-progress heartbeats.
+	s.sink.Progress(SearchProgress{
+		SearchID: searchID,
+		Status:   "collecting",
+	})
+	filesToProcess, err := s.collector.Collect(ctx, searchID, req, pattern, baseDir)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled before any file was searched. CancelSearch has already
+		// sent the cancelled event, so there is no completed one.
+		s.log.logInfo("Search cancelled during file collection", logrus.Fields{
+			"directory": req.Directory,
+		})
+		return searchOutcome{state: &SearchState{}, cancelled: true}, nil
+	}
+	if err != nil {
+		s.log.logError("Failed to collect files to process", err, logrus.Fields{
+			"directory": req.Directory,
+			"query":     req.Query,
+		})
+		return searchOutcome{}, err
+	}
+
+	totalFiles := len(filesToProcess)
+	s.log.logInfo("File collection completed", logrus.Fields{
+		"totalFiles": totalFiles,
+		"directory":  req.Directory,
+	})
+
+	if err := checkTreeCost(req, totalFiles); err != nil {
+		s.log.logWarn("Expensive search needs confirmation", logrus.Fields{
+			"query":      req.Query,
+			"totalFiles": totalFiles,
+		})
+		return searchOutcome{}, err
+	}
+
+	// Every event carries the search ID so the frontend can refer to this
+	// search in FilterResults, and overlapping searches don't mix.
+	s.log.logInfo("Sending initial search progress", logrus.Fields{
+		"status":       "started",
+		"totalFiles":   totalFiles,
+		"currentFile":  "",
+		"resultsCount": 0,
+	})
+	s.sink.Progress(SearchProgress{
+		SearchID:   searchID,
+		TotalFiles: totalFiles,
+		Status:     "started",
+	})
+
+	// Log search start
+	s.log.logInfo("Starting file processing with worker pool", logrus.Fields{
+		"totalFiles": totalFiles,
+		"workers":    searchWorkers(req),
+		"maxResults": req.MaxResults,
+		"slowFS":     req.SlowFS,
+	})
+
+	// In sampling mode the workers scan up to the sampling threshold; the
+	// sample is cut down to MaxResults once the scan finishes. With a result
+	// log they scan up to maxResultLogMatches and only the first MaxResults
+	// are kept in memory.
+	scanReq := req
+	scanReq.MaxResults = searchScanLimit(req)
+
+	// Process files using worker pool
+	resultsChan, searchState := s.processFiles(ctx, cancel, searchID, filesToProcess, scanReq, pattern, totalFiles)
+
+	// Collect results. Without sampling every result is final as soon as
+	// it arrives, so it goes to the sink straight away.
+	var results []SearchResult
+	var sinkErr error
+	matched := 0
+	for result := range resultsChan {
+		matched++
+		if req.ResultLogPath == "" || len(results) < req.MaxResults {
+			results = append(results, result)
+		}
+		if !req.Sampling {
+			if sinkErr = s.sink.AddResult(result); sinkErr != nil {
+				s.log.logError("Result sink failed, stopping search", sinkErr, nil)
+				cancel()
+				break
+			}
+		}
+
+		// Check if we've reached the result limit
+		if matched >= scanReq.MaxResults {
+			s.log.logInfo("Reached maximum results limit, stopping search", logrus.Fields{
+				"resultsCount": matched,
+				"maxResults":   scanReq.MaxResults,
+			})
+			// The context is already cancelled by the workers, but we'll do it again just in case
+			cancel()
+			break
+		}
+	}
+	// Wait for the workers before reporting: after an early stop the
+	// context is cancelled, so they finish their file and exit, and
+	// draining lets none block on a full results channel. No progress
+	// event can follow the completed one, and no goroutine outlives run.
+	for range resultsChan {
+	}
+	rootRemoved := atomic.LoadInt32(&searchState.rootRemoved) != 0
+	cancelled := ctx.Err() != nil && matched < scanReq.MaxResults && !rootRemoved && sinkErr == nil
+
+	// Replace the walk-order results with an even sample when there are
+	// more matches than the caller asked for. The per-file counts are lower
+	// bounds if the scan stopped at the threshold.
+	totalMatches := matched
+	var fileCounts []FileMatchCount
+	if req.Sampling && len(results) > req.MaxResults {
+		results, fileCounts = sampleResults(results, req.MaxResults)
+		s.log.logInfo("Sampled search results", logrus.Fields{
+			"totalMatches": totalMatches,
+			"sampleSize":   len(results),
+			"files":        len(fileCounts),
+		})
+	}
+	if req.Sampling {
+		for _, result := range results {
+			if sinkErr = s.sink.AddResult(result); sinkErr != nil {
+				s.log.logError("Result sink failed", sinkErr, nil)
+				break
+			}
+		}
+	}
+
+	processed := int(atomic.LoadInt32(&searchState.processedFiles))
+	s.log.logInfo("Sending final search progress", logrus.Fields{
+		"status":         "completed",
+		"processedFiles": processed,
+		"totalFiles":     totalFiles,
+		"resultsCount":   len(results),
+	})
+	s.sink.Done(SearchProgress{
+		SearchID:       searchID,
+		ProcessedFiles: processed,
+		TotalFiles:     totalFiles,
+		ResultsCount:   len(results),
+		Status:         "completed",
+		Sampled:        fileCounts != nil,
+		TotalMatches:   totalMatches,
+		Incomplete:     rootRemoved,
+		Skipped:        searchState.skipStats(),
+	})
+
+	return searchOutcome{
+		results:      results,
+		fileCounts:   fileCounts,
+		totalMatches: totalMatches,
+		totalFiles:   totalFiles,
+		state:        searchState,
+		cancelled:    cancelled,
+		rootRemoved:  rootRemoved,
+	}, sinkErr
+}
+
+// Bounds of the results channel buffer (see resultsBufferSize).
+const (
+	minResultsBuffer = 100
+	maxResultsBuffer = 4096
+)
+
+// resultsBufferSize sizes the results channel for a search that stops at
+// maxResults matches: room for all of them, so the workers never wait on a
+// slow sink (a large JSON payload, a result log on a slow disk), between
+// minResultsBuffer and maxResultsBuffer. Above the cap the workers can
+// block, but the memory reserved up front stays bounded.
+func resultsBufferSize(maxResults int) int {
+	return min(max(maxResults, minResultsBuffer), maxResultsBuffer)
+}
+
+// processFiles runs the files through a pool of workers calling the
+// Matcher and returns a channel of results, closed once every worker is
+// done. Progress events are tagged with searchID. The caller must read the
+// channel until it is closed.
+func (s *searcher) processFiles(ctx context.Context, cancel context.CancelFunc, searchID string, filesToProcess []fileMeta, req SearchRequest, pattern *regexp.Regexp, totalFiles int) (chan SearchResult, *SearchState) {
+	numWorkers := searchWorkers(req)
+	if len(filesToProcess) < numWorkers {
+		numWorkers = len(filesToProcess)
+	}
+
+	s.log.logDebug("Initializing worker pool", logrus.Fields{
+		"numWorkers":         numWorkers,
+		"totalFiles":         totalFiles,
+		"maxResults":         req.MaxResults,
+		"streamingThreshold": searchStreamingThreshold(req),
+		"scannerBufferSize":  searchScannerBufferSize(req),
+	})
+
+	// The queue holds every file, so it is filled up front and no sender
+	// goroutine can be left blocked when the search stops early.
+	filesChan := make(chan fileMeta, len(filesToProcess))
+	for _, meta := range filesToProcess {
+		filesChan <- meta
+	}
+	close(filesChan)
+	resultsChan := make(chan SearchResult, resultsBufferSize(req.MaxResults))
+
+	searchState := &SearchState{}
+	var searchCancelled int32
+	matcher := s.matcher
+	if req.InvertFileMatch {
+		matcher = invertMatcher{matcher}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case meta, ok := <-filesChan:
+					if !ok {
+						return
+					}
+
+					if !workerShouldContinue(ctx, &searchCancelled, cancel, &searchState.resultsCount, req.MaxResults) {
+						return
+					}
+
+					absFilePath, fileResults := matcher.Match(ctx, meta, pattern, req, searchState, &searchCancelled, cancel)
+					if absFilePath == "" {
+						// Skipped files still count as processed so the
+						// progress total adds up.
+						s.fileProgress(searchID, searchState, totalFiles, meta.absPath, req.SlowFS)
+						continue
+					}
+
+					// Send results and emit progress
+					emitFileResults(ctx, fileResults, resultsChan, searchState, &searchCancelled, cancel, req.MaxResults)
+					s.fileProgress(searchID, searchState, totalFiles, absFilePath, req.SlowFS)
+				}
+			}
+		}()
+	}
+
+	// Close results when all workers finish
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	return resultsChan, searchState
+}
+
+// fileProgress increments the processed file counter and reports progress
+// for the search searchID. In slow-FS mode only every
+// slowFSProgressInterval-th file is reported.
+func (s *searcher) fileProgress(searchID string, searchState *SearchState, totalFiles int, absFilePath string, slowFS bool) {
+	newCount := atomic.AddInt32(&searchState.processedFiles, 1)
+	if slowFS && int(newCount)%slowFSProgressInterval != 0 {
+		return
+	}
+	s.sink.Progress(SearchProgress{
+		SearchID:       searchID,
+		ProcessedFiles: int(newCount),
+		TotalFiles:     totalFiles,
+		CurrentFile:    absFilePath,
+		ResultsCount:   int(atomic.LoadInt32(&searchState.resultsCount)),
+		Status:         "in-progress",
+	})
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// nopSearchLogger discards the searcher's logging.
+type nopSearchLogger struct{}
+
+func (nopSearchLogger) logInfo(string, logrus.Fields)         {}
+func (nopSearchLogger) logWarn(string, logrus.Fields)         {}
+func (nopSearchLogger) logDebug(string, logrus.Fields)        {}
+func (nopSearchLogger) logError(string, error, logrus.Fields) {}
+
+// fakeCollector returns a fixed file list.
+type fakeCollector []fileMeta
+
+func (c fakeCollector) Collect(context.Context, string, SearchRequest, *regexp.Regexp, string) ([]fileMeta, error) {
+	return c, nil
+}
+
+// cancellingCollector cancels the search while collecting, as CancelSearch
+// would during a long walk, and fails with the context's error.
+type cancellingCollector struct{ cancel context.CancelFunc }
+
+func (c cancellingCollector) Collect(ctx context.Context, _ string, _ SearchRequest, _ *regexp.Regexp, _ string) ([]fileMeta, error) {
+	c.cancel()
+	return nil, ctx.Err()
+}
+
+// fakeMatcher returns perFile matches for every file, and skips files whose
+// size is 0.
+type fakeMatcher struct{ perFile int }
+
+func (m fakeMatcher) Match(ctx context.Context, meta fileMeta, _ *regexp.Regexp, req SearchRequest, state *SearchState, searchCancelled *int32, cancel context.CancelFunc) (string, []SearchResult) {
+	if meta.size == 0 {
+		return "", nil
+	}
+	var results []SearchResult
+	for i := 1; i <= m.perFile; i++ {
+		results = append(results, SearchResult{FilePath: meta.absPath, LineNum: i})
+	}
+	return meta.absPath, results
+}
+
+// recordingSink keeps every progress event, including the final one.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []SearchProgress
+}
+
+func (s *recordingSink) AddResult(SearchResult) error { return nil }
+
+func (s *recordingSink) Progress(p SearchProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, p)
+}
+
+func (s *recordingSink) Done(final SearchProgress) { s.Progress(final) }
+
+// TestSearcherRunWithFakes verifies the searcher core without an App:
+// results from the Matcher, skipped files counted as processed, and the
+// collecting, started, per-file, and completed progress events.
+func TestSearcherRunWithFakes(t *testing.T) {
+	sink := &recordingSink{}
+	s := &searcher{
+		collector: fakeCollector{{absPath: "/a", size: 1}, {absPath: "/b", size: 0}, {absPath: "/c", size: 1}},
+		matcher:   fakeMatcher{perFile: 2},
+		sink:      sink,
+		log:       nopSearchLogger{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, err := s.run(ctx, cancel, "search-x", SearchRequest{MaxResults: 100}, regexp.MustCompile("x"), "/")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(out.results) != 4 || out.totalFiles != 3 || out.cancelled {
+		t.Errorf("unexpected outcome: %d results, %d files, cancelled=%v", len(out.results), out.totalFiles, out.cancelled)
+	}
+
+	if len(sink.events) != 6 {
+		t.Fatalf("expected 6 progress events, got %d", len(sink.events))
+	}
+	if collecting := sink.events[0]; collecting.Status != "collecting" || collecting.SearchID != "search-x" {
+		t.Errorf("unexpected collecting event %+v", collecting)
+	}
+	first, last := sink.events[1], sink.events[len(sink.events)-1]
+	if first.Status != "started" || first.SearchID != "search-x" || first.TotalFiles != 3 {
+		t.Errorf("unexpected started event %+v", first)
+	}
+	if last.Status != "completed" || last.ProcessedFiles != 3 || last.ResultsCount != 4 || last.SearchID != "search-x" {
+		t.Errorf("unexpected last event %+v", last)
+	}
+}
+
+// TestSearcherRunStopsAtMaxResults verifies that the searcher cuts the
+// results at MaxResults and does not report that as a cancellation.
+func TestSearcherRunStopsAtMaxResults(t *testing.T) {
+	var files fakeCollector
+	for i := 0; i < 50; i++ {
+		files = append(files, fileMeta{absPath: "/f", size: 1})
+	}
+	s := &searcher{collector: files, matcher: fakeMatcher{perFile: 3}, sink: &recordingSink{}, log: nopSearchLogger{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, err := s.run(ctx, cancel, "", SearchRequest{MaxResults: 10}, regexp.MustCompile("x"), "/")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(out.results) != 10 || out.cancelled {
+		t.Errorf("expected 10 results without cancellation, got %d (cancelled=%v)", len(out.results), out.cancelled)
+	}
+}
+
+// TestInvertFileMatch verifies that an inverted search returns one result
+// per searched file without a match, and that filtered and binary files
+// are not among them.
+func TestInvertFileMatch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"users.go":  "func Users(w, r) {\n\trequireAuth(r)\n}\n",
+		"health.go": "func Health(w, r) {\n\tw.Write(ok)\n}\n",
+		"admin.go":  "func Admin(w, r) {\n\tlog(r)\n}\n",
+		"notes.md":  "no auth here\n",
+		"blob.dat":  "\x00\x01\x02binary",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	results, err := app.SearchWithProgress(SearchRequest{
+		Directory:        dir,
+		Query:            "requireAuth(",
+		UseRegex:         new(bool),
+		AllowedFileTypes: []string{"go", "dat"},
+		InvertFileMatch:  true,
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var names []string
+	for _, r := range results {
+		if r.LineNum != 0 || r.Content != "" {
+			t.Errorf("expected a file-only result, got %+v", r)
+		}
+		names = append(names, filepath.Base(r.FilePath))
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "admin.go,health.go" {
+		t.Errorf("expected admin.go and health.go, got %v", names)
+	}
+}
+
+// TestSearcherRunCancelledDuringCollection verifies that a search cancelled
+// while its files are collected ends as cancelled, without an error or a
+// completed event.
+func TestSearcherRunCancelledDuringCollection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := &recordingSink{}
+	s := &searcher{collector: cancellingCollector{cancel}, matcher: fakeMatcher{perFile: 1}, sink: sink, log: nopSearchLogger{}}
+
+	out, err := s.run(ctx, cancel, "search-x", SearchRequest{MaxResults: 10}, regexp.MustCompile("x"), "/")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !out.cancelled || out.state == nil || len(out.results) != 0 {
+		t.Errorf("unexpected outcome %+v", out)
+	}
+	if len(sink.events) != 1 || sink.events[0].Status != "collecting" {
+		t.Errorf("expected only the collecting event, got %+v", sink.events)
+	}
+}
+
+// TestResultsBufferSize verifies that the results buffer holds every
+// result of a search within the bounds.
+func TestResultsBufferSize(t *testing.T) {
+	for _, tt := range []struct{ maxResults, want int }{
+		{0, minResultsBuffer},
+		{10, minResultsBuffer},
+		{1000, 1000},
+		{maxResultsBuffer, maxResultsBuffer},
+		{1000000, maxResultsBuffer},
+	} {
+		if got := resultsBufferSize(tt.maxResults); got != tt.want {
+			t.Errorf("resultsBufferSize(%d) = %d, want %d", tt.maxResults, got, tt.want)
+		}
+	}
+}
+
+// blockingSink holds the first result until released, like a consumer busy
+// marshalling a large payload.
+type blockingSink struct {
+	recordingSink
+	release chan struct{}
+	once    sync.Once
+}
+
+func (s *blockingSink) AddResult(SearchResult) error {
+	s.once.Do(func() { <-s.release })
+	return nil
+}
+
+// TestSearcherWorkersDontWaitOnSlowSink verifies that with the results
+// buffer sized to MaxResults every file is searched while the sink is
+// still busy with the first result.
+func TestSearcherWorkersDontWaitOnSlowSink(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	files := make(fakeCollector, 100)
+	for i := range files {
+		files[i] = fileMeta{absPath: "/f", size: 1}
+	}
+	s := &searcher{collector: files, matcher: fakeMatcher{perFile: 5}, sink: sink, log: nopSearchLogger{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan searchOutcome)
+	go func() {
+		out, _ := s.run(ctx, cancel, "id", SearchRequest{MaxResults: 1000}, regexp.MustCompile("x"), "/")
+		done <- out
+	}()
+
+	processed := func() int {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		n := 0
+		for _, e := range sink.events {
+			n = max(n, e.ProcessedFiles)
+		}
+		return n
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for processed() < len(files) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := processed(); got != len(files) {
+		t.Errorf("expected all %d files searched while the sink was busy, got %d", len(files), got)
+	}
+	close(sink.release)
+	if out := <-done; len(out.results) != 500 {
+		t.Errorf("expected 500 results, got %d", len(out.results))
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SearchToFile runs a search like SearchWithProgress and also writes every
+// result to outputPath as NDJSON, one SearchResult object per line, as the
+// results come in. The file is created or truncated; outputPath must be
+// absolute. It returns the number of results written.
+func (a *App) SearchToFile(req SearchRequest, outputPath string) (int, error) {
+	if outputPath == "" {
+		return 0, newAppError(ErrCodePathRequired)
+	}
+	if !filepath.IsAbs(outputPath) {
+		return 0, newAppError(ErrCodeResultsExportFailed, outputPath, errors.New("path must be absolute"))
+	}
+
+	snap := snapshotForUndo(outputPath)
+	file, err := os.Create(toLongPath(outputPath))
+	if err != nil {
+		return 0, newAppError(ErrCodeResultsExportFailed, outputPath, err)
+	}
+	sink := newNDJSONSink(file)
+
+	results, searchErr := a.search(req, sink)
+	closeErr := file.Close()
+	if err := sink.Err(); err != nil {
+		return 0, newAppError(ErrCodeResultsExportFailed, outputPath, err)
+	}
+	if searchErr != nil {
+		return len(results), searchErr
+	}
+	if closeErr != nil {
+		return 0, newAppError(ErrCodeResultsExportFailed, outputPath, closeErr)
+	}
+
+	a.recordUndo(snap, undoKindExport, "Export search results")
+	a.logInfo("Search results written to file", logrus.Fields{
+		"outputPath":   outputPath,
+		"resultsCount": len(results),
+	})
+	return len(results), nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxStoredSearches is how many completed searches keep their results in
+// memory for FilterResults. Each holds up to MaxResults results, so the
+// bound matters more than the count suggests.
+const maxStoredSearches = 5
+
+// searchRecord is a completed search kept for post-filtering.
+type searchRecord struct {
+	id         string
+	request    SearchRequest
+	results    []SearchResult
+	counts     []FileMatchCount // Per-file match counts of a sampled search; nil otherwise
+	incomplete bool             // The search directory was removed before the search finished
+	modTimes   map[string]int64 // Modification times of the matched files when stored (see CheckResultsFreshness)
+	finishedAt time.Time
+}
+
+// newSearchID returns an ID for a search, unique for the process lifetime.
+// It is sent to the frontend on the started and completed search-progress
+// events.
+func (a *App) newSearchID() string {
+	return "search-" + strconv.FormatUint(atomic.AddUint64(&a.searchSeq, 1), 10)
+}
+
+// storeSearch records a finished search, evicting the oldest one beyond
+// maxStoredSearches, and watches its files for edits. finishedAt and
+// modTimes are set here.
+func (a *App) storeSearch(rec searchRecord) {
+	rec.modTimes = resultModTimes(rec.results, rec.counts)
+	a.watchResults(rec)
+
+	a.searchesMu.Lock()
+	defer a.searchesMu.Unlock()
+
+	rec.finishedAt = time.Now()
+	a.searches = append(a.searches, rec)
+	if excess := len(a.searches) - maxStoredSearches; excess > 0 {
+		// Copy instead of re-slicing so evicted results can be collected.
+		a.searches = append([]searchRecord(nil), a.searches[excess:]...)
+	}
+}
+
+// lookupSearch returns the stored search with the given ID.
+func (a *App) lookupSearch(id string) (searchRecord, bool) {
+	a.searchesMu.Lock()
+	defer a.searchesMu.Unlock()
+
+	for _, rec := range a.searches {
+		if rec.id == id {
+			return rec, true
+		}
+	}
+	return searchRecord{}, false
+}
+
+// resultExcluder decides whether a result falls under one of the paths
+// passed to FilterResults.
+type resultExcluder struct {
+	dirs     []string // Clean absolute paths: the path itself and everything below it
+	patterns []string // Bare names or globs matched against each path component
+}
+
+// newResultExcluder interprets excludePaths for a search rooted at baseDir:
+// absolute paths and relative paths with a separator ("src/tests") hide
+// that file or folder; a bare name or glob ("tests", "*_test.go") hides
+// every path with a matching component.
+func newResultExcluder(baseDir string, excludePaths []string) resultExcluder {
+	var ex resultExcluder
+	for _, p := range excludePaths {
+		p = strings.TrimSpace(p)
+		trimmed := strings.Trim(filepath.FromSlash(p), string(filepath.Separator))
+		switch {
+		case p == "" || trimmed == "":
+			continue
+		case filepath.IsAbs(p):
+			ex.dirs = append(ex.dirs, filepath.Clean(p))
+		case strings.ContainsRune(trimmed, filepath.Separator):
+			ex.dirs = append(ex.dirs, filepath.Join(baseDir, trimmed))
+		default:
+			ex.patterns = append(ex.patterns, trimmed)
+		}
+	}
+	return ex
+}
+
+// excludes reports whether the result at filePath is hidden.
+func (ex resultExcluder) excludes(a *App, filePath string) bool {
+	clean := filepath.Clean(filePath)
+	for _, dir := range ex.dirs {
+		if isWithinDir(clean, dir) {
+			return true
+		}
+	}
+	for _, pattern := range ex.patterns {
+		if a.matchesPattern(clean, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupResults groups results by file, keeping the order in which files
+// first appear.
+func groupResults(results []SearchResult) []ResultGroup {
+	index := make(map[string]int)
+	var groups []ResultGroup
+	for _, r := range results {
+		i, ok := index[r.FilePath]
+		if !ok {
+			i = len(groups)
+			index[r.FilePath] = i
+			groups = append(groups, ResultGroup{FilePath: r.FilePath})
+		}
+		groups[i].Results = append(groups[i].Results, r)
+		groups[i].Count++
+		groups[i].MatchCount++
+	}
+	return groups
+}
+
+// sampledGroups builds the groups of a sampled search from its per-file
+// counts, so files whose matches all fell outside the sample still show up
+// with their count and no results.
+func (a *App) sampledGroups(counts []FileMatchCount, kept []SearchResult, excluder resultExcluder) []ResultGroup {
+	byFile := make(map[string]ResultGroup)
+	for _, g := range groupResults(kept) {
+		byFile[g.FilePath] = g
+	}
+
+	var groups []ResultGroup
+	for _, c := range counts {
+		if excluder.excludes(a, c.FilePath) {
+			continue
+		}
+		g, ok := byFile[c.FilePath]
+		if !ok {
+			g = ResultGroup{FilePath: c.FilePath, Results: []SearchResult{}}
+		}
+		g.MatchCount = c.Count
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// FilterResults recomputes the grouped result view of a completed search
+// without re-running it, hiding every result under excludePaths. Entries
+// may be absolute paths, paths relative to the search directory
+// ("src/tests"), or bare folder names and globs matched at any depth
+// ("tests", "*_test.go"). An empty list returns the full view. For a
+// sampled search the view lists every file with matches, each with its
+// full MatchCount, even when none of its matches made the sample. Only the
+// most recent searches are kept; an older ID fails with SEARCH_NOT_FOUND.
+func (a *App) FilterResults(searchID string, excludePaths []string) (FilteredResults, error) {
+	rec, ok := a.lookupSearch(searchID)
+	if !ok {
+		return FilteredResults{}, newAppError(ErrCodeSearchNotFound, searchID)
+	}
+
+	baseDir, err := filepath.Abs(rec.request.Directory)
+	if err != nil {
+		baseDir = rec.request.Directory
+	}
+	excluder := newResultExcluder(baseDir, excludePaths)
+
+	kept := make([]SearchResult, 0, len(rec.results))
+	for _, r := range rec.results {
+		if !excluder.excludes(a, r.FilePath) {
+			kept = append(kept, r)
+		}
+	}
+
+	groups := groupResults(kept)
+	allFiles := len(groupResults(rec.results))
+	if rec.counts != nil {
+		groups = a.sampledGroups(rec.counts, kept, excluder)
+		allFiles = len(rec.counts)
+	}
+	if groups == nil {
+		groups = []ResultGroup{}
+	}
+
+	totalMatches := 0
+	for _, g := range groups {
+		totalMatches += g.MatchCount
+	}
+	return FilteredResults{
+		SearchID:      searchID,
+		Groups:        groups,
+		TotalResults:  len(kept),
+		TotalFiles:    len(groups),
+		HiddenResults: len(rec.results) - len(kept),
+		HiddenFiles:   allFiles - len(groups),
+		Sampled:       rec.counts != nil,
+		TotalMatches:  totalMatches,
+		Incomplete:    rec.incomplete,
+	}, nil
+}
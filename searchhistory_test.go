@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFilterResults verifies grouping by file and hiding results by
+// absolute path, relative path, and bare folder name.
+func TestFilterResults(t *testing.T) {
+	app := NewApp()
+	base := t.TempDir()
+	p := func(rel string) string { return filepath.Join(base, filepath.FromSlash(rel)) }
+
+	results := []SearchResult{
+		{FilePath: p("src/main.go"), LineNum: 1},
+		{FilePath: p("src/tests/a_test.go"), LineNum: 2},
+		{FilePath: p("src/main.go"), LineNum: 9},
+		{FilePath: p("vendor/lib.go"), LineNum: 3},
+		{FilePath: p("tests/b_test.go"), LineNum: 4},
+	}
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: base}, results: results})
+
+	full, err := app.FilterResults(id, nil)
+	if err != nil {
+		t.Fatalf("FilterResults failed: %v", err)
+	}
+	if full.TotalResults != 5 || full.TotalFiles != 4 || full.HiddenResults != 0 {
+		t.Errorf("unexpected full view %+v", full)
+	}
+	if full.Groups[0].FilePath != p("src/main.go") || full.Groups[0].Count != 2 {
+		t.Errorf("expected main.go first with 2 results, got %+v", full.Groups[0])
+	}
+
+	cases := []struct {
+		exclude      []string
+		wantResults  int
+		wantHidden   int
+		wantHiddenFs int
+	}{
+		{[]string{"tests"}, 3, 2, 2},
+		{[]string{p("vendor")}, 4, 1, 1},
+		{[]string{"src/tests/", "vendor"}, 3, 2, 2},
+		{[]string{"*_test.go", p("src")}, 1, 4, 3},
+		{[]string{"", "  "}, 5, 0, 0},
+	}
+	for _, tc := range cases {
+		got, err := app.FilterResults(id, tc.exclude)
+		if err != nil {
+			t.Fatalf("FilterResults(%q) failed: %v", tc.exclude, err)
+		}
+		if got.TotalResults != tc.wantResults || got.HiddenResults != tc.wantHidden || got.HiddenFiles != tc.wantHiddenFs {
+			t.Errorf("FilterResults(%q) = %d results, %d hidden in %d files; want %d, %d, %d",
+				tc.exclude, got.TotalResults, got.HiddenResults, got.HiddenFiles, tc.wantResults, tc.wantHidden, tc.wantHiddenFs)
+		}
+	}
+}
+
+// TestFilterResultsEviction verifies that only the most recent searches are
+// kept and an evicted ID reports SEARCH_NOT_FOUND.
+func TestFilterResultsEviction(t *testing.T) {
+	app := NewApp()
+	var ids []string
+	for i := 0; i < maxStoredSearches+1; i++ {
+		id := app.newSearchID()
+		ids = append(ids, id)
+		app.storeSearch(searchRecord{id: id, request: SearchRequest{Directory: "/"}, results: []SearchResult{{FilePath: fmt.Sprintf("/f%d", i)}}})
+	}
+
+	var appErr *AppError
+	if _, err := app.FilterResults(ids[0], nil); !errors.As(err, &appErr) || appErr.Code != ErrCodeSearchNotFound {
+		t.Errorf("expected %s for an evicted search, got %v", ErrCodeSearchNotFound, err)
+	}
+	if _, err := app.FilterResults(ids[len(ids)-1], nil); err != nil {
+		t.Errorf("expected the latest search to be available, got %v", err)
+	}
+}
+
+// TestSearchWithProgressStoresResults verifies that a completed search can
+// be filtered by the ID it was assigned.
+func TestSearchWithProgressStoresResults(t *testing.T) {
+	app := NewApp()
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", filepath.Join("skip", "b.txt")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("needle\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle", SearchSubdirs: true})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("SearchWithProgress = %d results, %v", len(results), err)
+	}
+
+	view, err := app.FilterResults(fmt.Sprintf("search-%d", app.searchSeq), []string{"skip"})
+	if err != nil {
+		t.Fatalf("FilterResults failed: %v", err)
+	}
+	if view.TotalResults != 1 || view.HiddenResults != 1 {
+		t.Errorf("unexpected view %+v", view)
+	}
+}
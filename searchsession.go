@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"slices"
+)
+
+// searchSession is one running search. Each search owns its context and
+// cancel function, so a search that finishes can't clear or cancel the
+// one that started after it.
+type searchSession struct {
+	id     string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// startSearchSession registers the search searchID with its own
+// cancellable context. The caller must pass it to endSearchSession when
+// done.
+func (a *App) startSearchSession(searchID string) *searchSession {
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &searchSession{id: searchID, ctx: ctx, cancel: cancel}
+	a.searchMu.Lock()
+	defer a.searchMu.Unlock()
+	a.searchSessions = append(a.searchSessions, session)
+	metricSearchesStarted.Add(1)
+	return session
+}
+
+// endSearchSession unregisters session and releases its context. The other
+// running searches stay cancellable.
+func (a *App) endSearchSession(session *searchSession) {
+	a.searchMu.Lock()
+	a.searchSessions = slices.DeleteFunc(a.searchSessions, func(s *searchSession) bool { return s == session })
+	a.searchMu.Unlock()
+	session.cancel()
+}
+
+// cancelActiveSearches cancels every running search and returns their IDs.
+func (a *App) cancelActiveSearches() []string {
+	a.searchMu.Lock()
+	defer a.searchMu.Unlock()
+	ids := make([]string, 0, len(a.searchSessions))
+	for _, session := range a.searchSessions {
+		session.cancel()
+		ids = append(ids, session.id)
+	}
+	metricSearchesCancelled.Add(int64(len(a.searchSessions)))
+	return ids
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestEndSearchSessionKeepsLaterSearchCancellable verifies that a search
+// finishing while a newer one runs leaves the newer one cancellable, which
+// the shared cancel function it replaces did not.
+func TestEndSearchSessionKeepsLaterSearchCancellable(t *testing.T) {
+	app := NewApp()
+	first := app.startSearchSession(app.newSearchID())
+	second := app.startSearchSession(app.newSearchID())
+
+	app.endSearchSession(first)
+	if first.ctx.Err() == nil {
+		t.Error("expected the ended session's context to be released")
+	}
+	if second.ctx.Err() != nil {
+		t.Fatal("ending the first search cancelled the second")
+	}
+
+	if err := app.CancelSearch(); err != nil {
+		t.Fatalf("CancelSearch failed: %v", err)
+	}
+	if second.ctx.Err() == nil {
+		t.Error("expected CancelSearch to cancel the running search")
+	}
+	app.endSearchSession(second)
+
+	err := app.CancelSearch()
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code != ErrCodeNoActiveSearch {
+		t.Errorf("expected %s once every search ended, got %v", ErrCodeNoActiveSearch, err)
+	}
+}
+
+// TestOverlappingSearchesWithCancel runs searches that overlap each other
+// and CancelSearch, including searches stopped by their result limit; run
+// it with -race to check the session bookkeeping.
+func TestOverlappingSearchesWithCancel(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		content := fmt.Sprintf("needle %d\nneedle again\n", i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.txt", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(maxResults int) {
+			defer wg.Done()
+			if _, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle", MaxResults: maxResults}); err != nil {
+				t.Errorf("search failed: %v", err)
+			}
+		}(1 + i*10)
+		go func() {
+			defer wg.Done()
+			_ = app.CancelSearch()
+		}()
+	}
+	wg.Wait()
+
+	app.searchMu.Lock()
+	running := len(app.searchSessions)
+	app.searchMu.Unlock()
+	if running != 0 {
+		t.Errorf("expected no running searches after all ended, got %d", running)
+	}
+}
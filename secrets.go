@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxSecretFindings caps the findings ScanSecrets returns; a tree with more
+// has bigger problems than the list can show.
+const maxSecretFindings = 1000
+
+// maxSecretScanFileSize is the largest file ScanSecrets reads. Credentials
+// live in source and config files, not in multi-megabyte data dumps.
+const maxSecretScanFileSize = 10 * 1024 * 1024
+
+// Entropy heuristic thresholds, in bits per character. Random hex tops out
+// at 4 bits and random base64 at 6, while identifiers, words, and paths of
+// the same length stay well below.
+const (
+	minEntropyLiteralLength = 20
+	hexEntropyThreshold     = 3.0
+	base64EntropyThreshold  = 4.2
+)
+
+// entropyRuleID is SecretFinding.Rule for a string literal flagged by its
+// entropy rather than by a known token format.
+const entropyRuleID = "high-entropy-string"
+
+// secretRule is a known credential format.
+type secretRule struct {
+	id      string
+	name    string
+	pattern *regexp.Regexp
+}
+
+// secretRules are the token formats ScanSecrets looks for on every line. A
+// rule whose match includes more than the secret, such as the variable
+// name, marks the secret with a group named "secret".
+var secretRules = []secretRule{
+	{"aws-access-key-id", "AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws-secret-access-key", "AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?(access_?)?key["']?\s*(:=|=|:)\s*["']?(?P<secret>[A-Za-z0-9/+]{40})\b`)},
+	{"github-token", "GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,255}\b|\bgithub_pat_[A-Za-z0-9_]{82}\b`)},
+	{"private-key", "Private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{"slack-token", "Slack token", regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}`)},
+	{"stripe-secret-key", "Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{"google-api-key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+}
+
+// stringLiteralPattern matches double-, single-, and back-quoted string
+// literals on one line, for the entropy heuristic.
+var stringLiteralPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'` + "|`([^`]*)`")
+
+// secretScanSkippedFiles are lock and checksum files, full of integrity
+// hashes that the entropy heuristic would flag by the hundred.
+var secretScanSkippedFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"composer.lock":     true,
+	"Gemfile.lock":      true,
+	"poetry.lock":       true,
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// isHexString and isBase64String report whether s uses only the hex or
+// (standard or URL-safe) base64 alphabet.
+func isHexString(s string) bool {
+	return strings.Trim(s, "0123456789abcdefABCDEF") == ""
+}
+
+func isBase64String(s string) bool {
+	return strings.Trim(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/-_=") == ""
+}
+
+// highEntropyLiteral reports whether a string literal's content looks like
+// a random token: long enough, from the hex or base64 alphabet, and above
+// that alphabet's entropy threshold. It returns the entropy.
+func highEntropyLiteral(s string) (float64, bool) {
+	if len(s) < minEntropyLiteralLength {
+		return 0, false
+	}
+	entropy := shannonEntropy(s)
+	switch {
+	case isHexString(s):
+		return entropy, entropy >= hexEntropyThreshold
+	case isBase64String(s):
+		return entropy, entropy >= base64EntropyThreshold
+	}
+	return entropy, false
+}
+
+// maskSecret hides all but the first four and last two characters of a
+// secret, so findings can be reported without leaking it again. Secrets of
+// eight characters or fewer are hidden entirely.
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", len(s)-6) + s[len(s)-2:]
+}
+
+// secretLinePreviewLength is the longest SecretFinding.Line, in bytes.
+const secretLinePreviewLength = 200
+
+// scanLineForSecrets returns the findings on one line, the secrets in the
+// line masked. Known token formats are checked first; a literal they
+// already flagged is not reported again by its entropy.
+func scanLineForSecrets(path string, lineNum int, line string) []SecretFinding {
+	var findings []SecretFinding
+	var spans [][2]int
+	add := func(rule, name, secret string, start, end int, entropy float64) {
+		spans = append(spans, [2]int{start, end})
+		findings = append(findings, SecretFinding{
+			FilePath: path,
+			LineNum:  lineNum,
+			Rule:     rule,
+			RuleName: name,
+			Match:    maskSecret(secret),
+			Entropy:  entropy,
+		})
+	}
+	overlaps := func(start, end int) bool {
+		for _, s := range spans {
+			if start < s[1] && s[0] < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rule := range secretRules {
+		group := rule.pattern.SubexpIndex("secret")
+		for _, loc := range rule.pattern.FindAllStringSubmatchIndex(line, -1) {
+			start, end := loc[0], loc[1]
+			if group > 0 && loc[2*group] >= 0 {
+				start, end = loc[2*group], loc[2*group+1]
+			}
+			if !overlaps(start, end) {
+				add(rule.id, rule.name, line[start:end], start, end, 0)
+			}
+		}
+	}
+	for _, loc := range stringLiteralPattern.FindAllStringSubmatchIndex(line, -1) {
+		for g := 2; g < len(loc); g += 2 {
+			start, end := loc[g], loc[g+1]
+			if start < 0 || overlaps(start, end) {
+				continue
+			}
+			if entropy, ok := highEntropyLiteral(line[start:end]); ok {
+				add(entropyRuleID, "High-entropy string", line[start:end], start, end, math.Round(entropy*100)/100)
+			}
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	// Mask every secret in the line, right to left so the offsets hold.
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] > spans[j][0] })
+	masked := line
+	for _, s := range spans {
+		masked = masked[:s[0]] + maskSecret(masked[s[0]:s[1]]) + masked[s[1]:]
+	}
+	masked = strings.TrimSpace(masked)
+	if len(masked) > secretLinePreviewLength {
+		masked = masked[:secretLinePreviewLength] + "..."
+	}
+	for i := range findings {
+		findings[i].Line = masked
+	}
+	return findings
+}
+
+// scanFileForSecrets returns the findings in one file. Binary files are
+// skipped, as are lines longer than the scanner buffer (minified bundles).
+func (a *App) scanFileForSecrets(path string) ([]SecretFinding, error) {
+	f, err := os.Open(toLongPath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	if a.isBinary(head[:n]) {
+		return nil, nil
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	var findings []SecretFinding
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), defaultScannerBufferSize)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		findings = append(findings, scanLineForSecrets(path, lineNum, scanner.Text())...)
+	}
+	return findings, scanner.Err()
+}
+
+// ScanSecrets audits root for committed credentials: known token formats
+// (AWS keys, GitHub, Slack, Stripe, and Google tokens, private key headers)
+// and string literals whose Shannon entropy marks them as random tokens.
+// The secrets are masked in the findings. The walk is a search's, so
+// hidden directories and .codesearchignore rules apply; lock files and
+// files over 10MB are skipped.
+func (a *App) ScanSecrets(root string) (SecretScanReport, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return SecretScanReport{}, err
+	}
+
+	start := time.Now()
+	req := SearchRequest{
+		Directory:      absRoot,
+		SearchSubdirs:  true,
+		IncludeBinary:  true,
+		MaxFileSize:    maxSecretScanFileSize,
+		MaxFilesPerDir: defaultMaxFilesPerDir,
+	}
+	files, _, _, err := a.walkDirectoryTree(req, false)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return SecretScanReport{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+
+	report := SecretScanReport{Root: absRoot, Findings: []SecretFinding{}}
+	var mu sync.Mutex
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < numCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				findings, err := a.scanFileForSecrets(path)
+				if err != nil {
+					a.logDebug("Skipping unreadable file while scanning for secrets", logrus.Fields{"filePath": path, "error": err.Error()})
+				}
+				mu.Lock()
+				report.FilesScanned++
+				report.Findings = append(report.Findings, findings...)
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, meta := range files {
+		if !secretScanSkippedFiles[filepath.Base(meta.absPath)] {
+			jobs <- meta.absPath
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		fi, fj := report.Findings[i], report.Findings[j]
+		if fi.FilePath != fj.FilePath {
+			return fi.FilePath < fj.FilePath
+		}
+		return fi.LineNum < fj.LineNum
+	})
+	if len(report.Findings) > maxSecretFindings {
+		report.Findings = report.Findings[:maxSecretFindings]
+		report.Truncated = true
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	a.logInfo("Secrets scan complete", logrus.Fields{
+		"root":     absRoot,
+		"files":    report.FilesScanned,
+		"findings": len(report.Findings),
+	})
+	return report, nil
+}
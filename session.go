@@ -0,0 +1,53 @@
+package main
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sessionFileName is the data-directory file holding the last session when
+// no workspace is active.
+const sessionFileName = "session.json"
+
+// currentSessionFile returns the session file for the active workspace, so
+// each workspace restores its own last session.
+func (a *App) currentSessionFile() string {
+	if id := a.activeWorkspaceID(); id != "" {
+		return workspaceSessionFileName(id)
+	}
+	return sessionFileName
+}
+
+// GetLastSession returns the session saved by the last SaveSession call in
+// the active workspace, so
+// the frontend can restore the search form, the open result, and scroll
+// positions on startup. On first run, or if the saved file is unreadable,
+// it returns an empty session rather than an error: a missing restore
+// should never block the app from starting.
+func (a *App) GetLastSession() SessionState {
+	var state SessionState
+	found, err := a.loadJSON(a.currentSessionFile(), &state)
+	if err != nil {
+		a.logWarn("Discarding unreadable saved session", logrus.Fields{
+			"error": err.Error(),
+		})
+		return SessionState{}
+	}
+	if !found {
+		return SessionState{}
+	}
+	return state
+}
+
+// SaveSession persists the current session state for the active workspace. The frontend calls it when
+// the search form, the open result, or a scroll position changes (debounced
+// on its side) and before the window closes.
+func (a *App) SaveSession(state SessionState) error {
+	state.SavedAt = time.Now().UnixMilli()
+	if err := a.saveJSON(a.currentSessionFile(), state); err != nil {
+		a.logError("Failed to save session", err, nil)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSessionRoundTrip verifies that a saved session is returned unchanged
+// by GetLastSession, with SavedAt filled in.
+func TestSessionRoundTrip(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	useRegex := true
+	saved := SessionState{
+		Request: SearchRequest{
+			Directory:       "/src/project",
+			Query:           "TODO",
+			UseRegex:        &useRegex,
+			ExcludePatterns: []string{"node_modules"},
+		},
+		OpenFilePath:  "/src/project/main.go",
+		OpenLineNum:   42,
+		ResultsScroll: 300,
+		PreviewScroll: 120,
+	}
+	if err := app.SaveSession(saved); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	got := app.GetLastSession()
+	if got.SavedAt == 0 {
+		t.Error("expected SavedAt to be set")
+	}
+	if got.Request.Directory != saved.Request.Directory || got.Request.Query != saved.Request.Query {
+		t.Errorf("request not restored: %+v", got.Request)
+	}
+	if got.Request.UseRegex == nil || !*got.Request.UseRegex {
+		t.Error("expected UseRegex to be restored")
+	}
+	if len(got.Request.ExcludePatterns) != 1 || got.Request.ExcludePatterns[0] != "node_modules" {
+		t.Errorf("exclude patterns not restored: %v", got.Request.ExcludePatterns)
+	}
+	if got.OpenFilePath != saved.OpenFilePath || got.OpenLineNum != 42 {
+		t.Errorf("open result not restored: %s:%d", got.OpenFilePath, got.OpenLineNum)
+	}
+	if got.ResultsScroll != 300 || got.PreviewScroll != 120 {
+		t.Errorf("scroll offsets not restored: %d/%d", got.ResultsScroll, got.PreviewScroll)
+	}
+}
+
+// TestGetLastSessionFallsBackToEmpty verifies that a first run and a corrupt
+// session file both yield an empty session instead of an error.
+func TestGetLastSessionFallsBackToEmpty(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	if got := app.GetLastSession(); got.Request.Directory != "" || got.SavedAt != 0 {
+		t.Errorf("expected empty session on first run, got %+v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(app.dataDir, sessionFileName), []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("writing corrupt session: %v", err)
+	}
+	if got := app.GetLastSession(); got.Request.Directory != "" || got.SavedAt != 0 {
+		t.Errorf("expected empty session for corrupt file, got %+v", got)
+	}
+}
+
+// TestSaveJSONLeavesNoTempFiles verifies the atomic write cleans up after
+// itself, so the data directory only ever holds the final file.
+func TestSaveJSONLeavesNoTempFiles(t *testing.T) {
+	app := NewApp()
+	app.dataDir = filepath.Join(t.TempDir(), "nested", "data")
+
+	for i := 0; i < 3; i++ {
+		if err := app.SaveSession(SessionState{OpenLineNum: i}); err != nil {
+			t.Fatalf("SaveSession failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(app.dataDir)
+	if err != nil {
+		t.Fatalf("reading data dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != sessionFileName {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("expected only %s in data dir, got %v", sessionFileName, names)
+	}
+}
+
+// TestSaveSessionWithoutDataDir verifies that saving reports an error rather
+// than writing somewhere unexpected when no data directory is available.
+func TestSaveSessionWithoutDataDir(t *testing.T) {
+	app := NewApp()
+	app.dataDir = ""
+
+	if err := app.SaveSession(SessionState{}); err == nil {
+		t.Error("expected error when no data directory is available")
+	}
+	if got := app.GetLastSession(); got.SavedAt != 0 {
+		t.Errorf("expected empty session without data dir, got %+v", got)
+	}
+}
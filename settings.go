@@ -0,0 +1,176 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// settingsFileName is the data-directory file holding user settings.
+const settingsFileName = "settings.json"
+
+// defaultNotifyMinSeconds is how long a search must run before its
+// completion triggers a desktop notification. Shorter searches finish while
+// the user is still looking at the window.
+const defaultNotifyMinSeconds = 10
+
+// defaultSettings returns the settings used before the user changes any.
+func defaultSettings() Settings {
+	return Settings{
+		NotifyOnCompletion: false,
+		NotifyMinSeconds:   defaultNotifyMinSeconds,
+		StreamingThreshold: streamingThreshold,
+		ScannerBufferSize:  defaultScannerBufferSize,
+		EditorCacheHours:   defaultEditorCacheHours,
+		LargeFileWarnMB:    defaultLargeFileWarnMB,
+		UpdateChannel:      updateChannelStable,
+		LogFilters:         defaultLogFilters(),
+	}
+}
+
+// normalizeSettings replaces out-of-range values with their defaults and
+// brings the hotkey into its canonical "Ctrl+Shift+F" form. It fails only
+// for an unparseable hotkey.
+func normalizeSettings(s Settings) (Settings, error) {
+	if s.NotifyMinSeconds <= 0 {
+		s.NotifyMinSeconds = defaultNotifyMinSeconds
+	}
+	if s.StreamingThreshold <= 0 {
+		s.StreamingThreshold = streamingThreshold
+	}
+	s.StreamingThreshold = clampInt64(s.StreamingThreshold, minStreamingThreshold, maxStreamingThreshold)
+	if s.ScannerBufferSize <= 0 {
+		s.ScannerBufferSize = defaultScannerBufferSize
+	}
+	s.ScannerBufferSize = int(clampInt64(int64(s.ScannerBufferSize), minScannerBufferSize, maxScannerBufferSize))
+	if s.EditorCacheHours <= 0 {
+		s.EditorCacheHours = defaultEditorCacheHours
+	}
+	s.EditorCacheHours = int(clampInt64(int64(s.EditorCacheHours), 1, maxEditorCacheHours))
+	if s.LargeFileWarnMB <= 0 {
+		s.LargeFileWarnMB = defaultLargeFileWarnMB
+	}
+	s.LargeFileWarnMB = int(clampInt64(int64(s.LargeFileWarnMB), 1, maxLargeFileWarnMB))
+	s.UpdateChannel = normalizeUpdateChannel(s.UpdateChannel)
+	if s.LogFilters == nil {
+		s.LogFilters = defaultLogFilters()
+	}
+	s.Hotkey = strings.TrimSpace(s.Hotkey)
+	if s.Hotkey != "" {
+		hk, err := parseHotkey(s.Hotkey)
+		if err != nil {
+			return s, err
+		}
+		s.Hotkey = hk.String()
+	}
+	return s, nil
+}
+
+// currentSettings returns the settings, loading them from the data
+// directory on first use. A missing or unreadable file yields the defaults.
+func (a *App) currentSettings() Settings {
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	if a.settings == nil {
+		settings := defaultSettings()
+		if _, err := a.loadJSON(settingsFileName, &settings); err != nil {
+			a.logWarn("Using default settings, saved settings are unreadable", logrus.Fields{
+				"error": err.Error(),
+			})
+			settings = defaultSettings()
+		}
+		if normalized, err := normalizeSettings(settings); err == nil {
+			settings = normalized
+		} else {
+			a.logWarn("Ignoring invalid saved hotkey", logrus.Fields{"hotkey": settings.Hotkey})
+			settings.Hotkey = ""
+		}
+		a.settings = &settings
+	}
+	return *a.settings
+}
+
+// GetSettings returns the current user settings.
+func (a *App) GetSettings() Settings {
+	return a.currentSettings()
+}
+
+// UpdateSettings replaces the user settings, persists them, and returns the
+// normalized values actually stored. A changed hotkey is registered, and a
+// changed metrics address served, before anything is saved, so a shortcut
+// another app already owns or a port in use is reported and the previous
+// settings stay in effect. A new logs directory is used from the next
+// launch.
+func (a *App) UpdateSettings(settings Settings) (Settings, error) {
+	settings, err := normalizeSettings(settings)
+	if err != nil {
+		return Settings{}, err
+	}
+	for _, name := range append([]string{settings.DefaultEditor}, settings.EditorPriority...) {
+		if name != "" && !validEditorName(name) {
+			return Settings{}, newAppError(ErrCodeUnknownEditor, name)
+		}
+	}
+	if err := validateEditorPaths(settings.EditorPaths); err != nil {
+		return Settings{}, err
+	}
+	if err := validateHooks(settings.Hooks); err != nil {
+		return Settings{}, err
+	}
+	if err := validateLogFilters(settings.LogFilters); err != nil {
+		return Settings{}, err
+	}
+	settings.MetricsAddr = strings.TrimSpace(settings.MetricsAddr)
+	if err := validateMetricsAddr(settings.MetricsAddr); err != nil {
+		return Settings{}, err
+	}
+	if err := validateUpdateChannel(settings.UpdateChannel); err != nil {
+		return Settings{}, err
+	}
+	settings.LogDir = strings.TrimSpace(settings.LogDir)
+	if settings.LogDir != "" && !filepath.IsAbs(settings.LogDir) {
+		return Settings{}, newAppError(ErrCodeLogDirInvalid, settings.LogDir)
+	}
+
+	a.settingsMu.Lock()
+	defer a.settingsMu.Unlock()
+
+	// Global shortcuts can only be grabbed with a running window.
+	if a.ctx != nil {
+		if err := a.applyHotkey(settings.Hotkey); err != nil {
+			return Settings{}, err
+		}
+	}
+	if err := a.applyMetricsAddr(settings.MetricsAddr); err != nil {
+		return Settings{}, err
+	}
+
+	if err := a.saveJSON(settingsFileName, settings); err != nil {
+		a.logError("Failed to save settings", err, nil)
+		return Settings{}, err
+	}
+	a.settings = &settings
+	a.applyLogFilters(settings.LogFilters)
+
+	a.logInfo("Settings updated", logrus.Fields{
+		"notifyOnCompletion": settings.NotifyOnCompletion,
+		"notifyMinSeconds":   settings.NotifyMinSeconds,
+		"hotkey":             settings.Hotkey,
+		"streamingThreshold": settings.StreamingThreshold,
+		"scannerBufferSize":  settings.ScannerBufferSize,
+		"persistResults":     settings.PersistResults,
+		"metricsAddr":        settings.MetricsAddr,
+		"logDir":             settings.LogDir,
+		"checkUpdates":       settings.CheckUpdatesOnStartup,
+		"updateChannel":      settings.UpdateChannel,
+		"defaultEditor":      settings.DefaultEditor,
+		"editorCacheHours":   settings.EditorCacheHours,
+		"editorPaths":        settings.EditorPaths,
+		"largeFileWarnMB":    settings.LargeFileWarnMB,
+		"hooks":              len(settings.Hooks),
+		"logFilters":         len(settings.LogFilters),
+	})
+	return settings, nil
+}
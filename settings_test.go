@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSettingsDefaultsAndRoundTrip verifies first-run defaults, persistence
+// across App instances, and normalization of out-of-range values.
+func TestSettingsDefaultsAndRoundTrip(t *testing.T) {
+	dataDir := t.TempDir()
+	app := NewApp()
+	app.dataDir = dataDir
+
+	if got := app.GetSettings(); !reflect.DeepEqual(got, defaultSettings()) {
+		t.Errorf("expected defaults on first run, got %+v", got)
+	}
+
+	saved, err := app.UpdateSettings(Settings{NotifyOnCompletion: true, NotifyMinSeconds: -5})
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if saved.NotifyMinSeconds != defaultNotifyMinSeconds {
+		t.Errorf("expected NotifyMinSeconds normalized to %d, got %d", defaultNotifyMinSeconds, saved.NotifyMinSeconds)
+	}
+
+	reloaded := NewApp()
+	reloaded.dataDir = dataDir
+	if got := reloaded.GetSettings(); !reflect.DeepEqual(got, saved) {
+		t.Errorf("settings not persisted: got %+v, want %+v", got, saved)
+	}
+}
+
+// TestSettingsCorruptFileFallsBack verifies that an unreadable settings file
+// yields the defaults instead of an error.
+func TestSettingsCorruptFileFallsBack(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(app.dataDir, settingsFileName), []byte("[]"), 0o644); err != nil {
+		t.Fatalf("writing corrupt settings: %v", err)
+	}
+	if got := app.GetSettings(); !reflect.DeepEqual(got, defaultSettings()) {
+		t.Errorf("expected defaults for corrupt file, got %+v", got)
+	}
+}
+
+// TestShouldNotifySearch verifies the notification threshold.
+func TestShouldNotifySearch(t *testing.T) {
+	on := Settings{NotifyOnCompletion: true, NotifyMinSeconds: 10}
+	cases := []struct {
+		name     string
+		settings Settings
+		duration time.Duration
+		want     bool
+	}{
+		{"LongSearch", on, 12 * time.Second, true},
+		{"AtThreshold", on, 10 * time.Second, true},
+		{"ShortSearch", on, 9 * time.Second, false},
+		{"Disabled", Settings{NotifyMinSeconds: 10}, time.Minute, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shouldNotifySearch(tc.settings, tc.duration); got != tc.want {
+				t.Errorf("shouldNotifySearch = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestNotifySearchFinishedWithoutRuntime verifies that a search finishing
+// with notifications enabled doesn't panic outside a Wails context.
+func TestNotifySearchFinishedWithoutRuntime(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{NotifyOnCompletion: true, NotifyMinSeconds: 1}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	app.notifySearchFinished(false, 3, time.Minute)
+	app.notifySearchFinished(true, 0, time.Minute)
+}
+
+// TestDefaultLogDir verifies that the default logs directory is absolute,
+// and follows $XDG_STATE_HOME on Linux.
+func TestDefaultLogDir(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	dir := defaultLogDir()
+	if !filepath.IsAbs(dir) {
+		t.Fatalf("expected an absolute logs directory, got %q", dir)
+	}
+	if want := filepath.Join(state, appDataDirName, "logs"); runtime.GOOS == "linux" && dir != want {
+		t.Errorf("expected %q, got %q", want, dir)
+	}
+}
+
+// TestLogDirSetting verifies that a saved logDir is used by the next App,
+// and that a relative one is rejected.
+func TestLogDirSetting(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{LogDir: "logs"}); err == nil || err.(*AppError).Code != ErrCodeLogDirInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeLogDirInvalid, err)
+	}
+
+	logDir := t.TempDir()
+	if _, err := app.UpdateSettings(Settings{LogDir: logDir}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	next := &App{dataDir: app.dataDir}
+	if got := next.resolveLogDir(); got != logDir {
+		t.Errorf("expected the saved logs directory %q, got %q", logDir, got)
+	}
+	next.logDir = logDir
+	next.setupLogger()
+	next.logInfo("hello", nil)
+	if _, err := os.Stat(filepath.Join(logDir, logFileName)); err != nil {
+		t.Errorf("expected the log written to the saved directory: %v", err)
+	}
+}
+
+// TestGetLogTail verifies that the last lines of app.log are returned, with
+// the count defaulted and capped, and that a missing log is empty.
+func TestGetLogTail(t *testing.T) {
+	app := &App{logDir: t.TempDir()}
+	if got, err := app.GetLogTail(10); err != nil || got != "" {
+		t.Errorf("expected no log yet, got %q, %v", got, err)
+	}
+
+	var lines []string
+	for i := range maxLogTailLines + 10 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	if err := os.WriteFile(app.GetLogFilePath(), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ lines, want int }{{3, 3}, {0, defaultLogTailLines}, {maxLogTailLines + 1, maxLogTailLines}}
+	for _, tt := range tests {
+		got, err := app.GetLogTail(tt.lines)
+		if err != nil {
+			t.Fatalf("GetLogTail(%d) failed: %v", tt.lines, err)
+		}
+		want := strings.Join(lines[len(lines)-tt.want:], "\n")
+		if got != want {
+			t.Errorf("GetLogTail(%d): expected the last %d lines, got %d", tt.lines, tt.want, len(strings.Split(got, "\n")))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// searchLinkParam is the codesearch://search query parameter that carries
+// a request from SerializeSearchRequest, e.g. codesearch://search?r=AXq….
+const searchLinkParam = "r"
+
+// searchLinkVersion is the first byte of a serialized request, before the
+// compressed JSON, so the layout can change without misreading old links.
+const searchLinkVersion byte = 1
+
+// maxSearchLinkSize bounds the decompressed JSON ParseSearchRequest accepts,
+// so a crafted link can't inflate to an unbounded size.
+const maxSearchLinkSize = 64 * 1024
+
+// sharedRequest returns req without the settings a link must not carry: a
+// result log path names a file on the sender's machine, and a confirmation
+// of an expensive search is for the person running it to give.
+func sharedRequest(req SearchRequest) SearchRequest {
+	req.ResultLogPath = ""
+	req.ConfirmExpensive = false
+	return req
+}
+
+// SerializeSearchRequest encodes req as a compact URL-safe string for
+// sharing a search: its JSON without the fields left at their zero values,
+// DEFLATE-compressed and base64url-encoded. Put it in a codesearch:// link
+// as codesearch://search?r=<encoded> for a teammate to open the same
+// search, or pass it to ParseSearchRequest.
+func (a *App) SerializeSearchRequest(req SearchRequest) (string, error) {
+	data, err := json.Marshal(sharedRequest(req))
+	if err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	for name, value := range fields {
+		// useRegex is a pointer whose nil means true, so an explicit false
+		// must stay.
+		if name == "useRegex" && string(value) != "null" {
+			continue
+		}
+		switch string(value) {
+		case "null", "false", "0", `""`, "[]":
+			delete(fields, name)
+		}
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(searchLinkVersion)
+	zw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ParseSearchRequest decodes a string from SerializeSearchRequest back into
+// the search it describes. The directory is the sender's and may not exist
+// here; the caller checks it as for any other search. A result log path or
+// expensive-search confirmation in the data is dropped.
+func (a *App) ParseSearchRequest(encoded string) (SearchRequest, error) {
+	return decodeSearchRequest(encoded)
+}
+
+// decodeSearchRequest is ParseSearchRequest, for parseDeepLink.
+func decodeSearchRequest(encoded string) (SearchRequest, error) {
+	// Tolerate padding and surrounding whitespace picked up by copy and paste.
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(encoded), "="))
+	if err != nil {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	if len(raw) == 0 {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, errors.New("empty"))
+	}
+	if raw[0] != searchLinkVersion {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, fmt.Errorf("unknown version %d", raw[0]))
+	}
+
+	zr := flate.NewReader(bytes.NewReader(raw[1:]))
+	defer zr.Close()
+	data, err := io.ReadAll(io.LimitReader(zr, maxSearchLinkSize+1))
+	if err != nil {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	if len(data) > maxSearchLinkSize {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, errors.New("too large"))
+	}
+	var req SearchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return SearchRequest{}, newAppError(ErrCodeSearchLinkInvalid, err)
+	}
+	return sharedRequest(req), nil
+}
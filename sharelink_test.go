@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSearchRequestRoundTrip verifies that a serialized request is URL-safe
+// and decodes to the same search, keeping an explicit literal mode and
+// dropping the fields a link must not carry.
+func TestSearchRequestRoundTrip(t *testing.T) {
+	app := NewApp()
+	useRegex := false
+	req := SearchRequest{
+		Directory:        "/home/me/project",
+		Query:            "TODO(me): fix + \"quote\"",
+		Extension:        "go",
+		CaseSensitive:    true,
+		MaxFileSize:      10485760,
+		MaxResults:       1000,
+		SearchSubdirs:    true,
+		UseRegex:         &useRegex,
+		ExcludePatterns:  []string{"node_modules", "*.log"},
+		AllowedFileTypes: []string{"go", "ts"},
+		Fuzziness:        1,
+		ResultLogPath:    "/home/me/results.ndjson",
+		ConfirmExpensive: true,
+	}
+
+	encoded, err := app.SerializeSearchRequest(req)
+	if err != nil {
+		t.Fatalf("SerializeSearchRequest failed: %v", err)
+	}
+	if url.QueryEscape(encoded) != encoded {
+		t.Errorf("expected a URL-safe string, got %q", encoded)
+	}
+	got, err := app.ParseSearchRequest(encoded)
+	if err != nil {
+		t.Fatalf("ParseSearchRequest failed: %v", err)
+	}
+	want := req
+	want.ResultLogPath = ""
+	want.ConfirmExpensive = false
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+
+	// Defaults are left out, so a minimal search gives a short string, and
+	// a nil useRegex stays nil.
+	short, err := app.SerializeSearchRequest(SearchRequest{Directory: "/src", Query: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(short) > 48 {
+		t.Errorf("expected a short string for a minimal search, got %d bytes", len(short))
+	}
+	if got, err := app.ParseSearchRequest(short + "==\n"); err != nil || got.UseRegex != nil || got.Query != "main" {
+		t.Errorf("unexpected minimal round trip %+v, %v", got, err)
+	}
+}
+
+// TestParseSearchRequestInvalid verifies that malformed, unknown-version,
+// and oversized data is rejected.
+func TestParseSearchRequestInvalid(t *testing.T) {
+	app := NewApp()
+	deflate := func(version byte, data []byte) string {
+		var buf bytes.Buffer
+		buf.WriteByte(version)
+		zw, _ := flate.NewWriter(&buf, flate.BestCompression)
+		zw.Write(data)
+		zw.Close()
+		return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	}
+
+	for name, encoded := range map[string]string{
+		"empty":          "",
+		"not base64":     "not base64!",
+		"not deflate":    base64.RawURLEncoding.EncodeToString([]byte{searchLinkVersion, 0xff, 0xff}),
+		"not JSON":       deflate(searchLinkVersion, []byte("query=x")),
+		"future version": deflate(searchLinkVersion+1, []byte(`{"query":"x"}`)),
+		"too large":      deflate(searchLinkVersion, []byte(`{"query":"`+strings.Repeat("a", maxSearchLinkSize)+`"}`)),
+	} {
+		if _, err := app.ParseSearchRequest(encoded); err == nil || err.(*AppError).Code != ErrCodeSearchLinkInvalid {
+			t.Errorf("%s: expected %s, got %v", name, ErrCodeSearchLinkInvalid, err)
+		}
+	}
+}
+
+// TestDeepLinkSharedRequest verifies that a codesearch:// link with a
+// serialized request carries every setting, that the directory is checked
+// like any launch directory, and that a broken request falls back to dir
+// and q.
+func TestDeepLinkSharedRequest(t *testing.T) {
+	app := NewApp()
+	dir := t.TempDir()
+	encoded, err := app.SerializeSearchRequest(SearchRequest{Directory: dir, Query: "needle", Extension: "go", CaseSensitive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := parseLaunchArgs([]string{"codesearch://search?r=" + encoded}, "")
+	if req.Request == nil || req.Directory != dir || req.Query != "needle" || req.Request.Extension != "go" || !req.Request.CaseSensitive {
+		t.Fatalf("unexpected launch request %+v", req)
+	}
+	app.launch = req
+	if got := app.GetLaunchRequest(); got.Request == nil || got.Request.Directory != dir {
+		t.Errorf("expected the shared directory to be kept, got %+v", got)
+	}
+
+	missing, err := app.SerializeSearchRequest(SearchRequest{Directory: "/no/such/dir", Query: "needle", Extension: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.launch = parseLaunchArgs([]string{"codesearch://search?r=" + missing}, "")
+	got := app.GetLaunchRequest()
+	if got.Directory != "" || got.Request == nil || got.Request.Directory != "" || got.Request.Extension != "go" {
+		t.Errorf("expected the missing directory to be dropped and the settings kept, got %+v", got)
+	}
+	if app.launch.Request.Directory != "/no/such/dir" {
+		t.Error("expected validation to leave the stored launch request alone")
+	}
+
+	if got := parseLaunchArgs([]string{"codesearch://search?r=broken&q=fallback"}, ""); got.Request != nil || got.Query != "fallback" {
+		t.Errorf("expected a broken request to fall back to q, got %+v", got)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shellMenuLabel is the text of the context-menu entry.
+const shellMenuLabel = "Open with code-search"
+
+// RegisterShellIntegration adds an "Open with code-search" entry to the
+// file manager's context menu for folders, pointing at the running
+// executable, and registers it as the handler of codesearch:// links. On
+// Windows these are per-user registry entries; on Linux .desktop files
+// (which file managers list under "Open With") plus a Nautilus script.
+// Registering again overwrites the entry, which also repairs it after
+// the app was moved.
+func (a *App) RegisterShellIntegration() error {
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		a.logError("Failed to resolve executable path", err, nil)
+		return newAppError(ErrCodeShellIntegrationFailed, err)
+	}
+
+	if err := installShellIntegration(exe); err != nil {
+		a.logError("Failed to register shell integration", err, logrus.Fields{
+			"executable": exe,
+		})
+		return newAppError(ErrCodeShellIntegrationFailed, err)
+	}
+
+	a.logInfo("Registered shell integration", logrus.Fields{
+		"executable": exe,
+	})
+	return nil
+}
+
+// UnregisterShellIntegration removes the context-menu entry added by
+// RegisterShellIntegration. Removing an entry that isn't there is not an
+// error.
+func (a *App) UnregisterShellIntegration() error {
+	if err := removeShellIntegration(); err != nil {
+		a.logError("Failed to unregister shell integration", err, nil)
+		return newAppError(ErrCodeShellIntegrationFailed, err)
+	}
+
+	a.logInfo("Unregistered shell integration", nil)
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestShellIntegrationRoundTrip verifies that registering writes the
+// .desktop files and an executable Nautilus script under XDG_DATA_HOME, and
+// that unregistering removes them and is idempotent.
+func TestShellIntegrationRoundTrip(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	app := NewApp()
+
+	if err := app.RegisterShellIntegration(); err != nil {
+		t.Fatalf("RegisterShellIntegration failed: %v", err)
+	}
+
+	desktopFile := filepath.Join(dataHome, "applications", shellDesktopFileName)
+	entry, err := os.ReadFile(desktopFile)
+	if err != nil {
+		t.Fatalf("reading desktop file: %v", err)
+	}
+	for _, want := range []string{"MimeType=inode/directory;", "--dir %f", "Name=" + shellMenuLabel} {
+		if !strings.Contains(string(entry), want) {
+			t.Errorf("desktop file missing %q:\n%s", want, entry)
+		}
+	}
+
+	deepLinkFile := filepath.Join(dataHome, "applications", deepLinkDesktopFileName)
+	entry, err = os.ReadFile(deepLinkFile)
+	if err != nil {
+		t.Fatalf("reading deep-link desktop file: %v", err)
+	}
+	if !strings.Contains(string(entry), "MimeType=x-scheme-handler/codesearch;") {
+		t.Errorf("deep-link desktop file missing scheme handler:\n%s", entry)
+	}
+
+	script := filepath.Join(dataHome, "nautilus", "scripts", shellMenuLabel)
+	info, err := os.Stat(script)
+	if err != nil {
+		t.Fatalf("stat nautilus script: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("expected nautilus script to be executable, mode %v", info.Mode())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := app.UnregisterShellIntegration(); err != nil {
+			t.Fatalf("UnregisterShellIntegration #%d failed: %v", i+1, err)
+		}
+	}
+	for _, path := range []string{desktopFile, deepLinkFile, script} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", path, err)
+		}
+	}
+}
+
+// TestDesktopExecQuote verifies the .desktop Exec escaping of reserved
+// characters and field-code percent signs.
+func TestDesktopExecQuote(t *testing.T) {
+	cases := map[string]string{
+		"/opt/app":    `"/opt/app"`,
+		"/opt/my app": `"/opt/my app"`,
+		`/opt/a"b`:    `"/opt/a\\"b"`,
+		"/opt/100%":   `"/opt/100%%"`,
+		"/opt/$HOME":  `"/opt/\\$HOME"`,
+	}
+	for in, want := range cases {
+		if got := desktopExecQuote(in); got != want {
+			t.Errorf("desktopExecQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
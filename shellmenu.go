@@ -0,0 +1,147 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Desktop entries installed by installShellIntegration. The first is listed
+// under "Open With" for folders (MimeType inode/directory); the second makes
+// the app the handler of codesearch:// links.
+const (
+	shellDesktopFileName    = "code-search-golang-open.desktop"
+	deepLinkDesktopFileName = "code-search-golang-url.desktop"
+)
+
+// xdgDataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func xdgDataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// desktopExecQuote quotes an argument for the Exec key of a .desktop file,
+// which uses its own escaping rules rather than the shell's. A literal "%"
+// has to be doubled so it isn't read as a field code.
+func desktopExecQuote(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range arg {
+		switch r {
+		case '"', '`', '$', '\\':
+			b.WriteByte('\\')
+		case '%':
+			b.WriteByte('%')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	// The desktop-entry spec applies string unescaping before Exec quoting,
+	// so backslashes have to be doubled once more.
+	return strings.ReplaceAll(b.String(), `\`, `\\`)
+}
+
+// shellQuote single-quotes an argument for a POSIX shell script.
+func shellQuote(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// shellIntegrationFile is one file written by installShellIntegration.
+type shellIntegrationFile struct {
+	path    string
+	content string
+	mode    os.FileMode
+}
+
+// shellIntegrationFiles returns the files that make up the shell
+// integration for exe: the folder .desktop entry, the codesearch:// handler
+// entry, and the Nautilus script.
+func shellIntegrationFiles(exe string) ([]shellIntegrationFile, error) {
+	dataHome, err := xdgDataHome()
+	if err != nil {
+		return nil, err
+	}
+	applications := filepath.Join(dataHome, "applications")
+
+	folderEntry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=%s
+Exec=%s %s %%f
+MimeType=inode/directory;
+NoDisplay=true
+Terminal=false
+`, shellMenuLabel, desktopExecQuote(exe), launchDirFlag)
+
+	deepLinkEntry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=code-search
+Exec=%s %%u
+MimeType=x-scheme-handler/%s;
+NoDisplay=true
+Terminal=false
+`, desktopExecQuote(exe), deepLinkScheme)
+
+	// Nautilus runs scripts with the selected items as arguments and the
+	// current folder as the working directory, so a right-click on the
+	// folder background opens that folder. A selected file opens its parent.
+	script := fmt.Sprintf(`#!/bin/sh
+# Installed by code-search-golang. Remove it from the app's settings.
+dir="${1:-$PWD}"
+[ -d "$dir" ] || dir=$(dirname -- "$dir")
+exec %s %s "$dir"
+`, shellQuote(exe), launchDirFlag)
+
+	return []shellIntegrationFile{
+		{filepath.Join(applications, shellDesktopFileName), folderEntry, 0o644},
+		{filepath.Join(applications, deepLinkDesktopFileName), deepLinkEntry, 0o644},
+		{filepath.Join(dataHome, "nautilus", "scripts", shellMenuLabel), script, 0o755},
+	}, nil
+}
+
+// installShellIntegration writes the .desktop entries and the Nautilus
+// script.
+func installShellIntegration(exe string) error {
+	files, err := shellIntegrationFiles(exe)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), f.mode); err != nil {
+			return err
+		}
+		// WriteFile keeps the mode of an existing file, e.g. a script that
+		// lost its executable bit.
+		if err := os.Chmod(f.path, f.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeShellIntegration deletes the files written by
+// installShellIntegration.
+func removeShellIntegration() error {
+	files, err := shellIntegrationFiles("")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
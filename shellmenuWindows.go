@@ -0,0 +1,102 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// Explorer verbs for folders are read from HKCU\Software\Classes, so
+// registering needs no elevation. "Directory\shell" covers right-clicking a
+// folder; "Directory\Background\shell" covers the empty area inside one.
+const shellVerbKey = `code-search-golang`
+
+// shellVerbParents maps each verb's parent key to the placeholder Explorer
+// substitutes with the folder path.
+var shellVerbParents = []struct {
+	key         string
+	placeholder string
+}{
+	{`Software\Classes\Directory\shell`, "%1"},
+	{`Software\Classes\Directory\Background\shell`, "%V"},
+}
+
+// deepLinkKey is the per-user URL protocol registration for codesearch://
+// links. The "URL Protocol" value marks the class as a protocol handler.
+const deepLinkKey = `Software\Classes\` + deepLinkScheme
+
+// installShellIntegration writes the Explorer context-menu verbs and the
+// codesearch:// protocol handler.
+func installShellIntegration(exe string) error {
+	if err := installDeepLinkHandler(exe); err != nil {
+		return err
+	}
+	for _, parent := range shellVerbParents {
+		verb, _, err := registry.CreateKey(registry.CURRENT_USER, parent.key+`\`+shellVerbKey, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = verb.SetStringValue("", shellMenuLabel)
+		if err == nil {
+			err = verb.SetStringValue("Icon", exe)
+		}
+		verb.Close()
+		if err != nil {
+			return err
+		}
+
+		command, _, err := registry.CreateKey(registry.CURRENT_USER, parent.key+`\`+shellVerbKey+`\command`, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = command.SetStringValue("", fmt.Sprintf(`"%s" %s "%s"`, exe, launchDirFlag, parent.placeholder))
+		command.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installDeepLinkHandler registers exe as the handler of codesearch:// links.
+func installDeepLinkHandler(exe string) error {
+	class, _, err := registry.CreateKey(registry.CURRENT_USER, deepLinkKey, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	err = class.SetStringValue("", "URL:code-search")
+	if err == nil {
+		err = class.SetStringValue("URL Protocol", "")
+	}
+	class.Close()
+	if err != nil {
+		return err
+	}
+
+	command, _, err := registry.CreateKey(registry.CURRENT_USER, deepLinkKey+`\shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer command.Close()
+	return command.SetStringValue("", fmt.Sprintf(`"%s" "%%1"`, exe))
+}
+
+// removeShellIntegration deletes the verbs and the protocol handler.
+// DeleteKey doesn't recurse, so subkeys go first.
+func removeShellIntegration() error {
+	var keys []string
+	for _, parent := range shellVerbParents {
+		keys = append(keys, parent.key+`\`+shellVerbKey+`\command`, parent.key+`\`+shellVerbKey)
+	}
+	keys = append(keys, deepLinkKey+`\shell\open\command`, deepLinkKey+`\shell\open`, deepLinkKey+`\shell`, deepLinkKey)
+
+	for _, key := range keys {
+		if err := registry.DeleteKey(registry.CURRENT_USER, key); err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestShowInFolderFallsBack verifies that ShowInFolder moves past a
+// launcher that exits with an error to the next file manager found.
+func TestShowInFolderFallsBack(t *testing.T) {
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	scripts := map[string]string{
+		"xdg-open": "#!/bin/sh\nexit 4\n",
+		"thunar":   "#!/bin/sh\necho \"$@\" > " + argsFile + "\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(target, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp()
+	if err := app.ShowInFolder(target); err != nil {
+		t.Fatalf("ShowInFolder failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(argsFile)
+		if err == nil && len(data) > 0 {
+			if got := strings.TrimSpace(string(data)); got != dir {
+				t.Errorf("expected thunar to get %s, got %q", dir, got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("thunar was not launched")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestShowInFolderNoFileManager verifies the NO_FILE_MANAGER error and its
+// per-program details when nothing is installed.
+func TestShowInFolderNoFileManager(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if fileManagerAvailable() {
+		t.Fatal("expected no file manager on an empty PATH")
+	}
+
+	target := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(target, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := NewApp().ShowInFolder(target)
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeNoFileManager {
+		t.Fatalf("expected %s, got %v", ErrCodeNoFileManager, err)
+	}
+	attempts, _ := appErr.Details.([]FileManagerAttempt)
+	if len(attempts) != len(fileManagers) {
+		t.Fatalf("expected an attempt per file manager, got %+v", appErr.Details)
+	}
+	for _, attempt := range attempts {
+		if attempt.Found {
+			t.Errorf("expected %s not to be found", attempt.Command)
+		}
+	}
+	if !strings.Contains(appErr.Error(), "xdg-open, gio, nautilus") {
+		t.Errorf("expected the message to list what was tried, got %q", appErr.Error())
+	}
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// networkFSMagic lists the statfs f_type values of network and FUSE-backed
+// filesystems. Searching these with full parallelism hammers the mount with
+// concurrent opens, so a root on any of them switches the search into
+// slow-FS mode.
+var networkFSMagic = map[int64]string{
+	0x6969:     "nfs",
+	0x517B:     "smb",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x65735546: "fuse",
+	0x01021997: "9p",
+	0x5346414F: "afs",
+	0x0BD00BD0: "lustre",
+}
+
+// isNetworkPath reports whether the given directory lives on a network or
+// FUSE filesystem. Errors from statfs are treated as "local" so detection
+// never blocks a search.
+func isNetworkPath(dir string) bool {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return false
+	}
+	_, ok := networkFSMagic[int64(fs.Type)]
+	return ok
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// driveRemote is the GetDriveTypeW result for a mapped network drive.
+const driveRemote = 4
+
+var procGetDriveTypeW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDriveTypeW")
+
+// isNetworkPath reports whether the given directory is a UNC share
+// (\\server\share) or lives on a mapped network drive. Any lookup failure is
+// treated as "local" so detection never blocks a search.
+func isNetworkPath(dir string) bool {
+	path := fromLongPath(dir)
+	if strings.HasPrefix(path, `\\`) {
+		return true
+	}
+
+	volume := filepath.VolumeName(path)
+	if volume == "" {
+		return false
+	}
+	root, err := syscall.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return false
+	}
+	driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(root)))
+	return driveType == driveRemote
+}
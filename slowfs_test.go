@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// TestSearchWorkersSlowFS verifies that slow-FS mode caps the worker pool
+// and that the normal mode still scales with the CPU count.
+func TestSearchWorkersSlowFS(t *testing.T) {
+	if got := searchWorkers(SearchRequest{SlowFS: true}); got != slowFSWorkers {
+		t.Errorf("expected %d workers in slow-FS mode, got %d", slowFSWorkers, got)
+	}
+	if got := searchWorkers(SearchRequest{}); got != numCPU() {
+		t.Errorf("expected %d workers in normal mode, got %d", numCPU(), got)
+	}
+}
+
+// TestIsNetworkPathLocalDir verifies that a local temp directory is not
+// mistaken for a network mount, which would silently slow down every search.
+func TestIsNetworkPathLocalDir(t *testing.T) {
+	if isNetworkPath(t.TempDir()) {
+		t.Skip("temp directory is on a network filesystem in this environment")
+	}
+	if isNetworkPath(filepath.Join(t.TempDir(), "does-not-exist")) {
+		t.Error("expected a missing directory to be treated as local")
+	}
+}
+
+// TestSlowFSDefersBinaryCheck verifies that slow-FS mode hands unknown-extension
+// files to the workers unprobed, and that binary files among them are still
+// dropped by the in-worker check.
+func TestSlowFSDefersBinaryCheck(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "notes.unknownext"), []byte("needle here\n"), 0o644); err != nil {
+		t.Fatalf("creating text file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "blob.unknownext"), []byte("needle\x00\x01\x02"), 0o644); err != nil {
+		t.Fatalf("creating binary file: %v", err)
+	}
+
+	req := SearchRequest{
+		Directory:     tempDir,
+		Query:         "needle",
+		SearchSubdirs: true,
+		MaxFileSize:   10 * 1024 * 1024,
+		MaxResults:    1000,
+		SlowFS:        true,
+	}
+	files, err := app.collectFilesToProcess(req, regexp.MustCompile("needle"), tempDir)
+	if err != nil {
+		t.Fatalf("collectFilesToProcess failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected both files to be collected unprobed, got %d", len(files))
+	}
+	for _, meta := range files {
+		if !meta.checkBinary {
+			t.Errorf("expected checkBinary on %s", meta.absPath)
+		}
+	}
+
+	results, err := app.SearchWithProgress(req)
+	if err != nil {
+		t.Fatalf("SearchWithProgress failed: %v", err)
+	}
+	if len(results) != 1 || filepath.Base(results[0].FilePath) != "notes.unknownext" {
+		t.Errorf("expected a single match in notes.unknownext, got %+v", results)
+	}
+}
+
+// TestProcessFileCheckBinaryStreaming verifies the deferred binary check on
+// the streaming path, where only the file head is read before streaming.
+func TestProcessFileCheckBinaryStreaming(t *testing.T) {
+	app := NewApp()
+	tempDir := t.TempDir()
+
+	content := make([]byte, streamingThreshold+1024)
+	for i := range content {
+		content[i] = 'a'
+	}
+	copy(content, "needle\x00")
+	path := filepath.Join(tempDir, "large.unknownext")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("creating large file: %v", err)
+	}
+
+	req := SearchRequest{Directory: tempDir, Query: "needle", MaxResults: 1000, MaxFileSize: 10 * 1024 * 1024}
+	pattern := regexp.MustCompile("needle")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	meta := fileMeta{absPath: path, size: int64(len(content)), checkBinary: true}
+	if _, results := app.processFile(ctx, meta, pattern, req, &SearchState{}, new(int32), cancel); len(results) != 0 {
+		t.Errorf("expected binary large file to be skipped, got %d results", len(results))
+	}
+}
+
+// TestEmitFileProgressThrottledInSlowFS verifies that the processed-file
+// counter still advances on every call when progress events are throttled.
+func TestEmitFileProgressThrottledInSlowFS(t *testing.T) {
+	app := NewApp()
+	searchState := &SearchState{}
+	for i := 0; i < slowFSProgressInterval+5; i++ {
+		app.emitFileProgress("search-1", searchState, 100, "file.txt", true)
+	}
+	if got := int(searchState.processedFiles); got != slowFSProgressInterval+5 {
+		t.Errorf("expected processedFiles=%d, got %d", slowFSProgressInterval+5, got)
+	}
+}
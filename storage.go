@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appDataDirName is the per-user directory (under os.UserConfigDir) that
+// holds persisted application state such as the last session.
+const appDataDirName = "code-search-golang"
+
+// defaultDataDir returns the per-user data directory, or "" when the
+// platform has no config directory (e.g. $HOME unset). With no data
+// directory, state is simply not persisted.
+func defaultDataDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, appDataDirName)
+}
+
+// logFileName is the file in the logs directory that the logger writes and
+// the log viewer tails.
+const logFileName = "app.log"
+
+// defaultLogDir returns the per-user logs directory: ~/Library/Logs on
+// macOS, the local app data directory on Windows, and $XDG_STATE_HOME
+// (~/.local/state) elsewhere. Without a home directory it falls back to the
+// temp directory, never to the working directory, which is / or the app
+// bundle when launched from a .desktop file or the Finder.
+func defaultLogDir() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		if home != "" {
+			return filepath.Join(home, "Library", "Logs", appDataDirName)
+		}
+	case "windows":
+		if dir, err := os.UserCacheDir(); err == nil {
+			return filepath.Join(dir, appDataDirName, "logs")
+		}
+	default:
+		if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+			return filepath.Join(dir, appDataDirName, "logs")
+		}
+		if home != "" {
+			return filepath.Join(home, ".local", "state", appDataDirName, "logs")
+		}
+	}
+	return filepath.Join(os.TempDir(), appDataDirName, "logs")
+}
+
+// resolveLogDir returns the logs directory: the logDir setting saved in the
+// data directory, or defaultLogDir. The logger is set up before anything
+// else, so the setting is read straight from the file rather than through
+// currentSettings.
+func (a *App) resolveLogDir() string {
+	var saved struct {
+		LogDir string `json:"logDir"`
+	}
+	if _, err := a.loadJSON(settingsFileName, &saved); err == nil && filepath.IsAbs(saved.LogDir) {
+		return saved.LogDir
+	}
+	return defaultLogDir()
+}
+
+// loadJSON decodes the named file from the data directory into v. It reports
+// false without an error when the file doesn't exist yet, so callers can
+// fall back to defaults on first run.
+func (a *App) loadJSON(name string, v interface{}) (bool, error) {
+	if a.dataDir == "" {
+		return false, nil
+	}
+
+	a.storeMu.Lock()
+	data, err := os.ReadFile(filepath.Join(a.dataDir, name))
+	a.storeMu.Unlock()
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// saveJSON encodes v into the named file in the data directory. The file is
+// written to a temp file and renamed into place so a crash mid-write never
+// leaves a truncated file behind.
+func (a *App) saveJSON(name string, v interface{}) error {
+	if a.dataDir == "" {
+		return fmt.Errorf("no data directory available to save %s", name)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+
+	if err := os.MkdirAll(a.dataDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(a.dataDir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(a.dataDir, name)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeDataFile deletes the named file from the data directory. A file that
+// doesn't exist is not an error.
+func (a *App) removeDataFile(name string) error {
+	if a.dataDir == "" {
+		return nil
+	}
+
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+
+	if err := os.Remove(filepath.Join(a.dataDir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", name, err)
+	}
+	return nil
+}
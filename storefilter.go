@@ -0,0 +1,285 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// storeCondition is one comparison in a result store filter, such as
+// path LIKE '%.go' or content MATCH 'open file*'.
+type storeCondition struct {
+	field string // One of storeFilterFields
+	op    string // "=", "!=", "LIKE", or "MATCH"
+	value string
+
+	like *regexp.Regexp // Compiled LIKE pattern
+	line int            // Parsed value of a line condition
+}
+
+// storeFilterFields maps each filter field to the operators it accepts.
+// search, query, and directory compare the stored search; the others
+// compare each result.
+var storeFilterFields = map[string][]string{
+	"search":    {"=", "!="},
+	"query":     {"=", "!=", "LIKE"},
+	"directory": {"=", "!=", "LIKE"},
+	"path":      {"=", "!=", "LIKE"},
+	"line":      {"=", "!="},
+	"content":   {"=", "!=", "LIKE", "MATCH"},
+}
+
+// searchLevel reports whether the condition compares the stored search
+// rather than a result.
+func (c storeCondition) searchLevel() bool {
+	return c.field == "search" || c.field == "query" || c.field == "directory"
+}
+
+// matchString applies a string comparison. Comparisons are
+// case-insensitive, like the search box.
+func (c storeCondition) matchString(s string) bool {
+	switch c.op {
+	case "=":
+		return strings.EqualFold(s, c.value)
+	case "!=":
+		return !strings.EqualFold(s, c.value)
+	case "LIKE":
+		return c.like.MatchString(s)
+	}
+	return false
+}
+
+// parseStoreFilter parses a filter of the form
+//
+//	field op 'value' [AND field op 'value' ...]
+//
+// Values are single- or double-quoted strings (” escapes a quote) or bare
+// numbers. Keywords are case-insensitive. An empty filter has no
+// conditions and matches every stored result.
+func parseStoreFilter(filter string) ([]storeCondition, error) {
+	tokens, err := lexStoreFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var conds []storeCondition
+	for i := 0; i < len(tokens); {
+		if len(conds) > 0 {
+			if !strings.EqualFold(tokens[i].text, "AND") || tokens[i].quoted {
+				return nil, newAppError(ErrCodeInvalidStoreFilter, "expected AND before "+tokens[i].text)
+			}
+			i++
+		}
+		if i+3 > len(tokens) {
+			return nil, newAppError(ErrCodeInvalidStoreFilter, "incomplete condition at end of filter")
+		}
+		field, op, value := tokens[i], tokens[i+1], tokens[i+2]
+		i += 3
+
+		cond := storeCondition{
+			field: strings.ToLower(field.text),
+			op:    strings.ToUpper(op.text),
+			value: value.text,
+		}
+		if op.text == "<>" {
+			cond.op = "!="
+		}
+		ops, ok := storeFilterFields[cond.field]
+		if !ok || field.quoted {
+			return nil, newAppError(ErrCodeInvalidStoreFilter, "unknown field "+field.text)
+		}
+		if op.quoted || !containsString(ops, cond.op) {
+			return nil, newAppError(ErrCodeInvalidStoreFilter, op.text+" is not supported for "+cond.field)
+		}
+
+		switch {
+		case cond.field == "line":
+			n, err := strconv.Atoi(cond.value)
+			if err != nil {
+				return nil, newAppError(ErrCodeInvalidStoreFilter, "line needs a number, got "+cond.value)
+			}
+			cond.line = n
+		case cond.op == "LIKE":
+			cond.like = likePattern(cond.value)
+		case cond.op == "MATCH" && len(ftsTerms(cond.value)) == 0:
+			return nil, newAppError(ErrCodeInvalidStoreFilter, "MATCH needs at least one word")
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// storeFilterToken is a word, operator, or string in a filter.
+type storeFilterToken struct {
+	text   string
+	quoted bool
+}
+
+// lexStoreFilter splits a filter into tokens.
+func lexStoreFilter(filter string) ([]storeFilterToken, error) {
+	var tokens []storeFilterToken
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					// A doubled quote is a literal quote.
+					if j+1 < len(runes) && runes[j+1] == r {
+						b.WriteRune(r)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, newAppError(ErrCodeInvalidStoreFilter, "unterminated string")
+			}
+			tokens = append(tokens, storeFilterToken{text: b.String(), quoted: true})
+			i = j + 1
+		case r == '=':
+			tokens = append(tokens, storeFilterToken{text: "="})
+			i++
+		case r == '!' || r == '<':
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				tokens = append(tokens, storeFilterToken{text: string(runes[i : i+2])})
+				i += 2
+				continue
+			}
+			return nil, newAppError(ErrCodeInvalidStoreFilter, "unexpected "+string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '-') {
+				j++
+			}
+			tokens = append(tokens, storeFilterToken{text: string(runes[i:j])})
+			i = j
+		default:
+			return nil, newAppError(ErrCodeInvalidStoreFilter, "unexpected "+string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// likePattern compiles an SQL LIKE pattern (% any run, _ any character)
+// into a case-insensitive regexp over the whole string.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// containsString reports whether list holds s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ftsTokens splits text into the lowercased words the full-text index is
+// built from: runs of letters, digits, and underscores.
+func ftsTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// ftsTerm is one word of a MATCH query; prefix terms end in * and match any
+// word starting with them.
+type ftsTerm struct {
+	word   string
+	prefix bool
+}
+
+// ftsTerms parses a MATCH query into its terms.
+func ftsTerms(query string) []ftsTerm {
+	var terms []ftsTerm
+	for _, field := range strings.Fields(query) {
+		prefix := strings.HasSuffix(field, "*")
+		for _, word := range ftsTokens(field) {
+			terms = append(terms, ftsTerm{word: word})
+		}
+		if prefix && len(terms) > 0 {
+			terms[len(terms)-1].prefix = true
+		}
+	}
+	return terms
+}
+
+// ftsIndex maps each word of the results' content to the indexes of the
+// results containing it.
+type ftsIndex map[string][]int
+
+// newFTSIndex indexes the content of results.
+func newFTSIndex(results []SearchResult) ftsIndex {
+	index := make(ftsIndex)
+	for i, result := range results {
+		seen := make(map[string]bool)
+		for _, word := range ftsTokens(result.Content) {
+			if !seen[word] {
+				seen[word] = true
+				index[word] = append(index[word], i)
+			}
+		}
+	}
+	return index
+}
+
+// match returns the indexes of the results containing every term, in
+// ascending order.
+func (idx ftsIndex) match(terms []ftsTerm) []int {
+	var hits map[int]bool
+	for _, term := range terms {
+		termHits := make(map[int]bool)
+		add := func(rows []int) {
+			for _, row := range rows {
+				if hits == nil || hits[row] {
+					termHits[row] = true
+				}
+			}
+		}
+		if term.prefix {
+			for word, rows := range idx {
+				if strings.HasPrefix(word, term.word) {
+					add(rows)
+				}
+			}
+		} else {
+			add(idx[term.word])
+		}
+		hits = termHits
+		if len(hits) == 0 {
+			return nil
+		}
+	}
+
+	rows := make([]int, 0, len(hits))
+	for row := range hits {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	return rows
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestStreamingSettingsNormalized verifies that unset streaming settings get
+// the defaults and out-of-range ones are clamped.
+func TestStreamingSettingsNormalized(t *testing.T) {
+	s, err := normalizeSettings(Settings{})
+	if err != nil {
+		t.Fatalf("normalizeSettings failed: %v", err)
+	}
+	if s.StreamingThreshold != streamingThreshold || s.ScannerBufferSize != defaultScannerBufferSize {
+		t.Errorf("expected defaults, got threshold %d and buffer %d", s.StreamingThreshold, s.ScannerBufferSize)
+	}
+
+	s, _ = normalizeSettings(Settings{StreamingThreshold: 1, ScannerBufferSize: 1 << 30})
+	if s.StreamingThreshold != minStreamingThreshold || s.ScannerBufferSize != maxScannerBufferSize {
+		t.Errorf("expected clamped values, got threshold %d and buffer %d", s.StreamingThreshold, s.ScannerBufferSize)
+	}
+}
+
+// TestValidateStreamingDefaultsFromSettings verifies that a request without
+// streaming sizes takes them from the settings, and that explicit request
+// values win but are clamped.
+func TestValidateStreamingDefaultsFromSettings(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{StreamingThreshold: 8 << 20, ScannerBufferSize: 4 << 20}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	req, err := app.validateAndSetDefaults(SearchRequest{Directory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("validateAndSetDefaults failed: %v", err)
+	}
+	if req.StreamingThreshold != 8<<20 || req.ScannerBufferSize != 4<<20 {
+		t.Errorf("expected settings values, got threshold %d and buffer %d", req.StreamingThreshold, req.ScannerBufferSize)
+	}
+
+	req, _ = app.validateAndSetDefaults(SearchRequest{Directory: t.TempDir(), StreamingThreshold: 100, ScannerBufferSize: 128 << 10})
+	if req.StreamingThreshold != minStreamingThreshold || req.ScannerBufferSize != 128<<10 {
+		t.Errorf("expected clamped threshold and explicit buffer, got %d and %d", req.StreamingThreshold, req.ScannerBufferSize)
+	}
+}
+
+// TestScannerBufferSizeLimitsLineLength verifies that the scanner buffer
+// decides how long a line the streaming path accepts.
+func TestScannerBufferSizeLimitsLineLength(t *testing.T) {
+	app := NewApp()
+	path := filepath.Join(t.TempDir(), "long.log")
+	line := "needle " + strings.Repeat("x", 100*1024)
+	if err := os.WriteFile(path, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+	pattern := regexp.MustCompile("needle")
+
+	if _, err := app.processFileLineByLine(context.Background(), path, pattern, 10, minScannerBufferSize); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("expected ErrTooLong with a 64KB buffer, got %v", err)
+	}
+	results, err := app.processFileLineByLine(context.Background(), path, pattern, 10, 256*1024)
+	if err != nil || len(results) != 1 {
+		t.Errorf("expected 1 result with a 256KB buffer, got %d (err %v)", len(results), err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitModulesFileName lists a repository's submodules and where their
+// working trees are checked out.
+const gitModulesFileName = ".gitmodules"
+
+// parseGitModules returns the path entries of a .gitmodules file, in slash
+// form relative to the repository root.
+func parseGitModules(data []byte) []string {
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "path" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		if value != "" {
+			paths = append(paths, strings.Trim(value, "/"))
+		}
+	}
+	return paths
+}
+
+// loadSubmoduleDirs returns the absolute working-tree directories of the
+// submodules of the repository containing dir: the nearest dir or ancestor
+// with a .git entry, whose .gitmodules is read. A submodule's own working
+// tree has a .git file, so a search started inside one treats it as the
+// repository. It returns nil outside a repository or when it has no
+// submodules.
+func loadSubmoduleDirs(dir string) map[string]bool {
+	for current := dir; ; {
+		if _, err := os.Lstat(toLongPath(filepath.Join(current, ".git"))); err == nil {
+			data, err := os.ReadFile(toLongPath(filepath.Join(current, gitModulesFileName)))
+			if err != nil {
+				return nil
+			}
+			var dirs map[string]bool
+			for _, p := range parseGitModules(data) {
+				if dirs == nil {
+					dirs = make(map[string]bool)
+				}
+				dirs[filepath.Join(current, filepath.FromSlash(p))] = true
+			}
+			return dirs
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil
+		}
+		current = parent
+	}
+}
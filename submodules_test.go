@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestParseGitModules verifies that path entries are read, with quotes and
+// slashes trimmed, and other keys ignored.
+func TestParseGitModules(t *testing.T) {
+	data := []byte(`[submodule "lib"]
+	path = third_party/lib
+	url = https://example.com/lib.git
+[submodule "docs"]
+	path = "docs/site/"
+`)
+	want := []string{"third_party/lib", "docs/site"}
+	if got := parseGitModules(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGitModules = %q, want %q", got, want)
+	}
+}
+
+// TestSearchSkipsSubmodules verifies that submodule working trees are
+// skipped by default, searched with IncludeSubmodules, and searched when
+// the search starts inside one.
+func TestSearchSkipsSubmodules(t *testing.T) {
+	app := NewApp()
+	repo := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":              "ref: refs/heads/main\n",
+		".gitmodules":            "[submodule \"vendored\"]\n\tpath = libs/vendored\n",
+		"main.go":                "// needle in the repository\n",
+		"libs/local.go":          "// needle next to the submodule\n",
+		"libs/vendored/.git":     "gitdir: ../../.git/modules/vendored\n",
+		"libs/vendored/lib.go":   "// needle in the submodule\n",
+		"libs/vendored/sub/x.go": "// needle deeper in the submodule\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	search := func(dir string, include bool) int {
+		t.Helper()
+		results, err := app.SearchWithProgress(SearchRequest{Directory: dir, Query: "needle", SearchSubdirs: true, IncludeSubmodules: include})
+		if err != nil {
+			t.Fatalf("SearchWithProgress failed: %v", err)
+		}
+		return len(results)
+	}
+	if n := search(repo, false); n != 2 {
+		t.Errorf("expected 2 results outside the submodule, got %d", n)
+	}
+	if n := search(filepath.Join(repo, "libs"), false); n != 1 {
+		t.Errorf("expected the submodule skipped from a subdirectory search, got %d results", n)
+	}
+	if n := search(repo, true); n != 4 {
+		t.Errorf("expected 4 results with IncludeSubmodules, got %d", n)
+	}
+	if n := search(filepath.Join(repo, "libs", "vendored"), false); n != 2 {
+		t.Errorf("expected 2 results searching inside the submodule, got %d", n)
+	}
+}
@@ -9,13 +9,17 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// detectAvailableEditors checks which editors are available on the system
-func (a *App) detectAvailableEditors() {
+// probeEditors checks which editors are available on the system and caches
+// the result for the next startup (see detectAvailableEditors). Editors with
+// a path in Settings.EditorPaths count as available without a PATH lookup;
+// those missing from PATH are looked for where they are usually installed.
+func (a *App) probeEditors() {
 	// Emit event to notify frontend that editor detection is starting
 	a.safeEmitEvent("editor-detection-start", map[string]interface{}{
 		"message": "Detecting available code editors...",
@@ -51,12 +55,18 @@ func (a *App) detectAvailableEditors() {
 		{"NetBeans", "netbeans", func(available bool) { a.availableEditors.NetBeans = available }},
 		{"Neovim", "nvim", func(available bool) { a.availableEditors.Neovim = available }},
 		{"Vim", "vim", func(available bool) { a.availableEditors.Vim = available }},
+		{"Nano", "nano", func(available bool) { a.availableEditors.Nano = available }},
+		{"Helix", "hx", func(available bool) { a.availableEditors.Helix = available }},
 	}
 
 	// Check each editor in parallel. Each probe is an independent exec.LookPath
 	// (a PATH scan), so running them concurrently turns ~21 sequential scans into
 	// roughly the cost of a single one. Results are written under editorsMu.
 	totalEditors := len(editorsToCheck)
+	editorPaths := a.currentSettings().EditorPaths
+	a.editorsMu.Lock()
+	a.editorBinaries = make(map[string]string)
+	a.editorsMu.Unlock()
 	var wg sync.WaitGroup
 	var completed int32
 	for _, editor := range editorsToCheck {
@@ -67,7 +77,9 @@ func (a *App) detectAvailableEditors() {
 			setter  func(bool)
 		}) {
 			defer wg.Done()
-			available := a.isEditorAvailable(editor.command)
+			available := editorPathFor(editorPaths, editor.command) != "" ||
+				a.isEditorAvailable(editor.command) ||
+				a.discoverEditor(editor.command)
 
 			a.editorsMu.Lock()
 			editor.setter(available)
@@ -101,7 +113,9 @@ func (a *App) detectAvailableEditors() {
 
 	// System default is conceptually always available
 	a.availableEditors.SystemDefault = true
+	a.editorsCheckedAt = time.Now().UnixMilli()
 	a.editorsMu.Unlock()
+	a.saveEditorCache()
 
 	// Emit completion event
 	a.safeEmitEvent("editor-detection-complete", map[string]interface{}{
@@ -120,6 +134,7 @@ func (a *App) availableEditorFields(ed *EditorAvailability) []*bool {
 		&ed.PhpStorm, &ed.CLion, &ed.Rider, &ed.AndroidStudio, &ed.Emacs,
 		&ed.Neovide, &ed.CodeBlocks, &ed.DevCpp, &ed.NotepadPlusPlus,
 		&ed.VisualStudio, &ed.Eclipse, &ed.NetBeans, &ed.Neovim, &ed.Vim,
+		&ed.Nano, &ed.Helix,
 	}
 }
 
@@ -145,6 +160,7 @@ func countEditorsFromSnapshot(ed EditorAvailability) int {
 		&ed.PhpStorm, &ed.CLion, &ed.Rider, &ed.AndroidStudio, &ed.Emacs,
 		&ed.Neovide, &ed.CodeBlocks, &ed.DevCpp, &ed.NotepadPlusPlus,
 		&ed.VisualStudio, &ed.Eclipse, &ed.NetBeans, &ed.Neovim, &ed.Vim,
+		&ed.Nano, &ed.Helix,
 	} {
 		if *ptr {
 			count++
@@ -174,9 +190,11 @@ func (a *App) GetAvailableEditors() EditorAvailability {
 func (a *App) GetEditorDetectionStatus() map[string]interface{} {
 	a.editorsMu.RLock()
 	editors := a.availableEditors
+	checkedAt := a.editorsCheckedAt
 	a.editorsMu.RUnlock()
 	return map[string]interface{}{
 		"availableEditors":  editors,
+		"checkedAt":         checkedAt, // When the editors were last probed, in Unix milliseconds
 		"totalAvailable":    countEditorsFromSnapshot(editors),
 		"detectionComplete": true, // By the time this is called, detection is complete at startup
 	}
@@ -217,7 +235,7 @@ func (a *App) ValidateDirectory(path string) (bool, error) {
 			a.logWarn("Directory does not exist", logrus.Fields{
 				"directory": path,
 			})
-			return false, fmt.Errorf("directory does not exist: %s", path)
+			return false, newAppError(ErrCodeDirectoryNotFound, path)
 		}
 		a.logError("Error accessing directory", err, logrus.Fields{
 			"directory": path,
@@ -230,7 +248,7 @@ func (a *App) ValidateDirectory(path string) (bool, error) {
 			"directory": path,
 			"fileInfo":  info.IsDir(),
 		})
-		return false, fmt.Errorf("path is not a directory: %s", path)
+		return false, newAppError(ErrCodeNotADirectory, path)
 	}
 
 	// Try to read the directory to ensure it's accessible
@@ -239,7 +257,7 @@ func (a *App) ValidateDirectory(path string) (bool, error) {
 		a.logError("Directory is not accessible", err, logrus.Fields{
 			"directory": path,
 		})
-		return false, fmt.Errorf("directory is not accessible: %s", path)
+		return false, newAppError(ErrCodeDirectoryNotAccessible, path)
 	}
 
 	a.logDebug("Directory validation successful", logrus.Fields{
@@ -263,17 +281,65 @@ func containsDotDotComponent(path string) bool {
 	return false
 }
 
-// ReadFile reads the content of a file and returns it as a string.
+// ReadFile reads the content of a file and returns it with its encoding.
 // This function is used by the frontend to read file contents for display in the modal.
-func (a *App) ReadFile(filePath string) (string, error) {
+// A byte order mark is stripped and UTF-16 text transcoded to UTF-8, the
+// same way searches read the file, so the preview shows no stray U+FEFF.
+func (a *App) ReadFile(filePath string) (FileContent, error) {
 	a.logDebug("Reading file", logrus.Fields{
 		"filePath": filePath,
 	})
 
+	cleanPath, err := a.validateReadPath(filePath)
+	if err != nil {
+		return FileContent{}, err
+	}
+
+	// Read file content with size limit to prevent memory issues
+	fileInfo, err := os.Stat(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to get file info", err, logrus.Fields{
+			"filePath": cleanPath,
+		})
+		return FileContent{}, newAppError(ErrCodeFileStatFailed, err)
+	}
+
+	// Limit file size to prevent memory issues
+	if fileInfo.Size() > maxReadFileSize {
+		a.logWarn("File too large to read", logrus.Fields{
+			"filePath": cleanPath,
+			"fileSize": fileInfo.Size(),
+			"maxSize":  maxReadFileSize,
+		})
+		return FileContent{}, newAppError(ErrCodeFileTooLarge, cleanPath, fileInfo.Size(), maxReadFileSize)
+	}
+
+	// Read file content
+	content, err := os.ReadFile(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to read file", err, logrus.Fields{
+			"filePath": cleanPath,
+		})
+		return FileContent{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+
+	text, encoding := decodeText(content)
+	a.logDebug("Successfully read file", logrus.Fields{
+		"filePath": cleanPath,
+		"fileSize": len(content),
+		"encoding": encoding,
+	})
+	return FileContent{Content: string(text), Encoding: encoding, LineEnding: detectLineEnding(text)}, nil
+}
+
+// validateReadPath runs the checks shared by the file-reading bindings
+// (ReadFile, GetFileSlice): non-empty path, no ".." traversal, no null bytes,
+// and the file exists. It returns the cleaned path.
+func (a *App) validateReadPath(filePath string) (string, error) {
 	// Validate input
 	if filePath == "" {
 		a.logWarn("Empty file path provided", logrus.Fields{})
-		return "", fmt.Errorf("file path is required")
+		return "", newAppError(ErrCodePathRequired)
 	}
 
 	// Check for directory traversal by inspecting the path components of the
@@ -286,7 +352,7 @@ func (a *App) ReadFile(filePath string) (string, error) {
 		a.logError("Invalid file path contains directory traversal", nil, logrus.Fields{
 			"filePath": filePath,
 		})
-		return "", fmt.Errorf("invalid file path: contains directory traversal")
+		return "", newAppError(ErrCodePathTraversal)
 	}
 
 	// Sanitize the input path to prevent directory traversal attacks
@@ -299,7 +365,7 @@ func (a *App) ReadFile(filePath string) (string, error) {
 			"filePath":  filePath,
 			"cleanPath": cleanPath,
 		})
-		return "", fmt.Errorf("invalid file path: contains directory traversal")
+		return "", newAppError(ErrCodePathTraversal)
 	}
 
 	// Additional security check: prevent null byte injection. The null-byte
@@ -313,56 +379,111 @@ func (a *App) ReadFile(filePath string) (string, error) {
 		a.logError("Invalid file path contains null bytes", nil, logrus.Fields{
 			"filePath": filePath,
 		})
-		return "", fmt.Errorf("invalid file path: contains null bytes")
+		return "", newAppError(ErrCodePathNullByte)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
+	// Check if file exists. toLongPath lets Windows reach files beyond
+	// MAX_PATH; it is a no-op elsewhere.
+	if _, err := os.Stat(toLongPath(cleanPath)); os.IsNotExist(err) {
 		a.logWarn("File does not exist", logrus.Fields{
 			"filePath": cleanPath,
 		})
-		return "", fmt.Errorf("file does not exist: %s", cleanPath)
+		return "", newAppError(ErrCodeFileNotFound, cleanPath)
 	}
 
-	// Read file content with size limit to prevent memory issues
-	fileInfo, err := os.Stat(cleanPath)
+	return cleanPath, nil
+}
+
+// Defaults and bounds for GetFileSlice. The inline preview shows a few dozen
+// lines around a match; the cap keeps a runaway radius from turning the
+// slice into a full ReadFile.
+const (
+	defaultSliceRadius = 20
+	maxSliceRadius     = 500
+)
+
+// GetFileSlice returns the lines around centerLine (1-indexed), radius lines
+// on each side, for the results pane's inline preview. Unlike ReadFile it
+// streams the file and stops after the last requested line, so opening a
+// preview near the top of a large file doesn't read the rest of it. The UI
+// can page through the file for virtual scrolling by requesting further
+// slices until EndOfFile is set.
+func (a *App) GetFileSlice(filePath string, centerLine int, radius int) (FileSlice, error) {
+	a.logDebug("Reading file slice", logrus.Fields{
+		"filePath":   filePath,
+		"centerLine": centerLine,
+		"radius":     radius,
+	})
+
+	cleanPath, err := a.validateReadPath(filePath)
 	if err != nil {
-		a.logError("Failed to get file info", err, logrus.Fields{
-			"filePath": cleanPath,
-		})
-		return "", fmt.Errorf("failed to get file info: %v", err)
+		return FileSlice{}, err
 	}
 
-	// Limit file size to prevent memory issues (e.g., 50MB)
-	maxReadSize := int64(50 * 1024 * 1024) // 50MB
-	if fileInfo.Size() > maxReadSize {
-		a.logWarn("File too large to read", logrus.Fields{
+	if centerLine < 1 {
+		centerLine = 1
+	}
+	if radius <= 0 {
+		radius = defaultSliceRadius
+	}
+	if radius > maxSliceRadius {
+		radius = maxSliceRadius
+	}
+	startLine := centerLine - radius
+	if startLine < 1 {
+		startLine = 1
+	}
+	endLine := centerLine + radius
+
+	file, err := os.Open(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to open file for slice", err, logrus.Fields{
 			"filePath": cleanPath,
-			"fileSize": fileInfo.Size(),
-			"maxSize":  maxReadSize,
 		})
-		return "", fmt.Errorf("file too large to read: %s (size: %d, max: %d)", cleanPath, fileInfo.Size(), maxReadSize)
+		return FileSlice{}, newAppError(ErrCodeFileReadFailed, err)
 	}
+	defer file.Close()
 
-	// Read file content
-	content, err := os.ReadFile(cleanPath)
-	if err != nil {
-		a.logError("Failed to read file", err, logrus.Fields{
+	text, _ := newTextReader(file)
+	// Same line splitting and long-line allowance as the streaming search path.
+	scanner := newLineScanner(text, defaultScannerBufferSize)
+
+	slice := FileSlice{
+		FilePath:  cleanPath,
+		StartLine: startLine,
+		Lines:     make([]string, 0, endLine-startLine+1),
+	}
+	lineNum := 0
+	for lineNum < endLine && scanner.Scan() {
+		lineNum++
+		if lineNum >= startLine {
+			slice.Lines = append(slice.Lines, scanner.Text())
+		}
+	}
+	// One more Scan tells the UI whether there is anything below the slice.
+	slice.EndOfFile = lineNum < endLine || !scanner.Scan()
+	if err := scanner.Err(); err != nil {
+		a.logError("Failed to read file slice", err, logrus.Fields{
 			"filePath": cleanPath,
 		})
-		return "", fmt.Errorf("failed to read file: %v", err)
+		return FileSlice{}, newAppError(ErrCodeFileReadFailed, err)
 	}
 
-	a.logDebug("Successfully read file", logrus.Fields{
-		"filePath": cleanPath,
-		"fileSize": len(content),
-	})
-	return string(content), nil
+	if lineNum < centerLine {
+		// The file shrank since the search ran (or the caller asked past the
+		// end); there is no line to center on.
+		return FileSlice{}, newAppError(ErrCodeLineOutOfRange, centerLine, cleanPath, lineNum)
+	}
+	slice.MatchIndex = centerLine - startLine
+
+	return slice, nil
 }
 
 // SelectDirectory opens a native directory selection dialog and returns the selected path.
 // This function uses the Wails runtime dialog to provide a native directory selection
-// experience across all platforms (Windows, Linux, macOS).
+// experience across all platforms (Windows, Linux, macOS). On Windows that is the shell's
+// IFileOpenDialog, called in-process: no PowerShell or other helper is started, and the
+// title is passed as data, never through a command line.
 func (a *App) SelectDirectory(title string) (string, error) {
 	// Validate input parameters
 	if title == "" {
@@ -372,7 +493,7 @@ func (a *App) SelectDirectory(title string) (string, error) {
 	// Check if we have a valid context
 	if a.ctx == nil {
 		a.logError("No valid context available for directory selection dialog", nil, logrus.Fields{})
-		return "", fmt.Errorf("no valid context available for dialog - application may not be fully initialized")
+		return "", newAppError(ErrCodeDialogUnavailable)
 	}
 
 	a.logDebug("Opening directory selection dialog", logrus.Fields{
@@ -392,7 +513,7 @@ func (a *App) SelectDirectory(title string) (string, error) {
 		})
 		// Return any error that occurred during the dialog operation
 		// This includes system-level errors but excludes user cancellation
-		return "", fmt.Errorf("failed to open directory dialog: %w", err)
+		return "", newAppError(ErrCodeDialogFailed, err)
 	}
 
 	// If selectedPath is empty, the user cancelled the dialog
@@ -440,6 +561,25 @@ var editorBindings = map[string]struct {
 	"NetBeans":        {"netbeans", nil},
 	"Neovim":          {"nvim", nil},
 	"Vim":             {"vim", nil},
+	"Nano":            {"nano", nil},
+	"Helix":           {"hx", nil},
+}
+
+// terminalEditorCommands are the editors that run inside a terminal and
+// need one opened for them (see startEditor).
+var terminalEditorCommands = map[string]bool{
+	"vim":   true,
+	"nvim":  true,
+	"nano":  true,
+	"hx":    true,
+	"helix": true,
+}
+
+// isTerminalEditor reports whether an editor command, or a path to its
+// binary, is a terminal editor.
+func isTerminalEditor(command string) bool {
+	name := strings.ToLower(filepath.Base(command))
+	return terminalEditorCommands[strings.TrimSuffix(name, ".exe")]
 }
 
 // OpenInEditorByName opens a file in the editor identified by the given
@@ -450,7 +590,7 @@ var editorBindings = map[string]struct {
 func (a *App) OpenInEditorByName(name string, filePath string) error {
 	binding, ok := editorBindings[name]
 	if !ok {
-		return fmt.Errorf("unknown editor binding: %q", name)
+		return newAppError(ErrCodeUnknownEditor, name)
 	}
 	return a.openInEditor(filePath, binding.command, binding.args)
 }
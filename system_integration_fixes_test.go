@@ -40,7 +40,8 @@ func TestReadFileAcceptsShellMetacharacters(t *testing.T) {
 			}
 			t.Fatalf("creating %q: %v", name, err)
 		}
-		content, err := app.ReadFile(full)
+		contentFile, err := app.ReadFile(full)
+		content := contentFile.Content
 		if err != nil {
 			t.Errorf("ReadFile(%q) failed: %v — the command-injection char filter should have been removed (#14)", name, err)
 			continue
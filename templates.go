@@ -0,0 +1,259 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// templatesFileName is the data-directory file holding the query templates.
+const templatesFileName = "templates.json"
+
+// templatePlaceholder matches {name} placeholders, and the {{name}} escape
+// that stands for a literal "{name}". Names are identifiers, so regex
+// quantifiers such as {2,3} are left alone.
+var templatePlaceholder = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}|\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// templateStore is the on-disk layout of templatesFileName.
+type templateStore struct {
+	Templates []QueryTemplate `json:"templates"`
+}
+
+// newTemplateID returns a random 16-character hex ID.
+func newTemplateID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate template ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// templateVariables returns the distinct placeholder names in s, in order
+// of first use.
+func templateVariables(s string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(s, -1) {
+		if name := m[2]; name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// expandTemplate replaces the placeholders in s with vars, each value
+// passed through quote first. It fails on the first placeholder without a
+// value.
+func expandTemplate(s string, vars map[string]string, quote func(string) string) (string, error) {
+	var missing string
+	out := templatePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+		sub := templatePlaceholder.FindStringSubmatch(m)
+		if sub[1] != "" {
+			return "{" + sub[1] + "}"
+		}
+		value, ok := vars[sub[2]]
+		if !ok && missing == "" {
+			missing = sub[2]
+		}
+		return quote(value)
+	})
+	if missing != "" {
+		return "", newAppError(ErrCodeTemplateVariableMissing, missing)
+	}
+	return out, nil
+}
+
+// resolveTemplate returns the template's request with its placeholders
+// filled in. Values inserted into a regex query are quoted, so a value is
+// always matched literally; the directory takes values as they are.
+func resolveTemplate(tmpl QueryTemplate, vars map[string]string) (SearchRequest, error) {
+	req := tmpl.Request
+	quote := func(s string) string { return s }
+	if usesRegex(req) {
+		quote = regexp.QuoteMeta
+	}
+
+	query, err := expandTemplate(req.Query, vars, quote)
+	if err != nil {
+		return SearchRequest{}, err
+	}
+	dir, err := expandTemplate(req.Directory, vars, func(s string) string { return s })
+	if err != nil {
+		return SearchRequest{}, err
+	}
+	req.Query, req.Directory = query, dir
+	return req, nil
+}
+
+// normalizeTemplate validates a template from the frontend and fills in
+// its Variables. A regex query is compiled with every placeholder set to a
+// sample value, so a broken pattern is reported on save rather than on
+// every run.
+func normalizeTemplate(tmpl *QueryTemplate) error {
+	tmpl.Name = strings.TrimSpace(tmpl.Name)
+	if tmpl.Name == "" {
+		return newAppError(ErrCodeTemplateNameRequired)
+	}
+	if strings.TrimSpace(tmpl.Request.Query) == "" {
+		return newAppError(ErrCodeTemplateQueryRequired)
+	}
+
+	tmpl.Variables = append(templateVariables(tmpl.Request.Query), templateVariables(tmpl.Request.Directory)...)
+	seen := make(map[string]bool)
+	vars := make(map[string]string)
+	names := tmpl.Variables[:0]
+	for _, name := range tmpl.Variables {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+			vars[name] = "x"
+		}
+	}
+	tmpl.Variables = names
+
+	if usesRegex(tmpl.Request) {
+		sample, err := resolveTemplate(*tmpl, vars)
+		if err != nil {
+			return err
+		}
+		if _, err := regexp.Compile(sample.Query); err != nil {
+			return newAppError(ErrCodeInvalidPattern, err)
+		}
+	}
+	return nil
+}
+
+// loadTemplates reads the template store. Callers must hold templatesMu.
+func (a *App) loadTemplates() (templateStore, error) {
+	var store templateStore
+	if _, err := a.loadJSON(templatesFileName, &store); err != nil {
+		return templateStore{}, err
+	}
+	return store, nil
+}
+
+// ListTemplates returns the query templates in creation order.
+func (a *App) ListTemplates() ([]QueryTemplate, error) {
+	a.templatesMu.Lock()
+	defer a.templatesMu.Unlock()
+
+	store, err := a.loadTemplates()
+	if err != nil {
+		a.logError("Failed to load templates", err, nil)
+		return nil, err
+	}
+	if store.Templates == nil {
+		return []QueryTemplate{}, nil
+	}
+	return store.Templates, nil
+}
+
+// SaveTemplate creates a template (empty ID) or replaces the one with the
+// same ID, and returns it as stored, with its ID and Variables set.
+func (a *App) SaveTemplate(tmpl QueryTemplate) (QueryTemplate, error) {
+	if err := normalizeTemplate(&tmpl); err != nil {
+		return QueryTemplate{}, err
+	}
+
+	a.templatesMu.Lock()
+	defer a.templatesMu.Unlock()
+
+	store, err := a.loadTemplates()
+	if err != nil {
+		return QueryTemplate{}, err
+	}
+
+	now := time.Now().UnixMilli()
+	tmpl.UpdatedAt = now
+	if tmpl.ID == "" {
+		id, err := newTemplateID()
+		if err != nil {
+			return QueryTemplate{}, err
+		}
+		tmpl.ID = id
+		tmpl.CreatedAt = now
+		store.Templates = append(store.Templates, tmpl)
+	} else {
+		idx := -1
+		for i := range store.Templates {
+			if store.Templates[i].ID == tmpl.ID {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return QueryTemplate{}, newAppError(ErrCodeTemplateNotFound, tmpl.ID)
+		}
+		tmpl.CreatedAt = store.Templates[idx].CreatedAt
+		store.Templates[idx] = tmpl
+	}
+
+	if err := a.saveJSON(templatesFileName, store); err != nil {
+		a.logError("Failed to save templates", err, nil)
+		return QueryTemplate{}, err
+	}
+	a.logInfo("Template saved", logrus.Fields{"id": tmpl.ID, "name": tmpl.Name, "variables": tmpl.Variables})
+	return tmpl, nil
+}
+
+// DeleteTemplate removes a template.
+func (a *App) DeleteTemplate(id string) error {
+	a.templatesMu.Lock()
+	defer a.templatesMu.Unlock()
+
+	store, err := a.loadTemplates()
+	if err != nil {
+		return err
+	}
+	for i := range store.Templates {
+		if store.Templates[i].ID == id {
+			store.Templates = append(store.Templates[:i], store.Templates[i+1:]...)
+			if err := a.saveJSON(templatesFileName, store); err != nil {
+				a.logError("Failed to save templates", err, nil)
+				return err
+			}
+			return nil
+		}
+	}
+	return newAppError(ErrCodeTemplateNotFound, id)
+}
+
+// RunTemplate fills in a template's placeholders from vars and runs the
+// resulting search like SearchWithProgress, progress events included.
+// Every placeholder needs a value; extra values are ignored. Built-in
+// presets (ListBuiltinPresets) run the same way.
+func (a *App) RunTemplate(templateID string, vars map[string]string) ([]SearchResult, error) {
+	if preset, ok := builtinPreset(templateID); ok {
+		return a.runTemplate(preset, vars)
+	}
+
+	a.templatesMu.Lock()
+	store, err := a.loadTemplates()
+	a.templatesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tmpl := range store.Templates {
+		if tmpl.ID == templateID {
+			return a.runTemplate(tmpl, vars)
+		}
+	}
+	return nil, newAppError(ErrCodeTemplateNotFound, templateID)
+}
+
+// runTemplate resolves a template with vars and runs the search.
+func (a *App) runTemplate(tmpl QueryTemplate, vars map[string]string) ([]SearchResult, error) {
+	req, err := resolveTemplate(tmpl, vars)
+	if err != nil {
+		return nil, err
+	}
+	a.logInfo("Running template", logrus.Fields{"id": tmpl.ID, "name": tmpl.Name, "query": req.Query})
+	return a.SearchWithProgress(req)
+}
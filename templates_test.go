@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestResolveTemplate verifies placeholder filling: values are quoted in
+// regex queries and used as-is in literal ones, {{name}} stays literal,
+// and a missing value is reported.
+func TestResolveTemplate(t *testing.T) {
+	literal := false
+	tests := []struct {
+		query   string
+		regex   bool
+		vars    map[string]string
+		want    string
+		wantErr ErrorCode
+	}{
+		{`func {name}\(`, true, map[string]string{"name": "Open.File"}, `func Open\.File\(`, ""},
+		{`os.Getenv("{var}")`, false, map[string]string{"var": "HOME"}, `os.Getenv("HOME")`, ""},
+		{`{{name}} and {name}`, false, map[string]string{"name": "x"}, `{name} and x`, ""},
+		{`a{2,3}{flag}`, true, map[string]string{"flag": "on"}, `a{2,3}on`, ""},
+		{`{flag} == {other}`, false, map[string]string{"flag": "on"}, "", ErrCodeTemplateVariableMissing},
+	}
+	for _, tt := range tests {
+		tmpl := QueryTemplate{Request: SearchRequest{Query: tt.query}}
+		if !tt.regex {
+			tmpl.Request.UseRegex = &literal
+		}
+		req, err := resolveTemplate(tmpl, tt.vars)
+		if tt.wantErr != "" {
+			if appErr, ok := err.(*AppError); !ok || appErr.Code != tt.wantErr {
+				t.Errorf("resolveTemplate(%q): expected %s, got %v", tt.query, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || req.Query != tt.want {
+			t.Errorf("resolveTemplate(%q) = %q (err %v), want %q", tt.query, req.Query, err, tt.want)
+		}
+	}
+}
+
+// TestTemplateLifecycle verifies saving (with Variables filled in and a
+// broken regex rejected), running, updating, and deleting a template.
+func TestTemplateLifecycle(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("func Open() {}\nfunc Close() {}\n"), 0o644); err != nil {
+		t.Fatalf("creating file: %v", err)
+	}
+
+	if _, err := app.SaveTemplate(QueryTemplate{Name: "broken", Request: SearchRequest{Query: `func {name}(`}}); err == nil {
+		t.Error("expected an invalid regex template to be rejected")
+	}
+
+	saved, err := app.SaveTemplate(QueryTemplate{
+		Name:    " Function definition ",
+		Request: SearchRequest{Directory: "{root}", Query: `func {name}\(`},
+	})
+	if err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	if saved.ID == "" || saved.Name != "Function definition" || !reflect.DeepEqual(saved.Variables, []string{"name", "root"}) {
+		t.Errorf("unexpected saved template %+v", saved)
+	}
+
+	results, err := app.RunTemplate(saved.ID, map[string]string{"name": "Close", "root": root})
+	if err != nil {
+		t.Fatalf("RunTemplate failed: %v", err)
+	}
+	if len(results) != 1 || results[0].LineNum != 2 {
+		t.Errorf("expected Close on line 2, got %+v", results)
+	}
+	if _, err := app.RunTemplate(saved.ID, map[string]string{"root": root}); err == nil {
+		t.Error("expected a missing variable to fail")
+	}
+
+	saved.Description = "Where a function is defined"
+	if _, err := app.SaveTemplate(saved); err != nil {
+		t.Fatalf("updating template failed: %v", err)
+	}
+	list, err := app.ListTemplates()
+	if err != nil || len(list) != 1 || list[0].Description != saved.Description || list[0].CreatedAt != saved.CreatedAt {
+		t.Errorf("expected the updated template, got %+v (err %v)", list, err)
+	}
+
+	if err := app.DeleteTemplate(saved.ID); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	if _, err := app.RunTemplate(saved.ID, nil); err == nil {
+		t.Error("expected a deleted template to be gone")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// terminalEmulator is a terminal that can run a command, and the flag that
+// precedes the command on its command line.
+type terminalEmulator struct {
+	command string
+	exec    string
+}
+
+// terminalEmulators are tried in order to host terminal editors. The
+// Debian alternatives entry comes first, as it points at the terminal the
+// user chose.
+var terminalEmulators = []terminalEmulator{
+	{"x-terminal-emulator", "-e"},
+	{"gnome-terminal", "--"},
+	{"konsole", "-e"},
+	{"xfce4-terminal", "-x"},
+	{"alacritty", "-e"},
+	{"kitty", ""},
+	{"xterm", "-e"},
+}
+
+// terminalCommand returns the command that runs editor with args in a new
+// terminal window: $TERMINAL when set, otherwise the first installed
+// terminalEmulators entry.
+func terminalCommand(editor string, args []string) (string, []string, error) {
+	candidates := terminalEmulators
+	if term := os.Getenv("TERMINAL"); term != "" {
+		candidates = append([]terminalEmulator{{term, "-e"}}, candidates...)
+	}
+	for _, t := range candidates {
+		if _, err := exec.LookPath(t.command); err != nil {
+			continue
+		}
+		var termArgs []string
+		if t.exec != "" {
+			termArgs = append(termArgs, t.exec)
+		}
+		return t.command, append(append(termArgs, editor), args...), nil
+	}
+	return "", nil, newAppError(ErrCodeNoTerminal, editor)
+}
+
+// startTerminalEditor runs a terminal editor in a new terminal window; started
+// directly from the GUI it would have no terminal to draw in and exit.
+func startTerminalEditor(editor string, args []string) error {
+	terminal, termArgs, err := terminalCommand(editor, args)
+	if err != nil {
+		return err
+	}
+	return runCommand(terminal, termArgs)
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNewConsole gives a process its own console window
+// (CREATE_NEW_CONSOLE).
+const createNewConsole = 0x00000010
+
+// startTerminalEditor runs a terminal editor in a new console window. The
+// hidden console startEditor uses for GUI editors would leave it invisible.
+func startTerminalEditor(editor string, args []string) error {
+	cmd := exec.Command(editor, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewConsole}
+	return cmd.Start()
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestIsTerminalEditor verifies that terminal editors are recognized by
+// command name or binary path, and GUI editors are not.
+func TestIsTerminalEditor(t *testing.T) {
+	for command, want := range map[string]bool{
+		"vim":              true,
+		"nvim":             true,
+		"/usr/bin/nano":    true,
+		"/opt/helix/hx":    true,
+		"C:/Tools/hx.exe":  true,
+		"gvim":             false,
+		"code":             false,
+		"/usr/bin/neovide": false,
+	} {
+		if got := isTerminalEditor(command); got != want {
+			t.Errorf("isTerminalEditor(%q) = %v, want %v", command, got, want)
+		}
+	}
+}
+
+// TestTerminalCommand verifies the terminal chosen for a terminal editor:
+// the first installed emulator, $TERMINAL ahead of it, and NO_TERMINAL when
+// none is installed.
+func TestTerminalCommand(t *testing.T) {
+	binDir := t.TempDir()
+	for _, name := range []string{"xterm", "foot"} {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+	t.Setenv("TERMINAL", "")
+
+	terminal, args, err := terminalCommand("nvim", []string{"+12", "/src/a.go"})
+	if err != nil {
+		t.Fatalf("terminalCommand failed: %v", err)
+	}
+	if terminal != "xterm" || !reflect.DeepEqual(args, []string{"-e", "nvim", "+12", "/src/a.go"}) {
+		t.Errorf("got %s %q, want xterm -e nvim +12 /src/a.go", terminal, args)
+	}
+
+	t.Setenv("TERMINAL", "foot")
+	if terminal, _, _ := terminalCommand("nvim", nil); terminal != "foot" {
+		t.Errorf("expected $TERMINAL to be preferred, got %s", terminal)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, _, err := terminalCommand("nvim", nil); err == nil || err.(*AppError).Code != ErrCodeNoTerminal {
+		t.Errorf("expected %s without a terminal, got %v", ErrCodeNoTerminal, err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	_ "image/gif"  // Registers GIF for image.Decode
+	_ "image/jpeg" // Registers JPEG for image.Decode
+	"image/png"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Bounds of ReadFileThumbnail's maxDim, in pixels.
+const (
+	defaultThumbnailDim = 256
+	maxThumbnailDim     = 1024
+)
+
+// Limits on the images ReadFileThumbnail decodes. The pixel limit is
+// checked against the image header before decoding, so a small file that
+// claims huge dimensions is rejected without allocating them.
+const (
+	maxThumbnailFileSize = 20 * 1024 * 1024
+	maxThumbnailPixels   = 50 * 1000 * 1000
+)
+
+// scaleImage shrinks img to fit within maxDim pixels on both sides, keeping
+// its aspect ratio. Each thumbnail pixel is the average of the source
+// pixels it covers; an image that already fits is copied as it is.
+func scaleImage(img image.Image, maxDim int) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > maxDim || h > maxDim {
+		if w >= h {
+			tw, th = maxDim, max(1, h*maxDim/w)
+		} else {
+			tw, th = max(1, w*maxDim/h), maxDim
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// RGBA() is alpha-premultiplied, so the averages are too;
+			// Set converts them to the non-premultiplied NRGBA.
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// ReadFileThumbnail returns a PNG, JPEG, or GIF image downscaled to fit
+// within maxDim pixels (default 256, at most 1024) as a base64 PNG, for
+// previewing image assets in the results. The path goes through the same
+// checks as ReadFile.
+func (a *App) ReadFileThumbnail(filePath string, maxDim int) (Thumbnail, error) {
+	cleanPath, err := a.validateReadPath(filePath)
+	if err != nil {
+		return Thumbnail{}, err
+	}
+	if maxDim <= 0 {
+		maxDim = defaultThumbnailDim
+	}
+	maxDim = min(maxDim, maxThumbnailDim)
+
+	stat, err := os.Stat(toLongPath(cleanPath))
+	if err != nil {
+		return Thumbnail{}, newAppError(ErrCodeFileStatFailed, err)
+	}
+	if stat.Size() > maxThumbnailFileSize {
+		return Thumbnail{}, newAppError(ErrCodeFileTooLarge, cleanPath, stat.Size(), int64(maxThumbnailFileSize))
+	}
+	data, err := os.ReadFile(toLongPath(cleanPath))
+	if err != nil {
+		a.logError("Failed to read image", err, logrus.Fields{"filePath": cleanPath})
+		return Thumbnail{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Thumbnail{}, newAppError(ErrCodeImageUnsupported, cleanPath)
+	}
+	if config.Width*config.Height > maxThumbnailPixels {
+		return Thumbnail{}, newAppError(ErrCodeImageTooLarge, cleanPath, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Thumbnail{}, newAppError(ErrCodeImageUnsupported, cleanPath)
+	}
+
+	thumb := scaleImage(img, maxDim)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, thumb); err != nil {
+		return Thumbnail{}, newAppError(ErrCodeFileReadFailed, err)
+	}
+	a.logDebug("Thumbnail created", logrus.Fields{
+		"filePath": cleanPath,
+		"format":   format,
+		"width":    thumb.Rect.Dx(),
+		"height":   thumb.Rect.Dy(),
+	})
+	return Thumbnail{
+		Data:           base64.StdEncoding.EncodeToString(buf.Bytes()),
+		Width:          thumb.Rect.Dx(),
+		Height:         thumb.Rect.Dy(),
+		OriginalWidth:  config.Width,
+		OriginalHeight: config.Height,
+		Format:         format,
+	}, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestScaleImage verifies the thumbnail size for wide, tall, and small
+// images, and that each pixel averages the source pixels it covers.
+func TestScaleImage(t *testing.T) {
+	cases := []struct{ w, h, wantW, wantH int }{
+		{800, 400, 100, 50},
+		{300, 900, 33, 100},
+		{40, 20, 40, 20},
+		{1000, 2, 100, 1},
+	}
+	for _, c := range cases {
+		got := scaleImage(image.NewNRGBA(image.Rect(0, 0, c.w, c.h)), 100)
+		if got.Rect.Dx() != c.wantW || got.Rect.Dy() != c.wantH {
+			t.Errorf("%dx%d scaled to %v, want %dx%d", c.w, c.h, got.Rect, c.wantW, c.wantH)
+		}
+	}
+
+	// Black and white columns average to grey.
+	stripes := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if x%2 == 0 {
+				stripes.Set(x, y, color.White)
+			} else {
+				stripes.Set(x, y, color.Black)
+			}
+		}
+	}
+	if got := scaleImage(stripes, 2).NRGBAAt(0, 0); got.R < 126 || got.R > 129 || got.A != 255 {
+		t.Errorf("expected an opaque mid grey, got %v", got)
+	}
+}
+
+// TestReadFileThumbnail verifies thumbnails of PNG and JPEG files, and the
+// rejection of files that are not images and of oversized dimensions.
+func TestReadFileThumbnail(t *testing.T) {
+	dir := t.TempDir()
+	app := NewApp()
+
+	src := image.NewNRGBA(image.Rect(0, 0, 600, 300))
+	var pngData, jpegData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{"logo.png": pngData.Bytes(), "photo.jpg": jpegData.Bytes(), "main.go": []byte("package main\n")}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, format := range map[string]string{"logo.png": "png", "photo.jpg": "jpeg"} {
+		thumb, err := app.ReadFileThumbnail(filepath.Join(dir, name), 0)
+		if err != nil {
+			t.Fatalf("%s: ReadFileThumbnail failed: %v", name, err)
+		}
+		if thumb.Width != 256 || thumb.Height != 128 || thumb.OriginalWidth != 600 || thumb.Format != format {
+			t.Errorf("%s: unexpected thumbnail %dx%d of %dx%d %s", name, thumb.Width, thumb.Height, thumb.OriginalWidth, thumb.OriginalHeight, thumb.Format)
+		}
+		data, err := base64.StdEncoding.DecodeString(thumb.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img, err := png.Decode(bytes.NewReader(data)); err != nil || img.Bounds().Dx() != 256 {
+			t.Errorf("%s: expected a 256 pixel wide PNG, got %v", name, err)
+		}
+	}
+
+	if _, err := app.ReadFileThumbnail(filepath.Join(dir, "main.go"), 64); err == nil || err.(*AppError).Code != ErrCodeImageUnsupported {
+		t.Errorf("expected %s for a source file, got %v", ErrCodeImageUnsupported, err)
+	}
+
+	// A valid header claiming 10000x10000 pixels is rejected before the
+	// pixel data is ever read.
+	var bomb bytes.Buffer
+	if err := png.Encode(&bomb, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	header := bomb.Bytes()[:33]
+	copy(header[16:], []byte{0, 0, 0x27, 0x10, 0, 0, 0x27, 0x10})
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+	if err := os.WriteFile(filepath.Join(dir, "bomb.png"), header, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ReadFileThumbnail(filepath.Join(dir, "bomb.png"), 64); err == nil || err.(*AppError).Code != ErrCodeImageTooLarge {
+		t.Errorf("expected %s, got %v", ErrCodeImageTooLarge, err)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats ExportTree writes.
+const (
+	treeFormatJSON = "json"
+	treeFormatText = "text"
+)
+
+// treeNode is a file or directory in an exported tree.
+type treeNode struct {
+	Name     string      `json:"name"`
+	Dir      bool        `json:"dir,omitempty"`
+	Size     int64       `json:"size"`            // File size, or the total size of the files below a directory
+	Files    int         `json:"files,omitempty"` // Files below a directory
+	Children []*treeNode `json:"children,omitempty"`
+}
+
+// treeDocument is the layout of a JSON tree export.
+type treeDocument struct {
+	Root            string    `json:"root"`
+	GeneratedAt     string    `json:"generatedAt"` // RFC 3339
+	ExcludePatterns []string  `json:"excludePatterns,omitempty"`
+	Files           int       `json:"files"`
+	Directories     int       `json:"directories"`
+	TotalSize       int64     `json:"totalSize"`
+	Tree            *treeNode `json:"tree"`
+}
+
+// buildTree arranges files, all below root, into a tree and returns it
+// with the number of directories under the root. Only directories that
+// hold files appear. Directories come before files, each sorted by name.
+func buildTree(root string, files []fileMeta) (*treeNode, int) {
+	top := &treeNode{Name: filepath.Base(root), Dir: true}
+	dirs := make(map[string]*treeNode)
+	for _, f := range files {
+		rel, err := filepath.Rel(root, f.absPath)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		top.Size += f.size
+		top.Files++
+		parent, key := top, ""
+		for _, name := range parts[:len(parts)-1] {
+			key = path.Join(key, name)
+			dir := dirs[key]
+			if dir == nil {
+				dir = &treeNode{Name: name, Dir: true}
+				dirs[key] = dir
+				parent.Children = append(parent.Children, dir)
+			}
+			dir.Size += f.size
+			dir.Files++
+			parent = dir
+		}
+		parent.Children = append(parent.Children, &treeNode{Name: parts[len(parts)-1], Size: f.size})
+	}
+	sortTree(top)
+	return top, len(dirs)
+}
+
+// sortTree orders the children of n and of every directory below it.
+func sortTree(n *treeNode) {
+	sort.Slice(n.Children, func(i, j int) bool {
+		a, b := n.Children[i], n.Children[j]
+		if a.Dir != b.Dir {
+			return a.Dir
+		}
+		return a.Name < b.Name
+	})
+	for _, child := range n.Children {
+		if child.Dir {
+			sortTree(child)
+		}
+	}
+}
+
+// writeTreeText writes doc in the style of the tree command: the root, one
+// entry per line under box-drawing guides with directories marked by a
+// trailing slash, and a closing count.
+func writeTreeText(w io.Writer, doc treeDocument) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, doc.Root)
+	var walk func(n *treeNode, prefix string)
+	walk = func(n *treeNode, prefix string) {
+		for i, child := range n.Children {
+			branch, indent := "├── ", "│   "
+			if i == len(n.Children)-1 {
+				branch, indent = "└── ", "    "
+			}
+			name := child.Name
+			if child.Dir {
+				name += "/"
+			}
+			fmt.Fprintf(bw, "%s%s%s\n", prefix, branch, name)
+			if child.Dir {
+				walk(child, prefix+indent)
+			}
+		}
+	}
+	walk(doc.Tree, "")
+	fmt.Fprintf(bw, "\n%d directories, %d files\n", doc.Directories, doc.Files)
+	// bufio.Writer keeps the first write error for Flush to return.
+	return bw.Flush()
+}
+
+// ExportTree writes the tree of files under root to outputPath, for
+// attaching the scope of a search to a bug report or documentation. The
+// walk is the one a search does, so hidden directories, .codesearchignore
+// rules, and excludePatterns apply. format is "text", a tree-command style
+// listing, or "json", nested nodes with sizes and file counts. The file is
+// created or truncated; outputPath must be absolute.
+func (a *App) ExportTree(root string, outputPath string, format string, excludePatterns []string) (TreeExport, error) {
+	absRoot, err := resolveDirectory(root)
+	if err != nil {
+		return TreeExport{}, err
+	}
+	format = strings.ToLower(format)
+	if format != treeFormatJSON && format != treeFormatText {
+		return TreeExport{}, newAppError(ErrCodeExportFormatUnsupported, format, "json, text")
+	}
+	if outputPath == "" {
+		return TreeExport{}, newAppError(ErrCodePathRequired)
+	}
+	if !filepath.IsAbs(outputPath) {
+		return TreeExport{}, newAppError(ErrCodeResultsExportFailed, outputPath, errors.New("path must be absolute"))
+	}
+
+	req := SearchRequest{
+		Directory:       absRoot,
+		SearchSubdirs:   true,
+		IncludeBinary:   true,
+		MaxFileSize:     math.MaxInt64,
+		MaxFilesPerDir:  defaultMaxFilesPerDir,
+		ExcludePatterns: excludePatterns,
+	}
+	files, _, stats, err := a.walkDirectoryTree(req, false)
+	if err != nil {
+		a.logError("Error during file walk", err, logrus.Fields{"directory": absRoot})
+		return TreeExport{}, newAppError(ErrCodeDirectoryInvalid, err)
+	}
+	tree, dirs := buildTree(absRoot, files)
+	doc := treeDocument{
+		Root:            absRoot,
+		GeneratedAt:     time.Now().Format(time.RFC3339),
+		ExcludePatterns: excludePatterns,
+		Files:           tree.Files,
+		Directories:     dirs,
+		TotalSize:       tree.Size,
+		Tree:            tree,
+	}
+
+	snap := snapshotForUndo(outputPath)
+	file, err := os.Create(toLongPath(outputPath))
+	if err != nil {
+		return TreeExport{}, newAppError(ErrCodeResultsExportFailed, outputPath, err)
+	}
+	var writeErr error
+	if format == treeFormatJSON {
+		enc := json.NewEncoder(file)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		writeErr = enc.Encode(doc)
+	} else {
+		writeErr = writeTreeText(file, doc)
+	}
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		return TreeExport{}, newAppError(ErrCodeResultsExportFailed, outputPath, errors.Join(writeErr, closeErr))
+	}
+
+	a.recordUndo(snap, undoKindExport, "Export directory tree")
+	a.logInfo("Directory tree exported", logrus.Fields{
+		"directory":  absRoot,
+		"outputPath": outputPath,
+		"format":     format,
+		"files":      doc.Files,
+	})
+	return TreeExport{
+		Path:        outputPath,
+		Files:       doc.Files,
+		Directories: dirs,
+		TotalSize:   doc.TotalSize,
+		Truncated:   stats.dirsTruncated,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExportTree verifies the text and JSON trees of a small project, with
+// exclude patterns and hidden directories left out, and the rejection of
+// unknown formats and relative output paths.
+func TestExportTree(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+	files := map[string]string{
+		"README.md":             "readme",
+		"src/main.go":           "package main",
+		"src/util/strings.go":   "package util",
+		"docs/guide.md":         "guide",
+		"node_modules/x/x.js":   "ignored",
+		".git/HEAD":             "ref: refs/heads/main",
+		"src/util/strings_x.go": "package util",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	out := t.TempDir()
+	textPath := filepath.Join(out, "tree.txt")
+	summary, err := app.ExportTree(root, textPath, "text", []string{"node_modules"})
+	if err != nil {
+		t.Fatalf("ExportTree failed: %v", err)
+	}
+	if summary.Files != 5 || summary.Directories != 3 || summary.TotalSize != 47 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	text, err := os.ReadFile(textPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := root + `
+├── docs/
+│   └── guide.md
+├── src/
+│   ├── util/
+│   │   ├── strings.go
+│   │   └── strings_x.go
+│   └── main.go
+└── README.md
+
+3 directories, 5 files
+`
+	if string(text) != want {
+		t.Errorf("text tree:\n%s\nwant:\n%s", text, want)
+	}
+
+	jsonPath := filepath.Join(out, "tree.json")
+	if _, err := app.ExportTree(root, jsonPath, "JSON", []string{"node_modules"}); err != nil {
+		t.Fatalf("ExportTree failed: %v", err)
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc treeDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	src := doc.Tree.Children[1]
+	if doc.Root != root || doc.Files != 5 || src.Name != "src" || src.Files != 3 || src.Size != 36 || len(src.Children) != 2 {
+		t.Errorf("unexpected JSON tree %s", data)
+	}
+	for _, child := range doc.Tree.Children {
+		if child.Name == "node_modules" || strings.HasPrefix(child.Name, ".") {
+			t.Errorf("expected %s to be left out of the tree", child.Name)
+		}
+	}
+
+	if _, err := app.ExportTree(root, filepath.Join(out, "tree.xml"), "xml", nil); err == nil || err.(*AppError).Code != ErrCodeExportFormatUnsupported {
+		t.Errorf("expected %s, got %v", ErrCodeExportFormatUnsupported, err)
+	}
+	if _, err := app.ExportTree(root, "tree.txt", "text", nil); err == nil || err.(*AppError).Code != ErrCodeResultsExportFailed {
+		t.Errorf("expected %s for a relative path, got %v", ErrCodeResultsExportFailed, err)
+	}
+}
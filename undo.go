@@ -0,0 +1,268 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// undoFileName is the data-directory file listing the undoable
+	// actions, oldest first.
+	undoFileName = "undo.json"
+	// undoDirName is the data-directory folder holding the previous
+	// content of each journaled file, as <action ID>.bak.
+	undoDirName = "undo"
+	// maxUndoActions is how many write operations stay undoable.
+	maxUndoActions = 20
+	// maxUndoBackupBytes caps the previous content journaled per action.
+	// Overwriting a larger file is not undoable.
+	maxUndoBackupBytes = 10 * 1024 * 1024
+)
+
+// Kinds of undoable write operations, reported as UndoAction.Kind.
+const (
+	undoKindIgnoreRule = "ignore-rule" // AddIgnoreRule edited an ignore file
+	undoKindExport     = "export"      // An export wrote a file the user chose
+)
+
+// undoEntry is a journaled write operation as stored in undo.json.
+type undoEntry struct {
+	Action    UndoAction  `json:"action"`
+	Mode      fs.FileMode `json:"mode"`      // Permissions of the previous file
+	AfterSize int64       `json:"afterSize"` // Size of the file the operation wrote
+	AfterHash string      `json:"afterHash"` // SHA-256 of that file, to detect later edits
+}
+
+// undoSnapshot is the state of a file taken just before a write, passed
+// to recordUndo once the write succeeded.
+type undoSnapshot struct {
+	path     string
+	existed  bool
+	mode     fs.FileMode
+	content  []byte
+	tooLarge bool // The previous content exceeded maxUndoBackupBytes and wasn't kept
+}
+
+// hashFile returns the hex SHA-256 of the file at path and its size.
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(toLongPath(path))
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// newUndoID returns a random 16-character hex ID.
+func newUndoID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate undo ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// snapshotForUndo reads the file at path before a write operation changes
+// it. A file that doesn't exist yet is recorded as such, so undoing its
+// creation deletes it. Errors reading it leave the write undoable only if
+// the file turns out to be new.
+func snapshotForUndo(path string) undoSnapshot {
+	snap := undoSnapshot{path: path}
+	info, err := os.Stat(toLongPath(path))
+	if err != nil {
+		return snap
+	}
+	snap.existed, snap.mode = true, info.Mode().Perm()
+	if info.Size() > maxUndoBackupBytes {
+		snap.tooLarge = true
+		return snap
+	}
+	if snap.content, err = os.ReadFile(toLongPath(path)); err != nil {
+		snap.tooLarge = true
+	}
+	return snap
+}
+
+// recordUndo journals a write operation that succeeded, keeping the
+// previous content from snap so Undo can restore it. Journaling is a
+// safety net: failures are logged and never fail the operation itself.
+func (a *App) recordUndo(snap undoSnapshot, kind, description string) {
+	if a.dataDir == "" {
+		return
+	}
+	if snap.tooLarge {
+		a.logInfo("Write is not undoable: previous content too large or unreadable", logrus.Fields{"path": snap.path})
+		return
+	}
+	if err := a.journalUndo(snap, kind, description); err != nil {
+		a.logError("Failed to journal write for undo", err, logrus.Fields{"path": snap.path})
+	}
+}
+
+// journalUndo stores the backup and the entry of a write operation and
+// drops the oldest actions beyond maxUndoActions.
+func (a *App) journalUndo(snap undoSnapshot, kind, description string) error {
+	hash, size, err := hashFile(snap.path)
+	if err != nil {
+		return err
+	}
+	id, err := newUndoID()
+	if err != nil {
+		return err
+	}
+	entry := undoEntry{
+		Action: UndoAction{
+			ID:          id,
+			Kind:        kind,
+			Description: description,
+			Path:        snap.path,
+			Created:     !snap.existed,
+			CreatedAt:   time.Now().UnixMilli(),
+		},
+		Mode:      snap.mode,
+		AfterSize: size,
+		AfterHash: hash,
+	}
+
+	a.undoMu.Lock()
+	defer a.undoMu.Unlock()
+
+	if snap.existed {
+		dir := filepath.Join(a.dataDir, undoDirName)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, id+".bak"), snap.content, 0o600); err != nil {
+			return err
+		}
+	}
+	entries, err := a.loadUndoEntries()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if excess := len(entries) - maxUndoActions; excess > 0 {
+		for _, old := range entries[:excess] {
+			a.removeUndoBackup(old.Action.ID)
+		}
+		entries = append([]undoEntry(nil), entries[excess:]...)
+	}
+	return a.saveJSON(undoFileName, entries)
+}
+
+// loadUndoEntries reads the undo journal. Callers must hold undoMu.
+func (a *App) loadUndoEntries() ([]undoEntry, error) {
+	var entries []undoEntry
+	if _, err := a.loadJSON(undoFileName, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// removeUndoBackup deletes the previous content kept for an action, if any.
+func (a *App) removeUndoBackup(id string) {
+	err := os.Remove(filepath.Join(a.dataDir, undoDirName, id+".bak"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		a.logError("Failed to remove undo backup", err, logrus.Fields{"actionId": id})
+	}
+}
+
+// ListUndoableActions returns the write operations that can still be
+// undone, most recent first: the last maxUndoActions files changed by
+// AddIgnoreRule or written by an export.
+func (a *App) ListUndoableActions() ([]UndoAction, error) {
+	a.undoMu.Lock()
+	entries, err := a.loadUndoEntries()
+	a.undoMu.Unlock()
+	if err != nil {
+		a.logError("Failed to load undo journal", err, nil)
+		return nil, err
+	}
+	actions := make([]UndoAction, len(entries))
+	for i, entry := range entries {
+		actions[len(entries)-1-i] = entry.Action
+	}
+	return actions, nil
+}
+
+// Undo restores the file changed by an action to its previous content and
+// permissions, or deletes it if the action created it, and drops the
+// action from the journal. A file changed again since the action fails
+// with UNDO_CONFLICT rather than losing those changes; an unknown ID fails
+// with UNDO_NOT_FOUND. That includes a later action on the same file, so
+// only the newest write to a file can be undone; undoing that one makes
+// the one before it undoable.
+func (a *App) Undo(actionID string) error {
+	a.undoMu.Lock()
+	defer a.undoMu.Unlock()
+
+	entries, err := a.loadUndoEntries()
+	if err != nil {
+		return err
+	}
+	i := -1
+	for j := range entries {
+		if entries[j].Action.ID == actionID {
+			i = j
+		}
+	}
+	if i < 0 {
+		return newAppError(ErrCodeUndoNotFound, actionID)
+	}
+	entry := entries[i]
+	path := entry.Action.Path
+
+	// Compare by content rather than modification time, so once the later
+	// actions on the same file are undone, this one matches again.
+	hash, size, err := hashFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && entry.Action.Created:
+		// Already gone; nothing to restore.
+	case err != nil:
+		return newAppError(ErrCodeUndoConflict, path)
+	case size != entry.AfterSize || hash != entry.AfterHash:
+		return newAppError(ErrCodeUndoConflict, path)
+	case entry.Action.Created:
+		if err := os.Remove(toLongPath(path)); err != nil {
+			return newAppError(ErrCodeUndoFailed, path, err)
+		}
+	default:
+		backup, err := os.ReadFile(filepath.Join(a.dataDir, undoDirName, actionID+".bak"))
+		if err != nil {
+			return newAppError(ErrCodeUndoFailed, path, err)
+		}
+		if err := os.WriteFile(toLongPath(path), backup, entry.Mode); err != nil {
+			return newAppError(ErrCodeUndoFailed, path, err)
+		}
+		if err := os.Chmod(toLongPath(path), entry.Mode); err != nil {
+			return newAppError(ErrCodeUndoFailed, path, err)
+		}
+	}
+
+	a.removeUndoBackup(actionID)
+	entries = append(entries[:i], entries[i+1:]...)
+	if err := a.saveJSON(undoFileName, entries); err != nil {
+		return err
+	}
+	a.logInfo("Write operation undone", logrus.Fields{
+		"actionId": actionID,
+		"kind":     entry.Action.Kind,
+		"path":     path,
+	})
+	return nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestUndoIgnoreRules verifies that ignore-file edits are listed most
+// recent first and undone back to the previous content, and that undoing
+// the edit that created the file deletes it.
+func TestUndoIgnoreRules(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	ignorePath := filepath.Join(root, ignoreFileName)
+
+	if _, err := app.AddIgnoreRule(root, "vendor"); err != nil {
+		t.Fatalf("AddIgnoreRule failed: %v", err)
+	}
+	afterFirst, err := os.ReadFile(ignorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AddIgnoreRule(root, "*.min.js"); err != nil {
+		t.Fatalf("AddIgnoreRule failed: %v", err)
+	}
+
+	actions, err := app.ListUndoableActions()
+	if err != nil {
+		t.Fatalf("ListUndoableActions failed: %v", err)
+	}
+	if len(actions) != 2 || actions[0].Description != "Add ignore rule *.min.js" || actions[0].Created ||
+		!actions[1].Created || actions[1].Kind != undoKindIgnoreRule || actions[1].Path != ignorePath {
+		t.Fatalf("unexpected actions %+v", actions)
+	}
+
+	if err := app.Undo(actions[0].ID); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got, _ := os.ReadFile(ignorePath); string(got) != string(afterFirst) {
+		t.Errorf("after undo the ignore file is %q, want %q", got, afterFirst)
+	}
+	if err := app.Undo(actions[1].ID); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if _, err := os.Stat(ignorePath); !os.IsNotExist(err) {
+		t.Errorf("expected the created ignore file to be deleted, got %v", err)
+	}
+
+	if actions, _ := app.ListUndoableActions(); len(actions) != 0 {
+		t.Errorf("expected an empty journal, got %+v", actions)
+	}
+	if err := app.Undo(actions[0].ID); err == nil || err.(*AppError).Code != ErrCodeUndoNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeUndoNotFound, err)
+	}
+}
+
+// TestUndoOlderWriteToSameFile verifies that with two writes to one file
+// the older one can't be undone while the newer one stands, and can be
+// once the newer one is undone.
+func TestUndoOlderWriteToSameFile(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	root := t.TempDir()
+	ignorePath := filepath.Join(root, ignoreFileName)
+	if err := os.WriteFile(ignorePath, []byte("node_modules\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range []string{"vendor", "dist"} {
+		if _, err := app.AddIgnoreRule(root, rule); err != nil {
+			t.Fatalf("AddIgnoreRule(%q) failed: %v", rule, err)
+		}
+	}
+	afterBoth, err := os.ReadFile(ignorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions, err := app.ListUndoableActions()
+	if err != nil || len(actions) != 2 {
+		t.Fatalf("expected two actions, got %+v, %v", actions, err)
+	}
+	newer, older := actions[0], actions[1]
+
+	if err := app.Undo(older.ID); err == nil || err.(*AppError).Code != ErrCodeUndoConflict {
+		t.Fatalf("expected %s undoing the older write first, got %v", ErrCodeUndoConflict, err)
+	}
+	if got, _ := os.ReadFile(ignorePath); string(got) != string(afterBoth) {
+		t.Errorf("a refused undo changed the file to %q", got)
+	}
+
+	if err := app.Undo(newer.ID); err != nil {
+		t.Fatalf("Undo of the newer write failed: %v", err)
+	}
+	if err := app.Undo(older.ID); err != nil {
+		t.Fatalf("Undo of the older write failed: %v", err)
+	}
+	if got, _ := os.ReadFile(ignorePath); string(got) != "node_modules\n" {
+		t.Errorf("after both undos the ignore file is %q, want the original", got)
+	}
+}
+
+// TestUndoExport verifies that an export over an existing file restores
+// it with its permissions, that a file changed after the export is not
+// overwritten, and that only the last maxUndoActions actions are kept.
+func TestUndoExport(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, results: []SearchResult{{FilePath: "/a.go", LineNum: 1, Content: "x"}}})
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("my notes\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.ExportResultsAsQuickfix(id, path); err != nil {
+		t.Fatalf("ExportResultsAsQuickfix failed: %v", err)
+	}
+	actions, _ := app.ListUndoableActions()
+	if len(actions) != 1 || actions[0].Kind != undoKindExport {
+		t.Fatalf("unexpected actions %+v", actions)
+	}
+	if err := app.Undo(actions[0].ID); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if got, _ := os.ReadFile(path); err != nil || string(got) != "my notes\n" || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the notes restored with mode 0600, got %q", got)
+	}
+
+	if _, err := app.ExportResultsAsQuickfix(id, path); err != nil {
+		t.Fatalf("ExportResultsAsQuickfix failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("edited after the export\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	actions, _ = app.ListUndoableActions()
+	if err := app.Undo(actions[0].ID); err == nil || err.(*AppError).Code != ErrCodeUndoConflict {
+		t.Errorf("expected %s, got %v", ErrCodeUndoConflict, err)
+	}
+
+	for i := 0; i < maxUndoActions; i++ {
+		if _, err := app.ExportResultsAsQuickfix(id, path); err != nil {
+			t.Fatalf("ExportResultsAsQuickfix failed: %v", err)
+		}
+	}
+	actions, _ = app.ListUndoableActions()
+	backups, _ := os.ReadDir(filepath.Join(app.dataDir, undoDirName))
+	if len(actions) != maxUndoActions || len(backups) != maxUndoActions {
+		t.Errorf("expected %d actions and backups, got %d and %d", maxUndoActions, len(actions), len(backups))
+	}
+}
+
+// listFiles returns the paths of the files under root.
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// TestUndoJournalStaysInTestDirs verifies that the tests can't journal to
+// the developer's data directory: a default App resolves it inside the
+// profile TestMain sets up, and every writer that journals writes only to
+// its output and to the dataDir the test chose.
+func TestUndoJournalStaysInTestDirs(t *testing.T) {
+	profile := os.Getenv("HOME")
+	app := NewApp()
+	for _, dir := range []string{app.dataDir, app.logDir} {
+		if rel, err := filepath.Rel(profile, dir); err != nil || !filepath.IsLocal(rel) {
+			t.Fatalf("expected %s inside the test profile %s", dir, profile)
+		}
+	}
+
+	app.dataDir = t.TempDir()
+	root, out := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("needle\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	id := app.newSearchID()
+	app.storeSearch(searchRecord{id: id, results: []SearchResult{{FilePath: filepath.Join(root, "a.txt"), LineNum: 1, Content: "needle"}}})
+	before := listFiles(t, profile)
+
+	if _, err := app.AddIgnoreRule(root, "vendor"); err != nil {
+		t.Fatalf("AddIgnoreRule failed: %v", err)
+	}
+	if _, err := app.ExportTree(root, filepath.Join(out, "tree.txt"), "text", nil); err != nil {
+		t.Fatalf("ExportTree failed: %v", err)
+	}
+	if _, err := app.ExportResultsAsQuickfix(id, filepath.Join(out, "results.qf")); err != nil {
+		t.Fatalf("ExportResultsAsQuickfix failed: %v", err)
+	}
+	if _, err := app.SearchToFile(SearchRequest{Directory: root, Query: "needle"}, filepath.Join(out, "results.ndjson")); err != nil {
+		t.Fatalf("SearchToFile failed: %v", err)
+	}
+	if _, err := app.GenerateReport(id, "md", filepath.Join(out, "report.md")); err != nil {
+		t.Fatalf("GenerateReport failed: %v", err)
+	}
+
+	if after := listFiles(t, profile); len(after) != len(before) {
+		t.Errorf("expected nothing written to the profile, files went from %v to %v", before, after)
+	}
+	if actions, _ := app.ListUndoableActions(); len(actions) != 5 {
+		t.Errorf("expected 5 journaled actions, got %+v", actions)
+	}
+	if _, err := os.Stat(filepath.Join(app.dataDir, undoFileName)); err != nil {
+		t.Errorf("expected the journal in the test's data directory: %v", err)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// appVersion is the version of this build. Release builds set it with
+// -ldflags "-X main.appVersion=v1.4.0"; local builds keep "dev" and never
+// report an update as available.
+var appVersion = "dev"
+
+// releasesFeedURL is the GitHub releases API of the project, newest first.
+var releasesFeedURL = "https://api.github.com/repos/afman42/code-search-golang-wails-vue/releases"
+
+// Release channels: stable only offers full releases, beta also offers
+// pre-releases.
+const (
+	updateChannelStable = "stable"
+	updateChannelBeta   = "beta"
+)
+
+// Limits of the update requests. The feed lists a page of releases with
+// their notes; downloads are installers of a few tens of MB.
+const (
+	updateCheckTimeout    = 15 * time.Second
+	updateDownloadTimeout = 10 * time.Minute
+	maxReleasesFeedBytes  = 4 << 20
+)
+
+// updateAssetExts are the download formats of each platform, preferred
+// first. "" is a bare executable, as the CI builds publish for Linux.
+var updateAssetExts = map[string][]string{
+	"linux":   {".appimage", ".deb", ".tar.gz", ""},
+	"windows": {".msi", ".exe", ".zip"},
+	"darwin":  {".dmg", ".zip"},
+}
+
+// updateAssetOSNames are the names release assets use for each platform.
+var updateAssetOSNames = map[string][]string{
+	"linux":   {"linux"},
+	"windows": {"windows", "win64"},
+	"darwin":  {"darwin", "macos", "mac"},
+}
+
+// githubRelease is a release in the GitHub releases feed.
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	HTMLURL     string `json:"html_url"`
+	Body        string `json:"body"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	PublishedAt string `json:"published_at"`
+	Assets      []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+		Size int64  `json:"size"`
+	} `json:"assets"`
+}
+
+// updateState keeps the result of the last update check, which
+// DownloadUpdate picks its asset from.
+type updateState struct {
+	mu   sync.Mutex
+	last *UpdateInfo // Result of the last successful check, nil before it
+}
+
+// normalizeUpdateChannel lowercases channel and defaults it to stable.
+func normalizeUpdateChannel(channel string) string {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel == "" {
+		return updateChannelStable
+	}
+	return channel
+}
+
+// validateUpdateChannel checks that channel, once normalized, is a release
+// channel.
+func validateUpdateChannel(channel string) error {
+	if channel != updateChannelStable && channel != updateChannelBeta {
+		return newAppError(ErrCodeUpdateChannelInvalid, channel)
+	}
+	return nil
+}
+
+// parseVersion splits a "v1.2.3" or "1.2.3-beta.1" version into its
+// numbers and pre-release suffix. Missing minor and patch numbers are 0.
+func parseVersion(v string) (nums [3]int, pre string, ok bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, _ = strings.Cut(v, "-")
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return nums, "", false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nums, "", false
+		}
+		nums[i] = n
+	}
+	return nums, pre, true
+}
+
+// compareVersions returns -1, 0, or 1 as version a is older than, the same
+// as, or newer than b. A pre-release is older than its release, and
+// pre-releases of the same version compare by suffix ("beta.2" > "beta.1").
+func compareVersions(a, b string) int {
+	an, apre, _ := parseVersion(a)
+	bn, bpre, _ := parseVersion(b)
+	for i := range an {
+		if an[i] != bn[i] {
+			if an[i] < bn[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0
+	case apre == "":
+		return 1
+	case bpre == "":
+		return -1
+	case apre < bpre:
+		return -1
+	}
+	return 1
+}
+
+// pickUpdateAsset returns the name of the asset to install on goos/goarch,
+// or "" if none is built for it.
+func pickUpdateAsset(assets []UpdateAsset, goos, goarch string) string {
+	best, bestRank := "", -1
+	exts := updateAssetExts[goos]
+	for _, asset := range assets {
+		name := strings.ToLower(asset.Name)
+		if !containsAny(name, updateAssetOSNames[goos]) {
+			continue
+		}
+		if isARM := containsAny(name, []string{"arm64", "aarch64"}); isARM != (goarch == "arm64") {
+			continue
+		}
+		for rank, ext := range exts {
+			matches := strings.HasSuffix(name, ext)
+			if ext == "" {
+				matches = !strings.Contains(filepath.Base(name), ".")
+			}
+			if matches {
+				if best == "" || rank < bestRank {
+					best, bestRank = asset.Name, rank
+				}
+				break
+			}
+		}
+	}
+	return best
+}
+
+// containsAny reports whether s contains one of subs.
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchReleases reads the releases feed.
+func fetchReleases(ctx context.Context) ([]githubRelease, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesFeedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "code-search/"+appVersion)
+	resp, err := (&http.Client{Timeout: updateCheckTimeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("releases feed returned %s", resp.Status)
+	}
+	var releases []githubRelease
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxReleasesFeedBytes)).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("reading releases feed: %w", err)
+	}
+	return releases, nil
+}
+
+// latestRelease returns the newest release on channel, or nil if it has
+// none. Drafts and releases without a version tag are ignored.
+func latestRelease(releases []githubRelease, channel string) *githubRelease {
+	var latest *githubRelease
+	for i, release := range releases {
+		if release.Draft || (release.Prerelease && channel != updateChannelBeta) {
+			continue
+		}
+		if _, _, ok := parseVersion(release.TagName); !ok {
+			continue
+		}
+		if latest == nil || compareVersions(release.TagName, latest.TagName) > 0 {
+			latest = &releases[i]
+		}
+	}
+	return latest
+}
+
+// CheckForUpdates looks up the newest release on the channel from the
+// settings and reports whether it is newer than this build, with the
+// downloads of the release and the one that fits this platform.
+func (a *App) CheckForUpdates() (UpdateInfo, error) {
+	channel := a.currentSettings().UpdateChannel
+	if err := validateUpdateChannel(channel); err != nil {
+		return UpdateInfo{}, err
+	}
+	releases, err := fetchReleases(context.Background())
+	if err != nil {
+		a.logWarn("Update check failed", logrus.Fields{"error": err.Error()})
+		return UpdateInfo{}, newAppError(ErrCodeUpdateCheckFailed, err)
+	}
+
+	info := UpdateInfo{
+		CurrentVersion: appVersion,
+		Channel:        channel,
+		CheckedAt:      time.Now().UnixMilli(),
+	}
+	if latest := latestRelease(releases, channel); latest != nil {
+		info.LatestVersion = latest.TagName
+		info.ReleaseName = latest.Name
+		info.ReleaseURL = latest.HTMLURL
+		info.Notes = latest.Body
+		info.PublishedAt = latest.PublishedAt
+		info.Assets = make([]UpdateAsset, 0, len(latest.Assets))
+		for _, asset := range latest.Assets {
+			info.Assets = append(info.Assets, UpdateAsset{Name: asset.Name, URL: asset.URL, Size: asset.Size})
+		}
+		info.Asset = pickUpdateAsset(info.Assets, runtime.GOOS, runtime.GOARCH)
+		if _, _, ok := parseVersion(appVersion); ok {
+			info.UpdateAvailable = compareVersions(latest.TagName, appVersion) > 0
+		}
+	}
+
+	a.updates.mu.Lock()
+	a.updates.last = &info
+	a.updates.mu.Unlock()
+	a.logInfo("Checked for updates", logrus.Fields{
+		"channel":         channel,
+		"currentVersion":  info.CurrentVersion,
+		"latestVersion":   info.LatestVersion,
+		"updateAvailable": info.UpdateAvailable,
+	})
+	return info, nil
+}
+
+// checkForUpdatesOnStartup runs the startup update check and tells the
+// frontend with an update-available event when there is one. Failures are
+// only logged: being offline must not get in the way of searching.
+func (a *App) checkForUpdatesOnStartup() {
+	info, err := a.CheckForUpdates()
+	if err == nil && info.UpdateAvailable {
+		a.safeEmitEvent("update-available", info)
+	}
+}
+
+// DownloadUpdate downloads an asset of the release the last
+// CheckForUpdates found, checking first if none ran yet, and returns where
+// it was saved. An empty asset picks the download for this platform (the
+// AppImage on Linux, the installer on Windows); an empty path saves to the
+// temp directory under the asset's name, and any other path must be
+// absolute. The file is written next to path and renamed into place once
+// complete, so a failed download never leaves a truncated installer.
+func (a *App) DownloadUpdate(asset, path string) (string, error) {
+	a.updates.mu.Lock()
+	last := a.updates.last
+	a.updates.mu.Unlock()
+	if last == nil {
+		info, err := a.CheckForUpdates()
+		if err != nil {
+			return "", err
+		}
+		last = &info
+	}
+
+	if asset == "" {
+		asset = last.Asset
+	}
+	var download *UpdateAsset
+	for i := range last.Assets {
+		if asset != "" && last.Assets[i].Name == asset {
+			download = &last.Assets[i]
+		}
+	}
+	if download == nil {
+		if asset == "" {
+			asset = runtime.GOOS + "/" + runtime.GOARCH
+		}
+		return "", newAppError(ErrCodeUpdateAssetNotFound, last.LatestVersion, asset)
+	}
+
+	if path == "" {
+		path = filepath.Join(os.TempDir(), filepath.Base(download.Name))
+	}
+	if !filepath.IsAbs(path) {
+		return "", newAppError(ErrCodeUpdateDownloadFailed, path, errors.New("path must be absolute"))
+	}
+
+	snap := snapshotForUndo(path)
+	size, err := downloadFile(download.URL, path, download.Size)
+	if err != nil {
+		a.logWarn("Update download failed", logrus.Fields{"url": download.URL, "error": err.Error()})
+		return "", newAppError(ErrCodeUpdateDownloadFailed, path, err)
+	}
+	a.recordUndo(snap, undoKindExport, "Download update "+download.Name)
+	a.logInfo("Update downloaded", logrus.Fields{
+		"version":    last.LatestVersion,
+		"asset":      download.Name,
+		"outputPath": path,
+		"bytes":      size,
+	})
+	return path, nil
+}
+
+// downloadFile saves url to path through a temporary file in the same
+// directory, failing if the size differs from want (when known). The file
+// is executable so an AppImage or bare binary can be run straight away.
+func downloadFile(url, path string, want int64) (int64, error) {
+	resp, err := (&http.Client{Timeout: updateDownloadTimeout}).Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download returned %s", resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(toLongPath(path)), ".update-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	size, err := io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && want > 0 && size != want {
+		err = fmt.Errorf("downloaded %d bytes, expected %d", size, want)
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o755)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), toLongPath(path))
+	}
+	return size, err
+}
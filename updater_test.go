@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// serveReleases points releasesFeedURL at a test server publishing
+// releases, with their assets served under /download/, until the test ends.
+func serveReleases(t *testing.T, releases []map[string]interface{}, assets map[string]string) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	for _, release := range releases {
+		var list []map[string]interface{}
+		for name, content := range assets {
+			list = append(list, map[string]interface{}{
+				"name":                 name,
+				"browser_download_url": server.URL + "/download/" + name,
+				"size":                 len(content),
+			})
+		}
+		release["assets"] = list
+	}
+	mux.HandleFunc("/releases", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(releases)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(assets[filepath.Base(r.URL.Path)]))
+	})
+
+	feed, version := releasesFeedURL, appVersion
+	releasesFeedURL = server.URL + "/releases"
+	t.Cleanup(func() { releasesFeedURL, appVersion = feed, version })
+}
+
+// platformAssetName is the name of the test release's download for this
+// platform.
+func platformAssetName() string {
+	name := "code-search-" + runtime.GOOS
+	if runtime.GOARCH == "arm64" {
+		name += "-arm64"
+	}
+	return name + updateAssetExts[runtime.GOOS][0]
+}
+
+// TestCompareVersions verifies version ordering, pre-releases included.
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v1.2", "v1.2.1", -1},
+		{"v2.0.0-beta.1", "v2.0.0", -1},
+		{"v2.0.0-beta.2", "v2.0.0-beta.1", 1},
+		{"v2.0.0-beta.1", "v1.9.0", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	for _, v := range []string{"dev", "", "v1.x", "1.2.3.4"} {
+		if _, _, ok := parseVersion(v); ok {
+			t.Errorf("parseVersion(%q) accepted an invalid version", v)
+		}
+	}
+}
+
+// TestPickUpdateAsset verifies that the preferred format for the platform
+// and architecture is chosen.
+func TestPickUpdateAsset(t *testing.T) {
+	assets := []UpdateAsset{
+		{Name: "code-search-golang-linux"},
+		{Name: "code-search-linux-x86_64.AppImage"},
+		{Name: "code-search-linux-arm64.AppImage"},
+		{Name: "code-search-golang-windows.exe"},
+		{Name: "code-search-windows-installer.msi"},
+		{Name: "checksums.txt"},
+	}
+	tests := []struct{ goos, goarch, want string }{
+		{"linux", "amd64", "code-search-linux-x86_64.AppImage"},
+		{"linux", "arm64", "code-search-linux-arm64.AppImage"},
+		{"windows", "amd64", "code-search-windows-installer.msi"},
+		{"darwin", "arm64", ""},
+	}
+	for _, tt := range tests {
+		if got := pickUpdateAsset(assets, tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("pickUpdateAsset(%s/%s) = %q, want %q", tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+	if got := pickUpdateAsset(assets[:1], "linux", "amd64"); got != "code-search-golang-linux" {
+		t.Errorf("expected the bare binary when there is no AppImage, got %q", got)
+	}
+}
+
+// TestCheckForUpdatesChannels verifies that the stable channel skips
+// pre-releases and drafts, and the beta channel offers pre-releases.
+func TestCheckForUpdatesChannels(t *testing.T) {
+	serveReleases(t, []map[string]interface{}{
+		{"tag_name": "v1.3.0", "draft": true},
+		{"tag_name": "v1.3.0-beta.1", "prerelease": true},
+		{"tag_name": "v1.2.0", "name": "Release 1.2", "html_url": "https://example.com/v1.2.0"},
+		{"tag_name": "v1.1.0"},
+	}, map[string]string{platformAssetName(): "installer"})
+	appVersion = "v1.1.0"
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	info, err := app.CheckForUpdates()
+	if err != nil {
+		t.Fatalf("CheckForUpdates failed: %v", err)
+	}
+	if info.Channel != "stable" || info.LatestVersion != "v1.2.0" || !info.UpdateAvailable {
+		t.Errorf("expected v1.2.0 available on stable, got %+v", info)
+	}
+	if info.ReleaseName != "Release 1.2" || info.Asset != platformAssetName() {
+		t.Errorf("unexpected release details %+v", info)
+	}
+
+	if _, err := app.UpdateSettings(Settings{UpdateChannel: "Beta"}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if info, err = app.CheckForUpdates(); err != nil || info.LatestVersion != "v1.3.0-beta.1" {
+		t.Errorf("expected v1.3.0-beta.1 on beta, got %+v, %v", info, err)
+	}
+
+	appVersion = "dev"
+	if info, err = app.CheckForUpdates(); err != nil || info.UpdateAvailable {
+		t.Errorf("expected no update offered to a dev build, got %+v, %v", info, err)
+	}
+}
+
+// TestUpdateSettingsRejectsUnknownChannel verifies channel validation.
+func TestUpdateSettingsRejectsUnknownChannel(t *testing.T) {
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.UpdateSettings(Settings{UpdateChannel: "nightly"}); err == nil || err.(*AppError).Code != ErrCodeUpdateChannelInvalid {
+		t.Errorf("expected %s, got %v", ErrCodeUpdateChannelInvalid, err)
+	}
+	if got := app.GetSettings().UpdateChannel; got != "stable" {
+		t.Errorf("expected the default stable channel, got %q", got)
+	}
+}
+
+// TestCheckForUpdatesFeedError verifies that a failing feed is reported.
+func TestCheckForUpdatesFeedError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	feed := releasesFeedURL
+	releasesFeedURL = server.URL
+	defer func() { releasesFeedURL = feed }()
+
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	if _, err := app.CheckForUpdates(); err == nil || err.(*AppError).Code != ErrCodeUpdateCheckFailed {
+		t.Errorf("expected %s, got %v", ErrCodeUpdateCheckFailed, err)
+	}
+}
+
+// TestDownloadUpdate verifies that the platform's download is saved to the
+// chosen path, and that unknown assets and relative paths are rejected.
+func TestDownloadUpdate(t *testing.T) {
+	serveReleases(t, []map[string]interface{}{{"tag_name": "v1.2.0"}}, map[string]string{
+		platformAssetName(): "installer bytes",
+		"checksums.txt":     "sums",
+	})
+	appVersion = "v1.1.0"
+	app := NewApp()
+	app.dataDir = t.TempDir()
+
+	out := filepath.Join(t.TempDir(), "update.bin")
+	got, err := app.DownloadUpdate("", out)
+	if err != nil {
+		t.Fatalf("DownloadUpdate failed: %v", err)
+	}
+	data, err := os.ReadFile(got)
+	if err != nil || string(data) != "installer bytes" || got != out {
+		t.Errorf("expected the installer at %s, got %q at %s (%v)", out, data, got, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(out)); len(entries) != 1 {
+		t.Errorf("expected no temporary files left, got %d entries", len(entries))
+	}
+
+	if _, err := app.DownloadUpdate("missing.exe", out); err == nil || err.(*AppError).Code != ErrCodeUpdateAssetNotFound {
+		t.Errorf("expected %s, got %v", ErrCodeUpdateAssetNotFound, err)
+	}
+	if _, err := app.DownloadUpdate("checksums.txt", "relative.txt"); err == nil || err.(*AppError).Code != ErrCodeUpdateDownloadFailed {
+		t.Errorf("expected %s, got %v", ErrCodeUpdateDownloadFailed, err)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// wellKnownFileNames are build and deployment files without an extension,
+// or with one the allow-list rarely names, matched case-insensitively by
+// base name.
+var wellKnownFileNames = map[string]bool{
+	"dockerfile":              true,
+	"containerfile":           true,
+	"docker-compose.yml":      true,
+	"docker-compose.yaml":     true,
+	"compose.yml":             true,
+	"compose.yaml":            true,
+	"jenkinsfile":             true,
+	"makefile":                true,
+	"gnumakefile":             true,
+	"justfile":                true,
+	"vagrantfile":             true,
+	"procfile":                true,
+	".gitlab-ci.yml":          true,
+	".travis.yml":             true,
+	"azure-pipelines.yml":     true,
+	"bitbucket-pipelines.yml": true,
+}
+
+// wellKnownDirs are the hidden directories IncludeWellKnownFiles walks
+// into. Only the well-known files inside them are searched.
+var wellKnownDirs = map[string]bool{
+	".github":   true,
+	".circleci": true,
+}
+
+// isWellKnownFile reports whether rel, a slash-separated path relative to
+// the search root, is a build or CI file that IncludeWellKnownFiles always
+// searches: a name in wellKnownFileNames, a Dockerfile variant
+// (Dockerfile.dev, api.dockerfile), a GitHub Actions workflow or action,
+// or the CircleCI config. Directories are matched by their last
+// components, so the files of nested projects count too.
+func isWellKnownFile(rel string) bool {
+	dir, name := path.Split(rel)
+	dir = "/" + strings.TrimSuffix(dir, "/")
+	name = strings.ToLower(name)
+	ext := path.Ext(name)
+	yaml := ext == ".yml" || ext == ".yaml"
+	switch {
+	case wellKnownFileNames[name]:
+		return true
+	case strings.HasPrefix(name, "dockerfile.") || ext == ".dockerfile":
+		return true
+	case yaml && strings.HasSuffix(dir, "/.github/workflows"):
+		return true
+	case yaml && strings.TrimSuffix(name, ext) == "action" && strings.HasSuffix(path.Dir(dir), "/.github/actions"):
+		return true
+	case yaml && strings.TrimSuffix(name, ext) == "config" && strings.HasSuffix(dir, "/.circleci"):
+		return true
+	}
+	return false
+}
+
+// inHiddenDir reports whether rel, a slash-separated path relative to the
+// search root, lies under a directory whose name starts with a dot.
+func inHiddenDir(rel string) bool {
+	dir, _ := path.Split(rel)
+	for _, part := range strings.Split(strings.TrimSuffix(dir, "/"), "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestIsWellKnownFile verifies which relative paths count as build and CI
+// files.
+func TestIsWellKnownFile(t *testing.T) {
+	tests := map[string]bool{
+		"Dockerfile":                         true,
+		"services/api/Dockerfile.dev":        true,
+		"deploy/api.dockerfile":              true,
+		"makefile":                           true,
+		"Jenkinsfile":                        true,
+		".gitlab-ci.yml":                     true,
+		".github/workflows/ci.yml":           true,
+		"sub/.github/workflows/release.yaml": true,
+		".github/actions/setup/action.yml":   true,
+		".circleci/config.yml":               true,
+		".github/CODEOWNERS":                 false,
+		".github/workflows/notes.md":         false,
+		"workflows/ci.yml":                   false,
+		"main.go":                            false,
+		"Makefile.am":                        false,
+	}
+	for rel, want := range tests {
+		if got := isWellKnownFile(rel); got != want {
+			t.Errorf("isWellKnownFile(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
+
+// TestCollectWellKnownFiles verifies that IncludeWellKnownFiles lets build
+// and CI files past the extension allow-list and into .github, without
+// collecting the other files there, and that nothing changes without it.
+func TestCollectWellKnownFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{
+		"main.go", "README.md", "Dockerfile", "Makefile",
+		".github/workflows/ci.yml", ".github/CODEOWNERS", ".git/config",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("needle\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	collect := func(wellKnown bool) string {
+		t.Helper()
+		req, err := app.validateAndSetDefaults(SearchRequest{
+			Directory:             dir,
+			Query:                 "needle",
+			SearchSubdirs:         true,
+			AllowedFileTypes:      []string{"go"},
+			IncludeWellKnownFiles: wellKnown,
+		})
+		if err != nil {
+			t.Fatalf("validateAndSetDefaults failed: %v", err)
+		}
+		files, err := app.collectFilesToProcess(req, nil, filepath.Clean(dir)+string(filepath.Separator))
+		if err != nil {
+			t.Fatalf("collectFilesToProcess failed: %v", err)
+		}
+		var rels []string
+		for _, f := range files {
+			rel, _ := filepath.Rel(dir, f.absPath)
+			rels = append(rels, filepath.ToSlash(rel))
+		}
+		sort.Strings(rels)
+		return strings.Join(rels, ",")
+	}
+
+	if got := collect(false); got != "main.go" {
+		t.Errorf("without well-known files: got %s", got)
+	}
+	if got, want := collect(true), ".github/workflows/ci.yml,Dockerfile,Makefile,main.go"; got != want {
+		t.Errorf("with well-known files: got %s, want %s", got, want)
+	}
+
+	req := SearchRequest{AllowedFileTypes: []string{"go"}, SearchSubdirs: true, MaxFileSize: 100, IncludeWellKnownFiles: true}
+	for rel, want := range map[string]bool{".github/workflows/ci.yml": true, ".github/CODEOWNERS": false, "Dockerfile": true, "README.md": false} {
+		if got := bucketKeyWanted(app, req, rel, 10); got != want {
+			t.Errorf("bucketKeyWanted(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
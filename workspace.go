@@ -0,0 +1,283 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// workspacesFileName is the data-directory file holding all workspaces and
+// the ID of the active one.
+const workspacesFileName = "workspaces.json"
+
+// workspaceStore is the on-disk layout of workspacesFileName.
+type workspaceStore struct {
+	ActiveID   string      `json:"activeId"`
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// newWorkspaceID returns a random 16-character hex ID.
+func newWorkspaceID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate workspace ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// loadWorkspaces reads the workspace store. Callers must hold workspacesMu.
+func (a *App) loadWorkspaces() (workspaceStore, error) {
+	var store workspaceStore
+	if _, err := a.loadJSON(workspacesFileName, &store); err != nil {
+		return workspaceStore{}, err
+	}
+	return store, nil
+}
+
+// findWorkspace returns the index of the workspace with the given ID, or -1.
+func (s *workspaceStore) findWorkspace(id string) int {
+	for i := range s.Workspaces {
+		if s.Workspaces[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeWorkspace validates a workspace from the frontend and cleans its
+// fields in place: the name is trimmed and required, roots are cleaned,
+// de-duplicated, and must be absolute. Roots are not required to exist, so
+// a workspace on an unmounted network drive can still be edited.
+func normalizeWorkspace(ws *Workspace) error {
+	ws.Name = strings.TrimSpace(ws.Name)
+	if ws.Name == "" {
+		return newAppError(ErrCodeWorkspaceNameRequired)
+	}
+
+	seen := make(map[string]bool, len(ws.Roots))
+	roots := make([]string, 0, len(ws.Roots))
+	for _, root := range ws.Roots {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+		root = filepath.Clean(root)
+		if !filepath.IsAbs(root) {
+			return newAppError(ErrCodeWorkspaceRootInvalid, root)
+		}
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+	ws.Roots = roots
+
+	for i := range ws.SavedSearches {
+		ws.SavedSearches[i].Name = strings.TrimSpace(ws.SavedSearches[i].Name)
+		if ws.SavedSearches[i].Name == "" {
+			return newAppError(ErrCodeSavedSearchNameRequired)
+		}
+	}
+	return nil
+}
+
+// checkWorkspaceNameFree rejects a name already used by another workspace.
+// Names are compared case-insensitively since they are what the user picks
+// from in the switcher.
+func (s *workspaceStore) checkWorkspaceNameFree(name, exceptID string) error {
+	for _, existing := range s.Workspaces {
+		if existing.ID != exceptID && strings.EqualFold(existing.Name, name) {
+			return newAppError(ErrCodeWorkspaceNameTaken, existing.Name)
+		}
+	}
+	return nil
+}
+
+// ListWorkspaces returns all workspaces in creation order.
+func (a *App) ListWorkspaces() ([]Workspace, error) {
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		a.logError("Failed to load workspaces", err, nil)
+		return nil, err
+	}
+	if store.Workspaces == nil {
+		return []Workspace{}, nil
+	}
+	return store.Workspaces, nil
+}
+
+// CreateWorkspace adds a new workspace and returns it with its generated ID.
+// The new workspace does not become active; call SwitchWorkspace for that.
+func (a *App) CreateWorkspace(ws Workspace) (Workspace, error) {
+	if err := normalizeWorkspace(&ws); err != nil {
+		return Workspace{}, err
+	}
+
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		return Workspace{}, err
+	}
+	if err := store.checkWorkspaceNameFree(ws.Name, ""); err != nil {
+		return Workspace{}, err
+	}
+
+	id, err := newWorkspaceID()
+	if err != nil {
+		return Workspace{}, err
+	}
+	now := time.Now().UnixMilli()
+	ws.ID = id
+	ws.CreatedAt = now
+	ws.UpdatedAt = now
+	store.Workspaces = append(store.Workspaces, ws)
+
+	if err := a.saveJSON(workspacesFileName, store); err != nil {
+		a.logError("Failed to save workspaces", err, nil)
+		return Workspace{}, err
+	}
+	a.logInfo("Workspace created", logrus.Fields{"id": ws.ID, "name": ws.Name, "roots": len(ws.Roots)})
+	return ws, nil
+}
+
+// UpdateWorkspace replaces the workspace with the same ID. CreatedAt is kept
+// from the stored copy; everything else comes from ws.
+func (a *App) UpdateWorkspace(ws Workspace) (Workspace, error) {
+	if err := normalizeWorkspace(&ws); err != nil {
+		return Workspace{}, err
+	}
+
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		return Workspace{}, err
+	}
+	idx := store.findWorkspace(ws.ID)
+	if idx < 0 {
+		return Workspace{}, newAppError(ErrCodeWorkspaceNotFound, ws.ID)
+	}
+	if err := store.checkWorkspaceNameFree(ws.Name, ws.ID); err != nil {
+		return Workspace{}, err
+	}
+
+	ws.CreatedAt = store.Workspaces[idx].CreatedAt
+	ws.UpdatedAt = time.Now().UnixMilli()
+	store.Workspaces[idx] = ws
+
+	if err := a.saveJSON(workspacesFileName, store); err != nil {
+		a.logError("Failed to save workspaces", err, nil)
+		return Workspace{}, err
+	}
+	return ws, nil
+}
+
+// DeleteWorkspace removes a workspace and its saved session. Deleting the
+// active workspace switches back to the global (no-workspace) state.
+func (a *App) DeleteWorkspace(id string) error {
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		return err
+	}
+	idx := store.findWorkspace(id)
+	if idx < 0 {
+		return newAppError(ErrCodeWorkspaceNotFound, id)
+	}
+	store.Workspaces = append(store.Workspaces[:idx], store.Workspaces[idx+1:]...)
+	if store.ActiveID == id {
+		store.ActiveID = ""
+	}
+
+	if err := a.saveJSON(workspacesFileName, store); err != nil {
+		a.logError("Failed to save workspaces", err, nil)
+		return err
+	}
+	if err := a.removeDataFile(workspaceSessionFileName(id)); err != nil {
+		a.logWarn("Failed to remove workspace session", logrus.Fields{"id": id, "error": err.Error()})
+	}
+	a.logInfo("Workspace deleted", logrus.Fields{"id": id})
+	return nil
+}
+
+// SwitchWorkspace makes the workspace with the given ID active and returns
+// it; an empty ID switches back to the global state. Sessions are kept per
+// workspace, so the next GetLastSession restores the state last saved in
+// the workspace being switched to. A "workspace-switched" event carries the
+// new workspace (zero value for global) to the frontend.
+func (a *App) SwitchWorkspace(id string) (Workspace, error) {
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	var active Workspace
+	if id != "" {
+		idx := store.findWorkspace(id)
+		if idx < 0 {
+			return Workspace{}, newAppError(ErrCodeWorkspaceNotFound, id)
+		}
+		active = store.Workspaces[idx]
+	}
+
+	store.ActiveID = id
+	if err := a.saveJSON(workspacesFileName, store); err != nil {
+		a.logError("Failed to save workspaces", err, nil)
+		return Workspace{}, err
+	}
+
+	a.logInfo("Switched workspace", logrus.Fields{"id": id, "name": active.Name})
+	a.safeEmitEvent("workspace-switched", active)
+	return active, nil
+}
+
+// GetActiveWorkspace returns the active workspace, or the zero value (empty
+// ID) when no workspace is active.
+func (a *App) GetActiveWorkspace() (Workspace, error) {
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil {
+		return Workspace{}, err
+	}
+	if idx := store.findWorkspace(store.ActiveID); idx >= 0 {
+		return store.Workspaces[idx], nil
+	}
+	return Workspace{}, nil
+}
+
+// activeWorkspaceID returns the ID of the active workspace, or "" for the
+// global state (also on a read error, so session handling degrades to the
+// global session rather than failing).
+func (a *App) activeWorkspaceID() string {
+	a.workspacesMu.Lock()
+	defer a.workspacesMu.Unlock()
+
+	store, err := a.loadWorkspaces()
+	if err != nil || store.findWorkspace(store.ActiveID) < 0 {
+		return ""
+	}
+	return store.ActiveID
+}
+
+// workspaceSessionFileName returns the session file of a workspace.
+func workspaceSessionFileName(id string) string {
+	return "session-" + id + ".json"
+}
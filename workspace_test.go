@@ -0,0 +1,165 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newWorkspaceTestApp returns an App whose persisted state lives in a temp dir.
+func newWorkspaceTestApp(t *testing.T) *App {
+	t.Helper()
+	app := NewApp()
+	app.dataDir = t.TempDir()
+	return app
+}
+
+// TestWorkspaceCRUD walks a workspace through create, list, update, and
+// delete, checking the normalization applied on the way in.
+func TestWorkspaceCRUD(t *testing.T) {
+	app := newWorkspaceTestApp(t)
+	root := t.TempDir()
+
+	created, err := app.CreateWorkspace(Workspace{
+		Name:  "  Client A  ",
+		Roots: []string{root, root + string(filepath.Separator), " "},
+		SavedSearches: []SavedSearch{
+			{Name: "todos", Request: SearchRequest{Query: "TODO"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkspace failed: %v", err)
+	}
+	if created.ID == "" || created.CreatedAt == 0 {
+		t.Errorf("expected generated ID and timestamps, got %+v", created)
+	}
+	if created.Name != "Client A" {
+		t.Errorf("expected trimmed name, got %q", created.Name)
+	}
+	if len(created.Roots) != 1 || created.Roots[0] != root {
+		t.Errorf("expected roots cleaned and de-duplicated, got %v", created.Roots)
+	}
+
+	list, err := app.ListWorkspaces()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("expected 1 workspace, got %d (err %v)", len(list), err)
+	}
+
+	created.Name = "Client A (prod)"
+	created.DefaultFilters.ExcludePatterns = []string{"vendor"}
+	updated, err := app.UpdateWorkspace(created)
+	if err != nil {
+		t.Fatalf("UpdateWorkspace failed: %v", err)
+	}
+	if updated.CreatedAt != created.CreatedAt {
+		t.Error("expected CreatedAt to be preserved")
+	}
+	list, _ = app.ListWorkspaces()
+	if list[0].Name != "Client A (prod)" || len(list[0].DefaultFilters.ExcludePatterns) != 1 {
+		t.Errorf("update not persisted: %+v", list[0])
+	}
+
+	if err := app.DeleteWorkspace(created.ID); err != nil {
+		t.Fatalf("DeleteWorkspace failed: %v", err)
+	}
+	if list, _ = app.ListWorkspaces(); len(list) != 0 {
+		t.Errorf("expected no workspaces after delete, got %d", len(list))
+	}
+	if err := app.DeleteWorkspace(created.ID); err == nil {
+		t.Error("expected error deleting a missing workspace")
+	}
+}
+
+// TestWorkspaceValidation verifies that invalid workspaces are rejected.
+func TestWorkspaceValidation(t *testing.T) {
+	app := newWorkspaceTestApp(t)
+
+	if _, err := app.CreateWorkspace(Workspace{Name: "  "}); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if _, err := app.CreateWorkspace(Workspace{Name: "rel", Roots: []string{"relative/dir"}}); err == nil {
+		t.Error("expected error for relative root")
+	}
+	if _, err := app.CreateWorkspace(Workspace{Name: "x", SavedSearches: []SavedSearch{{Name: ""}}}); err == nil {
+		t.Error("expected error for unnamed saved search")
+	}
+
+	if _, err := app.CreateWorkspace(Workspace{Name: "Alpha"}); err != nil {
+		t.Fatalf("CreateWorkspace failed: %v", err)
+	}
+	if _, err := app.CreateWorkspace(Workspace{Name: "alpha"}); err == nil {
+		t.Error("expected error for duplicate name")
+	}
+	if _, err := app.UpdateWorkspace(Workspace{ID: "missing", Name: "Beta"}); err == nil {
+		t.Error("expected error updating a missing workspace")
+	}
+}
+
+// TestWorkspaceSessionIsolation verifies that each workspace restores its
+// own session and that the global session is untouched by workspace saves.
+func TestWorkspaceSessionIsolation(t *testing.T) {
+	app := newWorkspaceTestApp(t)
+
+	if err := app.SaveSession(SessionState{Request: SearchRequest{Query: "global"}}); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	wsA, _ := app.CreateWorkspace(Workspace{Name: "A"})
+	wsB, _ := app.CreateWorkspace(Workspace{Name: "B"})
+
+	if _, err := app.SwitchWorkspace(wsA.ID); err != nil {
+		t.Fatalf("SwitchWorkspace failed: %v", err)
+	}
+	if got := app.GetLastSession(); got.Request.Query != "" {
+		t.Errorf("expected empty session in new workspace, got query %q", got.Request.Query)
+	}
+	app.SaveSession(SessionState{Request: SearchRequest{Query: "in A"}})
+
+	app.SwitchWorkspace(wsB.ID)
+	app.SaveSession(SessionState{Request: SearchRequest{Query: "in B"}})
+
+	app.SwitchWorkspace(wsA.ID)
+	if got := app.GetLastSession().Request.Query; got != "in A" {
+		t.Errorf("expected workspace A session, got %q", got)
+	}
+	active, err := app.GetActiveWorkspace()
+	if err != nil || active.ID != wsA.ID {
+		t.Errorf("expected A to be active, got %q (err %v)", active.ID, err)
+	}
+
+	app.SwitchWorkspace("")
+	if got := app.GetLastSession().Request.Query; got != "global" {
+		t.Errorf("expected global session, got %q", got)
+	}
+
+	// Deleting a workspace removes its session file.
+	if err := app.DeleteWorkspace(wsB.ID); err != nil {
+		t.Fatalf("DeleteWorkspace failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(app.dataDir, workspaceSessionFileName(wsB.ID))); !os.IsNotExist(err) {
+		t.Errorf("expected workspace B session file to be removed, stat err: %v", err)
+	}
+}
+
+// TestDeleteActiveWorkspace verifies that deleting the active workspace
+// falls back to the global state.
+func TestDeleteActiveWorkspace(t *testing.T) {
+	app := newWorkspaceTestApp(t)
+
+	ws, _ := app.CreateWorkspace(Workspace{Name: "Temp"})
+	app.SwitchWorkspace(ws.ID)
+	if err := app.DeleteWorkspace(ws.ID); err != nil {
+		t.Fatalf("DeleteWorkspace failed: %v", err)
+	}
+
+	active, err := app.GetActiveWorkspace()
+	if err != nil {
+		t.Fatalf("GetActiveWorkspace failed: %v", err)
+	}
+	if active.ID != "" {
+		t.Errorf("expected no active workspace, got %q", active.ID)
+	}
+	if _, err := app.SwitchWorkspace(ws.ID); err == nil {
+		t.Error("expected error switching to a deleted workspace")
+	}
+}